@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newAddCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <what you did>",
+		Short: "Log a session described in plain English",
+		Long: `Log a finished session from a plain English description, such as:
+
+  work add 2h yesterday for acme fixing the deploy pipeline
+  work add 90m monday at 2pm for globex planning
+  work add "1h30m 3 days ago for initech"
+
+Give how long, optionally when (today, yesterday, a weekday, "3 days ago" or YYYY-MM-DD, and "at 9:30"), then
+"for <client>" and what was done. The client can be part of its name or slightly misspelled. Work today ends
+now unless a time is given, and work on other days starts at 9am. The interpretation is shown to confirm
+before it's logged.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			add, err := timesheetService.InterpretQuickAdd(ctx, strings.Join(args, " "), time.Now())
+			if err != nil {
+				return err
+			}
+			timesheetService.DisplayQuickAdd(add)
+
+			if !yes {
+				if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+					fmt.Print("Log this session? (Y/n): ")
+					reader := bufio.NewReader(os.Stdin)
+					response, err := reader.ReadString('\n')
+					response = strings.ToLower(strings.TrimSpace(response))
+					if err != nil || (response != "" && response != "y" && response != "yes") {
+						fmt.Println("Session not logged")
+						return nil
+					}
+				}
+			}
+
+			session, err := timesheetService.LogQuickAdd(ctx, add)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Logged %s session for %s, %s to %s\n",
+				timesheetService.FormatDuration(timesheetService.CalculateDuration(session)),
+				session.ClientName,
+				session.StartTime.Format("15:04"),
+				session.EndTime.Format("15:04"))
+
+			client, _ := timesheetService.GetClientByID(ctx, session.ClientID)
+			showHints(cmd, sessionFinished, hintSubject{client: client, session: session})
+			return nil
+		},
+		Annotations: mutating(),
+	}
+
+	cmd.Flags().BoolP("yes", "y", false, "Log without asking to confirm")
+
+	return cmd
+}
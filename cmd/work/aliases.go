@@ -0,0 +1,84 @@
+package main
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// addAliases adds a command for each alias, running the root command again with the alias's arguments in
+// front of any given to it. Aliases can't replace a command or run another alias, so a typo in the config
+// can't loop or change what a documented command does.
+func addAliases(rootCmd *cobra.Command, aliases map[string][]string, logger *slog.Logger) {
+	taken := map[string]bool{"help": true, "completion": true}
+	for _, cmd := range rootCmd.Commands() {
+		taken[cmd.Name()] = true
+		for _, alias := range cmd.Aliases {
+			taken[alias] = true
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(aliases)) {
+		expansion := aliases[name]
+		switch {
+		case strings.ContainsAny(name, " -"):
+			logger.Warn("ignoring alias, names are a single word", "alias", name)
+			continue
+		case taken[name]:
+			logger.Warn("ignoring alias with the name of a command", "alias", name)
+			continue
+		case aliases[expansion[0]] != nil && !taken[expansion[0]]:
+			logger.Warn("ignoring alias that runs another alias", "alias", name, "runs", expansion[0])
+			continue
+		}
+
+		rootCmd.AddCommand(&cobra.Command{
+			Use:   name,
+			Short: "Alias for: work " + strings.Join(expansion, " "),
+			// Flags are for the aliased command, which parses them itself
+			DisableFlagParsing: true,
+			Annotations:        unchecked(),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				root := cmd.Root()
+				root.SetArgs(append(slices.Clone(expansion), args...))
+				return root.ExecuteContext(cmd.Context())
+			},
+		})
+	}
+}
+
+// applyDefaultFlags sets the defaults of each command's flags from the configured default flags, so they
+// apply whenever the flag isn't given. Flags set this way still count as not given, so commands that treat a
+// given flag differently, such as an explicit period, behave as before.
+func applyDefaultFlags(rootCmd *cobra.Command, defaults map[string][]string, logger *slog.Logger) {
+	for _, path := range slices.Sorted(maps.Keys(defaults)) {
+		cmd, _, err := rootCmd.Find(strings.Fields(path))
+		if err != nil || cmd.CommandPath() != rootCmd.Name()+" "+path {
+			logger.Warn("ignoring default flags for unknown command", "command", path)
+			continue
+		}
+
+		// Only the command's own flags, so a default for one command doesn't change a flag shared with others
+		flags := cmd.LocalFlags()
+		if err := flags.Parse(defaults[path]); err != nil {
+			// Flags parsed before the bad one are put back, so none of the command's defaults change
+			flags.Visit(func(flag *pflag.Flag) {
+				_ = flag.Value.Set(flag.DefValue)
+				flag.Changed = false
+			})
+			logger.Warn("ignoring default flags", "command", path, "error", err)
+			continue
+		}
+		if len(flags.Args()) > 0 {
+			logger.Warn("ignoring arguments in default flags, only flags can be given", "command", path, "args", flags.Args())
+		}
+		flags.Visit(func(flag *pflag.Flag) {
+			flag.DefValue = flag.Value.String()
+			flag.Changed = false
+		})
+	}
+}
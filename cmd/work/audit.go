@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newAuditCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Check tracked time against other records of your work",
+		Long:  "Commands for finding time that may have gone untracked, or tracked time that needs explaining.",
+	}
+
+	cmd.AddCommand(newAuditCoverageCmd(timesheetService))
+
+	return cmd
+}
+
+func newAuditCoverageCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var period, date, client string
+
+	cmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "Compare tracked sessions with your git commits",
+		Long: `Compare the sessions tracked in a period with the commits you made in each client's repositories, found
+under the client's directory. Commits made outside any session are listed as possible untracked time, grouped
+into stretches of work, and finished sessions without a commit are listed as work that may need a description
+or a note with 'work note'. Commits within 30 minutes of a session count as made in it. Ends with the commands
+that would fix what was found. Clients without a directory aren't checked.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.ShowCoverageAudit(cmd.Context(), period, date, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&period, "period", "p", "week", "Period type: "+strings.Join(service.PeriodTypes, ", "))
+	cmd.Flags().StringVarP(&date, "date", "d", "", "Date in the period to audit (YYYY-MM-DD), defaults to today")
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Only audit this client")
+
+	return cmd
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newAutoCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auto",
+		Short: "Start and stop sessions automatically in client directories",
+		Long: `Automatic tracking hooks into your shell's prompt. Whenever a prompt is shown in a client's directory, such as
+after cd-ing into it or committing there, a session is started for that client. Sessions started this way stop at
+the last prompt in the directory once AUTO_IDLE_MINUTES pass without another. Sessions started by hand are left alone.`,
+	}
+
+	cmd.AddCommand(newAutoEnableCmd(timesheetService))
+	cmd.AddCommand(newAutoDisableCmd(timesheetService))
+	cmd.AddCommand(newAutoInitCmd())
+	cmd.AddCommand(newAutoHookCmd(timesheetService))
+
+	return cmd
+}
+
+func newAutoEnableCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var shell string
+
+	cmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Add the automatic tracking hook to your shell's startup file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shell, err := autoShell(shell)
+			if err != nil {
+				return err
+			}
+			executable, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to find the work executable: %w", err)
+			}
+			return timesheetService.EnableAutoTracking(cmd.Context(), shell, executable)
+		},
+	}
+
+	cmd.Flags().StringVar(&shell, "shell", "", "Shell to enable it for: bash, zsh or fish (default from $SHELL)")
+
+	return cmd
+}
+
+func newAutoDisableCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var shell string
+
+	cmd := &cobra.Command{
+		Use:   "disable",
+		Short: "Remove the automatic tracking hook from your shell's startup file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shell, err := autoShell(shell)
+			if err != nil {
+				return err
+			}
+			return timesheetService.DisableAutoTracking(shell)
+		},
+	}
+
+	cmd.Flags().StringVar(&shell, "shell", "", "Shell to disable it for: bash, zsh or fish (default from $SHELL)")
+
+	return cmd
+}
+
+func newAutoInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init <shell>",
+		Short: "Print the automatic tracking hook for a shell to evaluate",
+		Long:  "Print the hook for bash, zsh or fish, for adding to a startup file by hand, e.g. eval \"$(work auto init zsh)\"",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			executable, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to find the work executable: %w", err)
+			}
+			script, err := service.AutoHookScript(args[0], executable)
+			if err != nil {
+				return err
+			}
+			fmt.Print(script)
+			return nil
+		},
+	}
+}
+
+func newAutoHookCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:    "hook",
+		Short:  "Start or stop sessions for the current directory, run by the shell hook at each prompt",
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
+			return timesheetService.AutoTrack(cmd.Context(), dir, time.Now())
+		},
+		Annotations: mutating(),
+	}
+}
+
+// autoShell is the shell given, or the user's login shell
+func autoShell(shell string) (string, error) {
+	if shell != "" {
+		return shell, nil
+	}
+	if login := os.Getenv("SHELL"); login != "" {
+		return filepath.Base(login), nil
+	}
+	return "", fmt.Errorf("couldn't tell which shell you use, give it with --shell")
+}
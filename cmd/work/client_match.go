@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+// resolveClientFlag replaces a --client given loosely, such as "acm" for acme_corp, with the client it matches.
+// A close match is confirmed and several matches are chosen between when run interactively. Otherwise a single
+// match is used and several are an error, so scripts that want exact names only should set CLIENT_MATCHING=strict.
+func resolveClientFlag(cmd *cobra.Command, timesheetService *service.TimesheetService) error {
+	flag := cmd.Flags().Lookup("client")
+	if flag == nil || !flag.Changed || flag.Value.String() == "" {
+		return nil
+	}
+	name := flag.Value.String()
+
+	matches, exact, err := timesheetService.ClientMatches(cmd.Context(), name)
+	if err != nil {
+		return err
+	}
+	if exact || len(matches) == 0 {
+		// Unknown clients are left for the command to report
+		return nil
+	}
+
+	interactive := false
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		interactive = true
+	}
+
+	var match string
+	switch {
+	case len(matches) == 1 && strings.EqualFold(matches[0], name):
+		match = matches[0]
+	case len(matches) == 1 && interactive:
+		fmt.Printf("No client named '%s', did you mean %s? (Y/n): ", name, matches[0])
+		// Reaching the end of input, as with stdin from /dev/null, takes the default
+		response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if response = strings.ToLower(strings.TrimSpace(response)); response != "" && response != "y" && response != "yes" {
+			return fmt.Errorf("client '%s' does not exist", name)
+		}
+		match = matches[0]
+	case len(matches) == 1:
+		fmt.Fprintf(os.Stderr, "Using client %s for '%s'\n", matches[0], name)
+		match = matches[0]
+	case interactive:
+		fmt.Printf("'%s' could be any of:\n", name)
+		for i, candidate := range matches {
+			fmt.Printf("  %d. %s\n", i+1, candidate)
+		}
+		fmt.Printf("Which client did you mean? [1-%d]: ", len(matches))
+		response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		choice, err := strconv.Atoi(strings.TrimSpace(response))
+		if err != nil || choice < 1 || choice > len(matches) {
+			return fmt.Errorf("no client chosen for '%s'", name)
+		}
+		match = matches[choice-1]
+	default:
+		return fmt.Errorf("'%s' could be any of %s, use more of the name", name, strings.Join(matches, ", "))
+	}
+
+	return flag.Value.Set(match)
+}
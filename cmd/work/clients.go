@@ -1,26 +1,71 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 
+	"github.com/jesses-code-adventures/work/internal/config"
 	"github.com/jesses-code-adventures/work/internal/database"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
 	"github.com/jesses-code-adventures/work/internal/service"
 )
 
 func newClientsCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "clients",
-		Short: "Create, update and list clients",
+		Short: "Create, update, show and list clients",
 		Long:  "Commands for managing clients, including listing clients and their hourly rates.",
 	}
 
 	cmd.AddCommand(newClientsCreateCmd(timesheetService))
 	cmd.AddCommand(newClientsListCmd(timesheetService))
+	cmd.AddCommand(newClientsShowCmd(timesheetService))
 	cmd.AddCommand(newClientsUpdateCmd(timesheetService))
+	cmd.AddCommand(newClientsBulkUpdateRateCmd(timesheetService))
+	cmd.AddCommand(newClientsRateCardCmd(timesheetService))
+
+	return cmd
+}
+
+func newClientsRateCardCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ratecard",
+		Short: "Share your rates with clients",
+		Long:  "Commands for producing rate cards for proposals from the same rates and terms used for billing.",
+	}
+
+	cmd.AddCommand(newClientsRateCardExportCmd(timesheetService))
+
+	return cmd
+}
+
+func newClientsRateCardExportCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client, format, output string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write a rate card to a PDF or markdown file",
+		Long: `Write a rate card listing the client's hourly rate and retainer, the roles and rates in RATE_CARD_ROLES and the
+standard terms in RATE_CARD_TERMS. Create the client first for prospective clients, or leave out --client for a general rate card.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.ExportRateCard(ctx, client, format, output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Client the rate card is for (optional)")
+	cmd.Flags().StringVar(&format, "format", service.RateCardFormatPDF, "Document format: pdf or md")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write, defaulting to ratecard_<client>.<format>")
 
 	return cmd
 }
@@ -43,8 +88,9 @@ func newClientsCreateCmd(timesheetService *service.TimesheetService) *cobra.Comm
 	// Retainer flags
 	cmd.Flags().Float64Var(&retainerAmount, "retainer-amount", 0.0, "Retainer amount (e.g., 5000.00)")
 	cmd.Flags().Float64Var(&retainerHours, "retainer-hours", 0.0, "Hours covered by retainer (e.g., 40.0)")
-	cmd.Flags().StringVar(&retainerBasis, "retainer-basis", "", "Retainer billing basis: day, week, month, quarter, year")
+	cmd.Flags().StringVar(&retainerBasis, "retainer-basis", "", "Retainer billing basis: "+strings.Join(service.PeriodTypes, ", "))
 
+	cmd.Annotations = mutating()
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 
@@ -52,7 +98,12 @@ func newClientsCreateCmd(timesheetService *service.TimesheetService) *cobra.Comm
 
 		switch {
 		case clientName != "":
-			return createClient(ctx, timesheetService, clientName, rate, retainerAmount, retainerHours, retainerBasis, dir)
+			client, err := createClient(ctx, timesheetService, clientName, rate, retainerAmount, retainerHours, retainerBasis, dir)
+			if err != nil {
+				return err
+			}
+			showHints(cmd, clientCreated, hintSubject{client: client})
+			return nil
 		default:
 			return fmt.Errorf("must supply a client name (usage: work clients create <client-name>)")
 		}
@@ -61,7 +112,7 @@ func newClientsCreateCmd(timesheetService *service.TimesheetService) *cobra.Comm
 	return cmd
 }
 
-func createClient(ctx context.Context, timesheetService *service.TimesheetService, name string, rate float64, retainerAmount, retainerHours float64, retainerBasis, dir string) error {
+func createClient(ctx context.Context, timesheetService *service.TimesheetService, name string, rate float64, retainerAmount, retainerHours float64, retainerBasis, dir string) (*models.Client, error) {
 	// Convert fields to pointers (nil if zero/empty)
 	var retainerAmountPtr *decimal.Decimal
 	var retainerHoursPtr *float64
@@ -83,14 +134,14 @@ func createClient(ctx context.Context, timesheetService *service.TimesheetServic
 
 	client, err := timesheetService.CreateClient(ctx, name, decimal.NewFromFloat(rate), retainerAmountPtr, retainerHoursPtr, retainerBasisPtr, dirPtr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Printf("Created client: %s (ID: %s, Rate: $%s/hr)\n", client.Name, client.ID, client.HourlyRate.StringFixed(2))
+	fmt.Printf("Created client: %s (ID: %s, Rate: %s)\n", client.Name, client.ID, timesheetService.FormatClientRate(client))
 
 	// Show retainer info if set
 	if client.RetainerAmount != nil && client.RetainerAmount.GreaterThan(decimal.Zero) {
-		fmt.Printf("Retainer: $%s for %.1f hours per %s\n", client.RetainerAmount.StringFixed(2), *client.RetainerHours, *client.RetainerBasis)
+		fmt.Printf("Retainer: %s for %.1f hours per %s\n", timesheetService.FormatClientMoney(client, *client.RetainerAmount), *client.RetainerHours, *client.RetainerBasis)
 	}
 
 	// Show directory if set
@@ -98,7 +149,7 @@ func createClient(ctx context.Context, timesheetService *service.TimesheetServic
 		fmt.Printf("Directory: %s\n", *client.Dir)
 	}
 
-	return nil
+	return client, nil
 }
 
 func newClientsListCmd(timesheetService *service.TimesheetService) *cobra.Command {
@@ -121,7 +172,7 @@ func newClientsListCmd(timesheetService *service.TimesheetService) *cobra.Comman
 
 			fmt.Println("Clients:")
 			for _, client := range clients {
-				rateStr := fmt.Sprintf("$%s/hr", client.HourlyRate.StringFixed(2))
+				rateStr := timesheetService.FormatClientRate(client)
 				if client.HourlyRate.Equal(decimal.Zero) {
 					rateStr = "No rate set"
 				}
@@ -141,12 +192,36 @@ func newClientsListCmd(timesheetService *service.TimesheetService) *cobra.Comman
 	return cmd
 }
 
+func newClientsShowCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <client-name>",
+		Short: "Show a client's details and activity",
+		Long:  "Show a client's contact and billing details, hours worked this month and overall, average weekly hours, their last session, retainer use, outstanding invoice balance and progress through fixed-price milestones.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.ShowClient(cmd.Context(), args[0])
+		},
+	}
+}
+
 func newClientsUpdateCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	var hourlyRate float64
 	var companyName, contactName, email, phone string
 	var addressLine1, addressLine2, city, state, postalCode, country, abn, dir string
 	var retainerAmount, retainerHours float64
-	var retainerBasis string
+	var retainerBasis, retainerStart string
+	var currency, locale string
+	var source string
+	var workLog string
+	var earlyDiscount float64
+	var earlyDiscountDays int64
+	var invoiceNotes, paymentTerms string
+	var poNumber, projectCode string
+	var invoiceRounding float64
+	var taxTreatment string
+	var logo, brandColour string
+	var analysisMaxCommits, analysisIgnore, analysisDetail string
+	var analysisPrompt string
 
 	cmd := &cobra.Command{
 		Use:   "update",
@@ -174,8 +249,42 @@ func newClientsUpdateCmd(timesheetService *service.TimesheetService) *cobra.Comm
 	// Retainer flags
 	cmd.Flags().Float64Var(&retainerAmount, "retainer-amount", 0.0, "Retainer amount (e.g., 5000.00)")
 	cmd.Flags().Float64Var(&retainerHours, "retainer-hours", 0.0, "Hours covered by retainer (e.g., 40.0)")
-	cmd.Flags().StringVar(&retainerBasis, "retainer-basis", "", "Retainer billing basis: day, week, month, quarter, year")
-
+	cmd.Flags().StringVar(&retainerBasis, "retainer-basis", "", "Retainer billing basis: "+strings.Join(service.PeriodTypes, ", "))
+	cmd.Flags().StringVar(&retainerStart, "retainer-start", "", "Date the retainer started (YYYY-MM-DD), to prorate it for the period it started in")
+
+	// Early payment discount flags
+	cmd.Flags().Float64Var(&earlyDiscount, "early-discount", 0.0, "Percentage off invoices paid early (e.g., 2 for 2%), 0 to remove the discount")
+	cmd.Flags().Int64Var(&earlyDiscountDays, "early-discount-days", 0, "Days after an invoice is issued that the early payment discount applies for")
+
+	// Invoice text flags
+	cmd.Flags().StringVar(&invoiceNotes, "invoice-notes", "", "Notes printed on the client's invoices, empty to remove them")
+	cmd.Flags().StringVar(&poNumber, "po-number", "", "Purchase order number printed on the client's invoices unless one is given when generating, empty to remove it")
+	cmd.Flags().StringVar(&projectCode, "project-code", "", "Project code printed on the client's invoices unless one is given when generating, empty to remove it")
+	cmd.Flags().StringVar(&paymentTerms, "payment-terms", "", "Payment terms printed on the client's invoices (e.g., \"Payment due within 14 days\"), empty to remove them")
+	cmd.Flags().Float64Var(&invoiceRounding, "invoice-rounding", 0.0, "Round invoice totals to the nearest multiple of this amount (e.g., 1 or 5), shown as a rounding adjustment, 0 to stop rounding invoices issued from now on")
+	cmd.Flags().StringVar(&taxTreatment, "tax-treatment", "", "Whether GST is charged on the client's invoices: "+strings.Join(service.TaxTreatments, ", ")+" (e.g., export for overseas clients)")
+
+	// Branding flags, for agencies that want their branding on the invoices they're sent
+	cmd.Flags().StringVar(&logo, "logo", "", "PNG, JPEG or GIF printed at the top of the client's invoices instead of BILLING_LOGO, empty to remove it")
+	cmd.Flags().StringVar(&brandColour, "brand-colour", "", "Colour of headings on the client's invoices as #rrggbb instead of BILLING_BRAND_COLOUR, empty to remove it")
+
+	// Git analysis flags, to cut down the history the AI reads for large repositories
+	cmd.Flags().StringVar(&analysisMaxCommits, "analysis-max-commits", "", "Most recent commits per repository the AI describes sessions from (0 for all) instead of GIT_ANALYSIS_MAX_COMMITS, empty to go back to it")
+	cmd.Flags().StringVar(&analysisIgnore, "analysis-ignore", "", "Comma separated paths left out of analysis, e.g. vendor/,*.lock, instead of GIT_ANALYSIS_IGNORE, empty to go back to it")
+	cmd.Flags().StringVar(&analysisDetail, "analysis-detail", "", "Whether the AI is given commit diffs or messages only instead of GIT_ANALYSIS_DETAIL: "+config.GitAnalysisDiffs+" or "+config.GitAnalysisMessages+", empty to go back to it")
+	cmd.Flags().StringVar(&analysisPrompt, "analysis-prompt", "", "Prompt the client's session descriptions are generated with instead of GIT_ANALYSIS_PROMPT, e.g. for a more formal or technical style, empty to go back to it")
+
+	// Money formatting overrides
+	cmd.Flags().StringVar(&currency, "currency", "", "Currency code for this client's amounts (e.g., EUR), overriding BILLING_CURRENCY, empty to go back to it")
+	cmd.Flags().StringVar(&locale, "locale", "", "Locale for this client's number formatting (e.g., de-DE), overriding BILLING_LOCALE, empty to go back to it")
+
+	// Marketing flags
+	cmd.Flags().StringVar(&source, "source", "", "How the client was acquired: "+strings.Join(service.ClientSources, ", ")+", empty to remove it")
+
+	// Work log flags
+	cmd.Flags().StringVar(&workLog, "work-log", "", "Where to publish each invoice period's work summary: notion:<database-id> or confluence:<page-id>, empty to stop publishing")
+
+	cmd.Annotations = mutating()
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		client := args[0]
@@ -183,6 +292,16 @@ func newClientsUpdateCmd(timesheetService *service.TimesheetService) *cobra.Comm
 			return fmt.Errorf("client name is required")
 		}
 
+		if err := money.Validate(currency, locale); err != nil {
+			return err
+		}
+		if err := service.ValidateClientSource(source); err != nil {
+			return err
+		}
+		if err := service.ValidateWorkLog(workLog); err != nil {
+			return err
+		}
+
 		var hourlyRateDecimal *decimal.Decimal
 		var retainerAmountDecimal *decimal.Decimal
 		var retainerHoursPtr *float64
@@ -194,6 +313,13 @@ func newClientsUpdateCmd(timesheetService *service.TimesheetService) *cobra.Comm
 			}
 			return &s
 		}
+		// Details kept unless their flag is given, so they can be removed with an empty value
+		changedPtr := func(flag, s string) *string {
+			if !cmd.Flags().Changed(flag) {
+				return nil
+			}
+			return &s
+		}
 
 		if hourlyRate > 0 {
 			rate := decimal.NewFromFloat(hourlyRate)
@@ -206,24 +332,84 @@ func newClientsUpdateCmd(timesheetService *service.TimesheetService) *cobra.Comm
 		if retainerHours > 0 {
 			retainerHoursPtr = &retainerHours
 		}
+		// The early payment discount is kept unless either flag is given, and removed when both are 0
+		var earlyDiscountPtr *float64
+		var earlyDiscountDaysPtr *int64
+		if cmd.Flags().Changed("early-discount") || cmd.Flags().Changed("early-discount-days") {
+			if (earlyDiscount > 0) != (earlyDiscountDays > 0) {
+				return fmt.Errorf("--early-discount and --early-discount-days must be given together")
+			}
+			if earlyDiscount >= 100 {
+				return fmt.Errorf("early payment discount must be less than 100%%")
+			}
+			earlyDiscountPtr = &earlyDiscount
+			earlyDiscountDaysPtr = &earlyDiscountDays
+		}
+		if invoiceRounding < 0 {
+			return fmt.Errorf("invoice rounding must not be negative")
+		}
+		var invoiceRoundingDecimal *decimal.Decimal
+		if cmd.Flags().Changed("invoice-rounding") {
+			rounding := decimal.NewFromFloat(invoiceRounding)
+			invoiceRoundingDecimal = &rounding
+		}
+		// Git analysis settings go back to the GIT_ANALYSIS_ ones when they're given empty
+		var analysisMaxCommitsPtr *int64
+		if cmd.Flags().Changed("analysis-max-commits") {
+			maxCommits := int64(-1)
+			if analysisMaxCommits != "" {
+				var err error
+				if maxCommits, err = strconv.ParseInt(analysisMaxCommits, 10, 64); err != nil || maxCommits < 0 {
+					return fmt.Errorf("--analysis-max-commits must be a whole number (0 for no limit), got %q", analysisMaxCommits)
+				}
+			}
+			analysisMaxCommitsPtr = &maxCommits
+		}
+		var retainerStartPtr *time.Time
+		if retainerStart != "" {
+			start, err := time.ParseInLocation("2006-01-02", retainerStart, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid retainer start date, expected YYYY-MM-DD: %w", err)
+			}
+			retainerStartPtr = &start
+		}
 
 		updatedClient, err := timesheetService.UpdateClient(ctx, client, &database.ClientUpdateDetails{
-			HourlyRate:     hourlyRateDecimal,
-			CompanyName:    stringPtr(companyName),
-			ContactName:    stringPtr(contactName),
-			Email:          stringPtr(email),
-			Phone:          stringPtr(phone),
-			AddressLine1:   stringPtr(addressLine1),
-			AddressLine2:   stringPtr(addressLine2),
-			City:           stringPtr(city),
-			State:          stringPtr(state),
-			PostalCode:     stringPtr(postalCode),
-			Country:        stringPtr(country),
-			Abn:            stringPtr(abn),
-			Dir:            stringPtr(dir),
-			RetainerAmount: retainerAmountDecimal,
-			RetainerHours:  retainerHoursPtr,
-			RetainerBasis:  stringPtr(retainerBasis),
+			HourlyRate:           hourlyRateDecimal,
+			CompanyName:          stringPtr(companyName),
+			ContactName:          stringPtr(contactName),
+			Email:                stringPtr(email),
+			Phone:                stringPtr(phone),
+			AddressLine1:         stringPtr(addressLine1),
+			AddressLine2:         stringPtr(addressLine2),
+			City:                 stringPtr(city),
+			State:                stringPtr(state),
+			PostalCode:           stringPtr(postalCode),
+			Country:              stringPtr(country),
+			Abn:                  stringPtr(abn),
+			Dir:                  stringPtr(dir),
+			RetainerAmount:       retainerAmountDecimal,
+			RetainerHours:        retainerHoursPtr,
+			RetainerBasis:        stringPtr(retainerBasis),
+			Currency:             changedPtr("currency", strings.ToUpper(currency)),
+			Locale:               changedPtr("locale", locale),
+			Source:               changedPtr("source", strings.ToLower(source)),
+			WorkLog:              changedPtr("work-log", workLog),
+			RetainerStart:        retainerStartPtr,
+			EarlyDiscountPercent: earlyDiscountPtr,
+			EarlyDiscountDays:    earlyDiscountDaysPtr,
+			InvoiceNotes:         changedPtr("invoice-notes", invoiceNotes),
+			PaymentTerms:         changedPtr("payment-terms", paymentTerms),
+			PoNumber:             changedPtr("po-number", poNumber),
+			ProjectCode:          changedPtr("project-code", projectCode),
+			InvoiceRounding:      invoiceRoundingDecimal,
+			TaxTreatment:         stringPtr(strings.ToLower(taxTreatment)),
+			LogoPath:             changedPtr("logo", logo),
+			BrandColour:          changedPtr("brand-colour", brandColour),
+			AnalysisMaxCommits:   analysisMaxCommitsPtr,
+			AnalysisIgnore:       changedPtr("analysis-ignore", analysisIgnore),
+			AnalysisDetail:       changedPtr("analysis-detail", analysisDetail),
+			AnalysisPrompt:       changedPtr("analysis-prompt", analysisPrompt),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to update client billing: %w", err)
@@ -236,3 +422,79 @@ func newClientsUpdateCmd(timesheetService *service.TimesheetService) *cobra.Comm
 
 	return cmd
 }
+
+func newClientsBulkUpdateRateCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var percent float64
+	var effective string
+	var exclude []string
+	var dryRun, yes bool
+
+	cmd := &cobra.Command{
+		Use:   "bulk-update-rate",
+		Short: "Change every client's hourly rate by a percentage",
+		Long: `Raise every client's hourly rate by --percent, or lower it with a negative one, from the --effective date.
+Each change is recorded in the client's rate history, shown by 'work clients show'. Sessions starting from the
+effective date are billed at the new rate, a date ahead schedules the change, and sessions already tracked keep
+the rates they were created with. Clients without an hourly rate are left as they are. The before and after
+rates are shown to confirm before anything changes.`,
+		Example: `  work clients bulk-update-rate --percent 10 --effective 2025-07-01
+  work clients bulk-update-rate --percent 5 --exclude acme --dry-run`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().Float64Var(&percent, "percent", 0, "Percentage to change rates by, e.g. 10 or -5 (required)")
+	cmd.Flags().StringVar(&effective, "effective", "", "Day the new rates take effect (YYYY-MM-DD), defaults to today")
+	cmd.Flags().StringSliceVar(&exclude, "exclude", nil, "Clients to leave out, comma separated")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the new rates without changing anything")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Change the rates without asking to confirm")
+	cmd.MarkFlagRequired("percent")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		from := time.Now()
+		if effective != "" {
+			var err error
+			if from, err = time.ParseInLocation("2006-01-02", effective, time.Local); err != nil {
+				return fmt.Errorf("invalid effective date format, expected YYYY-MM-DD: %w", err)
+			}
+		}
+
+		changes, err := timesheetService.ProposeRateChange(ctx, percent, from, exclude)
+		if err != nil {
+			return err
+		}
+		timesheetService.DisplayRateChanges(changes)
+		if len(changes) == 0 || dryRun {
+			return nil
+		}
+
+		if !yes {
+			fmt.Printf("\nChange %d client rates? (y/N): ", len(changes))
+			reader := bufio.NewReader(os.Stdin)
+			response, err := reader.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			response = strings.ToLower(strings.TrimSpace(response))
+			if response != "y" && response != "yes" {
+				fmt.Println("Operation cancelled.")
+				return nil
+			}
+		}
+
+		applied, err := timesheetService.ApplyRateChanges(ctx, changes)
+		if err != nil {
+			return err
+		}
+		if applied[0].EffectiveDate.After(time.Now()) {
+			fmt.Printf("\nScheduled new rates for %d clients from %s\n", len(applied), applied[0].EffectiveDate.Format("2006-01-02"))
+		} else {
+			fmt.Printf("\nUpdated rates for %d clients from %s, sessions already tracked keep their rates\n", len(applied), applied[0].EffectiveDate.Format("2006-01-02"))
+		}
+		return nil
+	}
+
+	return cmd
+}
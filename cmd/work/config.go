@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newConfigCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "config",
+		Short: "Show the active configuration",
+		Long:  "Show the active database, where it was configured (--db, WORK_DB, DATABASE_URL or the build) and billing settings.",
+		Args:  cobra.NoArgs,
+		// Shows where the database is configured, so it's worth running when the database can't be reached
+		Annotations: unchecked(),
+		Run: func(cmd *cobra.Command, args []string) {
+			timesheetService.Config().Dump()
+		},
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newContactsCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "contacts",
+		Short: "Log calls, emails and meetings with clients",
+		Long: `A log of contact with each client, for remembering what was discussed when invoicing or chasing payment.
+The most recent contacts are shown by 'work clients show', and a contact can be billed as a session.`,
+	}
+
+	cmd.AddCommand(newContactsLogCmd(timesheetService))
+	cmd.AddCommand(newContactsListCmd(timesheetService))
+	cmd.AddCommand(newContactsBillCmd(timesheetService))
+	cmd.AddCommand(newContactsDeleteCmd(timesheetService))
+
+	return cmd
+}
+
+func newContactsLogCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client, contactType, note, at string
+	var duration time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Log a call, email or meeting with a client",
+		Example: `  work contacts log --client acme --type call --note "Discussed scope"
+  work contacts log -c acme --type meeting -n "Quarterly review" --at "2025-07-01 14:00" --duration 45m`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Client name")
+	cmd.Flags().StringVar(&contactType, "type", "", "Kind of contact: "+strings.Join(service.ContactTypes, ", "))
+	cmd.Flags().StringVarP(&note, "note", "n", "", "What was discussed")
+	cmd.Flags().StringVar(&at, "at", "", "When it happened, e.g. 'YYYY-MM-DD HH:MM:SS', ISO8601 or 'HH:MM' (defaults to now)")
+	cmd.Flags().DurationVar(&duration, "duration", 0, "How long it took, e.g. 30m, used when billing it")
+	cmd.MarkFlagRequired("client")
+	cmd.MarkFlagRequired("type")
+	cmd.MarkFlagRequired("note")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := service.ValidateContactType(contactType); err != nil {
+			return err
+		}
+		contactedAt := time.Now()
+		if at != "" {
+			var err error
+			if contactedAt, err = timesheetService.ParseTimeString(at); err != nil {
+				return fmt.Errorf("invalid --at time: %w", err)
+			}
+		}
+
+		contact, err := timesheetService.LogContact(cmd.Context(), client, contactType, note, contactedAt, duration)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Logged %s with %s: %s\n", contact.Type, contact.ClientName, contact.ID)
+		return nil
+	}
+
+	return cmd
+}
+
+func newContactsListCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client string
+	var limit int32
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List contacts with clients, most recent first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.DisplayContacts(cmd.Context(), client, limit)
+		},
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Only show contacts with this client")
+	cmd.Flags().Int32VarP(&limit, "limit", "l", 20, "Maximum number of contacts to show")
+
+	return cmd
+}
+
+func newContactsBillCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var duration time.Duration
+	var description string
+	var includesGst bool
+
+	cmd := &cobra.Command{
+		Use:   "bill <contact-id>",
+		Short: "Bill a contact as a session",
+		Long: `Create a finished session for a contact's client, starting when the contact was made and lasting its
+logged duration or --duration. The session is described by the contact's note unless --description is given.
+A contact can only be billed once.`,
+		Example: `  work contacts bill 1f6c2b1e-... --duration 30m`,
+		Args:    cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().DurationVar(&duration, "duration", 0, "How long to bill, defaults to the contact's duration")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "Session description, defaults to the contact's note")
+	cmd.Flags().BoolVar(&includesGst, "includes-gst", false, "Session amount includes GST (default: false)")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var desc *string
+		if description != "" {
+			desc = &description
+		}
+		session, err := timesheetService.BillContact(cmd.Context(), args[0], duration, desc, includesGst)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created session for %s from %s to %s: %s\n", session.ClientName, session.StartTime.Format("2006-01-02 15:04"),
+			session.EndTime.Format("15:04"), session.ID)
+		return nil
+	}
+
+	return cmd
+}
+
+func newContactsDeleteCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <contact-id>",
+		Short: "Delete a logged contact",
+		Long:  "Delete a logged contact. A session it was billed as is kept.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contact, err := timesheetService.DeleteContact(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Deleted %s with %s on %s\n", contact.Type, contact.ClientName, contact.ContactedAt.Format("2006-01-02"))
+			return nil
+		},
+		Annotations: mutating(),
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+// uncheckedAnnotation marks commands that run without first checking a remote database can be reached
+const uncheckedAnnotation = "unchecked"
+
+func unchecked() map[string]string {
+	return map[string]string{uncheckedAnnotation: "true"}
+}
+
+func newDBCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the database",
+	}
+
+	cmd.AddCommand(newDBSyncCmd(timesheetService))
+	cmd.AddCommand(newDBEncryptCmd(timesheetService))
+	cmd.AddCommand(newDBDecryptCmd(timesheetService))
+	cmd.AddCommand(newDBPingCmd(timesheetService))
+	cmd.AddCommand(newDBSnapshotCmd(timesheetService))
+	return cmd
+}
+
+func newDBSyncCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var status bool
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Sync the embedded replica with Turso",
+		Long:  "Sync the embedded replica with its Turso primary now. Replicas also sync in the background every SYNC_INTERVAL, backing off while offline, and once more when each command finishes.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if status {
+				return timesheetService.ShowSyncStatus(cmd.Context())
+			}
+			return timesheetService.SyncDatabase(cmd.Context())
+		},
+		Annotations: mutating(),
+	}
+
+	cmd.Flags().BoolVar(&status, "status", false, "Show the last sync time, pending frames and conflicts instead of syncing")
+	return cmd
+}
+
+func newDBEncryptCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt client contact details saved as plaintext",
+		Long:  "Encrypt the contact name, email, phone, address and ABN of clients saved before ENCRYPTION_KEY was set. Details are encrypted as they're saved once the key is set.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.EncryptClientDetails(cmd.Context())
+		},
+		Annotations: mutating(),
+	}
+}
+
+func newDBDecryptCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "decrypt",
+		Short: "Store encrypted client contact details as plaintext again",
+		Long:  "Decrypt client contact details with ENCRYPTION_KEY and store them as plaintext, before unsetting the key or changing to a new one.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.DecryptClientDetails(cmd.Context())
+		},
+		Annotations: mutating(),
+	}
+}
+
+func newDBPingCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var count int
+
+	cmd := &cobra.Command{
+		Use:   "ping",
+		Short: "Check the database can be reached and how quickly",
+		Long: `Connect to the database and show how long it took, with the driver, SQLite version and snapshot. When a
+remote database can't be reached within DB_CONNECT_TIMEOUT, the likely cause is explained, such as an unknown
+host, a refused auth token or no connection. The auth token in the URL is hidden.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.PingDatabase(cmd.Context(), count)
+		},
+		Annotations: unchecked(),
+	}
+
+	cmd.Flags().IntVarP(&count, "count", "n", 3, "Number of times to ping")
+	return cmd
+}
+
+func newDBSnapshotCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save a local copy to read while a remote database is unreachable",
+		Long: `Copy every table to the SQLite file at DB_SNAPSHOT. While a remote database can't be reached, commands
+that don't change anything, such as status and the list commands, read from the snapshot instead, with a
+warning saying how old it is. Commands that change data still fail until the database is back.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.SaveDatabaseSnapshot(cmd.Context())
+		},
+		Annotations: unchecked(),
+	}
+}
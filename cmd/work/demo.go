@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newDemoCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "demo",
+		Short: "Demo data for trying out commands",
+	}
+
+	cmd.AddCommand(newDemoSeedCmd(timesheetService))
+	return cmd
+}
+
+func newDemoSeedCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var months int
+	var seed int64
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Fill an empty database with made up clients, sessions, invoices and payments",
+		Long:  "Fill an empty database with made up clients, sessions, expenses, invoices and payments over the last few months, to explore every command without entering data. Use it with a separate database, e.g. 'work --db ./demo.db demo seed'.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.SeedDemo(cmd.Context(), months, seed)
+		},
+		Annotations: mutating(),
+	}
+
+	cmd.Flags().IntVar(&months, "months", 3, "Number of months of history before the current month")
+	cmd.Flags().Int64Var(&seed, "seed", 1, "Random seed, the same seed always gives the same data")
+	return cmd
+}
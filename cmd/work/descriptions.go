@@ -1,8 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
 	"github.com/spf13/cobra"
 
+	"github.com/jesses-code-adventures/work/internal/lock"
+	"github.com/jesses-code-adventures/work/internal/notify"
 	"github.com/jesses-code-adventures/work/internal/service"
 )
 
@@ -14,6 +23,7 @@ func newDescriptionsCmd(timesheetService *service.TimesheetService) *cobra.Comma
 	}
 
 	cmd.AddCommand(newDescriptionsGenerateCmd(timesheetService))
+	cmd.AddCommand(newDescriptionsWatchCmd(timesheetService))
 
 	return cmd
 }
@@ -23,23 +33,135 @@ func newDescriptionsGenerateCmd(timesheetService *service.TimesheetService) *cob
 	var period string
 	var date string
 	var session string
+	var mode string
 
 	cmd := &cobra.Command{
 		Use:   "generate",
 		Short: "Generate missing session descriptions using git analysis",
-		Long:  "Gets all sessions missing descriptions and runs summarize analysis using the session start/end times to populate descriptions and full work summaries.",
+		Long: `Gets all sessions missing descriptions and runs summarize analysis using the session start/end times to populate descriptions and full work summaries.
+With --mode commits, descriptions list the commit subjects made in the client's repositories during each session, deduplicated and grouped by repository, without running any AI.`,
 	}
 
 	cmd.Flags().StringVarP(&client, "client", "c", "", "Process only the specified client (optional)")
-	cmd.Flags().StringVarP(&period, "period", "p", "week", "Period type: day, week, fortnight, month")
+	cmd.Flags().StringVarP(&period, "period", "p", "week", "Period type: day, week, fortnight, month, quarter, year")
 	cmd.Flags().StringVarP(&date, "date", "d", "", "Date in the period (YYYY-MM-DD)")
-	cmd.Flags().StringVarP(&session, "session", "s", "", "The ID of the session to analyze")
+	cmd.Flags().StringVarP(&session, "session", "s", "", "The ID of the session to analyze, or the start of it")
+	cmd.Flags().StringVarP(&mode, "mode", "m", service.DescriptionModeAI, "How to describe sessions: ai (summarise changes with opencode) or commits (list commit subjects)")
 	update := cmd.Flags().BoolP("update", "u", false, "Update the session descriptions in the database")
 
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
-		ctx := cmd.Context()
-		return timesheetService.GenerateDescriptions(ctx, client, session, *update)
+		// Ctrl+C stops any opencode runs in progress, keeping the descriptions already saved
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		if session != "" {
+			sessionID, err := timesheetService.ResolveSessionID(ctx, session)
+			if err != nil {
+				return err
+			}
+			session = sessionID
+		}
+		return timesheetService.GenerateDescriptions(ctx, client, session, mode, *update)
+	}
+
+	return cmd
+}
+
+func newDescriptionsWatchCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client, mode string
+	var olderThan, interval time.Duration
+	var once, notifyDone bool
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Describe sessions missing descriptions in the background once they're a few hours old",
+		Long: `Keep generating and saving descriptions for finished sessions missing them, checking every --interval. Only
+sessions that ended more than --older-than ago are described, leaving recent ones for you to describe while
+they're fresh. A desktop notification summarises each pass that described anything.
+
+With --once a single pass is made, for running from cron or a launchd or systemd timer, e.g. hourly:
+
+  0 * * * * work descriptions watch --once
+
+Only one backfill runs at a time against a database, so a pass that starts while another is still going exits
+without doing anything.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Process only the specified client (optional)")
+	cmd.Flags().StringVarP(&mode, "mode", "m", service.DescriptionModeAI, "How to describe sessions: ai (summarise changes with opencode) or commits (list commit subjects)")
+	cmd.Flags().DurationVar(&olderThan, "older-than", 2*time.Hour, "How long ago a session must have ended before it's described")
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Minute, "How often to check for sessions to describe")
+	cmd.Flags().BoolVar(&once, "once", false, "Make a single pass and exit, for running from cron")
+	cmd.Flags().BoolVar(&notifyDone, "notify", true, "Show a desktop notification summarising each pass, use --notify=false to turn it off")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := service.ValidateDescriptionMode(mode); err != nil {
+			return err
+		}
+		if interval <= 0 {
+			return fmt.Errorf("--interval must be greater than 0")
+		}
+
+		held, err := lock.Acquire(descriptionsLockPath(timesheetService.Config()), 0)
+		if err != nil {
+			if once && errors.Is(err, lock.ErrLocked) {
+				fmt.Fprintln(os.Stderr, "Another description backfill is still running, skipping this pass")
+				return nil
+			}
+			return err
+		}
+		defer held.Release()
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		if !once {
+			fmt.Printf("Describing sessions that ended more than %s ago every %s, press Ctrl+C to stop\n", olderThan, interval)
+		}
+
+		failed := make(map[string]bool)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			backfill, err := timesheetService.BackfillDescriptions(ctx, client, mode, olderThan, failed)
+			if err != nil {
+				return err
+			}
+			notifyDone = reportDescriptionBackfill(ctx, backfill, once, notifyDone)
+
+			if once {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
 	}
 
 	return cmd
 }
+
+// reportDescriptionBackfill prints what a backfill pass did, notifying the desktop when it described anything.
+// It returns whether to keep notifying, which stops when notifications aren't supported.
+func reportDescriptionBackfill(ctx context.Context, backfill *service.DescriptionBackfill, verbose, notifyDone bool) bool {
+	if backfill.Described == 0 && backfill.Failed == 0 {
+		if verbose {
+			fmt.Printf("No sessions to describe, %d waiting until they're old enough\n", backfill.Waiting)
+		}
+		return notifyDone
+	}
+
+	summary := fmt.Sprintf("Described %d session(s)", backfill.Described)
+	if backfill.Failed > 0 {
+		summary += fmt.Sprintf(", %d failed", backfill.Failed)
+	}
+	fmt.Printf("%s %s\n", time.Now().Format("2006-01-02 15:04"), summary)
+	if notifyDone {
+		if err := notify.Desktop(ctx, "work descriptions", summary); err != nil {
+			fmt.Fprintf(os.Stderr, "Couldn't show notification: %v\n", err)
+			return !errors.Is(err, notify.ErrUnsupported)
+		}
+	}
+	return notifyDone
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newDoctorCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check environment and database health",
+		Long: `Check that the database is reachable and migrated, that external tools (git, opencode) are available,
+that client directories exist, and that sessions and invoices are consistent. Prints a suggested fix for each problem.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.RunDoctor(ctx, fix)
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Unlink sessions and expenses from invoices that no longer exist")
+
+	return cmd
+}
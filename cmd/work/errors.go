@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// unknownCommandError is returned when a command is given a subcommand it doesn't have
+type unknownCommandError struct {
+	parent      *cobra.Command
+	name        string
+	suggestions []string
+}
+
+func (e *unknownCommandError) Error() string {
+	msg := fmt.Sprintf("unknown command %q for %q", e.name, e.parent.CommandPath())
+	if len(e.suggestions) > 0 {
+		msg += "\n\nDid you mean this?\n\t" + strings.Join(e.suggestions, "\n\t")
+	}
+	return msg + fmt.Sprintf("\n\nRun '%s --help' for usage.", e.parent.CommandPath())
+}
+
+// suggestSubcommands makes every command group reject unknown subcommands with "did you mean"
+// suggestions. Cobra only suggests for the root command and silently prints help for nested groups.
+func suggestSubcommands(cmd *cobra.Command) {
+	for _, sub := range cmd.Commands() {
+		suggestSubcommands(sub)
+	}
+	if !cmd.HasSubCommands() || cmd.Runnable() {
+		return
+	}
+
+	// Flags meant for the mistyped subcommand would fail to parse on the group, so they're left as args
+	cmd.DisableFlagParsing = true
+	cmd.Args = cobra.ArbitraryArgs
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var name string
+		for _, arg := range args {
+			if !strings.HasPrefix(arg, "-") {
+				name = arg
+				break
+			}
+		}
+		if name == "" || name == "help" {
+			return cmd.Help()
+		}
+
+		// Cobra only applies its default distance when suggesting for the root command itself
+		if cmd.SuggestionsMinimumDistance <= 0 {
+			cmd.SuggestionsMinimumDistance = 2
+		}
+		cmd.SilenceUsage = true
+		return &unknownCommandError{parent: cmd, name: name, suggestions: cmd.SuggestionsFor(name)}
+	}
+}
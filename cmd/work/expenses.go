@@ -1,7 +1,13 @@
 package main
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -14,36 +20,48 @@ import (
 func newExpensesCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "expenses",
-		Short: "Create, update and list expenses",
+		Short: "Create, update, list and delete expenses",
 		Long:  "Commands for managing expenses, including listing expenses and their amounts.",
 	}
 
 	cmd.AddCommand(newExpensesCreateCmd(timesheetService))
+	cmd.AddCommand(newExpensesMileageCmd(timesheetService))
 	cmd.AddCommand(newExpensesListCmd(timesheetService))
 	cmd.AddCommand(newExpensesUpdateCmd(timesheetService))
+	cmd.AddCommand(newExpensesDeleteCmd(timesheetService))
+	cmd.AddCommand(newExpensesImportReceiptsCmd(timesheetService))
+	cmd.AddCommand(newExpensesReviewCmd(timesheetService))
+	cmd.AddCommand(newExpensesImportCmd(timesheetService))
+	cmd.AddCommand(newExpensesRulesCmd(timesheetService))
 
 	return cmd
 }
 
 func newExpensesCreateCmd(timesheetService *service.TimesheetService) *cobra.Command {
-	var amount float64
-	var expenseDate, reference, client, description string
+	var amount, markup, gst float64
+	var expenseDate, reference, client, description, category string
+	var billable bool
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new expense",
-		Long:  "Create an expense with a given amount, date, and optional reference, description and client",
+		Long:  "Create an expense with a given amount, date, and optional reference, description, category and client",
 		Args:  cobra.NoArgs,
 	}
 
 	cmd.Flags().Float64VarP(&amount, "amount", "a", 0.0, "Amount of the expense (required)")
-	cmd.Flags().StringVarP(&expenseDate, "date", "d", "", "Date of the expense (YYYY-MM-DD, defaults to today)")
+	cmd.Flags().StringVarP(&expenseDate, "date", "d", "", "Date of the expense (YYYY-MM-DD or a timestamp, defaults to today)")
 	cmd.Flags().StringVarP(&reference, "reference", "r", "", "Reference for the expense")
 	cmd.Flags().StringVarP(&description, "description", "", "", "Description of the expense")
 	cmd.Flags().StringVarP(&client, "client", "c", "", "Client name to associate with the expense")
+	cmd.Flags().StringVar(&category, "category", "", "Category of the expense ("+strings.Join(service.ExpenseCategories, ", ")+")")
+	cmd.Flags().Float64Var(&markup, "markup", 0.0, "Markup percentage added when the expense is invoiced (e.g., 15)")
+	cmd.Flags().BoolVar(&billable, "billable", true, "Whether the expense is passed on to the client, use --billable=false for your own costs")
+	cmd.Flags().Float64Var(&gst, "gst", 0.0, "GST included in the amount, claimed as a GST credit on your BAS")
 
 	cmd.MarkFlagRequired("amount")
 
+	cmd.Annotations = mutating()
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 
@@ -51,15 +69,27 @@ func newExpensesCreateCmd(timesheetService *service.TimesheetService) *cobra.Com
 			return fmt.Errorf("amount must be greater than 0")
 		}
 
+		if markup < 0 {
+			return fmt.Errorf("markup must not be negative")
+		}
+
+		if gst < 0 || gst > amount {
+			return fmt.Errorf("gst must be between 0 and the amount of the expense")
+		}
+
+		if err := service.ValidateExpenseCategory(category); err != nil {
+			return err
+		}
+
 		// Parse expense date
 		var parsedDate time.Time
 		var err error
 		if expenseDate == "" {
 			parsedDate = time.Now()
 		} else {
-			parsedDate, err = time.Parse("2006-01-02", expenseDate)
+			parsedDate, err = timesheetService.ParseDateString(expenseDate)
 			if err != nil {
-				return fmt.Errorf("invalid date format, use YYYY-MM-DD: %w", err)
+				return err
 			}
 		}
 
@@ -84,13 +114,108 @@ func newExpensesCreateCmd(timesheetService *service.TimesheetService) *cobra.Com
 			descPtr = &description
 		}
 
-		expense, err := timesheetService.CreateExpense(ctx, decimal.NewFromFloat(amount), parsedDate, refPtr, clientID, nil, descPtr)
+		var categoryPtr *string
+		if category != "" {
+			category = strings.ToLower(category)
+			categoryPtr = &category
+		}
+
+		var markupPtr *decimal.Decimal
+		if markup > 0 {
+			m := decimal.NewFromFloat(markup)
+			markupPtr = &m
+		}
+
+		var gstPtr *decimal.Decimal
+		if gst > 0 {
+			g := decimal.NewFromFloat(gst)
+			gstPtr = &g
+		}
+
+		expense, err := timesheetService.CreateExpense(ctx, decimal.NewFromFloat(amount), parsedDate, refPtr, clientID, nil, descPtr, categoryPtr, markupPtr, billable, gstPtr)
 		if err != nil {
 			return fmt.Errorf("failed to create expense: %w", err)
 		}
 
 		fmt.Printf("Created expense: %s\n", expense.ID)
 		timesheetService.DisplayExpense(ctx, expense)
+		showHints(cmd, expenseCreated, hintSubject{expense: expense})
+
+		return nil
+	}
+
+	return cmd
+}
+
+func newExpensesMileageCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var km, rate float64
+	var expenseDate, reference, client, description string
+	var billable bool
+
+	cmd := &cobra.Command{
+		Use:   "mileage",
+		Short: "Record a travel expense from the distance driven",
+		Long: `Record a travel expense for a client from the distance driven. The amount is the distance multiplied by
+the rate per kilometre, which defaults to MILEAGE_RATE (the ATO cents per kilometre rate unless configured).
+The distance and rate are shown on the invoice, e.g. "Travel 42km @ $0.88".`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().Float64Var(&km, "km", 0.0, "Distance travelled in kilometres (required)")
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Client name to associate with the expense (required)")
+	cmd.Flags().Float64Var(&rate, "rate", 0.0, "Rate per kilometre (defaults to MILEAGE_RATE)")
+	cmd.Flags().StringVarP(&expenseDate, "date", "d", "", "Date of the trip (YYYY-MM-DD or a timestamp, defaults to today)")
+	cmd.Flags().StringVarP(&reference, "reference", "r", "", "Reference for the expense")
+	cmd.Flags().StringVarP(&description, "description", "", "", "Description of the expense")
+	cmd.Flags().BoolVar(&billable, "billable", true, "Whether the expense is passed on to the client, use --billable=false for your own costs")
+
+	cmd.MarkFlagRequired("km")
+	cmd.MarkFlagRequired("client")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		if km <= 0 {
+			return fmt.Errorf("km must be greater than 0")
+		}
+
+		var ratePtr *decimal.Decimal
+		if cmd.Flags().Changed("rate") {
+			if rate <= 0 {
+				return fmt.Errorf("rate must be greater than 0")
+			}
+			r := decimal.NewFromFloat(rate)
+			ratePtr = &r
+		}
+
+		parsedDate := time.Now()
+		if expenseDate != "" {
+			var err error
+			parsedDate, err = timesheetService.ParseDateString(expenseDate)
+			if err != nil {
+				return err
+			}
+		}
+
+		var refPtr *string
+		if reference != "" {
+			refPtr = &reference
+		}
+
+		var descPtr *string
+		if description != "" {
+			descPtr = &description
+		}
+
+		expense, err := timesheetService.CreateMileageExpense(ctx, client, decimal.NewFromFloat(km), ratePtr, parsedDate, refPtr, descPtr, billable)
+		if err != nil {
+			return fmt.Errorf("failed to create mileage expense: %w", err)
+		}
+
+		fmt.Printf("Created expense: %s\n", expense.ID)
+		timesheetService.DisplayExpense(ctx, expense)
+		showHints(cmd, expenseCreated, hintSubject{expense: expense})
 
 		return nil
 	}
@@ -100,16 +225,22 @@ func newExpensesCreateCmd(timesheetService *service.TimesheetService) *cobra.Com
 
 func newExpensesListCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	var verbose bool
-	var client string
+	var client, category string
 	var fromDate, toDate string
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List expenses",
-		Long:  "Display a list of expenses with optional filtering by client and date range.",
+		Long:  "Display a list of expenses with optional filtering by client, category and date range.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
+			if category != "" && !strings.EqualFold(category, "uncategorised") {
+				if err := service.ValidateExpenseCategory(category); err != nil {
+					return err
+				}
+			}
+
 			var expenses []*models.Expense
 			var err error
 
@@ -148,6 +279,10 @@ func newExpensesListCmd(timesheetService *service.TimesheetService) *cobra.Comma
 				return fmt.Errorf("failed to list expenses: %w", err)
 			}
 
+			if category != "" {
+				expenses = service.FilterExpensesByCategory(expenses, category)
+			}
+
 			if len(expenses) == 0 {
 				fmt.Println("No expenses found.")
 				return nil
@@ -162,7 +297,7 @@ func newExpensesListCmd(timesheetService *service.TimesheetService) *cobra.Comma
 				} else {
 					fmt.Printf("%s - %s - %s",
 						expense.ExpenseDate.Format("2006-01-02"),
-						timesheetService.FormatBillableAmount(expense.Amount),
+						timesheetService.FormatExpenseAmount(expense),
 						expense.ID)
 
 					if expense.Reference != nil && *expense.Reference != "" {
@@ -173,6 +308,24 @@ func newExpensesListCmd(timesheetService *service.TimesheetService) *cobra.Comma
 						fmt.Printf(" - %s", *expense.Description)
 					}
 
+					if mileage := timesheetService.FormatMileage(expense); mileage != "" {
+						fmt.Printf(" - %s", mileage)
+					}
+
+					if expense.Category != nil && *expense.Category != "" {
+						fmt.Printf(" - [%s]", *expense.Category)
+					}
+
+					if expense.Draft {
+						fmt.Print(" - draft")
+					}
+
+					if !expense.Billable {
+						fmt.Print(" - not billable")
+					} else if expense.MarkupPercent != nil && !expense.MarkupPercent.IsZero() {
+						fmt.Printf(" - +%s%% markup", expense.MarkupPercent.String())
+					}
+
 					if expense.ClientID != nil {
 						client, err := timesheetService.GetClientByID(ctx, *expense.ClientID)
 						if err == nil {
@@ -190,6 +343,7 @@ func newExpensesListCmd(timesheetService *service.TimesheetService) *cobra.Comma
 
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed expense information")
 	cmd.Flags().StringVarP(&client, "client", "c", "", "Filter by client name")
+	cmd.Flags().StringVar(&category, "category", "", "Filter by category, or \"uncategorised\" for expenses without one")
 	cmd.Flags().StringVar(&fromDate, "from", "", "Filter from date (YYYY-MM-DD)")
 	cmd.Flags().StringVar(&toDate, "to", "", "Filter to date (YYYY-MM-DD)")
 
@@ -197,22 +351,28 @@ func newExpensesListCmd(timesheetService *service.TimesheetService) *cobra.Comma
 }
 
 func newExpensesUpdateCmd(timesheetService *service.TimesheetService) *cobra.Command {
-	var amount float64
-	var expenseDate, reference, client, description string
+	var amount, markup, gst float64
+	var expenseDate, reference, client, description, category string
+	var billable bool
 
 	cmd := &cobra.Command{
 		Use:   "update <expense-id>",
 		Short: "Update an expense",
-		Long:  "Update attributes of an expense, such as amount, date, reference, description, or client.",
+		Long:  "Update attributes of an expense, such as amount, date, reference, description, category, or client.",
 		Args:  cobra.ExactArgs(1),
 	}
 
 	cmd.Flags().Float64VarP(&amount, "amount", "a", 0.0, "New amount for the expense")
-	cmd.Flags().StringVarP(&expenseDate, "date", "d", "", "New date for the expense (YYYY-MM-DD)")
+	cmd.Flags().StringVarP(&expenseDate, "date", "d", "", "New date for the expense (YYYY-MM-DD or a timestamp)")
 	cmd.Flags().StringVarP(&reference, "reference", "r", "", "New reference for the expense")
 	cmd.Flags().StringVarP(&description, "description", "", "", "New description for the expense")
 	cmd.Flags().StringVarP(&client, "client", "c", "", "New client name for the expense")
+	cmd.Flags().StringVar(&category, "category", "", "New category for the expense ("+strings.Join(service.ExpenseCategories, ", ")+"), empty to clear")
+	cmd.Flags().Float64Var(&markup, "markup", 0.0, "New markup percentage for the expense, 0 to bill at cost")
+	cmd.Flags().BoolVar(&billable, "billable", true, "Whether the expense is passed on to the client")
+	cmd.Flags().Float64Var(&gst, "gst", 0.0, "New GST included in the amount, 0 when no GST was charged")
 
+	cmd.Annotations = mutating()
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		expenseID := args[0]
@@ -223,6 +383,10 @@ func newExpensesUpdateCmd(timesheetService *service.TimesheetService) *cobra.Com
 		var refPtr *string
 		var clientPtr *string
 		var descPtr *string
+		var categoryPtr *string
+		var markupPtr *decimal.Decimal
+		var billablePtr *bool
+		var gstPtr *decimal.Decimal
 
 		if amount > 0 {
 			amt := decimal.NewFromFloat(amount)
@@ -230,9 +394,9 @@ func newExpensesUpdateCmd(timesheetService *service.TimesheetService) *cobra.Com
 		}
 
 		if expenseDate != "" {
-			parsedDate, err := time.Parse("2006-01-02", expenseDate)
+			parsedDate, err := timesheetService.ParseDateString(expenseDate)
 			if err != nil {
-				return fmt.Errorf("invalid date format, use YYYY-MM-DD: %w", err)
+				return err
 			}
 			datePtr = &parsedDate
 		}
@@ -249,7 +413,35 @@ func newExpensesUpdateCmd(timesheetService *service.TimesheetService) *cobra.Com
 			clientPtr = &client
 		}
 
-		updatedExpense, err := timesheetService.UpdateExpense(ctx, expenseID, amountPtr, datePtr, refPtr, clientPtr, nil, descPtr)
+		if cmd.Flags().Changed("category") {
+			if err := service.ValidateExpenseCategory(category); err != nil {
+				return err
+			}
+			category = strings.ToLower(category)
+			categoryPtr = &category
+		}
+
+		if cmd.Flags().Changed("markup") {
+			if markup < 0 {
+				return fmt.Errorf("markup must not be negative")
+			}
+			m := decimal.NewFromFloat(markup)
+			markupPtr = &m
+		}
+
+		if cmd.Flags().Changed("billable") {
+			billablePtr = &billable
+		}
+
+		if cmd.Flags().Changed("gst") {
+			if gst < 0 {
+				return fmt.Errorf("gst must not be negative")
+			}
+			g := decimal.NewFromFloat(gst)
+			gstPtr = &g
+		}
+
+		updatedExpense, err := timesheetService.UpdateExpense(ctx, expenseID, amountPtr, datePtr, refPtr, clientPtr, nil, descPtr, categoryPtr, markupPtr, billablePtr, gstPtr)
 		if err != nil {
 			return fmt.Errorf("failed to update expense: %w", err)
 		}
@@ -262,3 +454,428 @@ func newExpensesUpdateCmd(timesheetService *service.TimesheetService) *cobra.Com
 
 	return cmd
 }
+
+func newExpensesDeleteCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "delete <expense-id>",
+		Short: "Delete an expense",
+		Long:  "Delete an expense, moving it to the trash. It can be brought back with 'work trash restore' until it's purged. Expenses that are already on an invoice are only deleted with --force, after which the invoice should be regenerated.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.DeleteExpense(cmd.Context(), args[0], force)
+		},
+		Annotations: mutating(),
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Delete the expense even if it is on an invoice")
+
+	return cmd
+}
+
+func newExpensesImportReceiptsCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client string
+	var watch bool
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "import-receipts",
+		Short: "Import receipts dropped in client receipts folders as draft expenses",
+		Long: `Import receipt files dropped into the receipts folder of each client's directory (e.g. ~/work/acme/receipts)
+as draft expenses. The date, reference and amount are read from file names like 2025-03-14_officeworks_45.90.pdf,
+and the file's modification time is used when there's no date. Drafts aren't invoiced until they're confirmed
+with 'work expenses review'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if watch {
+				ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+				defer stop()
+				fmt.Printf("Watching receipts folders every %s, press Ctrl+C to stop\n", interval)
+				return timesheetService.WatchReceipts(ctx, client, interval)
+			}
+
+			imported, err := timesheetService.ImportReceipts(cmd.Context(), client)
+			if err != nil {
+				return err
+			}
+			if imported == 0 {
+				fmt.Println("No new receipts found")
+				return nil
+			}
+			fmt.Printf("Imported %d receipt(s), review them with 'work expenses review'\n", imported)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Only import receipts for this client")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Keep watching the receipts folders for new files")
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "How often to check for new receipts when watching")
+
+	return cmd
+}
+
+func newExpensesReviewCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Confirm, correct or discard draft expenses imported from receipts",
+		Long:  "Step through draft expenses imported from receipts, confirming each at its parsed amount, correcting the amount, skipping it for now or deleting it.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			drafts, err := timesheetService.ListDraftExpenses(ctx)
+			if err != nil {
+				return err
+			}
+			if len(drafts) == 0 {
+				fmt.Println("No draft expenses to review")
+				return nil
+			}
+
+			reader := bufio.NewReader(os.Stdin)
+			prompt := func(question string) (string, error) {
+				fmt.Print(question)
+				response, err := reader.ReadString('\n')
+				if err != nil {
+					return "", err
+				}
+				return strings.TrimSpace(response), nil
+			}
+
+			confirmed := 0
+			for i, draft := range drafts {
+				fmt.Printf("\nDraft %d of %d\n", i+1, len(drafts))
+				timesheetService.DisplayExpense(ctx, draft)
+
+				response, err := prompt("[a]ccept, [e]dit amount, [s]kip, [d]elete (a/e/s/d): ")
+				if err != nil {
+					return err
+				}
+
+				amount := draft.Amount
+				switch strings.ToLower(response) {
+				case "a", "accept":
+					if !amount.IsPositive() {
+						fmt.Println("No amount was found on this receipt, enter one instead")
+						amount, err = promptAmount(prompt)
+						if err != nil {
+							return err
+						}
+					}
+				case "e", "edit":
+					amount, err = promptAmount(prompt)
+					if err != nil {
+						return err
+					}
+				case "d", "delete":
+					if err := timesheetService.DeleteExpense(ctx, draft.ID, false); err != nil {
+						return err
+					}
+					if draft.ReceiptPath != nil {
+						fmt.Printf("Move %s out of the receipts folder or it will be imported again\n", *draft.ReceiptPath)
+					}
+					continue
+				default:
+					fmt.Println("Skipped")
+					continue
+				}
+
+				expense, err := timesheetService.ConfirmExpense(ctx, draft.ID, amount)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Confirmed expense %s (%s)\n", expense.ID, timesheetService.FormatExpenseAmount(expense))
+				confirmed++
+			}
+
+			fmt.Printf("\nConfirmed %d of %d draft expense(s)\n", confirmed, len(drafts))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newExpensesImportCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var dryRun, debitsPositive bool
+
+	cmd := &cobra.Command{
+		Use:   "import <statement.csv>",
+		Short: "Import expenses from a card or bank statement CSV",
+		Long: `Import the purchases on a card or bank statement exported as CSV. Each transaction is matched against the
+rules from 'work expenses rules', and the first matching rule sets its client, category and whether it's
+billable, or skips it. Transactions no rule matches are asked about one at a time when run interactively, where
+a rule can be saved for next time, and are listed otherwise. Transactions already imported are left out, so an
+overlapping statement can be imported again. Everything is created in one go, once every transaction is sorted.
+
+Statements with a header row are read by their column names, such as Date, Description and Amount or Debit.
+Those without one are read as date, amount and description. Purchases are negative amounts unless
+--debits-positive is given.`,
+		Example: `  work expenses rules add --pattern "github|figma" --client acme --category software --billable
+  work expenses import ~/Downloads/statement.csv --dry-run
+  work expenses import ~/Downloads/statement.csv`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open statement: %w", err)
+			}
+			defer file.Close()
+
+			statement, err := timesheetService.ReadStatement(ctx, file, debitsPositive)
+			if err != nil {
+				return err
+			}
+			if len(statement.Transactions) == 0 {
+				fmt.Println("No purchases found on the statement")
+				return nil
+			}
+
+			unmatched := statement.Unmatched()
+			if dryRun {
+				for _, transaction := range statement.Transactions {
+					fmt.Println(timesheetService.StatementTransactionSummary(transaction))
+				}
+				fmt.Printf("\nWould import %d expense(s), %d transaction(s) matched no rule\n", len(statement.Expenses()), len(unmatched))
+				return nil
+			}
+
+			if len(unmatched) > 0 && stdinIsTerminal() {
+				// Reaching the end of input, as with stdin from /dev/null, leaves the rest unmatched
+				if err := assignStatementTransactions(cmd, timesheetService, statement); errors.Is(err, io.EOF) {
+					fmt.Println()
+				} else if err != nil {
+					return err
+				}
+				unmatched = statement.Unmatched()
+			}
+
+			expenses, err := timesheetService.ImportStatement(ctx, statement)
+			if err != nil {
+				return err
+			}
+
+			skipped, duplicates := 0, 0
+			for _, transaction := range statement.Transactions {
+				if transaction.Duplicate {
+					duplicates++
+				} else if transaction.Skipped {
+					skipped++
+				}
+			}
+			fmt.Printf("Imported %d expense(s)", len(expenses))
+			if skipped > 0 {
+				fmt.Printf(", skipped %d", skipped)
+			}
+			if duplicates > 0 {
+				fmt.Printf(", %d already imported", duplicates)
+			}
+			if statement.Credits > 0 {
+				fmt.Printf(", ignored %d credit(s)", statement.Credits)
+			}
+			fmt.Println()
+
+			if len(unmatched) > 0 {
+				fmt.Printf("\n%d transaction(s) matched no rule and weren't imported:\n", len(unmatched))
+				for _, transaction := range unmatched {
+					fmt.Printf("  line %d: %s\n", transaction.Line, timesheetService.StatementTransactionSummary(transaction))
+				}
+				fmt.Println("Add rules for them with 'work expenses rules add', or import the statement again interactively")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what each transaction would be imported as without importing anything")
+	cmd.Flags().BoolVar(&debitsPositive, "debits-positive", false, "Read positive amounts as purchases, for statements that show them that way")
+
+	return cmd
+}
+
+// assignStatementTransactions asks which client and category each transaction no rule matched is for, offering
+// to save a rule for the transactions like it
+func assignStatementTransactions(cmd *cobra.Command, timesheetService *service.TimesheetService, statement *service.Statement) error {
+	ctx := cmd.Context()
+	reader := bufio.NewReader(os.Stdin)
+	prompt := func(question string) (string, error) {
+		fmt.Print(question)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(response), nil
+	}
+
+	unmatched := statement.Unmatched()
+	for i, transaction := range unmatched {
+		if transaction.Expense != nil || transaction.Skipped {
+			// Matched by a rule saved for an earlier transaction
+			continue
+		}
+		fmt.Printf("\nUnmatched %d of %d: %s %s %s\n", i+1, len(unmatched), transaction.Date.Format("2006-01-02"),
+			transaction.Description, timesheetService.FormatExpenseAmount(&models.Expense{Amount: transaction.Amount}))
+
+		var client, category string
+		var billable, skip bool
+		for {
+			response, err := prompt("Client (blank for your own costs, s to skip): ")
+			if err != nil {
+				return err
+			}
+			if strings.EqualFold(response, "s") {
+				skip = true
+				transaction.Skipped = true
+				break
+			}
+			client = response
+			if client != "" {
+				if _, err := timesheetService.GetClientByName(ctx, client); err != nil {
+					fmt.Printf("No client named '%s'\n", client)
+					continue
+				}
+			}
+			break
+		}
+
+		if !skip {
+			for {
+				response, err := prompt("Category (" + strings.Join(service.ExpenseCategories, ", ") + ", blank for none): ")
+				if err != nil {
+					return err
+				}
+				if err := service.ValidateExpenseCategory(response); err != nil {
+					fmt.Println(err)
+					continue
+				}
+				category = response
+				break
+			}
+			if client != "" {
+				response, err := prompt("Billable to " + client + "? (y/N): ")
+				if err != nil {
+					return err
+				}
+				billable = strings.EqualFold(response, "y") || strings.EqualFold(response, "yes")
+			}
+			if err := timesheetService.AssignStatementTransaction(ctx, transaction, client, category, billable); err != nil {
+				return err
+			}
+		}
+
+		pattern, err := prompt("Save a rule for transactions like this? Enter a pattern, or leave blank: ")
+		if err != nil {
+			return err
+		}
+		if pattern == "" {
+			continue
+		}
+		rule, err := timesheetService.AddExpenseRule(ctx, pattern, client, category, billable, skip)
+		if err != nil {
+			fmt.Printf("Rule not saved: %v\n", err)
+			continue
+		}
+		matched, err := statement.ApplyRule(rule)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Saved rule %s", service.ExpenseRuleSummary(rule))
+		if matched > 0 {
+			fmt.Printf(", matching %d more transaction(s)", matched)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func newExpensesRulesCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Manage the rules for importing card and bank statements",
+		Long: `Rules match statement transactions by description when importing with 'work expenses import'. Patterns
+are regular expressions matched ignoring case, and rules are tried in the order they were added.`,
+	}
+
+	cmd.AddCommand(newExpensesRulesAddCmd(timesheetService))
+	cmd.AddCommand(newExpensesRulesListCmd(timesheetService))
+	cmd.AddCommand(newExpensesRulesDeleteCmd(timesheetService))
+
+	return cmd
+}
+
+func newExpensesRulesAddCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var pattern, client, category string
+	var billable, skip bool
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a rule for importing statements",
+		Example: `  work expenses rules add --pattern "github|figma" --client acme --category software --billable
+  work expenses rules add --pattern "qantas|virgin australia" --category travel
+  work expenses rules add --pattern "payment received" --skip`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rule, err := timesheetService.AddExpenseRule(cmd.Context(), pattern, client, category, billable, skip)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Added rule %s: %s\n", rule.ID, service.ExpenseRuleSummary(rule))
+			return nil
+		},
+		Annotations: mutating(),
+	}
+
+	cmd.Flags().StringVarP(&pattern, "pattern", "p", "", "Regular expression matched against transaction descriptions (required)")
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Client the matching expenses are for")
+	cmd.Flags().StringVar(&category, "category", "", "Category of the matching expenses ("+strings.Join(service.ExpenseCategories, ", ")+")")
+	cmd.Flags().BoolVar(&billable, "billable", false, "Pass the matching expenses on to the client")
+	cmd.Flags().BoolVar(&skip, "skip", false, "Leave the matching transactions out, such as card repayments or personal purchases")
+	cmd.MarkFlagRequired("pattern")
+
+	return cmd
+}
+
+func newExpensesRulesListCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the rules for importing statements, in the order they're tried",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.DisplayExpenseRules(cmd.Context())
+		},
+	}
+}
+
+func newExpensesRulesDeleteCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete a rule for importing statements",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rule, err := timesheetService.DeleteExpenseRule(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Deleted rule %s\n", service.ExpenseRuleSummary(rule))
+			return nil
+		},
+		Annotations: mutating(),
+	}
+}
+
+// promptAmount asks for an expense amount until a positive number is entered
+func promptAmount(prompt func(string) (string, error)) (decimal.Decimal, error) {
+	for {
+		response, err := prompt("Amount: ")
+		if err != nil {
+			return decimal.Zero, err
+		}
+		amount, err := decimal.NewFromString(strings.TrimPrefix(response, "$"))
+		if err == nil && amount.IsPositive() {
+			return amount, nil
+		}
+		fmt.Println("Enter an amount greater than 0, e.g. 45.90")
+	}
+}
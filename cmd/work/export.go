@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newExportCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export data for backup, analysis or another tool",
+		Long: `Export clients, users, sessions, invoices, payments, credit notes, expenses, session templates, clients'
+rate history, contact log and milestones, the repositories sessions were described from, the rules for importing
+statements and leave as JSON or CSV. Field names match across formats and only change with the schema version in a full
+export's manifest. Restore a full JSON export with 'work import'.`,
+	}
+
+	cmd.AddCommand(newExportAllCmd(timesheetService))
+	for _, entity := range service.ExportEntities {
+		cmd.AddCommand(newExportEntityCmd(timesheetService, entity))
+	}
+
+	return cmd
+}
+
+func newExportAllCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var format, output string
+
+	cmd := &cobra.Command{
+		Use:   "all",
+		Short: "Export everything to a directory",
+		Long: fmt.Sprintf(`Export everything to a file per kind of record in the output directory (%s), with a
+manifest.json giving the schema version and how many of each were exported.`, strings.Join(service.ExportEntities, ", ")),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.ExportAll(ctx, format, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", service.ExportFormatJSON, "Export format: json or csv")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Directory to write the export to")
+	cmd.MarkFlagRequired("output")
+
+	return cmd
+}
+
+func newExportEntityCmd(timesheetService *service.TimesheetService, entity string) *cobra.Command {
+	var format, output string
+	name := strings.ReplaceAll(entity, "_", " ")
+
+	cmd := &cobra.Command{
+		Use:   strings.ReplaceAll(entity, "_", "-"),
+		Short: fmt.Sprintf("Export all %s", name),
+		Long:  fmt.Sprintf("Export all %s as JSON or CSV, to a file or stdout.", name),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.ExportEntity(ctx, entity, format, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", service.ExportFormatJSON, "Export format: json or csv")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default: stdout)")
+
+	return cmd
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"github.com/jesses-code-adventures/work/internal/service"
@@ -8,13 +10,18 @@ import (
 
 func newGitCheckCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "git-check <session-id>",
+		Use:   "git-check [session-id]",
 		Short: "Debug git commands for a specific session",
-		Long:  "Shows exactly what git commands are executed for a session's time period and their outputs.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Shows exactly what git commands are executed for a session's time period and their outputs.
+The session can be given by the start of its ID, or picked from your recent sessions when it's left out.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			sessionID := args[0]
-			return timesheetService.GitCheckSession(sessionID)
+			ctx := cmd.Context()
+			sessionID, err := sessionIDArg(cmd, timesheetService, strings.Join(args, ""))
+			if err != nil {
+				return err
+			}
+			return timesheetService.GitCheckSession(ctx, sessionID)
 		},
 	}
 
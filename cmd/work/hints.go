@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// outcome identifies what a command just did, so a hint can suggest a sensible next step
+type outcome int
+
+const (
+	clientCreated outcome = iota
+	sessionFinished
+	expenseCreated
+)
+
+// hintSubject carries what the hints for an outcome need to know about the result
+type hintSubject struct {
+	client  *models.Client
+	session *models.WorkSession
+	expense *models.Expense
+}
+
+// nextStepHints returns the hints for each outcome, or nothing when there's no obvious next step
+var nextStepHints = map[outcome]func(hintSubject) []string{
+	clientCreated: func(h hintSubject) []string {
+		rate := "<rate>"
+		if h.client.HourlyRate.IsPositive() {
+			rate = h.client.HourlyRate.String()
+		}
+
+		var hints []string
+		if !h.client.HourlyRate.IsPositive() && h.client.RetainerAmount == nil {
+			hints = append(hints, fmt.Sprintf("Set an hourly rate so sessions are billable: work clients update %s -r <rate>", h.client.Name))
+		}
+		if utils.FromPtr(h.client.Dir) == "" {
+			hints = append(hints, fmt.Sprintf("Set the client's code directory to generate session descriptions from git: work clients update %s -r %s -d <dir>", h.client.Name, rate))
+		}
+		return hints
+	},
+	sessionFinished: func(h hintSubject) []string {
+		if utils.FromPtr(h.session.Description) != "" {
+			return nil
+		}
+		if h.client != nil && utils.FromPtr(h.client.Dir) != "" {
+			return []string{fmt.Sprintf("Describe the session from its git history: work descriptions generate -s %s -u", h.session.ID)}
+		}
+		return []string{"Add notes to sessions while they're active to describe the work: work note <text>"}
+	},
+	expenseCreated: func(h hintSubject) []string {
+		if h.expense.ClientID != nil || !h.expense.Billable {
+			return nil
+		}
+		return []string{fmt.Sprintf("Link the expense to a client so it's invoiced: work expenses update %s -c <client>", h.expense.ID)}
+	},
+}
+
+// showHints prints the next step hints for an outcome to stderr, unless --quiet was given
+func showHints(cmd *cobra.Command, o outcome, subject hintSubject) {
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		return
+	}
+	hints, ok := nextStepHints[o]
+	if !ok {
+		return
+	}
+	for _, hint := range hints(subject) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Hint: %s\n", hint)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 func newHoursCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	var client string
+	var user string
 	var period string
 	var periodDate string
 	var fromDate string
@@ -16,15 +17,21 @@ func newHoursCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "hours",
 		Short: "Display total worked hours",
-		Long:  "Display total worked hours with optional filtering by client, period, or date range.",
+		Long:  "Display total worked hours with optional filtering by client, user, period, or date range. A session running past midnight counts its time towards each day it ran on, so only the part within a period or range is included.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			return timesheetService.ShowTotalHours(ctx, client, period, periodDate, fromDate, toDate)
+			total, err := timesheetService.TotalHours(ctx, client, user, period, periodDate, fromDate, toDate)
+			if err != nil {
+				return err
+			}
+			timesheetService.Presenter(cmd.OutOrStdout()).HoursTotal(total)
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&client, "client", "c", "", "Filter by client name")
-	cmd.Flags().StringVarP(&period, "period", "p", "", "Period type: day, week, fortnight, month")
+	cmd.Flags().StringVarP(&user, "user", "u", "", "Filter by the user who did the work, or '"+service.OwnerUser+"' for your own")
+	cmd.Flags().StringVarP(&period, "period", "p", "", "Period type: day, week, fortnight, month, quarter, year")
 	cmd.Flags().StringVarP(&periodDate, "date", "d", "", "Date in the period (YYYY-MM-DD), defaults to today when using -p")
 	cmd.Flags().StringVarP(&fromDate, "from", "f", "", "Show hours from this date (YYYY-MM-DD)")
 	cmd.Flags().StringVarP(&toDate, "to", "t", "", "Show hours to this date (YYYY-MM-DD)")
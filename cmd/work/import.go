@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newImportCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var input string
+	var merge, replace, yes bool
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Restore data from a JSON export",
+		Long: `Restore a directory written by 'work export all --format json', e.g. to move to another machine without copying
+the database. The export is checked for references to records it doesn't contain before anything is written,
+and it's imported in a single transaction.
+
+--merge keeps what's already in the database. Clients, users and templates are matched by name, invoices and
+credit notes by number and sessions by client and start time, so they're skipped rather than duplicated and the
+imported records that refer to them are pointed at the existing ones.
+
+--replace deletes every client, user, session, invoice, payment, credit note, expense and template first, along
+with clients' rate history, contact log and milestones, the rules for importing statements and leave.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			mode := service.ImportModeMerge
+			if replace {
+				mode = service.ImportModeReplace
+				if !yes {
+					info, err := os.Stdin.Stat()
+					if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+						return fmt.Errorf("--replace deletes everything in the database first, pass --yes to confirm")
+					}
+					fmt.Print("This will permanently delete everything in the database and replace it with the export. Are you sure? (y/N): ")
+					reader := bufio.NewReader(os.Stdin)
+					response, err := reader.ReadString('\n')
+					if err != nil {
+						return err
+					}
+					response = strings.ToLower(strings.TrimSpace(response))
+					if response != "y" && response != "yes" {
+						fmt.Println("Operation cancelled.")
+						return nil
+					}
+				}
+			}
+
+			return timesheetService.ImportData(ctx, input, mode)
+		},
+		Annotations: mutating(),
+	}
+
+	cmd.Flags().StringVarP(&input, "input", "i", "", "Directory written by 'work export all'")
+	cmd.Flags().BoolVar(&merge, "merge", false, "Add to what's already in the database, skipping records already present")
+	cmd.Flags().BoolVar(&replace, "replace", false, "Delete everything in the database first")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Replace without asking to confirm")
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagsMutuallyExclusive("merge", "replace")
+	cmd.MarkFlagsOneRequired("merge", "replace")
+
+	return cmd
+}
@@ -2,18 +2,24 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/jesses-code-adventures/work/internal/config"
 	"github.com/jesses-code-adventures/work/internal/database"
+	"github.com/jesses-code-adventures/work/internal/models"
 	"github.com/jesses-code-adventures/work/internal/service"
+	"github.com/jesses-code-adventures/work/internal/utils"
 	"github.com/shopspring/decimal"
 )
 
@@ -32,6 +38,8 @@ func TestIntegrationWorkCommands(t *testing.T) {
 		DatabaseDriver: "sqlite3",
 		DatabaseName:   "test",
 		DevMode:        true,
+		Aliases:        map[string][]string{"l": {"sessions", "list"}},
+		DefaultFlags:   map[string][]string{"sessions list": {"--limit", "1"}},
 	}
 
 	// Initialize database
@@ -76,6 +84,20 @@ func TestIntegrationWorkCommands(t *testing.T) {
 		}
 	})
 
+	t.Run("Work Start With Loose Client Name", func(t *testing.T) {
+		output := captureOutput(func() {
+			rootCmd.SetArgs([]string{"start", "-c", "TEST-cli", "-d", "Test session"})
+			err := rootCmd.ExecuteContext(ctx)
+			if err != nil {
+				t.Errorf("Work start command failed: %v", err)
+			}
+		})
+
+		if !strings.Contains(output, "Started work session for test-client") {
+			t.Errorf("Expected the session to be started for test-client, got: %s", output)
+		}
+	})
+
 	t.Run("Work Status", func(t *testing.T) {
 		output := captureOutput(func() {
 			rootCmd.SetArgs([]string{"status"})
@@ -90,6 +112,28 @@ func TestIntegrationWorkCommands(t *testing.T) {
 		}
 	})
 
+	t.Run("Work Serve Status", func(t *testing.T) {
+		handler := newStatusHandler(timesheetService, "secret")
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 without an API key, got %d", recorder.Code)
+		}
+
+		request := httptest.NewRequest(http.MethodGet, "/status", nil)
+		request.Header.Set("Authorization", "Bearer secret")
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		var status service.WorkStatus
+		if err := json.NewDecoder(recorder.Body).Decode(&status); err != nil {
+			t.Fatalf("Failed to decode status: %v", err)
+		}
+		if recorder.Code != http.StatusOK || !status.Active || status.Client != "test-client" {
+			t.Errorf("Expected test-client to be active, got %d: %+v", recorder.Code, status)
+		}
+	})
+
 	t.Run("Work Stop", func(t *testing.T) {
 		output := captureOutput(func() {
 			rootCmd.SetArgs([]string{"stop"})
@@ -112,7 +156,7 @@ func TestIntegrationWorkCommands(t *testing.T) {
 
 	t.Run("Work Note", func(t *testing.T) {
 		// Start a new session first
-		_, err := timesheetService.StartWork(ctx, "test-client", nil)
+		_, err := timesheetService.StartWork(ctx, "test-client", nil, nil, false)
 		if err != nil {
 			t.Fatalf("Failed to start work session: %v", err)
 		}
@@ -150,6 +194,25 @@ func TestIntegrationWorkCommands(t *testing.T) {
 		}
 	})
 
+	t.Run("Work Sessions Create ISO8601", func(t *testing.T) {
+		output := captureOutput(func() {
+			rootCmd.SetArgs([]string{"sessions", "create", "-c", "test-client", "-f", "2025-08-21T09:00:30", "-t", "2025-08-21 11:30:15"})
+			err := rootCmd.ExecuteContext(ctx)
+			if err != nil {
+				t.Errorf("Work sessions create command failed: %v", err)
+			}
+		})
+
+		if !strings.Contains(output, "Created session for") {
+			t.Errorf("Expected 'Created session for' in output, got: %s", output)
+		}
+
+		rootCmd.SetArgs([]string{"sessions", "create", "-c", "test-client", "-f", "21/08/2025 09:00", "-t", "2025-08-21 11:30"})
+		if err := rootCmd.ExecuteContext(ctx); err == nil {
+			t.Errorf("Expected an error for an unsupported time format")
+		}
+	})
+
 	t.Run("Work Sessions List", func(t *testing.T) {
 		output := captureOutput(func() {
 			rootCmd.SetArgs([]string{"sessions", "list"})
@@ -164,12 +227,26 @@ func TestIntegrationWorkCommands(t *testing.T) {
 		}
 	})
 
+	t.Run("Work Alias With Default Flags", func(t *testing.T) {
+		output := captureOutput(func() {
+			rootCmd.SetArgs([]string{"l"})
+			err := rootCmd.ExecuteContext(ctx)
+			if err != nil {
+				t.Errorf("Work alias command failed: %v", err)
+			}
+		})
+
+		if got := strings.Count(output, "test-client |"); got != 1 {
+			t.Errorf("Expected the alias to list 1 session with the default --limit, got %d in: %s", got, output)
+		}
+	})
+
 	t.Run("Work Sessions CSV", func(t *testing.T) {
 		// Create a temporary CSV file
 		csvFile := filepath.Join(tempDir, "test_export.csv")
 
 		// Clear all sessions
-		err := timesheetService.DeleteAllSessions(ctx)
+		_, _, err := timesheetService.DeleteAllSessions(ctx, false)
 		if err != nil {
 			t.Fatalf("Failed to delete all sessions: %v", err)
 		}
@@ -242,6 +319,80 @@ func TestIntegrationWorkCommands(t *testing.T) {
 		}
 	})
 
+	t.Run("Work Clients Update Without Rate", func(t *testing.T) {
+		client, err := timesheetService.GetClientByName(ctx, "new-client")
+		if err != nil {
+			t.Fatalf("Failed to get client: %v", err)
+		}
+		history, err := db.ListClientRates(ctx, client.ID)
+		if err != nil {
+			t.Fatalf("Failed to list client rates: %v", err)
+		}
+
+		// A new command, as flags given to the last one are still set on it
+		captureOutput(func() {
+			cmd := newRootCmd(timesheetService)
+			cmd.SetArgs([]string{"clients", "update", "new-client", "--email", "accounts@new-client.test"})
+			if err := cmd.ExecuteContext(ctx); err != nil {
+				t.Errorf("Work clients update command failed: %v", err)
+			}
+		})
+
+		updated, err := timesheetService.GetClientByName(ctx, "new-client")
+		if err != nil {
+			t.Fatalf("Failed to get client: %v", err)
+		}
+		if !updated.HourlyRate.Equal(decimal.NewFromFloat(80.0)) {
+			t.Errorf("Expected the rate to stay 80.00 without --rate, got %s", updated.HourlyRate)
+		}
+		if after, err := db.ListClientRates(ctx, client.ID); err != nil || len(after) != len(history) {
+			t.Errorf("Expected %d rate change(s) without --rate, got %d (err %v)", len(history), len(after), err)
+		}
+	})
+
+	t.Run("Work Clients Update Keeps Invoice Details", func(t *testing.T) {
+		update := func(args ...string) *models.Client {
+			t.Helper()
+			captureOutput(func() {
+				cmd := newRootCmd(timesheetService)
+				cmd.SetArgs(append([]string{"clients", "update", "new-client"}, args...))
+				if err := cmd.ExecuteContext(ctx); err != nil {
+					t.Errorf("Work clients update command failed: %v", err)
+				}
+			})
+			client, err := timesheetService.GetClientByName(ctx, "new-client")
+			if err != nil {
+				t.Fatalf("Failed to get client: %v", err)
+			}
+			return client
+		}
+		details := func(client *models.Client) string {
+			var rounding, retainerStart string
+			if client.InvoiceRounding != nil {
+				rounding = client.InvoiceRounding.String()
+			}
+			if client.RetainerStart != nil {
+				retainerStart = client.RetainerStart.Format("2006-01-02")
+			}
+			return strings.Join([]string{utils.FromPtr(client.Currency), utils.FromPtr(client.InvoiceNotes), utils.FromPtr(client.PaymentTerms),
+				utils.FromPtr(client.PoNumber), utils.FromPtr(client.ProjectCode), rounding, utils.FromPtr(client.Source),
+				utils.FromPtr(client.WorkLog), retainerStart,
+				fmt.Sprintf("%v/%v", utils.FromPtr(client.EarlyDiscountPercent), utils.FromPtr(client.EarlyDiscountDays))}, "|")
+		}
+
+		update("--currency", "eur", "--invoice-notes", "Thanks", "--payment-terms", "Net 14", "--po-number", "PO-1",
+			"--project-code", "WEB", "--invoice-rounding", "5", "--source", "referral",
+			"--work-log", "notion:abc123", "--retainer-start", "2025-07-01",
+			"--early-discount", "2", "--early-discount-days", "7")
+		if got, want := details(update("--city", "Hobart")), "EUR|Thanks|Net 14|PO-1|WEB|5|referral|notion:abc123|2025-07-01|2/7"; got != want {
+			t.Errorf("Expected an unrelated update to keep the invoice details %q, got %q", want, got)
+		}
+		if got, want := details(update("--po-number", "", "--invoice-rounding", "0", "--source", "", "--work-log", "",
+			"--early-discount", "0")), "EUR|Thanks|Net 14||WEB||||2025-07-01|0/0"; got != want {
+			t.Errorf("Expected empty flags to remove the PO number, rounding, source, work log and early payment discount, leaving %q, got %q", want, got)
+		}
+	})
+
 	t.Run("Work Clients List", func(t *testing.T) {
 		output := captureOutput(func() {
 			rootCmd.SetArgs([]string{"clients", "list"})
@@ -256,6 +407,146 @@ func TestIntegrationWorkCommands(t *testing.T) {
 		}
 	})
 
+	t.Run("Work Export Import Round Trip", func(t *testing.T) {
+		run := func(args ...string) {
+			t.Helper()
+			captureOutput(func() {
+				cmd := newRootCmd(timesheetService)
+				cmd.SetArgs(args)
+				if err := cmd.ExecuteContext(ctx); err != nil {
+					t.Errorf("Work %s command failed: %v", strings.Join(args, " "), err)
+				}
+			})
+		}
+		client, err := timesheetService.GetClientByName(ctx, "new-client")
+		if err != nil {
+			t.Fatalf("Failed to get client: %v", err)
+		}
+
+		user, err := db.CreateUser(ctx, "sam", nil)
+		if err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		start := time.Date(2025, 9, 1, 9, 0, 0, 0, time.Local)
+		session, err := db.CreateWorkSessionWithTimes(ctx, client.ID, start, start.Add(time.Hour), nil, client.HourlyRate, false)
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		if _, err := db.UpdateSessionUser(ctx, session.ID, &user.ID, false); err != nil {
+			t.Fatalf("Failed to attribute session: %v", err)
+		}
+		if err := db.ReplaceSessionRepos(ctx, session.ID, []*models.SessionRepo{{RepoPath: "/src/app", CommitCount: 3}}, false); err != nil {
+			t.Fatalf("Failed to record session repos: %v", err)
+		}
+		rates, err := db.ListClientRates(ctx, client.ID)
+		if err != nil || len(rates) == 0 {
+			t.Fatalf("Expected the rate change from updating the client, got %d (err %v)", len(rates), err)
+		}
+		contact, err := db.CreateClientContact(ctx, &models.ClientContact{ClientID: client.ID, Type: models.ContactTypeCall,
+			Note: "Scoping call", ContactedAt: start.Add(2 * time.Hour)})
+		if err != nil {
+			t.Fatalf("Failed to log contact: %v", err)
+		}
+		callEnd := start.Add(150 * time.Minute)
+		call, err := db.BillClientContact(ctx, contact.ID, &models.WorkSession{ClientID: client.ID,
+			StartTime: contact.ContactedAt, EndTime: &callEnd, HourlyRate: &client.HourlyRate})
+		if err != nil {
+			t.Fatalf("Failed to bill contact: %v", err)
+		}
+		rule, err := db.CreateExpenseRule(ctx, &models.ExpenseRule{Pattern: "github", ClientID: &client.ID, Billable: true})
+		if err != nil {
+			t.Fatalf("Failed to create expense rule: %v", err)
+		}
+		milestone, err := db.CreateMilestone(ctx, &models.Milestone{ClientID: client.ID, Name: "Launch", Amount: decimal.NewFromInt(500)})
+		if err != nil {
+			t.Fatalf("Failed to create milestone: %v", err)
+		}
+
+		leaveDay := time.Date(2025, 9, 5, 0, 0, 0, 0, time.UTC)
+		leave, err := db.CreateLeave(ctx, models.LeaveTypeAnnual, leaveDay, leaveDay, nil)
+		if err != nil {
+			t.Fatalf("Failed to record leave: %v", err)
+		}
+
+		exportDir := filepath.Join(tempDir, "export")
+		run("export", "all", "-o", exportDir)
+		stale, err := db.CreateLeave(ctx, models.LeaveTypeSick, leaveDay.AddDate(0, 0, 3), leaveDay.AddDate(0, 0, 3), nil)
+		if err != nil {
+			t.Fatalf("Failed to record leave: %v", err)
+		}
+		run("import", "-i", exportDir, "--replace", "-y")
+
+		if users, err := db.ListUsers(ctx); err != nil || len(users) != 1 || users[0].ID != user.ID {
+			t.Errorf("Expected sam to be imported, got %v (err %v)", users, err)
+		}
+		if imported, err := db.GetSessionByID(ctx, session.ID); err != nil || imported.UserID == nil || *imported.UserID != user.ID {
+			t.Errorf("Expected the session to still be sam's after importing, got %+v (err %v)", imported, err)
+		}
+		if repos, err := db.ListSessionRepos(ctx, session.ID); err != nil || len(repos) != 1 || repos[0].RepoPath != "/src/app" || repos[0].CommitCount != 3 {
+			t.Errorf("Expected the session's repository to be imported, got %v (err %v)", repos, err)
+		}
+		if imported, err := db.ListClientRates(ctx, client.ID); err != nil || len(imported) != len(rates) {
+			t.Errorf("Expected %d rate change(s) after importing, got %d (err %v)", len(rates), len(imported), err)
+		}
+		if imported, err := db.GetClientContactByID(ctx, contact.ID); err != nil || imported.SessionID == nil || *imported.SessionID != call.ID {
+			t.Errorf("Expected the contact to still be billed as its session after importing, got %+v (err %v)", imported, err)
+		}
+		if imported, err := db.GetExpenseRuleByID(ctx, rule.ID); err != nil || imported.ClientID == nil || *imported.ClientID != client.ID || !imported.Billable {
+			t.Errorf("Expected the expense rule to be imported, got %+v (err %v)", imported, err)
+		}
+		if imported, err := db.GetMilestoneByID(ctx, milestone.ID); err != nil || imported.Name != "Launch" || !imported.Amount.Equal(milestone.Amount) {
+			t.Errorf("Expected the milestone to be imported, got %+v (err %v)", imported, err)
+		}
+		if imported, err := db.GetLeaveByID(ctx, leave.ID); err != nil || imported.Type != models.LeaveTypeAnnual || !imported.StartDate.Equal(leaveDay) {
+			t.Errorf("Expected the leave to be imported, got %+v (err %v)", imported, err)
+		}
+		if _, err := db.GetLeaveByID(ctx, stale.ID); err == nil {
+			t.Errorf("Expected leave recorded after exporting to be deleted by --replace")
+		}
+	})
+
+	t.Run("Work Import Merge Skips Trashed Records", func(t *testing.T) {
+		client, err := timesheetService.GetClientByName(ctx, "new-client")
+		if err != nil {
+			t.Fatalf("Failed to get client: %v", err)
+		}
+		day := time.Date(2025, 9, 2, 9, 0, 0, 0, time.Local)
+		if _, err := db.CreateWorkSessionWithTimes(ctx, client.ID, day, day.Add(time.Hour), nil, client.HourlyRate, false); err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		description := "Train ticket"
+		expense, err := db.CreateExpense(ctx, decimal.NewFromInt(20), day, nil, &client.ID, nil, &description, nil, nil, false, nil)
+		if err != nil {
+			t.Fatalf("Failed to create expense: %v", err)
+		}
+
+		exportDir := filepath.Join(tempDir, "export-trash")
+		captureOutput(func() {
+			cmd := newRootCmd(timesheetService)
+			cmd.SetArgs([]string{"export", "all", "-o", exportDir})
+			if err := cmd.ExecuteContext(ctx); err != nil {
+				t.Errorf("Work export all command failed: %v", err)
+			}
+		})
+		if _, _, err := db.DeleteSessionsByDateRange(ctx, "2025-09-02 00:00:00", "2025-09-02 23:59:59", false); err != nil {
+			t.Fatalf("Failed to delete session: %v", err)
+		}
+		if err := db.DeleteExpense(ctx, expense.ID); err != nil {
+			t.Fatalf("Failed to delete expense: %v", err)
+		}
+
+		output := captureOutput(func() {
+			cmd := newRootCmd(timesheetService)
+			cmd.SetArgs([]string{"import", "-i", exportDir, "--merge"})
+			if err := cmd.ExecuteContext(ctx); err != nil {
+				t.Errorf("Expected trashed records to count as already present, import failed: %v", err)
+			}
+		})
+		if !strings.Contains(output, "already present") {
+			t.Errorf("Expected the import to skip what's already present, got: %s", output)
+		}
+	})
+
 	t.Run("Work Sessions Delete", func(t *testing.T) {
 		output := captureOutput(func() {
 			rootCmd.SetArgs([]string{"sessions", "delete", "--force"})
@@ -283,6 +574,144 @@ func TestIntegrationWorkCommands(t *testing.T) {
 			t.Errorf("Expected 'Generated invoice' or 'No invoices generated' in output, got: %s", output)
 		}
 	})
+
+	t.Run("Work Invoices Custom Range", func(t *testing.T) {
+		// One root command throughout, so the lock held by a command that fails is released by the next
+		cmd := newRootCmd(timesheetService)
+		run := func(args ...string) error {
+			cmd.SetArgs(args)
+			var err error
+			captureOutput(func() { err = cmd.ExecuteContext(ctx) })
+			return err
+		}
+		client, err := timesheetService.GetClientByName(ctx, "test-client")
+		if err != nil {
+			t.Fatalf("Failed to get client: %v", err)
+		}
+		for _, day := range []int{5, 12} {
+			start := time.Date(2024, 3, day, 9, 0, 0, 0, time.Local)
+			if _, err := db.CreateWorkSessionWithTimes(ctx, client.ID, start, start.Add(2*time.Hour), nil, client.HourlyRate, false); err != nil {
+				t.Fatalf("Failed to create session: %v", err)
+			}
+		}
+		customInvoices := func() []*models.Invoice {
+			t.Helper()
+			invoices, err := db.GetInvoicesByClient(ctx, client.Name)
+			if err != nil {
+				t.Fatalf("Failed to list invoices: %v", err)
+			}
+			var custom []*models.Invoice
+			for _, invoice := range invoices {
+				if invoice.PeriodType == service.CustomPeriod && invoice.Status != models.InvoiceStatusVoid {
+					custom = append(custom, invoice)
+				}
+			}
+			return custom
+		}
+
+		if err := run("invoices", "generate", "--from", "2024-03-04", "--to", "2024-03-21", "-c", client.Name); err != nil {
+			t.Fatalf("Failed to generate a custom range invoice: %v", err)
+		}
+		invoices := customInvoices()
+		if len(invoices) != 1 || !invoices[0].TotalAmount.IsPositive() {
+			t.Fatalf("Expected one custom range invoice for both sessions, got %v", invoices)
+		}
+		invoice := invoices[0]
+
+		if err := run("invoices", "generate", "--from", "2024-03-04", "--to", "2024-03-21", "-c", client.Name); err != nil {
+			t.Errorf("Failed to generate the custom range again: %v", err)
+		}
+		if invoices := customInvoices(); len(invoices) != 1 || invoices[0].ID != invoice.ID {
+			t.Errorf("Expected generating the same range again to keep its invoice, got %v", invoices)
+		}
+		if err := run("invoices", "regenerate", "--from", "2024-03-04", "--to", "2024-03-21", "-c", client.Name); err != nil {
+			t.Errorf("Failed to regenerate the custom range: %v", err)
+		}
+		invoices = customInvoices()
+		if len(invoices) != 1 || !invoices[0].TotalAmount.Equal(invoice.TotalAmount) {
+			t.Fatalf("Expected regenerating the range to replace its invoice, got %v", invoices)
+		}
+		invoice = invoices[0]
+
+		err = run("invoices", "regenerate", "--from", "2024-03-04", "--to", "2024-03-20", "-c", client.Name)
+		if err == nil || !strings.Contains(err.Error(), invoice.InvoiceNumber) {
+			t.Errorf("Expected regenerating a range a day out to be refused over %s, got %v", invoice.InvoiceNumber, err)
+		}
+		if invoices := customInvoices(); len(invoices) != 1 || invoices[0].ID != invoice.ID {
+			t.Errorf("Expected the refused regeneration to leave the invoice alone, got %v", invoices)
+		}
+
+		if err := run("invoices", "void", invoice.InvoiceNumber, "-r", "Wrong range"); err != nil {
+			t.Fatalf("Failed to void invoice: %v", err)
+		}
+		if err := run("invoices", "regenerate", "--from", "2024-03-04", "--to", "2024-03-20", "-c", client.Name); err != nil {
+			t.Errorf("Expected a void invoice not to block regenerating an overlapping range, got %v", err)
+		}
+	})
+
+	t.Run("Work Invoices Draft And Issue", func(t *testing.T) {
+		// One root command throughout, so the lock held by a command that fails is released by the next
+		cmd := newRootCmd(timesheetService)
+		run := func(args ...string) error {
+			cmd.SetArgs(args)
+			var err error
+			captureOutput(func() { err = cmd.ExecuteContext(ctx) })
+			return err
+		}
+		client, err := timesheetService.GetClientByName(ctx, "test-client")
+		if err != nil {
+			t.Fatalf("Failed to get client: %v", err)
+		}
+		start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.Local)
+		invoice, err := db.CreateInvoice(ctx, client.ID, "INV-test-client-draft", "month", start, start.AddDate(0, 1, -1),
+			decimal.NewFromInt(100), decimal.NewFromInt(10), decimal.NewFromInt(110), models.InvoiceGroupBySession, nil, nil, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("Failed to create invoice: %v", err)
+		}
+		status := func() string {
+			t.Helper()
+			invoice, err := db.GetInvoiceByID(ctx, invoice.ID)
+			if err != nil {
+				t.Fatalf("Failed to get invoice: %v", err)
+			}
+			return invoice.Status
+		}
+
+		if err := run("invoices", "issue", invoice.InvoiceNumber); err == nil {
+			t.Errorf("Expected an error issuing an invoice that isn't a draft")
+		}
+		if err := run("invoices", "draft", invoice.InvoiceNumber); err != nil || status() != models.InvoiceStatusDraft {
+			t.Errorf("Expected the invoice to be a draft, got %q (err %v)", status(), err)
+		}
+		if err := run("invoices", "pay", invoice.ID, "-a", "110", "-d", "2025-08-05"); err == nil {
+			t.Errorf("Expected an error paying a draft invoice")
+		}
+		if err := run("invoices", "credit", invoice.ID, "-a", "10", "-r", "Discount"); err == nil {
+			t.Errorf("Expected an error crediting a draft invoice")
+		}
+		if status() != models.InvoiceStatusDraft {
+			t.Errorf("Expected the invoice to still be a draft, got %q", status())
+		}
+		list, err := timesheetService.ListInvoices(ctx, -1, client.Name, true)
+		if err != nil {
+			t.Fatalf("Failed to list unpaid invoices: %v", err)
+		}
+		for _, listed := range list.Invoices {
+			if listed.Invoice.ID == invoice.ID {
+				t.Errorf("Expected a draft invoice to be left out of unpaid invoices")
+			}
+		}
+		if err := run("invoices", "issue", invoice.InvoiceNumber); err != nil || status() != models.InvoiceStatusIssued {
+			t.Errorf("Expected the invoice to be issued, got %q (err %v)", status(), err)
+		}
+
+		if err := run("invoices", "pay", invoice.ID, "-a", "50", "-d", "2025-08-05"); err != nil {
+			t.Fatalf("Failed to pay invoice: %v", err)
+		}
+		if err := run("invoices", "draft", invoice.InvoiceNumber); err == nil {
+			t.Errorf("Expected an error making an invoice with payments a draft")
+		}
+	})
 }
 
 // Helper function to capture stdout output
@@ -303,26 +732,26 @@ func captureOutput(f func()) string {
 
 // Helper function to run database migrations using sqlite3 command
 func runMigrationsWithSQLite(cfg *config.Config) error {
-	// Read migration files
-	migrationFiles := []string{
-		"001_initial_schema.sql",
-		"002_add_rates.sql",
-		"003_add_billing_details.sql",
-		"004_add_dir.sql",
-		"005_add_full_work_summary.sql",
-		"006_add_outside_git.sql",
+	// Read migration files in order
+	migrationFiles, err := filepath.Glob(filepath.Join("../../migrations", "*.sql"))
+	if err != nil {
+		return fmt.Errorf("failed to list migration files: %w", err)
 	}
+	sort.Strings(migrationFiles)
+
+	// Migrations are applied through the driver rather than the sqlite3 CLI, so the test runs wherever Go does
+	conn, err := sql.Open(cfg.DatabaseDriver, cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close()
 
 	for _, file := range migrationFiles {
-		content, err := os.ReadFile(filepath.Join("../../migrations", file))
+		content, err := os.ReadFile(file)
 		if err != nil {
 			return fmt.Errorf("failed to read migration file %s: %w", file, err)
 		}
-
-		// Execute migration using sqlite3 command
-		cmd := exec.Command("sqlite3", cfg.DatabaseURL)
-		cmd.Stdin = strings.NewReader(string(content))
-		if err := cmd.Run(); err != nil {
+		if _, err := conn.Exec(string(content)); err != nil {
 			return fmt.Errorf("failed to execute migration %s: %w", file, err)
 		}
 	}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// newInvoiceReviewer returns a reviewer that shows each new invoice and asks whether to create it, skip the
+// client or stop, with session descriptions corrected in between until it's accepted
+func newInvoiceReviewer(timesheetService *service.TimesheetService) service.InvoiceReviewer {
+	reader := bufio.NewReader(os.Stdin)
+	prompt := func(question string) (string, error) {
+		fmt.Print(question)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(response), nil
+	}
+
+	return func(ctx context.Context, preview *service.InvoicePreview) (service.InvoiceReview, error) {
+		for {
+			timesheetService.DisplayInvoicePreview(preview)
+
+			response, err := prompt("\n[a]ccept, [s]kip client, [e]dit description, a[b]ort (a/s/e/b): ")
+			if err != nil {
+				return service.InvoiceReviewAbort, err
+			}
+			switch strings.ToLower(response) {
+			case "a", "accept":
+				return service.InvoiceReviewAccept, nil
+			case "s", "skip":
+				return service.InvoiceReviewSkip, nil
+			case "b", "abort":
+				return service.InvoiceReviewAbort, nil
+			case "e", "edit":
+				if err := editPreviewDescription(ctx, timesheetService, preview, prompt); err != nil {
+					return service.InvoiceReviewAbort, err
+				}
+			default:
+				fmt.Println("Enter a, s, e or b")
+			}
+		}
+	}
+}
+
+// editPreviewDescription asks which of the previewed sessions to describe differently and saves its new
+// description. Leaving either answer empty changes nothing.
+func editPreviewDescription(ctx context.Context, timesheetService *service.TimesheetService, preview *service.InvoicePreview, prompt func(string) (string, error)) error {
+	if len(preview.Sessions) == 0 {
+		fmt.Println("There are no sessions on this invoice to describe")
+		return nil
+	}
+
+	fmt.Println()
+	for i, session := range preview.Sessions {
+		fmt.Printf("  %d. %s  %s\n", i+1, session.StartTime.Format("2006-01-02 15:04"), utils.FromPtr(session.Description))
+	}
+	response, err := prompt(fmt.Sprintf("Session to edit [1-%d]: ", len(preview.Sessions)))
+	if err != nil || response == "" {
+		return err
+	}
+	choice, err := strconv.Atoi(response)
+	if err != nil {
+		fmt.Println("Enter the session's number")
+		return nil
+	}
+
+	description, err := prompt("New description: ")
+	if err != nil || description == "" {
+		return err
+	}
+	if err := timesheetService.EditInvoicePreviewDescription(ctx, preview, choice-1, description); err != nil {
+		// A bad choice is asked again rather than ending the review
+		fmt.Printf("Error: %v\n", err)
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -19,29 +22,78 @@ func newInvoicesCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	cmd.AddCommand(newInvoicesGenerateCmd(timesheetService))
 	cmd.AddCommand(newInvoicesRegenerateCmd(timesheetService))
 	cmd.AddCommand(newInvoicesListCmd(timesheetService))
+	cmd.AddCommand(newInvoicesShowCmd(timesheetService))
 	cmd.AddCommand(newInvoicesPayCmd(timesheetService))
+	cmd.AddCommand(newInvoicesPayBatchCmd(timesheetService))
+	cmd.AddCommand(newInvoicesCreditCmd(timesheetService))
+	cmd.AddCommand(newInvoicesDraftCmd(timesheetService))
+	cmd.AddCommand(newInvoicesIssueCmd(timesheetService))
+	cmd.AddCommand(newInvoicesVoidCmd(timesheetService))
+	cmd.AddCommand(newInvoicesWriteOffCmd(timesheetService))
+	cmd.AddCommand(newInvoicesPublishCmd(timesheetService))
+	cmd.AddCommand(newInvoicesVerifyCmd(timesheetService))
 	return cmd
 }
 
 func newInvoicesGenerateCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	var period string
 	var date string
+	var fromDate, toDate string
 	var client string
+	var groupBy string
+	var notes string
+	var poNumber, projectCode string
+	var formats []string
+	var withAppendix bool
+	var review bool
 
 	cmd := &cobra.Command{
 		Use:   "generate",
 		Short: "Generate PDF invoices for clients",
-		Long:  "Generate PDF invoices for each client with billable hours > 0 in the specified period",
+		Long:  "Generate PDF invoices for each client with billable hours > 0 in the specified period, or in a custom range given with --from and --to. Use --format pdf,html to also write an HTML copy alongside the PDF, for pasting into an email body or hosting. Use --with-appendix to add each session's full work summary in an appendix after the session details, referring to the numbered lines they were billed on. Use --review to preview each new invoice before it's created and numbered, then accept it, skip the client, correct a session's description or abort.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			return timesheetService.GenerateInvoices(ctx, period, date, client)
+			period, err := invoicePeriod(cmd, period, fromDate, toDate)
+			if err != nil {
+				return err
+			}
+			var reviewer service.InvoiceReviewer
+			if review {
+				if !stdinIsTerminal() {
+					return fmt.Errorf("--review needs to be run interactively, to answer each invoice's prompt")
+				}
+				reviewer = newInvoiceReviewer(timesheetService)
+			}
+			run, err := timesheetService.GenerateInvoices(ctx, period, date, fromDate, toDate, client, groupBy, notes, service.InvoiceReferences{PoNumber: poNumber, ProjectCode: projectCode}, formats, withAppendix, reviewer)
+			aborted := errors.Is(err, service.ErrInvoiceReviewAborted)
+			// Invoices written before an error are still shown
+			if run != nil && (err == nil || aborted || len(run.Deleted)+len(run.Invoices) > 0) {
+				if review {
+					fmt.Println()
+				}
+				timesheetService.Presenter(cmd.OutOrStdout()).InvoiceRun(run)
+			}
+			if aborted {
+				fmt.Println("Aborted, the remaining clients weren't invoiced")
+				return nil
+			}
+			return err
 		},
+		Annotations: mutating(),
 	}
 
-	cmd.Flags().StringVarP(&period, "period", "p", "week", "Period type: day, week, fortnight, month")
+	cmd.Flags().StringVarP(&period, "period", "p", "week", "Period type: day, week, fortnight, month, quarter, year")
 	cmd.Flags().StringVarP(&date, "date", "d", "", "Date in the period (YYYY-MM-DD)")
+	cmd.Flags().StringVarP(&fromDate, "from", "f", "", "Invoice a custom range from this date (YYYY-MM-DD)")
+	cmd.Flags().StringVarP(&toDate, "to", "t", "", "Invoice a custom range to this date (YYYY-MM-DD)")
 	cmd.Flags().StringVarP(&client, "client", "c", "", "Generate invoice for specific client only")
-	cmd.MarkFlagRequired("date")
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "List sessions one per line or summed per group: session, day or description (default session, or the invoice's existing grouping)")
+	cmd.Flags().StringVar(&notes, "notes", "", "Notes to print on the invoice instead of the client's invoice notes")
+	cmd.Flags().StringVar(&poNumber, "po-number", "", "Purchase order number to print on the invoice (default the client's)")
+	cmd.Flags().StringVar(&projectCode, "project-code", "", "Project code to print on the invoice (default the client's)")
+	cmd.Flags().StringSliceVar(&formats, "format", []string{service.InvoiceFormatPDF}, "Invoice files to write, comma separated: "+strings.Join(service.InvoiceFormats, ", "))
+	cmd.Flags().BoolVar(&withAppendix, "with-appendix", false, "Append each session's full work summary after the session details")
+	cmd.Flags().BoolVar(&review, "review", false, "Preview each new invoice and accept, skip or correct it before it's created")
 
 	return cmd
 }
@@ -49,26 +101,61 @@ func newInvoicesGenerateCmd(timesheetService *service.TimesheetService) *cobra.C
 func newInvoicesRegenerateCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	var period string
 	var date string
+	var fromDate, toDate string
 	var client string
+	var groupBy string
+	var notes string
+	var poNumber, projectCode string
+	var formats []string
+	var withAppendix bool
 
 	cmd := &cobra.Command{
 		Use:   "regenerate",
 		Short: "Regenerate invoices for a period (clears existing invoices for that period)",
-		Long:  "Regenerate invoices for each client with billable hours > 0 in the specified period. This will clear existing invoices for the period and regenerate them.",
+		Long:  "Regenerate invoices for each client with billable hours > 0 in the specified period or custom --from/--to range. This will clear existing invoices for the period and regenerate them.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			return timesheetService.RegenerateInvoices(ctx, period, date, client)
+			period, err := invoicePeriod(cmd, period, fromDate, toDate)
+			if err != nil {
+				return err
+			}
+			run, err := timesheetService.RegenerateInvoices(ctx, period, date, fromDate, toDate, client, groupBy, notes, service.InvoiceReferences{PoNumber: poNumber, ProjectCode: projectCode}, formats, withAppendix)
+			// Invoices written before an error are still shown
+			if run != nil && (err == nil || len(run.Deleted)+len(run.Invoices) > 0) {
+				timesheetService.Presenter(cmd.OutOrStdout()).InvoiceRun(run)
+			}
+			return err
 		},
+		Annotations: mutating(),
 	}
 
-	cmd.Flags().StringVarP(&period, "period", "p", "week", "Period type: day, week, fortnight, month")
+	cmd.Flags().StringVarP(&period, "period", "p", "week", "Period type: day, week, fortnight, month, quarter, year")
 	cmd.Flags().StringVarP(&date, "date", "d", "", "Date in the period (YYYY-MM-DD)")
+	cmd.Flags().StringVarP(&fromDate, "from", "f", "", "Regenerate invoices for a custom range from this date (YYYY-MM-DD)")
+	cmd.Flags().StringVarP(&toDate, "to", "t", "", "Regenerate invoices for a custom range to this date (YYYY-MM-DD)")
 	cmd.Flags().StringVarP(&client, "client", "c", "", "Regenerate invoice for specific client only")
-	cmd.MarkFlagRequired("date")
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "List sessions one per line or summed per group: session, day or description (default the grouping the invoice had)")
+	cmd.Flags().StringVar(&notes, "notes", "", "Notes to print on the invoice (default the notes the invoice had)")
+	cmd.Flags().StringVar(&poNumber, "po-number", "", "Purchase order number to print on the invoice (default the one the invoice had)")
+	cmd.Flags().StringVar(&projectCode, "project-code", "", "Project code to print on the invoice (default the one the invoice had)")
+	cmd.Flags().StringSliceVar(&formats, "format", []string{service.InvoiceFormatPDF}, "Invoice files to write, comma separated: "+strings.Join(service.InvoiceFormats, ", "))
+	cmd.Flags().BoolVar(&withAppendix, "with-appendix", false, "Append each session's full work summary after the session details")
 
 	return cmd
 }
 
+// invoicePeriod returns the period an invoice command covers, which is a custom range when --from or --to
+// is given instead of a period
+func invoicePeriod(cmd *cobra.Command, period, fromDate, toDate string) (string, error) {
+	if fromDate == "" && toDate == "" {
+		return period, nil
+	}
+	if cmd.Flags().Changed("period") || cmd.Flags().Changed("date") {
+		return "", fmt.Errorf("use either --period and --date or --from and --to, not both")
+	}
+	return service.CustomPeriod, nil
+}
+
 func newInvoicesListCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	var limit int32
 	var client string
@@ -80,13 +167,33 @@ func newInvoicesListCmd(timesheetService *service.TimesheetService) *cobra.Comma
 		Long:  "List invoices showing client, period, dates, amounts and payment status",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
-			return timesheetService.ListInvoices(ctx, limit, client, unpaidOnly)
+			list, err := timesheetService.ListInvoices(ctx, limit, client, unpaidOnly)
+			if err != nil {
+				return err
+			}
+			timesheetService.Presenter(cmd.OutOrStdout()).Invoices(list)
+			return nil
 		},
 	}
 
 	cmd.Flags().Int32VarP(&limit, "limit", "l", 20, "Number of invoices to show")
 	cmd.Flags().StringVarP(&client, "client", "c", "", "Filter by specific client")
-	cmd.Flags().BoolVarP(&unpaidOnly, "unpaid", "u", false, "Show only unpaid invoices")
+	cmd.Flags().BoolVarP(&unpaidOnly, "unpaid", "u", false, "Show only issued invoices with something left to pay")
+
+	return cmd
+}
+
+func newInvoicesShowCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <invoice-id>",
+		Short: "Show an invoice with its payment history",
+		Long:  "Show an invoice, given by ID or invoice number, with its amounts, status, credit notes and payment history.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.ShowInvoice(ctx, args[0])
+		},
+	}
 
 	return cmd
 }
@@ -98,13 +205,14 @@ func newInvoicesPayCmd(timesheetService *service.TimesheetService) *cobra.Comman
 	cmd := &cobra.Command{
 		Use:   "pay",
 		Short: "Pay an invoice",
-		Long:  "Pay an invoice with the specified invoice number and amount, amount defaults to the total amount of the invoice",
+		Long:  "Pay an invoice with the specified invoice number and amount, amount defaults to the remaining balance less any early payment discount the client is due. Paying the balance less the discount before the discount deadline records the discount and settles the invoice.",
 		Args:  cobra.ExactArgs(1),
 	}
 
-	cmd.Flags().Float64VarP(&amount, "amount", "a", 0.0, "Amount being paid, defaulting to the total amount of the invoice")
+	cmd.Flags().Float64VarP(&amount, "amount", "a", 0.0, "Amount being paid, defaulting to the remaining balance less any early payment discount")
 	cmd.Flags().StringVarP(&dateStr, "date", "d", "", "Date the payment was made (YYYY-MM-DD)")
 
+	cmd.Annotations = mutating()
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		id := args[0]
@@ -117,3 +225,181 @@ func newInvoicesPayCmd(timesheetService *service.TimesheetService) *cobra.Comman
 
 	return cmd
 }
+
+func newInvoicesPayBatchCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "pay-batch <payments.csv>",
+		Short: "Pay several invoices from a CSV file",
+		Long: `Record payments from a CSV file with one payment per row: invoice number, amount and date (YYYY-MM-DD).
+An empty amount pays the remaining balance, less any early payment discount due, and an empty date means today. A header row and lines starting with # are ignored.
+Every row is checked first, and the payments are recorded in a single transaction so nothing is recorded if any row is invalid.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.PayInvoicesFromFile(ctx, args[0], dryRun)
+		},
+		Annotations: mutating(),
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Check the file and show the payments without recording them")
+
+	return cmd
+}
+
+func newInvoicesPublishCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "publish <invoice-id>",
+		Short: "Publish an invoice's work summary to the client's work log",
+		Long: `Append the sessions on an invoice, given by ID or invoice number, to the client's Notion database or Confluence page.
+New invoices are published automatically when the client has a work log, set with 'work clients update <client> --work-log'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.PublishWorkLog(ctx, args[0])
+		},
+		Annotations: mutating(),
+	}
+
+	return cmd
+}
+
+func newInvoicesVerifyCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check invoice totals against their sessions and expenses",
+		Long: `Recalculate every invoice's totals from the sessions and expenses linked to it, reporting mismatches and
+sessions or expenses linked to invoices that no longer exist. With --fix, orphans are relinked to the client's invoice
+covering their date, or unlinked so they can be invoiced again, and stored totals are recalculated.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.VerifyInvoices(ctx, fix)
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Relink orphaned sessions and expenses and recalculate mismatched totals")
+	return cmd
+}
+
+func newInvoicesCreditCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var amountStr string
+	var reason string
+	var dateStr string
+
+	cmd := &cobra.Command{
+		Use:   "credit <invoice-id>",
+		Short: "Issue a credit note against an invoice",
+		Long: `Issue a credit note against an over-invoiced invoice, given by ID or invoice number, and write it to a PDF.
+The amount includes GST and reduces what the client owes. If they've already paid, the difference is shown as a refund.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			amount, err := decimal.NewFromString(amountStr)
+			if err != nil {
+				return fmt.Errorf("invalid amount %q", amountStr)
+			}
+			date, err := time.Parse("2006-01-02", dateStr)
+			if err != nil && dateStr != "" {
+				return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+			}
+			return timesheetService.CreditInvoice(ctx, args[0], amount, reason, date)
+		},
+		Annotations: mutating(),
+	}
+
+	cmd.Flags().StringVarP(&amountStr, "amount", "a", "", "Amount to credit, including GST")
+	cmd.Flags().StringVarP(&reason, "reason", "r", "", "Why the invoice is being credited, shown on the credit note")
+	cmd.Flags().StringVarP(&dateStr, "date", "d", "", "Date of the credit note (YYYY-MM-DD), defaults to today")
+	cmd.MarkFlagRequired("amount")
+	cmd.MarkFlagRequired("reason")
+
+	return cmd
+}
+
+func newInvoicesDraftCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "draft <invoice-id>",
+		Short: "Hold back an invoice that hasn't been sent",
+		Long: `Mark an invoice, given by ID or invoice number, as a draft until it's sent to the client. Drafts are never
+overdue and are left out of aging and GST reporting. Issue it with 'work invoices issue' once it's sent.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.DraftInvoice(ctx, args[0])
+		},
+		Annotations: mutating(),
+	}
+
+	return cmd
+}
+
+func newInvoicesIssueCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "issue <invoice-id>",
+		Short: "Issue a draft invoice once it's sent",
+		Long:  "Mark a draft invoice, given by ID or invoice number, as issued once it's been sent to the client.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.IssueInvoice(ctx, args[0])
+		},
+		Annotations: mutating(),
+	}
+
+	return cmd
+}
+
+func newInvoicesVoidCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:   "void <invoice-id>",
+		Short: "Void an invoice issued in error",
+		Long: `Void an invoice, given by ID or invoice number, that was issued in error. A void invoice owes nothing, is
+reversed out of GST in the quarter it's voided, and its sessions and expenses are taken off it so they can be
+invoiced again. Invoices with payments can't be voided, credit them instead.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.VoidInvoice(ctx, args[0], reason)
+		},
+		Annotations: mutating(),
+	}
+
+	cmd.Flags().StringVarP(&reason, "reason", "r", "", "Why the invoice is being voided")
+
+	return cmd
+}
+
+func newInvoicesWriteOffCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var reason string
+	var dateStr string
+
+	cmd := &cobra.Command{
+		Use:   "write-off <invoice-id>",
+		Short: "Write off an invoice that will never be paid",
+		Long: `Write off the balance of an invoice, given by ID or invoice number, that will never be paid. The invoice
+no longer shows as unpaid, and on an accrual basis the GST on the amount written off comes off GST on sales in the
+quarter it's written off.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			date, err := time.Parse("2006-01-02", dateStr)
+			if err != nil && dateStr != "" {
+				return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+			}
+			return timesheetService.WriteOffInvoice(ctx, args[0], reason, date)
+		},
+		Annotations: mutating(),
+	}
+
+	cmd.Flags().StringVarP(&reason, "reason", "r", "", "Why the invoice won't be paid")
+	cmd.Flags().StringVarP(&dateStr, "date", "d", "", "Date it's written off (YYYY-MM-DD), defaults to today")
+	cmd.MarkFlagRequired("reason")
+
+	return cmd
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newLeaveCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "leave",
+		Short: "Record annual leave, sick leave and public holidays",
+		Long: `Leave is days off, so 'work week' names them rather than flagging them as untracked and the monthly
+report counts them.`,
+	}
+
+	cmd.AddCommand(newLeaveAddCmd(timesheetService))
+	cmd.AddCommand(newLeaveListCmd(timesheetService))
+	cmd.AddCommand(newLeaveDeleteCmd(timesheetService))
+
+	return cmd
+}
+
+func newLeaveAddCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var fromDate, toDate, leaveType, note string
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Record days off",
+		Long:  "Record days off from --from to --to inclusive, or just --from for a single day.",
+		Example: `  work leave add --from 2025-12-22 --to 2026-01-02 --type annual
+  work leave add --from 2025-12-25 --type public --note "Christmas Day"`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVarP(&fromDate, "from", "f", "", "First day of leave (YYYY-MM-DD)")
+	cmd.Flags().StringVarP(&toDate, "to", "t", "", "Last day of leave (YYYY-MM-DD), defaults to --from")
+	cmd.Flags().StringVar(&leaveType, "type", "", "Kind of leave: "+strings.Join(service.LeaveTypes, ", "))
+	cmd.Flags().StringVarP(&note, "note", "n", "", "Optional note, e.g. the holiday's name")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("type")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := service.ValidateLeaveType(leaveType); err != nil {
+			return err
+		}
+		start, err := time.ParseInLocation("2006-01-02", fromDate, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid from date format, use YYYY-MM-DD: %w", err)
+		}
+		end := start
+		if toDate != "" {
+			if end, err = time.ParseInLocation("2006-01-02", toDate, time.Local); err != nil {
+				return fmt.Errorf("invalid to date format, use YYYY-MM-DD: %w", err)
+			}
+		}
+		var notePtr *string
+		if note != "" {
+			notePtr = &note
+		}
+
+		leave, err := timesheetService.AddLeave(cmd.Context(), leaveType, start, end, notePtr)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Recorded %s leave from %s to %s: %s\n", leave.Type, leave.StartDate.Format("2006-01-02"), leave.EndDate.Format("2006-01-02"), leave.ID)
+		return nil
+	}
+
+	return cmd
+}
+
+func newLeaveListCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var year int
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recorded leave and the working days taken",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.DisplayLeave(cmd.Context(), year)
+		},
+	}
+
+	cmd.Flags().IntVarP(&year, "year", "y", 0, "Only show leave in this year")
+
+	return cmd
+}
+
+func newLeaveDeleteCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <leave-id>",
+		Short: "Delete recorded leave",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			leave, err := timesheetService.DeleteLeave(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Deleted %s leave from %s to %s\n", leave.Type, leave.StartDate.Format("2006-01-02"), leave.EndDate.Format("2006-01-02"))
+			return nil
+		},
+		Annotations: mutating(),
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/lock"
+)
+
+// mutatingAnnotation marks commands that change the database, which run one at a time so they can't interleave.
+// Commands that can run for minutes, such as generating descriptions or watching for receipts, aren't marked so
+// they don't hold up starting and stopping sessions.
+const mutatingAnnotation = "work.mutating"
+
+// lockWait is how long a command waits for another to finish before giving up, long enough for the quick
+// commands that usually overlap, such as stopping one session and starting the next
+const lockWait = 3 * time.Second
+
+// mutating marks a command as changing the database
+func mutating() map[string]string {
+	return map[string]string{mutatingAnnotation: "true"}
+}
+
+// lockIfMutating takes the database's lock when cmd changes it, returning nil when it doesn't
+func lockIfMutating(cmd *cobra.Command, cfg *config.Config) (*lock.Lock, error) {
+	if cmd.Annotations[mutatingAnnotation] == "" {
+		return nil, nil
+	}
+	return lock.Acquire(lockPath(cfg), lockWait)
+}
+
+// lockPath is the lock file for the active database, so commands against separate databases don't wait on each
+// other. It's kept in the temp directory as remote databases have nowhere local to put it.
+func lockPath(cfg *config.Config) string {
+	sum := sha256.Sum256([]byte(cfg.DatabaseURL))
+	return filepath.Join(os.TempDir(), fmt.Sprintf("work-%x.lock", sum[:8]))
+}
+
+// descriptionsLockPath is the lock file keeping description backfills against the active database to one at a
+// time, separate from the database's lock so a backfill doesn't hold up other commands
+func descriptionsLockPath(cfg *config.Config) string {
+	return strings.TrimSuffix(lockPath(cfg), ".lock") + "-descriptions.lock"
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/jesses-code-adventures/work/internal/config"
 	"github.com/jesses-code-adventures/work/internal/database"
@@ -22,10 +24,17 @@ var BillingABN string
 var BillingACN string
 var BillingCompanyName string
 var GSTRegistered string
+var BillingCurrency string
+var BillingLocale string
 
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		// Usage mistakes exit with 2, like most CLIs, so scripts can tell them apart from failures
+		var unknownCommand *unknownCommandError
+		if errors.As(err, &unknownCommand) {
+			os.Exit(2)
+		}
 		os.Exit(1)
 	}
 }
@@ -59,11 +68,15 @@ func main() {
 // }
 
 func run() error {
-	cfg, err := config.Load(DBConn, DBDriver, GitPrompt, DevMode, BillingBank, BillingAccountName, BillingAccountNumber, BillingBSB, BillingABN, BillingACN, BillingCompanyName, GSTRegistered)
+	cfg, err := config.Load(DBConn, DBDriver, GitPrompt, DevMode, BillingBank, BillingAccountName, BillingAccountNumber, BillingBSB, BillingABN, BillingACN, BillingCompanyName, GSTRegistered, BillingCurrency, BillingLocale)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if path := dbFlag(os.Args[1:]); path != "" {
+		cfg.UseDatabase(path, "--db flag")
+	}
+
 	db, err := database.NewDB(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
@@ -75,3 +88,20 @@ func run() error {
 	rootCmd := newRootCmd(timesheetService)
 	return rootCmd.ExecuteContext(context.Background())
 }
+
+// dbFlag returns the value of the root --db flag. The database is opened before cobra parses flags,
+// so the flag is read from the arguments directly.
+func dbFlag(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--db="); ok {
+			return value
+		}
+		if arg == "--db" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
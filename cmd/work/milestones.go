@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newMilestonesCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "milestones",
+		Short: "Track and bill fixed-price milestones",
+		Long: `Milestones are fixed-price pieces of work agreed with a client. Completing one adds it to the client's next
+invoice as a line of its own, independent of the hours worked, and 'work clients show' shows the progress
+through them.`,
+	}
+
+	cmd.AddCommand(newMilestonesAddCmd(timesheetService))
+	cmd.AddCommand(newMilestonesListCmd(timesheetService))
+	cmd.AddCommand(newMilestonesCompleteCmd(timesheetService))
+	cmd.AddCommand(newMilestonesDeleteCmd(timesheetService))
+
+	return cmd
+}
+
+func newMilestonesAddCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client, due string
+	var amount float64
+
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a fixed-price milestone for a client",
+		Example: `  work milestones add "Design sign-off" -c acme --amount 4000 --due 2025-11-30
+  work milestones add "Launch" -c acme -a 6000`,
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Client name")
+	cmd.Flags().Float64VarP(&amount, "amount", "a", 0, "Fixed price of the milestone, excluding GST")
+	cmd.Flags().StringVar(&due, "due", "", "When it's due (YYYY-MM-DD)")
+	cmd.MarkFlagRequired("client")
+	cmd.MarkFlagRequired("amount")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var dueDate *time.Time
+		if due != "" {
+			date, err := time.ParseInLocation("2006-01-02", due, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid due date format, use YYYY-MM-DD: %w", err)
+			}
+			dueDate = &date
+		}
+
+		milestone, err := timesheetService.AddMilestone(cmd.Context(), client, args[0], decimal.NewFromFloat(amount), dueDate)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Added milestone '%s' for %s: %s\n", milestone.Name, milestone.ClientName, milestone.ID)
+		return nil
+	}
+
+	return cmd
+}
+
+func newMilestonesListCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List milestones and whether they're pending, complete or invoiced",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.DisplayMilestones(cmd.Context(), client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Only show this client's milestones")
+
+	return cmd
+}
+
+func newMilestonesCompleteCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var date string
+
+	cmd := &cobra.Command{
+		Use:   "complete <milestone-id>",
+		Short: "Mark a milestone complete, adding it to the client's next invoice",
+		Long: `Mark a milestone complete, today or on --date. It's billed for its amount on the next invoice generated for
+the client whose period ends on or after the day it was completed.`,
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&date, "date", "d", "", "Day it was completed (YYYY-MM-DD), defaults to today")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		completed := time.Now()
+		if date != "" {
+			var err error
+			if completed, err = time.ParseInLocation("2006-01-02", date, time.Local); err != nil {
+				return fmt.Errorf("invalid date format, use YYYY-MM-DD: %w", err)
+			}
+		}
+
+		milestone, err := timesheetService.CompleteMilestone(cmd.Context(), args[0], completed)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Completed milestone '%s' for %s on %s, it will be billed on their next invoice\n",
+			milestone.Name, milestone.ClientName, milestone.CompletedDate.Format("2006-01-02"))
+		return nil
+	}
+
+	return cmd
+}
+
+func newMilestonesDeleteCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <milestone-id>",
+		Short: "Delete a milestone that hasn't been invoiced",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			milestone, err := timesheetService.DeleteMilestone(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Deleted milestone '%s' for %s\n", milestone.Name, milestone.ClientName)
+			return nil
+		},
+		Annotations: mutating(),
+	}
+}
@@ -2,42 +2,103 @@ package main
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/jesses-code-adventures/work/internal/models"
 	"github.com/jesses-code-adventures/work/internal/service"
 	"github.com/spf13/cobra"
 )
 
 func newNoteCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var sessionID string
+	var list bool
+	var edit, remove int
+	var force bool
+
 	cmd := &cobra.Command{
-		Use:   "note <text>",
-		Short: "Add a note to the active session",
-		Long:  "Add a note to the currently active work session. Notes are stored as bullet points and included in invoices and exports.",
-		Args:  cobra.MinimumNArgs(1),
+		Use:   "note [text]",
+		Short: "Add, list, edit or delete notes on a session",
+		Long: `Add a note to the currently active work session, or another session with --session, given by its ID or the start
+of it. Without an active session, the session is picked from your recent ones. Notes are stored as
+timestamped bullet points and included in invoices and exports, with the time of each shown on invoices when
+INVOICE_NOTE_TIMES is true. List a session's notes with --list to see their numbers for --edit and --delete.`,
+		Example: `  work note "Call with the client about the launch"
+  work note --session <session-id> --list
+  work note --session <session-id> --edit 2 "Call with the client about the launch date"
+  work note --session <session-id> --delete 1`,
 	}
 
+	cmd.Flags().StringVarP(&sessionID, "session", "s", "", "Session to change the notes of (default the active session)")
+	cmd.Flags().BoolVarP(&list, "list", "l", false, "List the session's notes by number")
+	cmd.Flags().IntVar(&edit, "edit", 0, "Replace the text of the note with this number")
+	cmd.Flags().IntVar(&remove, "delete", 0, "Delete the note with this number")
+	cmd.Flags().BoolVar(&force, "force", false, "Change the notes even if the session is on an invoice")
+	cmd.MarkFlagsMutuallyExclusive("list", "edit", "delete")
+
+	cmd.Annotations = mutating()
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		note := args[0]
+		text := strings.Join(args, " ")
 
-		activeSession, err := timesheetService.GetActiveSession(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get active session: %w", err)
+		var session *models.WorkSession
+		var err error
+		if sessionID == "" {
+			session, err = timesheetService.GetActiveSession(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get active session: %w", err)
+			}
+			// Without an active session, one is picked from your recent sessions
+			if session == nil && !stdinIsTerminal() {
+				return fmt.Errorf("no active session found. Start a session first with 'work start <client>', or give one with --session")
+			}
 		}
-
-		if activeSession == nil {
-			return fmt.Errorf("no active session found. Start a session first with 'work start <client>'")
+		if session == nil {
+			if sessionID, err = sessionIDArg(cmd, timesheetService, sessionID); err != nil {
+				return err
+			}
+			if session, err = timesheetService.GetSessionByID(ctx, sessionID); err != nil {
+				return fmt.Errorf("session %s not found: %w", sessionID, err)
+			}
 		}
 
-		updatedSession, err := timesheetService.AddSessionNote(ctx, activeSession.ID, note)
-		if err != nil {
-			return fmt.Errorf("failed to add note to session: %w", err)
+		clientName := session.ClientName
+		switch {
+		case list:
+			if len(args) > 0 {
+				return fmt.Errorf("--list doesn't take note text")
+			}
+			fmt.Printf("Notes on the %s session of %s:\n", session.ClientName, session.StartTime.Format("2006-01-02 15:04"))
+			timesheetService.DisplaySessionNotes(session)
+			return nil
+		case cmd.Flags().Changed("edit"):
+			session, err = timesheetService.EditSessionNote(ctx, session.ID, edit, text, force)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Updated note %d on session for %s\n", edit, clientName)
+		case cmd.Flags().Changed("delete"):
+			if len(args) > 0 {
+				return fmt.Errorf("--delete doesn't take note text")
+			}
+			session, err = timesheetService.DeleteSessionNote(ctx, session.ID, remove, force)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Deleted note %d from session for %s\n", remove, clientName)
+		default:
+			if len(args) == 0 {
+				return fmt.Errorf("note text is required")
+			}
+			session, err = timesheetService.AddSessionNote(ctx, session.ID, text, force)
+			if err != nil {
+				return fmt.Errorf("failed to add note to session: %w", err)
+			}
+			fmt.Printf("Added note to session for %s:\n", clientName)
+			fmt.Printf("- %s\n", text)
 		}
 
-		fmt.Printf("Added note to session for %s:\n", activeSession.ClientName)
-		fmt.Printf("- %s\n", note)
-
-		if updatedSession.OutsideGit != nil {
-			fmt.Printf("\nAll notes for this session:\n%s\n", *updatedSession.OutsideGit)
-		}
+		fmt.Printf("\nAll notes for this session:\n")
+		timesheetService.DisplaySessionNotes(session)
 		return nil
 	}
 
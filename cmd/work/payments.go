@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newPaymentsCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "payments",
+		Short: "View and correct invoice payments",
+		Long:  "View the payments recorded against invoices and delete any recorded in error. Record payments with 'work invoices pay'.",
+	}
+
+	cmd.AddCommand(newPaymentsListCmd(timesheetService))
+	cmd.AddCommand(newPaymentsDeleteCmd(timesheetService))
+
+	return cmd
+}
+
+func newPaymentsListCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var invoice string
+	var client string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List payments",
+		Long:  "List payments recorded against invoices, oldest first, with any early payment discount each one took.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.ListPayments(ctx, invoice, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&invoice, "invoice", "i", "", "Filter by invoice ID or number")
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Filter by client")
+
+	return cmd
+}
+
+func newPaymentsDeleteCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <payment-id>",
+		Short: "Delete a payment recorded in error",
+		Long: `Delete a payment recorded in error, taking it off its invoice's amount paid. Any early payment discount the
+payment took is removed with it. Payment IDs are shown by 'work payments list'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.DeletePayment(ctx, args[0])
+		},
+		Annotations: mutating(),
+	}
+
+	return cmd
+}
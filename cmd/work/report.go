@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newReportCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Business reports across clients and invoices",
+		Long:  "Commands for reporting on the business as a whole, such as where revenue comes from.",
+	}
+
+	cmd.AddCommand(newReportSourcesCmd(timesheetService))
+	cmd.AddCommand(newReportExpensesCmd(timesheetService))
+	cmd.AddCommand(newReportEffectiveRateCmd(timesheetService))
+	cmd.AddCommand(newReportMonthlyCmd(timesheetService))
+	cmd.AddCommand(newReportUtilisationCmd(timesheetService))
+	cmd.AddCommand(newReportAgingCmd(timesheetService))
+	cmd.AddCommand(newReportHeatmapCmd(timesheetService))
+	cmd.AddCommand(newReportUninvoicedCmd(timesheetService))
+
+	return cmd
+}
+
+func newReportSourcesCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var year int
+	var financialYear bool
+
+	cmd := &cobra.Command{
+		Use:   "sources",
+		Short: "Show revenue by client acquisition channel per year",
+		Long: `Show invoiced revenue (excluding GST) grouped by each client's source, such as referral,
+linkedin, agency or repeat, for each year. Set a client's source with 'work clients update <client> --source <source>'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.ShowRevenueBySource(ctx, year, financialYear)
+		},
+	}
+
+	cmd.Flags().IntVarP(&year, "year", "y", 0, "Only show this year (e.g., 2025, the year a financial year ends in with --fy)")
+	cmd.Flags().BoolVar(&financialYear, "fy", false, "Group by financial year (starting FY_START_MONTH) rather than calendar year")
+
+	return cmd
+}
+
+func newReportExpensesCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var year int
+	var financialYear bool
+	var client string
+
+	cmd := &cobra.Command{
+		Use:   "expenses",
+		Short: "Show expense totals by category per year",
+		Long: `Show expense totals grouped by category, such as travel, software or hardware, for each year.
+Set an expense's category with 'work expenses update <expense-id> --category <category>'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.ShowExpensesByCategory(ctx, client, year, financialYear)
+		},
+	}
+
+	cmd.Flags().IntVarP(&year, "year", "y", 0, "Only show this year (e.g., 2025, the year a financial year ends in with --fy)")
+	cmd.Flags().BoolVar(&financialYear, "fy", false, "Group by financial year (starting FY_START_MONTH) rather than calendar year")
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Only show expenses for this client")
+
+	return cmd
+}
+
+func newReportEffectiveRateCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var period, date, client string
+	var periods int
+	var paid bool
+
+	cmd := &cobra.Command{
+		Use:   "effective-rate",
+		Short: "Show what each client pays per hour actually worked, over recent periods",
+		Long: `Show each client's revenue (excluding GST and billed expenses) divided by every hour worked for them,
+including unbillable time and hours covered by a retainer, for each of the last few periods and overall. A
+trend compares the first and last periods with time worked. Invoices are shared out across the periods their own
+period overlaps by days, so use a report period at least as long as you invoice for to keep it steady.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.ShowEffectiveRates(ctx, client, period, date, periods, paid)
+		},
+	}
+
+	cmd.Flags().StringVarP(&period, "period", "p", "month", "Period type: "+strings.Join(service.PeriodTypes, ", "))
+	cmd.Flags().IntVarP(&periods, "periods", "n", 6, "Number of periods to show, ending with the current one")
+	cmd.Flags().StringVarP(&date, "date", "d", "", "Date in the last period to show (YYYY-MM-DD), defaults to today")
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Only show this client")
+	cmd.Flags().BoolVar(&paid, "paid", false, "Count only what's been paid of each invoice rather than everything invoiced")
+
+	return cmd
+}
+
+func newReportMonthlyCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var month, output string
+	var email bool
+
+	cmd := &cobra.Command{
+		Use:   "monthly",
+		Short: "Summarise a month's hours, invoices, payments and expenses for your records",
+		Long: `Summarise a month for your own records: hours and billable amounts by client, invoices issued, payments
+received, balances still outstanding, the largest expenses and how the month compares with the one before.
+It's printed by default, written to a PDF or HTML file with --output, or emailed to REPORT_EMAIL with --email
+once SMTP_HOST and SMTP_FROM are set.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reportMonth, err := service.ParseReportMonth(month, time.Now())
+			if err != nil {
+				return err
+			}
+			return timesheetService.MonthlyReport(cmd.Context(), reportMonth, output, email)
+		},
+	}
+
+	cmd.Flags().StringVarP(&month, "month", "m", "", "Month to report on (YYYY-MM), defaults to last month")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the report to a .pdf or .html file")
+	cmd.Flags().BoolVar(&email, "email", false, "Email the report to REPORT_EMAIL through SMTP_HOST")
+
+	return cmd
+}
+
+func newReportUtilisationCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var period, date string
+	var ignoreLeave bool
+
+	cmd := &cobra.Command{
+		Use:     "utilisation",
+		Aliases: []string{"utilization"},
+		Short:   "Compare billable hours with capacity, week by week",
+		Long: `Compare the billable hours you tracked in each week of a period with your capacity, TARGET_WEEKLY_HOURS
+shared across the week's working days less any leave recorded with 'work leave add'. Each week shows the
+percentage of capacity used and the change from the week before, flagging weeks over capacity or under 75%,
+and the period is compared with the one before it. Unbillable time and other users' sessions aren't counted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.ShowUtilisation(cmd.Context(), period, date, ignoreLeave)
+		},
+	}
+
+	cmd.Flags().StringVarP(&period, "period", "p", "month", "Period type: "+strings.Join(service.PeriodTypes[1:], ", "))
+	cmd.Flags().StringVarP(&date, "date", "d", "", "Date in the period to show (YYYY-MM-DD), defaults to today")
+	cmd.Flags().BoolVar(&ignoreLeave, "ignore-leave", false, "Don't take leave out of capacity")
+
+	return cmd
+}
+
+func newReportAgingCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client, date, sortBy string
+	var showInvoices bool
+
+	cmd := &cobra.Command{
+		Use:     "aging",
+		Aliases: []string{"ageing"},
+		Short:   "Show what each client owes on unpaid invoices by how overdue it is",
+		Long: `Show what each client owes on unpaid invoices grouped by how many days past due they are: current (not yet
+due), 1-30, 31-60, 61-90 and over 90 days, with each client's total, the days overdue of their oldest invoice and
+totals for each currency. An invoice is due the number of days its payment terms give after it was issued (e.g.
+"Payment due within 30 days" or "Net 30"), or PAYMENT_DUE_DAYS when they don't say. Drafts, written-off and void
+invoices aren't included.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.ShowInvoiceAging(cmd.Context(), client, date, sortBy, showInvoices)
+		},
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Only show this client")
+	cmd.Flags().StringVarP(&date, "date", "d", "", "Date to age invoices on (YYYY-MM-DD), defaults to today")
+	cmd.Flags().StringVarP(&sortBy, "sort", "s", service.AgingSortClient, "Sort clients by: "+strings.Join(service.AgingSorts, ", "))
+	cmd.Flags().BoolVarP(&showInvoices, "invoices", "i", false, "List each client's unpaid invoices beneath it, oldest first")
+
+	return cmd
+}
+
+func newReportHeatmapCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var year int
+	var client, output string
+
+	cmd := &cobra.Command{
+		Use:   "heatmap",
+		Short: "Draw a year of tracked hours as a contribution calendar image",
+		Long: `Draw the hours tracked on each day of a year as a contribution calendar, like GitHub's, with a column of
+days for each week shaded from light to dark by how much was worked, for a portfolio or annual review. It's
+written as an SVG, where hovering over a day shows its hours, or a PNG. Give --output a format to write
+heatmap-<year>.svg or .png, or a file name ending in one.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if year == 0 {
+				year = time.Now().Year()
+			}
+			return timesheetService.ExportHeatmap(cmd.Context(), year, client, output)
+		},
+	}
+
+	cmd.Flags().IntVarP(&year, "year", "y", 0, "Year to draw, defaults to this year")
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Only count this client's sessions")
+	cmd.Flags().StringVarP(&output, "output", "o", service.HeatmapFormatSVG, "svg, png, or a file name ending in .svg or .png")
+
+	return cmd
+}
+
+func newReportUninvoicedCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client, threshold string
+	var olderThan int
+
+	cmd := &cobra.Command{
+		Use:   "uninvoiced",
+		Short: "Show each client's completed work that hasn't been invoiced yet",
+		Long: `Show each client's completed sessions that haven't been invoiced: how many, the hours worked, their value
+at session rates before retainers and GST, and the date of the oldest, with totals for each currency. Clients with
+work older than --older-than days are warned about, e.g. "acme has $4,200.00 uninvoiced older than 30 days", when
+it's worth at least --threshold. Run it weekly, from cron for example, so no work goes unbilled.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			amount, err := decimal.NewFromString(threshold)
+			if err != nil {
+				return fmt.Errorf("invalid threshold %q", threshold)
+			}
+			return timesheetService.ShowUninvoicedWork(cmd.Context(), client, olderThan, amount)
+		},
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Only show this client")
+	cmd.Flags().IntVar(&olderThan, "older-than", 30, "Warn about clients with work uninvoiced for more than this many days")
+	cmd.Flags().StringVar(&threshold, "threshold", "0", "Only warn when the older work is worth at least this much")
+
+	return cmd
+}
@@ -3,30 +3,89 @@ package main
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/jesses-code-adventures/work/internal/lock"
+	"github.com/jesses-code-adventures/work/internal/logging"
 	"github.com/jesses-code-adventures/work/internal/service"
 )
 
 func newRootCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var quiet, verbose bool
+	var dbPath string
+	var held *lock.Lock
+
 	rootCmd := &cobra.Command{
 		Use:   "work",
 		Short: "CLI work time tracker for freelance work",
 		Long: `Track your work sessions across multiple clients with simple start/stop commands.
 Supports hourly rate tracking and automatic billable amount calculations for freelance work.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			timesheetService.SetLogLevel(logging.Level(quiet, verbose))
+			timesheetService.SetOutput(cmd.OutOrStdout())
+			if cmd.Annotations[uncheckedAnnotation] == "" {
+				if err := timesheetService.CheckDatabase(cmd.Context(), cmd.Annotations[mutatingAnnotation] != ""); err != nil {
+					return err
+				}
+			}
+			// Failed commands skip PersistentPostRun, so a lock they held is released here when the root
+			// command is run again, as in tests, and otherwise when the process exits
+			held.Release()
+			// Resolved before taking the lock, so a prompt for which client was meant doesn't hold up other commands
+			if err := resolveClientFlag(cmd, timesheetService); err != nil {
+				return err
+			}
+			var err error
+			held, err = lockIfMutating(cmd, timesheetService.Config())
+			return err
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			held.Release()
+		},
+		// main prints returned errors, so cobra doesn't print them a second time
+		SilenceErrors: true,
 	}
 
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only log warnings and errors, and hide progress")
+	// Read in main before the database is opened, declared here so it's accepted and documented
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db", "", "Database to use instead of WORK_DB or DATABASE_URL, e.g. one per business year")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Log debug detail such as each repository analyzed")
+
 	rootCmd.AddCommand(
 		newStartCmd(timesheetService),
 		newStopCmd(timesheetService),
 		newStatusCmd(timesheetService),
+		newQuickCmd(timesheetService),
+		newAddCmd(timesheetService),
 		newNoteCmd(timesheetService),
 		newGitCheckCmd(timesheetService),
+		newAutoCmd(timesheetService),
 		newClientsCmd(timesheetService),
+		newContactsCmd(timesheetService),
+		newUsersCmd(timesheetService),
 		newSessionsCmd(timesheetService),
 		newDescriptionsCmd(timesheetService),
 		newInvoicesCmd(timesheetService),
+		newPaymentsCmd(timesheetService),
 		newHoursCmd(timesheetService),
+		newWeekCmd(timesheetService),
+		newLeaveCmd(timesheetService),
 		newExpensesCmd(timesheetService),
+		newMilestonesCmd(timesheetService),
+		newExportCmd(timesheetService),
+		newImportCmd(timesheetService),
+		newDoctorCmd(timesheetService),
+		newDBCmd(timesheetService),
+		newDemoCmd(timesheetService),
+		newConfigCmd(timesheetService),
+		newTemplatesCmd(timesheetService),
+		newTrashCmd(timesheetService),
+		newReportCmd(timesheetService),
+		newAuditCmd(timesheetService),
+		newTaxCmd(timesheetService),
+		newServeCmd(timesheetService),
 	)
+	addAliases(rootCmd, timesheetService.Config().Aliases, timesheetService.Logger())
+	applyDefaultFlags(rootCmd, timesheetService.Config().DefaultFlags, timesheetService.Logger())
+	suggestSubcommands(rootCmd)
 
 	return rootCmd
 }
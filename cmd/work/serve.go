@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newServeCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var addr string
+	var statusOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the current status to widgets over HTTP",
+		Long: `Serve the current status over HTTP, for menu bar widgets, stream overlays and the like. Only the status is
+served, with --status-only, so nothing else in the database can be read or changed through it.
+
+GET /status returns JSON with the active session's client, description, start and elapsed time, and the time
+worked today. Requests must send STATUS_API_KEY as "Authorization: Bearer <key>" or in an X-API-Key header,
+and the server won't start without one set.`,
+		Example: `  work serve --status-only
+  work serve --status-only --addr 127.0.0.1:8787
+  curl -H "Authorization: Bearer $STATUS_API_KEY" http://127.0.0.1:8787/status`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8787", "Address to listen on, use 0.0.0.0:<port> to serve other machines")
+	cmd.Flags().BoolVar(&statusOnly, "status-only", false, "Only serve GET /status")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if !statusOnly {
+			return fmt.Errorf("only the status can be served so far, run 'work serve --status-only'")
+		}
+		apiKey := timesheetService.Config().StatusAPIKey
+		if apiKey == "" {
+			return fmt.Errorf("STATUS_API_KEY isn't set, set it to the key widgets will send before serving the status")
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		server := &http.Server{
+			Addr:              addr,
+			Handler:           newStatusHandler(timesheetService, apiKey),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		errs := make(chan error, 1)
+		go func() {
+			errs <- server.ListenAndServe()
+		}()
+		fmt.Printf("Serving the status at http://%s/status, press Ctrl+C to stop\n", addr)
+
+		select {
+		case err := <-errs:
+			return fmt.Errorf("failed to serve the status: %w", err)
+		case <-ctx.Done():
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+
+	return cmd
+}
+
+// newStatusHandler serves the current status as JSON at GET /status to requests sending apiKey, and nothing else
+func newStatusHandler(timesheetService *service.TimesheetService, apiKey string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		if !validAPIKey(r, apiKey) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "a valid API key is required"})
+			return
+		}
+		status, err := timesheetService.CurrentStatus(r.Context())
+		if err != nil {
+			timesheetService.Logger().Error("failed to get status", "error", err)
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to get status"})
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+	})
+	return mux
+}
+
+// validAPIKey reports whether a request sent apiKey as a bearer token or in an X-API-Key header, comparing in
+// constant time
+func validAPIKey(r *http.Request, apiKey string) bool {
+	key := r.Header.Get("X-API-Key")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		key = strings.TrimSpace(bearer)
+	}
+	return key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, code int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+const (
+	// sessionPickerLimit is how many recent sessions the picker searches
+	sessionPickerLimit = 200
+	// sessionPickerShown is how many matching sessions the picker lists at once
+	sessionPickerShown = 15
+)
+
+// sessionIDArg returns the full ID of the session ref names, by its ID or a prefix of it no other session shares.
+// When ref is empty the session is picked from your recent sessions, if run interactively.
+func sessionIDArg(cmd *cobra.Command, timesheetService *service.TimesheetService, ref string) (string, error) {
+	if ref != "" {
+		return timesheetService.ResolveSessionID(cmd.Context(), ref)
+	}
+	return pickSession(cmd, timesheetService)
+}
+
+// pickSession lists recent sessions to choose one by number, narrowing them down by client, date, ID or
+// description with anything else typed
+func pickSession(cmd *cobra.Command, timesheetService *service.TimesheetService) (string, error) {
+	if !stdinIsTerminal() {
+		return "", fmt.Errorf("a session ID is required when not run interactively")
+	}
+	sessions, err := timesheetService.ListRecentSessions(cmd.Context(), sessionPickerLimit)
+	if err != nil {
+		return "", err
+	}
+	if len(sessions) == 0 {
+		return "", fmt.Errorf("no sessions to choose from")
+	}
+	shortIDs := service.ShortSessionIDs(sessions)
+
+	reader := bufio.NewReader(os.Stdin)
+	matches, query := sessions, ""
+	for {
+		shown := matches[:min(len(matches), sessionPickerShown)]
+		if len(shown) == 0 {
+			fmt.Printf("No sessions match '%s'\n", query)
+		}
+		for i, session := range shown {
+			fmt.Printf("%3d. %s\n", i+1, timesheetService.SessionPickerLine(session, shortIDs[session.ID]))
+		}
+		if len(matches) > len(shown) {
+			fmt.Printf("     and %d more, type to narrow them down\n", len(matches)-len(shown))
+		}
+
+		fmt.Print("Filter by client, date or description, choose a number, or press enter to cancel: ")
+		// Reaching the end of input cancels, as nothing more can be typed
+		response, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("failed to read response: %w", err)
+		}
+		response = strings.TrimSpace(response)
+		if response == "" {
+			return "", fmt.Errorf("no session chosen")
+		}
+		if choice, err := strconv.Atoi(response); err == nil && choice >= 1 && choice <= len(shown) {
+			return shown[choice-1].ID, nil
+		}
+		query = response
+		matches = timesheetService.FilterSessionsByQuery(sessions, query)
+		fmt.Println()
+	}
+}
+
+// stdinIsTerminal reports whether the command is being run interactively, with someone to answer prompts
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
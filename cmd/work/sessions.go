@@ -25,6 +25,8 @@ func newSessionsCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	cmd.AddCommand(newSessionsUpdateCmd(timesheetService))
 	cmd.AddCommand(newSessionsDeleteCmd(timesheetService))
 	cmd.AddCommand(newSessionsCsvCmd(timesheetService))
+	cmd.AddCommand(newSessionsSplitByGitCmd(timesheetService))
+	cmd.AddCommand(newSessionsFromGitCmd(timesheetService))
 
 	return cmd
 }
@@ -35,26 +37,58 @@ func newSessionsCreateCmd(timesheetService *service.TimesheetService) *cobra.Com
 	var toTime string
 	var description string
 	var includesGst bool
+	var user string
+	var batch string
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a work session with custom start and end times",
-		Long:  "Create a work session for a client with specified start and end times. Times should be in format 'YYYY-MM-DD HH:MM' or 'HH:MM' for today.",
+		Long: "Create a work session for a client with specified start and end times. Times can be " + service.TimeFormatsHelp + `.
+
+--batch creates many sessions at once from a file, or from stdin with '-'. It's read as JSON when it starts
+with [ or {, either an array of objects or one object after another, each with client, start, end and an
+optional description. Anything else is read as CSV with client, start, end and description columns, in that
+order or named in a header row. Every record is checked before anything is created, and either every session
+is created or none are.`,
+		Example: `  work sessions create -c acme -f "2025-07-01 09:00" -t "2025-07-01 12:30" -d "Planning"
+  work sessions create --batch sessions.csv --dry-run
+  echo '{"client": "acme", "start": "2025-07-01 09:00", "end": "2025-07-01 12:30"}' | work sessions create --batch -`,
 	}
 
 	cmd.Flags().StringVarP(&client, "client", "c", "", "Client name (required)")
-	cmd.Flags().StringVarP(&fromTime, "from", "f", "", "Start time (required, format: 'YYYY-MM-DD HH:MM' or 'HH:MM')")
-	cmd.Flags().StringVarP(&toTime, "to", "t", "", "End time (required, format: 'YYYY-MM-DD HH:MM' or 'HH:MM')")
+	cmd.Flags().StringVarP(&fromTime, "from", "f", "", "Start time (required), e.g. 'YYYY-MM-DD HH:MM:SS', ISO8601 or 'HH:MM' for today")
+	cmd.Flags().StringVarP(&toTime, "to", "t", "", "End time (required), e.g. 'YYYY-MM-DD HH:MM:SS', ISO8601 or 'HH:MM' for today")
 	cmd.Flags().StringVarP(&description, "description", "d", "", "Session description (optional)")
 	cmd.Flags().BoolVar(&includesGst, "includes-gst", false, "Session amount includes GST (default: false)")
+	cmd.Flags().StringVarP(&user, "user", "u", "", "User who did the work, or '"+service.OwnerUser+"' for yourself (default: WORK_USER)")
 
-	cmd.MarkFlagRequired("client")
-	cmd.MarkFlagRequired("from")
-	cmd.MarkFlagRequired("to")
+	cmd.Flags().StringVar(&batch, "batch", "", "Create the sessions in a JSON or CSV file, or '-' to read them from stdin")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "With --batch, check the sessions and show what would be created without creating them")
 
+	cmd.MarkFlagsRequiredTogether("client", "from", "to")
+	cmd.MarkFlagsOneRequired("batch", "from")
+	cmd.MarkFlagsMutuallyExclusive("batch", "from")
+	cmd.MarkFlagsMutuallyExclusive("batch", "description")
+	cmd.MarkFlagsMutuallyExclusive("batch", "user")
+
+	cmd.Annotations = mutating()
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 
+		if batch != "" {
+			input := os.Stdin
+			if batch != "-" {
+				file, err := os.Open(batch)
+				if err != nil {
+					return fmt.Errorf("failed to open batch file: %w", err)
+				}
+				defer file.Close()
+				input = file
+			}
+			return timesheetService.CreateSessionsFromBatch(ctx, input, includesGst, dryRun)
+		}
+
 		startTime, err := timesheetService.ParseTimeString(fromTime)
 		if err != nil {
 			return fmt.Errorf("invalid start time format: %w", err)
@@ -78,6 +112,11 @@ func newSessionsCreateCmd(timesheetService *service.TimesheetService) *cobra.Com
 		if err != nil {
 			return fmt.Errorf("failed to create session: %w", err)
 		}
+		if user != "" {
+			if session, err = timesheetService.SetSessionUser(ctx, session.ID, user, false); err != nil {
+				return fmt.Errorf("failed to attribute session to '%s': %w", user, err)
+			}
+		}
 
 		duration := timesheetService.CalculateDuration(session)
 		billableAmount := timesheetService.CalculateBillableAmount(session)
@@ -87,12 +126,18 @@ func newSessionsCreateCmd(timesheetService *service.TimesheetService) *cobra.Com
 		fmt.Printf("  Start: %s\n", session.StartTime.Format("2006-01-02 15:04:05"))
 		fmt.Printf("  End: %s\n", session.EndTime.Format("2006-01-02 15:04:05"))
 		fmt.Printf("  Duration: %s\n", timesheetService.FormatDuration(duration))
+		if user != "" {
+			fmt.Printf("  User: %s\n", user)
+		}
 		if description != "" {
 			fmt.Printf("  Description: %s\n", description)
 		}
 		if billableAmount.GreaterThan(decimal.Zero) {
 			fmt.Printf("  Billable: %s\n", timesheetService.FormatSessionBillableAmount(session))
 		}
+
+		clientModel, _ := timesheetService.GetClientByID(ctx, session.ClientID)
+		showHints(cmd, sessionFinished, hintSubject{client: clientModel, session: session})
 		return nil
 	}
 
@@ -106,45 +151,48 @@ func newSessionsListCmd(timesheetService *service.TimesheetService) *cobra.Comma
 	var client string
 	var period string
 	var periodDate string
+	var machine string
+	var user string
+	var uninvoiced bool
+	var missingDescription bool
+	var summary bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List work sessions",
-		Long:  "Show a list of work sessions with durations and billable amounts. Filter by date range using -f and -t flags, by period using -p flag, or by client using -c flag. Use -v for verbose output including full work summaries.",
+		Long:  "Show a list of work sessions with durations and billable amounts. Filter by date range using -f and -t flags, by period using -p flag, by client using -c flag, by the machine that recorded them using --machine, by who did the work using --user, or to those not yet invoiced using --uninvoiced, or without a description or notes using --missing-description. Each session is marked INVOICED, with the invoice number, or UNINVOICED, and the uninvoiced hours and amount are totalled at the end. Use --summary to follow the list with the total hours and billable amount, each client's share, the average session length and a sparkline of the hours worked across the listed range. Use -v for verbose output including full work summaries and the git repositories behind each description.",
 	}
 
 	cmd.Flags().Int32VarP(&limit, "limit", "l", 10, "Number of sessions to show")
 	cmd.Flags().StringVarP(&fromDate, "from", "f", "", "Show sessions from this date (YYYY-MM-DD)")
 	cmd.Flags().StringVarP(&toDate, "to", "t", "", "Show sessions to this date (YYYY-MM-DD)")
-	cmd.Flags().StringVarP(&period, "period", "p", "", "Period type: day, week, fortnight, month")
+	cmd.Flags().StringVarP(&period, "period", "p", "", "Period type: day, week, fortnight, month, quarter, year")
 	cmd.Flags().StringVarP(&periodDate, "date", "d", "", "Date in the period (YYYY-MM-DD), defaults to today when using -p")
-	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show full work summaries")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show full work summaries and the repositories they came from")
 	cmd.Flags().StringVarP(&client, "client", "c", "", "Filter sessions by client name")
+	cmd.Flags().StringVar(&machine, "machine", "", "Filter sessions by the hostname of the machine that started them")
+	cmd.Flags().StringVarP(&user, "user", "u", "", "Filter sessions by the user who did the work, or '"+service.OwnerUser+"' for your own")
+	cmd.Flags().BoolVar(&uninvoiced, "uninvoiced", false, "Only show sessions that aren't on an invoice")
+	cmd.Flags().BoolVar(&missingDescription, "missing-description", false, "Only show sessions with neither a description nor notes")
+	cmd.Flags().BoolVar(&summary, "summary", false, "Finish with totals, each client's share, the average session length and a sparkline of hours worked")
 
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 
-		// Handle period filtering (same logic as hours command)
-		if period != "" {
-			var targetDate time.Time
-			var err error
-
-			if periodDate == "" {
-				// Default to today's date
-				targetDate = time.Now()
-			} else {
-				targetDate, err = time.Parse("2006-01-02", periodDate)
-				if err != nil {
-					return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
-				}
-			}
+		// These filters are applied after fetching, so fetch everything and apply the limit afterwards
+		fetchLimit := limit
+		if machine != "" || user != "" || uninvoiced || missingDescription {
+			fetchLimit = 10000
+		}
 
-			fromDateTime, toDateTime := timesheetService.CalculatePeriodRange(period, targetDate)
-			fromDate = fromDateTime.Format("2006-01-02")
-			toDate = toDateTime.Format("2006-01-02")
+		// Handle period filtering (same logic as hours command)
+		var err error
+		fromDate, toDate, err = timesheetService.ResolvePeriodRange(period, periodDate, fromDate, toDate)
+		if err != nil {
+			return err
 		}
 
-		var sessions, err = func() ([]*models.WorkSession, error) {
+		sessions, err := func() ([]*models.WorkSession, error) {
 			if client != "" {
 				if fromDate != "" || toDate != "" {
 					// Get all sessions for client, then filter by date range
@@ -152,14 +200,10 @@ func newSessionsListCmd(timesheetService *service.TimesheetService) *cobra.Comma
 					if err != nil {
 						return nil, fmt.Errorf("failed to get sessions for client: %w", err)
 					}
-					filtered := timesheetService.FilterSessionsByDateRange(allSessions, fromDate, toDate)
-					// Apply limit after filtering
-					if int32(len(filtered)) > limit {
-						filtered = filtered[:limit]
-					}
-					return filtered, nil
+					// The limit is applied after filtering
+					return timesheetService.FilterSessionsByDateRange(allSessions, fromDate, toDate), nil
 				} else {
-					return timesheetService.ListSessionsByClient(ctx, client, limit)
+					return timesheetService.ListSessionsByClient(ctx, client, fetchLimit)
 				}
 			}
 			if fromDate != "" || toDate != "" {
@@ -169,27 +213,55 @@ func newSessionsListCmd(timesheetService *service.TimesheetService) *cobra.Comma
 				if toDate == "" {
 					toDate = "2099-12-31"
 				}
-				return timesheetService.ListSessionsWithDateRange(ctx, fromDate, toDate, limit)
+				return timesheetService.ListSessionsWithDateRange(ctx, fromDate, toDate, fetchLimit)
 			}
-			return timesheetService.ListRecentSessions(ctx, limit)
+			return timesheetService.ListRecentSessions(ctx, fetchLimit)
 		}()
 		if err != nil {
 			return err
 		}
 
+		if machine != "" {
+			sessions = timesheetService.FilterSessionsByMachine(sessions, machine)
+		}
+		if user != "" {
+			if sessions, err = timesheetService.FilterSessionsByUser(ctx, sessions, user); err != nil {
+				return err
+			}
+		}
+		if uninvoiced {
+			sessions = timesheetService.FilterUninvoicedSessions(sessions)
+		}
+		if missingDescription {
+			sessions = timesheetService.FilterSessionsMissingDescription(sessions)
+		}
+		if int32(len(sessions)) > limit {
+			sessions = sessions[:limit]
+		}
+
 		if len(sessions) == 0 {
 			if client != "" {
 				fmt.Printf("No work sessions found for client '%s'.\n", client)
+			} else if machine != "" {
+				fmt.Printf("No work sessions found for machine '%s'.\n", machine)
+			} else if user != "" {
+				fmt.Printf("No work sessions found for user '%s'.\n", user)
+			} else if uninvoiced {
+				fmt.Println("No uninvoiced work sessions found.")
+			} else if missingDescription {
+				fmt.Println("No work sessions missing a description.")
 			} else {
 				fmt.Println("No work sessions found.")
 			}
 			return nil
 		}
 
-		for _, session := range sessions {
-			timesheetService.DisplaySession(session, verbose)
+		if err := timesheetService.DisplaySessionList(ctx, sessions, verbose); err != nil {
+			return err
+		}
+		if summary {
+			timesheetService.DisplaySessionSummary(sessions)
 		}
-
 		return nil
 	}
 
@@ -198,18 +270,20 @@ func newSessionsListCmd(timesheetService *service.TimesheetService) *cobra.Comma
 
 func newSessionsDeleteCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	var fromDate, toDate string
-	var force bool
+	var force, includeInvoiced bool
 
 	cmd := &cobra.Command{
 		Use:   "delete",
 		Short: "Delete work sessions",
-		Long:  "Delete work sessions. Use with caution - this action cannot be undone.",
+		Long:  "Delete work sessions, moving them to the trash. They can be brought back with 'work trash restore' until they're purged. Sessions on an invoice are kept so billing history stays intact, and only deleted with --include-invoiced, after which the invoice should be regenerated.",
 	}
 
 	cmd.Flags().StringVarP(&fromDate, "from", "f", "", "Delete sessions from this date (YYYY-MM-DD)")
 	cmd.Flags().StringVarP(&toDate, "to", "t", "", "Delete sessions to this date (YYYY-MM-DD)")
 	cmd.Flags().BoolVar(&force, "force", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&includeInvoiced, "include-invoiced", false, "Delete sessions even if they are on an invoice")
 
+	cmd.Annotations = mutating()
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 
@@ -224,8 +298,12 @@ func newSessionsDeleteCmd(timesheetService *service.TimesheetService) *cobra.Com
 			} else {
 				rangeStr = "all work sessions"
 			}
+			kept := ""
+			if !includeInvoiced {
+				kept = ", keeping any on an invoice"
+			}
 
-			fmt.Printf("This will permanently delete %s. Are you sure? (y/N): ", rangeStr)
+			fmt.Printf("This will move %s to the trash%s. Are you sure? (y/N): ", rangeStr, kept)
 			reader := bufio.NewReader(os.Stdin)
 			response, err := reader.ReadString('\n')
 			if err != nil {
@@ -246,27 +324,36 @@ func newSessionsDeleteCmd(timesheetService *service.TimesheetService) *cobra.Com
 				toDate = "2099-12-31"
 			}
 
-			err := timesheetService.DeleteSessionsByDateRange(ctx, fromDate, toDate)
+			deleted, skipped, err := timesheetService.DeleteSessionsByDateRange(ctx, fromDate, toDate, includeInvoiced)
 			if err != nil {
 				return err
 			}
 
-			fmt.Printf("Deleted work sessions from %s to %s\n", fromDate, toDate)
+			fmt.Printf("Deleted %d work session(s) from %s to %s, restore them from the trash with 'work trash restore'\n", deleted, fromDate, toDate)
+			printSkippedInvoicedSessions(skipped)
 		} else {
-			err := timesheetService.DeleteAllSessions(ctx)
+			deleted, skipped, err := timesheetService.DeleteAllSessions(ctx, includeInvoiced)
 			if err != nil {
 				return err
 			}
 
-			fmt.Println("Deleted all work sessions")
+			fmt.Printf("Deleted %d work session(s), restore them from the trash with 'work trash restore'\n", deleted)
+			printSkippedInvoicedSessions(skipped)
 		}
 
-		return nil
+		return timesheetService.DisplayInvoicesNeedingRegeneration(ctx)
 	}
 
 	return cmd
 }
 
+// printSkippedInvoicedSessions says how many sessions weren't deleted for being on an invoice
+func printSkippedInvoicedSessions(skipped int64) {
+	if skipped > 0 {
+		fmt.Printf("Skipped %d session(s) on an invoice, use --include-invoiced to delete them too\n", skipped)
+	}
+}
+
 func newSessionsUpdateCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	var hourlyRate float64
 	var companyName, contactName, email, phone string
@@ -295,6 +382,7 @@ func newSessionsUpdateCmd(timesheetService *service.TimesheetService) *cobra.Com
 	cmd.Flags().StringVar(&taxNumber, "tax", "", "Tax/VAT number")
 	cmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory path for the session")
 
+	cmd.Annotations = mutating()
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		print("not implemented")
 		// sessionID := args[0]
@@ -334,6 +422,153 @@ func newSessionsUpdateCmd(timesheetService *service.TimesheetService) *cobra.Com
 	return cmd
 }
 
+func newSessionsSplitByGitCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "split-by-git [session-id]",
+		Short: "Split a session that covered work for two clients",
+		Long: `Split a session that accidentally covered work for two clients. Commit times in both clients'
+repositories during the session are used to propose a split point, and after confirmation the session
+is narrowed to its own client's work and a new session is created for the other client. A session on an
+invoice is only split with --force, after which the invoice should be regenerated.
+The session can be given by the start of its ID, or picked from your recent sessions when it's left out.`,
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "The other client worked on during the session (required)")
+	cmd.Flags().BoolVar(&force, "force", false, "Skip confirmation prompt, and split the session even if it is on an invoice")
+	cmd.MarkFlagRequired("client")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sessionID, err := sessionIDArg(cmd, timesheetService, strings.Join(args, ""))
+		if err != nil {
+			return err
+		}
+
+		split, err := timesheetService.ProposeSessionSplit(ctx, sessionID, client)
+		if err != nil {
+			return err
+		}
+		timesheetService.DisplaySessionSplit(split)
+
+		if !force {
+			fmt.Print("\nApply this split? (y/N): ")
+			reader := bufio.NewReader(os.Stdin)
+			response, err := reader.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			response = strings.ToLower(strings.TrimSpace(response))
+			if response != "y" && response != "yes" {
+				fmt.Println("Operation cancelled.")
+				return nil
+			}
+		}
+
+		updated, created, err := timesheetService.ApplySessionSplit(ctx, split, force)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\nSplit session %s:\n", updated.ID)
+		timesheetService.DisplaySession(updated, nil, false)
+		timesheetService.DisplaySession(created, nil, false)
+		if updated.Description != nil {
+			fmt.Printf("The description of session %s was kept and may describe both clients' work\n", updated.ID)
+		}
+		return timesheetService.DisplayInvoicesNeedingRegeneration(ctx)
+	}
+
+	return cmd
+}
+
+func newSessionsFromGitCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client, fromDate, toDate string
+	var gap time.Duration
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "from-git",
+		Short: "Create sessions for untracked work from git history",
+		Long: `Propose sessions for a client from the commits you made in its repositories between two dates, for
+time that was never tracked. Commits are grouped into a session until there's more than --gap between one
+and the next, and each session starts 30 minutes before its first commit and ends at its last, described by
+the commits' subjects. Commits already in or near a tracked session are left out, and a proposed session is
+skipped if it would overlap one of yours. The proposal is shown to confirm before anything is created.`,
+		Example: `  work sessions from-git --client acme --from 2025-03-10 --to 2025-03-14
+  work sessions from-git -c acme -f 2025-03-10 -t 2025-03-14 --gap 2h`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Client whose repositories to read (required)")
+	cmd.Flags().StringVarP(&fromDate, "from", "f", "", "First day to look at (YYYY-MM-DD, required)")
+	cmd.Flags().StringVarP(&toDate, "to", "t", "", "Last day to look at (YYYY-MM-DD, required)")
+	cmd.Flags().DurationVar(&gap, "gap", service.DefaultGitSessionGap, "Longest gap between commits in the same session")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Create the sessions without asking to confirm")
+	cmd.MarkFlagRequired("client")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		from, err := time.ParseInLocation("2006-01-02", fromDate, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid from date format, expected YYYY-MM-DD: %w", err)
+		}
+		to, err := time.ParseInLocation("2006-01-02", toDate, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid to date format, expected YYYY-MM-DD: %w", err)
+		}
+
+		proposal, err := timesheetService.ProposeSessionsFromGit(ctx, client, from, to.AddDate(0, 0, 1).Add(-time.Nanosecond), gap)
+		if err != nil {
+			return err
+		}
+		timesheetService.DisplayGitSessionProposal(proposal)
+		creatable := 0
+		for _, proposed := range proposal.Sessions {
+			if proposed.Overlaps == nil {
+				creatable++
+			}
+		}
+		if creatable == 0 {
+			return nil
+		}
+
+		if !yes {
+			fmt.Printf("\nCreate %d sessions? (y/N): ", creatable)
+			reader := bufio.NewReader(os.Stdin)
+			response, err := reader.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			response = strings.ToLower(strings.TrimSpace(response))
+			if response != "y" && response != "yes" {
+				fmt.Println("Operation cancelled.")
+				return nil
+			}
+		}
+
+		created, err := timesheetService.CreateSessionsFromGit(ctx, proposal)
+		if err != nil {
+			return fmt.Errorf("failed to create sessions, %d were created: %w", len(created), err)
+		}
+		fmt.Printf("\nCreated %d sessions for %s:\n", len(created), proposal.Client.Name)
+		for _, session := range created {
+			timesheetService.DisplaySession(session, nil, false)
+		}
+		return nil
+	}
+
+	return cmd
+}
+
 func newSessionsCsvCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	var fromDate, toDate string
 	var output string
@@ -347,7 +582,7 @@ func newSessionsCsvCmd(timesheetService *service.TimesheetService) *cobra.Comman
 		Long:  "Export work sessions to CSV format with hourly rates and billable amounts. Supports optional date filtering.",
 	}
 
-	cmd.Flags().StringVarP(&period, "period", "p", "", "Period type: day, week, fortnight, month")
+	cmd.Flags().StringVarP(&period, "period", "p", "", "Period type: day, week, fortnight, month, quarter, year")
 	cmd.Flags().StringVarP(&date, "date", "d", "", "If using period, the date in the period (YYYY-MM-DD)")
 	cmd.Flags().StringVarP(&fromDate, "from", "f", "", "Export sessions from this date (YYYY-MM-DD)")
 	cmd.Flags().StringVarP(&toDate, "to", "t", "", "Export sessions to this date (YYYY-MM-DD)")
@@ -357,15 +592,10 @@ func newSessionsCsvCmd(timesheetService *service.TimesheetService) *cobra.Comman
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 
-		if fromDate == "" && toDate == "" && period != "" && date != "" {
-			fmt.Printf("Period: %s, date: %s\n", period, date)
-			var d time.Time
-			if date != "" {
-				d, _ = time.Parse("2006-01-02", date)
-			}
-			fromDateTime, toDateTime := timesheetService.CalculatePeriodRange(period, d)
-			fromDate = fromDateTime.Format("2006-01-02")
-			toDate = toDateTime.Format("2006-01-02")
+		var err error
+		fromDate, toDate, err = timesheetService.ResolvePeriodRange(period, date, fromDate, toDate)
+		if err != nil {
+			return err
 		}
 
 		fmt.Printf("Flags: period: %s, date: %s, from: %s, to: %s, output: %s, limit: %d\n", period, date, fromDate, toDate, output, limit)
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 
+	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 
 	"github.com/jesses-code-adventures/work/internal/models"
@@ -13,57 +14,91 @@ func newStartCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	var clientName string
 	var description string
 	var fromTime string
+	var templateName string
+	var rateStr string
+	var includesGst bool
+	var sinceLastStop bool
+	var sinceSession string
 
 	cmd := &cobra.Command{
 		Use:   "start",
 		Short: "Start a work session",
-		Long:  "Start a new work session for a client. This will automatically stop any active session.",
+		Long:  "Start a new work session for a client, or from a session template with --template. This will automatically stop any active session. Use --rate for a one-off negotiated rate instead of the client's. If you forgot to start tracking after your last session, --since-last-stop starts the new one when your last session today ended, or --since-session when a given session ended, so there's no gap between them.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if clientName == "" {
-				return fmt.Errorf("client name is required (use -c flag)")
+			ctx := cmd.Context()
+
+			if templateName != "" {
+				if clientName != "" || description != "" || fromTime != "" || rateStr != "" || includesGst || sinceLastStop || sinceSession != "" {
+					return fmt.Errorf("--template can't be combined with --client, --description, --from, --rate, --includes-gst or --since-last-stop")
+				}
+				session, err := timesheetService.StartFromTemplate(ctx, templateName)
+				if err != nil {
+					return err
+				}
+				printSessionStarted(session)
+				return nil
 			}
 
-			ctx := cmd.Context()
+			if clientName == "" {
+				return fmt.Errorf("client name is required (use -c flag, or --template)")
+			}
 
 			var desc *string
 			if description != "" {
 				desc = &description
 			}
 
+			var rate *decimal.Decimal
+			if rateStr != "" {
+				parsed, err := decimal.NewFromString(rateStr)
+				if err != nil || parsed.IsNegative() {
+					return fmt.Errorf("invalid rate %q, expected a non-negative amount", rateStr)
+				}
+				rate = &parsed
+			}
+
 			var session *models.WorkSession
 			var err error
 
-			if fromTime != "" {
+			if sinceLastStop || sinceSession != "" {
+				if fromTime != "" {
+					return fmt.Errorf("use either --from or --since-last-stop, not both")
+				}
+				last, lastErr := timesheetService.LastStoppedSession(ctx, sinceSession)
+				if lastErr != nil {
+					return lastErr
+				}
+				fmt.Printf("Starting from the end of the %s session at %s\n", last.ClientName, last.EndTime.Format("15:04:05"))
+				session, err = timesheetService.StartWorkWithTime(ctx, clientName, *last.EndTime, desc, rate, includesGst)
+			} else if fromTime != "" {
 				// Parse the custom start time
-				startTime, parseErr := timesheetService.ParseStartTime(fromTime)
+				startTime, parseErr := timesheetService.ParseTimeString(fromTime)
 				if parseErr != nil {
 					return fmt.Errorf("invalid time format: %w", parseErr)
 				}
-				session, err = timesheetService.StartWorkWithTime(ctx, clientName, startTime, desc)
+				session, err = timesheetService.StartWorkWithTime(ctx, clientName, startTime, desc, rate, includesGst)
 			} else {
-				session, err = timesheetService.StartWork(ctx, clientName, desc)
+				session, err = timesheetService.StartWork(ctx, clientName, desc, rate, includesGst)
 			}
 
 			if err != nil {
 				return err
 			}
 
-			fmt.Printf("Started work session for %s at %s\n",
-				clientName,
-				session.StartTime.Format("15:04:05"))
-
-			if desc != nil {
-				fmt.Printf("Description: %s\n", *desc)
-			}
-
+			printSessionStarted(session)
 			return nil
 		},
+		Annotations: mutating(),
 	}
 
-	cmd.Flags().StringVarP(&clientName, "client", "c", "", "Client name (required)")
+	cmd.Flags().StringVarP(&clientName, "client", "c", "", "Client name (required unless using --template)")
 	cmd.Flags().StringVarP(&description, "description", "d", "", "Optional description of the work")
-	cmd.Flags().StringVarP(&fromTime, "from", "f", "", "Start time (YYYY-MM-DD HH:MM or HH:MM)")
-	cmd.MarkFlagRequired("client")
+	cmd.Flags().StringVarP(&fromTime, "from", "f", "", "Start time, e.g. 'YYYY-MM-DD HH:MM:SS', ISO8601 or 'HH:MM' for today")
+	cmd.Flags().StringVar(&templateName, "template", "", "Start a session from a template (see 'work templates')")
+	cmd.Flags().StringVarP(&rateStr, "rate", "r", "", "Hourly rate for this session instead of the client's rate")
+	cmd.Flags().BoolVar(&includesGst, "includes-gst", false, "Session amount includes GST (default: false)")
+	cmd.Flags().BoolVar(&sinceLastStop, "since-last-stop", false, "Start when your last session today ended")
+	cmd.Flags().StringVar(&sinceSession, "since-session", "", "Start when this session ended, by ID or a prefix of it")
 
 	return cmd
 }
@@ -12,7 +12,7 @@ func newStatusCmd(timesheetService *service.TimesheetService) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show current work status",
-		Long:  "Display the currently active work session, if any.",
+		Long:  "Display the currently active work session, if any, and the hours and value of completed sessions that haven't been invoiced yet.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
@@ -23,25 +23,33 @@ func newStatusCmd(timesheetService *service.TimesheetService) *cobra.Command {
 
 			if session == nil {
 				fmt.Println("No active work session.")
-				return nil
+				return timesheetService.ShowUnbilledExposure(ctx)
 			}
 
 			duration := timesheetService.CalculateDuration(session)
-			billableAmount := timesheetService.CalculateBillableAmount(session)
 
 			fmt.Printf("Active work session:\n")
 			fmt.Printf("Client: %s\n", session.ClientName)
 			fmt.Printf("Started: %s (%s)\n",
 				session.StartTime.Format("15:04:05"),
 				session.StartTime.Format("2006-01-02"))
+			if session.Hostname != nil {
+				fmt.Printf("Machine: %s\n", *session.Hostname)
+			}
+			if session.GitBranch != nil {
+				fmt.Printf("Branch: %s\n", *session.GitBranch)
+			}
 			fmt.Printf("Duration: %s\n", timesheetService.FormatDuration(duration))
-			fmt.Printf("Billable amount: %s\n", timesheetService.FormatBillableAmount(billableAmount))
+			if err := timesheetService.ShowSessionEarnings(ctx, session); err != nil {
+				return err
+			}
 
 			if session.Description != nil && *session.Description != "" {
 				fmt.Printf("Description: %s\n", *session.Description)
 			}
 
-			return nil
+			fmt.Println()
+			return timesheetService.ShowUnbilledExposure(ctx)
 		},
 	}
 
@@ -1,26 +1,83 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
 	"github.com/jesses-code-adventures/work/internal/service"
 )
 
 func newStopCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var atTime string
+	var trimIdle bool
+	var description string
+	var ai bool
+
 	cmd := &cobra.Command{
 		Use:   "stop",
 		Short: "Stop the current work session",
-		Long:  "Stop the currently active work session and record the end time.",
+		Long: `Stop the currently active work session and record the end time, now or at the time given with --at.
+When IDLE_TRIM_MINUTES is set, idle periods ActivityWatch saw during the session are listed and can be taken out of it as breaks.
+When DESCRIPTION_POLICY is warn or block, a session without a description or notes asks for one, which can be
+given with --description or generated from git with --ai instead. Under block it keeps running until it's described.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
-			session, err := timesheetService.StopWork(ctx)
+			endTime := time.Now()
+			if atTime != "" {
+				var err error
+				endTime, err = timesheetService.ParseTimeString(atTime)
+				if err != nil {
+					return fmt.Errorf("invalid end time format: %w", err)
+				}
+			}
+
+			active, err := timesheetService.GetActiveSession(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get active session: %w", err)
+			}
+			if active != nil {
+				if description == "" && !ai && timesheetService.NeedsStopDescription(active) {
+					description, ai = askStopDescription(active)
+					if description == "" && !ai && timesheetService.Config().DescriptionPolicy == config.DescriptionPolicyWarn {
+						fmt.Fprintf(os.Stderr, "Warning: stopping the session for %s without a description\n", active.ClientName)
+					}
+				}
+				if err := timesheetService.CheckStopDescription(ctx, active, description, ai); err != nil {
+					return err
+				}
+			}
+
+			session, err := timesheetService.StopWorkAt(ctx, endTime)
 			if err != nil {
 				return err
 			}
 
+			if session.HourlyRate == nil || session.HourlyRate.IsZero() {
+				if session, err = confirmSessionRate(ctx, timesheetService, session); err != nil {
+					return err
+				}
+			}
+
+			if timesheetService.IdleTrimEnabled() {
+				if session, err = trimIdlePeriods(ctx, timesheetService, session, trimIdle); err != nil {
+					return err
+				}
+			}
+
+			if session, err = timesheetService.DescribeStoppedSession(ctx, session, description, ai); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+
 			duration := timesheetService.CalculateDuration(session)
 
 			fmt.Printf("Stopped work session for %s\n", session.ClientName)
@@ -28,10 +85,110 @@ func newStopCmd(timesheetService *service.TimesheetService) *cobra.Command {
 			fmt.Printf("Started: %s, Ended: %s\n",
 				session.StartTime.Format("15:04:05"),
 				session.EndTime.Format("15:04:05"))
+			if session.BreakSeconds > 0 {
+				fmt.Printf("Breaks: %s\n", timesheetService.FormatDuration(time.Duration(session.BreakSeconds)*time.Second))
+			}
+			if session.Description != nil && *session.Description != "" {
+				fmt.Printf("Description: %s\n", *session.Description)
+			}
+			if err := timesheetService.ShowSessionEarnings(ctx, session); err != nil {
+				return err
+			}
 
+			client, _ := timesheetService.GetClientByID(ctx, session.ClientID)
+			showHints(cmd, sessionFinished, hintSubject{client: client, session: session})
 			return nil
 		},
+		Annotations: mutating(),
 	}
 
+	cmd.Flags().StringVar(&atTime, "at", "", "End time, e.g. 'YYYY-MM-DD HH:MM:SS', ISO8601 or 'HH:MM' (defaults to now)")
+	cmd.Flags().BoolVar(&trimIdle, "trim-idle", false, "Take idle periods found by ActivityWatch out of the session without asking")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "Describe the session as it's stopped")
+	cmd.Flags().BoolVar(&ai, "ai", false, "Generate the session's description from the git history of its time once it's stopped")
+	cmd.MarkFlagsMutuallyExclusive("description", "ai")
+
 	return cmd
 }
+
+// confirmSessionRate asks for a rate when a session stops without one, so it isn't invoiced at nothing by
+// accident. A blank answer leaves it unbilled. Nothing is asked when stdin isn't a terminal, such as in scripts.
+func confirmSessionRate(ctx context.Context, timesheetService *service.TimesheetService, session *models.WorkSession) (*models.WorkSession, error) {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return session, nil
+	}
+
+	fmt.Printf("The session for %s has no hourly rate. Rate to bill it at (blank to leave it unbilled): ", session.ClientName)
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return session, nil
+	}
+	response = strings.TrimSpace(response)
+	if response == "" {
+		return session, nil
+	}
+	rate, err := decimal.NewFromString(response)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate %q, the session was stopped without one", response)
+	}
+
+	updated, err := timesheetService.SetSessionRate(ctx, session.ID, rate, session.IncludesGst)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// askStopDescription asks for a description of a session being stopped without one, or "ai" to generate it from
+// git. A blank answer, or stdin not being a terminal, leaves it undescribed.
+func askStopDescription(session *models.WorkSession) (string, bool) {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return "", false
+	}
+
+	fmt.Printf("The session for %s has no description. Describe it, 'ai' to generate one from git, or blank to skip: ", session.ClientName)
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return "", false
+	}
+	response = strings.TrimSpace(response)
+	if strings.EqualFold(response, "ai") {
+		return "", true
+	}
+	return response, false
+}
+
+// trimIdlePeriods offers to take the idle periods ActivityWatch saw during a session out of it as breaks, or
+// takes them out without asking when trim is set. The session is still stopped if ActivityWatch can't be
+// reached, and nothing is asked when stdin isn't a terminal.
+func trimIdlePeriods(ctx context.Context, timesheetService *service.TimesheetService, session *models.WorkSession, trim bool) (*models.WorkSession, error) {
+	periods, err := timesheetService.FindIdlePeriods(ctx, session)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't check for idle time: %v\n", err)
+		return session, nil
+	}
+	if len(periods) == 0 {
+		return session, nil
+	}
+	timesheetService.DisplayIdlePeriods(periods)
+
+	if !trim {
+		if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return session, nil
+		}
+		fmt.Print("Take this time out of the session as breaks? (y/N): ")
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return session, nil
+		}
+		response = strings.ToLower(strings.TrimSpace(response))
+		if response != "y" && response != "yes" {
+			return session, nil
+		}
+	}
+
+	return timesheetService.TrimIdlePeriods(ctx, session, periods)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newTaxCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tax",
+		Short: "Tax summaries for lodging with the ATO",
+		Long:  "Commands for totalling the amounts needed to lodge tax returns and activity statements.",
+	}
+
+	cmd.AddCommand(newTaxGSTCmd(timesheetService))
+
+	return cmd
+}
+
+func newTaxGSTCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var quarter string
+	var basis string
+	var csvOutput string
+
+	cmd := &cobra.Command{
+		Use:   "gst",
+		Short: "Show GST collected and paid for a BAS quarter",
+		Long: `Total GST collected on sales and GST paid on expenses for a calendar quarter, with the BAS labels
+(G1, G2, G3, 1A, G11 and 1B) they're lodged under. Sales to clients with an export or GST-free tax treatment
+('work clients update <client> --tax-treatment') are reported under G2 and G3 as well as G1.
+
+On a cash basis sales are counted when payments are received. On an accrual basis they're counted when
+invoices are issued, less credit notes issued in the quarter. The basis defaults to GST_BASIS. Record the
+GST on an expense with 'work expenses create --gst' or 'work expenses update <expense-id> --gst'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return timesheetService.ShowGSTReport(ctx, quarter, basis, csvOutput)
+		},
+	}
+
+	cmd.Flags().StringVar(&quarter, "quarter", "", "Calendar quarter, e.g. 2025Q1 for January to March 2025 (required)")
+	cmd.Flags().StringVar(&basis, "basis", "", "Accounting basis: cash or accrual (default: GST_BASIS)")
+	cmd.Flags().StringVar(&csvOutput, "csv", "", "Also write every amount counted to this CSV file, or - for stdout")
+	cmd.MarkFlagRequired("quarter")
+
+	return cmd
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newTemplatesCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Create, list and delete session templates",
+		Long:  "Session templates save the client, description and duration of recurring sessions such as standups, to start them with 'work start --template <name>' or log them with 'work quick <name>'.",
+	}
+
+	cmd.AddCommand(newTemplatesCreateCmd(timesheetService))
+	cmd.AddCommand(newTemplatesListCmd(timesheetService))
+	cmd.AddCommand(newTemplatesDeleteCmd(timesheetService))
+
+	return cmd
+}
+
+func newTemplatesCreateCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var client, description string
+	var duration time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "create <template-name>",
+		Short: "Create a session template",
+		Long:  "Create a template for a recurring session. With a duration, 'work quick <name>' logs a session of that length ending now, otherwise it starts a session.",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&client, "client", "c", "", "Client name (required)")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "Description for sessions from the template")
+	cmd.Flags().DurationVar(&duration, "duration", 0, "Length of sessions logged with 'work quick' (e.g., 30m, 1h15m)")
+
+	cmd.MarkFlagRequired("client")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var desc *string
+		if description != "" {
+			desc = &description
+		}
+
+		template, err := timesheetService.CreateSessionTemplate(cmd.Context(), args[0], client, desc, duration)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print("Created template: ")
+		timesheetService.DisplaySessionTemplate(template)
+		return nil
+	}
+
+	return cmd
+}
+
+func newTemplatesListCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List session templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			templates, err := timesheetService.ListSessionTemplates(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if len(templates) == 0 {
+				fmt.Println("No templates found, create one with 'work templates create <name> -c <client>'")
+				return nil
+			}
+
+			fmt.Println("Templates:")
+			for _, template := range templates {
+				timesheetService.DisplaySessionTemplate(template)
+			}
+			return nil
+		},
+	}
+}
+
+func newTemplatesDeleteCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <template-name>",
+		Short: "Delete a session template",
+		Long:  "Delete a session template. Sessions already created from it are kept.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := timesheetService.DeleteSessionTemplate(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted template %s\n", args[0])
+			return nil
+		},
+		Annotations: mutating(),
+	}
+}
+
+func newQuickCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "quick <template-name>",
+		Short: "Log or start a session from a template",
+		Long:  "Log a session from a template that ends now and lasts the template's duration. Templates without a duration start a session instead, stopping any active session.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			session, err := timesheetService.QuickSession(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			if session.EndTime == nil {
+				printSessionStarted(session)
+				return nil
+			}
+
+			fmt.Printf("Logged %s session for %s, %s to %s\n",
+				timesheetService.FormatDuration(timesheetService.CalculateDuration(session)),
+				session.ClientName,
+				session.StartTime.Format("15:04"),
+				session.EndTime.Format("15:04"))
+			if session.Description != nil {
+				fmt.Printf("Description: %s\n", *session.Description)
+			}
+
+			client, _ := timesheetService.GetClientByID(ctx, session.ClientID)
+			showHints(cmd, sessionFinished, hintSubject{client: client, session: session})
+			return nil
+		},
+		Annotations: mutating(),
+	}
+}
+
+func printSessionStarted(session *models.WorkSession) {
+	fmt.Printf("Started work session for %s at %s\n",
+		session.ClientName,
+		session.StartTime.Format("15:04:05"))
+
+	if session.Description != nil {
+		fmt.Printf("Description: %s\n", *session.Description)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newTrashCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Restore or purge deleted sessions and expenses",
+		Long: `Deleted sessions and expenses go to the trash, where nothing else sees them, until they're restored or
+purged for good.`,
+	}
+
+	cmd.AddCommand(newTrashListCmd(timesheetService))
+	cmd.AddCommand(newTrashRestoreCmd(timesheetService))
+	cmd.AddCommand(newTrashPurgeCmd(timesheetService))
+
+	return cmd
+}
+
+func newTrashListCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List deleted sessions and expenses",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.DisplayTrash(cmd.Context())
+		},
+	}
+}
+
+func newTrashRestoreCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <id>",
+		Short: "Restore a deleted session or expense",
+		Long: `Restore a deleted session or expense by its ID, or the start of it as shown by 'work trash list'. An invoice
+a restored session was on is flagged for regeneration.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			restored, err := timesheetService.RestoreFromTrash(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Restored %s\n", restored)
+			return timesheetService.DisplayInvoicesNeedingRegeneration(cmd.Context())
+		},
+		Annotations: mutating(),
+	}
+}
+
+func newTrashPurgeCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var olderThan string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Permanently delete what's been in the trash a while",
+		Long:  "Permanently delete the sessions and expenses that have been in the trash for longer than --older-than. This can't be undone.",
+		Example: `  work trash purge
+  work trash purge --older-than 7d
+  work trash purge --older-than 0d --force`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.Flags().StringVar(&olderThan, "older-than", service.DefaultTrashPurgeAge, "Only purge what was deleted longer ago than this, e.g. 30d, 2w or 12h")
+	cmd.Flags().BoolVar(&force, "force", false, "Skip confirmation prompt")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		age, err := service.ParseTrashAge(olderThan)
+		if err != nil {
+			return err
+		}
+
+		if !force {
+			fmt.Printf("This will permanently delete sessions and expenses deleted more than %s ago. Are you sure? (y/N): ", olderThan)
+			reader := bufio.NewReader(os.Stdin)
+			response, err := reader.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			response = strings.ToLower(strings.TrimSpace(response))
+			if response != "y" && response != "yes" {
+				fmt.Println("Operation cancelled.")
+				return nil
+			}
+		}
+
+		sessions, expenses, err := timesheetService.PurgeTrash(cmd.Context(), age)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Purged %d sessions and %d expenses from the trash\n", sessions, expenses)
+		return nil
+	}
+
+	return cmd
+}
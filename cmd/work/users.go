@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newUsersCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "users",
+		Short: "Create, list and update the people sessions are attributed to",
+		Long: `Users are the people other than you who work on client sessions, such as subcontractors. Sessions started
+on a machine with WORK_USER set are attributed to that user, and 'work sessions create --user' attributes one by
+hand. With a cost rate, 'work invoices show' compares what their hours were billed at with what they cost.`,
+	}
+
+	cmd.AddCommand(newUsersCreateCmd(timesheetService))
+	cmd.AddCommand(newUsersListCmd(timesheetService))
+	cmd.AddCommand(newUsersUpdateCmd(timesheetService))
+
+	return cmd
+}
+
+func newUsersCreateCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var costRate float64
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Add a user",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().Float64Var(&costRate, "cost-rate", 0, "What an hour of their time costs you, e.g. a subcontractor's rate")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var rate *decimal.Decimal
+		if costRate > 0 {
+			r := decimal.NewFromFloat(costRate)
+			rate = &r
+		}
+
+		user, err := timesheetService.CreateUser(cmd.Context(), args[0], rate)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created user %s\n", user.Name)
+		return nil
+	}
+
+	return cmd
+}
+
+func newUsersListCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List users and their cost rates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return timesheetService.DisplayUsers(cmd.Context())
+		},
+	}
+}
+
+func newUsersUpdateCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var name string
+	var costRate float64
+
+	cmd := &cobra.Command{
+		Use:   "update <name>",
+		Short: "Rename a user or change their cost rate",
+		Args:  cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "New name for the user")
+	cmd.Flags().Float64Var(&costRate, "cost-rate", 0, "What an hour of their time costs you")
+
+	cmd.Annotations = mutating()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var newName *string
+		if name != "" {
+			newName = &name
+		}
+		var rate *decimal.Decimal
+		if cmd.Flags().Changed("cost-rate") {
+			r := decimal.NewFromFloat(costRate)
+			rate = &r
+		}
+		if newName == nil && rate == nil {
+			return fmt.Errorf("nothing to update, give --name or --cost-rate")
+		}
+
+		user, err := timesheetService.UpdateUser(cmd.Context(), args[0], newName, rate)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Updated user %s\n", user.Name)
+		return nil
+	}
+
+	return cmd
+}
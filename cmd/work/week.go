@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jesses-code-adventures/work/internal/logging"
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+func newWeekCmd(timesheetService *service.TimesheetService) *cobra.Command {
+	var date string
+	var user string
+
+	cmd := &cobra.Command{
+		Use:   "week",
+		Short: "Show a calendar of the week's tracked time",
+		Long: `Show a Monday to Sunday grid of tracked time, one row per day with each half hour marked by the client
+worked on and the day's total hours, to spot days that weren't fully tracked. Past weekdays with nothing
+tracked are flagged as untracked, unless they were leave recorded with 'work leave add'. Clients are colour
+coded on terminals that support it (set NO_COLOR to disable) and lettered otherwise.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetDate := time.Now()
+			if date != "" {
+				var err error
+				targetDate, err = time.ParseInLocation("2006-01-02", date, time.Local)
+				if err != nil {
+					return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+				}
+			}
+
+			colour := os.Getenv("NO_COLOR") == "" && logging.NewTerminal(os.Stdout).IsTTY()
+			return timesheetService.ShowWeek(cmd.Context(), targetDate, user, colour)
+		},
+	}
+
+	cmd.Flags().StringVarP(&date, "date", "d", "", "Date in the week to show (YYYY-MM-DD), defaults to today")
+	cmd.Flags().StringVarP(&user, "user", "u", "", "Only show sessions by this user, or '"+service.OwnerUser+"' for your own")
+
+	return cmd
+}
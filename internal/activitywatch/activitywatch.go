@@ -0,0 +1,145 @@
+// Package activitywatch reads when the computer was idle from a local ActivityWatch server, so idle time can
+// be taken out of work sessions.
+package activitywatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// afkBucketType is the type of bucket the AFK watcher reports idle and active periods to
+const afkBucketType = "afkstatus"
+
+// Period is a span of time the computer was idle
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration is how long the period lasted
+func (p Period) Duration() time.Duration {
+	return p.End.Sub(p.Start)
+}
+
+// Client reads from the ActivityWatch REST API
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient returns a client for the ActivityWatch server at baseURL, e.g. http://localhost:5600
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// IdlePeriods returns the periods between from and to that the AFK watcher on hostname reported the computer
+// as idle for at least minimum, oldest first. Periods are clipped to from and to, and overlapping ones merged.
+func (c *Client) IdlePeriods(ctx context.Context, hostname string, from, to time.Time, minimum time.Duration) ([]Period, error) {
+	bucket, err := c.afkBucket(ctx, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []struct {
+		Timestamp time.Time `json:"timestamp"`
+		Duration  float64   `json:"duration"` // seconds
+		Data      struct {
+			Status string `json:"status"`
+		} `json:"data"`
+	}
+	query := url.Values{
+		"start": {from.UTC().Format(time.RFC3339)},
+		"end":   {to.UTC().Format(time.RFC3339)},
+	}
+	if err := c.get(ctx, "/api/0/buckets/"+url.PathEscape(bucket)+"/events?"+query.Encode(), &events); err != nil {
+		return nil, err
+	}
+
+	var periods []Period
+	for _, event := range events {
+		if event.Data.Status != "afk" {
+			continue
+		}
+		start := event.Timestamp.In(from.Location())
+		end := start.Add(time.Duration(event.Duration * float64(time.Second)))
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			periods = append(periods, Period{Start: start, End: end})
+		}
+	}
+
+	var idle []Period
+	for _, period := range merge(periods) {
+		if period.Duration() >= minimum {
+			idle = append(idle, period)
+		}
+	}
+	return idle, nil
+}
+
+// afkBucket finds the AFK watcher's bucket for hostname
+func (c *Client) afkBucket(ctx context.Context, hostname string) (string, error) {
+	var buckets map[string]struct {
+		Type     string `json:"type"`
+		Hostname string `json:"hostname"`
+	}
+	if err := c.get(ctx, "/api/0/buckets/", &buckets); err != nil {
+		return "", err
+	}
+	for id, bucket := range buckets {
+		if bucket.Type == afkBucketType && strings.EqualFold(bucket.Hostname, hostname) {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("no ActivityWatch AFK watcher found for %s, is aw-watcher-afk running?", hostname)
+}
+
+func (c *Client) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create ActivityWatch request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ActivityWatch request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("ActivityWatch returned %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to read ActivityWatch response: %w", err)
+	}
+	return nil
+}
+
+// merge joins overlapping or touching periods
+func merge(periods []Period) []Period {
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Start.Before(periods[j].Start) })
+	var merged []Period
+	for _, period := range periods {
+		if last := len(merged) - 1; last >= 0 && !period.Start.After(merged[last].End) {
+			if period.End.After(merged[last].End) {
+				merged[last].End = period.End
+			}
+			continue
+		}
+		merged = append(merged, period)
+	}
+	return merged
+}
@@ -1,10 +1,22 @@
 package config
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"net/url"
 	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
 )
 
 type Config struct {
@@ -12,6 +24,7 @@ type Config struct {
 	DatabasePath         string
 	DatabaseURL          string
 	DatabaseDriver       string
+	DatabaseSource       string // where DatabaseURL was set, shown by doctor and config
 	TempDir              string
 	GitAnalysisPrompt    string
 	DevMode              bool
@@ -23,14 +36,201 @@ type Config struct {
 	BillingACN           string
 	BillingCompanyName   string
 	GSTRegistered        bool
+	BillingCurrency      string
+	BillingLocale        string
+	AnalysisConcurrency  int
+	AnalysisRateLimits   map[string]int // calls per minute, keyed by provider command
+	AnalysisTimeout      time.Duration  // how long one opencode run can take before it's stopped, 0 for no limit
+	AnalysisRetries      int            // times an opencode run that fails or times out is retried, with backoff
+	AnalysisMaxCommits   int            // most recent commits in each repository given to the AI, 0 for no limit
+	AnalysisIgnore       []string       // paths whose changes are left out of analysis, e.g. vendor/ and *.lock
+	AnalysisDetail       string         // GitAnalysisDiffs or GitAnalysisMessages
+	MinimumRate          decimal.Decimal
+	TargetWeeklyHours    decimal.Decimal // billable hours aimed for in a full working week, zero when not set
+	MileageRate          decimal.Decimal // per kilometre, for travel expenses
+	GSTRounding          string          // GSTRoundingInvoice or GSTRoundingLine
+	GSTBasis             string          // GSTBasisCash or GSTBasisAccrual, how GST is accounted for on the BAS
+	FinancialYearStart   time.Month      // first month of the financial year, which quarters and years line up with
+	NotionToken          string          // integration token for publishing work logs to Notion
+	ConfluenceURL        string          // site URL for publishing work logs to Confluence, e.g. https://acme.atlassian.net
+	ConfluenceEmail      string
+	ConfluenceAPIToken   string
+	SMTPHost             string // server reports are emailed through, e.g. smtp.fastmail.com
+	SMTPPort             int
+	SMTPUsername         string
+	SMTPPassword         string
+	SMTPFrom             string              // address reports are sent from
+	ReportEmail          string              // address reports are sent to, defaulting to SMTPFrom
+	Webhooks             []Webhook           // posted to when sessions start or stop and invoices are generated or paid
+	StatusAPIKey         string              // key requests to 'work serve --status-only' must send, which won't start without one
+	SyncInterval         time.Duration       // how often an embedded replica syncs in the background while online
+	ConnectTimeout       time.Duration       // how long to wait for a remote database before it's unreachable
+	DatabaseSnapshot     string              // read-only local copy of a remote database used while it's unreachable, empty for none
+	EncryptionKey        []byte              // AES-256 key for client contact details in the database, nil to store them as plaintext
+	EncryptionKeySource  string              // "ENCRYPTION_KEY" or "keychain"
+	DurationFormats      map[string]string   // duration format for each of DurationContexts
+	DurationPrecision    int                 // decimal places decimal durations are rounded to, e.g. 2 for 3.45h
+	RetainerProration    string              // RetainerProrateDays, RetainerProrateWeekdays or RetainerProrateNone
+	RateCardRoles        []RateCardRole      // roles offered on rate cards, in the order they're listed
+	RateCardTerms        []string            // standard terms listed on rate cards
+	IdleTrimThreshold    time.Duration       // idle periods at least this long can be taken out of sessions when stopping, 0 disables
+	ActivityWatchURL     string              // where the ActivityWatch server idle time is read from listens
+	ClientMatching       string              // ClientMatchingFuzzy or ClientMatchingStrict
+	AutoIdleTimeout      time.Duration       // how long after the last prompt in a client directory an automatically started session stops
+	SQLiteJournalMode    string              // journal_mode pragma for local sqlite3 databases, WAL so reads don't wait on writes
+	SQLiteBusyTimeout    time.Duration       // busy_timeout pragma, how long a statement waits for another process's write
+	SQLiteBusyRetries    int                 // times a statement still blocked after SQLiteBusyTimeout is retried, with backoff
+	WorkUser             string              // user sessions started on this machine are attributed to, empty for the owner
+	SessionMetadata      bool                // record the hostname, OS and git branch sessions are started on
+	InvoiceNoteTimes     bool                // show the time each session note was added on invoices
+	InvoiceDaySubtotals  bool                // add a subtotal row after each day with more than one session on invoices
+	BillingLogo          string              // PNG, JPEG or GIF printed on invoices for clients without a logo of their own
+	BillingBrandColour   string              // #rrggbb colour of invoice headings for clients without a colour of their own
+	DescriptionPolicy    string              // DescriptionPolicyOff, DescriptionPolicyWarn or DescriptionPolicyBlock
+	PaymentDueDays       int                 // days after issue invoices are due when their payment terms don't say
+	Aliases              map[string][]string // commands run by a name of your own, e.g. "s" runs start -c acme
+	DefaultFlags         map[string][]string // flags given to a command by default, keyed by its path, e.g. "sessions list"
 }
 
-func Load(dbConn, dbDriver, gitPrompt, devMode, billingBank, billingAccountName, billingAccountNumber, billingBSB, billingABN, billingACN, billingCompanyName, gstRegistered string) (*Config, error) {
+// RateCardRole is a role offered in proposals and its hourly rate in the billing currency
+type RateCardRole struct {
+	Name string
+	Rate decimal.Decimal
+}
+
+// Webhook posts events to a URL, such as a Slack or Discord incoming webhook
+type Webhook struct {
+	Event    string // one of WebhookEvents, or "*" for every event
+	URL      string
+	Template *template.Template // renders the request body from the event, or nil to send the event as JSON
+}
+
+// WebhookEvents are the events webhooks can be configured for
+var WebhookEvents = []string{"session.started", "session.stopped", "invoice.generated", "invoice.paid"}
+
+// GST rounding methods accepted by the ATO. Either can be used as long as it's used consistently.
+const (
+	// GSTRoundingInvoice rounds the GST on the invoice as a whole, once
+	GSTRoundingInvoice = "invoice"
+	// GSTRoundingLine rounds the GST on each session, retainer and expense line, and adds them up
+	GSTRoundingLine = "line"
+)
+
+// GST accounting bases for the BAS. Small businesses usually account on a cash basis.
+const (
+	// GSTBasisCash reports GST when payments are received
+	GSTBasisCash = "cash"
+	// GSTBasisAccrual reports GST when invoices are issued
+	GSTBasisAccrual = "accrual"
+)
+
+// How a retainer that starts partway through a period is billed for that first period
+const (
+	// RetainerProrateDays bills the share of the period's calendar days the retainer was in place for
+	RetainerProrateDays = "days"
+	// RetainerProrateWeekdays bills the share of the period's weekdays the retainer was in place for
+	RetainerProrateWeekdays = "weekdays"
+	// RetainerProrateNone bills the full retainer however late in the period it started
+	RetainerProrateNone = "none"
+)
+
+// sqliteJournalModes are the journal modes SQLite accepts
+var sqliteJournalModes = []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+
+// How client names given on the command line are matched against existing clients
+const (
+	// ClientMatchingFuzzy accepts names that differ in case, a prefix or a close spelling, asking which client
+	// was meant when more than one matches
+	ClientMatchingFuzzy = "fuzzy"
+	// ClientMatchingStrict only accepts a client's exact name, for scripts
+	ClientMatchingStrict = "strict"
+)
+
+// What happens when a session without a description or notes is stopped
+const (
+	// DescriptionPolicyOff stops the session, only hinting at how to describe it
+	DescriptionPolicyOff = "off"
+	// DescriptionPolicyWarn asks for a description and warns when the session is stopped without one
+	DescriptionPolicyWarn = "warn"
+	// DescriptionPolicyBlock keeps the session running until it's described or its description generated
+	DescriptionPolicyBlock = "block"
+)
+
+// How much of each commit the AI is given to describe sessions
+const (
+	// GitAnalysisDiffs gives commit messages and their diffs
+	GitAnalysisDiffs = "diffs"
+	// GitAnalysisMessages gives only commit messages, which is much cheaper and faster on large repositories
+	GitAnalysisMessages = "messages"
+)
+
+// Duration formats for showing how long was worked
+const (
+	// DurationHoursMinutes shows hours and minutes, e.g. 2h 15m
+	DurationHoursMinutes = "hm"
+	// DurationDecimal shows decimal hours, e.g. 2.25h
+	DurationDecimal = "decimal"
+	// DurationClock shows hours and minutes like a clock, e.g. 2:15
+	DurationClock = "clock"
+	// DurationVerbose spells out hours and minutes, e.g. 2 hours 15 minutes
+	DurationVerbose = "verbose"
+)
+
+// Places durations are shown, which can each have their own format
+const (
+	// DurationContextStatus is start, stop, status and other messages about a single session
+	DurationContextStatus = "status"
+	// DurationContextList is session and template lists, and work logs published for invoices
+	DurationContextList = "list"
+	// DurationContextReport is hours, week and unbilled totals
+	DurationContextReport = "report"
+	// DurationContextInvoice is the session lines on invoice PDFs
+	DurationContextInvoice = "invoice"
+	// DurationContextExport is the duration column of CSV exports
+	DurationContextExport = "export"
+)
+
+// DurationContexts are the places durations are shown, in the order they're dumped
+var DurationContexts = []string{DurationContextStatus, DurationContextList, DurationContextReport, DurationContextInvoice, DurationContextExport}
+
+// defaultDurationFormats are used when neither DURATION_FORMAT nor a context's own setting is set
+var defaultDurationFormats = map[string]string{
+	DurationContextStatus:  DurationHoursMinutes,
+	DurationContextList:    DurationHoursMinutes,
+	DurationContextReport:  DurationDecimal,
+	DurationContextInvoice: DurationDecimal,
+	DurationContextExport:  DurationDecimal,
+}
+
+// defaultMileageRate is the ATO cents per kilometre rate for work related car expenses
+const defaultMileageRate = "0.88"
+
+// defaultActivityWatchURL is where ActivityWatch serves its API with the default settings
+const defaultActivityWatchURL = "http://localhost:5600"
+
+// defaultFinancialYearStart is the start of the Australian financial year
+const defaultFinancialYearStart = time.July
+
+// LoadFromEnv reads the configuration from the environment and any .env file alone, for programs without
+// settings baked in at build time like the CLI's
+func LoadFromEnv() (*Config, error) {
+	return Load("", "", "", "", "", "", "", "", "", "", "", "", "", "")
+}
+
+func Load(dbConn, dbDriver, gitPrompt, devMode, billingBank, billingAccountName, billingAccountNumber, billingBSB, billingABN, billingACN, billingCompanyName, gstRegistered, billingCurrency, billingLocale string) (*Config, error) {
 	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
 		return nil, fmt.Errorf("error loading .env file: %w", err)
 	}
 
-	if dbConn == "" {
+	// WORK_DB switches between databases, e.g. one per business year, so it wins over the build time connection
+	dbSource := "build"
+	if workDB := os.Getenv("WORK_DB"); workDB != "" {
+		dbConn, dbSource = workDB, "WORK_DB"
+	} else if dbConn == "" {
+		dbSource = "DATABASE_URL"
+		if os.Getenv("DATABASE_URL") == "" {
+			dbSource = "default"
+		}
 		dbConn = getEnv("DATABASE_URL", "./work.db")
 	}
 
@@ -74,14 +274,183 @@ func Load(dbConn, dbDriver, gitPrompt, devMode, billingBank, billingAccountName,
 		gstRegistered = getEnv("GST_REGISTERED", "false")
 	}
 
+	if billingCurrency == "" {
+		billingCurrency = getEnv("BILLING_CURRENCY", "AUD")
+	}
+
+	if billingLocale == "" {
+		billingLocale = getEnv("BILLING_LOCALE", "en-AU")
+	}
+
 	// Dev mode defaults to true for local builds, false for prod
 	isDevMode := devMode == "true" || (devMode == "" && getEnv("DEV_MODE", "true") == "true")
 	isGSTRegistered := gstRegistered == "true" || (gstRegistered == "" && getEnv("GST_REGISTERED", "false") == "true")
 
+	analysisConcurrency, err := strconv.Atoi(getEnv("ANALYSIS_CONCURRENCY", "4"))
+	if err != nil || analysisConcurrency < 1 {
+		return nil, fmt.Errorf("ANALYSIS_CONCURRENCY must be a positive integer, got %q", os.Getenv("ANALYSIS_CONCURRENCY"))
+	}
+
+	analysisRateLimits, err := parseRateLimits(getEnv("ANALYSIS_RATE_LIMITS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ANALYSIS_RATE_LIMITS: %w", err)
+	}
+
+	analysisTimeout, err := time.ParseDuration(getEnv("ANALYSIS_TIMEOUT", "5m"))
+	if err != nil || analysisTimeout < 0 {
+		return nil, fmt.Errorf("ANALYSIS_TIMEOUT must be a duration such as 5m (0 for no limit), got %q", os.Getenv("ANALYSIS_TIMEOUT"))
+	}
+
+	analysisRetries, err := strconv.Atoi(getEnv("ANALYSIS_RETRIES", "2"))
+	if err != nil || analysisRetries < 0 {
+		return nil, fmt.Errorf("ANALYSIS_RETRIES must be a whole number (0 disables), got %q", os.Getenv("ANALYSIS_RETRIES"))
+	}
+
+	analysisMaxCommits, err := strconv.Atoi(getEnv("GIT_ANALYSIS_MAX_COMMITS", "0"))
+	if err != nil || analysisMaxCommits < 0 {
+		return nil, fmt.Errorf("GIT_ANALYSIS_MAX_COMMITS must be a whole number (0 for no limit), got %q", os.Getenv("GIT_ANALYSIS_MAX_COMMITS"))
+	}
+
+	analysisDetail, err := ParseGitAnalysisDetail(getEnv("GIT_ANALYSIS_DETAIL", GitAnalysisDiffs))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GIT_ANALYSIS_DETAIL: %w", err)
+	}
+
+	minimumRate, err := decimal.NewFromString(getEnv("MINIMUM_RATE", "0"))
+	if err != nil || minimumRate.IsNegative() {
+		return nil, fmt.Errorf("MINIMUM_RATE must be a non-negative amount, got %q", os.Getenv("MINIMUM_RATE"))
+	}
+
+	targetWeeklyHours, err := decimal.NewFromString(getEnv("TARGET_WEEKLY_HOURS", "0"))
+	if err != nil || targetWeeklyHours.IsNegative() || targetWeeklyHours.GreaterThan(decimal.NewFromInt(168)) {
+		return nil, fmt.Errorf("TARGET_WEEKLY_HOURS must be a number of hours from 0 to 168, got %q", os.Getenv("TARGET_WEEKLY_HOURS"))
+	}
+
+	mileageRate, err := decimal.NewFromString(getEnv("MILEAGE_RATE", defaultMileageRate))
+	if err != nil || !mileageRate.IsPositive() {
+		return nil, fmt.Errorf("MILEAGE_RATE must be a positive amount per kilometre, got %q", os.Getenv("MILEAGE_RATE"))
+	}
+
+	gstRounding := strings.ToLower(getEnv("GST_ROUNDING", GSTRoundingInvoice))
+	if gstRounding != GSTRoundingInvoice && gstRounding != GSTRoundingLine {
+		return nil, fmt.Errorf("GST_ROUNDING must be %q or %q, got %q", GSTRoundingInvoice, GSTRoundingLine, os.Getenv("GST_ROUNDING"))
+	}
+
+	gstBasis := strings.ToLower(getEnv("GST_BASIS", GSTBasisCash))
+	if gstBasis != GSTBasisCash && gstBasis != GSTBasisAccrual {
+		return nil, fmt.Errorf("GST_BASIS must be %q or %q, got %q", GSTBasisCash, GSTBasisAccrual, os.Getenv("GST_BASIS"))
+	}
+
+	fyStart, err := strconv.Atoi(getEnv("FY_START_MONTH", strconv.Itoa(int(defaultFinancialYearStart))))
+	if err != nil || fyStart < 1 || fyStart > 12 {
+		return nil, fmt.Errorf("FY_START_MONTH must be a month number from 1 to 12, got %q", os.Getenv("FY_START_MONTH"))
+	}
+
+	webhooks, err := parseWebhooks(getEnv("WEBHOOK_URLS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_URLS: %w", err)
+	}
+
+	syncInterval, err := time.ParseDuration(getEnv("SYNC_INTERVAL", "1m"))
+	if err != nil || syncInterval <= 0 {
+		return nil, fmt.Errorf("SYNC_INTERVAL must be a positive duration such as 30s or 5m, got %q", os.Getenv("SYNC_INTERVAL"))
+	}
+
+	connectTimeout, err := time.ParseDuration(getEnv("DB_CONNECT_TIMEOUT", "5s"))
+	if err != nil || connectTimeout <= 0 {
+		return nil, fmt.Errorf("DB_CONNECT_TIMEOUT must be a positive duration such as 5s, got %q", os.Getenv("DB_CONNECT_TIMEOUT"))
+	}
+
+	encryptionKey, encryptionKeySource, err := loadEncryptionKey(getEnv("ENCRYPTION_KEY", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	durationFormats, err := parseDurationFormats()
+	if err != nil {
+		return nil, err
+	}
+
+	durationPrecision, err := strconv.Atoi(getEnv("DURATION_PRECISION", "2"))
+	if err != nil || durationPrecision < 0 || durationPrecision > 4 {
+		return nil, fmt.Errorf("DURATION_PRECISION must be a number of decimal places from 0 to 4, got %q", os.Getenv("DURATION_PRECISION"))
+	}
+
+	retainerProration := strings.ToLower(getEnv("RETAINER_PRORATION", RetainerProrateDays))
+	if retainerProration != RetainerProrateDays && retainerProration != RetainerProrateWeekdays && retainerProration != RetainerProrateNone {
+		return nil, fmt.Errorf("RETAINER_PRORATION must be %q, %q or %q, got %q", RetainerProrateDays, RetainerProrateWeekdays, RetainerProrateNone, os.Getenv("RETAINER_PRORATION"))
+	}
+
+	rateCardRoles, err := parseRateCardRoles(getEnv("RATE_CARD_ROLES", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_CARD_ROLES: %w", err)
+	}
+
+	idleTrimMinutes, err := strconv.Atoi(getEnv("IDLE_TRIM_MINUTES", "0"))
+	if err != nil || idleTrimMinutes < 0 {
+		return nil, fmt.Errorf("IDLE_TRIM_MINUTES must be a whole number of minutes (0 disables), got %q", os.Getenv("IDLE_TRIM_MINUTES"))
+	}
+
+	descriptionPolicy := strings.ToLower(getEnv("DESCRIPTION_POLICY", DescriptionPolicyOff))
+	if descriptionPolicy != DescriptionPolicyOff && descriptionPolicy != DescriptionPolicyWarn && descriptionPolicy != DescriptionPolicyBlock {
+		return nil, fmt.Errorf("DESCRIPTION_POLICY must be %q, %q or %q, got %q", DescriptionPolicyOff, DescriptionPolicyWarn, DescriptionPolicyBlock, os.Getenv("DESCRIPTION_POLICY"))
+	}
+
+	paymentDueDays, err := strconv.Atoi(getEnv("PAYMENT_DUE_DAYS", "14"))
+	if err != nil || paymentDueDays < 0 {
+		return nil, fmt.Errorf("PAYMENT_DUE_DAYS must be a whole number of days (0 for due on issue), got %q", os.Getenv("PAYMENT_DUE_DAYS"))
+	}
+
+	clientMatching := strings.ToLower(getEnv("CLIENT_MATCHING", ClientMatchingFuzzy))
+	if clientMatching != ClientMatchingFuzzy && clientMatching != ClientMatchingStrict {
+		return nil, fmt.Errorf("CLIENT_MATCHING must be %q or %q, got %q", ClientMatchingFuzzy, ClientMatchingStrict, os.Getenv("CLIENT_MATCHING"))
+	}
+
+	autoIdleMinutes, err := strconv.Atoi(getEnv("AUTO_IDLE_MINUTES", "30"))
+	if err != nil || autoIdleMinutes < 1 {
+		return nil, fmt.Errorf("AUTO_IDLE_MINUTES must be a positive whole number of minutes, got %q", os.Getenv("AUTO_IDLE_MINUTES"))
+	}
+
+	journalMode := strings.ToUpper(getEnv("SQLITE_JOURNAL_MODE", "WAL"))
+	if !slices.Contains(sqliteJournalModes, journalMode) {
+		return nil, fmt.Errorf("SQLITE_JOURNAL_MODE must be one of %s, got %q", strings.Join(sqliteJournalModes, ", "), os.Getenv("SQLITE_JOURNAL_MODE"))
+	}
+
+	busyTimeout, err := time.ParseDuration(getEnv("SQLITE_BUSY_TIMEOUT", "5s"))
+	if err != nil || busyTimeout < 0 {
+		return nil, fmt.Errorf("SQLITE_BUSY_TIMEOUT must be a duration such as 5s, got %q", os.Getenv("SQLITE_BUSY_TIMEOUT"))
+	}
+
+	busyRetries, err := strconv.Atoi(getEnv("SQLITE_BUSY_RETRIES", "3"))
+	if err != nil || busyRetries < 0 {
+		return nil, fmt.Errorf("SQLITE_BUSY_RETRIES must be a whole number (0 disables), got %q", os.Getenv("SQLITE_BUSY_RETRIES"))
+	}
+
+	smtpPort, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil || smtpPort < 1 || smtpPort > 65535 {
+		return nil, fmt.Errorf("SMTP_PORT must be a port number, got %q", os.Getenv("SMTP_PORT"))
+	}
+	smtpFrom := getEnv("SMTP_FROM", "")
+	aliases, err := parseCommandArgs(getEnv("WORK_ALIASES", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WORK_ALIASES: %w", err)
+	}
+	defaultFlags, err := parseCommandArgs(getEnv("WORK_DEFAULT_FLAGS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WORK_DEFAULT_FLAGS: %w", err)
+	}
+	var brandColour string
+	if value := getEnv("BILLING_BRAND_COLOUR", ""); value != "" {
+		if brandColour, err = ParseColour(value); err != nil {
+			return nil, fmt.Errorf("invalid BILLING_BRAND_COLOUR: %w", err)
+		}
+	}
+
 	cfg := &Config{
 		DatabaseName:         getEnv("DATABASE_NAME", "work"),
 		DatabaseURL:          dbConn,
 		DatabaseDriver:       dbDriver,
+		DatabaseSource:       dbSource,
 		GitAnalysisPrompt:    gitPrompt,
 		DevMode:              isDevMode,
 		BillingBank:          billingBank,
@@ -92,15 +461,429 @@ func Load(dbConn, dbDriver, gitPrompt, devMode, billingBank, billingAccountName,
 		BillingACN:           billingACN,
 		BillingCompanyName:   billingCompanyName,
 		GSTRegistered:        isGSTRegistered,
+		BillingCurrency:      billingCurrency,
+		BillingLocale:        billingLocale,
+		AnalysisConcurrency:  analysisConcurrency,
+		AnalysisRateLimits:   analysisRateLimits,
+		AnalysisTimeout:      analysisTimeout,
+		AnalysisRetries:      analysisRetries,
+		AnalysisMaxCommits:   analysisMaxCommits,
+		AnalysisIgnore:       ParseIgnorePatterns(getEnv("GIT_ANALYSIS_IGNORE", "")),
+		AnalysisDetail:       analysisDetail,
+		MinimumRate:          minimumRate,
+		TargetWeeklyHours:    targetWeeklyHours,
+		MileageRate:          mileageRate,
+		GSTRounding:          gstRounding,
+		GSTBasis:             gstBasis,
+		FinancialYearStart:   time.Month(fyStart),
+		NotionToken:          getEnv("NOTION_TOKEN", ""),
+		ConfluenceURL:        getEnv("CONFLUENCE_URL", ""),
+		ConfluenceEmail:      getEnv("CONFLUENCE_EMAIL", ""),
+		ConfluenceAPIToken:   getEnv("CONFLUENCE_API_TOKEN", ""),
+		SMTPHost:             getEnv("SMTP_HOST", ""),
+		SMTPPort:             smtpPort,
+		SMTPUsername:         getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:         getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:             smtpFrom,
+		ReportEmail:          getEnv("REPORT_EMAIL", smtpFrom),
+		Webhooks:             webhooks,
+		StatusAPIKey:         getEnv("STATUS_API_KEY", ""),
+		SyncInterval:         syncInterval,
+		ConnectTimeout:       connectTimeout,
+		DatabaseSnapshot:     getEnv("DB_SNAPSHOT", ""),
+		EncryptionKey:        encryptionKey,
+		EncryptionKeySource:  encryptionKeySource,
+		DurationFormats:      durationFormats,
+		DurationPrecision:    durationPrecision,
+		RetainerProration:    retainerProration,
+		RateCardRoles:        rateCardRoles,
+		RateCardTerms:        parseRateCardTerms(getEnv("RATE_CARD_TERMS", "")),
+		IdleTrimThreshold:    time.Duration(idleTrimMinutes) * time.Minute,
+		ActivityWatchURL:     getEnv("ACTIVITYWATCH_URL", defaultActivityWatchURL),
+		ClientMatching:       clientMatching,
+		AutoIdleTimeout:      time.Duration(autoIdleMinutes) * time.Minute,
+		SQLiteJournalMode:    journalMode,
+		SQLiteBusyTimeout:    busyTimeout,
+		SQLiteBusyRetries:    busyRetries,
+		WorkUser:             getEnv("WORK_USER", ""),
+		SessionMetadata:      getEnv("SESSION_METADATA", "true") == "true",
+		InvoiceNoteTimes:     getEnv("INVOICE_NOTE_TIMES", "false") == "true",
+		InvoiceDaySubtotals:  getEnv("INVOICE_DAY_SUBTOTALS", "false") == "true",
+		BillingLogo:          getEnv("BILLING_LOGO", ""),
+		BillingBrandColour:   brandColour,
+		DescriptionPolicy:    descriptionPolicy,
+		PaymentDueDays:       paymentDueDays,
+		Aliases:              aliases,
+		DefaultFlags:         defaultFlags,
 	}
 
 	return cfg, nil
 }
 
+// UseDatabase points the config at another database, such as one given with the --db flag
+func (c *Config) UseDatabase(url, source string) {
+	c.DatabaseURL = url
+	c.DatabaseSource = source
+}
+
 func (c *Config) Dump() {
-	fmt.Printf("Database Name: %s\n", c.DatabaseName)
-	fmt.Printf("Database URL: %s\n", c.DatabaseURL)
+	if c.DatabaseName != "" {
+		fmt.Printf("Database Name: %s\n", c.DatabaseName)
+	}
+	fmt.Printf("Database URL: %s (from %s)\n", c.DatabaseURL, c.DatabaseSource)
 	fmt.Printf("Database Driver: %s\n", c.DatabaseDriver)
+	if c.DatabaseDriver == "sqlite3" {
+		fmt.Printf("SQLite Journal Mode: %s\n", c.SQLiteJournalMode)
+		fmt.Printf("SQLite Busy Timeout: %s (%d retries)\n", c.SQLiteBusyTimeout, c.SQLiteBusyRetries)
+	}
+	if c.WorkUser != "" {
+		fmt.Printf("Work User: %s\n", c.WorkUser)
+	}
+	fmt.Printf("Session Metadata: %t\n", c.SessionMetadata)
+	fmt.Printf("Invoice Note Times: %t\n", c.InvoiceNoteTimes)
+	fmt.Printf("Invoice Day Subtotals: %t\n", c.InvoiceDaySubtotals)
+	if c.BillingLogo != "" {
+		fmt.Printf("Billing Logo: %s\n", c.BillingLogo)
+	}
+	if c.BillingBrandColour != "" {
+		fmt.Printf("Billing Brand Colour: %s\n", c.BillingBrandColour)
+	}
+	fmt.Printf("Replica Sync Interval: %s\n", c.SyncInterval)
+	fmt.Printf("Connect Timeout: %s\n", c.ConnectTimeout)
+	if c.DatabaseSnapshot != "" {
+		fmt.Printf("Database Snapshot: %s\n", c.DatabaseSnapshot)
+	}
+	if c.AnalysisTimeout > 0 {
+		fmt.Printf("Analysis Timeout: %s (%d retries)\n", c.AnalysisTimeout, c.AnalysisRetries)
+	} else {
+		fmt.Printf("Analysis Timeout: none (%d retries)\n", c.AnalysisRetries)
+	}
+	if c.AnalysisMaxCommits > 0 {
+		fmt.Printf("Git Analysis Max Commits: %d\n", c.AnalysisMaxCommits)
+	}
+	if len(c.AnalysisIgnore) > 0 {
+		fmt.Printf("Git Analysis Ignore: %s\n", strings.Join(c.AnalysisIgnore, ", "))
+	}
+	fmt.Printf("Git Analysis Detail: %s\n", c.AnalysisDetail)
+	if c.EncryptionKey != nil {
+		fmt.Printf("Field Encryption: true (key from %s)\n", c.EncryptionKeySource)
+	} else {
+		fmt.Printf("Field Encryption: false\n")
+	}
+	fmt.Printf("Billing Currency: %s\n", c.BillingCurrency)
+	fmt.Printf("Billing Locale: %s\n", c.BillingLocale)
+	fmt.Printf("GST Registered: %t\n", c.GSTRegistered)
+	fmt.Printf("GST Rounding: %s\n", c.GSTRounding)
+	fmt.Printf("GST Basis: %s\n", c.GSTBasis)
+	fmt.Printf("Minimum Rate: %s\n", c.MinimumRate.String())
+	if c.TargetWeeklyHours.IsPositive() {
+		fmt.Printf("Target Weekly Hours: %s\n", c.TargetWeeklyHours.String())
+	}
+	fmt.Printf("Retainer Proration: %s\n", c.RetainerProration)
+	fmt.Printf("Mileage Rate: %s\n", c.MileageRate.String())
+	for _, role := range c.RateCardRoles {
+		fmt.Printf("Rate Card Role: %s at %s\n", role.Name, role.Rate.String())
+	}
+	fmt.Printf("Rate Card Terms: %d\n", len(c.RateCardTerms))
+	fmt.Printf("Financial Year Start: %s\n", c.FinancialYearStart)
+	fmt.Printf("Client Matching: %s\n", c.ClientMatching)
+	fmt.Printf("Description Policy: %s\n", c.DescriptionPolicy)
+	fmt.Printf("Auto Tracking Idle Timeout: %s\n", c.AutoIdleTimeout)
+	if c.IdleTrimThreshold > 0 {
+		fmt.Printf("Idle Trimming: idle for %s or more, from %s\n", c.IdleTrimThreshold, c.ActivityWatchURL)
+	} else {
+		fmt.Printf("Idle Trimming: false\n")
+	}
+	for _, context := range DurationContexts {
+		fmt.Printf("Duration Format (%s): %s\n", context, c.DurationFormats[context])
+	}
+	fmt.Printf("Duration Precision: %d decimal places\n", c.DurationPrecision)
+	fmt.Printf("Notion Work Logs: %t\n", c.NotionToken != "")
+	fmt.Printf("Confluence Work Logs: %t\n", c.ConfluenceURL != "" && c.ConfluenceEmail != "" && c.ConfluenceAPIToken != "")
+	if c.SMTPHost != "" {
+		fmt.Printf("SMTP Server: %s:%d (reports to %s)\n", c.SMTPHost, c.SMTPPort, c.ReportEmail)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Aliases)) {
+		fmt.Printf("Alias: %s -> %s\n", name, strings.Join(c.Aliases[name], " "))
+	}
+	for _, command := range slices.Sorted(maps.Keys(c.DefaultFlags)) {
+		fmt.Printf("Default Flags: %s %s\n", command, strings.Join(c.DefaultFlags[command], " "))
+	}
+	for _, webhook := range c.Webhooks {
+		host := webhook.URL
+		if u, err := url.Parse(webhook.URL); err == nil {
+			host = u.Host
+		}
+		fmt.Printf("Webhook: %s -> %s\n", webhook.Event, host)
+	}
+	fmt.Printf("Status API Key: %t\n", c.StatusAPIKey != "")
+}
+
+// parseDurationFormats reads the duration format for each context from DURATION_FORMAT_<CONTEXT>, falling back
+// to DURATION_FORMAT and then the context's default
+func parseDurationFormats() (map[string]string, error) {
+	fallback := getEnv("DURATION_FORMAT", "")
+	formats := make(map[string]string, len(DurationContexts))
+	for _, context := range DurationContexts {
+		key := "DURATION_FORMAT_" + strings.ToUpper(context)
+		format := strings.ToLower(getEnv(key, fallback))
+		if format == "" {
+			format = defaultDurationFormats[context]
+		}
+		switch format {
+		case DurationHoursMinutes, DurationDecimal, DurationClock, DurationVerbose:
+			formats[context] = format
+		default:
+			if os.Getenv(key) == "" {
+				key = "DURATION_FORMAT"
+			}
+			return nil, fmt.Errorf("%s must be one of %s, %s, %s or %s, got %q", key, DurationHoursMinutes, DurationDecimal, DurationClock, DurationVerbose, format)
+		}
+	}
+	return formats, nil
+}
+
+// parseRateLimits parses comma separated provider=calls-per-minute pairs, e.g. "opencode=30"
+func parseRateLimits(value string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		provider, perMinute, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected provider=calls-per-minute, got %q", pair)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(perMinute))
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("calls per minute for %s must be a positive integer, got %q", provider, perMinute)
+		}
+		limits[strings.TrimSpace(provider)] = limit
+	}
+	return limits, nil
+}
+
+// parseRateCardRoles parses comma separated role=rate pairs, e.g. "Developer=180,Technical lead=220"
+func parseRateCardRoles(value string) ([]RateCardRole, error) {
+	var roles []RateCardRole
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, rate, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected role=hourly-rate, got %q", pair)
+		}
+		amount, err := decimal.NewFromString(strings.TrimSpace(rate))
+		if err != nil || !amount.IsPositive() {
+			return nil, fmt.Errorf("hourly rate for %s must be a positive amount, got %q", name, rate)
+		}
+		roles = append(roles, RateCardRole{Name: name, Rate: amount})
+	}
+	return roles, nil
+}
+
+// ParseColour reads a colour written as #rrggbb or #rgb, with or without the #, and returns it as lowercase #rrggbb
+func ParseColour(value string) (string, error) {
+	hex := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), "#"))
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 || strings.Trim(hex, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("expected a colour as #rrggbb, e.g. #1f6feb, got %q", value)
+	}
+	return "#" + hex, nil
+}
+
+// ParseIgnorePatterns splits comma separated paths left out of git analysis, e.g. "vendor/,*.lock,go.sum"
+func ParseIgnorePatterns(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// ParseGitAnalysisDetail reads GitAnalysisDiffs or GitAnalysisMessages, in any case
+func ParseGitAnalysisDetail(value string) (string, error) {
+	detail := strings.ToLower(strings.TrimSpace(value))
+	if detail != GitAnalysisDiffs && detail != GitAnalysisMessages {
+		return "", fmt.Errorf("expected %q or %q, got %q", GitAnalysisDiffs, GitAnalysisMessages, value)
+	}
+	return detail, nil
+}
+
+// parseRateCardTerms splits | separated terms, e.g. "Invoiced monthly|Payment due within 14 days"
+func parseRateCardTerms(value string) []string {
+	var terms []string
+	for _, term := range strings.Split(value, "|") {
+		if term = strings.TrimSpace(term); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// parseCommandArgs parses ; separated name=arguments pairs, e.g. "s=start -c acme;l=sessions list -p week".
+// Arguments are split on spaces, except within single or double quotes.
+func parseCommandArgs(value string) (map[string][]string, error) {
+	commands := make(map[string][]string)
+	for _, pair := range strings.Split(value, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, args, ok := strings.Cut(pair, "=")
+		name = strings.Join(strings.Fields(name), " ")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected name=arguments, got %q", pair)
+		}
+		split, err := splitArgs(args)
+		if err != nil {
+			return nil, fmt.Errorf("arguments for %s: %w", name, err)
+		}
+		if len(split) == 0 {
+			return nil, fmt.Errorf("no arguments given for %s", name)
+		}
+		commands[name] = split
+	}
+	return commands, nil
+}
+
+// splitArgs splits a command line on spaces, keeping quoted text together without its quotes
+func splitArgs(value string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range value {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, value)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// keychainService and keychainAccount name the encryption key's entry in the OS keychain
+const (
+	keychainService = "work"
+	keychainAccount = "encryption-key"
+)
+
+// loadEncryptionKey reads a base64 encoded 32 byte key from ENCRYPTION_KEY, or from the OS keychain when
+// ENCRYPTION_KEY is "keychain". No key leaves encryption off.
+func loadEncryptionKey(value string) ([]byte, string, error) {
+	if value == "" {
+		return nil, "", nil
+	}
+	source := "ENCRYPTION_KEY"
+	if value == "keychain" {
+		source = "keychain"
+		var err error
+		value, err = readKeychain(keychainService, keychainAccount)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read the encryption key from the keychain (service %q, account %q): %w", keychainService, keychainAccount, err)
+		}
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil || len(key) != 32 {
+		return nil, "", fmt.Errorf("the encryption key from %s must be 32 bytes, base64 encoded (generate one with 'openssl rand -base64 32')", source)
+	}
+	return key, source, nil
+}
+
+// readKeychain reads a password from the macOS keychain, or the Secret Service on Linux
+func readKeychain(service, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	default:
+		return "", fmt.Errorf("no keychain support on %s, set ENCRYPTION_KEY to the key instead", runtime.GOOS)
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// parseWebhooks parses comma separated event=url pairs, e.g. "session.started=https://hooks.slack.com/...".
+// Each webhook's body template is read from WEBHOOK_TEMPLATE_<EVENT>, e.g. WEBHOOK_TEMPLATE_SESSION_STARTED,
+// falling back to WEBHOOK_TEMPLATE.
+func parseWebhooks(value string) ([]Webhook, error) {
+	var webhooks []Webhook
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		event, rawURL, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected event=url, got %q", pair)
+		}
+		event = strings.ToLower(strings.TrimSpace(event))
+		if event != "*" && !slices.Contains(WebhookEvents, event) {
+			return nil, fmt.Errorf("unknown event %q, expected * or one of %s", event, strings.Join(WebhookEvents, ", "))
+		}
+		rawURL = strings.TrimSpace(rawURL)
+		if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("webhook for %s must be an http(s) URL, got %q", event, rawURL)
+		}
+
+		templateKey := "WEBHOOK_TEMPLATE"
+		if event != "*" {
+			if specific := "WEBHOOK_TEMPLATE_" + strings.ToUpper(strings.ReplaceAll(event, ".", "_")); os.Getenv(specific) != "" {
+				templateKey = specific
+			}
+		}
+		webhook := Webhook{Event: event, URL: rawURL}
+		if text := os.Getenv(templateKey); text != "" {
+			tmpl, err := template.New(templateKey).Funcs(WebhookTemplateFuncs).Parse(text)
+			if err != nil {
+				return nil, fmt.Errorf("%s is not a valid template: %w", templateKey, err)
+			}
+			webhook.Template = tmpl
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
+// WebhookTemplateFuncs are available in webhook templates. json quotes a value for a JSON body, e.g.
+// {"text": {{json .Message}}}
+var WebhookTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
 }
 
 func getEnv(key, defaultValue string) string {
@@ -0,0 +1,992 @@
+//go:build acceptance
+
+// Acceptance tests run the DB interface against every supported driver, to catch behaviour that differs
+// between mattn/go-sqlite3 and the libsql client, such as the formats timestamps come back in.
+//
+//	go test -tags acceptance ./internal/database/
+//
+// libsql runs against a local file by default. Set WORK_ACCEPTANCE_LIBSQL_URL to an empty libsql
+// server (e.g. http://127.0.0.1:8080 from 'turso dev') to exercise the remote protocol as well.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/db"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+type acceptanceDriver struct {
+	name   string
+	driver string
+	url    func(dir string) string
+}
+
+func acceptanceDrivers() []acceptanceDriver {
+	drivers := []acceptanceDriver{
+		{"sqlite3", "sqlite3", func(dir string) string { return filepath.Join(dir, "work.db") }},
+		{"libsql file", "libsql", func(dir string) string { return "file:" + filepath.Join(dir, "work.db") }},
+	}
+	if url := os.Getenv("WORK_ACCEPTANCE_LIBSQL_URL"); url != "" {
+		drivers = append(drivers, acceptanceDriver{"libsql remote", "libsql", func(string) string { return url }})
+	}
+	return drivers
+}
+
+func TestAcceptanceDrivers(t *testing.T) {
+	for _, driver := range acceptanceDrivers() {
+		t.Run(driver.name, func(t *testing.T) {
+			s := openAcceptanceDB(t, driver)
+			ctx := context.Background()
+
+			client := testClientLifecycle(ctx, t, s)
+			session := testSessionLifecycle(ctx, t, s, client)
+			invoice := testInvoiceLifecycle(ctx, t, s, client, session)
+			testExpenseLifecycle(ctx, t, s, client, invoice)
+			testClientActivity(ctx, t, s, client, invoice)
+			testInvoicedSessions(ctx, t, s, session, invoice)
+			testSessionBreaks(ctx, t, s, client)
+			testSessionTemplates(ctx, t, s, client)
+			testImportData(ctx, t, s)
+			testDiagnostics(ctx, t, s)
+			testClientEncryption(ctx, t, s, client)
+			testUsers(ctx, t, s, client)
+			testInvoiceStatus(ctx, t, s, client)
+			// Swaps the connection for the snapshot, so it runs last
+			testSnapshot(ctx, t, s)
+		})
+	}
+}
+
+// openAcceptanceDB opens a database with the driver and applies every migration through the same connection
+func openAcceptanceDB(t *testing.T, driver acceptanceDriver) *SQLiteDB {
+	t.Helper()
+	s, err := NewDB(&config.Config{
+		DatabaseURL:     driver.url(t.TempDir()),
+		DatabaseDriver:  driver.driver,
+		DatabaseName:    "acceptance",
+		DevMode:         true,
+		SessionMetadata: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	migrations, err := filepath.Glob(filepath.Join("../../migrations", "*.sql"))
+	if err != nil {
+		t.Fatalf("Failed to list migrations: %v", err)
+	}
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		content, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatalf("Failed to read migration %s: %v", migration, err)
+		}
+		if _, err := s.conn.Exec(string(content)); err != nil {
+			t.Fatalf("Failed to apply migration %s: %v", filepath.Base(migration), err)
+		}
+	}
+	return s
+}
+
+func testClientLifecycle(ctx context.Context, t *testing.T, s *SQLiteDB) *models.Client {
+	t.Helper()
+	retainer := decimal.RequireFromString("1500.50")
+	hours := 20.5
+	basis := "month"
+	dir := "~/code/acme"
+	client, err := s.CreateClient(ctx, "acme", decimal.RequireFromString("152.25"), &retainer, &hours, &basis, &dir)
+	if err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+	if !client.HourlyRate.Equal(decimal.RequireFromString("152.25")) {
+		t.Errorf("hourly rate = %s, want 152.25", client.HourlyRate)
+	}
+
+	byName, err := s.GetClientByName(ctx, "acme")
+	if err != nil {
+		t.Fatalf("GetClientByName: %v", err)
+	}
+	if byName.ID != client.ID {
+		t.Errorf("GetClientByName returned %s, want %s", byName.ID, client.ID)
+	}
+	if byName.RetainerAmount == nil || !byName.RetainerAmount.Equal(retainer) {
+		t.Errorf("retainer amount = %v, want %s", byName.RetainerAmount, retainer)
+	}
+	if byName.RetainerHours == nil || *byName.RetainerHours != hours {
+		t.Errorf("retainer hours = %v, want %v", byName.RetainerHours, hours)
+	}
+
+	// UpdateClient replaces every detail, so the existing ones are passed back alongside the changes
+	email := "accounts@acme.test"
+	currency := "NZD"
+	retainerStart := time.Date(2025, 3, 18, 0, 0, 0, 0, time.Local)
+	logo, colour := "/srv/branding/acme.png", "#1f6feb"
+	maxCommits, detail := int64(20), "messages"
+	prompt := "describe the commits from {from_date} to {to_date} formally"
+	updated, err := s.UpdateClient(ctx, client.ID, &ClientUpdateDetails{
+		HourlyRate:         &client.HourlyRate,
+		Email:              &email,
+		Dir:                &dir,
+		RetainerAmount:     &retainer,
+		RetainerHours:      &hours,
+		RetainerBasis:      &basis,
+		Currency:           &currency,
+		RetainerStart:      &retainerStart,
+		LogoPath:           &logo,
+		BrandColour:        &colour,
+		AnalysisMaxCommits: &maxCommits,
+		AnalysisDetail:     &detail,
+		AnalysisPrompt:     &prompt,
+	})
+	if err != nil {
+		t.Fatalf("UpdateClient: %v", err)
+	}
+	if updated.Email == nil || *updated.Email != email {
+		t.Errorf("email = %v, want %s", updated.Email, email)
+	}
+	if updated.Currency == nil || *updated.Currency != currency {
+		t.Errorf("currency = %v, want %s", updated.Currency, currency)
+	}
+	if updated.RetainerStart == nil || !updated.RetainerStart.Equal(retainerStart) {
+		t.Errorf("retainer start = %v, want %s", updated.RetainerStart, retainerStart)
+	}
+
+	// Currency, branding and analysis depth are kept unless they're given, and removed when they're given empty or
+	// negative
+	noColour, noMaxCommits := "", int64(-1)
+	branded, err := s.UpdateClient(ctx, client.ID, &ClientUpdateDetails{
+		HourlyRate:         &client.HourlyRate,
+		Email:              &email,
+		Dir:                &dir,
+		RetainerAmount:     &retainer,
+		RetainerHours:      &hours,
+		RetainerBasis:      &basis,
+		RetainerStart:      &retainerStart,
+		BrandColour:        &noColour,
+		AnalysisMaxCommits: &noMaxCommits,
+	})
+	if err != nil {
+		t.Fatalf("UpdateClient: %v", err)
+	}
+	if branded.Currency == nil || *branded.Currency != currency {
+		t.Errorf("currency = %v, want %s kept", branded.Currency, currency)
+	}
+	if branded.LogoPath == nil || *branded.LogoPath != logo || branded.BrandColour != nil {
+		t.Errorf("branding = %v, %v, want %s and no colour", branded.LogoPath, branded.BrandColour, logo)
+	}
+	if branded.AnalysisMaxCommits != nil || branded.AnalysisDetail == nil || *branded.AnalysisDetail != detail {
+		t.Errorf("analysis depth = %v, %v, want no max commits and %s", branded.AnalysisMaxCommits, branded.AnalysisDetail, detail)
+	}
+	if branded.AnalysisPrompt == nil || *branded.AnalysisPrompt != prompt {
+		t.Errorf("analysis prompt = %v, want %s", branded.AnalysisPrompt, prompt)
+	}
+
+	withDirs, err := s.GetClientsWithDirectories(ctx)
+	if err != nil {
+		t.Fatalf("GetClientsWithDirectories: %v", err)
+	}
+	if len(withDirs) != 1 || withDirs[0].ID != client.ID {
+		t.Errorf("GetClientsWithDirectories returned %d client(s), want acme", len(withDirs))
+	}
+	return client
+}
+
+// testClientEncryption encrypts the client's details saved as plaintext, then decrypts them again
+func testClientEncryption(ctx context.Context, t *testing.T, s *SQLiteDB, client *models.Client) {
+	t.Helper()
+	cipher, err := newFieldCipher([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("newFieldCipher: %v", err)
+	}
+	s.cipher = cipher
+	defer func() { s.cipher = nil }()
+
+	changed, err := s.EncryptClientFields(ctx)
+	if err != nil {
+		t.Fatalf("EncryptClientFields: %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("EncryptClientFields changed %d client(s), want 1", changed)
+	}
+	var stored string
+	if err := s.conn.QueryRowContext(ctx, "SELECT email FROM clients WHERE id = ?", client.ID).Scan(&stored); err != nil {
+		t.Fatalf("reading stored email: %v", err)
+	}
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		t.Errorf("stored email = %q, want it encrypted", stored)
+	}
+	decrypted, err := s.GetClientByID(ctx, client.ID)
+	if err != nil {
+		t.Fatalf("GetClientByID: %v", err)
+	}
+	if decrypted.Email == nil || *decrypted.Email != "accounts@acme.test" {
+		t.Errorf("email = %v, want accounts@acme.test", decrypted.Email)
+	}
+
+	if changed, err := s.DecryptClientFields(ctx); err != nil || changed != 1 {
+		t.Errorf("DecryptClientFields = %d, %v, want 1 client", changed, err)
+	}
+}
+
+func testSessionLifecycle(ctx context.Context, t *testing.T, s *SQLiteDB, client *models.Client) *models.WorkSession {
+	t.Helper()
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.Local)
+	end := start.Add(3 * time.Hour)
+	description := "Build the thing"
+	session, err := s.CreateWorkSessionWithTimes(ctx, client.ID, start, end, &description, client.HourlyRate, true)
+	if err != nil {
+		t.Fatalf("CreateWorkSessionWithTimes: %v", err)
+	}
+
+	got, err := s.GetSessionByID(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("GetSessionByID: %v", err)
+	}
+	if !got.StartTime.Equal(start) {
+		t.Errorf("start time = %s, want %s", got.StartTime, start)
+	}
+	if got.EndTime == nil || !got.EndTime.Equal(end) {
+		t.Errorf("end time = %v, want %s", got.EndTime, end)
+	}
+	if got.HourlyRate == nil || !got.HourlyRate.Equal(client.HourlyRate) {
+		t.Errorf("hourly rate = %v, want %s", got.HourlyRate, client.HourlyRate)
+	}
+	if !got.IncludesGst {
+		t.Error("includes GST was not stored")
+	}
+	if got.Hostname == nil {
+		t.Error("hostname was not stored")
+	}
+	if got.OS == nil || *got.OS != runtime.GOOS {
+		t.Errorf("OS = %v, want %s", got.OS, runtime.GOOS)
+	}
+
+	// An active session is started from now and stopped
+	active, err := s.CreateWorkSession(ctx, client.ID, nil, client.HourlyRate, false)
+	if err != nil {
+		t.Fatalf("CreateWorkSession: %v", err)
+	}
+	current, err := s.GetActiveSession(ctx)
+	if err != nil {
+		t.Fatalf("GetActiveSession: %v", err)
+	}
+	if current.ID != active.ID {
+		t.Errorf("active session = %s, want %s", current.ID, active.ID)
+	}
+	stopped, err := s.StopWorkSession(ctx, active.ID, time.Now())
+	if err != nil {
+		t.Fatalf("StopWorkSession: %v", err)
+	}
+	if stopped.EndTime == nil || stopped.EndTime.Before(stopped.StartTime) {
+		t.Errorf("stopped session ends at %v, before its start %s", stopped.EndTime, stopped.StartTime)
+	}
+
+	inRange, err := s.ListSessionsWithDateRange(ctx, "2025-03-01", "2025-03-31", 100)
+	if err != nil {
+		t.Fatalf("ListSessionsWithDateRange: %v", err)
+	}
+	if len(inRange) != 1 || inRange[0].ID != session.ID {
+		t.Errorf("ListSessionsWithDateRange returned %d session(s), want only %s", len(inRange), session.ID)
+	}
+
+	// Splitting keeps the first two hours and moves the last hour to a new session
+	at := start.Add(2 * time.Hour)
+	first, second, err := s.SplitSession(ctx, session.ID, start, at, db.CreateSessionParams{
+		ID:        models.NewUUID(),
+		ClientID:  client.ID,
+		StartTime: at,
+	}, end, false)
+	if err != nil {
+		t.Fatalf("SplitSession: %v", err)
+	}
+	if first.EndTime == nil || !first.EndTime.Equal(at) {
+		t.Errorf("split session ends at %v, want %s", first.EndTime, at)
+	}
+	if !second.StartTime.Equal(at) || second.EndTime == nil || !second.EndTime.Equal(end) {
+		t.Errorf("new session covers %s to %v, want %s to %s", second.StartTime, second.EndTime, at, end)
+	}
+
+	updated, err := s.UpdateSessionDescription(ctx, session.ID, "Built the thing", nil, false)
+	if err != nil {
+		t.Fatalf("UpdateSessionDescription: %v", err)
+	}
+	if updated.Description == nil || *updated.Description != "Built the thing" {
+		t.Errorf("description = %v, want 'Built the thing'", updated.Description)
+	}
+
+	// Generating the description again replaces the repositories recorded the first time
+	if err := s.ReplaceSessionRepos(ctx, session.ID, []*models.SessionRepo{{RepoPath: "/code/old", CommitCount: 1}}, false); err != nil {
+		t.Fatalf("ReplaceSessionRepos: %v", err)
+	}
+	if err := s.ReplaceSessionRepos(ctx, session.ID, []*models.SessionRepo{
+		{RepoPath: "/code/web", CommitCount: 2},
+		{RepoPath: "/code/api", CommitCount: 5},
+	}, false); err != nil {
+		t.Fatalf("ReplaceSessionRepos again: %v", err)
+	}
+	repos, err := s.ListSessionRepos(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("ListSessionRepos: %v", err)
+	}
+	if len(repos) != 2 || repos[0].RepoPath != "/code/api" || repos[0].CommitCount != 5 || repos[1].RepoPath != "/code/web" {
+		t.Errorf("session repos = %+v, want /code/api with 5 commits and /code/web", repos)
+	}
+	return updated
+}
+
+func testInvoiceLifecycle(ctx context.Context, t *testing.T, s *SQLiteDB, client *models.Client, session *models.WorkSession) *models.Invoice {
+	t.Helper()
+	periodStart := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)
+	periodEnd := time.Date(2025, 3, 31, 23, 59, 59, 0, time.Local)
+
+	unbilled, err := s.GetSessionsForPeriodWithoutInvoiceByClient(ctx, periodStart, periodEnd, client.Name)
+	if err != nil {
+		t.Fatalf("GetSessionsForPeriodWithoutInvoiceByClient: %v", err)
+	}
+	if len(unbilled) != 2 {
+		t.Fatalf("found %d unbilled session(s), want 2", len(unbilled))
+	}
+
+	subtotal := decimal.RequireFromString("456.75")
+	gst := decimal.RequireFromString("45.68")
+	total := decimal.RequireFromString("502.43")
+	terms := "Payment due within 14 days"
+	rounding := decimal.NewFromInt(1)
+	invoice, err := s.CreateInvoice(ctx, client.ID, "INV-ACME-2025-03", "month", periodStart, periodEnd, subtotal, gst, total, models.InvoiceGroupBySession, nil, &terms, nil, nil, &rounding)
+	if err != nil {
+		t.Fatalf("CreateInvoice: %v", err)
+	}
+	notes := "PO 4521"
+	if err := s.UpdateInvoiceNotes(ctx, invoice.ID, &notes); err != nil {
+		t.Fatalf("UpdateInvoiceNotes: %v", err)
+	}
+	poNumber, projectCode := "PO-4521", "ACME-WEB"
+	if err := s.UpdateInvoiceReferences(ctx, invoice.ID, &poNumber, &projectCode); err != nil {
+		t.Fatalf("UpdateInvoiceReferences: %v", err)
+	}
+	for _, unbilledSession := range unbilled {
+		if err := s.UpdateSessionInvoiceID(ctx, unbilledSession.ID, invoice.ID); err != nil {
+			t.Fatalf("UpdateSessionInvoiceID: %v", err)
+		}
+	}
+
+	invoiced, err := s.GetSessionsByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("GetSessionsByInvoiceID: %v", err)
+	}
+	if len(invoiced) != 2 {
+		t.Errorf("invoice has %d session(s), want 2", len(invoiced))
+	}
+
+	byPeriod, err := s.GetInvoicesByPeriodAndClient(ctx, periodStart, periodEnd, "month", client.Name)
+	if err != nil {
+		t.Fatalf("GetInvoicesByPeriodAndClient: %v", err)
+	}
+	if len(byPeriod) != 1 || byPeriod[0].ID != invoice.ID {
+		t.Errorf("GetInvoicesByPeriodAndClient returned %d invoice(s), want %s", len(byPeriod), invoice.InvoiceNumber)
+	}
+	if len(byPeriod) == 1 && (byPeriod[0].Notes == nil || *byPeriod[0].Notes != notes || byPeriod[0].PaymentTerms == nil || *byPeriod[0].PaymentTerms != terms) {
+		t.Errorf("invoice notes = %v, payment terms = %v, want %q and %q", byPeriod[0].Notes, byPeriod[0].PaymentTerms, notes, terms)
+	}
+	if len(byPeriod) == 1 && (byPeriod[0].PoNumber == nil || *byPeriod[0].PoNumber != poNumber || byPeriod[0].ProjectCode == nil || *byPeriod[0].ProjectCode != projectCode) {
+		t.Errorf("invoice PO number = %v, project code = %v, want %q and %q", byPeriod[0].PoNumber, byPeriod[0].ProjectCode, poNumber, projectCode)
+	}
+	if len(byPeriod) == 1 && (byPeriod[0].Rounding == nil || !byPeriod[0].Rounding.Equal(rounding)) {
+		t.Errorf("invoice rounding = %v, want %s", byPeriod[0].Rounding, rounding)
+	}
+
+	// Payment dates come back as strings or times depending on the driver
+	paidOn := time.Date(2025, 4, 14, 12, 0, 0, 0, time.Local)
+	if err := s.PayInvoices(ctx, []db.PayInvoiceParams{
+		{ID: models.NewUUID(), InvoiceID: invoice.ID, Amount: decimal.RequireFromString("200.00"), PaymentDate: paidOn.AddDate(0, 0, -7)},
+		{ID: models.NewUUID(), InvoiceID: invoice.ID, Amount: decimal.RequireFromString("302.43"), PaymentDate: paidOn},
+	}); err != nil {
+		t.Fatalf("PayInvoices: %v", err)
+	}
+
+	paid, err := s.GetInvoiceByNumber(ctx, invoice.InvoiceNumber)
+	if err != nil {
+		t.Fatalf("GetInvoiceByNumber: %v", err)
+	}
+	if !paid.SubtotalAmount.Equal(subtotal) || !paid.GstAmount.Equal(gst) || !paid.TotalAmount.Equal(total) {
+		t.Errorf("amounts = %s/%s/%s, want %s/%s/%s", paid.SubtotalAmount, paid.GstAmount, paid.TotalAmount, subtotal, gst, total)
+	}
+	if !paid.AmountPaid.Equal(total) {
+		t.Errorf("amount paid = %s, want %s", paid.AmountPaid, total)
+	}
+	if paid.PaymentDate == nil || paid.PaymentDate.Format("2006-01-02") != paidOn.Format("2006-01-02") {
+		t.Errorf("payment date = %v, want %s", paid.PaymentDate, paidOn.Format("2006-01-02"))
+	}
+
+	payments, err := s.ListPaymentsByDateRange(ctx, paidOn.AddDate(0, 0, -1), paidOn.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("ListPaymentsByDateRange: %v", err)
+	}
+	if len(payments) != 1 || !payments[0].Amount.Equal(decimal.RequireFromString("302.43")) || payments[0].InvoiceID != invoice.ID {
+		t.Errorf("payments in range = %+v, want the single 302.43 payment", payments)
+	}
+
+	// A payment recorded twice by mistake is deleted, taking amount paid back down
+	mistake := models.NewUUID()
+	if err := s.PayInvoice(ctx, db.PayInvoiceParams{ID: mistake, InvoiceID: invoice.ID, Amount: decimal.RequireFromString("302.43"), PaymentDate: paidOn}); err != nil {
+		t.Fatalf("PayInvoice: %v", err)
+	}
+	listed, err := s.ListPayments(ctx, invoice.ID, client.Name)
+	if err != nil {
+		t.Fatalf("ListPayments: %v", err)
+	}
+	if len(listed) != 3 || listed[0].InvoiceNumber != invoice.InvoiceNumber || listed[0].ClientName != client.Name {
+		t.Errorf("listed payments = %+v, want 3 on %s for %s", listed, invoice.InvoiceNumber, client.Name)
+	}
+	if others, err := s.ListPayments(ctx, "", "No Such Client"); err != nil || len(others) != 0 {
+		t.Errorf("ListPayments for another client = %d payment(s), %v, want none", len(others), err)
+	}
+	if _, err := s.GetPaymentByID(ctx, mistake); err != nil {
+		t.Fatalf("GetPaymentByID: %v", err)
+	}
+	if err := s.DeletePayment(ctx, mistake); err != nil {
+		t.Fatalf("DeletePayment: %v", err)
+	}
+	if _, err := s.GetPaymentByID(ctx, mistake); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetPaymentByID after delete = %v, want sql.ErrNoRows", err)
+	}
+	corrected, err := s.GetInvoiceByID(ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("GetInvoiceByID: %v", err)
+	}
+	if !corrected.AmountPaid.Equal(total) {
+		t.Errorf("amount paid after deleting a payment = %s, want %s", corrected.AmountPaid, total)
+	}
+
+	if paid.PeriodStartDate.Format("2006-01-02") != "2025-03-01" || paid.PeriodEndDate.Format("2006-01-02") != "2025-03-31" {
+		t.Errorf("period = %s to %s, want 2025-03-01 to 2025-03-31", paid.PeriodStartDate, paid.PeriodEndDate)
+	}
+
+	if _, err := s.CreateCreditNote(ctx, invoice.ID, "CN-ACME-2025-03-1", decimal.RequireFromString("55.00"),
+		decimal.RequireFromString("5.00"), "Over-invoiced", paidOn); err != nil {
+		t.Fatalf("CreateCreditNote: %v", err)
+	}
+	creditNotes, err := s.GetCreditNotesByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("GetCreditNotesByInvoiceID: %v", err)
+	}
+	if len(creditNotes) != 1 || !creditNotes[0].IssuedDate.Equal(paidOn) {
+		t.Errorf("found %d credit note(s), want one issued %s", len(creditNotes), paidOn)
+	}
+	credited, err := s.GetInvoiceByID(ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("GetInvoiceByID: %v", err)
+	}
+	if !credited.AmountCredited.Equal(decimal.RequireFromString("55.00")) || !credited.AmountPaid.Equal(total) {
+		t.Errorf("credited %s and paid %s, want 55.00 and %s", credited.AmountCredited, credited.AmountPaid, total)
+	}
+	return paid
+}
+
+func testClientActivity(ctx context.Context, t *testing.T, s *SQLiteDB, client *models.Client, invoice *models.Invoice) {
+	t.Helper()
+	activity, err := s.GetClientActivity(ctx, client.ID)
+	if err != nil {
+		t.Fatalf("GetClientActivity: %v", err)
+	}
+	// The split session's two halves and the briefly active session
+	if activity.SessionCount != 3 {
+		t.Errorf("session count = %d, want 3", activity.SessionCount)
+	}
+	if math.Abs(activity.TotalHours-3) > 0.01 {
+		t.Errorf("total hours = %f, want 3", activity.TotalHours)
+	}
+	if activity.LastSession == nil {
+		t.Error("last session was not found")
+	}
+	if activity.InvoiceCount != 1 || !activity.TotalInvoiced.Equal(invoice.TotalAmount) {
+		t.Errorf("invoiced %s across %d invoice(s), want %s across 1", activity.TotalInvoiced, activity.InvoiceCount, invoice.TotalAmount)
+	}
+	// Paid in full and credited on top, which doesn't count as owing a negative amount
+	if !activity.Outstanding.IsZero() {
+		t.Errorf("outstanding = %s, want 0", activity.Outstanding)
+	}
+
+	// Only the second half of the split session starts after 10am, besides the active session from today
+	since, err := s.GetClientHoursSince(ctx, client.ID, time.Date(2025, 3, 3, 10, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("GetClientHoursSince: %v", err)
+	}
+	if math.Abs(since-1) > 0.01 {
+		t.Errorf("hours since 10am = %f, want 1", since)
+	}
+}
+
+func testInvoicedSessions(ctx context.Context, t *testing.T, s *SQLiteDB, session *models.WorkSession, invoice *models.Invoice) {
+	t.Helper()
+
+	if _, err := s.UpdateSessionDescription(ctx, session.ID, "Rewritten", nil, false); !errors.Is(err, ErrSessionInvoiced) {
+		t.Errorf("UpdateSessionDescription on an invoiced session = %v, want ErrSessionInvoiced", err)
+	}
+	if deleted, skipped, err := s.DeleteSessionsByDateRange(ctx, "2025-03-01 00:00:00", "2025-03-31 23:59:59", false); err != nil || deleted != 0 || skipped != 2 {
+		t.Errorf("DeleteSessionsByDateRange over invoiced sessions = %d deleted, %d skipped (err %v), want both skipped", deleted, skipped, err)
+	}
+	if sessions, err := s.GetSessionsByInvoiceID(ctx, invoice.ID); err != nil || len(sessions) != 2 {
+		t.Errorf("invoice has %d session(s) after refused changes (err %v), want 2", len(sessions), err)
+	}
+	if repos, err := s.ListInvoiceSessionRepos(ctx, invoice.ID); err != nil || len(repos) != 2 {
+		t.Errorf("invoice has %d session repo(s) (err %v), want 2", len(repos), err)
+	}
+
+	// Forcing the change goes through and flags the invoice
+	updated, err := s.UpdateSessionDescription(ctx, session.ID, "Rewritten", nil, true)
+	if err != nil {
+		t.Fatalf("UpdateSessionDescription forced: %v", err)
+	}
+	if updated.Description == nil || *updated.Description != "Rewritten" {
+		t.Errorf("description = %v, want 'Rewritten'", updated.Description)
+	}
+	flagged, err := s.GetInvoiceByID(ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("GetInvoiceByID: %v", err)
+	}
+	if !flagged.NeedsRegeneration {
+		t.Errorf("invoice isn't flagged as needing regeneration after its session was changed")
+	}
+}
+
+func testSessionBreaks(ctx context.Context, t *testing.T, s *SQLiteDB, client *models.Client) {
+	t.Helper()
+	start := time.Date(2025, 4, 7, 9, 0, 0, 0, time.Local)
+	session, err := s.CreateWorkSessionWithTimes(ctx, client.ID, start, start.Add(2*time.Hour), nil, client.HourlyRate, false)
+	if err != nil {
+		t.Fatalf("CreateWorkSessionWithTimes: %v", err)
+	}
+
+	updated, err := s.AddSessionBreaks(ctx, session.ID, []*models.SessionBreak{
+		{StartTime: start.Add(30 * time.Minute), EndTime: start.Add(45 * time.Minute), Source: models.BreakSourceActivityWatch},
+		{StartTime: start.Add(time.Hour), EndTime: start.Add(75 * time.Minute)},
+	}, false)
+	if err != nil {
+		t.Fatalf("AddSessionBreaks: %v", err)
+	}
+	if updated.BreakSeconds != 1800 {
+		t.Errorf("break seconds = %d, want 1800", updated.BreakSeconds)
+	}
+	breaks, err := s.ListSessionBreaks(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("ListSessionBreaks: %v", err)
+	}
+	if len(breaks) != 2 || breaks[0].Source != models.BreakSourceActivityWatch || breaks[1].Source != models.BreakSourceManual {
+		t.Errorf("ListSessionBreaks returned %d break(s), want the activitywatch break then the manual one", len(breaks))
+	}
+
+	hours, err := s.GetClientHoursSince(ctx, client.ID, start)
+	if err != nil {
+		t.Fatalf("GetClientHoursSince: %v", err)
+	}
+	if math.Abs(hours-1.5) > 0.01 {
+		t.Errorf("hours with breaks taken out = %f, want 1.5", hours)
+	}
+
+	// Deleting the session moves it to the trash with its breaks, so it can be restored whole
+	if _, _, err := s.DeleteSessionsByDateRange(ctx, "2025-04-07 00:00:00", "2025-04-07 23:59:59", false); err != nil {
+		t.Fatalf("DeleteSessionsByDateRange: %v", err)
+	}
+	if _, err := s.GetSessionByID(ctx, session.ID); err == nil {
+		t.Error("deleted session can still be fetched")
+	}
+	trashed, err := s.ListTrashedSessions(ctx)
+	if err != nil {
+		t.Fatalf("ListTrashedSessions: %v", err)
+	}
+	if len(trashed) != 1 || trashed[0].ID != session.ID || trashed[0].DeletedAt == nil {
+		t.Errorf("ListTrashedSessions returned %d session(s), want the deleted one", len(trashed))
+	}
+	if restored, err := s.RestoreSession(ctx, session.ID); err != nil || restored.BreakSeconds != 1800 {
+		t.Errorf("RestoreSession = %v, want the session back with its breaks", err)
+	}
+	if _, err := s.RestoreSession(ctx, session.ID); err == nil {
+		t.Error("restored a session that isn't in the trash")
+	}
+
+	// Purging it takes its breaks with it
+	if _, _, err := s.DeleteSessionsByDateRange(ctx, "2025-04-07 00:00:00", "2025-04-07 23:59:59", false); err != nil {
+		t.Fatalf("DeleteSessionsByDateRange: %v", err)
+	}
+	if sessions, _, err := s.PurgeTrash(ctx, time.Now().Add(-time.Hour)); err != nil || sessions != 0 {
+		t.Errorf("PurgeTrash before the delete = %d session(s) (err %v), want none", sessions, err)
+	}
+	if sessions, _, err := s.PurgeTrash(ctx, time.Now().Add(time.Second)); err != nil || sessions != 1 {
+		t.Errorf("PurgeTrash = %d session(s) (err %v), want 1", sessions, err)
+	}
+	if breaks, err := s.ListSessionBreaks(ctx, session.ID); err != nil || len(breaks) != 0 {
+		t.Errorf("purged session still has %d break(s) (err %v)", len(breaks), err)
+	}
+}
+
+func testExpenseLifecycle(ctx context.Context, t *testing.T, s *SQLiteDB, client *models.Client, invoice *models.Invoice) {
+	t.Helper()
+	expenseDate := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	reference := "Domain renewal"
+	category := "software"
+	markup := decimal.RequireFromString("12.5")
+	gst := decimal.RequireFromString("2.27")
+	expense, err := s.CreateExpense(ctx, decimal.RequireFromString("24.99"), expenseDate, &reference, &client.ID, nil, nil, &category, &markup, true, &gst)
+	if err != nil {
+		t.Fatalf("CreateExpense: %v", err)
+	}
+	if !expense.Amount.Equal(decimal.RequireFromString("24.99")) {
+		t.Errorf("amount = %s, want 24.99", expense.Amount)
+	}
+	if expense.GstAmount == nil || !expense.GstAmount.Equal(gst) {
+		t.Errorf("gst = %v, want %s", expense.GstAmount, gst)
+	}
+	if expense.MarkupPercent == nil || !expense.MarkupPercent.Equal(markup) {
+		t.Errorf("markup = %v, want %s", expense.MarkupPercent, markup)
+	}
+
+	mileage, err := s.CreateMileageExpense(ctx, client.ID, decimal.RequireFromString("36.96"), decimal.RequireFromString("42"),
+		decimal.RequireFromString("0.88"), expenseDate, nil, nil, true)
+	if err != nil {
+		t.Fatalf("CreateMileageExpense: %v", err)
+	}
+	if mileage.DistanceKm == nil || !mileage.DistanceKm.Equal(decimal.RequireFromString("42")) {
+		t.Errorf("distance = %v, want 42", mileage.DistanceKm)
+	}
+	if mileage.RatePerKm == nil || !mileage.RatePerKm.Equal(decimal.RequireFromString("0.88")) {
+		t.Errorf("rate per km = %v, want 0.88", mileage.RatePerKm)
+	}
+
+	draft, err := s.CreateDraftExpense(ctx, client.ID, decimal.Zero, expenseDate, nil, "/receipts/lunch.pdf")
+	if err != nil {
+		t.Fatalf("CreateDraftExpense: %v", err)
+	}
+	if byReceipt, err := s.GetExpenseByReceiptPath(ctx, "/receipts/lunch.pdf"); err != nil || byReceipt.ID != draft.ID {
+		t.Errorf("GetExpenseByReceiptPath = %v, %v, want %s", byReceipt, err, draft.ID)
+	}
+	confirmed, err := s.ConfirmExpense(ctx, draft.ID, decimal.RequireFromString("18.40"))
+	if err != nil {
+		t.Fatalf("ConfirmExpense: %v", err)
+	}
+	if confirmed.Draft || !confirmed.Amount.Equal(decimal.RequireFromString("18.40")) {
+		t.Errorf("confirmed expense is draft=%t with amount %s, want 18.40", confirmed.Draft, confirmed.Amount)
+	}
+
+	if err := s.UpdateExpenseInvoiceID(ctx, expense.ID, &invoice.ID); err != nil {
+		t.Fatalf("UpdateExpenseInvoiceID: %v", err)
+	}
+	onInvoice, err := s.GetExpensesByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("GetExpensesByInvoiceID: %v", err)
+	}
+	if len(onInvoice) != 1 || onInvoice[0].ID != expense.ID {
+		t.Errorf("invoice has %d expense(s), want %s", len(onInvoice), expense.ID)
+	}
+
+	inRange, err := s.ListExpensesByClientAndDateRange(ctx, client.ID, expenseDate.AddDate(0, 0, -1), expenseDate.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("ListExpensesByClientAndDateRange: %v", err)
+	}
+	if len(inRange) != 3 {
+		t.Errorf("found %d expense(s) around %s, want 3", len(inRange), expenseDate.Format("2006-01-02"))
+	}
+	for _, found := range inRange {
+		if found.ExpenseDate.Format("2006-01-02") != "2025-03-12" {
+			t.Errorf("expense %s dated %s, want 2025-03-12", found.ID, found.ExpenseDate)
+		}
+	}
+
+	unbilled, err := s.GetExpensesWithoutInvoiceByClient(ctx, client.ID)
+	if err != nil {
+		t.Fatalf("GetExpensesWithoutInvoiceByClient: %v", err)
+	}
+	if len(unbilled) != 2 {
+		t.Errorf("found %d unbilled expense(s), want 2", len(unbilled))
+	}
+
+	if err := s.DeleteExpense(ctx, mileage.ID); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+	if _, err := s.GetExpenseByID(ctx, mileage.ID); err == nil {
+		t.Error("deleted expense can still be fetched")
+	}
+	if trashed, err := s.ListTrashedExpenses(ctx); err != nil || len(trashed) != 1 || trashed[0].ID != mileage.ID {
+		t.Errorf("ListTrashedExpenses returned %d expense(s) (err %v), want the deleted one", len(trashed), err)
+	}
+	if _, err := s.RestoreExpense(ctx, mileage.ID); err != nil {
+		t.Fatalf("RestoreExpense: %v", err)
+	}
+	if _, err := s.GetExpenseByID(ctx, mileage.ID); err != nil {
+		t.Errorf("restored expense can't be fetched: %v", err)
+	}
+	if err := s.DeleteExpense(ctx, mileage.ID); err != nil {
+		t.Fatalf("DeleteExpense: %v", err)
+	}
+}
+
+func testSessionTemplates(ctx context.Context, t *testing.T, s *SQLiteDB, client *models.Client) {
+	t.Helper()
+	description := "Weekly standup"
+	minutes := int64(30)
+	template, err := s.CreateSessionTemplate(ctx, "standup", client.ID, &description, &minutes)
+	if err != nil {
+		t.Fatalf("CreateSessionTemplate: %v", err)
+	}
+
+	got, err := s.GetSessionTemplateByName(ctx, "standup")
+	if err != nil {
+		t.Fatalf("GetSessionTemplateByName: %v", err)
+	}
+	if got.DurationMinutes == nil || *got.DurationMinutes != minutes {
+		t.Errorf("duration = %v, want %d", got.DurationMinutes, minutes)
+	}
+
+	if err := s.DeleteSessionTemplate(ctx, template.ID); err != nil {
+		t.Fatalf("DeleteSessionTemplate: %v", err)
+	}
+	templates, err := s.ListSessionTemplates(ctx)
+	if err != nil {
+		t.Fatalf("ListSessionTemplates: %v", err)
+	}
+	if len(templates) != 0 {
+		t.Errorf("found %d template(s) after deleting, want 0", len(templates))
+	}
+}
+
+func testUsers(ctx context.Context, t *testing.T, s *SQLiteDB, client *models.Client) {
+	t.Helper()
+	user, err := s.CreateUser(ctx, "sam", nil)
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	costRate := decimal.RequireFromString("85.50")
+	if _, err := s.UpdateUser(ctx, user.ID, nil, &costRate); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	got, err := s.GetUserByName(ctx, "sam")
+	if err != nil {
+		t.Fatalf("GetUserByName: %v", err)
+	}
+	if got.CostRate == nil || !got.CostRate.Equal(costRate) {
+		t.Errorf("cost rate = %v, want %s", got.CostRate, costRate)
+	}
+
+	// Sessions created with WORK_USER set are attributed to that user
+	s.workUser = "sam"
+	defer func() { s.workUser = "" }()
+	start := time.Date(2025, 9, 1, 9, 0, 0, 0, time.Local)
+	session, err := s.CreateWorkSessionWithTimes(ctx, client.ID, start, start.Add(time.Hour), nil, client.HourlyRate, false)
+	if err != nil {
+		t.Fatalf("CreateWorkSessionWithTimes as a user: %v", err)
+	}
+	if session.UserID == nil || *session.UserID != user.ID {
+		t.Errorf("session user = %v, want %s", session.UserID, user.ID)
+	}
+
+	session, err = s.UpdateSessionUser(ctx, session.ID, nil, false)
+	if err != nil {
+		t.Fatalf("UpdateSessionUser: %v", err)
+	}
+	if session.UserID != nil {
+		t.Errorf("session user = %s after giving it back to the owner, want none", *session.UserID)
+	}
+
+	s.workUser = "nobody"
+	if _, err := s.CreateWorkSessionWithTimes(ctx, client.ID, start, start.Add(time.Hour), nil, client.HourlyRate, false); err == nil {
+		t.Error("CreateWorkSessionWithTimes with an unknown WORK_USER succeeded, want an error")
+	}
+}
+
+func testInvoiceStatus(ctx context.Context, t *testing.T, s *SQLiteDB, client *models.Client) {
+	t.Helper()
+	start := time.Date(2025, 5, 5, 9, 0, 0, 0, time.Local)
+	session, err := s.CreateWorkSessionWithTimes(ctx, client.ID, start, start.Add(2*time.Hour), nil, client.HourlyRate, false)
+	if err != nil {
+		t.Fatalf("CreateWorkSessionWithTimes: %v", err)
+	}
+	amount := decimal.RequireFromString("300.00")
+	invoice, err := s.CreateInvoice(ctx, client.ID, "INV-ACME-2025-05", "month", time.Date(2025, 5, 1, 0, 0, 0, 0, time.Local),
+		time.Date(2025, 5, 31, 23, 59, 59, 0, time.Local), amount, decimal.Zero, amount, models.InvoiceGroupBySession, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateInvoice: %v", err)
+	}
+	if invoice.Status != models.InvoiceStatusIssued {
+		t.Errorf("new invoice status = %q, want %q", invoice.Status, models.InvoiceStatusIssued)
+	}
+	if err := s.UpdateSessionInvoiceID(ctx, session.ID, invoice.ID); err != nil {
+		t.Fatalf("UpdateSessionInvoiceID: %v", err)
+	}
+	milestone, err := s.CreateMilestone(ctx, &models.Milestone{ClientID: client.ID, Name: "Design sign-off", Amount: decimal.RequireFromString("4000.00")})
+	if err != nil {
+		t.Fatalf("CreateMilestone: %v", err)
+	}
+	if err := s.CompleteMilestone(ctx, milestone.ID, start); err != nil {
+		t.Fatalf("CompleteMilestone: %v", err)
+	}
+	if unbilled, err := s.GetCompletedMilestonesWithoutInvoice(ctx, invoice.PeriodEndDate, &client.ID); err != nil || len(unbilled) != 1 {
+		t.Fatalf("GetCompletedMilestonesWithoutInvoice = %d milestone(s) (err %v), want 1", len(unbilled), err)
+	}
+	if err := s.UpdateMilestoneInvoiceID(ctx, milestone.ID, &invoice.ID); err != nil {
+		t.Fatalf("UpdateMilestoneInvoiceID: %v", err)
+	}
+
+	writtenOffOn := time.Date(2025, 8, 1, 12, 0, 0, 0, time.Local)
+	reason := "Client went under"
+	if err := s.UpdateInvoiceStatus(ctx, invoice.ID, models.InvoiceStatusWrittenOff, &reason, writtenOffOn, amount); err != nil {
+		t.Fatalf("UpdateInvoiceStatus: %v", err)
+	}
+	writtenOff, err := s.GetInvoiceByNumber(ctx, invoice.InvoiceNumber)
+	if err != nil {
+		t.Fatalf("GetInvoiceByNumber: %v", err)
+	}
+	if writtenOff.Status != models.InvoiceStatusWrittenOff || !writtenOff.AmountWrittenOff.Equal(amount) ||
+		writtenOff.StatusReason == nil || *writtenOff.StatusReason != reason ||
+		writtenOff.StatusChangedAt == nil || !writtenOff.StatusChangedAt.Equal(writtenOffOn) {
+		t.Errorf("written off invoice = %s, %s, %v, %v, want %s, %s, %q, %s", writtenOff.Status, writtenOff.AmountWrittenOff,
+			writtenOff.StatusReason, writtenOff.StatusChangedAt, models.InvoiceStatusWrittenOff, amount, reason, writtenOffOn)
+	}
+
+	// Voiding takes the sessions off the invoice so they can be invoiced again
+	if err := s.VoidInvoice(ctx, invoice.ID, nil, writtenOffOn); err != nil {
+		t.Fatalf("VoidInvoice: %v", err)
+	}
+	voided, err := s.GetInvoiceByID(ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("GetInvoiceByID: %v", err)
+	}
+	if voided.Status != models.InvoiceStatusVoid || !voided.AmountWrittenOff.IsZero() || voided.StatusReason != nil {
+		t.Errorf("voided invoice = %s, %s, %v, want void, 0, no reason", voided.Status, voided.AmountWrittenOff, voided.StatusReason)
+	}
+	if sessions, err := s.GetSessionsByInvoiceID(ctx, invoice.ID); err != nil || len(sessions) != 0 {
+		t.Errorf("void invoice has %d session(s) (err %v), want 0", len(sessions), err)
+	}
+	voidedMilestone, err := s.GetMilestoneByID(ctx, milestone.ID)
+	if err != nil {
+		t.Fatalf("GetMilestoneByID: %v", err)
+	}
+	if voidedMilestone.InvoiceID != nil || voidedMilestone.Status != models.MilestoneStatusComplete {
+		t.Errorf("milestone on void invoice = %s, invoice %v, want complete and not invoiced", voidedMilestone.Status, voidedMilestone.InvoiceID)
+	}
+}
+
+func testImportData(ctx context.Context, t *testing.T, s *SQLiteDB) {
+	t.Helper()
+	created := time.Date(2024, 7, 1, 8, 0, 0, 0, time.UTC)
+	start := time.Date(2024, 7, 2, 9, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	rate := decimal.RequireFromString("120")
+	client := &models.Client{ID: models.NewUUID(), Name: "Imported Co", HourlyRate: rate, CreatedAt: created, UpdatedAt: created}
+	session := &models.WorkSession{ID: models.NewUUID(), ClientID: client.ID, StartTime: start, EndTime: &end,
+		HourlyRate: &rate, BreakSeconds: 600, CreatedAt: created, UpdatedAt: created}
+
+	// Breaks are inserted after their session, adding up its break seconds again rather than doubling them
+	if err := s.ImportData(ctx, &ImportData{
+		Clients:  []*models.Client{client},
+		Sessions: []*models.WorkSession{session},
+		Breaks: []*models.SessionBreak{{ID: models.NewUUID(), SessionID: session.ID, StartTime: start.Add(time.Hour),
+			EndTime: start.Add(70 * time.Minute), Source: models.BreakSourceManual, CreatedAt: created}},
+	}, false); err != nil {
+		t.Fatalf("ImportData: %v", err)
+	}
+
+	imported, err := s.GetSessionByID(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("GetSessionByID: %v", err)
+	}
+	if imported.ClientID != client.ID || imported.BreakSeconds != 600 || !imported.CreatedAt.Equal(created) {
+		t.Errorf("imported session = client %s, %d break seconds, created %s, want %s, 600, %s",
+			imported.ClientID, imported.BreakSeconds, imported.CreatedAt, client.ID, created)
+	}
+
+	// A failed import leaves nothing behind
+	if err := s.ImportData(ctx, &ImportData{
+		Clients: []*models.Client{{ID: models.NewUUID(), Name: "Half Imported", CreatedAt: created, UpdatedAt: created}, client},
+	}, false); err == nil {
+		t.Error("ImportData of a client already present succeeded, want an error")
+	}
+	if _, err := s.GetClientByName(ctx, "Half Imported"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetClientByName after a failed import = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testSnapshot(ctx context.Context, t *testing.T, s *SQLiteDB) {
+	t.Helper()
+	if err := s.CheckConnection(ctx, 5*time.Second); err != nil {
+		t.Fatalf("CheckConnection: %v", err)
+	}
+	if version, err := s.Version(ctx); err != nil || version == "" {
+		t.Fatalf("Version = %q, %v", version, err)
+	}
+
+	clients, err := s.ListClients(ctx)
+	if err != nil {
+		t.Fatalf("ListClients: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "snapshot.db")
+	rows, err := s.SaveSnapshot(ctx, path)
+	if err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	if rows == 0 {
+		t.Error("SaveSnapshot copied no rows")
+	}
+
+	if _, err := s.UseSnapshot(path); err != nil {
+		t.Fatalf("UseSnapshot: %v", err)
+	}
+	if s.ReadOnlySnapshot() != path {
+		t.Errorf("ReadOnlySnapshot = %q, want %q", s.ReadOnlySnapshot(), path)
+	}
+	fromSnapshot, err := s.ListClients(ctx)
+	if err != nil {
+		t.Fatalf("ListClients from snapshot: %v", err)
+	}
+	if len(fromSnapshot) != len(clients) {
+		t.Errorf("snapshot has %d clients, want %d", len(fromSnapshot), len(clients))
+	}
+	if _, err := s.CreateClient(ctx, "snapshot-write", decimal.NewFromInt(100), nil, nil, nil, nil); err == nil {
+		t.Error("CreateClient on the read-only snapshot succeeded")
+	}
+}
+
+func testDiagnostics(ctx context.Context, t *testing.T, s *SQLiteDB) {
+	t.Helper()
+	if err := s.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+
+	columns, err := s.GetTableColumns(ctx, "sessions")
+	if err != nil {
+		t.Fatalf("GetTableColumns: %v", err)
+	}
+	if len(columns) == 0 {
+		t.Error("GetTableColumns returned no columns for sessions")
+	}
+
+	active, err := s.CountActiveSessions(ctx)
+	if err != nil {
+		t.Fatalf("CountActiveSessions: %v", err)
+	}
+	if active != 0 {
+		t.Errorf("found %d active session(s), want 0", active)
+	}
+
+	missing, err := s.GetSessionsWithMissingInvoice(ctx)
+	if err != nil {
+		t.Fatalf("GetSessionsWithMissingInvoice: %v", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("found %d session(s) linked to a missing invoice, want 0", len(missing))
+	}
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+)
+
+// busyRetryDelay is how long the first retry of a statement blocked by another process waits, doubling each time
+const busyRetryDelay = 100 * time.Millisecond
+
+// sqliteDSN adds the journal mode and busy timeout to a go-sqlite3 connection string as parameters, so every
+// connection the pool opens gets them, and has transactions take the write lock when they begin. A write lock
+// taken partway through a transaction fails straight away if another process holds one, without waiting out
+// the busy timeout. Parameters already in the connection string are left as they are.
+func sqliteDSN(cfg *config.Config) string {
+	var params []string
+	add := func(name, value string) {
+		if !strings.Contains(cfg.DatabaseURL, name+"=") {
+			params = append(params, name+"="+value)
+		}
+	}
+	if cfg.SQLiteJournalMode != "" {
+		add("_journal_mode", cfg.SQLiteJournalMode)
+	}
+	if cfg.SQLiteBusyTimeout > 0 {
+		add("_busy_timeout", fmt.Sprint(cfg.SQLiteBusyTimeout.Milliseconds()))
+	}
+	add("_txlock", "immediate")
+
+	if len(params) == 0 {
+		return cfg.DatabaseURL
+	}
+	separator := "?"
+	if strings.Contains(cfg.DatabaseURL, "?") {
+		separator = "&"
+	}
+	return cfg.DatabaseURL + separator + strings.Join(params, "&")
+}
+
+// isBusy reports whether err is SQLite refusing a statement because another connection holds a lock it needs
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	// The libsql client only passes on the message
+	return err != nil && strings.Contains(err.Error(), "database is locked")
+}
+
+// retryBusy runs op, running it again up to retries times with a growing delay while it fails with isBusy
+func retryBusy(ctx context.Context, retries int, op func() error) error {
+	delay := busyRetryDelay
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= retries || !isBusy(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// busyRetryConn runs the generated queries against the connection pool, retrying those that fail because
+// another process, such as a long description run, is writing to the database
+type busyRetryConn struct {
+	conn    *sql.DB
+	retries int
+}
+
+func (c *busyRetryConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := retryBusy(ctx, c.retries, func() error {
+		var err error
+		result, err = c.conn.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+func (c *busyRetryConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	var stmt *sql.Stmt
+	err := retryBusy(ctx, c.retries, func() error {
+		var err error
+		stmt, err = c.conn.PrepareContext(ctx, query)
+		return err
+	})
+	return stmt, err
+}
+
+func (c *busyRetryConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := retryBusy(ctx, c.retries, func() error {
+		var err error
+		rows, err = c.conn.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (c *busyRetryConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var row *sql.Row
+	retryBusy(ctx, c.retries, func() error {
+		row = c.conn.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+// beginTx begins a transaction, retrying while another process holds the write lock
+func (s *SQLiteDB) beginTx(ctx context.Context) (*sql.Tx, error) {
+	var tx *sql.Tx
+	err := retryBusy(ctx, s.busyRetries, func() error {
+		var err error
+		tx, err = s.conn.BeginTx(ctx, nil)
+		return err
+	})
+	return tx, err
+}
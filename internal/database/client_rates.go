@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/db"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// RecordClientRates adds changes to clients' rate history in a single transaction. Clients' rates themselves are
+// left as they are.
+func (s *SQLiteDB) RecordClientRates(ctx context.Context, changes []*models.ClientRate) ([]*models.ClientRate, error) {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	result := make([]*models.ClientRate, 0, len(changes))
+	for _, change := range changes {
+		rate, err := qtx.CreateClientRate(ctx, db.CreateClientRateParams{
+			ID:            models.NewUUID(),
+			ClientID:      change.ClientID,
+			PreviousRate:  change.PreviousRate,
+			HourlyRate:    change.HourlyRate,
+			EffectiveDate: change.EffectiveDate,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record client rate: %w", err)
+		}
+		recorded := convertDBClientRateToModel(rate)
+		recorded.ClientName = change.ClientName
+		result = append(result, recorded)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit client rates: %w", err)
+	}
+	return result, nil
+}
+
+// ListClientRates lists the changes to a client's rate, in the order they take effect
+func (s *SQLiteDB) ListClientRates(ctx context.Context, clientID string) ([]*models.ClientRate, error) {
+	rates, err := s.queries.ListClientRates(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list client rates: %w", err)
+	}
+
+	result := make([]*models.ClientRate, len(rates))
+	for i, rate := range rates {
+		result[i] = convertDBClientRateToModel(rate)
+	}
+	return result, nil
+}
+
+// UpdateClientRate sets a client's hourly rate without recording it in the rate history, for bringing it in line
+// with the change to it in effect today
+func (s *SQLiteDB) UpdateClientRate(ctx context.Context, clientID string, hourlyRate decimal.Decimal) error {
+	if err := s.queries.UpdateClientHourlyRate(ctx, db.UpdateClientHourlyRateParams{
+		HourlyRate: decimal.NullDecimal{Decimal: hourlyRate, Valid: true},
+		ID:         clientID,
+	}); err != nil {
+		return fmt.Errorf("failed to update client rate: %w", err)
+	}
+	return nil
+}
+
+func convertDBClientRateToModel(rate db.ClientRate) *models.ClientRate {
+	return &models.ClientRate{
+		ID:            rate.ID,
+		ClientID:      rate.ClientID,
+		PreviousRate:  rate.PreviousRate,
+		HourlyRate:    rate.HourlyRate,
+		EffectiveDate: rate.EffectiveDate,
+		CreatedAt:     rate.CreatedAt,
+	}
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jesses-code-adventures/work/internal/db"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+func (s *SQLiteDB) CreateClientContact(ctx context.Context, contact *models.ClientContact) (*models.ClientContact, error) {
+	created, err := s.queries.CreateClientContact(ctx, db.CreateClientContactParams{
+		ID:              models.NewUUID(),
+		ClientID:        contact.ClientID,
+		ContactType:     contact.Type,
+		Note:            contact.Note,
+		ContactedAt:     contact.ContactedAt,
+		DurationMinutes: ptrToNullInt64(contact.DurationMinutes),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client contact: %w", err)
+	}
+
+	result := convertDBClientContactToModel(db.GetClientContactByIDRow{
+		ID:              created.ID,
+		ClientID:        created.ClientID,
+		ContactType:     created.ContactType,
+		Note:            created.Note,
+		ContactedAt:     created.ContactedAt,
+		DurationMinutes: created.DurationMinutes,
+		SessionID:       created.SessionID,
+		CreatedAt:       created.CreatedAt,
+		UpdatedAt:       created.UpdatedAt,
+	})
+	result.ClientName = contact.ClientName
+	return result, nil
+}
+
+func (s *SQLiteDB) GetClientContactByID(ctx context.Context, contactID string) (*models.ClientContact, error) {
+	contact, err := s.queries.GetClientContactByID(ctx, contactID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get client contact: %w", err)
+	}
+
+	return convertDBClientContactToModel(contact), nil
+}
+
+// ListClientContacts lists the most recent contacts first, with every client's when clientID is nil
+func (s *SQLiteDB) ListClientContacts(ctx context.Context, clientID *string, limit int32) ([]*models.ClientContact, error) {
+	contacts, err := s.queries.ListClientContacts(ctx, db.ListClientContactsParams{
+		ClientID:   ptrToNullString(clientID),
+		LimitCount: int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list client contacts: %w", err)
+	}
+
+	result := make([]*models.ClientContact, len(contacts))
+	for i, contact := range contacts {
+		result[i] = convertDBClientContactToModel(db.GetClientContactByIDRow(contact))
+	}
+	return result, nil
+}
+
+// BillClientContact creates a finished session for a contact and links the contact to it in a single
+// transaction, so a contact is never billed twice
+func (s *SQLiteDB) BillClientContact(ctx context.Context, contactID string, session *models.WorkSession) (*models.WorkSession, error) {
+	userID, err := s.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	created, err := s.createFinishedSession(ctx, qtx, session, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := qtx.SetClientContactSession(ctx, db.SetClientContactSessionParams{
+		SessionID: sql.NullString{String: created.ID, Valid: true},
+		ID:        contactID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to link client contact to session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit client contact session: %w", err)
+	}
+	return created, nil
+}
+
+func (s *SQLiteDB) DeleteClientContact(ctx context.Context, contactID string) error {
+	if err := s.queries.DeleteClientContact(ctx, contactID); err != nil {
+		return fmt.Errorf("failed to delete client contact: %w", err)
+	}
+	return nil
+}
+
+func convertDBClientContactToModel(contact db.GetClientContactByIDRow) *models.ClientContact {
+	return &models.ClientContact{
+		ID:              contact.ID,
+		ClientID:        contact.ClientID,
+		Type:            contact.ContactType,
+		Note:            contact.Note,
+		ContactedAt:     contact.ContactedAt,
+		DurationMinutes: nullInt64ToPtr(contact.DurationMinutes),
+		SessionID:       nullStringToPtr(contact.SessionID),
+		CreatedAt:       contact.CreatedAt,
+		UpdatedAt:       contact.UpdatedAt,
+		ClientName:      contact.ClientName,
+	}
+}
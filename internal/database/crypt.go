@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jesses-code-adventures/work/internal/db"
+)
+
+// encryptedPrefix marks values encrypted by fieldCipher. Values without it are plaintext, such as those
+// saved before encryption was turned on, and are read as they are.
+const encryptedPrefix = "enc:v1:"
+
+// ErrNoEncryptionKey is returned when reading or encrypting fields without ENCRYPTION_KEY set
+var ErrNoEncryptionKey = errors.New("client details are encrypted, set ENCRYPTION_KEY to read them")
+
+// fieldCipher encrypts individual column values with AES-256-GCM
+type fieldCipher struct {
+	aead cipher.AEAD
+}
+
+func newFieldCipher(key []byte) (*fieldCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return &fieldCipher{aead: aead}, nil
+}
+
+func (c *fieldCipher) encrypt(value string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *fieldCipher) decrypt(value string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt, check ENCRYPTION_KEY is the key the data was encrypted with")
+	}
+	return string(plaintext), nil
+}
+
+// clientContactFields are the client columns holding personal details, which are encrypted when a key is set
+func clientContactFields(client *db.Client) []*sql.NullString {
+	return []*sql.NullString{&client.ContactName, &client.Email, &client.Phone, &client.AddressLine1, &client.AddressLine2, &client.Abn}
+}
+
+// encryptField encrypts a value for saving, leaving it as it is when encryption is off
+func (s *SQLiteDB) encryptField(value *sql.NullString) error {
+	if s.cipher == nil || !value.Valid || strings.HasPrefix(value.String, encryptedPrefix) {
+		return nil
+	}
+	encrypted, err := s.cipher.encrypt(value.String)
+	if err != nil {
+		return err
+	}
+	value.String = encrypted
+	return nil
+}
+
+// decryptField decrypts a value read from the database, leaving plaintext as it is
+func (s *SQLiteDB) decryptField(value *sql.NullString) error {
+	if !value.Valid || !strings.HasPrefix(value.String, encryptedPrefix) {
+		return nil
+	}
+	if s.cipher == nil {
+		return ErrNoEncryptionKey
+	}
+	decrypted, err := s.cipher.decrypt(value.String)
+	if err != nil {
+		return err
+	}
+	value.String = decrypted
+	return nil
+}
+
+// EncryptClientFields encrypts the contact details of every client saved before encryption was turned on,
+// returning how many clients were changed
+func (s *SQLiteDB) EncryptClientFields(ctx context.Context) (int, error) {
+	if s.cipher == nil {
+		return 0, fmt.Errorf("set ENCRYPTION_KEY to encrypt client details")
+	}
+	return s.rewriteClientContactFields(ctx, s.encryptField)
+}
+
+// DecryptClientFields stores every client's contact details as plaintext again, returning how many clients
+// were changed
+func (s *SQLiteDB) DecryptClientFields(ctx context.Context) (int, error) {
+	return s.rewriteClientContactFields(ctx, s.decryptField)
+}
+
+func (s *SQLiteDB) rewriteClientContactFields(ctx context.Context, rewrite func(*sql.NullString) error) (int, error) {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+	clients, err := qtx.ListClients(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list clients: %w", err)
+	}
+
+	changed := 0
+	for _, client := range clients {
+		dirty := false
+		for _, field := range clientContactFields(&client) {
+			before := *field
+			if err := rewrite(field); err != nil {
+				return 0, fmt.Errorf("client '%s': %w", client.Name, err)
+			}
+			dirty = dirty || *field != before
+		}
+		if !dirty {
+			continue
+		}
+		err := qtx.UpdateClientContactFields(ctx, db.UpdateClientContactFieldsParams{
+			ContactName:  client.ContactName,
+			Email:        client.Email,
+			Phone:        client.Phone,
+			AddressLine1: client.AddressLine1,
+			AddressLine2: client.AddressLine2,
+			Abn:          client.Abn,
+			ID:           client.ID,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to update client '%s': %w", client.Name, err)
+		}
+		changed++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit client details: %w", err)
+	}
+	return changed, nil
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jesses-code-adventures/work/internal/db"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+func (s *SQLiteDB) CreateExpenseRule(ctx context.Context, rule *models.ExpenseRule) (*models.ExpenseRule, error) {
+	created, err := s.queries.CreateExpenseRule(ctx, db.CreateExpenseRuleParams{
+		ID:       models.NewUUID(),
+		Pattern:  rule.Pattern,
+		ClientID: ptrToNullString(rule.ClientID),
+		Category: ptrToNullString(rule.Category),
+		Billable: rule.Billable,
+		Skip:     rule.Skip,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create expense rule: %w", err)
+	}
+
+	result := convertDBExpenseRuleToModel(db.GetExpenseRuleByIDRow{
+		ID:        created.ID,
+		Pattern:   created.Pattern,
+		ClientID:  created.ClientID,
+		Category:  created.Category,
+		Billable:  created.Billable,
+		Skip:      created.Skip,
+		CreatedAt: created.CreatedAt,
+	})
+	result.ClientName = rule.ClientName
+	return result, nil
+}
+
+func (s *SQLiteDB) GetExpenseRuleByID(ctx context.Context, ruleID string) (*models.ExpenseRule, error) {
+	rule, err := s.queries.GetExpenseRuleByID(ctx, ruleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get expense rule: %w", err)
+	}
+
+	return convertDBExpenseRuleToModel(rule), nil
+}
+
+// ListExpenseRules lists rules in the order they were added, which is the order they're tried in
+func (s *SQLiteDB) ListExpenseRules(ctx context.Context) ([]*models.ExpenseRule, error) {
+	rules, err := s.queries.ListExpenseRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expense rules: %w", err)
+	}
+
+	result := make([]*models.ExpenseRule, len(rules))
+	for i, rule := range rules {
+		result[i] = convertDBExpenseRuleToModel(db.GetExpenseRuleByIDRow(rule))
+	}
+	return result, nil
+}
+
+func (s *SQLiteDB) DeleteExpenseRule(ctx context.Context, ruleID string) error {
+	if err := s.queries.DeleteExpenseRule(ctx, ruleID); err != nil {
+		return fmt.Errorf("failed to delete expense rule: %w", err)
+	}
+	return nil
+}
+
+func convertDBExpenseRuleToModel(rule db.GetExpenseRuleByIDRow) *models.ExpenseRule {
+	return &models.ExpenseRule{
+		ID:         rule.ID,
+		Pattern:    rule.Pattern,
+		ClientID:   nullStringToPtr(rule.ClientID),
+		Category:   nullStringToPtr(rule.Category),
+		Billable:   rule.Billable,
+		Skip:       rule.Skip,
+		CreatedAt:  rule.CreatedAt,
+		ClientName: nullStringToPtr(rule.ClientName),
+	}
+}
@@ -0,0 +1,211 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/db"
+)
+
+// ErrUnreachable is wrapped by the error from checking a remote database that can't be reached
+var ErrUnreachable = errors.New("database can't be reached")
+
+// authTokenPattern matches the token in a libsql URL, so it's never printed
+var authTokenPattern = regexp.MustCompile(`(?i)(authToken=)[^&]+`)
+
+// RedactURL hides the auth token in a database URL
+func RedactURL(url string) string {
+	return authTokenPattern.ReplaceAllString(url, "${1}***")
+}
+
+// IsRemote reports whether the database is reached over the network rather than opened as a local file
+func IsRemote(cfg *config.Config) bool {
+	if cfg.DatabaseDriver != "libsql" {
+		return false
+	}
+	return !strings.HasPrefix(cfg.DatabaseURL, "file:")
+}
+
+// CheckConnection runs a query on the database, giving up after timeout. The libsql driver doesn't connect to
+// ping, so a query is the only way to know the server answers. An error explains the likely cause and wraps
+// ErrUnreachable.
+func (s *SQLiteDB) CheckConnection(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var one int
+	if err := s.conn.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		return fmt.Errorf("%w: %s", ErrUnreachable, diagnoseConnection(err, timeout))
+	}
+	return nil
+}
+
+// diagnoseConnection describes why a connection failed and what to check
+func diagnoseConnection(err error, timeout time.Duration) string {
+	message := strings.ReplaceAll(RedactURL(err.Error()), "\n", " ")
+	lower := strings.ToLower(message)
+
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &dnsErr) || strings.Contains(lower, "no such host"):
+		return message + ", the host wasn't found, check the host name in DATABASE_URL and that you're online"
+	case errors.Is(err, context.DeadlineExceeded) || strings.Contains(lower, "timeout") || strings.Contains(lower, "deadline exceeded"):
+		return fmt.Sprintf("%s, no response within %s, check your connection or raise DB_CONNECT_TIMEOUT", message, timeout)
+	case strings.Contains(lower, "401") || strings.Contains(lower, "unauthorized") || strings.Contains(lower, "authentication"):
+		return message + ", the auth token was refused, check the authToken in DATABASE_URL hasn't expired or been revoked"
+	case strings.Contains(lower, "404") || strings.Contains(lower, "not found"):
+		return message + ", the database wasn't found, check the database name in DATABASE_URL"
+	case strings.Contains(lower, "certificate") || strings.Contains(lower, "tls"):
+		return message + ", the secure connection failed, check the system clock and any proxy intercepting TLS"
+	case errors.As(err, &opErr) || strings.Contains(lower, "connection refused"):
+		return message + ", the connection failed, check you're online and the URL's host and port"
+	}
+	return message
+}
+
+// Version returns the SQLite version of the database, which for libsql is the server's
+func (s *SQLiteDB) Version(ctx context.Context) (string, error) {
+	var version string
+	if err := s.conn.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err != nil {
+		return "", fmt.Errorf("failed to get database version: %w", err)
+	}
+	return version, nil
+}
+
+// ReadOnlySnapshot returns the path of the snapshot the database fell back to, or empty when it's the
+// configured database
+func (s *SQLiteDB) ReadOnlySnapshot() string {
+	return s.snapshot
+}
+
+// UseSnapshot swaps the connection for a read-only one to a snapshot saved by SaveSnapshot, for when the
+// database can't be reached. Returns when the snapshot was saved.
+func (s *SQLiteDB) UseSnapshot(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to open database snapshot: %w", err)
+	}
+	conn, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to open database snapshot: %w", err)
+	}
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return time.Time{}, fmt.Errorf("failed to open database snapshot: %w", err)
+	}
+
+	s.conn.Close()
+	s.conn = conn
+	s.queries = db.New(&busyRetryConn{conn: conn, retries: s.busyRetries})
+	s.snapshot = path
+	return info.ModTime(), nil
+}
+
+// SaveSnapshot copies every table and index to a SQLite database at path, replacing it once the copy is
+// complete, to read from while the database can't be reached
+func (s *SQLiteDB) SaveSnapshot(ctx context.Context, path string) (int, error) {
+	tmp := path + ".tmp"
+	os.Remove(tmp)
+	dest, err := sql.Open("sqlite3", "file:"+tmp)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create database snapshot: %w", err)
+	}
+	defer os.Remove(tmp)
+	defer dest.Close()
+
+	rows, err := s.conn.QueryContext(ctx, "SELECT type, name, sql FROM sqlite_master WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%' ORDER BY type != 'table', name")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read database schema: %w", err)
+	}
+	type object struct{ kind, name, sql string }
+	var objects []object
+	for rows.Next() {
+		var o object
+		if err := rows.Scan(&o.kind, &o.name, &o.sql); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to read database schema: %w", err)
+		}
+		objects = append(objects, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read database schema: %w", err)
+	}
+
+	tx, err := dest.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create database snapshot: %w", err)
+	}
+	defer tx.Rollback()
+	// Tables are filled before indexes and triggers are created, so triggers don't fire on the copied rows
+	copied := 0
+	for _, o := range objects {
+		if _, err := tx.ExecContext(ctx, o.sql); err != nil {
+			return 0, fmt.Errorf("failed to create %s %s in snapshot: %w", o.kind, o.name, err)
+		}
+		if o.kind != "table" {
+			continue
+		}
+		n, err := s.copyTable(ctx, tx, o.name)
+		if err != nil {
+			return 0, err
+		}
+		copied += n
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to save database snapshot: %w", err)
+	}
+	if err := dest.Close(); err != nil {
+		return 0, fmt.Errorf("failed to save database snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return 0, fmt.Errorf("failed to save database snapshot: %w", err)
+	}
+	return copied, nil
+}
+
+// copyTable copies the rows of a table into the snapshot being written by tx
+func (s *SQLiteDB) copyTable(ctx context.Context, tx *sql.Tx, table string) (int, error) {
+	rows, err := s.conn.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %q", table))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+	insert, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %q VALUES (%s)", table, strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")))
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy %s: %w", table, err)
+	}
+	defer insert.Close()
+
+	copied := 0
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", table, err)
+		}
+		if _, err := insert.ExecContext(ctx, values...); err != nil {
+			return 0, fmt.Errorf("failed to copy %s: %w", table, err)
+		}
+		copied++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+	return copied, nil
+}
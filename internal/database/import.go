@@ -0,0 +1,360 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/db"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// ImportData is a set of records to insert as they are, IDs and timestamps included. References between them
+// must already point at IDs that exist once they're inserted.
+type ImportData struct {
+	Clients     []*models.Client
+	Users       []*models.User
+	ClientRates []*models.ClientRate
+	Templates   []*models.SessionTemplate
+	Invoices    []*models.Invoice
+	Milestones  []*models.Milestone
+	Sessions    []*models.WorkSession
+	Breaks      []*models.SessionBreak
+	Repos       []*models.SessionRepo
+	Contacts    []*models.ClientContact
+	Payments    []*models.Payment
+	CreditNotes []*models.CreditNote
+	Expenses    []*models.Expense
+	Rules       []*models.ExpenseRule
+	Leave       []*models.Leave
+}
+
+// ImportData inserts records in a single transaction, so either all of them are imported or none are. With
+// replace, every client, user, session, invoice, payment, credit note, expense and template is deleted first,
+// along with clients' rate history, contact log and milestones, the rules for importing statements and leave.
+func (s *SQLiteDB) ImportData(ctx context.Context, data *ImportData, replace bool) error {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	if replace {
+		for _, deleteAll := range []func(context.Context) error{
+			qtx.DeleteAllSessionBreaks, qtx.DeleteAllPayments, qtx.DeleteAllCreditNotes, qtx.DeleteAllExpenses,
+			qtx.DeleteAllExpenseRules, qtx.DeleteAllClientContacts, qtx.DeleteAllMilestones, qtx.DeleteAllSessions,
+			qtx.DeleteAllUsers, qtx.DeleteAllSessionTemplates, qtx.DeleteAllInvoices, qtx.DeleteAllClientRates,
+			qtx.DeleteAllClients, qtx.DeleteAllLeave,
+		} {
+			if err := deleteAll(ctx); err != nil {
+				return fmt.Errorf("failed to clear existing data: %w", err)
+			}
+		}
+	}
+
+	for _, client := range data.Clients {
+		params := db.Client{
+			ID:                   client.ID,
+			Name:                 client.Name,
+			CreatedAt:            client.CreatedAt,
+			UpdatedAt:            client.UpdatedAt,
+			HourlyRate:           decimal.NullDecimal{Decimal: client.HourlyRate, Valid: true},
+			CompanyName:          ptrToNullString(client.CompanyName),
+			ContactName:          ptrToNullString(client.ContactName),
+			Email:                ptrToNullString(client.Email),
+			Phone:                ptrToNullString(client.Phone),
+			AddressLine1:         ptrToNullString(client.AddressLine1),
+			AddressLine2:         ptrToNullString(client.AddressLine2),
+			City:                 ptrToNullString(client.City),
+			State:                ptrToNullString(client.State),
+			PostalCode:           ptrToNullString(client.PostalCode),
+			Country:              ptrToNullString(client.Country),
+			Dir:                  ptrToNullString(client.Dir),
+			Abn:                  ptrToNullString(client.Abn),
+			RetainerAmount:       ptrToNullDecimal(client.RetainerAmount),
+			RetainerHours:        ptrToNullFloat64(client.RetainerHours),
+			RetainerBasis:        ptrToNullString(client.RetainerBasis),
+			Currency:             ptrToNullString(client.Currency),
+			Locale:               ptrToNullString(client.Locale),
+			Source:               ptrToNullString(client.Source),
+			WorkLog:              ptrToNullString(client.WorkLog),
+			RetainerStartDate:    ptrToNullTime(client.RetainerStart),
+			EarlyDiscountPercent: ptrToNullFloat64(client.EarlyDiscountPercent),
+			EarlyDiscountDays:    ptrToNullInt64(client.EarlyDiscountDays),
+			InvoiceNotes:         ptrToNullString(client.InvoiceNotes),
+			PaymentTerms:         ptrToNullString(client.PaymentTerms),
+			PoNumber:             ptrToNullString(client.PoNumber),
+			ProjectCode:          ptrToNullString(client.ProjectCode),
+			InvoiceRounding:      ptrToNullDecimal(client.InvoiceRounding),
+			TaxTreatment:         client.TaxTreatment,
+			LogoPath:             ptrToNullString(client.LogoPath),
+			BrandColour:          ptrToNullString(client.BrandColour),
+			AnalysisMaxCommits:   ptrToNullInt64(client.AnalysisMaxCommits),
+			AnalysisIgnore:       ptrToNullString(client.AnalysisIgnore),
+			AnalysisDetail:       ptrToNullString(client.AnalysisDetail),
+			AnalysisPrompt:       ptrToNullString(client.AnalysisPrompt),
+		}
+		// Exports from before clients had a tax treatment are taxable
+		if params.TaxTreatment == "" {
+			params.TaxTreatment = models.TaxTreatmentTaxable
+		}
+		for _, field := range clientContactFields(&params) {
+			if err := s.encryptField(field); err != nil {
+				return fmt.Errorf("failed to encrypt client details: %w", err)
+			}
+		}
+		if err := qtx.ImportClient(ctx, db.ImportClientParams(params)); err != nil {
+			return fmt.Errorf("failed to import client '%s': %w", client.Name, err)
+		}
+	}
+
+	for _, rate := range data.ClientRates {
+		if err := qtx.ImportClientRate(ctx, db.ImportClientRateParams{
+			ID:            rate.ID,
+			ClientID:      rate.ClientID,
+			PreviousRate:  rate.PreviousRate,
+			HourlyRate:    rate.HourlyRate,
+			EffectiveDate: rate.EffectiveDate,
+			CreatedAt:     rate.CreatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to import client rate %s: %w", rate.ID, err)
+		}
+	}
+
+	for _, user := range data.Users {
+		if err := qtx.ImportUser(ctx, db.ImportUserParams{
+			ID:        user.ID,
+			Name:      user.Name,
+			CostRate:  ptrToNullDecimal(user.CostRate),
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to import user '%s': %w", user.Name, err)
+		}
+	}
+
+	for _, template := range data.Templates {
+		if err := qtx.ImportSessionTemplate(ctx, db.ImportSessionTemplateParams{
+			ID:              template.ID,
+			Name:            template.Name,
+			ClientID:        template.ClientID,
+			Description:     ptrToNullString(template.Description),
+			DurationMinutes: ptrToNullInt64(template.DurationMinutes),
+			CreatedAt:       template.CreatedAt,
+			UpdatedAt:       template.UpdatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to import template '%s': %w", template.Name, err)
+		}
+	}
+
+	for _, invoice := range data.Invoices {
+		// Exports from before invoices had a status only held issued invoices
+		status := invoice.Status
+		if status == "" {
+			status = models.InvoiceStatusIssued
+		}
+		if err := qtx.ImportInvoice(ctx, db.ImportInvoiceParams{
+			ID:                invoice.ID,
+			ClientID:          invoice.ClientID,
+			InvoiceNumber:     invoice.InvoiceNumber,
+			PeriodType:        invoice.PeriodType,
+			PeriodStartDate:   invoice.PeriodStartDate,
+			PeriodEndDate:     invoice.PeriodEndDate,
+			SubtotalAmount:    invoice.SubtotalAmount,
+			GstAmount:         invoice.GstAmount,
+			TotalAmount:       invoice.TotalAmount,
+			GeneratedDate:     invoice.GeneratedDate,
+			CreatedAt:         invoice.CreatedAt,
+			UpdatedAt:         invoice.UpdatedAt,
+			GroupBy:           invoice.GroupBy,
+			NeedsRegeneration: invoice.NeedsRegeneration,
+			Notes:             ptrToNullString(invoice.Notes),
+			PaymentTerms:      ptrToNullString(invoice.PaymentTerms),
+			PoNumber:          ptrToNullString(invoice.PoNumber),
+			ProjectCode:       ptrToNullString(invoice.ProjectCode),
+			Status:            status,
+			StatusReason:      ptrToNullString(invoice.StatusReason),
+			StatusChangedAt:   ptrToNullTime(invoice.StatusChangedAt),
+			AmountWrittenOff:  invoice.AmountWrittenOff,
+			Rounding:          ptrToNullDecimal(invoice.Rounding),
+		}); err != nil {
+			return fmt.Errorf("failed to import invoice %s: %w", invoice.InvoiceNumber, err)
+		}
+	}
+
+	for _, milestone := range data.Milestones {
+		if err := qtx.ImportMilestone(ctx, db.ImportMilestoneParams{
+			ID:            milestone.ID,
+			ClientID:      milestone.ClientID,
+			Name:          milestone.Name,
+			Amount:        milestone.Amount,
+			DueDate:       ptrToNullTime(milestone.DueDate),
+			Status:        milestone.Status,
+			CompletedDate: ptrToNullTime(milestone.CompletedDate),
+			InvoiceID:     ptrToNullString(milestone.InvoiceID),
+			CreatedAt:     milestone.CreatedAt,
+			UpdatedAt:     milestone.UpdatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to import milestone '%s': %w", milestone.Name, err)
+		}
+	}
+
+	// Inserting a break adds it to its session's break seconds, so sessions with breaks start from none
+	withBreaks := make(map[string]bool)
+	for _, sessionBreak := range data.Breaks {
+		withBreaks[sessionBreak.SessionID] = true
+	}
+	for _, session := range data.Sessions {
+		breakSeconds := session.BreakSeconds
+		if withBreaks[session.ID] {
+			breakSeconds = 0
+		}
+		if err := qtx.ImportSession(ctx, db.ImportSessionParams{
+			ID:              session.ID,
+			ClientID:        session.ClientID,
+			StartTime:       session.StartTime,
+			EndTime:         ptrToNullTime(session.EndTime),
+			Description:     ptrToNullString(session.Description),
+			CreatedAt:       session.CreatedAt,
+			UpdatedAt:       session.UpdatedAt,
+			HourlyRate:      ptrToNullDecimal(session.HourlyRate),
+			FullWorkSummary: ptrToNullString(session.FullWorkSummary),
+			OutsideGit:      ptrToNullString(session.OutsideGit),
+			InvoiceID:       ptrToNullString(session.InvoiceID),
+			IncludesGst:     session.IncludesGst,
+			Hostname:        ptrToNullString(session.Hostname),
+			Os:              ptrToNullString(session.OS),
+			GitBranch:       ptrToNullString(session.GitBranch),
+			BreakSeconds:    breakSeconds,
+			UserID:          ptrToNullString(session.UserID),
+		}); err != nil {
+			return fmt.Errorf("failed to import session %s: %w", session.ID, err)
+		}
+	}
+
+	for _, sessionBreak := range data.Breaks {
+		if err := qtx.ImportSessionBreak(ctx, db.ImportSessionBreakParams{
+			ID:        sessionBreak.ID,
+			SessionID: sessionBreak.SessionID,
+			StartTime: sessionBreak.StartTime,
+			EndTime:   sessionBreak.EndTime,
+			Source:    sessionBreak.Source,
+			CreatedAt: sessionBreak.CreatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to import session break %s: %w", sessionBreak.ID, err)
+		}
+	}
+
+	for _, repo := range data.Repos {
+		if err := qtx.ImportSessionRepo(ctx, db.ImportSessionRepoParams{
+			SessionID:   repo.SessionID,
+			RepoPath:    repo.RepoPath,
+			CommitCount: repo.CommitCount,
+			CreatedAt:   repo.CreatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to import repository %s of session %s: %w", repo.RepoPath, repo.SessionID, err)
+		}
+	}
+
+	for _, contact := range data.Contacts {
+		if err := qtx.ImportClientContact(ctx, db.ImportClientContactParams{
+			ID:              contact.ID,
+			ClientID:        contact.ClientID,
+			ContactType:     contact.Type,
+			Note:            contact.Note,
+			ContactedAt:     contact.ContactedAt,
+			DurationMinutes: ptrToNullInt64(contact.DurationMinutes),
+			SessionID:       ptrToNullString(contact.SessionID),
+			CreatedAt:       contact.CreatedAt,
+			UpdatedAt:       contact.UpdatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to import client contact %s: %w", contact.ID, err)
+		}
+	}
+
+	for _, payment := range data.Payments {
+		if err := qtx.ImportPayment(ctx, db.ImportPaymentParams{
+			ID:             payment.ID,
+			InvoiceID:      payment.InvoiceID,
+			Amount:         payment.Amount,
+			PaymentDate:    payment.PaymentDate,
+			CreatedAt:      payment.CreatedAt,
+			UpdatedAt:      payment.UpdatedAt,
+			DiscountAmount: payment.DiscountAmount,
+		}); err != nil {
+			return fmt.Errorf("failed to import payment %s: %w", payment.ID, err)
+		}
+	}
+
+	for _, creditNote := range data.CreditNotes {
+		if err := qtx.ImportCreditNote(ctx, db.ImportCreditNoteParams{
+			ID:               creditNote.ID,
+			InvoiceID:        creditNote.InvoiceID,
+			CreditNoteNumber: creditNote.CreditNoteNumber,
+			Amount:           creditNote.Amount,
+			GstAmount:        creditNote.GstAmount,
+			Reason:           creditNote.Reason,
+			IssuedDate:       creditNote.IssuedDate,
+			CreatedAt:        creditNote.CreatedAt,
+			UpdatedAt:        creditNote.UpdatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to import credit note %s: %w", creditNote.CreditNoteNumber, err)
+		}
+	}
+
+	for _, expense := range data.Expenses {
+		if err := qtx.ImportExpense(ctx, db.ImportExpenseParams{
+			ID:            expense.ID,
+			Amount:        expense.Amount,
+			CreatedAt:     expense.CreatedAt,
+			UpdatedAt:     expense.UpdatedAt,
+			ExpenseDate:   expense.ExpenseDate,
+			Reference:     ptrToNullString(expense.Reference),
+			ClientID:      ptrToNullString(expense.ClientID),
+			InvoiceID:     ptrToNullString(expense.InvoiceID),
+			Description:   ptrToNullString(expense.Description),
+			Category:      ptrToNullString(expense.Category),
+			MarkupPercent: ptrToNullDecimal(expense.MarkupPercent),
+			Billable:      expense.Billable,
+			Draft:         expense.Draft,
+			ReceiptPath:   ptrToNullString(expense.ReceiptPath),
+			DistanceKm:    ptrToNullDecimal(expense.DistanceKm),
+			RatePerKm:     ptrToNullDecimal(expense.RatePerKm),
+			GstAmount:     ptrToNullDecimal(expense.GstAmount),
+		}); err != nil {
+			return fmt.Errorf("failed to import expense %s: %w", expense.ID, err)
+		}
+	}
+
+	for _, rule := range data.Rules {
+		if err := qtx.ImportExpenseRule(ctx, db.ImportExpenseRuleParams{
+			ID:        rule.ID,
+			Pattern:   rule.Pattern,
+			ClientID:  ptrToNullString(rule.ClientID),
+			Category:  ptrToNullString(rule.Category),
+			Billable:  rule.Billable,
+			Skip:      rule.Skip,
+			CreatedAt: rule.CreatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to import expense rule '%s': %w", rule.Pattern, err)
+		}
+	}
+
+	for _, leave := range data.Leave {
+		if err := qtx.ImportLeave(ctx, db.ImportLeaveParams{
+			ID:        leave.ID,
+			LeaveType: leave.Type,
+			StartDate: leave.StartDate,
+			EndDate:   leave.EndDate,
+			Note:      ptrToNullString(leave.Note),
+			CreatedAt: leave.CreatedAt,
+			UpdatedAt: leave.UpdatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to import leave %s: %w", leave.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
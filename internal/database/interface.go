@@ -10,53 +10,117 @@ import (
 )
 
 type ClientUpdateDetails struct {
-	HourlyRate     *decimal.Decimal
-	CompanyName    *string
-	ContactName    *string
-	Email          *string
-	Phone          *string
-	AddressLine1   *string
-	AddressLine2   *string
-	City           *string
-	State          *string
-	PostalCode     *string
-	Country        *string
-	Abn            *string
-	Dir            *string
-	RetainerAmount *decimal.Decimal
-	RetainerHours  *float64
-	RetainerBasis  *string
+	HourlyRate           *decimal.Decimal // left as it is when nil
+	CompanyName          *string
+	ContactName          *string
+	Email                *string
+	Phone                *string
+	AddressLine1         *string
+	AddressLine2         *string
+	City                 *string
+	State                *string
+	PostalCode           *string
+	Country              *string
+	Abn                  *string
+	Dir                  *string
+	RetainerAmount       *decimal.Decimal
+	RetainerHours        *float64
+	RetainerBasis        *string
+	Currency             *string          // left as it is when nil, and cleared when empty
+	Locale               *string          // left as it is when nil, and cleared when empty
+	Source               *string          // left as it is when nil, and cleared when empty
+	WorkLog              *string          // left as it is when nil, and cleared when empty
+	RetainerStart        *time.Time       // left as it is when nil
+	EarlyDiscountPercent *float64         // left as it is when nil, and cleared when zero
+	EarlyDiscountDays    *int64           // left as it is when nil, and cleared when zero
+	InvoiceNotes         *string          // left as it is when nil, and cleared when empty
+	PaymentTerms         *string          // left as it is when nil, and cleared when empty
+	PoNumber             *string          // left as it is when nil, and cleared when empty
+	ProjectCode          *string          // left as it is when nil, and cleared when empty
+	InvoiceRounding      *decimal.Decimal // left as it is when nil, and cleared when zero
+	TaxTreatment         *string          // left as it is when nil
+	LogoPath             *string          // left as it is when nil, and cleared when empty
+	BrandColour          *string          // left as it is when nil, and cleared when empty
+	AnalysisMaxCommits   *int64           // left as it is when nil, and cleared when negative
+	AnalysisIgnore       *string          // left as it is when nil, and cleared when empty
+	AnalysisDetail       *string          // left as it is when nil, and cleared when empty
+	AnalysisPrompt       *string          // left as it is when nil, and cleared when empty
 }
 
 type DB interface {
 	Close() error
+	Ping(ctx context.Context) error
+	// CheckConnection pings the database within timeout, explaining why it can't be reached
+	CheckConnection(ctx context.Context, timeout time.Duration) error
+	Version(ctx context.Context) (string, error)
+	// SaveSnapshot copies the database to a local file, for UseSnapshot to read while it can't be reached
+	SaveSnapshot(ctx context.Context, path string) (int, error)
+	UseSnapshot(path string) (time.Time, error)
+	ReadOnlySnapshot() string
+	GetTableColumns(ctx context.Context, table string) ([]string, error)
+	Sync(ctx context.Context) (*SyncStatus, error)
+	SyncStatus(ctx context.Context) (*SyncStatus, error)
 
 	CreateClient(ctx context.Context, name string, hourlyRate decimal.Decimal, retainerAmount *decimal.Decimal, retainerHours *float64, retainerBasis, dir *string) (*models.Client, error)
 	GetClientByName(ctx context.Context, name string) (*models.Client, error)
+	GetClientActivity(ctx context.Context, clientID string) (*models.ClientActivity, error)
+	GetClientHoursSince(ctx context.Context, clientID string, since time.Time) (float64, error)
 	GetClientByID(ctx context.Context, ID string) (*models.Client, error)
 	ListClients(ctx context.Context) ([]*models.Client, error)
 	GetClientsWithDirectories(ctx context.Context) ([]*models.Client, error)
 	UpdateClient(ctx context.Context, clientID string, billing *ClientUpdateDetails) (*models.Client, error)
+	RecordClientRates(ctx context.Context, changes []*models.ClientRate) ([]*models.ClientRate, error)
+	ListClientRates(ctx context.Context, clientID string) ([]*models.ClientRate, error)
+	UpdateClientRate(ctx context.Context, clientID string, hourlyRate decimal.Decimal) error
+	EncryptClientFields(ctx context.Context) (int, error)
+	DecryptClientFields(ctx context.Context) (int, error)
 
 	CreateWorkSession(ctx context.Context, clientID string, description *string, hourlyRate decimal.Decimal, includesGst bool) (*models.WorkSession, error)
 	CreateWorkSessionWithStartTime(ctx context.Context, clientID string, startTime time.Time, description *string, hourlyRate decimal.Decimal, includesGst bool) (*models.WorkSession, error)
 	CreateWorkSessionWithTimes(ctx context.Context, clientID string, startTime, endTime time.Time, description *string, hourlyRate decimal.Decimal, includesGst bool) (*models.WorkSession, error)
+	CreateWorkSessions(ctx context.Context, sessions []*models.WorkSession) ([]*models.WorkSession, error)
 	GetActiveSession(ctx context.Context) (*models.WorkSession, error)
-	StopWorkSession(ctx context.Context, sessionID string) (*models.WorkSession, error)
+	StopWorkSession(ctx context.Context, sessionID string, endTime time.Time) (*models.WorkSession, error)
 	ListRecentSessions(ctx context.Context, limit int32) ([]*models.WorkSession, error)
+	ListSessionIDsByPrefix(ctx context.Context, prefix string, limit int32) ([]string, error)
 	ListSessionsWithDateRange(ctx context.Context, fromDate, toDate string, limit int32) ([]*models.WorkSession, error)
 	ListSessionsByClient(ctx context.Context, clientName string, limit int32) ([]*models.WorkSession, error)
 	GetSessionsWithoutDescription(ctx context.Context, clientName *string, sessionID *string) ([]*models.WorkSession, error)
 	GetSessionByID(ctx context.Context, sessionID string) (*models.WorkSession, error)
-	UpdateSessionDescription(ctx context.Context, sessionID string, description string, fullWorkSummary *string) (*models.WorkSession, error)
-	UpdateSessionOutsideGit(ctx context.Context, sessionID string, outsideGit string) (*models.WorkSession, error)
-	DeleteAllSessions(ctx context.Context) error
-	DeleteSessionsByDateRange(ctx context.Context, fromDate, toDate string) error
+	// Changing sessions on an invoice fails with ErrSessionInvoiced unless forced, which flags the invoice as
+	// needing regeneration
+	UpdateSessionDescription(ctx context.Context, sessionID string, description string, fullWorkSummary *string, force bool) (*models.WorkSession, error)
+	UpdateSessionRate(ctx context.Context, sessionID string, hourlyRate decimal.Decimal, includesGst bool, force bool) (*models.WorkSession, error)
+	UpdateSessionUser(ctx context.Context, sessionID string, userID *string, force bool) (*models.WorkSession, error)
+	UpdateSessionOutsideGit(ctx context.Context, sessionID string, outsideGit string, force bool) (*models.WorkSession, error)
+	AddSessionBreaks(ctx context.Context, sessionID string, breaks []*models.SessionBreak, force bool) (*models.WorkSession, error)
+	ListSessionBreaks(ctx context.Context, sessionID string) ([]*models.SessionBreak, error)
+	ReplaceSessionRepos(ctx context.Context, sessionID string, repos []*models.SessionRepo, force bool) error
+	ListSessionRepos(ctx context.Context, sessionID string) ([]*models.SessionRepo, error)
+	ListInvoiceSessionRepos(ctx context.Context, invoiceID string) ([]*models.SessionRepo, error)
+	SplitSession(ctx context.Context, sessionID string, startTime, endTime time.Time, other db.CreateSessionParams, otherEndTime time.Time, force bool) (*models.WorkSession, *models.WorkSession, error)
+	// Deleting sessions skips those on an invoice unless includeInvoiced is set, returning how many were deleted
+	// and how many were skipped
+	DeleteAllSessions(ctx context.Context, includeInvoiced bool) (int64, int64, error)
+	DeleteSessionsByDateRange(ctx context.Context, fromDate, toDate string, includeInvoiced bool) (int64, int64, error)
 
 	// Invoice operations
-	CreateInvoice(ctx context.Context, clientID, invoiceNumber, periodType string, periodStart, periodEnd time.Time, subtotal, gst, total decimal.Decimal) (*models.Invoice, error)
+	CreateInvoice(ctx context.Context, clientID, invoiceNumber, periodType string, periodStart, periodEnd time.Time, subtotal, gst, total decimal.Decimal, groupBy string, notes, paymentTerms, poNumber, projectCode *string, rounding *decimal.Decimal) (*models.Invoice, error)
 	GetInvoiceByID(ctx context.Context, invoiceID string) (*models.Invoice, error)
+	UpdateInvoiceAmounts(ctx context.Context, invoiceID string, subtotal, gst, total decimal.Decimal) error
+	UpdateInvoiceGroupBy(ctx context.Context, invoiceID, groupBy string) error
+	UpdateInvoiceNotes(ctx context.Context, invoiceID string, notes *string) error
+	UpdateInvoiceReferences(ctx context.Context, invoiceID string, poNumber, projectCode *string) error
+	UpdateInvoiceStatus(ctx context.Context, invoiceID, status string, reason *string, changedAt time.Time, writtenOff decimal.Decimal) error
+	VoidInvoice(ctx context.Context, invoiceID string, reason *string, voidedAt time.Time) error
 	PayInvoice(ctx context.Context, param db.PayInvoiceParams) error
+	PayInvoices(ctx context.Context, params []db.PayInvoiceParams) error
+	ListPaymentsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.Payment, error)
+	// ListPayments lists payments with their invoice number and client, filtered to an invoice and/or client when
+	// either is non-empty
+	ListPayments(ctx context.Context, invoiceID, clientName string) ([]*models.Payment, error)
+	GetPaymentByID(ctx context.Context, paymentID string) (*models.Payment, error)
+	DeletePayment(ctx context.Context, paymentID string) error
 	GetInvoiceByNumber(ctx context.Context, invoiceNumber string) (*models.Invoice, error)
 	ListInvoices(ctx context.Context, limit int32) ([]*models.Invoice, error)
 	GetInvoicesByClient(ctx context.Context, clientName string) ([]*models.Invoice, error)
@@ -69,8 +133,13 @@ type DB interface {
 	UpdateSessionInvoiceID(ctx context.Context, sessionID, invoiceID string) error
 	ClearSessionInvoiceIDs(ctx context.Context, invoiceID string) error
 
+	// Credit note operations
+	CreateCreditNote(ctx context.Context, invoiceID, creditNoteNumber string, amount, gstAmount decimal.Decimal, reason string, issuedDate time.Time) (*models.CreditNote, error)
+	GetCreditNotesByInvoiceID(ctx context.Context, invoiceID string) ([]*models.CreditNote, error)
+	ListCreditNotes(ctx context.Context) ([]*models.CreditNote, error)
+
 	// Expense operations
-	CreateExpense(ctx context.Context, amount decimal.Decimal, expenseDate time.Time, reference *string, clientID *string, invoiceID *string, description *string) (*models.Expense, error)
+	CreateExpense(ctx context.Context, amount decimal.Decimal, expenseDate time.Time, reference *string, clientID *string, invoiceID *string, description *string, category *string, markupPercent *decimal.Decimal, billable bool, gstAmount *decimal.Decimal) (*models.Expense, error)
 	GetExpenseByID(ctx context.Context, expenseID string) (*models.Expense, error)
 	ListExpenses(ctx context.Context) ([]*models.Expense, error)
 	ListExpensesByClient(ctx context.Context, clientID string) ([]*models.Expense, error)
@@ -79,8 +148,73 @@ type DB interface {
 	GetExpensesByInvoiceID(ctx context.Context, invoiceID string) ([]*models.Expense, error)
 	GetExpensesWithoutInvoiceByClient(ctx context.Context, clientID string) ([]*models.Expense, error)
 	GetExpensesWithoutInvoiceByClientAndDateRange(ctx context.Context, clientID string, startDate, endDate time.Time) ([]*models.Expense, error)
-	UpdateExpense(ctx context.Context, expenseID string, amount *decimal.Decimal, expenseDate *time.Time, reference *string, clientID *string, invoiceID *string, description *string) (*models.Expense, error)
+	UpdateExpense(ctx context.Context, expenseID string, amount *decimal.Decimal, expenseDate *time.Time, reference *string, clientID *string, invoiceID *string, description *string, category *string, markupPercent *decimal.Decimal, billable *bool, gstAmount *decimal.Decimal) (*models.Expense, error)
 	UpdateExpenseInvoiceID(ctx context.Context, expenseID string, invoiceID *string) error
 	ClearExpenseInvoiceIDs(ctx context.Context, invoiceID string) error
 	DeleteExpense(ctx context.Context, expenseID string) error
+	CreateDraftExpense(ctx context.Context, clientID string, amount decimal.Decimal, expenseDate time.Time, reference *string, receiptPath string) (*models.Expense, error)
+	CreateMileageExpense(ctx context.Context, clientID string, amount, distanceKm, ratePerKm decimal.Decimal, expenseDate time.Time, reference *string, description *string, billable bool) (*models.Expense, error)
+	GetExpenseByReceiptPath(ctx context.Context, receiptPath string) (*models.Expense, error)
+	ListDraftExpenses(ctx context.Context) ([]*models.Expense, error)
+	ConfirmExpense(ctx context.Context, expenseID string, amount decimal.Decimal) (*models.Expense, error)
+	CreateExpenses(ctx context.Context, expenses []*models.Expense) ([]*models.Expense, error)
+
+	// Expense rule operations, for importing card and bank statements
+	CreateExpenseRule(ctx context.Context, rule *models.ExpenseRule) (*models.ExpenseRule, error)
+	GetExpenseRuleByID(ctx context.Context, ruleID string) (*models.ExpenseRule, error)
+	ListExpenseRules(ctx context.Context) ([]*models.ExpenseRule, error)
+	DeleteExpenseRule(ctx context.Context, ruleID string) error
+
+	// Session template operations
+	CreateSessionTemplate(ctx context.Context, name, clientID string, description *string, durationMinutes *int64) (*models.SessionTemplate, error)
+	GetSessionTemplateByName(ctx context.Context, name string) (*models.SessionTemplate, error)
+	ListSessionTemplates(ctx context.Context) ([]*models.SessionTemplate, error)
+	DeleteSessionTemplate(ctx context.Context, templateID string) error
+
+	// User operations, for the people other than the owner who work on sessions
+	CreateUser(ctx context.Context, name string, costRate *decimal.Decimal) (*models.User, error)
+	GetUserByName(ctx context.Context, name string) (*models.User, error)
+	ListUsers(ctx context.Context) ([]*models.User, error)
+	UpdateUser(ctx context.Context, userID string, name *string, costRate *decimal.Decimal) (*models.User, error)
+
+	// Client contact operations, for the log of calls, emails and meetings
+	CreateClientContact(ctx context.Context, contact *models.ClientContact) (*models.ClientContact, error)
+	GetClientContactByID(ctx context.Context, contactID string) (*models.ClientContact, error)
+	ListClientContacts(ctx context.Context, clientID *string, limit int32) ([]*models.ClientContact, error)
+	BillClientContact(ctx context.Context, contactID string, session *models.WorkSession) (*models.WorkSession, error)
+	DeleteClientContact(ctx context.Context, contactID string) error
+
+	// Milestone operations, for fixed-price work billed once it's complete
+	CreateMilestone(ctx context.Context, milestone *models.Milestone) (*models.Milestone, error)
+	GetMilestoneByID(ctx context.Context, milestoneID string) (*models.Milestone, error)
+	ListMilestones(ctx context.Context, clientID *string) ([]*models.Milestone, error)
+	GetCompletedMilestonesWithoutInvoice(ctx context.Context, completedBefore time.Time, clientID *string) ([]*models.Milestone, error)
+	GetMilestonesByInvoiceID(ctx context.Context, invoiceID string) ([]*models.Milestone, error)
+	CompleteMilestone(ctx context.Context, milestoneID string, completedDate time.Time) error
+	UpdateMilestoneInvoiceID(ctx context.Context, milestoneID string, invoiceID *string) error
+	ClearMilestoneInvoiceIDs(ctx context.Context, invoiceID string) error
+	DeleteMilestone(ctx context.Context, milestoneID string) error
+
+	// Leave operations, for days off
+	CreateLeave(ctx context.Context, leaveType string, startDate, endDate time.Time, note *string) (*models.Leave, error)
+	GetLeaveByID(ctx context.Context, leaveID string) (*models.Leave, error)
+	ListLeave(ctx context.Context) ([]*models.Leave, error)
+	ListLeaveInRange(ctx context.Context, fromDate, toDate time.Time) ([]*models.Leave, error)
+	DeleteLeave(ctx context.Context, leaveID string) error
+
+	// Import inserts exported records as they are, replacing everything when asked to
+	ImportData(ctx context.Context, data *ImportData, replace bool) error
+
+	// Trash operations, for deleted sessions and expenses until they're restored or purged
+	ListTrashedSessions(ctx context.Context) ([]*models.WorkSession, error)
+	ListTrashedExpenses(ctx context.Context) ([]*models.Expense, error)
+	RestoreSession(ctx context.Context, sessionID string) (*models.WorkSession, error)
+	RestoreExpense(ctx context.Context, expenseID string) (*models.Expense, error)
+	PurgeTrash(ctx context.Context, before time.Time) (sessions, expenses int64, err error)
+
+	// Diagnostics
+	CountActiveSessions(ctx context.Context) (int64, error)
+	GetSessionsWithMissingInvoice(ctx context.Context) ([]*models.WorkSession, error)
+	GetExpensesWithMissingInvoice(ctx context.Context) ([]*models.Expense, error)
+	ClearMissingInvoiceIDs(ctx context.Context) error
 }
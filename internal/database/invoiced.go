@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jesses-code-adventures/work/internal/db"
+)
+
+// ErrSessionInvoiced is wrapped by the error returned when changing or deleting sessions that are on an issued
+// invoice without forcing it, since the invoice would no longer match its sessions
+var ErrSessionInvoiced = errors.New("sessions are on an issued invoice")
+
+// beginSessionChange starts a transaction for changing a session, once it's checked against the invoice it's on
+func (s *SQLiteDB) beginSessionChange(ctx context.Context, sessionID string, force bool) (*sql.Tx, *db.Queries, error) {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	qtx := s.queries.WithTx(tx)
+	session, err := qtx.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	var invoiceIDs []string
+	if session.InvoiceID.Valid {
+		invoiceIDs = append(invoiceIDs, session.InvoiceID.String)
+	}
+	if err := guardInvoices(ctx, qtx, invoiceIDs, force); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	return tx, qtx, nil
+}
+
+// beginSessionsChange starts a transaction for changing the sessions started in a date range, once they're
+// checked against the invoices they're on. Nil dates leave the range open at that end.
+func (s *SQLiteDB) beginSessionsChange(ctx context.Context, startDate, endDate any, force bool) (*sql.Tx, *db.Queries, error) {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	qtx := s.queries.WithTx(tx)
+	ids, err := qtx.GetInvoiceIDsForSessionsByDateRange(ctx, db.GetInvoiceIDsForSessionsByDateRangeParams{
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, fmt.Errorf("failed to get invoices for sessions: %w", err)
+	}
+	invoiceIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		invoiceIDs = append(invoiceIDs, id.String)
+	}
+	if err := guardInvoices(ctx, qtx, invoiceIDs, force); err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	return tx, qtx, nil
+}
+
+// guardInvoices refuses a change to sessions on the given invoices unless it's forced, in which case the invoices
+// are flagged as needing regeneration in the same transaction as the change. Invoices that have since been
+// deleted don't hold their old sessions back.
+func guardInvoices(ctx context.Context, qtx *db.Queries, invoiceIDs []string, force bool) error {
+	var numbers []string
+	for _, id := range invoiceIDs {
+		invoice, err := qtx.GetInvoiceByID(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get invoice: %w", err)
+		}
+		numbers = append(numbers, invoice.InvoiceNumber)
+
+		if force {
+			if err := qtx.FlagInvoiceNeedsRegeneration(ctx, id); err != nil {
+				return fmt.Errorf("failed to flag invoice for regeneration: %w", err)
+			}
+		}
+	}
+
+	if len(numbers) > 0 && !force {
+		return fmt.Errorf("%w (%s)", ErrSessionInvoiced, strings.Join(numbers, ", "))
+	}
+	return nil
+}
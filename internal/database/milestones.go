@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/db"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+func (s *SQLiteDB) CreateMilestone(ctx context.Context, milestone *models.Milestone) (*models.Milestone, error) {
+	created, err := s.queries.CreateMilestone(ctx, db.CreateMilestoneParams{
+		ID:       models.NewUUID(),
+		ClientID: milestone.ClientID,
+		Name:     milestone.Name,
+		Amount:   milestone.Amount,
+		DueDate:  ptrToNullTime(milestone.DueDate),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create milestone: %w", err)
+	}
+
+	result := convertDBMilestoneToModel(db.GetMilestoneByIDRow{
+		ID:            created.ID,
+		ClientID:      created.ClientID,
+		Name:          created.Name,
+		Amount:        created.Amount,
+		DueDate:       created.DueDate,
+		Status:        created.Status,
+		CompletedDate: created.CompletedDate,
+		InvoiceID:     created.InvoiceID,
+		CreatedAt:     created.CreatedAt,
+		UpdatedAt:     created.UpdatedAt,
+	})
+	result.ClientName = milestone.ClientName
+	return result, nil
+}
+
+func (s *SQLiteDB) GetMilestoneByID(ctx context.Context, milestoneID string) (*models.Milestone, error) {
+	milestone, err := s.queries.GetMilestoneByID(ctx, milestoneID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get milestone: %w", err)
+	}
+
+	return convertDBMilestoneToModel(milestone), nil
+}
+
+// ListMilestones lists milestones by client and then due date, with every client's when clientID is nil
+func (s *SQLiteDB) ListMilestones(ctx context.Context, clientID *string) ([]*models.Milestone, error) {
+	milestones, err := s.queries.ListMilestones(ctx, ptrToNullString(clientID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list milestones: %w", err)
+	}
+
+	result := make([]*models.Milestone, len(milestones))
+	for i, milestone := range milestones {
+		result[i] = convertDBMilestoneToModel(db.GetMilestoneByIDRow(milestone))
+	}
+	return result, nil
+}
+
+// GetCompletedMilestonesWithoutInvoice returns the milestones completed by completedBefore that haven't been
+// invoiced, with every client's when clientID is nil
+func (s *SQLiteDB) GetCompletedMilestonesWithoutInvoice(ctx context.Context, completedBefore time.Time, clientID *string) ([]*models.Milestone, error) {
+	milestones, err := s.queries.GetCompletedMilestonesWithoutInvoice(ctx, db.GetCompletedMilestonesWithoutInvoiceParams{
+		CompletedBefore: sql.NullTime{Time: completedBefore, Valid: true},
+		ClientID:        ptrToNullString(clientID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uninvoiced milestones: %w", err)
+	}
+
+	result := make([]*models.Milestone, len(milestones))
+	for i, milestone := range milestones {
+		result[i] = convertDBMilestoneToModel(db.GetMilestoneByIDRow(milestone))
+	}
+	return result, nil
+}
+
+func (s *SQLiteDB) GetMilestonesByInvoiceID(ctx context.Context, invoiceID string) ([]*models.Milestone, error) {
+	milestones, err := s.queries.GetMilestonesByInvoiceID(ctx, sql.NullString{String: invoiceID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get milestones by invoice ID: %w", err)
+	}
+
+	result := make([]*models.Milestone, len(milestones))
+	for i, milestone := range milestones {
+		result[i] = convertDBMilestoneToModel(db.GetMilestoneByIDRow(milestone))
+	}
+	return result, nil
+}
+
+func (s *SQLiteDB) CompleteMilestone(ctx context.Context, milestoneID string, completedDate time.Time) error {
+	if err := s.queries.CompleteMilestone(ctx, db.CompleteMilestoneParams{
+		CompletedDate: sql.NullTime{Time: completedDate, Valid: true},
+		ID:            milestoneID,
+	}); err != nil {
+		return fmt.Errorf("failed to complete milestone: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) UpdateMilestoneInvoiceID(ctx context.Context, milestoneID string, invoiceID *string) error {
+	if err := s.queries.UpdateMilestoneInvoiceID(ctx, db.UpdateMilestoneInvoiceIDParams{
+		InvoiceID: ptrToNullString(invoiceID),
+		ID:        milestoneID,
+	}); err != nil {
+		return fmt.Errorf("failed to update milestone invoice ID: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) ClearMilestoneInvoiceIDs(ctx context.Context, invoiceID string) error {
+	if err := s.queries.ClearMilestoneInvoiceIDs(ctx, sql.NullString{String: invoiceID, Valid: true}); err != nil {
+		return fmt.Errorf("failed to clear milestone invoice IDs: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) DeleteMilestone(ctx context.Context, milestoneID string) error {
+	if err := s.queries.DeleteMilestone(ctx, milestoneID); err != nil {
+		return fmt.Errorf("failed to delete milestone: %w", err)
+	}
+	return nil
+}
+
+func convertDBMilestoneToModel(milestone db.GetMilestoneByIDRow) *models.Milestone {
+	return &models.Milestone{
+		ID:            milestone.ID,
+		ClientID:      milestone.ClientID,
+		Name:          milestone.Name,
+		Amount:        milestone.Amount,
+		DueDate:       nullTimeToPtr(milestone.DueDate),
+		Status:        milestone.Status,
+		CompletedDate: nullTimeToPtr(milestone.CompletedDate),
+		InvoiceID:     nullStringToPtr(milestone.InvoiceID),
+		CreatedAt:     milestone.CreatedAt,
+		UpdatedAt:     milestone.UpdatedAt,
+		ClientName:    milestone.ClientName,
+	}
+}
@@ -3,7 +3,12 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -16,19 +21,41 @@ import (
 )
 
 type SQLiteDB struct {
-	conn     *sql.DB
-	queries  *db.Queries
-	exitFunc func()
+	conn        *sql.DB
+	queries     *db.Queries
+	replica     *replicaSync // syncs an embedded replica with its primary, nil otherwise
+	cipher      *fieldCipher // encrypts client contact details, nil when ENCRYPTION_KEY isn't set
+	busyRetries int          // times a statement blocked by another process's write is retried
+	workUser    string       // name of the user new sessions are attributed to, empty for the owner
+	metadata    bool         // record the hostname, OS and git branch new sessions are started on
+	snapshot    string       // path of the read-only snapshot fallen back to while the database is unreachable
 }
 
 func NewDB(cfg *config.Config) (*SQLiteDB, error) {
-	conn, err := sql.Open(cfg.DatabaseDriver, cfg.DatabaseURL)
+	dsn := cfg.DatabaseURL
+	if cfg.DatabaseDriver == "sqlite3" {
+		dsn = sqliteDSN(cfg)
+	}
+	conn, err := sql.Open(cfg.DatabaseDriver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	if cfg.DatabaseDriver == "sqlite3" {
+		// SQLite allows one writer at a time, so a single connection queues this process's writes rather than
+		// having them fail against each other
+		conn.SetMaxOpenConns(1)
+	}
 	s := SQLiteDB{
-		conn:    conn,
-		queries: db.New(conn),
+		conn:        conn,
+		queries:     db.New(&busyRetryConn{conn: conn, retries: cfg.SQLiteBusyRetries}),
+		busyRetries: cfg.SQLiteBusyRetries,
+		workUser:    cfg.WorkUser,
+		metadata:    cfg.SessionMetadata,
+	}
+	if cfg.EncryptionKey != nil {
+		if s.cipher, err = newFieldCipher(cfg.EncryptionKey); err != nil {
+			return nil, err
+		}
 	}
 	return &s, nil
 }
@@ -40,26 +67,27 @@ func NewTursoDBWithEmbeddedReplica(cfg *config.Config) (*SQLiteDB, error) {
 	// 	libsql.WithAuthToken(cfg.TursoToken),
 	// )
 	// if err != nil {
-	// 	fmt.Println("Error creating connector:", err)
-	// 	os.Exit(1)
+	// 	return nil, fmt.Errorf("failed to create replica connector: %w", err)
 	// }
 	// conn := sql.OpenDB(connector)
 	// s := SQLiteDB{
 	// 	conn:    conn,
 	// 	queries: db.New(conn),
+	// 	replica: newReplicaSync(libsqlSyncer{connector}, cfg.DatabasePath+".sync.json", cfg.SyncInterval),
 	// }
-	// s.exitFunc = func() {
-	// 	if _, err := connector.Sync(); err != nil {
-	// 		fmt.Println("Error syncing database:", err)
-	// 		os.Exit(1)
-	// 	}
-	// }
+	// s.replica.start()
 	// return &s, nil
 }
 
 func (s *SQLiteDB) Close() error {
-	if s.exitFunc != nil {
-		s.exitFunc()
+	if s.replica != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		// The local writes are safe in the replica and go out with the next sync, so a failure here
+		// shouldn't fail the command that made them
+		if err := s.replica.close(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v, run 'work db sync' to retry\n", err)
+		}
 	}
 	return s.conn.Close()
 }
@@ -68,6 +96,35 @@ func (s *SQLiteDB) GetConnection() *sql.DB {
 	return s.conn
 }
 
+func (s *SQLiteDB) Ping(ctx context.Context) error {
+	if err := s.conn.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
+// GetTableColumns returns the column names of a table, or an empty slice if the table does not exist
+func (s *SQLiteDB) GetTableColumns(ctx context.Context, table string) ([]string, error) {
+	rows, err := s.conn.QueryContext(ctx, "SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns for table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan column for table %s: %w", table, err)
+		}
+		columns = append(columns, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get columns for table %s: %w", table, err)
+	}
+	return columns, nil
+}
+
 func (s *SQLiteDB) CreateClient(ctx context.Context, name string, hourlyRate decimal.Decimal, retainerAmount *decimal.Decimal, retainerHours *float64, retainerBasis, dir *string) (*models.Client, error) {
 	client, err := s.queries.CreateClient(ctx, db.CreateClientParams{
 		ID:   models.NewUUID(),
@@ -85,7 +142,43 @@ func (s *SQLiteDB) CreateClient(ctx context.Context, name string, hourlyRate dec
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
 
-	return s.convertDBClientToModel(client), nil
+	return s.convertDBClientToModel(client)
+}
+
+// GetClientActivity sums up the hours worked for a client and what's been invoiced and is still owed
+func (s *SQLiteDB) GetClientActivity(ctx context.Context, clientID string) (*models.ClientActivity, error) {
+	sessions, err := s.queries.GetClientSessionStats(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client session stats: %w", err)
+	}
+	invoices, err := s.queries.GetClientInvoiceStats(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client invoice stats: %w", err)
+	}
+
+	activity := &models.ClientActivity{
+		SessionCount:  sessions.SessionCount,
+		TotalHours:    sessions.TotalHours,
+		InvoiceCount:  invoices.InvoiceCount,
+		TotalInvoiced: decimal.NewFromFloat(invoices.TotalInvoiced).Round(2),
+		Outstanding:   decimal.NewFromFloat(invoices.Outstanding).Round(2),
+	}
+	last, err := s.queries.GetClientLastSessionStart(ctx, clientID)
+	if err == nil {
+		activity.LastSession = &last
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get client's last session: %w", err)
+	}
+	return activity, nil
+}
+
+// GetClientHoursSince sums the hours of a client's completed sessions starting from since
+func (s *SQLiteDB) GetClientHoursSince(ctx context.Context, clientID string, since time.Time) (float64, error) {
+	hours, err := s.queries.GetClientHoursSince(ctx, db.GetClientHoursSinceParams{ClientID: clientID, Since: since})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get client hours: %w", err)
+	}
+	return hours, nil
 }
 
 func (s *SQLiteDB) GetClientByName(ctx context.Context, name string) (*models.Client, error) {
@@ -97,7 +190,7 @@ func (s *SQLiteDB) GetClientByName(ctx context.Context, name string) (*models.Cl
 		return nil, fmt.Errorf("failed to get client by name: %w", err)
 	}
 
-	return s.convertDBClientToModel(client), nil
+	return s.convertDBClientToModel(client)
 }
 
 func (s *SQLiteDB) GetClientByID(ctx context.Context, ID string) (*models.Client, error) {
@@ -109,7 +202,7 @@ func (s *SQLiteDB) GetClientByID(ctx context.Context, ID string) (*models.Client
 		return nil, fmt.Errorf("failed to get client by ID: %w", err)
 	}
 
-	return s.convertDBClientToModel(client), nil
+	return s.convertDBClientToModel(client)
 }
 
 func (s *SQLiteDB) ListClients(ctx context.Context) ([]*models.Client, error) {
@@ -120,7 +213,9 @@ func (s *SQLiteDB) ListClients(ctx context.Context) ([]*models.Client, error) {
 
 	result := make([]*models.Client, len(clients))
 	for i, client := range clients {
-		result[i] = s.convertDBClientToModel(client)
+		if result[i], err = s.convertDBClientToModel(client); err != nil {
+			return nil, err
+		}
 	}
 
 	return result, nil
@@ -134,7 +229,9 @@ func (s *SQLiteDB) GetClientsWithDirectories(ctx context.Context) ([]*models.Cli
 
 	result := make([]*models.Client, len(clients))
 	for i, client := range clients {
-		result[i] = s.convertDBClientToModel(client)
+		if result[i], err = s.convertDBClientToModel(client); err != nil {
+			return nil, err
+		}
 	}
 
 	return result, nil
@@ -151,6 +248,11 @@ func (s *SQLiteDB) CreateWorkSession(ctx context.Context, clientID string, descr
 		rate = decimal.NullDecimal{Decimal: hourlyRate, Valid: true}
 	}
 
+	userID, err := s.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	session, err := s.queries.CreateSession(ctx, db.CreateSessionParams{
 		ID:          models.NewUUID(),
 		ClientID:    clientID,
@@ -158,22 +260,31 @@ func (s *SQLiteDB) CreateWorkSession(ctx context.Context, clientID string, descr
 		Description: desc,
 		HourlyRate:  rate,
 		IncludesGst: includesGst,
+		Hostname:    s.currentHostname(),
+		Os:          s.currentOS(),
+		GitBranch:   s.currentGitBranch(ctx),
+		UserID:      userID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create work session: %w", err)
 	}
 
 	return &models.WorkSession{
-		ID:          session.ID,
-		ClientID:    session.ClientID,
-		StartTime:   session.StartTime,
-		EndTime:     nullTimeToPtr(session.EndTime),
-		Description: nullStringToPtr(session.Description),
-		HourlyRate:  nullDecimalToPtr(session.HourlyRate),
-		OutsideGit:  nullStringToPtr(session.OutsideGit),
-		IncludesGst: session.IncludesGst,
-		CreatedAt:   session.CreatedAt,
-		UpdatedAt:   session.UpdatedAt,
+		ID:           session.ID,
+		ClientID:     session.ClientID,
+		StartTime:    session.StartTime,
+		EndTime:      nullTimeToPtr(session.EndTime),
+		Description:  nullStringToPtr(session.Description),
+		HourlyRate:   nullDecimalToPtr(session.HourlyRate),
+		OutsideGit:   nullStringToPtr(session.OutsideGit),
+		Hostname:     nullStringToPtr(session.Hostname),
+		OS:           nullStringToPtr(session.Os),
+		GitBranch:    nullStringToPtr(session.GitBranch),
+		BreakSeconds: session.BreakSeconds,
+		UserID:       nullStringToPtr(session.UserID),
+		IncludesGst:  session.IncludesGst,
+		CreatedAt:    session.CreatedAt,
+		UpdatedAt:    session.UpdatedAt,
 	}, nil
 }
 
@@ -188,6 +299,11 @@ func (s *SQLiteDB) CreateWorkSessionWithStartTime(ctx context.Context, clientID
 		rate = decimal.NullDecimal{Decimal: hourlyRate, Valid: true}
 	}
 
+	userID, err := s.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	session, err := s.queries.CreateSession(ctx, db.CreateSessionParams{
 		ID:          models.NewUUID(),
 		ClientID:    clientID,
@@ -195,22 +311,31 @@ func (s *SQLiteDB) CreateWorkSessionWithStartTime(ctx context.Context, clientID
 		Description: desc,
 		HourlyRate:  rate,
 		IncludesGst: includesGst,
+		Hostname:    s.currentHostname(),
+		Os:          s.currentOS(),
+		GitBranch:   s.currentGitBranch(ctx),
+		UserID:      userID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create work session: %w", err)
 	}
 
 	return &models.WorkSession{
-		ID:          session.ID,
-		ClientID:    session.ClientID,
-		StartTime:   session.StartTime,
-		EndTime:     nullTimeToPtr(session.EndTime),
-		Description: nullStringToPtr(session.Description),
-		HourlyRate:  nullDecimalToPtr(session.HourlyRate),
-		OutsideGit:  nullStringToPtr(session.OutsideGit),
-		IncludesGst: session.IncludesGst,
-		CreatedAt:   session.CreatedAt,
-		UpdatedAt:   session.UpdatedAt,
+		ID:           session.ID,
+		ClientID:     session.ClientID,
+		StartTime:    session.StartTime,
+		EndTime:      nullTimeToPtr(session.EndTime),
+		Description:  nullStringToPtr(session.Description),
+		HourlyRate:   nullDecimalToPtr(session.HourlyRate),
+		OutsideGit:   nullStringToPtr(session.OutsideGit),
+		Hostname:     nullStringToPtr(session.Hostname),
+		OS:           nullStringToPtr(session.Os),
+		GitBranch:    nullStringToPtr(session.GitBranch),
+		BreakSeconds: session.BreakSeconds,
+		UserID:       nullStringToPtr(session.UserID),
+		IncludesGst:  session.IncludesGst,
+		CreatedAt:    session.CreatedAt,
+		UpdatedAt:    session.UpdatedAt,
 	}, nil
 }
 
@@ -225,6 +350,11 @@ func (s *SQLiteDB) CreateWorkSessionWithTimes(ctx context.Context, clientID stri
 		rate = decimal.NullDecimal{Decimal: hourlyRate, Valid: true}
 	}
 
+	userID, err := s.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	session, err := s.queries.CreateSession(ctx, db.CreateSessionParams{
 		ID:          models.NewUUID(),
 		ClientID:    clientID,
@@ -232,6 +362,9 @@ func (s *SQLiteDB) CreateWorkSessionWithTimes(ctx context.Context, clientID stri
 		Description: desc,
 		HourlyRate:  rate,
 		IncludesGst: includesGst,
+		Hostname:    s.currentHostname(),
+		Os:          s.currentOS(),
+		UserID:      userID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create work session: %w", err)
@@ -247,19 +380,87 @@ func (s *SQLiteDB) CreateWorkSessionWithTimes(ctx context.Context, clientID stri
 	}
 
 	return &models.WorkSession{
-		ID:          updatedSession.ID,
-		ClientID:    updatedSession.ClientID,
-		StartTime:   updatedSession.StartTime,
-		EndTime:     nullTimeToPtr(updatedSession.EndTime),
-		Description: nullStringToPtr(updatedSession.Description),
-		HourlyRate:  nullDecimalToPtr(updatedSession.HourlyRate),
-		OutsideGit:  nullStringToPtr(updatedSession.OutsideGit),
-		IncludesGst: updatedSession.IncludesGst,
-		CreatedAt:   updatedSession.CreatedAt,
-		UpdatedAt:   updatedSession.UpdatedAt,
+		ID:           updatedSession.ID,
+		ClientID:     updatedSession.ClientID,
+		StartTime:    updatedSession.StartTime,
+		EndTime:      nullTimeToPtr(updatedSession.EndTime),
+		Description:  nullStringToPtr(updatedSession.Description),
+		HourlyRate:   nullDecimalToPtr(updatedSession.HourlyRate),
+		OutsideGit:   nullStringToPtr(updatedSession.OutsideGit),
+		Hostname:     nullStringToPtr(updatedSession.Hostname),
+		OS:           nullStringToPtr(updatedSession.Os),
+		GitBranch:    nullStringToPtr(updatedSession.GitBranch),
+		BreakSeconds: updatedSession.BreakSeconds,
+		UserID:       nullStringToPtr(updatedSession.UserID),
+		IncludesGst:  updatedSession.IncludesGst,
+		CreatedAt:    updatedSession.CreatedAt,
+		UpdatedAt:    updatedSession.UpdatedAt,
 	}, nil
 }
 
+// CreateWorkSessions creates finished sessions in a single transaction, so either every session is created or
+// none are. Each session's client, times, description, rate and GST setting are taken from sessions.
+func (s *SQLiteDB) CreateWorkSessions(ctx context.Context, sessions []*models.WorkSession) ([]*models.WorkSession, error) {
+	userID, err := s.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	created := make([]*models.WorkSession, 0, len(sessions))
+	for _, session := range sessions {
+		result, err := s.createFinishedSession(ctx, qtx, session, userID)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, result)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit sessions: %w", err)
+	}
+	return created, nil
+}
+
+// createFinishedSession creates a session from its start and end times with qtx, so it can be part of a larger
+// transaction
+func (s *SQLiteDB) createFinishedSession(ctx context.Context, qtx *db.Queries, session *models.WorkSession, userID sql.NullString) (*models.WorkSession, error) {
+	var rate decimal.NullDecimal
+	if session.HourlyRate != nil && session.HourlyRate.GreaterThan(decimal.Zero) {
+		rate = decimal.NullDecimal{Decimal: *session.HourlyRate, Valid: true}
+	}
+	params := db.CreateSessionParams{
+		ID:          models.NewUUID(),
+		ClientID:    session.ClientID,
+		StartTime:   session.StartTime,
+		Description: ptrToNullString(session.Description),
+		HourlyRate:  rate,
+		IncludesGst: session.IncludesGst,
+		Hostname:    s.currentHostname(),
+		Os:          s.currentOS(),
+		UserID:      userID,
+	}
+	if _, err := qtx.CreateSession(ctx, params); err != nil {
+		return nil, fmt.Errorf("failed to create work session: %w", err)
+	}
+	stopped, err := qtx.StopSession(ctx, db.StopSessionParams{
+		ID:      params.ID,
+		EndTime: sql.NullTime{Time: *session.EndTime, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set end time on session: %w", err)
+	}
+	result := s.convertDBSessionToModel(stopped)
+	result.ClientName = session.ClientName
+	return result, nil
+}
+
 func (s *SQLiteDB) GetActiveSession(ctx context.Context) (*models.WorkSession, error) {
 	session, err := s.queries.GetActiveSession(ctx)
 	if err != nil {
@@ -275,43 +476,206 @@ func (s *SQLiteDB) GetActiveSession(ctx context.Context) (*models.WorkSession, e
 	}
 
 	return &models.WorkSession{
-		ID:          session.ID,
-		ClientID:    session.ClientID,
-		StartTime:   session.StartTime,
-		EndTime:     nullTimeToPtr(session.EndTime),
-		Description: nullStringToPtr(session.Description),
-		HourlyRate:  &sessionRate,
-		OutsideGit:  nullStringToPtr(session.OutsideGit),
-		IncludesGst: session.IncludesGst,
-		CreatedAt:   session.CreatedAt,
-		UpdatedAt:   session.UpdatedAt,
-		ClientName:  session.ClientName,
+		ID:           session.ID,
+		ClientID:     session.ClientID,
+		StartTime:    session.StartTime,
+		EndTime:      nullTimeToPtr(session.EndTime),
+		Description:  nullStringToPtr(session.Description),
+		HourlyRate:   &sessionRate,
+		OutsideGit:   nullStringToPtr(session.OutsideGit),
+		Hostname:     nullStringToPtr(session.Hostname),
+		OS:           nullStringToPtr(session.Os),
+		GitBranch:    nullStringToPtr(session.GitBranch),
+		BreakSeconds: session.BreakSeconds,
+		UserID:       nullStringToPtr(session.UserID),
+		IncludesGst:  session.IncludesGst,
+		CreatedAt:    session.CreatedAt,
+		UpdatedAt:    session.UpdatedAt,
+		ClientName:   session.ClientName,
 	}, nil
 }
 
-func (s *SQLiteDB) StopWorkSession(ctx context.Context, sessionID string) (*models.WorkSession, error) {
+func (s *SQLiteDB) StopWorkSession(ctx context.Context, sessionID string, endTime time.Time) (*models.WorkSession, error) {
 	session, err := s.queries.StopSession(ctx, db.StopSessionParams{
 		ID:      sessionID,
-		EndTime: sql.NullTime{Time: time.Now(), Valid: true},
+		EndTime: sql.NullTime{Time: endTime, Valid: true},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to stop work session: %w", err)
 	}
 
 	return &models.WorkSession{
-		ID:          session.ID,
-		ClientID:    session.ClientID,
-		StartTime:   session.StartTime,
-		EndTime:     nullTimeToPtr(session.EndTime),
-		Description: nullStringToPtr(session.Description),
-		HourlyRate:  nullDecimalToPtr(session.HourlyRate),
-		OutsideGit:  nullStringToPtr(session.OutsideGit),
-		IncludesGst: session.IncludesGst,
-		CreatedAt:   session.CreatedAt,
-		UpdatedAt:   session.UpdatedAt,
+		ID:           session.ID,
+		ClientID:     session.ClientID,
+		StartTime:    session.StartTime,
+		EndTime:      nullTimeToPtr(session.EndTime),
+		Description:  nullStringToPtr(session.Description),
+		HourlyRate:   nullDecimalToPtr(session.HourlyRate),
+		OutsideGit:   nullStringToPtr(session.OutsideGit),
+		Hostname:     nullStringToPtr(session.Hostname),
+		OS:           nullStringToPtr(session.Os),
+		GitBranch:    nullStringToPtr(session.GitBranch),
+		BreakSeconds: session.BreakSeconds,
+		UserID:       nullStringToPtr(session.UserID),
+		IncludesGst:  session.IncludesGst,
+		CreatedAt:    session.CreatedAt,
+		UpdatedAt:    session.UpdatedAt,
 	}, nil
 }
 
+// SplitSession narrows a session to startTime and endTime and creates the other session covering
+// the rest of its time in one transaction, so a failure can't leave time missing or double counted
+func (s *SQLiteDB) SplitSession(ctx context.Context, sessionID string, startTime, endTime time.Time, other db.CreateSessionParams, otherEndTime time.Time, force bool) (*models.WorkSession, *models.WorkSession, error) {
+	tx, qtx, err := s.beginSessionChange(ctx, sessionID, force)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	session, err := qtx.UpdateSessionTimes(ctx, db.UpdateSessionTimesParams{
+		ID:        sessionID,
+		StartTime: startTime,
+		EndTime:   sql.NullTime{Time: endTime, Valid: true},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to update session times: %w", err)
+	}
+
+	if _, err := qtx.CreateSession(ctx, other); err != nil {
+		return nil, nil, fmt.Errorf("failed to create split session: %w", err)
+	}
+	otherSession, err := qtx.StopSession(ctx, db.StopSessionParams{
+		ID:      other.ID,
+		EndTime: sql.NullTime{Time: otherEndTime, Valid: true},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to set end time on split session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit session split: %w", err)
+	}
+	return s.convertDBSessionToModel(session), s.convertDBSessionToModel(otherSession), nil
+}
+
+// AddSessionBreaks records breaks taken out of a session in a single transaction, returning the session with
+// its updated break total
+func (s *SQLiteDB) AddSessionBreaks(ctx context.Context, sessionID string, breaks []*models.SessionBreak, force bool) (*models.WorkSession, error) {
+	tx, qtx, err := s.beginSessionChange(ctx, sessionID, force)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, b := range breaks {
+		source := b.Source
+		if source == "" {
+			source = models.BreakSourceManual
+		}
+		if _, err := qtx.CreateSessionBreak(ctx, db.CreateSessionBreakParams{
+			ID:        models.NewUUID(),
+			SessionID: sessionID,
+			StartTime: b.StartTime,
+			EndTime:   b.EndTime,
+			Source:    source,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create session break: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit session breaks: %w", err)
+	}
+	return s.GetSessionByID(ctx, sessionID)
+}
+
+func (s *SQLiteDB) ListSessionBreaks(ctx context.Context, sessionID string) ([]*models.SessionBreak, error) {
+	breaks, err := s.queries.ListSessionBreaks(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session breaks: %w", err)
+	}
+
+	result := make([]*models.SessionBreak, len(breaks))
+	for i, b := range breaks {
+		result[i] = &models.SessionBreak{
+			ID:        b.ID,
+			SessionID: b.SessionID,
+			StartTime: b.StartTime,
+			EndTime:   b.EndTime,
+			Source:    b.Source,
+			CreatedAt: b.CreatedAt,
+		}
+	}
+	return result, nil
+}
+
+// ReplaceSessionRepos records the repositories a session's description was generated from, in place of those
+// recorded when it was last generated
+func (s *SQLiteDB) ReplaceSessionRepos(ctx context.Context, sessionID string, repos []*models.SessionRepo, force bool) error {
+	tx, qtx, err := s.beginSessionChange(ctx, sessionID, force)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := qtx.DeleteSessionRepos(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to delete session repos: %w", err)
+	}
+	for _, repo := range repos {
+		if err := qtx.CreateSessionRepo(ctx, db.CreateSessionRepoParams{
+			SessionID:   sessionID,
+			RepoPath:    repo.RepoPath,
+			CommitCount: repo.CommitCount,
+		}); err != nil {
+			return fmt.Errorf("failed to create session repo: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit session repos: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) ListSessionRepos(ctx context.Context, sessionID string) ([]*models.SessionRepo, error) {
+	repos, err := s.queries.ListSessionRepos(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session repos: %w", err)
+	}
+	return convertDBSessionRepos(repos), nil
+}
+
+// ListInvoiceSessionRepos lists the repositories recorded for the sessions on an invoice, in session order
+func (s *SQLiteDB) ListInvoiceSessionRepos(ctx context.Context, invoiceID string) ([]*models.SessionRepo, error) {
+	repos, err := s.queries.ListInvoiceSessionRepos(ctx, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoice session repos: %w", err)
+	}
+	return convertDBSessionRepos(repos), nil
+}
+
+func convertDBSessionRepos(repos []db.SessionRepo) []*models.SessionRepo {
+	result := make([]*models.SessionRepo, len(repos))
+	for i, repo := range repos {
+		result[i] = &models.SessionRepo{
+			SessionID:   repo.SessionID,
+			RepoPath:    repo.RepoPath,
+			CommitCount: repo.CommitCount,
+			CreatedAt:   repo.CreatedAt,
+		}
+	}
+	return result
+}
+
+// ListSessionIDsByPrefix returns the IDs of up to limit sessions starting with prefix, most recent first
+func (s *SQLiteDB) ListSessionIDsByPrefix(ctx context.Context, prefix string, limit int32) ([]string, error) {
+	ids, err := s.queries.ListSessionIDsByPrefix(ctx, db.ListSessionIDsByPrefixParams{Prefix: prefix, LimitCount: int64(limit)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions by ID prefix: %w", err)
+	}
+	return ids, nil
+}
+
 func (s *SQLiteDB) ListRecentSessions(ctx context.Context, limit int32) ([]*models.WorkSession, error) {
 	sessions, err := s.queries.ListRecentSessions(ctx, int64(limit))
 	if err != nil {
@@ -334,6 +698,11 @@ func (s *SQLiteDB) ListRecentSessions(ctx context.Context, limit int32) ([]*mode
 			HourlyRate:      &sessionRate,
 			FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
 			OutsideGit:      nullStringToPtr(session.OutsideGit),
+			Hostname:        nullStringToPtr(session.Hostname),
+			OS:              nullStringToPtr(session.Os),
+			GitBranch:       nullStringToPtr(session.GitBranch),
+			BreakSeconds:    session.BreakSeconds,
+			UserID:          nullStringToPtr(session.UserID),
 			InvoiceID:       nullStringToPtr(session.InvoiceID),
 			IncludesGst:     session.IncludesGst,
 			CreatedAt:       session.CreatedAt,
@@ -380,6 +749,12 @@ func (s *SQLiteDB) ListSessionsWithDateRange(ctx context.Context, fromDate, toDa
 			HourlyRate:      &sessionRate,
 			FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
 			OutsideGit:      nullStringToPtr(session.OutsideGit),
+			InvoiceID:       nullStringToPtr(session.InvoiceID),
+			Hostname:        nullStringToPtr(session.Hostname),
+			OS:              nullStringToPtr(session.Os),
+			GitBranch:       nullStringToPtr(session.GitBranch),
+			BreakSeconds:    session.BreakSeconds,
+			UserID:          nullStringToPtr(session.UserID),
 			CreatedAt:       session.CreatedAt,
 			UpdatedAt:       session.UpdatedAt,
 			ClientName:      session.ClientName,
@@ -416,6 +791,11 @@ func (s *SQLiteDB) ListSessionsByClient(ctx context.Context, clientName string,
 			HourlyRate:      &sessionRate,
 			FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
 			OutsideGit:      nullStringToPtr(session.OutsideGit),
+			Hostname:        nullStringToPtr(session.Hostname),
+			OS:              nullStringToPtr(session.Os),
+			GitBranch:       nullStringToPtr(session.GitBranch),
+			BreakSeconds:    session.BreakSeconds,
+			UserID:          nullStringToPtr(session.UserID),
 			CreatedAt:       session.CreatedAt,
 			UpdatedAt:       session.UpdatedAt,
 			ClientName:      session.ClientName,
@@ -426,41 +806,66 @@ func (s *SQLiteDB) ListSessionsByClient(ctx context.Context, clientName string,
 }
 
 func (s *SQLiteDB) UpdateClient(ctx context.Context, clientID string, updates *ClientUpdateDetails) (*models.Client, error) {
-	client, err := s.queries.UpdateClient(ctx, db.UpdateClientParams{
-		ID:             clientID,
-		HourlyRate:     ptrToNullDecimal(updates.HourlyRate),
-		CompanyName:    ptrToNullString(updates.CompanyName),
-		ContactName:    ptrToNullString(updates.ContactName),
-		Email:          ptrToNullString(updates.Email),
-		Phone:          ptrToNullString(updates.Phone),
-		AddressLine1:   ptrToNullString(updates.AddressLine1),
-		AddressLine2:   ptrToNullString(updates.AddressLine2),
-		City:           ptrToNullString(updates.City),
-		State:          ptrToNullString(updates.State),
-		PostalCode:     ptrToNullString(updates.PostalCode),
-		Country:        ptrToNullString(updates.Country),
-		Abn:            ptrToNullString(updates.Abn),
-		Dir:            ptrToNullString(updates.Dir),
-		RetainerAmount: ptrToNullDecimal(updates.RetainerAmount),
-		RetainerHours:  ptrToNullFloat64(updates.RetainerHours),
-		RetainerBasis:  ptrToNullString(updates.RetainerBasis),
-	})
+	params := db.UpdateClientParams{
+		ID:                   clientID,
+		HourlyRate:           ptrToNullDecimal(updates.HourlyRate),
+		CompanyName:          ptrToNullString(updates.CompanyName),
+		ContactName:          ptrToNullString(updates.ContactName),
+		Email:                ptrToNullString(updates.Email),
+		Phone:                ptrToNullString(updates.Phone),
+		AddressLine1:         ptrToNullString(updates.AddressLine1),
+		AddressLine2:         ptrToNullString(updates.AddressLine2),
+		City:                 ptrToNullString(updates.City),
+		State:                ptrToNullString(updates.State),
+		PostalCode:           ptrToNullString(updates.PostalCode),
+		Country:              ptrToNullString(updates.Country),
+		Abn:                  ptrToNullString(updates.Abn),
+		Dir:                  ptrToNullString(updates.Dir),
+		RetainerAmount:       ptrToNullDecimal(updates.RetainerAmount),
+		RetainerHours:        ptrToNullFloat64(updates.RetainerHours),
+		RetainerBasis:        ptrToNullString(updates.RetainerBasis),
+		Currency:             ptrToNullString(updates.Currency),
+		Locale:               ptrToNullString(updates.Locale),
+		Source:               ptrToNullString(updates.Source),
+		WorkLog:              ptrToNullString(updates.WorkLog),
+		RetainerStartDate:    ptrToNullTime(updates.RetainerStart),
+		EarlyDiscountPercent: ptrToNullFloat64(updates.EarlyDiscountPercent),
+		EarlyDiscountDays:    ptrToNullInt64(updates.EarlyDiscountDays),
+		InvoiceNotes:         ptrToNullString(updates.InvoiceNotes),
+		PaymentTerms:         ptrToNullString(updates.PaymentTerms),
+		PoNumber:             ptrToNullString(updates.PoNumber),
+		ProjectCode:          ptrToNullString(updates.ProjectCode),
+		InvoiceRounding:      ptrToNullDecimal(updates.InvoiceRounding),
+		TaxTreatment:         ptrToNullString(updates.TaxTreatment),
+		LogoPath:             ptrToNullString(updates.LogoPath),
+		BrandColour:          ptrToNullString(updates.BrandColour),
+		AnalysisMaxCommits:   ptrToNullInt64(updates.AnalysisMaxCommits),
+		AnalysisIgnore:       ptrToNullString(updates.AnalysisIgnore),
+		AnalysisDetail:       ptrToNullString(updates.AnalysisDetail),
+		AnalysisPrompt:       ptrToNullString(updates.AnalysisPrompt),
+	}
+	for _, field := range []*sql.NullString{&params.ContactName, &params.Email, &params.Phone, &params.AddressLine1, &params.AddressLine2, &params.Abn} {
+		if err := s.encryptField(field); err != nil {
+			return nil, fmt.Errorf("failed to encrypt client details: %w", err)
+		}
+	}
+	client, err := s.queries.UpdateClient(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update client billing: %w", err)
 	}
 
-	return s.convertDBClientToModel(client), nil
+	return s.convertDBClientToModel(client)
 }
 
-func (s *SQLiteDB) DeleteAllSessions(ctx context.Context) error {
-	err := s.queries.DeleteAllSessions(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to delete all sessions: %w", err)
-	}
-	return nil
+// DeleteAllSessions moves every session to the trash, as DeleteSessionsByDateRange does for a date range
+func (s *SQLiteDB) DeleteAllSessions(ctx context.Context, includeInvoiced bool) (int64, int64, error) {
+	return s.DeleteSessionsByDateRange(ctx, "", "", includeInvoiced)
 }
 
-func (s *SQLiteDB) DeleteSessionsByDateRange(ctx context.Context, fromDate, toDate string) error {
+// DeleteSessionsByDateRange moves the sessions started in a date range to the trash, returning how many were
+// deleted and how many were skipped for being on an invoice. Invoiced sessions are only deleted with
+// includeInvoiced, which flags their invoices as needing regeneration.
+func (s *SQLiteDB) DeleteSessionsByDateRange(ctx context.Context, fromDate, toDate string, includeInvoiced bool) (int64, int64, error) {
 	var startDate, endDate any
 	if fromDate != "" {
 		startDate = fromDate
@@ -469,14 +874,52 @@ func (s *SQLiteDB) DeleteSessionsByDateRange(ctx context.Context, fromDate, toDa
 		endDate = toDate
 	}
 
-	err := s.queries.DeleteSessionsByDateRange(ctx, db.DeleteSessionsByDateRangeParams{
-		StartDate: startDate,
-		EndDate:   endDate,
+	var tx *sql.Tx
+	var qtx *db.Queries
+	var skipped int64
+	var err error
+	if includeInvoiced {
+		tx, qtx, err = s.beginSessionsChange(ctx, startDate, endDate, true)
+		if err != nil {
+			return 0, 0, err
+		}
+	} else {
+		tx, err = s.beginTx(ctx)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		qtx = s.queries.WithTx(tx)
+		skipped, err = qtx.CountInvoicedSessionsByDateRange(ctx, db.CountInvoicedSessionsByDateRangeParams{
+			StartDate: startDate,
+			EndDate:   endDate,
+		})
+		if err != nil {
+			tx.Rollback()
+			return 0, 0, fmt.Errorf("failed to count invoiced sessions: %w", err)
+		}
+	}
+	defer tx.Rollback()
+
+	deleted, err := qtx.TrashSessionsByDateRange(ctx, db.TrashSessionsByDateRangeParams{
+		DeletedAt:       sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		StartDate:       startDate,
+		EndDate:         endDate,
+		IncludeInvoiced: includeInvoiced,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to delete sessions by date range: %w", err)
+		return 0, 0, fmt.Errorf("failed to delete sessions by date range: %w", err)
 	}
-	return nil
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return deleted, skipped, nil
+}
+
+func ptrToNullTime(t *time.Time) sql.NullTime {
+	if t != nil {
+		return sql.NullTime{Time: *t, Valid: true}
+	}
+	return sql.NullTime{Valid: false}
 }
 
 func nullTimeToPtr(nt sql.NullTime) *time.Time {
@@ -500,33 +943,57 @@ func nullFloat64ToPtr(nf sql.NullFloat64) *float64 {
 	return nil
 }
 
-func (s *SQLiteDB) convertDBClientToModel(client db.Client) *models.Client {
+func (s *SQLiteDB) convertDBClientToModel(client db.Client) (*models.Client, error) {
+	for _, field := range clientContactFields(&client) {
+		if err := s.decryptField(field); err != nil {
+			return nil, fmt.Errorf("failed to read client '%s': %w", client.Name, err)
+		}
+	}
 	var rate decimal.Decimal
 	if client.HourlyRate.Valid {
 		rate = client.HourlyRate.Decimal
 	}
 	return &models.Client{
-		ID:             client.ID,
-		Name:           client.Name,
-		HourlyRate:     rate,
-		CompanyName:    nullStringToPtr(client.CompanyName),
-		ContactName:    nullStringToPtr(client.ContactName),
-		Email:          nullStringToPtr(client.Email),
-		Phone:          nullStringToPtr(client.Phone),
-		AddressLine1:   nullStringToPtr(client.AddressLine1),
-		AddressLine2:   nullStringToPtr(client.AddressLine2),
-		City:           nullStringToPtr(client.City),
-		State:          nullStringToPtr(client.State),
-		PostalCode:     nullStringToPtr(client.PostalCode),
-		Country:        nullStringToPtr(client.Country),
-		Abn:            nullStringToPtr(client.Abn),
-		Dir:            nullStringToPtr(client.Dir),
-		RetainerAmount: nullDecimalToPtr(client.RetainerAmount),
-		RetainerHours:  nullFloat64ToPtr(client.RetainerHours),
-		RetainerBasis:  nullStringToPtr(client.RetainerBasis),
-		CreatedAt:      client.CreatedAt,
-		UpdatedAt:      client.UpdatedAt,
-	}
+		ID:                   client.ID,
+		Name:                 client.Name,
+		HourlyRate:           rate,
+		CompanyName:          nullStringToPtr(client.CompanyName),
+		ContactName:          nullStringToPtr(client.ContactName),
+		Email:                nullStringToPtr(client.Email),
+		Phone:                nullStringToPtr(client.Phone),
+		AddressLine1:         nullStringToPtr(client.AddressLine1),
+		AddressLine2:         nullStringToPtr(client.AddressLine2),
+		City:                 nullStringToPtr(client.City),
+		State:                nullStringToPtr(client.State),
+		PostalCode:           nullStringToPtr(client.PostalCode),
+		Country:              nullStringToPtr(client.Country),
+		Abn:                  nullStringToPtr(client.Abn),
+		Dir:                  nullStringToPtr(client.Dir),
+		RetainerAmount:       nullDecimalToPtr(client.RetainerAmount),
+		RetainerHours:        nullFloat64ToPtr(client.RetainerHours),
+		RetainerBasis:        nullStringToPtr(client.RetainerBasis),
+		Currency:             nullStringToPtr(client.Currency),
+		Locale:               nullStringToPtr(client.Locale),
+		Source:               nullStringToPtr(client.Source),
+		WorkLog:              nullStringToPtr(client.WorkLog),
+		RetainerStart:        nullTimeToPtr(client.RetainerStartDate),
+		EarlyDiscountPercent: nullFloat64ToPtr(client.EarlyDiscountPercent),
+		EarlyDiscountDays:    nullInt64ToPtr(client.EarlyDiscountDays),
+		InvoiceNotes:         nullStringToPtr(client.InvoiceNotes),
+		PaymentTerms:         nullStringToPtr(client.PaymentTerms),
+		PoNumber:             nullStringToPtr(client.PoNumber),
+		ProjectCode:          nullStringToPtr(client.ProjectCode),
+		InvoiceRounding:      nullDecimalToPtr(client.InvoiceRounding),
+		TaxTreatment:         client.TaxTreatment,
+		LogoPath:             nullStringToPtr(client.LogoPath),
+		BrandColour:          nullStringToPtr(client.BrandColour),
+		AnalysisMaxCommits:   nullInt64ToPtr(client.AnalysisMaxCommits),
+		AnalysisIgnore:       nullStringToPtr(client.AnalysisIgnore),
+		AnalysisDetail:       nullStringToPtr(client.AnalysisDetail),
+		AnalysisPrompt:       nullStringToPtr(client.AnalysisPrompt),
+		CreatedAt:            client.CreatedAt,
+		UpdatedAt:            client.UpdatedAt,
+	}, nil
 }
 
 func ptrToNullString(s *string) sql.NullString {
@@ -536,6 +1003,20 @@ func ptrToNullString(s *string) sql.NullString {
 	return sql.NullString{Valid: false}
 }
 
+func nullInt64ToPtr(ni sql.NullInt64) *int64 {
+	if ni.Valid {
+		return &ni.Int64
+	}
+	return nil
+}
+
+func ptrToNullInt64(i *int64) sql.NullInt64 {
+	if i != nil {
+		return sql.NullInt64{Int64: *i, Valid: true}
+	}
+	return sql.NullInt64{Valid: false}
+}
+
 func ptrToNullFloat64(f *float64) sql.NullFloat64 {
 	if f != nil {
 		return sql.NullFloat64{Float64: *f, Valid: true}
@@ -557,6 +1038,58 @@ func nullDecimalToPtr(nd decimal.NullDecimal) *decimal.Decimal {
 	return nil
 }
 
+// currentHostname identifies the machine a session was started on, for databases shared between machines
+func (s *SQLiteDB) currentHostname() sql.NullString {
+	if !s.metadata {
+		return sql.NullString{}
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: hostname, Valid: true}
+}
+
+// currentOS is the operating system a session was started on
+func (s *SQLiteDB) currentOS() sql.NullString {
+	if !s.metadata {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: runtime.GOOS, Valid: true}
+}
+
+// currentGitBranch is the branch checked out in the working directory a session was started from, null outside
+// a repository or on a detached HEAD
+func (s *SQLiteDB) currentGitBranch(ctx context.Context) sql.NullString {
+	if !s.metadata {
+		return sql.NullString{}
+	}
+	out, err := exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return sql.NullString{}
+	}
+	branch := strings.TrimSpace(string(out))
+	if branch == "" || branch == "HEAD" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: branch, Valid: true}
+}
+
+// currentUserID is the ID of the WORK_USER sessions started here are attributed to, or null for the owner
+func (s *SQLiteDB) currentUserID(ctx context.Context) (sql.NullString, error) {
+	if s.workUser == "" {
+		return sql.NullString{}, nil
+	}
+	user, err := s.queries.GetUserByName(ctx, s.workUser)
+	if err == sql.ErrNoRows {
+		return sql.NullString{}, fmt.Errorf("WORK_USER is '%s' but there's no such user, add them with 'work users create %s'", s.workUser, s.workUser)
+	}
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to get user '%s': %w", s.workUser, err)
+	}
+	return sql.NullString{String: user.ID, Valid: true}, nil
+}
+
 func (s *SQLiteDB) convertDBSessionToModel(session interface{}) *models.WorkSession {
 	switch dbSession := session.(type) {
 	case db.Session:
@@ -573,6 +1106,11 @@ func (s *SQLiteDB) convertDBSessionToModel(session interface{}) *models.WorkSess
 			HourlyRate:      &rate,
 			FullWorkSummary: nullStringToPtr(dbSession.FullWorkSummary),
 			OutsideGit:      nullStringToPtr(dbSession.OutsideGit),
+			Hostname:        nullStringToPtr(dbSession.Hostname),
+			OS:              nullStringToPtr(dbSession.Os),
+			GitBranch:       nullStringToPtr(dbSession.GitBranch),
+			BreakSeconds:    dbSession.BreakSeconds,
+			UserID:          nullStringToPtr(dbSession.UserID),
 			IncludesGst:     dbSession.IncludesGst,
 			CreatedAt:       dbSession.CreatedAt,
 			UpdatedAt:       dbSession.UpdatedAt,
@@ -617,6 +1155,12 @@ func (s *SQLiteDB) GetSessionsWithoutDescription(ctx context.Context, clientName
 			HourlyRate:      &sessionRate,
 			FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
 			OutsideGit:      nullStringToPtr(session.OutsideGit),
+			Hostname:        nullStringToPtr(session.Hostname),
+			OS:              nullStringToPtr(session.Os),
+			GitBranch:       nullStringToPtr(session.GitBranch),
+			BreakSeconds:    session.BreakSeconds,
+			UserID:          nullStringToPtr(session.UserID),
+			InvoiceID:       nullStringToPtr(session.InvoiceID),
 			CreatedAt:       session.CreatedAt,
 			UpdatedAt:       session.UpdatedAt,
 			ClientName:      session.ClientName,
@@ -626,8 +1170,14 @@ func (s *SQLiteDB) GetSessionsWithoutDescription(ctx context.Context, clientName
 	return result, nil
 }
 
-func (s *SQLiteDB) UpdateSessionDescription(ctx context.Context, sessionID string, description string, fullWorkSummary *string) (*models.WorkSession, error) {
-	session, err := s.queries.UpdateSessionDescription(ctx, db.UpdateSessionDescriptionParams{
+func (s *SQLiteDB) UpdateSessionDescription(ctx context.Context, sessionID string, description string, fullWorkSummary *string, force bool) (*models.WorkSession, error) {
+	tx, qtx, err := s.beginSessionChange(ctx, sessionID, force)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session, err := qtx.UpdateSessionDescription(ctx, db.UpdateSessionDescriptionParams{
 		ID:              sessionID,
 		Description:     sql.NullString{String: description, Valid: true},
 		FullWorkSummary: ptrToNullString(fullWorkSummary),
@@ -635,6 +1185,9 @@ func (s *SQLiteDB) UpdateSessionDescription(ctx context.Context, sessionID strin
 	if err != nil {
 		return nil, fmt.Errorf("failed to update session description: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit session description: %w", err)
+	}
 
 	sessionRate := decimal.Zero
 	if session.HourlyRate.Valid {
@@ -650,6 +1203,11 @@ func (s *SQLiteDB) UpdateSessionDescription(ctx context.Context, sessionID strin
 		HourlyRate:      &sessionRate,
 		FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
 		OutsideGit:      nullStringToPtr(session.OutsideGit),
+		Hostname:        nullStringToPtr(session.Hostname),
+		OS:              nullStringToPtr(session.Os),
+		GitBranch:       nullStringToPtr(session.GitBranch),
+		BreakSeconds:    session.BreakSeconds,
+		UserID:          nullStringToPtr(session.UserID),
 		CreatedAt:       session.CreatedAt,
 		UpdatedAt:       session.UpdatedAt,
 	}, nil
@@ -675,20 +1233,96 @@ func (s *SQLiteDB) GetSessionByID(ctx context.Context, sessionID string) (*model
 		HourlyRate:      &sessionRate,
 		FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
 		OutsideGit:      nullStringToPtr(session.OutsideGit),
+		InvoiceID:       nullStringToPtr(session.InvoiceID),
+		IncludesGst:     session.IncludesGst,
+		Hostname:        nullStringToPtr(session.Hostname),
+		OS:              nullStringToPtr(session.Os),
+		GitBranch:       nullStringToPtr(session.GitBranch),
+		BreakSeconds:    session.BreakSeconds,
+		UserID:          nullStringToPtr(session.UserID),
 		CreatedAt:       session.CreatedAt,
 		UpdatedAt:       session.UpdatedAt,
 		ClientName:      session.ClientName,
 	}, nil
 }
 
-func (s *SQLiteDB) UpdateSessionOutsideGit(ctx context.Context, sessionID string, outsideGit string) (*models.WorkSession, error) {
-	session, err := s.queries.UpdateSessionOutsideGit(ctx, db.UpdateSessionOutsideGitParams{
+func (s *SQLiteDB) UpdateSessionRate(ctx context.Context, sessionID string, hourlyRate decimal.Decimal, includesGst bool, force bool) (*models.WorkSession, error) {
+	tx, qtx, err := s.beginSessionChange(ctx, sessionID, force)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session, err := qtx.UpdateSessionRate(ctx, db.UpdateSessionRateParams{
+		ID:          sessionID,
+		HourlyRate:  decimal.NullDecimal{Decimal: hourlyRate, Valid: true},
+		IncludesGst: includesGst,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update session rate: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit session rate: %w", err)
+	}
+
+	return &models.WorkSession{
+		ID:              session.ID,
+		ClientID:        session.ClientID,
+		StartTime:       session.StartTime,
+		EndTime:         nullTimeToPtr(session.EndTime),
+		Description:     nullStringToPtr(session.Description),
+		HourlyRate:      &session.HourlyRate.Decimal,
+		FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
+		OutsideGit:      nullStringToPtr(session.OutsideGit),
+		IncludesGst:     session.IncludesGst,
+		Hostname:        nullStringToPtr(session.Hostname),
+		OS:              nullStringToPtr(session.Os),
+		GitBranch:       nullStringToPtr(session.GitBranch),
+		BreakSeconds:    session.BreakSeconds,
+		UserID:          nullStringToPtr(session.UserID),
+		CreatedAt:       session.CreatedAt,
+		UpdatedAt:       session.UpdatedAt,
+	}, nil
+}
+
+func (s *SQLiteDB) UpdateSessionUser(ctx context.Context, sessionID string, userID *string, force bool) (*models.WorkSession, error) {
+	tx, qtx, err := s.beginSessionChange(ctx, sessionID, force)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session, err := qtx.UpdateSessionUser(ctx, db.UpdateSessionUserParams{
+		ID:     sessionID,
+		UserID: ptrToNullString(userID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update session user: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit session user: %w", err)
+	}
+
+	return s.convertDBSessionToModel(session), nil
+}
+
+func (s *SQLiteDB) UpdateSessionOutsideGit(ctx context.Context, sessionID string, outsideGit string, force bool) (*models.WorkSession, error) {
+	tx, qtx, err := s.beginSessionChange(ctx, sessionID, force)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session, err := qtx.UpdateSessionOutsideGit(ctx, db.UpdateSessionOutsideGitParams{
 		ID:         sessionID,
 		OutsideGit: sql.NullString{String: outsideGit, Valid: true},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to update session outside git: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit session notes: %w", err)
+	}
 
 	sessionRate := decimal.Zero
 	if session.HourlyRate.Valid {
@@ -704,6 +1338,11 @@ func (s *SQLiteDB) UpdateSessionOutsideGit(ctx context.Context, sessionID string
 		HourlyRate:      &sessionRate,
 		FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
 		OutsideGit:      nullStringToPtr(session.OutsideGit),
+		Hostname:        nullStringToPtr(session.Hostname),
+		OS:              nullStringToPtr(session.Os),
+		GitBranch:       nullStringToPtr(session.GitBranch),
+		BreakSeconds:    session.BreakSeconds,
+		UserID:          nullStringToPtr(session.UserID),
 		CreatedAt:       session.CreatedAt,
 		UpdatedAt:       session.UpdatedAt,
 	}, nil
@@ -711,7 +1350,7 @@ func (s *SQLiteDB) UpdateSessionOutsideGit(ctx context.Context, sessionID string
 
 // Invoice methods
 
-func (s *SQLiteDB) CreateInvoice(ctx context.Context, clientID, invoiceNumber, periodType string, periodStart, periodEnd time.Time, subtotal, gst, total decimal.Decimal) (*models.Invoice, error) {
+func (s *SQLiteDB) CreateInvoice(ctx context.Context, clientID, invoiceNumber, periodType string, periodStart, periodEnd time.Time, subtotal, gst, total decimal.Decimal, groupBy string, notes, paymentTerms, poNumber, projectCode *string, rounding *decimal.Decimal) (*models.Invoice, error) {
 	invoice, err := s.queries.CreateInvoice(ctx, db.CreateInvoiceParams{
 		ID:              models.NewUUID(),
 		ClientID:        clientID,
@@ -722,6 +1361,12 @@ func (s *SQLiteDB) CreateInvoice(ctx context.Context, clientID, invoiceNumber, p
 		SubtotalAmount:  subtotal,
 		GstAmount:       gst,
 		TotalAmount:     total,
+		GroupBy:         groupBy,
+		Notes:           ptrToNullString(notes),
+		PaymentTerms:    ptrToNullString(paymentTerms),
+		PoNumber:        ptrToNullString(poNumber),
+		ProjectCode:     ptrToNullString(projectCode),
+		Rounding:        ptrToNullDecimal(rounding),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create invoice: %w", err)
@@ -827,6 +1472,11 @@ func (s *SQLiteDB) GetSessionsForPeriodWithoutInvoice(ctx context.Context, start
 			HourlyRate:      &sessionRate,
 			FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
 			OutsideGit:      nullStringToPtr(session.OutsideGit),
+			Hostname:        nullStringToPtr(session.Hostname),
+			OS:              nullStringToPtr(session.Os),
+			GitBranch:       nullStringToPtr(session.GitBranch),
+			BreakSeconds:    session.BreakSeconds,
+			UserID:          nullStringToPtr(session.UserID),
 			InvoiceID:       nullStringToPtr(session.InvoiceID),
 			IncludesGst:     session.IncludesGst,
 			CreatedAt:       session.CreatedAt,
@@ -860,7 +1510,13 @@ func (s *SQLiteDB) GetSessionsByInvoiceID(ctx context.Context, invoiceID string)
 			HourlyRate:      &sessionRate,
 			FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
 			OutsideGit:      nullStringToPtr(session.OutsideGit),
+			Hostname:        nullStringToPtr(session.Hostname),
+			OS:              nullStringToPtr(session.Os),
+			GitBranch:       nullStringToPtr(session.GitBranch),
+			BreakSeconds:    session.BreakSeconds,
+			UserID:          nullStringToPtr(session.UserID),
 			InvoiceID:       nullStringToPtr(session.InvoiceID),
+			IncludesGst:     session.IncludesGst,
 			CreatedAt:       session.CreatedAt,
 			UpdatedAt:       session.UpdatedAt,
 			ClientName:      session.ClientName,
@@ -870,42 +1526,139 @@ func (s *SQLiteDB) GetSessionsByInvoiceID(ctx context.Context, invoiceID string)
 	return result, nil
 }
 
-func (s *SQLiteDB) UpdateSessionInvoiceID(ctx context.Context, sessionID, invoiceID string) error {
-	err := s.queries.UpdateSessionInvoiceID(ctx, db.UpdateSessionInvoiceIDParams{
-		InvoiceID: sql.NullString{String: invoiceID, Valid: true},
-		SessionID: sessionID,
+func (s *SQLiteDB) UpdateInvoiceAmounts(ctx context.Context, invoiceID string, subtotal, gst, total decimal.Decimal) error {
+	err := s.queries.UpdateInvoiceAmounts(ctx, db.UpdateInvoiceAmountsParams{
+		SubtotalAmount: subtotal,
+		GstAmount:      gst,
+		TotalAmount:    total,
+		ID:             invoiceID,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to update session invoice ID: %w", err)
+		return fmt.Errorf("failed to update invoice amounts: %w", err)
 	}
 	return nil
 }
 
-func (s *SQLiteDB) ClearSessionInvoiceIDs(ctx context.Context, invoiceID string) error {
-	err := s.queries.ClearSessionInvoiceIDs(ctx, sql.NullString{String: invoiceID, Valid: true})
+func (s *SQLiteDB) UpdateInvoiceGroupBy(ctx context.Context, invoiceID, groupBy string) error {
+	err := s.queries.UpdateInvoiceGroupBy(ctx, db.UpdateInvoiceGroupByParams{
+		GroupBy: groupBy,
+		ID:      invoiceID,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to clear session invoice IDs: %w", err)
+		return fmt.Errorf("failed to update invoice grouping: %w", err)
 	}
 	return nil
 }
 
-func (s *SQLiteDB) GetSessionsForPeriodWithoutInvoiceByClient(ctx context.Context, startDate, endDate time.Time, clientName string) ([]*models.WorkSession, error) {
-	sessions, err := s.queries.GetSessionsForPeriodWithoutInvoiceByClient(ctx, db.GetSessionsForPeriodWithoutInvoiceByClientParams{
-		StartDate:  startDate,
-		EndDate:    endDate,
-		ClientName: clientName,
+func (s *SQLiteDB) UpdateInvoiceNotes(ctx context.Context, invoiceID string, notes *string) error {
+	err := s.queries.UpdateInvoiceNotes(ctx, db.UpdateInvoiceNotesParams{
+		Notes: ptrToNullString(notes),
+		ID:    invoiceID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sessions for period without invoice by client: %w", err)
+		return fmt.Errorf("failed to update invoice notes: %w", err)
 	}
+	return nil
+}
+
+func (s *SQLiteDB) UpdateInvoiceReferences(ctx context.Context, invoiceID string, poNumber, projectCode *string) error {
+	err := s.queries.UpdateInvoiceReferences(ctx, db.UpdateInvoiceReferencesParams{
+		PoNumber:    ptrToNullString(poNumber),
+		ProjectCode: ptrToNullString(projectCode),
+		ID:          invoiceID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update invoice references: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) UpdateInvoiceStatus(ctx context.Context, invoiceID, status string, reason *string, changedAt time.Time, writtenOff decimal.Decimal) error {
+	err := s.queries.UpdateInvoiceStatus(ctx, db.UpdateInvoiceStatusParams{
+		Status:           status,
+		StatusReason:     ptrToNullString(reason),
+		StatusChangedAt:  sql.NullTime{Time: changedAt, Valid: true},
+		AmountWrittenOff: writtenOff,
+		ID:               invoiceID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update invoice status: %w", err)
+	}
+	return nil
+}
+
+// VoidInvoice marks an invoice void and takes its sessions, expenses and milestones off it, in one transaction,
+// so they can be invoiced again
+func (s *SQLiteDB) VoidInvoice(ctx context.Context, invoiceID string, reason *string, voidedAt time.Time) error {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+	err = qtx.UpdateInvoiceStatus(ctx, db.UpdateInvoiceStatusParams{
+		Status:           models.InvoiceStatusVoid,
+		StatusReason:     ptrToNullString(reason),
+		StatusChangedAt:  sql.NullTime{Time: voidedAt, Valid: true},
+		AmountWrittenOff: decimal.Zero,
+		ID:               invoiceID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to void invoice: %w", err)
+	}
+	if err := qtx.ClearSessionInvoiceIDs(ctx, sql.NullString{String: invoiceID, Valid: true}); err != nil {
+		return fmt.Errorf("failed to release sessions from void invoice: %w", err)
+	}
+	if err := qtx.ClearExpenseInvoiceIDs(ctx, sql.NullString{String: invoiceID, Valid: true}); err != nil {
+		return fmt.Errorf("failed to release expenses from void invoice: %w", err)
+	}
+	if err := qtx.ClearMilestoneInvoiceIDs(ctx, sql.NullString{String: invoiceID, Valid: true}); err != nil {
+		return fmt.Errorf("failed to release milestones from void invoice: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit void invoice: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) UpdateSessionInvoiceID(ctx context.Context, sessionID, invoiceID string) error {
+	err := s.queries.UpdateSessionInvoiceID(ctx, db.UpdateSessionInvoiceIDParams{
+		InvoiceID: sql.NullString{String: invoiceID, Valid: true},
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update session invoice ID: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) ClearSessionInvoiceIDs(ctx context.Context, invoiceID string) error {
+	err := s.queries.ClearSessionInvoiceIDs(ctx, sql.NullString{String: invoiceID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("failed to clear session invoice IDs: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) GetSessionsForPeriodWithoutInvoiceByClient(ctx context.Context, startDate, endDate time.Time, clientName string) ([]*models.WorkSession, error) {
+	sessions, err := s.queries.GetSessionsForPeriodWithoutInvoiceByClient(ctx, db.GetSessionsForPeriodWithoutInvoiceByClientParams{
+		StartDate:  startDate,
+		EndDate:    endDate,
+		ClientName: clientName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions for period without invoice by client: %w", err)
+	}
+
+	result := make([]*models.WorkSession, len(sessions))
+	for i, session := range sessions {
+		sessionRate := decimal.Zero
+		if session.HourlyRate.Valid {
+			sessionRate = session.HourlyRate.Decimal
+		}
 
-	result := make([]*models.WorkSession, len(sessions))
-	for i, session := range sessions {
-		sessionRate := decimal.Zero
-		if session.HourlyRate.Valid {
-			sessionRate = session.HourlyRate.Decimal
-		}
-
 		result[i] = &models.WorkSession{
 			ID:              session.ID,
 			ClientID:        session.ClientID,
@@ -915,6 +1668,11 @@ func (s *SQLiteDB) GetSessionsForPeriodWithoutInvoiceByClient(ctx context.Contex
 			HourlyRate:      &sessionRate,
 			FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
 			OutsideGit:      nullStringToPtr(session.OutsideGit),
+			Hostname:        nullStringToPtr(session.Hostname),
+			OS:              nullStringToPtr(session.Os),
+			GitBranch:       nullStringToPtr(session.GitBranch),
+			BreakSeconds:    session.BreakSeconds,
+			UserID:          nullStringToPtr(session.UserID),
 			InvoiceID:       nullStringToPtr(session.InvoiceID),
 			IncludesGst:     session.IncludesGst,
 			CreatedAt:       session.CreatedAt,
@@ -953,25 +1711,131 @@ func (s *SQLiteDB) PayInvoice(ctx context.Context, param db.PayInvoiceParams) er
 	return nil
 }
 
+func (s *SQLiteDB) ListPaymentsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.Payment, error) {
+	payments, err := s.queries.ListPaymentsByDateRange(ctx, db.ListPaymentsByDateRangeParams{
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+
+	result := make([]*models.Payment, len(payments))
+	for i, payment := range payments {
+		result[i] = convertDBPaymentToModel(payment)
+	}
+	return result, nil
+}
+
+func (s *SQLiteDB) ListPayments(ctx context.Context, invoiceID, clientName string) ([]*models.Payment, error) {
+	payments, err := s.queries.ListPayments(ctx, db.ListPaymentsParams{
+		InvoiceID:  sql.NullString{String: invoiceID, Valid: invoiceID != ""},
+		ClientName: sql.NullString{String: clientName, Valid: clientName != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+
+	result := make([]*models.Payment, len(payments))
+	for i, row := range payments {
+		payment := convertDBPaymentToModel(db.Payment{
+			ID:             row.ID,
+			InvoiceID:      row.InvoiceID,
+			Amount:         row.Amount,
+			PaymentDate:    row.PaymentDate,
+			CreatedAt:      row.CreatedAt,
+			UpdatedAt:      row.UpdatedAt,
+			DiscountAmount: row.DiscountAmount,
+		})
+		payment.InvoiceNumber = row.InvoiceNumber
+		payment.ClientName = row.ClientName
+		result[i] = payment
+	}
+	return result, nil
+}
+
+func (s *SQLiteDB) GetPaymentByID(ctx context.Context, paymentID string) (*models.Payment, error) {
+	payment, err := s.queries.GetPaymentByID(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	return convertDBPaymentToModel(payment), nil
+}
+
+// DeletePayment removes a payment. The invoice's amount paid is summed from its payments, so it drops by the
+// payment and any early payment discount it took.
+func (s *SQLiteDB) DeletePayment(ctx context.Context, paymentID string) error {
+	if err := s.queries.DeletePayment(ctx, paymentID); err != nil {
+		return fmt.Errorf("failed to delete payment: %w", err)
+	}
+	return nil
+}
+
+func convertDBPaymentToModel(payment db.Payment) *models.Payment {
+	return &models.Payment{
+		ID:             payment.ID,
+		InvoiceID:      payment.InvoiceID,
+		Amount:         payment.Amount,
+		PaymentDate:    payment.PaymentDate,
+		DiscountAmount: payment.DiscountAmount,
+		CreatedAt:      payment.CreatedAt,
+		UpdatedAt:      payment.UpdatedAt,
+	}
+}
+
+// PayInvoices records all payments in a single transaction, so either every payment is recorded or none are
+func (s *SQLiteDB) PayInvoices(ctx context.Context, params []db.PayInvoiceParams) error {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+	for _, param := range params {
+		if err := qtx.PayInvoice(ctx, param); err != nil {
+			return fmt.Errorf("failed to pay invoice: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit payments: %w", err)
+	}
+	return nil
+}
+
 func (s *SQLiteDB) convertDBInvoicesByPeriodAndClientRowToModel(invoice db.GetInvoicesByPeriodAndClientRow) *models.Invoice {
 	paymentDate := convertPaymentDate(invoice.PaymentDate)
 
 	return &models.Invoice{
-		ID:              invoice.ID,
-		ClientID:        invoice.ClientID,
-		InvoiceNumber:   invoice.InvoiceNumber,
-		PeriodType:      invoice.PeriodType,
-		PeriodStartDate: invoice.PeriodStartDate,
-		PeriodEndDate:   invoice.PeriodEndDate,
-		SubtotalAmount:  invoice.SubtotalAmount,
-		GstAmount:       invoice.GstAmount,
-		TotalAmount:     invoice.TotalAmount,
-		GeneratedDate:   invoice.GeneratedDate,
-		AmountPaid:      decimal.NewFromFloat(invoice.AmountPaid),
-		PaymentDate:     paymentDate,
-		CreatedAt:       invoice.CreatedAt,
-		UpdatedAt:       invoice.UpdatedAt,
-		ClientName:      invoice.ClientName,
+		ID:                invoice.ID,
+		ClientID:          invoice.ClientID,
+		InvoiceNumber:     invoice.InvoiceNumber,
+		PeriodType:        invoice.PeriodType,
+		PeriodStartDate:   invoice.PeriodStartDate,
+		PeriodEndDate:     invoice.PeriodEndDate,
+		SubtotalAmount:    invoice.SubtotalAmount,
+		GstAmount:         invoice.GstAmount,
+		TotalAmount:       invoice.TotalAmount,
+		GeneratedDate:     invoice.GeneratedDate,
+		AmountPaid:        decimal.NewFromFloat(invoice.AmountPaid),
+		PaymentDate:       paymentDate,
+		AmountCredited:    decimal.NewFromFloat(invoice.AmountCredited),
+		AmountDiscounted:  decimal.NewFromFloat(invoice.AmountDiscounted),
+		CreatedAt:         invoice.CreatedAt,
+		UpdatedAt:         invoice.UpdatedAt,
+		GroupBy:           invoice.GroupBy,
+		NeedsRegeneration: invoice.NeedsRegeneration,
+		Notes:             nullStringToPtr(invoice.Notes),
+		PaymentTerms:      nullStringToPtr(invoice.PaymentTerms),
+		PoNumber:          nullStringToPtr(invoice.PoNumber),
+		ProjectCode:       nullStringToPtr(invoice.ProjectCode),
+		Status:            invoice.Status,
+		StatusReason:      nullStringToPtr(invoice.StatusReason),
+		StatusChangedAt:   nullTimeToPtr(invoice.StatusChangedAt),
+		AmountWrittenOff:  invoice.AmountWrittenOff,
+		Rounding:          nullDecimalToPtr(invoice.Rounding),
+		ClientName:        invoice.ClientName,
 	}
 }
 
@@ -1009,18 +1873,29 @@ func convertPaymentDate(paymentDate interface{}) *time.Time {
 
 func (s *SQLiteDB) convertDBInvoiceToModel(invoice db.Invoice) *models.Invoice {
 	return &models.Invoice{
-		ID:              invoice.ID,
-		ClientID:        invoice.ClientID,
-		InvoiceNumber:   invoice.InvoiceNumber,
-		PeriodType:      invoice.PeriodType,
-		PeriodStartDate: invoice.PeriodStartDate,
-		PeriodEndDate:   invoice.PeriodEndDate,
-		SubtotalAmount:  invoice.SubtotalAmount,
-		GstAmount:       invoice.GstAmount,
-		TotalAmount:     invoice.TotalAmount,
-		GeneratedDate:   invoice.GeneratedDate,
-		CreatedAt:       invoice.CreatedAt,
-		UpdatedAt:       invoice.UpdatedAt,
+		ID:                invoice.ID,
+		ClientID:          invoice.ClientID,
+		InvoiceNumber:     invoice.InvoiceNumber,
+		PeriodType:        invoice.PeriodType,
+		PeriodStartDate:   invoice.PeriodStartDate,
+		PeriodEndDate:     invoice.PeriodEndDate,
+		SubtotalAmount:    invoice.SubtotalAmount,
+		GstAmount:         invoice.GstAmount,
+		TotalAmount:       invoice.TotalAmount,
+		GeneratedDate:     invoice.GeneratedDate,
+		CreatedAt:         invoice.CreatedAt,
+		UpdatedAt:         invoice.UpdatedAt,
+		GroupBy:           invoice.GroupBy,
+		NeedsRegeneration: invoice.NeedsRegeneration,
+		Notes:             nullStringToPtr(invoice.Notes),
+		PaymentTerms:      nullStringToPtr(invoice.PaymentTerms),
+		PoNumber:          nullStringToPtr(invoice.PoNumber),
+		ProjectCode:       nullStringToPtr(invoice.ProjectCode),
+		Status:            invoice.Status,
+		StatusReason:      nullStringToPtr(invoice.StatusReason),
+		StatusChangedAt:   nullTimeToPtr(invoice.StatusChangedAt),
+		AmountWrittenOff:  invoice.AmountWrittenOff,
+		Rounding:          nullDecimalToPtr(invoice.Rounding),
 	}
 }
 
@@ -1028,21 +1903,34 @@ func (s *SQLiteDB) convertDBInvoiceRowToModel(invoice db.GetInvoiceByIDRow) *mod
 	paymentDate := convertPaymentDate(invoice.PaymentDate)
 
 	return &models.Invoice{
-		ID:              invoice.ID,
-		ClientID:        invoice.ClientID,
-		InvoiceNumber:   invoice.InvoiceNumber,
-		PeriodType:      invoice.PeriodType,
-		PeriodStartDate: invoice.PeriodStartDate,
-		PeriodEndDate:   invoice.PeriodEndDate,
-		SubtotalAmount:  invoice.SubtotalAmount,
-		GstAmount:       invoice.GstAmount,
-		TotalAmount:     invoice.TotalAmount,
-		GeneratedDate:   invoice.GeneratedDate,
-		AmountPaid:      decimal.NewFromFloat(invoice.AmountPaid),
-		PaymentDate:     paymentDate,
-		CreatedAt:       invoice.CreatedAt,
-		UpdatedAt:       invoice.UpdatedAt,
-		ClientName:      invoice.ClientName,
+		ID:                invoice.ID,
+		ClientID:          invoice.ClientID,
+		InvoiceNumber:     invoice.InvoiceNumber,
+		PeriodType:        invoice.PeriodType,
+		PeriodStartDate:   invoice.PeriodStartDate,
+		PeriodEndDate:     invoice.PeriodEndDate,
+		SubtotalAmount:    invoice.SubtotalAmount,
+		GstAmount:         invoice.GstAmount,
+		TotalAmount:       invoice.TotalAmount,
+		GeneratedDate:     invoice.GeneratedDate,
+		AmountPaid:        decimal.NewFromFloat(invoice.AmountPaid),
+		PaymentDate:       paymentDate,
+		AmountCredited:    decimal.NewFromFloat(invoice.AmountCredited),
+		AmountDiscounted:  decimal.NewFromFloat(invoice.AmountDiscounted),
+		CreatedAt:         invoice.CreatedAt,
+		UpdatedAt:         invoice.UpdatedAt,
+		GroupBy:           invoice.GroupBy,
+		NeedsRegeneration: invoice.NeedsRegeneration,
+		Notes:             nullStringToPtr(invoice.Notes),
+		PaymentTerms:      nullStringToPtr(invoice.PaymentTerms),
+		PoNumber:          nullStringToPtr(invoice.PoNumber),
+		ProjectCode:       nullStringToPtr(invoice.ProjectCode),
+		Status:            invoice.Status,
+		StatusReason:      nullStringToPtr(invoice.StatusReason),
+		StatusChangedAt:   nullTimeToPtr(invoice.StatusChangedAt),
+		AmountWrittenOff:  invoice.AmountWrittenOff,
+		Rounding:          nullDecimalToPtr(invoice.Rounding),
+		ClientName:        invoice.ClientName,
 	}
 }
 
@@ -1050,21 +1938,34 @@ func (s *SQLiteDB) convertDBInvoiceListRowToModel(invoice db.ListInvoicesRow) *m
 	paymentDate := convertPaymentDate(invoice.PaymentDate)
 
 	return &models.Invoice{
-		ID:              invoice.ID,
-		ClientID:        invoice.ClientID,
-		InvoiceNumber:   invoice.InvoiceNumber,
-		PeriodType:      invoice.PeriodType,
-		PeriodStartDate: invoice.PeriodStartDate,
-		PeriodEndDate:   invoice.PeriodEndDate,
-		SubtotalAmount:  invoice.SubtotalAmount,
-		GstAmount:       invoice.GstAmount,
-		TotalAmount:     invoice.TotalAmount,
-		GeneratedDate:   invoice.GeneratedDate,
-		AmountPaid:      decimal.NewFromFloat(invoice.AmountPaid),
-		PaymentDate:     paymentDate,
-		CreatedAt:       invoice.CreatedAt,
-		UpdatedAt:       invoice.UpdatedAt,
-		ClientName:      invoice.ClientName,
+		ID:                invoice.ID,
+		ClientID:          invoice.ClientID,
+		InvoiceNumber:     invoice.InvoiceNumber,
+		PeriodType:        invoice.PeriodType,
+		PeriodStartDate:   invoice.PeriodStartDate,
+		PeriodEndDate:     invoice.PeriodEndDate,
+		SubtotalAmount:    invoice.SubtotalAmount,
+		GstAmount:         invoice.GstAmount,
+		TotalAmount:       invoice.TotalAmount,
+		GeneratedDate:     invoice.GeneratedDate,
+		AmountPaid:        decimal.NewFromFloat(invoice.AmountPaid),
+		PaymentDate:       paymentDate,
+		AmountCredited:    decimal.NewFromFloat(invoice.AmountCredited),
+		AmountDiscounted:  decimal.NewFromFloat(invoice.AmountDiscounted),
+		CreatedAt:         invoice.CreatedAt,
+		UpdatedAt:         invoice.UpdatedAt,
+		GroupBy:           invoice.GroupBy,
+		NeedsRegeneration: invoice.NeedsRegeneration,
+		Notes:             nullStringToPtr(invoice.Notes),
+		PaymentTerms:      nullStringToPtr(invoice.PaymentTerms),
+		PoNumber:          nullStringToPtr(invoice.PoNumber),
+		ProjectCode:       nullStringToPtr(invoice.ProjectCode),
+		Status:            invoice.Status,
+		StatusReason:      nullStringToPtr(invoice.StatusReason),
+		StatusChangedAt:   nullTimeToPtr(invoice.StatusChangedAt),
+		AmountWrittenOff:  invoice.AmountWrittenOff,
+		Rounding:          nullDecimalToPtr(invoice.Rounding),
+		ClientName:        invoice.ClientName,
 	}
 }
 
@@ -1072,21 +1973,34 @@ func (s *SQLiteDB) convertDBInvoicesByClientRowToModel(invoice db.GetInvoicesByC
 	paymentDate := convertPaymentDate(invoice.PaymentDate)
 
 	return &models.Invoice{
-		ID:              invoice.ID,
-		ClientID:        invoice.ClientID,
-		InvoiceNumber:   invoice.InvoiceNumber,
-		PeriodType:      invoice.PeriodType,
-		PeriodStartDate: invoice.PeriodStartDate,
-		PeriodEndDate:   invoice.PeriodEndDate,
-		SubtotalAmount:  invoice.SubtotalAmount,
-		GstAmount:       invoice.GstAmount,
-		TotalAmount:     invoice.TotalAmount,
-		GeneratedDate:   invoice.GeneratedDate,
-		AmountPaid:      decimal.NewFromFloat(invoice.AmountPaid),
-		PaymentDate:     paymentDate,
-		CreatedAt:       invoice.CreatedAt,
-		UpdatedAt:       invoice.UpdatedAt,
-		ClientName:      invoice.ClientName,
+		ID:                invoice.ID,
+		ClientID:          invoice.ClientID,
+		InvoiceNumber:     invoice.InvoiceNumber,
+		PeriodType:        invoice.PeriodType,
+		PeriodStartDate:   invoice.PeriodStartDate,
+		PeriodEndDate:     invoice.PeriodEndDate,
+		SubtotalAmount:    invoice.SubtotalAmount,
+		GstAmount:         invoice.GstAmount,
+		TotalAmount:       invoice.TotalAmount,
+		GeneratedDate:     invoice.GeneratedDate,
+		AmountPaid:        decimal.NewFromFloat(invoice.AmountPaid),
+		PaymentDate:       paymentDate,
+		AmountCredited:    decimal.NewFromFloat(invoice.AmountCredited),
+		AmountDiscounted:  decimal.NewFromFloat(invoice.AmountDiscounted),
+		CreatedAt:         invoice.CreatedAt,
+		UpdatedAt:         invoice.UpdatedAt,
+		GroupBy:           invoice.GroupBy,
+		NeedsRegeneration: invoice.NeedsRegeneration,
+		Notes:             nullStringToPtr(invoice.Notes),
+		PaymentTerms:      nullStringToPtr(invoice.PaymentTerms),
+		PoNumber:          nullStringToPtr(invoice.PoNumber),
+		ProjectCode:       nullStringToPtr(invoice.ProjectCode),
+		Status:            invoice.Status,
+		StatusReason:      nullStringToPtr(invoice.StatusReason),
+		StatusChangedAt:   nullTimeToPtr(invoice.StatusChangedAt),
+		AmountWrittenOff:  invoice.AmountWrittenOff,
+		Rounding:          nullDecimalToPtr(invoice.Rounding),
+		ClientName:        invoice.ClientName,
 	}
 }
 
@@ -1094,21 +2008,34 @@ func (s *SQLiteDB) convertDBInvoicesByPeriodRowToModel(invoice db.GetInvoicesByP
 	paymentDate := convertPaymentDate(invoice.PaymentDate)
 
 	return &models.Invoice{
-		ID:              invoice.ID,
-		ClientID:        invoice.ClientID,
-		InvoiceNumber:   invoice.InvoiceNumber,
-		PeriodType:      invoice.PeriodType,
-		PeriodStartDate: invoice.PeriodStartDate,
-		PeriodEndDate:   invoice.PeriodEndDate,
-		SubtotalAmount:  invoice.SubtotalAmount,
-		GstAmount:       invoice.GstAmount,
-		TotalAmount:     invoice.TotalAmount,
-		GeneratedDate:   invoice.GeneratedDate,
-		AmountPaid:      decimal.NewFromFloat(invoice.AmountPaid),
-		PaymentDate:     paymentDate,
-		CreatedAt:       invoice.CreatedAt,
-		UpdatedAt:       invoice.UpdatedAt,
-		ClientName:      invoice.ClientName,
+		ID:                invoice.ID,
+		ClientID:          invoice.ClientID,
+		InvoiceNumber:     invoice.InvoiceNumber,
+		PeriodType:        invoice.PeriodType,
+		PeriodStartDate:   invoice.PeriodStartDate,
+		PeriodEndDate:     invoice.PeriodEndDate,
+		SubtotalAmount:    invoice.SubtotalAmount,
+		GstAmount:         invoice.GstAmount,
+		TotalAmount:       invoice.TotalAmount,
+		GeneratedDate:     invoice.GeneratedDate,
+		AmountPaid:        decimal.NewFromFloat(invoice.AmountPaid),
+		PaymentDate:       paymentDate,
+		AmountCredited:    decimal.NewFromFloat(invoice.AmountCredited),
+		AmountDiscounted:  decimal.NewFromFloat(invoice.AmountDiscounted),
+		CreatedAt:         invoice.CreatedAt,
+		UpdatedAt:         invoice.UpdatedAt,
+		GroupBy:           invoice.GroupBy,
+		NeedsRegeneration: invoice.NeedsRegeneration,
+		Notes:             nullStringToPtr(invoice.Notes),
+		PaymentTerms:      nullStringToPtr(invoice.PaymentTerms),
+		PoNumber:          nullStringToPtr(invoice.PoNumber),
+		ProjectCode:       nullStringToPtr(invoice.ProjectCode),
+		Status:            invoice.Status,
+		StatusReason:      nullStringToPtr(invoice.StatusReason),
+		StatusChangedAt:   nullTimeToPtr(invoice.StatusChangedAt),
+		AmountWrittenOff:  invoice.AmountWrittenOff,
+		Rounding:          nullDecimalToPtr(invoice.Rounding),
+		ClientName:        invoice.ClientName,
 	}
 }
 
@@ -1116,34 +2043,51 @@ func (s *SQLiteDB) convertDBInvoiceByNumberRowToModel(invoice db.GetInvoiceByNum
 	paymentDate := convertPaymentDate(invoice.PaymentDate)
 
 	return &models.Invoice{
-		ID:              invoice.ID,
-		ClientID:        invoice.ClientID,
-		InvoiceNumber:   invoice.InvoiceNumber,
-		PeriodType:      invoice.PeriodType,
-		PeriodStartDate: invoice.PeriodStartDate,
-		PeriodEndDate:   invoice.PeriodEndDate,
-		SubtotalAmount:  invoice.SubtotalAmount,
-		GstAmount:       invoice.GstAmount,
-		TotalAmount:     invoice.TotalAmount,
-		GeneratedDate:   invoice.GeneratedDate,
-		AmountPaid:      decimal.NewFromFloat(invoice.AmountPaid),
-		PaymentDate:     paymentDate,
-		CreatedAt:       invoice.CreatedAt,
-		UpdatedAt:       invoice.UpdatedAt,
-		ClientName:      invoice.ClientName,
+		ID:                invoice.ID,
+		ClientID:          invoice.ClientID,
+		InvoiceNumber:     invoice.InvoiceNumber,
+		PeriodType:        invoice.PeriodType,
+		PeriodStartDate:   invoice.PeriodStartDate,
+		PeriodEndDate:     invoice.PeriodEndDate,
+		SubtotalAmount:    invoice.SubtotalAmount,
+		GstAmount:         invoice.GstAmount,
+		TotalAmount:       invoice.TotalAmount,
+		GeneratedDate:     invoice.GeneratedDate,
+		AmountPaid:        decimal.NewFromFloat(invoice.AmountPaid),
+		PaymentDate:       paymentDate,
+		AmountCredited:    decimal.NewFromFloat(invoice.AmountCredited),
+		AmountDiscounted:  decimal.NewFromFloat(invoice.AmountDiscounted),
+		CreatedAt:         invoice.CreatedAt,
+		UpdatedAt:         invoice.UpdatedAt,
+		GroupBy:           invoice.GroupBy,
+		NeedsRegeneration: invoice.NeedsRegeneration,
+		Notes:             nullStringToPtr(invoice.Notes),
+		PaymentTerms:      nullStringToPtr(invoice.PaymentTerms),
+		PoNumber:          nullStringToPtr(invoice.PoNumber),
+		ProjectCode:       nullStringToPtr(invoice.ProjectCode),
+		Status:            invoice.Status,
+		StatusReason:      nullStringToPtr(invoice.StatusReason),
+		StatusChangedAt:   nullTimeToPtr(invoice.StatusChangedAt),
+		AmountWrittenOff:  invoice.AmountWrittenOff,
+		Rounding:          nullDecimalToPtr(invoice.Rounding),
+		ClientName:        invoice.ClientName,
 	}
 }
 
 // Expense operations
-func (s *SQLiteDB) CreateExpense(ctx context.Context, amount decimal.Decimal, expenseDate time.Time, reference *string, clientID *string, invoiceID *string, description *string) (*models.Expense, error) {
+func (s *SQLiteDB) CreateExpense(ctx context.Context, amount decimal.Decimal, expenseDate time.Time, reference *string, clientID *string, invoiceID *string, description *string, category *string, markupPercent *decimal.Decimal, billable bool, gstAmount *decimal.Decimal) (*models.Expense, error) {
 	expense, err := s.queries.CreateExpense(ctx, db.CreateExpenseParams{
-		ID:          models.NewUUID(),
-		Amount:      amount,
-		ExpenseDate: expenseDate,
-		Reference:   ptrToNullString(reference),
-		ClientID:    ptrToNullString(clientID),
-		InvoiceID:   ptrToNullString(invoiceID),
-		Description: ptrToNullString(description),
+		ID:            models.NewUUID(),
+		Amount:        amount,
+		ExpenseDate:   expenseDate,
+		Reference:     ptrToNullString(reference),
+		ClientID:      ptrToNullString(clientID),
+		InvoiceID:     ptrToNullString(invoiceID),
+		Description:   ptrToNullString(description),
+		Category:      ptrToNullString(category),
+		MarkupPercent: ptrToNullDecimal(markupPercent),
+		Billable:      billable,
+		GstAmount:     ptrToNullDecimal(gstAmount),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create expense: %w", err)
@@ -1227,7 +2171,7 @@ func (s *SQLiteDB) ListExpensesByClientAndDateRange(ctx context.Context, clientI
 	return result, nil
 }
 
-func (s *SQLiteDB) UpdateExpense(ctx context.Context, expenseID string, amount *decimal.Decimal, expenseDate *time.Time, reference *string, clientID *string, invoiceID *string, description *string) (*models.Expense, error) {
+func (s *SQLiteDB) UpdateExpense(ctx context.Context, expenseID string, amount *decimal.Decimal, expenseDate *time.Time, reference *string, clientID *string, invoiceID *string, description *string, category *string, markupPercent *decimal.Decimal, billable *bool, gstAmount *decimal.Decimal) (*models.Expense, error) {
 	// Get current expense to preserve existing values
 	current, err := s.GetExpenseByID(ctx, expenseID)
 	if err != nil {
@@ -1235,13 +2179,17 @@ func (s *SQLiteDB) UpdateExpense(ctx context.Context, expenseID string, amount *
 	}
 
 	updateParams := db.UpdateExpenseParams{
-		ID:          expenseID,
-		Amount:      current.Amount,
-		ExpenseDate: sql.NullTime{Time: current.ExpenseDate, Valid: true},
-		Reference:   ptrToNullString(current.Reference),
-		ClientID:    ptrToNullString(current.ClientID),
-		InvoiceID:   ptrToNullString(current.InvoiceID),
-		Description: ptrToNullString(current.Description),
+		ID:            expenseID,
+		Amount:        current.Amount,
+		ExpenseDate:   sql.NullTime{Time: current.ExpenseDate, Valid: true},
+		Reference:     ptrToNullString(current.Reference),
+		ClientID:      ptrToNullString(current.ClientID),
+		InvoiceID:     ptrToNullString(current.InvoiceID),
+		Description:   ptrToNullString(current.Description),
+		Category:      ptrToNullString(current.Category),
+		MarkupPercent: ptrToNullDecimal(current.MarkupPercent),
+		Billable:      current.Billable,
+		GstAmount:     ptrToNullDecimal(current.GstAmount),
 	}
 
 	if amount != nil {
@@ -1262,6 +2210,18 @@ func (s *SQLiteDB) UpdateExpense(ctx context.Context, expenseID string, amount *
 	if description != nil {
 		updateParams.Description = ptrToNullString(description)
 	}
+	if category != nil {
+		updateParams.Category = ptrToNullString(category)
+	}
+	if markupPercent != nil {
+		updateParams.MarkupPercent = ptrToNullDecimal(markupPercent)
+	}
+	if billable != nil {
+		updateParams.Billable = *billable
+	}
+	if gstAmount != nil {
+		updateParams.GstAmount = ptrToNullDecimal(gstAmount)
+	}
 
 	expense, err := s.queries.UpdateExpense(ctx, updateParams)
 	if err != nil {
@@ -1271,8 +2231,12 @@ func (s *SQLiteDB) UpdateExpense(ctx context.Context, expenseID string, amount *
 	return s.convertDBExpenseToModel(expense), nil
 }
 
+// DeleteExpense moves an expense to the trash
 func (s *SQLiteDB) DeleteExpense(ctx context.Context, expenseID string) error {
-	err := s.queries.DeleteExpense(ctx, expenseID)
+	err := s.queries.TrashExpense(ctx, db.TrashExpenseParams{
+		DeletedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID:        expenseID,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete expense: %w", err)
 	}
@@ -1344,16 +2308,461 @@ func (s *SQLiteDB) ClearExpenseInvoiceIDs(ctx context.Context, invoiceID string)
 	return nil
 }
 
+func (s *SQLiteDB) CreateDraftExpense(ctx context.Context, clientID string, amount decimal.Decimal, expenseDate time.Time, reference *string, receiptPath string) (*models.Expense, error) {
+	expense, err := s.queries.CreateDraftExpense(ctx, db.CreateDraftExpenseParams{
+		ID:          models.NewUUID(),
+		Amount:      amount,
+		ExpenseDate: expenseDate,
+		Reference:   ptrToNullString(reference),
+		ClientID:    sql.NullString{String: clientID, Valid: true},
+		ReceiptPath: sql.NullString{String: receiptPath, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create draft expense: %w", err)
+	}
+
+	return s.convertDBExpenseToModel(expense), nil
+}
+
+func (s *SQLiteDB) CreateMileageExpense(ctx context.Context, clientID string, amount, distanceKm, ratePerKm decimal.Decimal, expenseDate time.Time, reference *string, description *string, billable bool) (*models.Expense, error) {
+	expense, err := s.queries.CreateMileageExpense(ctx, db.CreateMileageExpenseParams{
+		ID:          models.NewUUID(),
+		Amount:      amount,
+		ExpenseDate: expenseDate,
+		Reference:   ptrToNullString(reference),
+		ClientID:    sql.NullString{String: clientID, Valid: true},
+		Description: ptrToNullString(description),
+		Billable:    billable,
+		DistanceKm:  decimal.NullDecimal{Decimal: distanceKm, Valid: true},
+		RatePerKm:   decimal.NullDecimal{Decimal: ratePerKm, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mileage expense: %w", err)
+	}
+
+	return s.convertDBExpenseToModel(expense), nil
+}
+
+func (s *SQLiteDB) GetExpenseByReceiptPath(ctx context.Context, receiptPath string) (*models.Expense, error) {
+	expense, err := s.queries.GetExpenseByReceiptPath(ctx, sql.NullString{String: receiptPath, Valid: true})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get expense by receipt path: %w", err)
+	}
+
+	return s.convertDBExpenseToModel(expense), nil
+}
+
+func (s *SQLiteDB) ListDraftExpenses(ctx context.Context) ([]*models.Expense, error) {
+	expenses, err := s.queries.ListDraftExpenses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list draft expenses: %w", err)
+	}
+
+	result := make([]*models.Expense, len(expenses))
+	for i, expense := range expenses {
+		result[i] = s.convertDBExpenseToModel(expense)
+	}
+
+	return result, nil
+}
+
+func (s *SQLiteDB) ConfirmExpense(ctx context.Context, expenseID string, amount decimal.Decimal) (*models.Expense, error) {
+	expense, err := s.queries.ConfirmExpense(ctx, db.ConfirmExpenseParams{
+		ID:     expenseID,
+		Amount: amount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm expense: %w", err)
+	}
+
+	return s.convertDBExpenseToModel(expense), nil
+}
+
+// CreateExpenses creates expenses in a single transaction, so either every expense is created or none are
+func (s *SQLiteDB) CreateExpenses(ctx context.Context, expenses []*models.Expense) ([]*models.Expense, error) {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	created := make([]*models.Expense, 0, len(expenses))
+	for _, expense := range expenses {
+		result, err := qtx.CreateExpense(ctx, db.CreateExpenseParams{
+			ID:            models.NewUUID(),
+			Amount:        expense.Amount,
+			ExpenseDate:   expense.ExpenseDate,
+			Reference:     ptrToNullString(expense.Reference),
+			ClientID:      ptrToNullString(expense.ClientID),
+			InvoiceID:     ptrToNullString(expense.InvoiceID),
+			Description:   ptrToNullString(expense.Description),
+			Category:      ptrToNullString(expense.Category),
+			MarkupPercent: ptrToNullDecimal(expense.MarkupPercent),
+			Billable:      expense.Billable,
+			GstAmount:     ptrToNullDecimal(expense.GstAmount),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create expense: %w", err)
+		}
+		created = append(created, s.convertDBExpenseToModel(result))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit expenses: %w", err)
+	}
+	return created, nil
+}
+
 func (s *SQLiteDB) convertDBExpenseToModel(expense db.Expense) *models.Expense {
 	return &models.Expense{
-		ID:          expense.ID,
-		Amount:      expense.Amount,
-		ExpenseDate: expense.ExpenseDate,
-		Reference:   nullStringToPtr(expense.Reference),
-		ClientID:    nullStringToPtr(expense.ClientID),
-		InvoiceID:   nullStringToPtr(expense.InvoiceID),
-		Description: nullStringToPtr(expense.Description),
-		CreatedAt:   expense.CreatedAt,
-		UpdatedAt:   expense.UpdatedAt,
+		ID:            expense.ID,
+		Amount:        expense.Amount,
+		ExpenseDate:   expense.ExpenseDate,
+		Reference:     nullStringToPtr(expense.Reference),
+		ClientID:      nullStringToPtr(expense.ClientID),
+		InvoiceID:     nullStringToPtr(expense.InvoiceID),
+		Description:   nullStringToPtr(expense.Description),
+		Category:      nullStringToPtr(expense.Category),
+		MarkupPercent: nullDecimalToPtr(expense.MarkupPercent),
+		Billable:      expense.Billable,
+		Draft:         expense.Draft,
+		ReceiptPath:   nullStringToPtr(expense.ReceiptPath),
+		DistanceKm:    nullDecimalToPtr(expense.DistanceKm),
+		RatePerKm:     nullDecimalToPtr(expense.RatePerKm),
+		GstAmount:     nullDecimalToPtr(expense.GstAmount),
+		DeletedAt:     nullTimeToPtr(expense.DeletedAt),
+		CreatedAt:     expense.CreatedAt,
+		UpdatedAt:     expense.UpdatedAt,
+	}
+}
+
+func (s *SQLiteDB) CountActiveSessions(ctx context.Context) (int64, error) {
+	count, err := s.queries.CountActiveSessions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count active sessions: %w", err)
+	}
+	return count, nil
+}
+
+func (s *SQLiteDB) GetSessionsWithMissingInvoice(ctx context.Context) ([]*models.WorkSession, error) {
+	sessions, err := s.queries.GetSessionsWithMissingInvoice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions with missing invoice: %w", err)
+	}
+
+	result := make([]*models.WorkSession, len(sessions))
+	for i, session := range sessions {
+		sessionRate := decimal.Zero
+		if session.HourlyRate.Valid {
+			sessionRate = session.HourlyRate.Decimal
+		}
+
+		result[i] = &models.WorkSession{
+			ID:              session.ID,
+			ClientID:        session.ClientID,
+			StartTime:       session.StartTime,
+			EndTime:         nullTimeToPtr(session.EndTime),
+			Description:     nullStringToPtr(session.Description),
+			HourlyRate:      &sessionRate,
+			FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
+			OutsideGit:      nullStringToPtr(session.OutsideGit),
+			Hostname:        nullStringToPtr(session.Hostname),
+			OS:              nullStringToPtr(session.Os),
+			GitBranch:       nullStringToPtr(session.GitBranch),
+			BreakSeconds:    session.BreakSeconds,
+			UserID:          nullStringToPtr(session.UserID),
+			InvoiceID:       nullStringToPtr(session.InvoiceID),
+			IncludesGst:     session.IncludesGst,
+			CreatedAt:       session.CreatedAt,
+			UpdatedAt:       session.UpdatedAt,
+			ClientName:      session.ClientName,
+		}
+	}
+
+	return result, nil
+}
+
+func (s *SQLiteDB) GetExpensesWithMissingInvoice(ctx context.Context) ([]*models.Expense, error) {
+	expenses, err := s.queries.GetExpensesWithMissingInvoice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expenses with missing invoice: %w", err)
+	}
+
+	result := make([]*models.Expense, len(expenses))
+	for i, expense := range expenses {
+		result[i] = s.convertDBExpenseToModel(expense)
+	}
+
+	return result, nil
+}
+
+// ClearMissingInvoiceIDs unlinks sessions and expenses from invoices that no longer exist
+func (s *SQLiteDB) ClearMissingInvoiceIDs(ctx context.Context) error {
+	if err := s.queries.ClearMissingSessionInvoiceIDs(ctx); err != nil {
+		return fmt.Errorf("failed to clear missing session invoice IDs: %w", err)
+	}
+	if err := s.queries.ClearMissingExpenseInvoiceIDs(ctx); err != nil {
+		return fmt.Errorf("failed to clear missing expense invoice IDs: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) CreateCreditNote(ctx context.Context, invoiceID, creditNoteNumber string, amount, gstAmount decimal.Decimal, reason string, issuedDate time.Time) (*models.CreditNote, error) {
+	creditNote, err := s.queries.CreateCreditNote(ctx, db.CreateCreditNoteParams{
+		ID:               models.NewUUID(),
+		InvoiceID:        invoiceID,
+		CreditNoteNumber: creditNoteNumber,
+		Amount:           amount,
+		GstAmount:        gstAmount,
+		Reason:           reason,
+		IssuedDate:       issuedDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credit note: %w", err)
+	}
+
+	return s.convertDBCreditNoteToModel(creditNote), nil
+}
+
+func (s *SQLiteDB) GetCreditNotesByInvoiceID(ctx context.Context, invoiceID string) ([]*models.CreditNote, error) {
+	creditNotes, err := s.queries.GetCreditNotesByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit notes for invoice: %w", err)
+	}
+
+	return s.convertDBCreditNotesToModel(creditNotes), nil
+}
+
+func (s *SQLiteDB) ListCreditNotes(ctx context.Context) ([]*models.CreditNote, error) {
+	creditNotes, err := s.queries.ListCreditNotes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit notes: %w", err)
+	}
+
+	return s.convertDBCreditNotesToModel(creditNotes), nil
+}
+
+func (s *SQLiteDB) convertDBCreditNotesToModel(creditNotes []db.CreditNote) []*models.CreditNote {
+	result := make([]*models.CreditNote, len(creditNotes))
+	for i, creditNote := range creditNotes {
+		result[i] = s.convertDBCreditNoteToModel(creditNote)
+	}
+	return result
+}
+
+func (s *SQLiteDB) convertDBCreditNoteToModel(creditNote db.CreditNote) *models.CreditNote {
+	return &models.CreditNote{
+		ID:               creditNote.ID,
+		InvoiceID:        creditNote.InvoiceID,
+		CreditNoteNumber: creditNote.CreditNoteNumber,
+		Amount:           creditNote.Amount,
+		GstAmount:        creditNote.GstAmount,
+		Reason:           creditNote.Reason,
+		IssuedDate:       creditNote.IssuedDate,
+		CreatedAt:        creditNote.CreatedAt,
+		UpdatedAt:        creditNote.UpdatedAt,
+	}
+}
+
+func (s *SQLiteDB) CreateSessionTemplate(ctx context.Context, name, clientID string, description *string, durationMinutes *int64) (*models.SessionTemplate, error) {
+	template, err := s.queries.CreateSessionTemplate(ctx, db.CreateSessionTemplateParams{
+		ID:              models.NewUUID(),
+		Name:            name,
+		ClientID:        clientID,
+		Description:     ptrToNullString(description),
+		DurationMinutes: ptrToNullInt64(durationMinutes),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session template: %w", err)
+	}
+
+	return s.convertDBSessionTemplateToModel(template), nil
+}
+
+func (s *SQLiteDB) GetSessionTemplateByName(ctx context.Context, name string) (*models.SessionTemplate, error) {
+	template, err := s.queries.GetSessionTemplateByName(ctx, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get session template by name: %w", err)
+	}
+
+	return s.convertDBSessionTemplateToModel(template), nil
+}
+
+func (s *SQLiteDB) ListSessionTemplates(ctx context.Context) ([]*models.SessionTemplate, error) {
+	templates, err := s.queries.ListSessionTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session templates: %w", err)
+	}
+
+	result := make([]*models.SessionTemplate, len(templates))
+	for i, template := range templates {
+		result[i] = s.convertDBSessionTemplateToModel(template)
+	}
+
+	return result, nil
+}
+
+func (s *SQLiteDB) DeleteSessionTemplate(ctx context.Context, templateID string) error {
+	if err := s.queries.DeleteSessionTemplate(ctx, templateID); err != nil {
+		return fmt.Errorf("failed to delete session template: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) convertDBSessionTemplateToModel(template db.SessionTemplate) *models.SessionTemplate {
+	return &models.SessionTemplate{
+		ID:              template.ID,
+		Name:            template.Name,
+		ClientID:        template.ClientID,
+		Description:     nullStringToPtr(template.Description),
+		DurationMinutes: nullInt64ToPtr(template.DurationMinutes),
+		CreatedAt:       template.CreatedAt,
+		UpdatedAt:       template.UpdatedAt,
+	}
+}
+
+func (s *SQLiteDB) CreateUser(ctx context.Context, name string, costRate *decimal.Decimal) (*models.User, error) {
+	user, err := s.queries.CreateUser(ctx, db.CreateUserParams{
+		ID:       models.NewUUID(),
+		Name:     name,
+		CostRate: ptrToNullDecimal(costRate),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return convertDBUserToModel(user), nil
+}
+
+func (s *SQLiteDB) GetUserByName(ctx context.Context, name string) (*models.User, error) {
+	user, err := s.queries.GetUserByName(ctx, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get user by name: %w", err)
+	}
+
+	return convertDBUserToModel(user), nil
+}
+
+func (s *SQLiteDB) ListUsers(ctx context.Context) ([]*models.User, error) {
+	users, err := s.queries.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	result := make([]*models.User, len(users))
+	for i, user := range users {
+		result[i] = convertDBUserToModel(user)
+	}
+
+	return result, nil
+}
+
+func (s *SQLiteDB) UpdateUser(ctx context.Context, userID string, name *string, costRate *decimal.Decimal) (*models.User, error) {
+	user, err := s.queries.UpdateUser(ctx, db.UpdateUserParams{
+		ID:       userID,
+		Name:     ptrToNullString(name),
+		CostRate: ptrToNullDecimal(costRate),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return convertDBUserToModel(user), nil
+}
+
+func convertDBUserToModel(user db.User) *models.User {
+	return &models.User{
+		ID:        user.ID,
+		Name:      user.Name,
+		CostRate:  nullDecimalToPtr(user.CostRate),
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+func (s *SQLiteDB) CreateLeave(ctx context.Context, leaveType string, startDate, endDate time.Time, note *string) (*models.Leave, error) {
+	leave, err := s.queries.CreateLeave(ctx, db.CreateLeaveParams{
+		ID:        models.NewUUID(),
+		LeaveType: leaveType,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Note:      ptrToNullString(note),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create leave: %w", err)
+	}
+
+	return convertDBLeaveToModel(leave), nil
+}
+
+func (s *SQLiteDB) GetLeaveByID(ctx context.Context, leaveID string) (*models.Leave, error) {
+	leave, err := s.queries.GetLeaveByID(ctx, leaveID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get leave: %w", err)
+	}
+
+	return convertDBLeaveToModel(leave), nil
+}
+
+func (s *SQLiteDB) ListLeave(ctx context.Context) ([]*models.Leave, error) {
+	leave, err := s.queries.ListLeave(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leave: %w", err)
+	}
+
+	result := make([]*models.Leave, len(leave))
+	for i, l := range leave {
+		result[i] = convertDBLeaveToModel(l)
+	}
+
+	return result, nil
+}
+
+func (s *SQLiteDB) ListLeaveInRange(ctx context.Context, fromDate, toDate time.Time) ([]*models.Leave, error) {
+	leave, err := s.queries.ListLeaveInRange(ctx, db.ListLeaveInRangeParams{
+		FromDate: fromDate,
+		ToDate:   toDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leave by date range: %w", err)
+	}
+
+	result := make([]*models.Leave, len(leave))
+	for i, l := range leave {
+		result[i] = convertDBLeaveToModel(l)
+	}
+
+	return result, nil
+}
+
+func (s *SQLiteDB) DeleteLeave(ctx context.Context, leaveID string) error {
+	if err := s.queries.DeleteLeave(ctx, leaveID); err != nil {
+		return fmt.Errorf("failed to delete leave: %w", err)
+	}
+	return nil
+}
+
+func convertDBLeaveToModel(leave db.Leave) *models.Leave {
+	return &models.Leave{
+		ID:        leave.ID,
+		Type:      leave.LeaveType,
+		StartDate: leave.StartDate,
+		EndDate:   leave.EndDate,
+		Note:      nullStringToPtr(leave.Note),
+		CreatedAt: leave.CreatedAt,
+		UpdatedAt: leave.UpdatedAt,
 	}
 }
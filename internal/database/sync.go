@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrNoReplica is returned when syncing a database that isn't an embedded replica
+var ErrNoReplica = errors.New("database is not an embedded replica")
+
+// ErrSyncConflict is wrapped by a Syncer's error when the primary rejected local changes
+var ErrSyncConflict = errors.New("sync conflict")
+
+// Syncer pulls changes from the primary database into an embedded replica and pushes local writes to it
+type Syncer interface {
+	Sync(ctx context.Context) (SyncResult, error)
+}
+
+// SyncResult is what one sync did
+type SyncResult struct {
+	FrameNo       int // the replica's position in the primary's log after syncing
+	FramesSynced  int
+	PendingFrames int // local writes still to be pushed
+}
+
+// SyncConflict is a sync the primary rejected
+type SyncConflict struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// SyncStatus is kept beside the replica, so it can be shown by later runs
+type SyncStatus struct {
+	LastSync      time.Time      `json:"last_sync"`
+	LastAttempt   time.Time      `json:"last_attempt"`
+	LastError     string         `json:"last_error,omitempty"`
+	Failures      int            `json:"failures"` // failed syncs since the last one that worked
+	NextAttempt   time.Time      `json:"next_attempt"`
+	FrameNo       int            `json:"frame_no"`
+	PendingFrames int            `json:"pending_frames"`
+	Conflicts     []SyncConflict `json:"conflicts,omitempty"`
+}
+
+const (
+	// minSyncBackoff is the wait after the first failed sync, doubled for each failure after it
+	minSyncBackoff = 5 * time.Second
+	// maxSyncBackoff caps the wait between syncs while offline
+	maxSyncBackoff = 5 * time.Minute
+	// maxSyncConflicts is how many conflicts are kept for the status
+	maxSyncConflicts = 20
+)
+
+// replicaSync syncs an embedded replica in the background, every interval while the primary is reachable
+// and backing off exponentially while it isn't
+type replicaSync struct {
+	syncer    Syncer
+	statePath string
+	interval  time.Duration
+
+	mu     sync.Mutex
+	status SyncStatus
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newReplicaSync(syncer Syncer, statePath string, interval time.Duration) *replicaSync {
+	r := &replicaSync{
+		syncer:    syncer,
+		statePath: statePath,
+		interval:  interval,
+	}
+	if data, err := os.ReadFile(statePath); err == nil {
+		_ = json.Unmarshal(data, &r.status)
+	}
+	return r
+}
+
+// start syncs in the background until close is called
+func (r *replicaSync) start() {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		for {
+			r.mu.Lock()
+			wait := time.Until(r.status.NextAttempt)
+			r.mu.Unlock()
+			if wait < 0 {
+				wait = 0
+			}
+			select {
+			case <-r.stop:
+				return
+			case <-time.After(wait):
+				_ = r.sync(context.Background())
+			}
+		}
+	}()
+}
+
+// sync runs one sync and records how it went, scheduling the next attempt
+func (r *replicaSync) sync(ctx context.Context) error {
+	result, err := r.syncer.Sync(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.status.LastAttempt = now
+	if err != nil {
+		r.status.Failures++
+		r.status.LastError = err.Error()
+		r.status.NextAttempt = now.Add(syncBackoff(r.status.Failures))
+		if errors.Is(err, ErrSyncConflict) {
+			r.status.Conflicts = append(r.status.Conflicts, SyncConflict{Time: now, Message: err.Error()})
+			if len(r.status.Conflicts) > maxSyncConflicts {
+				r.status.Conflicts = r.status.Conflicts[len(r.status.Conflicts)-maxSyncConflicts:]
+			}
+		}
+	} else {
+		r.status.Failures = 0
+		r.status.LastError = ""
+		r.status.LastSync = now
+		r.status.NextAttempt = now.Add(r.interval)
+		r.status.FrameNo = result.FrameNo
+		r.status.PendingFrames = result.PendingFrames
+	}
+	if saveErr := r.save(); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to sync replica: %w", err)
+	}
+	return nil
+}
+
+// close stops background syncing and syncs once more, so writes made by this run reach the primary
+func (r *replicaSync) close(ctx context.Context) error {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+	}
+	return r.sync(ctx)
+}
+
+func (r *replicaSync) snapshot() SyncStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := r.status
+	status.Conflicts = append([]SyncConflict(nil), r.status.Conflicts...)
+	return status
+}
+
+func (r *replicaSync) save() error {
+	data, err := json.MarshalIndent(r.status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode sync status: %w", err)
+	}
+	if err := os.WriteFile(r.statePath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save sync status: %w", err)
+	}
+	return nil
+}
+
+// syncBackoff is how long to wait after failures failed syncs in a row
+func syncBackoff(failures int) time.Duration {
+	backoff := minSyncBackoff
+	for i := 1; i < failures && backoff < maxSyncBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxSyncBackoff)
+}
+
+// Sync syncs the embedded replica with its primary now, rather than waiting for the next background sync
+func (s *SQLiteDB) Sync(ctx context.Context) (*SyncStatus, error) {
+	if s.replica == nil {
+		return nil, ErrNoReplica
+	}
+	err := s.replica.sync(ctx)
+	status := s.replica.snapshot()
+	return &status, err
+}
+
+// SyncStatus returns how syncing the embedded replica has gone
+func (s *SQLiteDB) SyncStatus(ctx context.Context) (*SyncStatus, error) {
+	if s.replica == nil {
+		return nil, ErrNoReplica
+	}
+	status := s.replica.snapshot()
+	return &status, nil
+}
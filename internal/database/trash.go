@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// ListTrashedSessions lists the sessions in the trash, most recently deleted first
+func (s *SQLiteDB) ListTrashedSessions(ctx context.Context) ([]*models.WorkSession, error) {
+	sessions, err := s.queries.ListTrashedSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trashed sessions: %w", err)
+	}
+
+	result := make([]*models.WorkSession, len(sessions))
+	for i, session := range sessions {
+		sessionRate := decimal.Zero
+		if session.HourlyRate.Valid {
+			sessionRate = session.HourlyRate.Decimal
+		}
+
+		result[i] = &models.WorkSession{
+			ID:              session.ID,
+			ClientID:        session.ClientID,
+			StartTime:       session.StartTime,
+			EndTime:         nullTimeToPtr(session.EndTime),
+			Description:     nullStringToPtr(session.Description),
+			HourlyRate:      &sessionRate,
+			FullWorkSummary: nullStringToPtr(session.FullWorkSummary),
+			OutsideGit:      nullStringToPtr(session.OutsideGit),
+			InvoiceID:       nullStringToPtr(session.InvoiceID),
+			IncludesGst:     session.IncludesGst,
+			Hostname:        nullStringToPtr(session.Hostname),
+			OS:              nullStringToPtr(session.Os),
+			GitBranch:       nullStringToPtr(session.GitBranch),
+			BreakSeconds:    session.BreakSeconds,
+			UserID:          nullStringToPtr(session.UserID),
+			DeletedAt:       nullTimeToPtr(session.DeletedAt),
+			CreatedAt:       session.CreatedAt,
+			UpdatedAt:       session.UpdatedAt,
+			ClientName:      session.ClientName,
+		}
+	}
+
+	return result, nil
+}
+
+// ListTrashedExpenses lists the expenses in the trash, most recently deleted first
+func (s *SQLiteDB) ListTrashedExpenses(ctx context.Context) ([]*models.Expense, error) {
+	expenses, err := s.queries.ListTrashedExpenses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trashed expenses: %w", err)
+	}
+
+	result := make([]*models.Expense, len(expenses))
+	for i, expense := range expenses {
+		result[i] = s.convertDBExpenseToModel(expense)
+	}
+	return result, nil
+}
+
+// RestoreSession takes a session out of the trash. If it was on an invoice that still exists, the invoice is
+// flagged as needing regeneration, since the session is back on it.
+func (s *SQLiteDB) RestoreSession(ctx context.Context, sessionID string) (*models.WorkSession, error) {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	session, err := qtx.RestoreSession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore session: %w", err)
+	}
+	if session.InvoiceID.Valid {
+		if err := guardInvoices(ctx, qtx, []string{session.InvoiceID.String}, true); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit session restore: %w", err)
+	}
+
+	result := s.convertDBSessionToModel(session)
+	result.InvoiceID = nullStringToPtr(session.InvoiceID)
+	return result, nil
+}
+
+// RestoreExpense takes an expense out of the trash
+func (s *SQLiteDB) RestoreExpense(ctx context.Context, expenseID string) (*models.Expense, error) {
+	expense, err := s.queries.RestoreExpense(ctx, expenseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore expense: %w", err)
+	}
+	return s.convertDBExpenseToModel(expense), nil
+}
+
+// PurgeTrash permanently deletes the sessions and expenses moved to the trash before a time, along with the
+// sessions' breaks and repos, returning how many of each were deleted
+func (s *SQLiteDB) PurgeTrash(ctx context.Context, before time.Time) (int64, int64, error) {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	// Deletion times are kept in UTC, so they compare as text
+	cutoff := sql.NullTime{Time: before.UTC(), Valid: true}
+	sessions, err := qtx.PurgeSessions(ctx, cutoff)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to purge sessions: %w", err)
+	}
+	expenses, err := qtx.PurgeExpenses(ctx, cutoff)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to purge expenses: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit purge: %w", err)
+	}
+	return sessions, expenses, nil
+}
@@ -8,6 +8,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
@@ -15,7 +16,7 @@ import (
 const createClient = `-- name: CreateClient :one
 INSERT INTO clients (id, name, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, abn, dir, retainer_amount, retainer_hours, retainer_basis)
 VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13, ?14, ?15, ?16, ?17, ?18)
-RETURNING id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis
+RETURNING id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis, currency, locale, source, work_log, retainer_start_date, early_discount_percent, early_discount_days, invoice_notes, payment_terms, po_number, project_code, invoice_rounding, tax_treatment, logo_path, brand_colour, analysis_max_commits, analysis_ignore, analysis_detail, analysis_prompt
 `
 
 type CreateClientParams struct {
@@ -82,12 +83,31 @@ func (q *Queries) CreateClient(ctx context.Context, arg CreateClientParams) (Cli
 		&i.RetainerAmount,
 		&i.RetainerHours,
 		&i.RetainerBasis,
+		&i.Currency,
+		&i.Locale,
+		&i.Source,
+		&i.WorkLog,
+		&i.RetainerStartDate,
+		&i.EarlyDiscountPercent,
+		&i.EarlyDiscountDays,
+		&i.InvoiceNotes,
+		&i.PaymentTerms,
+		&i.PoNumber,
+		&i.ProjectCode,
+		&i.InvoiceRounding,
+		&i.TaxTreatment,
+		&i.LogoPath,
+		&i.BrandColour,
+		&i.AnalysisMaxCommits,
+		&i.AnalysisIgnore,
+		&i.AnalysisDetail,
+		&i.AnalysisPrompt,
 	)
 	return i, err
 }
 
 const getClientByID = `-- name: GetClientByID :one
-SELECT id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis FROM clients
+SELECT id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis, currency, locale, source, work_log, retainer_start_date, early_discount_percent, early_discount_days, invoice_notes, payment_terms, po_number, project_code, invoice_rounding, tax_treatment, logo_path, brand_colour, analysis_max_commits, analysis_ignore, analysis_detail, analysis_prompt FROM clients
 WHERE id = ?1
 `
 
@@ -115,12 +135,86 @@ func (q *Queries) GetClientByID(ctx context.Context, id string) (Client, error)
 		&i.RetainerAmount,
 		&i.RetainerHours,
 		&i.RetainerBasis,
+		&i.Currency,
+		&i.Locale,
+		&i.Source,
+		&i.WorkLog,
+		&i.RetainerStartDate,
+		&i.EarlyDiscountPercent,
+		&i.EarlyDiscountDays,
+		&i.InvoiceNotes,
+		&i.PaymentTerms,
+		&i.PoNumber,
+		&i.ProjectCode,
+		&i.InvoiceRounding,
+		&i.TaxTreatment,
+		&i.LogoPath,
+		&i.BrandColour,
+		&i.AnalysisMaxCommits,
+		&i.AnalysisIgnore,
+		&i.AnalysisDetail,
+		&i.AnalysisPrompt,
 	)
 	return i, err
 }
 
+const getClientHoursSince = `-- name: GetClientHoursSince :one
+SELECT CAST(COALESCE(SUM(ROUND(((julianday(end_time) - julianday(start_time)) * 86400 - break_seconds) / 60.0) / 60.0), 0) AS REAL) AS hours
+FROM sessions
+WHERE client_id = ?1 AND end_time IS NOT NULL AND deleted_at IS NULL AND start_time >= ?2
+`
+
+type GetClientHoursSinceParams struct {
+	ClientID string    `db:"client_id" json:"client_id"`
+	Since    time.Time `db:"since" json:"since"`
+}
+
+func (q *Queries) GetClientHoursSince(ctx context.Context, arg GetClientHoursSinceParams) (float64, error) {
+	row := q.db.QueryRowContext(ctx, getClientHoursSince, arg.ClientID, arg.Since)
+	var hours float64
+	err := row.Scan(&hours)
+	return hours, err
+}
+
+const getClientInvoiceStats = `-- name: GetClientInvoiceStats :one
+SELECT
+    COUNT(*) AS invoice_count,
+    CAST(COALESCE(SUM(total_amount), 0) AS REAL) AS total_invoiced,
+    CAST(COALESCE(SUM(MAX(total_amount - amount_paid - amount_credited - amount_discounted, 0)), 0) AS REAL) AS outstanding
+FROM v_invoices
+WHERE client_id = ?1
+`
+
+type GetClientInvoiceStatsRow struct {
+	InvoiceCount  int64   `db:"invoice_count" json:"invoice_count"`
+	TotalInvoiced float64 `db:"total_invoiced" json:"total_invoiced"`
+	Outstanding   float64 `db:"outstanding" json:"outstanding"`
+}
+
+func (q *Queries) GetClientInvoiceStats(ctx context.Context, clientID string) (GetClientInvoiceStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getClientInvoiceStats, clientID)
+	var i GetClientInvoiceStatsRow
+	err := row.Scan(&i.InvoiceCount, &i.TotalInvoiced, &i.Outstanding)
+	return i, err
+}
+
+const getClientLastSessionStart = `-- name: GetClientLastSessionStart :one
+SELECT start_time
+FROM sessions
+WHERE client_id = ?1 AND deleted_at IS NULL
+ORDER BY start_time DESC
+LIMIT 1
+`
+
+func (q *Queries) GetClientLastSessionStart(ctx context.Context, clientID string) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getClientLastSessionStart, clientID)
+	var start_time time.Time
+	err := row.Scan(&start_time)
+	return start_time, err
+}
+
 const getClientByName = `-- name: GetClientByName :one
-SELECT id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis FROM clients
+SELECT id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis, currency, locale, source, work_log, retainer_start_date, early_discount_percent, early_discount_days, invoice_notes, payment_terms, po_number, project_code, invoice_rounding, tax_treatment, logo_path, brand_colour, analysis_max_commits, analysis_ignore, analysis_detail, analysis_prompt FROM clients
 WHERE name = ?1
 `
 
@@ -148,12 +242,51 @@ func (q *Queries) GetClientByName(ctx context.Context, name string) (Client, err
 		&i.RetainerAmount,
 		&i.RetainerHours,
 		&i.RetainerBasis,
+		&i.Currency,
+		&i.Locale,
+		&i.Source,
+		&i.WorkLog,
+		&i.RetainerStartDate,
+		&i.EarlyDiscountPercent,
+		&i.EarlyDiscountDays,
+		&i.InvoiceNotes,
+		&i.PaymentTerms,
+		&i.PoNumber,
+		&i.ProjectCode,
+		&i.InvoiceRounding,
+		&i.TaxTreatment,
+		&i.LogoPath,
+		&i.BrandColour,
+		&i.AnalysisMaxCommits,
+		&i.AnalysisIgnore,
+		&i.AnalysisDetail,
+		&i.AnalysisPrompt,
 	)
 	return i, err
 }
 
+const getClientSessionStats = `-- name: GetClientSessionStats :one
+SELECT
+    COUNT(*) AS session_count,
+    CAST(COALESCE(SUM(ROUND(((julianday(end_time) - julianday(start_time)) * 86400 - break_seconds) / 60.0) / 60.0), 0) AS REAL) AS total_hours
+FROM sessions
+WHERE client_id = ?1 AND end_time IS NOT NULL AND deleted_at IS NULL
+`
+
+type GetClientSessionStatsRow struct {
+	SessionCount int64   `db:"session_count" json:"session_count"`
+	TotalHours   float64 `db:"total_hours" json:"total_hours"`
+}
+
+func (q *Queries) GetClientSessionStats(ctx context.Context, clientID string) (GetClientSessionStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getClientSessionStats, clientID)
+	var i GetClientSessionStatsRow
+	err := row.Scan(&i.SessionCount, &i.TotalHours)
+	return i, err
+}
+
 const getClientsWithDirectories = `-- name: GetClientsWithDirectories :many
-SELECT id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis FROM clients
+SELECT id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis, currency, locale, source, work_log, retainer_start_date, early_discount_percent, early_discount_days, invoice_notes, payment_terms, po_number, project_code, invoice_rounding, tax_treatment, logo_path, brand_colour, analysis_max_commits, analysis_ignore, analysis_detail, analysis_prompt FROM clients
 WHERE dir IS NOT NULL AND dir != ''
 ORDER BY name
 `
@@ -188,6 +321,25 @@ func (q *Queries) GetClientsWithDirectories(ctx context.Context) ([]Client, erro
 			&i.RetainerAmount,
 			&i.RetainerHours,
 			&i.RetainerBasis,
+			&i.Currency,
+			&i.Locale,
+			&i.Source,
+			&i.WorkLog,
+			&i.RetainerStartDate,
+			&i.EarlyDiscountPercent,
+			&i.EarlyDiscountDays,
+			&i.InvoiceNotes,
+			&i.PaymentTerms,
+			&i.PoNumber,
+			&i.ProjectCode,
+			&i.InvoiceRounding,
+			&i.TaxTreatment,
+			&i.LogoPath,
+			&i.BrandColour,
+			&i.AnalysisMaxCommits,
+			&i.AnalysisIgnore,
+			&i.AnalysisDetail,
+			&i.AnalysisPrompt,
 		); err != nil {
 			return nil, err
 		}
@@ -203,7 +355,7 @@ func (q *Queries) GetClientsWithDirectories(ctx context.Context) ([]Client, erro
 }
 
 const listClients = `-- name: ListClients :many
-SELECT id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis FROM clients
+SELECT id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis, currency, locale, source, work_log, retainer_start_date, early_discount_percent, early_discount_days, invoice_notes, payment_terms, po_number, project_code, invoice_rounding, tax_treatment, logo_path, brand_colour, analysis_max_commits, analysis_ignore, analysis_detail, analysis_prompt FROM clients
 ORDER BY name
 `
 
@@ -237,6 +389,25 @@ func (q *Queries) ListClients(ctx context.Context) ([]Client, error) {
 			&i.RetainerAmount,
 			&i.RetainerHours,
 			&i.RetainerBasis,
+			&i.Currency,
+			&i.Locale,
+			&i.Source,
+			&i.WorkLog,
+			&i.RetainerStartDate,
+			&i.EarlyDiscountPercent,
+			&i.EarlyDiscountDays,
+			&i.InvoiceNotes,
+			&i.PaymentTerms,
+			&i.PoNumber,
+			&i.ProjectCode,
+			&i.InvoiceRounding,
+			&i.TaxTreatment,
+			&i.LogoPath,
+			&i.BrandColour,
+			&i.AnalysisMaxCommits,
+			&i.AnalysisIgnore,
+			&i.AnalysisDetail,
+			&i.AnalysisPrompt,
 		); err != nil {
 			return nil, err
 		}
@@ -254,7 +425,7 @@ func (q *Queries) ListClients(ctx context.Context) ([]Client, error) {
 const updateClient = `-- name: UpdateClient :one
 UPDATE clients 
 SET 
-	hourly_rate = ?1,
+	hourly_rate = CASE WHEN ?1 IS NULL THEN hourly_rate ELSE ?1 END,
     company_name = ?2,
     contact_name = ?3,
     email = ?4,
@@ -269,29 +440,67 @@ SET
     dir = ?13,
     retainer_amount = ?14,
     retainer_hours = ?15,
-    retainer_basis = ?16
-WHERE id = ?17
-RETURNING id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis
+    retainer_basis = ?16,
+    currency = CASE WHEN ?17 IS NULL THEN currency ELSE NULLIF(?17, '') END,
+    locale = CASE WHEN ?18 IS NULL THEN locale ELSE NULLIF(?18, '') END,
+    source = CASE WHEN ?19 IS NULL THEN source ELSE NULLIF(?19, '') END,
+    work_log = CASE WHEN ?20 IS NULL THEN work_log ELSE NULLIF(?20, '') END,
+    retainer_start_date = CASE WHEN ?21 IS NULL THEN retainer_start_date ELSE ?21 END,
+    early_discount_percent = CASE WHEN ?22 IS NULL THEN early_discount_percent ELSE NULLIF(CAST(?22 AS REAL), 0) END,
+    early_discount_days = CASE WHEN ?23 IS NULL THEN early_discount_days ELSE NULLIF(?23, 0) END,
+    invoice_notes = CASE WHEN ?24 IS NULL THEN invoice_notes ELSE NULLIF(?24, '') END,
+    payment_terms = CASE WHEN ?25 IS NULL THEN payment_terms ELSE NULLIF(?25, '') END,
+    po_number = CASE WHEN ?26 IS NULL THEN po_number ELSE NULLIF(?26, '') END,
+    project_code = CASE WHEN ?27 IS NULL THEN project_code ELSE NULLIF(?27, '') END,
+    invoice_rounding = CASE WHEN ?28 IS NULL THEN invoice_rounding ELSE NULLIF(CAST(?28 AS REAL), 0) END,
+    tax_treatment = COALESCE(?29, tax_treatment),
+    logo_path = CASE WHEN ?30 IS NULL THEN logo_path ELSE NULLIF(?30, '') END,
+    brand_colour = CASE WHEN ?31 IS NULL THEN brand_colour ELSE NULLIF(?31, '') END,
+    analysis_max_commits = CASE WHEN ?32 IS NULL THEN analysis_max_commits ELSE NULLIF(?32, -1) END,
+    analysis_ignore = CASE WHEN ?33 IS NULL THEN analysis_ignore ELSE NULLIF(?33, '') END,
+    analysis_detail = CASE WHEN ?34 IS NULL THEN analysis_detail ELSE NULLIF(?34, '') END,
+    analysis_prompt = CASE WHEN ?35 IS NULL THEN analysis_prompt ELSE NULLIF(?35, '') END
+WHERE id = ?36
+RETURNING id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis, currency, locale, source, work_log, retainer_start_date, early_discount_percent, early_discount_days, invoice_notes, payment_terms, po_number, project_code, invoice_rounding, tax_treatment, logo_path, brand_colour, analysis_max_commits, analysis_ignore, analysis_detail, analysis_prompt
 `
 
 type UpdateClientParams struct {
-	HourlyRate     decimal.NullDecimal `db:"hourly_rate" json:"hourly_rate"`
-	CompanyName    sql.NullString      `db:"company_name" json:"company_name"`
-	ContactName    sql.NullString      `db:"contact_name" json:"contact_name"`
-	Email          sql.NullString      `db:"email" json:"email"`
-	Phone          sql.NullString      `db:"phone" json:"phone"`
-	AddressLine1   sql.NullString      `db:"address_line1" json:"address_line1"`
-	AddressLine2   sql.NullString      `db:"address_line2" json:"address_line2"`
-	City           sql.NullString      `db:"city" json:"city"`
-	State          sql.NullString      `db:"state" json:"state"`
-	PostalCode     sql.NullString      `db:"postal_code" json:"postal_code"`
-	Country        sql.NullString      `db:"country" json:"country"`
-	Abn            sql.NullString      `db:"abn" json:"abn"`
-	Dir            sql.NullString      `db:"dir" json:"dir"`
-	RetainerAmount decimal.NullDecimal `db:"retainer_amount" json:"retainer_amount"`
-	RetainerHours  sql.NullFloat64     `db:"retainer_hours" json:"retainer_hours"`
-	RetainerBasis  sql.NullString      `db:"retainer_basis" json:"retainer_basis"`
-	ID             string              `db:"id" json:"id"`
+	HourlyRate           decimal.NullDecimal `db:"hourly_rate" json:"hourly_rate"`
+	CompanyName          sql.NullString      `db:"company_name" json:"company_name"`
+	ContactName          sql.NullString      `db:"contact_name" json:"contact_name"`
+	Email                sql.NullString      `db:"email" json:"email"`
+	Phone                sql.NullString      `db:"phone" json:"phone"`
+	AddressLine1         sql.NullString      `db:"address_line1" json:"address_line1"`
+	AddressLine2         sql.NullString      `db:"address_line2" json:"address_line2"`
+	City                 sql.NullString      `db:"city" json:"city"`
+	State                sql.NullString      `db:"state" json:"state"`
+	PostalCode           sql.NullString      `db:"postal_code" json:"postal_code"`
+	Country              sql.NullString      `db:"country" json:"country"`
+	Abn                  sql.NullString      `db:"abn" json:"abn"`
+	Dir                  sql.NullString      `db:"dir" json:"dir"`
+	RetainerAmount       decimal.NullDecimal `db:"retainer_amount" json:"retainer_amount"`
+	RetainerHours        sql.NullFloat64     `db:"retainer_hours" json:"retainer_hours"`
+	RetainerBasis        sql.NullString      `db:"retainer_basis" json:"retainer_basis"`
+	Currency             sql.NullString      `db:"currency" json:"currency"`
+	Locale               sql.NullString      `db:"locale" json:"locale"`
+	Source               sql.NullString      `db:"source" json:"source"`
+	WorkLog              sql.NullString      `db:"work_log" json:"work_log"`
+	RetainerStartDate    sql.NullTime        `db:"retainer_start_date" json:"retainer_start_date"`
+	EarlyDiscountPercent sql.NullFloat64     `db:"early_discount_percent" json:"early_discount_percent"`
+	EarlyDiscountDays    sql.NullInt64       `db:"early_discount_days" json:"early_discount_days"`
+	InvoiceNotes         sql.NullString      `db:"invoice_notes" json:"invoice_notes"`
+	PaymentTerms         sql.NullString      `db:"payment_terms" json:"payment_terms"`
+	PoNumber             sql.NullString      `db:"po_number" json:"po_number"`
+	ProjectCode          sql.NullString      `db:"project_code" json:"project_code"`
+	InvoiceRounding      decimal.NullDecimal `db:"invoice_rounding" json:"invoice_rounding"`
+	TaxTreatment         sql.NullString      `db:"tax_treatment" json:"tax_treatment"`
+	LogoPath             sql.NullString      `db:"logo_path" json:"logo_path"`
+	BrandColour          sql.NullString      `db:"brand_colour" json:"brand_colour"`
+	AnalysisMaxCommits   sql.NullInt64       `db:"analysis_max_commits" json:"analysis_max_commits"`
+	AnalysisIgnore       sql.NullString      `db:"analysis_ignore" json:"analysis_ignore"`
+	AnalysisDetail       sql.NullString      `db:"analysis_detail" json:"analysis_detail"`
+	AnalysisPrompt       sql.NullString      `db:"analysis_prompt" json:"analysis_prompt"`
+	ID                   string              `db:"id" json:"id"`
 }
 
 func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (Client, error) {
@@ -312,6 +521,25 @@ func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (Cli
 		arg.RetainerAmount,
 		arg.RetainerHours,
 		arg.RetainerBasis,
+		arg.Currency,
+		arg.Locale,
+		arg.Source,
+		arg.WorkLog,
+		arg.RetainerStartDate,
+		arg.EarlyDiscountPercent,
+		arg.EarlyDiscountDays,
+		arg.InvoiceNotes,
+		arg.PaymentTerms,
+		arg.PoNumber,
+		arg.ProjectCode,
+		arg.InvoiceRounding,
+		arg.TaxTreatment,
+		arg.LogoPath,
+		arg.BrandColour,
+		arg.AnalysisMaxCommits,
+		arg.AnalysisIgnore,
+		arg.AnalysisDetail,
+		arg.AnalysisPrompt,
 		arg.ID,
 	)
 	var i Client
@@ -336,6 +564,60 @@ func (q *Queries) UpdateClient(ctx context.Context, arg UpdateClientParams) (Cli
 		&i.RetainerAmount,
 		&i.RetainerHours,
 		&i.RetainerBasis,
+		&i.Currency,
+		&i.Locale,
+		&i.Source,
+		&i.WorkLog,
+		&i.RetainerStartDate,
+		&i.EarlyDiscountPercent,
+		&i.EarlyDiscountDays,
+		&i.InvoiceNotes,
+		&i.PaymentTerms,
+		&i.PoNumber,
+		&i.ProjectCode,
+		&i.InvoiceRounding,
+		&i.TaxTreatment,
+		&i.LogoPath,
+		&i.BrandColour,
+		&i.AnalysisMaxCommits,
+		&i.AnalysisIgnore,
+		&i.AnalysisDetail,
+		&i.AnalysisPrompt,
 	)
 	return i, err
 }
+
+const updateClientContactFields = `-- name: UpdateClientContactFields :exec
+UPDATE clients
+SET
+    contact_name = ?1,
+    email = ?2,
+    phone = ?3,
+    address_line1 = ?4,
+    address_line2 = ?5,
+    abn = ?6
+WHERE id = ?7
+`
+
+type UpdateClientContactFieldsParams struct {
+	ContactName  sql.NullString `db:"contact_name" json:"contact_name"`
+	Email        sql.NullString `db:"email" json:"email"`
+	Phone        sql.NullString `db:"phone" json:"phone"`
+	AddressLine1 sql.NullString `db:"address_line1" json:"address_line1"`
+	AddressLine2 sql.NullString `db:"address_line2" json:"address_line2"`
+	Abn          sql.NullString `db:"abn" json:"abn"`
+	ID           string         `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateClientContactFields(ctx context.Context, arg UpdateClientContactFieldsParams) error {
+	_, err := q.db.ExecContext(ctx, updateClientContactFields,
+		arg.ContactName,
+		arg.Email,
+		arg.Phone,
+		arg.AddressLine1,
+		arg.AddressLine2,
+		arg.Abn,
+		arg.ID,
+	)
+	return err
+}
@@ -24,31 +24,125 @@ func (q *Queries) ClearExpenseInvoiceIDs(ctx context.Context, invoiceID sql.Null
 	return err
 }
 
+const confirmExpense = `-- name: ConfirmExpense :one
+UPDATE expenses
+SET amount = ?1, draft = 0
+WHERE id = ?2 AND deleted_at IS NULL
+RETURNING id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at
+`
+
+type ConfirmExpenseParams struct {
+	Amount decimal.Decimal `db:"amount" json:"amount"`
+	ID     string          `db:"id" json:"id"`
+}
+
+func (q *Queries) ConfirmExpense(ctx context.Context, arg ConfirmExpenseParams) (Expense, error) {
+	row := q.db.QueryRowContext(ctx, confirmExpense, arg.Amount, arg.ID)
+	var i Expense
+	err := row.Scan(
+		&i.ID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpenseDate,
+		&i.Reference,
+		&i.ClientID,
+		&i.InvoiceID,
+		&i.Description,
+		&i.Category,
+		&i.MarkupPercent,
+		&i.Billable,
+		&i.Draft,
+		&i.ReceiptPath,
+		&i.DistanceKm,
+		&i.RatePerKm,
+		&i.GstAmount,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const createExpense = `-- name: CreateExpense :one
-INSERT INTO expenses (id, amount, expense_date, reference, client_id, invoice_id, description)
-VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
-RETURNING id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description
+INSERT INTO expenses (id, amount, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, gst_amount)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11)
+RETURNING id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at
 `
 
 type CreateExpenseParams struct {
+	ID            string              `db:"id" json:"id"`
+	Amount        decimal.Decimal     `db:"amount" json:"amount"`
+	ExpenseDate   time.Time           `db:"expense_date" json:"expense_date"`
+	Reference     sql.NullString      `db:"reference" json:"reference"`
+	ClientID      sql.NullString      `db:"client_id" json:"client_id"`
+	InvoiceID     sql.NullString      `db:"invoice_id" json:"invoice_id"`
+	Description   sql.NullString      `db:"description" json:"description"`
+	Category      sql.NullString      `db:"category" json:"category"`
+	MarkupPercent decimal.NullDecimal `db:"markup_percent" json:"markup_percent"`
+	Billable      bool                `db:"billable" json:"billable"`
+	GstAmount     decimal.NullDecimal `db:"gst_amount" json:"gst_amount"`
+}
+
+func (q *Queries) CreateExpense(ctx context.Context, arg CreateExpenseParams) (Expense, error) {
+	row := q.db.QueryRowContext(ctx, createExpense,
+		arg.ID,
+		arg.Amount,
+		arg.ExpenseDate,
+		arg.Reference,
+		arg.ClientID,
+		arg.InvoiceID,
+		arg.Description,
+		arg.Category,
+		arg.MarkupPercent,
+		arg.Billable,
+		arg.GstAmount,
+	)
+	var i Expense
+	err := row.Scan(
+		&i.ID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpenseDate,
+		&i.Reference,
+		&i.ClientID,
+		&i.InvoiceID,
+		&i.Description,
+		&i.Category,
+		&i.MarkupPercent,
+		&i.Billable,
+		&i.Draft,
+		&i.ReceiptPath,
+		&i.DistanceKm,
+		&i.RatePerKm,
+		&i.GstAmount,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const createDraftExpense = `-- name: CreateDraftExpense :one
+INSERT INTO expenses (id, amount, expense_date, reference, client_id, receipt_path, draft)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, 1)
+RETURNING id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at
+`
+
+type CreateDraftExpenseParams struct {
 	ID          string          `db:"id" json:"id"`
 	Amount      decimal.Decimal `db:"amount" json:"amount"`
 	ExpenseDate time.Time       `db:"expense_date" json:"expense_date"`
 	Reference   sql.NullString  `db:"reference" json:"reference"`
 	ClientID    sql.NullString  `db:"client_id" json:"client_id"`
-	InvoiceID   sql.NullString  `db:"invoice_id" json:"invoice_id"`
-	Description sql.NullString  `db:"description" json:"description"`
+	ReceiptPath sql.NullString  `db:"receipt_path" json:"receipt_path"`
 }
 
-func (q *Queries) CreateExpense(ctx context.Context, arg CreateExpenseParams) (Expense, error) {
-	row := q.db.QueryRowContext(ctx, createExpense,
+func (q *Queries) CreateDraftExpense(ctx context.Context, arg CreateDraftExpenseParams) (Expense, error) {
+	row := q.db.QueryRowContext(ctx, createDraftExpense,
 		arg.ID,
 		arg.Amount,
 		arg.ExpenseDate,
 		arg.Reference,
 		arg.ClientID,
-		arg.InvoiceID,
-		arg.Description,
+		arg.ReceiptPath,
 	)
 	var i Expense
 	err := row.Scan(
@@ -61,23 +155,76 @@ func (q *Queries) CreateExpense(ctx context.Context, arg CreateExpenseParams) (E
 		&i.ClientID,
 		&i.InvoiceID,
 		&i.Description,
+		&i.Category,
+		&i.MarkupPercent,
+		&i.Billable,
+		&i.Draft,
+		&i.ReceiptPath,
+		&i.DistanceKm,
+		&i.RatePerKm,
+		&i.GstAmount,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteExpense = `-- name: DeleteExpense :exec
-DELETE FROM expenses
-WHERE id = ?1
+const createMileageExpense = `-- name: CreateMileageExpense :one
+INSERT INTO expenses (id, amount, expense_date, reference, client_id, description, category, billable, distance_km, rate_per_km)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, 'travel', ?7, ?8, ?9)
+RETURNING id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at
 `
 
-func (q *Queries) DeleteExpense(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, deleteExpense, id)
-	return err
+type CreateMileageExpenseParams struct {
+	ID          string              `db:"id" json:"id"`
+	Amount      decimal.Decimal     `db:"amount" json:"amount"`
+	ExpenseDate time.Time           `db:"expense_date" json:"expense_date"`
+	Reference   sql.NullString      `db:"reference" json:"reference"`
+	ClientID    sql.NullString      `db:"client_id" json:"client_id"`
+	Description sql.NullString      `db:"description" json:"description"`
+	Billable    bool                `db:"billable" json:"billable"`
+	DistanceKm  decimal.NullDecimal `db:"distance_km" json:"distance_km"`
+	RatePerKm   decimal.NullDecimal `db:"rate_per_km" json:"rate_per_km"`
+}
+
+func (q *Queries) CreateMileageExpense(ctx context.Context, arg CreateMileageExpenseParams) (Expense, error) {
+	row := q.db.QueryRowContext(ctx, createMileageExpense,
+		arg.ID,
+		arg.Amount,
+		arg.ExpenseDate,
+		arg.Reference,
+		arg.ClientID,
+		arg.Description,
+		arg.Billable,
+		arg.DistanceKm,
+		arg.RatePerKm,
+	)
+	var i Expense
+	err := row.Scan(
+		&i.ID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpenseDate,
+		&i.Reference,
+		&i.ClientID,
+		&i.InvoiceID,
+		&i.Description,
+		&i.Category,
+		&i.MarkupPercent,
+		&i.Billable,
+		&i.Draft,
+		&i.ReceiptPath,
+		&i.DistanceKm,
+		&i.RatePerKm,
+		&i.GstAmount,
+		&i.DeletedAt,
+	)
+	return i, err
 }
 
 const getExpenseByID = `-- name: GetExpenseByID :one
-SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description FROM expenses
-WHERE id = ?1
+SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at FROM expenses
+WHERE id = ?1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetExpenseByID(ctx context.Context, id string) (Expense, error) {
@@ -93,13 +240,56 @@ func (q *Queries) GetExpenseByID(ctx context.Context, id string) (Expense, error
 		&i.ClientID,
 		&i.InvoiceID,
 		&i.Description,
+		&i.Category,
+		&i.MarkupPercent,
+		&i.Billable,
+		&i.Draft,
+		&i.ReceiptPath,
+		&i.DistanceKm,
+		&i.RatePerKm,
+		&i.GstAmount,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getExpenseByReceiptPath = `-- name: GetExpenseByReceiptPath :one
+SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at FROM expenses
+WHERE receipt_path = ?1
+  AND deleted_at IS NULL
+LIMIT 1
+`
+
+func (q *Queries) GetExpenseByReceiptPath(ctx context.Context, receiptPath sql.NullString) (Expense, error) {
+	row := q.db.QueryRowContext(ctx, getExpenseByReceiptPath, receiptPath)
+	var i Expense
+	err := row.Scan(
+		&i.ID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpenseDate,
+		&i.Reference,
+		&i.ClientID,
+		&i.InvoiceID,
+		&i.Description,
+		&i.Category,
+		&i.MarkupPercent,
+		&i.Billable,
+		&i.Draft,
+		&i.ReceiptPath,
+		&i.DistanceKm,
+		&i.RatePerKm,
+		&i.GstAmount,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getExpensesByInvoiceID = `-- name: GetExpensesByInvoiceID :many
-SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description FROM expenses
+SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at FROM expenses
 WHERE invoice_id = ?1
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC
 `
 
@@ -122,6 +312,15 @@ func (q *Queries) GetExpensesByInvoiceID(ctx context.Context, invoiceID sql.Null
 			&i.ClientID,
 			&i.InvoiceID,
 			&i.Description,
+			&i.Category,
+			&i.MarkupPercent,
+			&i.Billable,
+			&i.Draft,
+			&i.ReceiptPath,
+			&i.DistanceKm,
+			&i.RatePerKm,
+			&i.GstAmount,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -137,8 +336,9 @@ func (q *Queries) GetExpensesByInvoiceID(ctx context.Context, invoiceID sql.Null
 }
 
 const getExpensesByReference = `-- name: GetExpensesByReference :many
-SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description FROM expenses
+SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at FROM expenses
 WHERE reference = ?1
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC
 `
 
@@ -161,6 +361,15 @@ func (q *Queries) GetExpensesByReference(ctx context.Context, reference sql.Null
 			&i.ClientID,
 			&i.InvoiceID,
 			&i.Description,
+			&i.Category,
+			&i.MarkupPercent,
+			&i.Billable,
+			&i.Draft,
+			&i.ReceiptPath,
+			&i.DistanceKm,
+			&i.RatePerKm,
+			&i.GstAmount,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -176,8 +385,9 @@ func (q *Queries) GetExpensesByReference(ctx context.Context, reference sql.Null
 }
 
 const getExpensesWithoutInvoiceByClient = `-- name: GetExpensesWithoutInvoiceByClient :many
-SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description FROM expenses
+SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at FROM expenses
 WHERE client_id = ?1 AND invoice_id IS NULL
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC
 `
 
@@ -200,6 +410,15 @@ func (q *Queries) GetExpensesWithoutInvoiceByClient(ctx context.Context, clientI
 			&i.ClientID,
 			&i.InvoiceID,
 			&i.Description,
+			&i.Category,
+			&i.MarkupPercent,
+			&i.Billable,
+			&i.Draft,
+			&i.ReceiptPath,
+			&i.DistanceKm,
+			&i.RatePerKm,
+			&i.GstAmount,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -215,11 +434,12 @@ func (q *Queries) GetExpensesWithoutInvoiceByClient(ctx context.Context, clientI
 }
 
 const getExpensesWithoutInvoiceByClientAndDateRange = `-- name: GetExpensesWithoutInvoiceByClientAndDateRange :many
-SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description FROM expenses
+SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at FROM expenses
 WHERE client_id = ?1 
   AND invoice_id IS NULL
   AND expense_date >= ?2 
   AND expense_date <= ?3
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC
 `
 
@@ -248,6 +468,64 @@ func (q *Queries) GetExpensesWithoutInvoiceByClientAndDateRange(ctx context.Cont
 			&i.ClientID,
 			&i.InvoiceID,
 			&i.Description,
+			&i.Category,
+			&i.MarkupPercent,
+			&i.Billable,
+			&i.Draft,
+			&i.ReceiptPath,
+			&i.DistanceKm,
+			&i.RatePerKm,
+			&i.GstAmount,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDraftExpenses = `-- name: ListDraftExpenses :many
+SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at FROM expenses
+WHERE draft = 1
+  AND deleted_at IS NULL
+ORDER BY expense_date ASC
+`
+
+func (q *Queries) ListDraftExpenses(ctx context.Context) ([]Expense, error) {
+	rows, err := q.db.QueryContext(ctx, listDraftExpenses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Expense
+	for rows.Next() {
+		var i Expense
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExpenseDate,
+			&i.Reference,
+			&i.ClientID,
+			&i.InvoiceID,
+			&i.Description,
+			&i.Category,
+			&i.MarkupPercent,
+			&i.Billable,
+			&i.Draft,
+			&i.ReceiptPath,
+			&i.DistanceKm,
+			&i.RatePerKm,
+			&i.GstAmount,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -263,7 +541,8 @@ func (q *Queries) GetExpensesWithoutInvoiceByClientAndDateRange(ctx context.Cont
 }
 
 const listExpenses = `-- name: ListExpenses :many
-SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description FROM expenses
+SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at FROM expenses
+WHERE deleted_at IS NULL
 ORDER BY expense_date DESC
 `
 
@@ -286,6 +565,15 @@ func (q *Queries) ListExpenses(ctx context.Context) ([]Expense, error) {
 			&i.ClientID,
 			&i.InvoiceID,
 			&i.Description,
+			&i.Category,
+			&i.MarkupPercent,
+			&i.Billable,
+			&i.Draft,
+			&i.ReceiptPath,
+			&i.DistanceKm,
+			&i.RatePerKm,
+			&i.GstAmount,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -301,8 +589,9 @@ func (q *Queries) ListExpenses(ctx context.Context) ([]Expense, error) {
 }
 
 const listExpensesByClient = `-- name: ListExpensesByClient :many
-SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description FROM expenses
+SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at FROM expenses
 WHERE client_id = ?1
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC
 `
 
@@ -325,6 +614,15 @@ func (q *Queries) ListExpensesByClient(ctx context.Context, clientID sql.NullStr
 			&i.ClientID,
 			&i.InvoiceID,
 			&i.Description,
+			&i.Category,
+			&i.MarkupPercent,
+			&i.Billable,
+			&i.Draft,
+			&i.ReceiptPath,
+			&i.DistanceKm,
+			&i.RatePerKm,
+			&i.GstAmount,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -340,10 +638,11 @@ func (q *Queries) ListExpensesByClient(ctx context.Context, clientID sql.NullStr
 }
 
 const listExpensesByClientAndDateRange = `-- name: ListExpensesByClientAndDateRange :many
-SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description FROM expenses
+SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at FROM expenses
 WHERE client_id = ?1 
   AND expense_date >= ?2 
   AND expense_date <= ?3
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC
 `
 
@@ -372,6 +671,15 @@ func (q *Queries) ListExpensesByClientAndDateRange(ctx context.Context, arg List
 			&i.ClientID,
 			&i.InvoiceID,
 			&i.Description,
+			&i.Category,
+			&i.MarkupPercent,
+			&i.Billable,
+			&i.Draft,
+			&i.ReceiptPath,
+			&i.DistanceKm,
+			&i.RatePerKm,
+			&i.GstAmount,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -387,8 +695,9 @@ func (q *Queries) ListExpensesByClientAndDateRange(ctx context.Context, arg List
 }
 
 const listExpensesByDateRange = `-- name: ListExpensesByDateRange :many
-SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description FROM expenses
+SELECT id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at FROM expenses
 WHERE expense_date >= ?1 AND expense_date <= ?2
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC
 `
 
@@ -416,6 +725,15 @@ func (q *Queries) ListExpensesByDateRange(ctx context.Context, arg ListExpensesB
 			&i.ClientID,
 			&i.InvoiceID,
 			&i.Description,
+			&i.Category,
+			&i.MarkupPercent,
+			&i.Billable,
+			&i.Draft,
+			&i.ReceiptPath,
+			&i.DistanceKm,
+			&i.RatePerKm,
+			&i.GstAmount,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -430,6 +748,22 @@ func (q *Queries) ListExpensesByDateRange(ctx context.Context, arg ListExpensesB
 	return items, nil
 }
 
+const trashExpense = `-- name: TrashExpense :exec
+UPDATE expenses
+SET deleted_at = ?1
+WHERE id = ?2 AND deleted_at IS NULL
+`
+
+type TrashExpenseParams struct {
+	DeletedAt sql.NullTime `db:"deleted_at" json:"deleted_at"`
+	ID        string       `db:"id" json:"id"`
+}
+
+func (q *Queries) TrashExpense(ctx context.Context, arg TrashExpenseParams) error {
+	_, err := q.db.ExecContext(ctx, trashExpense, arg.DeletedAt, arg.ID)
+	return err
+}
+
 const updateExpense = `-- name: UpdateExpense :one
 UPDATE expenses 
 SET 
@@ -438,19 +772,27 @@ SET
     reference = ?3,
     client_id = ?4,
     invoice_id = ?5,
-    description = ?6
-WHERE id = ?7
-RETURNING id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description
+    description = ?6,
+    category = ?7,
+    markup_percent = ?8,
+    billable = ?9,
+    gst_amount = ?10
+WHERE id = ?11 AND deleted_at IS NULL
+RETURNING id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount, deleted_at
 `
 
 type UpdateExpenseParams struct {
-	Amount      decimal.Decimal `db:"amount" json:"amount"`
-	ExpenseDate sql.NullTime    `db:"expense_date" json:"expense_date"`
-	Reference   sql.NullString  `db:"reference" json:"reference"`
-	ClientID    sql.NullString  `db:"client_id" json:"client_id"`
-	InvoiceID   sql.NullString  `db:"invoice_id" json:"invoice_id"`
-	Description sql.NullString  `db:"description" json:"description"`
-	ID          string          `db:"id" json:"id"`
+	Amount        decimal.Decimal     `db:"amount" json:"amount"`
+	ExpenseDate   sql.NullTime        `db:"expense_date" json:"expense_date"`
+	Reference     sql.NullString      `db:"reference" json:"reference"`
+	ClientID      sql.NullString      `db:"client_id" json:"client_id"`
+	InvoiceID     sql.NullString      `db:"invoice_id" json:"invoice_id"`
+	Description   sql.NullString      `db:"description" json:"description"`
+	Category      sql.NullString      `db:"category" json:"category"`
+	MarkupPercent decimal.NullDecimal `db:"markup_percent" json:"markup_percent"`
+	Billable      bool                `db:"billable" json:"billable"`
+	GstAmount     decimal.NullDecimal `db:"gst_amount" json:"gst_amount"`
+	ID            string              `db:"id" json:"id"`
 }
 
 func (q *Queries) UpdateExpense(ctx context.Context, arg UpdateExpenseParams) (Expense, error) {
@@ -461,6 +803,10 @@ func (q *Queries) UpdateExpense(ctx context.Context, arg UpdateExpenseParams) (E
 		arg.ClientID,
 		arg.InvoiceID,
 		arg.Description,
+		arg.Category,
+		arg.MarkupPercent,
+		arg.Billable,
+		arg.GstAmount,
 		arg.ID,
 	)
 	var i Expense
@@ -474,6 +820,15 @@ func (q *Queries) UpdateExpense(ctx context.Context, arg UpdateExpenseParams) (E
 		&i.ClientID,
 		&i.InvoiceID,
 		&i.Description,
+		&i.Category,
+		&i.MarkupPercent,
+		&i.Billable,
+		&i.Draft,
+		&i.ReceiptPath,
+		&i.DistanceKm,
+		&i.RatePerKm,
+		&i.GstAmount,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -481,7 +836,7 @@ func (q *Queries) UpdateExpense(ctx context.Context, arg UpdateExpenseParams) (E
 const updateExpenseInvoiceID = `-- name: UpdateExpenseInvoiceID :exec
 UPDATE expenses 
 SET invoice_id = ?1
-WHERE id = ?2
+WHERE id = ?2 AND deleted_at IS NULL
 `
 
 type UpdateExpenseInvoiceIDParams struct {
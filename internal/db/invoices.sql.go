@@ -25,21 +25,27 @@ func (q *Queries) ClearSessionInvoiceIDs(ctx context.Context, invoiceID sql.Null
 }
 
 const createInvoice = `-- name: CreateInvoice :one
-INSERT INTO invoices (id, client_id, invoice_number, period_type, period_start_date, period_end_date, subtotal_amount, gst_amount, total_amount)
-VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9)
-RETURNING id, client_id, invoice_number, period_type, period_start_date, period_end_date, subtotal_amount, gst_amount, total_amount, generated_date, created_at, updated_at
+INSERT INTO invoices (id, client_id, invoice_number, period_type, period_start_date, period_end_date, subtotal_amount, gst_amount, total_amount, group_by, notes, payment_terms, po_number, project_code, rounding)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13, ?14, ?15)
+RETURNING id, client_id, invoice_number, period_type, period_start_date, period_end_date, subtotal_amount, gst_amount, total_amount, generated_date, created_at, updated_at, group_by, needs_regeneration, notes, payment_terms, po_number, project_code, status, status_reason, status_changed_at, amount_written_off, rounding
 `
 
 type CreateInvoiceParams struct {
-	ID              string          `db:"id" json:"id"`
-	ClientID        string          `db:"client_id" json:"client_id"`
-	InvoiceNumber   string          `db:"invoice_number" json:"invoice_number"`
-	PeriodType      string          `db:"period_type" json:"period_type"`
-	PeriodStartDate time.Time       `db:"period_start_date" json:"period_start_date"`
-	PeriodEndDate   time.Time       `db:"period_end_date" json:"period_end_date"`
-	SubtotalAmount  decimal.Decimal `db:"subtotal_amount" json:"subtotal_amount"`
-	GstAmount       decimal.Decimal `db:"gst_amount" json:"gst_amount"`
-	TotalAmount     decimal.Decimal `db:"total_amount" json:"total_amount"`
+	ID              string              `db:"id" json:"id"`
+	ClientID        string              `db:"client_id" json:"client_id"`
+	InvoiceNumber   string              `db:"invoice_number" json:"invoice_number"`
+	PeriodType      string              `db:"period_type" json:"period_type"`
+	PeriodStartDate time.Time           `db:"period_start_date" json:"period_start_date"`
+	PeriodEndDate   time.Time           `db:"period_end_date" json:"period_end_date"`
+	SubtotalAmount  decimal.Decimal     `db:"subtotal_amount" json:"subtotal_amount"`
+	GstAmount       decimal.Decimal     `db:"gst_amount" json:"gst_amount"`
+	TotalAmount     decimal.Decimal     `db:"total_amount" json:"total_amount"`
+	GroupBy         string              `db:"group_by" json:"group_by"`
+	Notes           sql.NullString      `db:"notes" json:"notes"`
+	PaymentTerms    sql.NullString      `db:"payment_terms" json:"payment_terms"`
+	PoNumber        sql.NullString      `db:"po_number" json:"po_number"`
+	ProjectCode     sql.NullString      `db:"project_code" json:"project_code"`
+	Rounding        decimal.NullDecimal `db:"rounding" json:"rounding"`
 }
 
 func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error) {
@@ -53,6 +59,12 @@ func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (I
 		arg.SubtotalAmount,
 		arg.GstAmount,
 		arg.TotalAmount,
+		arg.GroupBy,
+		arg.Notes,
+		arg.PaymentTerms,
+		arg.PoNumber,
+		arg.ProjectCode,
+		arg.Rounding,
 	)
 	var i Invoice
 	err := row.Scan(
@@ -68,6 +80,17 @@ func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (I
 		&i.GeneratedDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GroupBy,
+		&i.NeedsRegeneration,
+		&i.Notes,
+		&i.PaymentTerms,
+		&i.PoNumber,
+		&i.ProjectCode,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.AmountWrittenOff,
+		&i.Rounding,
 	)
 	return i, err
 }
@@ -82,29 +105,63 @@ func (q *Queries) DeleteInvoice(ctx context.Context, id string) error {
 	return err
 }
 
+const deletePayment = `-- name: DeletePayment :exec
+DELETE FROM payments
+WHERE id = ?1
+`
+
+func (q *Queries) DeletePayment(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deletePayment, id)
+	return err
+}
+
+const flagInvoiceNeedsRegeneration = `-- name: FlagInvoiceNeedsRegeneration :exec
+UPDATE invoices
+SET needs_regeneration = 1
+WHERE id = ?1
+`
+
+func (q *Queries) FlagInvoiceNeedsRegeneration(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, flagInvoiceNeedsRegeneration, id)
+	return err
+}
+
 const getInvoiceByID = `-- name: GetInvoiceByID :one
-SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.amount_paid, i.payment_date, c.name as client_name
+SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.group_by, i.needs_regeneration, i.notes, i.payment_terms, i.po_number, i.project_code, i.status, i.status_reason, i.status_changed_at, i.amount_written_off, i.rounding, i.amount_paid, i.payment_date, i.amount_credited, i.amount_discounted, c.name as client_name
 FROM v_invoices i
 JOIN clients c ON i.client_id = c.id
 WHERE i.id = ?1
 `
 
 type GetInvoiceByIDRow struct {
-	ID              string          `db:"id" json:"id"`
-	ClientID        string          `db:"client_id" json:"client_id"`
-	InvoiceNumber   string          `db:"invoice_number" json:"invoice_number"`
-	PeriodType      string          `db:"period_type" json:"period_type"`
-	PeriodStartDate time.Time       `db:"period_start_date" json:"period_start_date"`
-	PeriodEndDate   time.Time       `db:"period_end_date" json:"period_end_date"`
-	SubtotalAmount  decimal.Decimal `db:"subtotal_amount" json:"subtotal_amount"`
-	GstAmount       decimal.Decimal `db:"gst_amount" json:"gst_amount"`
-	TotalAmount     decimal.Decimal `db:"total_amount" json:"total_amount"`
-	GeneratedDate   time.Time       `db:"generated_date" json:"generated_date"`
-	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
-	AmountPaid      float64         `db:"amount_paid" json:"amount_paid"`
-	PaymentDate     interface{}     `db:"payment_date" json:"payment_date"`
-	ClientName      string          `db:"client_name" json:"client_name"`
+	ID                string              `db:"id" json:"id"`
+	ClientID          string              `db:"client_id" json:"client_id"`
+	InvoiceNumber     string              `db:"invoice_number" json:"invoice_number"`
+	PeriodType        string              `db:"period_type" json:"period_type"`
+	PeriodStartDate   time.Time           `db:"period_start_date" json:"period_start_date"`
+	PeriodEndDate     time.Time           `db:"period_end_date" json:"period_end_date"`
+	SubtotalAmount    decimal.Decimal     `db:"subtotal_amount" json:"subtotal_amount"`
+	GstAmount         decimal.Decimal     `db:"gst_amount" json:"gst_amount"`
+	TotalAmount       decimal.Decimal     `db:"total_amount" json:"total_amount"`
+	GeneratedDate     time.Time           `db:"generated_date" json:"generated_date"`
+	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
+	GroupBy           string              `db:"group_by" json:"group_by"`
+	NeedsRegeneration bool                `db:"needs_regeneration" json:"needs_regeneration"`
+	Notes             sql.NullString      `db:"notes" json:"notes"`
+	PaymentTerms      sql.NullString      `db:"payment_terms" json:"payment_terms"`
+	PoNumber          sql.NullString      `db:"po_number" json:"po_number"`
+	ProjectCode       sql.NullString      `db:"project_code" json:"project_code"`
+	Status            string              `db:"status" json:"status"`
+	StatusReason      sql.NullString      `db:"status_reason" json:"status_reason"`
+	StatusChangedAt   sql.NullTime        `db:"status_changed_at" json:"status_changed_at"`
+	AmountWrittenOff  decimal.Decimal     `db:"amount_written_off" json:"amount_written_off"`
+	Rounding          decimal.NullDecimal `db:"rounding" json:"rounding"`
+	AmountPaid        float64             `db:"amount_paid" json:"amount_paid"`
+	PaymentDate       interface{}         `db:"payment_date" json:"payment_date"`
+	AmountCredited    float64             `db:"amount_credited" json:"amount_credited"`
+	AmountDiscounted  float64             `db:"amount_discounted" json:"amount_discounted"`
+	ClientName        string              `db:"client_name" json:"client_name"`
 }
 
 func (q *Queries) GetInvoiceByID(ctx context.Context, id string) (GetInvoiceByIDRow, error) {
@@ -123,36 +180,62 @@ func (q *Queries) GetInvoiceByID(ctx context.Context, id string) (GetInvoiceByID
 		&i.GeneratedDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GroupBy,
+		&i.NeedsRegeneration,
+		&i.Notes,
+		&i.PaymentTerms,
+		&i.PoNumber,
+		&i.ProjectCode,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.AmountWrittenOff,
+		&i.Rounding,
 		&i.AmountPaid,
 		&i.PaymentDate,
+		&i.AmountCredited,
+		&i.AmountDiscounted,
 		&i.ClientName,
 	)
 	return i, err
 }
 
 const getInvoiceByNumber = `-- name: GetInvoiceByNumber :one
-SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.amount_paid, i.payment_date, c.name as client_name
+SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.group_by, i.needs_regeneration, i.notes, i.payment_terms, i.po_number, i.project_code, i.status, i.status_reason, i.status_changed_at, i.amount_written_off, i.rounding, i.amount_paid, i.payment_date, i.amount_credited, i.amount_discounted, c.name as client_name
 FROM v_invoices i
 JOIN clients c ON i.client_id = c.id
 WHERE i.invoice_number = ?1
 `
 
 type GetInvoiceByNumberRow struct {
-	ID              string          `db:"id" json:"id"`
-	ClientID        string          `db:"client_id" json:"client_id"`
-	InvoiceNumber   string          `db:"invoice_number" json:"invoice_number"`
-	PeriodType      string          `db:"period_type" json:"period_type"`
-	PeriodStartDate time.Time       `db:"period_start_date" json:"period_start_date"`
-	PeriodEndDate   time.Time       `db:"period_end_date" json:"period_end_date"`
-	SubtotalAmount  decimal.Decimal `db:"subtotal_amount" json:"subtotal_amount"`
-	GstAmount       decimal.Decimal `db:"gst_amount" json:"gst_amount"`
-	TotalAmount     decimal.Decimal `db:"total_amount" json:"total_amount"`
-	GeneratedDate   time.Time       `db:"generated_date" json:"generated_date"`
-	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
-	AmountPaid      float64         `db:"amount_paid" json:"amount_paid"`
-	PaymentDate     interface{}     `db:"payment_date" json:"payment_date"`
-	ClientName      string          `db:"client_name" json:"client_name"`
+	ID                string              `db:"id" json:"id"`
+	ClientID          string              `db:"client_id" json:"client_id"`
+	InvoiceNumber     string              `db:"invoice_number" json:"invoice_number"`
+	PeriodType        string              `db:"period_type" json:"period_type"`
+	PeriodStartDate   time.Time           `db:"period_start_date" json:"period_start_date"`
+	PeriodEndDate     time.Time           `db:"period_end_date" json:"period_end_date"`
+	SubtotalAmount    decimal.Decimal     `db:"subtotal_amount" json:"subtotal_amount"`
+	GstAmount         decimal.Decimal     `db:"gst_amount" json:"gst_amount"`
+	TotalAmount       decimal.Decimal     `db:"total_amount" json:"total_amount"`
+	GeneratedDate     time.Time           `db:"generated_date" json:"generated_date"`
+	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
+	GroupBy           string              `db:"group_by" json:"group_by"`
+	NeedsRegeneration bool                `db:"needs_regeneration" json:"needs_regeneration"`
+	Notes             sql.NullString      `db:"notes" json:"notes"`
+	PaymentTerms      sql.NullString      `db:"payment_terms" json:"payment_terms"`
+	PoNumber          sql.NullString      `db:"po_number" json:"po_number"`
+	ProjectCode       sql.NullString      `db:"project_code" json:"project_code"`
+	Status            string              `db:"status" json:"status"`
+	StatusReason      sql.NullString      `db:"status_reason" json:"status_reason"`
+	StatusChangedAt   sql.NullTime        `db:"status_changed_at" json:"status_changed_at"`
+	AmountWrittenOff  decimal.Decimal     `db:"amount_written_off" json:"amount_written_off"`
+	Rounding          decimal.NullDecimal `db:"rounding" json:"rounding"`
+	AmountPaid        float64             `db:"amount_paid" json:"amount_paid"`
+	PaymentDate       interface{}         `db:"payment_date" json:"payment_date"`
+	AmountCredited    float64             `db:"amount_credited" json:"amount_credited"`
+	AmountDiscounted  float64             `db:"amount_discounted" json:"amount_discounted"`
+	ClientName        string              `db:"client_name" json:"client_name"`
 }
 
 func (q *Queries) GetInvoiceByNumber(ctx context.Context, invoiceNumber string) (GetInvoiceByNumberRow, error) {
@@ -171,15 +254,84 @@ func (q *Queries) GetInvoiceByNumber(ctx context.Context, invoiceNumber string)
 		&i.GeneratedDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GroupBy,
+		&i.NeedsRegeneration,
+		&i.Notes,
+		&i.PaymentTerms,
+		&i.PoNumber,
+		&i.ProjectCode,
+		&i.Status,
+		&i.StatusReason,
+		&i.StatusChangedAt,
+		&i.AmountWrittenOff,
+		&i.Rounding,
 		&i.AmountPaid,
 		&i.PaymentDate,
+		&i.AmountCredited,
+		&i.AmountDiscounted,
 		&i.ClientName,
 	)
 	return i, err
 }
 
+const getInvoiceIDsForSessionsByDateRange = `-- name: GetInvoiceIDsForSessionsByDateRange :many
+SELECT DISTINCT invoice_id
+FROM sessions
+WHERE invoice_id IS NOT NULL AND deleted_at IS NULL
+  AND (?1 IS NULL OR start_time >= ?1)
+  AND (?2 IS NULL OR start_time <= ?2)
+`
+
+type GetInvoiceIDsForSessionsByDateRangeParams struct {
+	StartDate interface{} `db:"start_date" json:"start_date"`
+	EndDate   interface{} `db:"end_date" json:"end_date"`
+}
+
+func (q *Queries) GetInvoiceIDsForSessionsByDateRange(ctx context.Context, arg GetInvoiceIDsForSessionsByDateRangeParams) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, getInvoiceIDsForSessionsByDateRange, arg.StartDate, arg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var invoice_id sql.NullString
+		if err := rows.Scan(&invoice_id); err != nil {
+			return nil, err
+		}
+		items = append(items, invoice_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countInvoicedSessionsByDateRange = `-- name: CountInvoicedSessionsByDateRange :one
+SELECT COUNT(*)
+FROM sessions
+WHERE invoice_id IN (SELECT id FROM invoices) AND deleted_at IS NULL
+  AND (?1 IS NULL OR start_time >= ?1)
+  AND (?2 IS NULL OR start_time <= ?2)
+`
+
+type CountInvoicedSessionsByDateRangeParams struct {
+	StartDate interface{} `db:"start_date" json:"start_date"`
+	EndDate   interface{} `db:"end_date" json:"end_date"`
+}
+
+func (q *Queries) CountInvoicedSessionsByDateRange(ctx context.Context, arg CountInvoicedSessionsByDateRangeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countInvoicedSessionsByDateRange, arg.StartDate, arg.EndDate)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getInvoicesByClient = `-- name: GetInvoicesByClient :many
-SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.amount_paid, i.payment_date, c.name as client_name
+SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.group_by, i.needs_regeneration, i.notes, i.payment_terms, i.po_number, i.project_code, i.status, i.status_reason, i.status_changed_at, i.amount_written_off, i.rounding, i.amount_paid, i.payment_date, i.amount_credited, i.amount_discounted, c.name as client_name
 FROM v_invoices i
 JOIN clients c ON i.client_id = c.id
 WHERE c.name = ?1
@@ -187,21 +339,34 @@ ORDER BY i.generated_date DESC
 `
 
 type GetInvoicesByClientRow struct {
-	ID              string          `db:"id" json:"id"`
-	ClientID        string          `db:"client_id" json:"client_id"`
-	InvoiceNumber   string          `db:"invoice_number" json:"invoice_number"`
-	PeriodType      string          `db:"period_type" json:"period_type"`
-	PeriodStartDate time.Time       `db:"period_start_date" json:"period_start_date"`
-	PeriodEndDate   time.Time       `db:"period_end_date" json:"period_end_date"`
-	SubtotalAmount  decimal.Decimal `db:"subtotal_amount" json:"subtotal_amount"`
-	GstAmount       decimal.Decimal `db:"gst_amount" json:"gst_amount"`
-	TotalAmount     decimal.Decimal `db:"total_amount" json:"total_amount"`
-	GeneratedDate   time.Time       `db:"generated_date" json:"generated_date"`
-	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
-	AmountPaid      float64         `db:"amount_paid" json:"amount_paid"`
-	PaymentDate     interface{}     `db:"payment_date" json:"payment_date"`
-	ClientName      string          `db:"client_name" json:"client_name"`
+	ID                string              `db:"id" json:"id"`
+	ClientID          string              `db:"client_id" json:"client_id"`
+	InvoiceNumber     string              `db:"invoice_number" json:"invoice_number"`
+	PeriodType        string              `db:"period_type" json:"period_type"`
+	PeriodStartDate   time.Time           `db:"period_start_date" json:"period_start_date"`
+	PeriodEndDate     time.Time           `db:"period_end_date" json:"period_end_date"`
+	SubtotalAmount    decimal.Decimal     `db:"subtotal_amount" json:"subtotal_amount"`
+	GstAmount         decimal.Decimal     `db:"gst_amount" json:"gst_amount"`
+	TotalAmount       decimal.Decimal     `db:"total_amount" json:"total_amount"`
+	GeneratedDate     time.Time           `db:"generated_date" json:"generated_date"`
+	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
+	GroupBy           string              `db:"group_by" json:"group_by"`
+	NeedsRegeneration bool                `db:"needs_regeneration" json:"needs_regeneration"`
+	Notes             sql.NullString      `db:"notes" json:"notes"`
+	PaymentTerms      sql.NullString      `db:"payment_terms" json:"payment_terms"`
+	PoNumber          sql.NullString      `db:"po_number" json:"po_number"`
+	ProjectCode       sql.NullString      `db:"project_code" json:"project_code"`
+	Status            string              `db:"status" json:"status"`
+	StatusReason      sql.NullString      `db:"status_reason" json:"status_reason"`
+	StatusChangedAt   sql.NullTime        `db:"status_changed_at" json:"status_changed_at"`
+	AmountWrittenOff  decimal.Decimal     `db:"amount_written_off" json:"amount_written_off"`
+	Rounding          decimal.NullDecimal `db:"rounding" json:"rounding"`
+	AmountPaid        float64             `db:"amount_paid" json:"amount_paid"`
+	PaymentDate       interface{}         `db:"payment_date" json:"payment_date"`
+	AmountCredited    float64             `db:"amount_credited" json:"amount_credited"`
+	AmountDiscounted  float64             `db:"amount_discounted" json:"amount_discounted"`
+	ClientName        string              `db:"client_name" json:"client_name"`
 }
 
 func (q *Queries) GetInvoicesByClient(ctx context.Context, clientName string) ([]GetInvoicesByClientRow, error) {
@@ -226,8 +391,21 @@ func (q *Queries) GetInvoicesByClient(ctx context.Context, clientName string) ([
 			&i.GeneratedDate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.GroupBy,
+			&i.NeedsRegeneration,
+			&i.Notes,
+			&i.PaymentTerms,
+			&i.PoNumber,
+			&i.ProjectCode,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.AmountWrittenOff,
+			&i.Rounding,
 			&i.AmountPaid,
 			&i.PaymentDate,
+			&i.AmountCredited,
+			&i.AmountDiscounted,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -244,7 +422,7 @@ func (q *Queries) GetInvoicesByClient(ctx context.Context, clientName string) ([
 }
 
 const getInvoicesByPeriod = `-- name: GetInvoicesByPeriod :many
-SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.amount_paid, i.payment_date, c.name as client_name
+SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.group_by, i.needs_regeneration, i.notes, i.payment_terms, i.po_number, i.project_code, i.status, i.status_reason, i.status_changed_at, i.amount_written_off, i.rounding, i.amount_paid, i.payment_date, i.amount_credited, i.amount_discounted, c.name as client_name
 FROM v_invoices i
 JOIN clients c ON i.client_id = c.id
 WHERE i.period_start_date = ?1 
@@ -260,21 +438,34 @@ type GetInvoicesByPeriodParams struct {
 }
 
 type GetInvoicesByPeriodRow struct {
-	ID              string          `db:"id" json:"id"`
-	ClientID        string          `db:"client_id" json:"client_id"`
-	InvoiceNumber   string          `db:"invoice_number" json:"invoice_number"`
-	PeriodType      string          `db:"period_type" json:"period_type"`
-	PeriodStartDate time.Time       `db:"period_start_date" json:"period_start_date"`
-	PeriodEndDate   time.Time       `db:"period_end_date" json:"period_end_date"`
-	SubtotalAmount  decimal.Decimal `db:"subtotal_amount" json:"subtotal_amount"`
-	GstAmount       decimal.Decimal `db:"gst_amount" json:"gst_amount"`
-	TotalAmount     decimal.Decimal `db:"total_amount" json:"total_amount"`
-	GeneratedDate   time.Time       `db:"generated_date" json:"generated_date"`
-	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
-	AmountPaid      float64         `db:"amount_paid" json:"amount_paid"`
-	PaymentDate     interface{}     `db:"payment_date" json:"payment_date"`
-	ClientName      string          `db:"client_name" json:"client_name"`
+	ID                string              `db:"id" json:"id"`
+	ClientID          string              `db:"client_id" json:"client_id"`
+	InvoiceNumber     string              `db:"invoice_number" json:"invoice_number"`
+	PeriodType        string              `db:"period_type" json:"period_type"`
+	PeriodStartDate   time.Time           `db:"period_start_date" json:"period_start_date"`
+	PeriodEndDate     time.Time           `db:"period_end_date" json:"period_end_date"`
+	SubtotalAmount    decimal.Decimal     `db:"subtotal_amount" json:"subtotal_amount"`
+	GstAmount         decimal.Decimal     `db:"gst_amount" json:"gst_amount"`
+	TotalAmount       decimal.Decimal     `db:"total_amount" json:"total_amount"`
+	GeneratedDate     time.Time           `db:"generated_date" json:"generated_date"`
+	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
+	GroupBy           string              `db:"group_by" json:"group_by"`
+	NeedsRegeneration bool                `db:"needs_regeneration" json:"needs_regeneration"`
+	Notes             sql.NullString      `db:"notes" json:"notes"`
+	PaymentTerms      sql.NullString      `db:"payment_terms" json:"payment_terms"`
+	PoNumber          sql.NullString      `db:"po_number" json:"po_number"`
+	ProjectCode       sql.NullString      `db:"project_code" json:"project_code"`
+	Status            string              `db:"status" json:"status"`
+	StatusReason      sql.NullString      `db:"status_reason" json:"status_reason"`
+	StatusChangedAt   sql.NullTime        `db:"status_changed_at" json:"status_changed_at"`
+	AmountWrittenOff  decimal.Decimal     `db:"amount_written_off" json:"amount_written_off"`
+	Rounding          decimal.NullDecimal `db:"rounding" json:"rounding"`
+	AmountPaid        float64             `db:"amount_paid" json:"amount_paid"`
+	PaymentDate       interface{}         `db:"payment_date" json:"payment_date"`
+	AmountCredited    float64             `db:"amount_credited" json:"amount_credited"`
+	AmountDiscounted  float64             `db:"amount_discounted" json:"amount_discounted"`
+	ClientName        string              `db:"client_name" json:"client_name"`
 }
 
 func (q *Queries) GetInvoicesByPeriod(ctx context.Context, arg GetInvoicesByPeriodParams) ([]GetInvoicesByPeriodRow, error) {
@@ -299,8 +490,21 @@ func (q *Queries) GetInvoicesByPeriod(ctx context.Context, arg GetInvoicesByPeri
 			&i.GeneratedDate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.GroupBy,
+			&i.NeedsRegeneration,
+			&i.Notes,
+			&i.PaymentTerms,
+			&i.PoNumber,
+			&i.ProjectCode,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.AmountWrittenOff,
+			&i.Rounding,
 			&i.AmountPaid,
 			&i.PaymentDate,
+			&i.AmountCredited,
+			&i.AmountDiscounted,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -317,7 +521,7 @@ func (q *Queries) GetInvoicesByPeriod(ctx context.Context, arg GetInvoicesByPeri
 }
 
 const getInvoicesByPeriodAndClient = `-- name: GetInvoicesByPeriodAndClient :many
-SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.amount_paid, i.payment_date, c.name as client_name
+SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.group_by, i.needs_regeneration, i.notes, i.payment_terms, i.po_number, i.project_code, i.status, i.status_reason, i.status_changed_at, i.amount_written_off, i.rounding, i.amount_paid, i.payment_date, i.amount_credited, i.amount_discounted, c.name as client_name
 FROM v_invoices i
 JOIN clients c ON i.client_id = c.id
 WHERE i.period_start_date = ?1 
@@ -335,21 +539,34 @@ type GetInvoicesByPeriodAndClientParams struct {
 }
 
 type GetInvoicesByPeriodAndClientRow struct {
-	ID              string          `db:"id" json:"id"`
-	ClientID        string          `db:"client_id" json:"client_id"`
-	InvoiceNumber   string          `db:"invoice_number" json:"invoice_number"`
-	PeriodType      string          `db:"period_type" json:"period_type"`
-	PeriodStartDate time.Time       `db:"period_start_date" json:"period_start_date"`
-	PeriodEndDate   time.Time       `db:"period_end_date" json:"period_end_date"`
-	SubtotalAmount  decimal.Decimal `db:"subtotal_amount" json:"subtotal_amount"`
-	GstAmount       decimal.Decimal `db:"gst_amount" json:"gst_amount"`
-	TotalAmount     decimal.Decimal `db:"total_amount" json:"total_amount"`
-	GeneratedDate   time.Time       `db:"generated_date" json:"generated_date"`
-	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
-	AmountPaid      float64         `db:"amount_paid" json:"amount_paid"`
-	PaymentDate     interface{}     `db:"payment_date" json:"payment_date"`
-	ClientName      string          `db:"client_name" json:"client_name"`
+	ID                string              `db:"id" json:"id"`
+	ClientID          string              `db:"client_id" json:"client_id"`
+	InvoiceNumber     string              `db:"invoice_number" json:"invoice_number"`
+	PeriodType        string              `db:"period_type" json:"period_type"`
+	PeriodStartDate   time.Time           `db:"period_start_date" json:"period_start_date"`
+	PeriodEndDate     time.Time           `db:"period_end_date" json:"period_end_date"`
+	SubtotalAmount    decimal.Decimal     `db:"subtotal_amount" json:"subtotal_amount"`
+	GstAmount         decimal.Decimal     `db:"gst_amount" json:"gst_amount"`
+	TotalAmount       decimal.Decimal     `db:"total_amount" json:"total_amount"`
+	GeneratedDate     time.Time           `db:"generated_date" json:"generated_date"`
+	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
+	GroupBy           string              `db:"group_by" json:"group_by"`
+	NeedsRegeneration bool                `db:"needs_regeneration" json:"needs_regeneration"`
+	Notes             sql.NullString      `db:"notes" json:"notes"`
+	PaymentTerms      sql.NullString      `db:"payment_terms" json:"payment_terms"`
+	PoNumber          sql.NullString      `db:"po_number" json:"po_number"`
+	ProjectCode       sql.NullString      `db:"project_code" json:"project_code"`
+	Status            string              `db:"status" json:"status"`
+	StatusReason      sql.NullString      `db:"status_reason" json:"status_reason"`
+	StatusChangedAt   sql.NullTime        `db:"status_changed_at" json:"status_changed_at"`
+	AmountWrittenOff  decimal.Decimal     `db:"amount_written_off" json:"amount_written_off"`
+	Rounding          decimal.NullDecimal `db:"rounding" json:"rounding"`
+	AmountPaid        float64             `db:"amount_paid" json:"amount_paid"`
+	PaymentDate       interface{}         `db:"payment_date" json:"payment_date"`
+	AmountCredited    float64             `db:"amount_credited" json:"amount_credited"`
+	AmountDiscounted  float64             `db:"amount_discounted" json:"amount_discounted"`
+	ClientName        string              `db:"client_name" json:"client_name"`
 }
 
 func (q *Queries) GetInvoicesByPeriodAndClient(ctx context.Context, arg GetInvoicesByPeriodAndClientParams) ([]GetInvoicesByPeriodAndClientRow, error) {
@@ -379,8 +596,21 @@ func (q *Queries) GetInvoicesByPeriodAndClient(ctx context.Context, arg GetInvoi
 			&i.GeneratedDate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.GroupBy,
+			&i.NeedsRegeneration,
+			&i.Notes,
+			&i.PaymentTerms,
+			&i.PoNumber,
+			&i.ProjectCode,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.AmountWrittenOff,
+			&i.Rounding,
 			&i.AmountPaid,
 			&i.PaymentDate,
+			&i.AmountCredited,
+			&i.AmountDiscounted,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -396,11 +626,31 @@ func (q *Queries) GetInvoicesByPeriodAndClient(ctx context.Context, arg GetInvoi
 	return items, nil
 }
 
+const getPaymentByID = `-- name: GetPaymentByID :one
+SELECT id, invoice_id, amount, payment_date, created_at, updated_at, discount_amount FROM payments
+WHERE id = ?1
+`
+
+func (q *Queries) GetPaymentByID(ctx context.Context, id string) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentByID, id)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.InvoiceID,
+		&i.Amount,
+		&i.PaymentDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DiscountAmount,
+	)
+	return i, err
+}
+
 const getSessionsByInvoiceID = `-- name: GetSessionsByInvoiceID :many
-SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, c.name as client_name
+SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, s.hostname, s.break_seconds, s.user_id, s.os, s.git_branch, s.deleted_at, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
-WHERE s.invoice_id = ?1
+WHERE s.invoice_id = ?1 AND s.deleted_at IS NULL
 ORDER BY s.start_time
 `
 
@@ -417,6 +667,12 @@ type GetSessionsByInvoiceIDRow struct {
 	OutsideGit      sql.NullString      `db:"outside_git" json:"outside_git"`
 	InvoiceID       sql.NullString      `db:"invoice_id" json:"invoice_id"`
 	IncludesGst     bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname        sql.NullString      `db:"hostname" json:"hostname"`
+	BreakSeconds    int64               `db:"break_seconds" json:"break_seconds"`
+	UserID          sql.NullString      `db:"user_id" json:"user_id"`
+	Os              sql.NullString      `db:"os" json:"os"`
+	GitBranch       sql.NullString      `db:"git_branch" json:"git_branch"`
+	DeletedAt       sql.NullTime        `db:"deleted_at" json:"deleted_at"`
 	ClientName      string              `db:"client_name" json:"client_name"`
 }
 
@@ -442,6 +698,12 @@ func (q *Queries) GetSessionsByInvoiceID(ctx context.Context, invoiceID sql.Null
 			&i.OutsideGit,
 			&i.InvoiceID,
 			&i.IncludesGst,
+			&i.Hostname,
+			&i.BreakSeconds,
+			&i.UserID,
+			&i.Os,
+			&i.GitBranch,
+			&i.DeletedAt,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -458,13 +720,14 @@ func (q *Queries) GetSessionsByInvoiceID(ctx context.Context, invoiceID sql.Null
 }
 
 const getSessionsForPeriodWithoutInvoice = `-- name: GetSessionsForPeriodWithoutInvoice :many
-SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, c.name as client_name
+SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, s.hostname, s.break_seconds, s.user_id, s.os, s.git_branch, s.deleted_at, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
 WHERE s.start_time >= ?1 
   AND s.start_time <= ?2
   AND s.end_time IS NOT NULL
   AND s.invoice_id IS NULL
+  AND s.deleted_at IS NULL
 ORDER BY c.name, s.start_time
 `
 
@@ -486,6 +749,12 @@ type GetSessionsForPeriodWithoutInvoiceRow struct {
 	OutsideGit      sql.NullString      `db:"outside_git" json:"outside_git"`
 	InvoiceID       sql.NullString      `db:"invoice_id" json:"invoice_id"`
 	IncludesGst     bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname        sql.NullString      `db:"hostname" json:"hostname"`
+	BreakSeconds    int64               `db:"break_seconds" json:"break_seconds"`
+	UserID          sql.NullString      `db:"user_id" json:"user_id"`
+	Os              sql.NullString      `db:"os" json:"os"`
+	GitBranch       sql.NullString      `db:"git_branch" json:"git_branch"`
+	DeletedAt       sql.NullTime        `db:"deleted_at" json:"deleted_at"`
 	ClientName      string              `db:"client_name" json:"client_name"`
 }
 
@@ -511,6 +780,12 @@ func (q *Queries) GetSessionsForPeriodWithoutInvoice(ctx context.Context, arg Ge
 			&i.OutsideGit,
 			&i.InvoiceID,
 			&i.IncludesGst,
+			&i.Hostname,
+			&i.BreakSeconds,
+			&i.UserID,
+			&i.Os,
+			&i.GitBranch,
+			&i.DeletedAt,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -527,13 +802,14 @@ func (q *Queries) GetSessionsForPeriodWithoutInvoice(ctx context.Context, arg Ge
 }
 
 const getSessionsForPeriodWithoutInvoiceByClient = `-- name: GetSessionsForPeriodWithoutInvoiceByClient :many
-SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, c.name as client_name
+SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, s.hostname, s.break_seconds, s.user_id, s.os, s.git_branch, s.deleted_at, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
 WHERE s.start_time >= ?1 
   AND s.start_time <= ?2
   AND s.end_time IS NOT NULL
   AND s.invoice_id IS NULL
+  AND s.deleted_at IS NULL
   AND c.name = ?3
 ORDER BY s.start_time
 `
@@ -557,6 +833,12 @@ type GetSessionsForPeriodWithoutInvoiceByClientRow struct {
 	OutsideGit      sql.NullString      `db:"outside_git" json:"outside_git"`
 	InvoiceID       sql.NullString      `db:"invoice_id" json:"invoice_id"`
 	IncludesGst     bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname        sql.NullString      `db:"hostname" json:"hostname"`
+	BreakSeconds    int64               `db:"break_seconds" json:"break_seconds"`
+	UserID          sql.NullString      `db:"user_id" json:"user_id"`
+	Os              sql.NullString      `db:"os" json:"os"`
+	GitBranch       sql.NullString      `db:"git_branch" json:"git_branch"`
+	DeletedAt       sql.NullTime        `db:"deleted_at" json:"deleted_at"`
 	ClientName      string              `db:"client_name" json:"client_name"`
 }
 
@@ -582,6 +864,12 @@ func (q *Queries) GetSessionsForPeriodWithoutInvoiceByClient(ctx context.Context
 			&i.OutsideGit,
 			&i.InvoiceID,
 			&i.IncludesGst,
+			&i.Hostname,
+			&i.BreakSeconds,
+			&i.UserID,
+			&i.Os,
+			&i.GitBranch,
+			&i.DeletedAt,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -598,7 +886,7 @@ func (q *Queries) GetSessionsForPeriodWithoutInvoiceByClient(ctx context.Context
 }
 
 const listInvoices = `-- name: ListInvoices :many
-SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.amount_paid, i.payment_date, c.name as client_name
+SELECT i.id, i.client_id, i.invoice_number, i.period_type, i.period_start_date, i.period_end_date, i.subtotal_amount, i.gst_amount, i.total_amount, i.generated_date, i.created_at, i.updated_at, i.group_by, i.needs_regeneration, i.notes, i.payment_terms, i.po_number, i.project_code, i.status, i.status_reason, i.status_changed_at, i.amount_written_off, i.rounding, i.amount_paid, i.payment_date, i.amount_credited, i.amount_discounted, c.name as client_name
 FROM v_invoices i
 JOIN clients c ON i.client_id = c.id
 ORDER BY i.generated_date DESC
@@ -606,21 +894,34 @@ LIMIT ?1
 `
 
 type ListInvoicesRow struct {
-	ID              string          `db:"id" json:"id"`
-	ClientID        string          `db:"client_id" json:"client_id"`
-	InvoiceNumber   string          `db:"invoice_number" json:"invoice_number"`
-	PeriodType      string          `db:"period_type" json:"period_type"`
-	PeriodStartDate time.Time       `db:"period_start_date" json:"period_start_date"`
-	PeriodEndDate   time.Time       `db:"period_end_date" json:"period_end_date"`
-	SubtotalAmount  decimal.Decimal `db:"subtotal_amount" json:"subtotal_amount"`
-	GstAmount       decimal.Decimal `db:"gst_amount" json:"gst_amount"`
-	TotalAmount     decimal.Decimal `db:"total_amount" json:"total_amount"`
-	GeneratedDate   time.Time       `db:"generated_date" json:"generated_date"`
-	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
-	AmountPaid      float64         `db:"amount_paid" json:"amount_paid"`
-	PaymentDate     interface{}     `db:"payment_date" json:"payment_date"`
-	ClientName      string          `db:"client_name" json:"client_name"`
+	ID                string              `db:"id" json:"id"`
+	ClientID          string              `db:"client_id" json:"client_id"`
+	InvoiceNumber     string              `db:"invoice_number" json:"invoice_number"`
+	PeriodType        string              `db:"period_type" json:"period_type"`
+	PeriodStartDate   time.Time           `db:"period_start_date" json:"period_start_date"`
+	PeriodEndDate     time.Time           `db:"period_end_date" json:"period_end_date"`
+	SubtotalAmount    decimal.Decimal     `db:"subtotal_amount" json:"subtotal_amount"`
+	GstAmount         decimal.Decimal     `db:"gst_amount" json:"gst_amount"`
+	TotalAmount       decimal.Decimal     `db:"total_amount" json:"total_amount"`
+	GeneratedDate     time.Time           `db:"generated_date" json:"generated_date"`
+	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
+	GroupBy           string              `db:"group_by" json:"group_by"`
+	NeedsRegeneration bool                `db:"needs_regeneration" json:"needs_regeneration"`
+	Notes             sql.NullString      `db:"notes" json:"notes"`
+	PaymentTerms      sql.NullString      `db:"payment_terms" json:"payment_terms"`
+	PoNumber          sql.NullString      `db:"po_number" json:"po_number"`
+	ProjectCode       sql.NullString      `db:"project_code" json:"project_code"`
+	Status            string              `db:"status" json:"status"`
+	StatusReason      sql.NullString      `db:"status_reason" json:"status_reason"`
+	StatusChangedAt   sql.NullTime        `db:"status_changed_at" json:"status_changed_at"`
+	AmountWrittenOff  decimal.Decimal     `db:"amount_written_off" json:"amount_written_off"`
+	Rounding          decimal.NullDecimal `db:"rounding" json:"rounding"`
+	AmountPaid        float64             `db:"amount_paid" json:"amount_paid"`
+	PaymentDate       interface{}         `db:"payment_date" json:"payment_date"`
+	AmountCredited    float64             `db:"amount_credited" json:"amount_credited"`
+	AmountDiscounted  float64             `db:"amount_discounted" json:"amount_discounted"`
+	ClientName        string              `db:"client_name" json:"client_name"`
 }
 
 func (q *Queries) ListInvoices(ctx context.Context, limitCount int64) ([]ListInvoicesRow, error) {
@@ -645,8 +946,21 @@ func (q *Queries) ListInvoices(ctx context.Context, limitCount int64) ([]ListInv
 			&i.GeneratedDate,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.GroupBy,
+			&i.NeedsRegeneration,
+			&i.Notes,
+			&i.PaymentTerms,
+			&i.PoNumber,
+			&i.ProjectCode,
+			&i.Status,
+			&i.StatusReason,
+			&i.StatusChangedAt,
+			&i.AmountWrittenOff,
+			&i.Rounding,
 			&i.AmountPaid,
 			&i.PaymentDate,
+			&i.AmountCredited,
+			&i.AmountDiscounted,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -663,15 +977,16 @@ func (q *Queries) ListInvoices(ctx context.Context, limitCount int64) ([]ListInv
 }
 
 const payInvoice = `-- name: PayInvoice :exec
-INSERT INTO payments (id, invoice_id, amount, payment_date)
-VALUES (?1, ?2, ?3, ?4)
+INSERT INTO payments (id, invoice_id, amount, payment_date, discount_amount)
+VALUES (?1, ?2, ?3, ?4, ?5)
 `
 
 type PayInvoiceParams struct {
-	ID          string          `db:"id" json:"id"`
-	InvoiceID   string          `db:"invoice_id" json:"invoice_id"`
-	Amount      decimal.Decimal `db:"amount" json:"amount"`
-	PaymentDate time.Time       `db:"payment_date" json:"payment_date"`
+	ID             string          `db:"id" json:"id"`
+	InvoiceID      string          `db:"invoice_id" json:"invoice_id"`
+	Amount         decimal.Decimal `db:"amount" json:"amount"`
+	PaymentDate    time.Time       `db:"payment_date" json:"payment_date"`
+	DiscountAmount decimal.Decimal `db:"discount_amount" json:"discount_amount"`
 }
 
 func (q *Queries) PayInvoice(ctx context.Context, arg PayInvoiceParams) error {
@@ -680,6 +995,212 @@ func (q *Queries) PayInvoice(ctx context.Context, arg PayInvoiceParams) error {
 		arg.InvoiceID,
 		arg.Amount,
 		arg.PaymentDate,
+		arg.DiscountAmount,
+	)
+	return err
+}
+
+const listPaymentsByDateRange = `-- name: ListPaymentsByDateRange :many
+SELECT id, invoice_id, amount, payment_date, created_at, updated_at, discount_amount FROM payments
+WHERE payment_date >= ?1 AND payment_date <= ?2
+ORDER BY payment_date, created_at
+`
+
+type ListPaymentsByDateRangeParams struct {
+	StartDate time.Time `db:"start_date" json:"start_date"`
+	EndDate   time.Time `db:"end_date" json:"end_date"`
+}
+
+func (q *Queries) ListPaymentsByDateRange(ctx context.Context, arg ListPaymentsByDateRangeParams) ([]Payment, error) {
+	rows, err := q.db.QueryContext(ctx, listPaymentsByDateRange, arg.StartDate, arg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.Amount,
+			&i.PaymentDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DiscountAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPayments = `-- name: ListPayments :many
+SELECT p.id, p.invoice_id, p.amount, p.payment_date, p.created_at, p.updated_at, p.discount_amount, i.invoice_number, c.name AS client_name
+FROM payments p
+JOIN invoices i ON p.invoice_id = i.id
+JOIN clients c ON i.client_id = c.id
+WHERE (?1 IS NULL OR p.invoice_id = ?1)
+  AND (?2 IS NULL OR c.name = ?2)
+ORDER BY p.payment_date, p.created_at
+`
+
+type ListPaymentsParams struct {
+	InvoiceID  sql.NullString `db:"invoice_id" json:"invoice_id"`
+	ClientName sql.NullString `db:"client_name" json:"client_name"`
+}
+
+type ListPaymentsRow struct {
+	ID             string          `db:"id" json:"id"`
+	InvoiceID      string          `db:"invoice_id" json:"invoice_id"`
+	Amount         decimal.Decimal `db:"amount" json:"amount"`
+	PaymentDate    time.Time       `db:"payment_date" json:"payment_date"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time       `db:"updated_at" json:"updated_at"`
+	DiscountAmount decimal.Decimal `db:"discount_amount" json:"discount_amount"`
+	InvoiceNumber  string          `db:"invoice_number" json:"invoice_number"`
+	ClientName     string          `db:"client_name" json:"client_name"`
+}
+
+func (q *Queries) ListPayments(ctx context.Context, arg ListPaymentsParams) ([]ListPaymentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPayments, arg.InvoiceID, arg.ClientName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPaymentsRow
+	for rows.Next() {
+		var i ListPaymentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.InvoiceID,
+			&i.Amount,
+			&i.PaymentDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DiscountAmount,
+			&i.InvoiceNumber,
+			&i.ClientName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateInvoiceAmounts = `-- name: UpdateInvoiceAmounts :exec
+UPDATE invoices
+SET subtotal_amount = ?1,
+    gst_amount = ?2,
+    total_amount = ?3
+WHERE id = ?4
+`
+
+type UpdateInvoiceAmountsParams struct {
+	SubtotalAmount decimal.Decimal `db:"subtotal_amount" json:"subtotal_amount"`
+	GstAmount      decimal.Decimal `db:"gst_amount" json:"gst_amount"`
+	TotalAmount    decimal.Decimal `db:"total_amount" json:"total_amount"`
+	ID             string          `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateInvoiceAmounts(ctx context.Context, arg UpdateInvoiceAmountsParams) error {
+	_, err := q.db.ExecContext(ctx, updateInvoiceAmounts,
+		arg.SubtotalAmount,
+		arg.GstAmount,
+		arg.TotalAmount,
+		arg.ID,
+	)
+	return err
+}
+
+const updateInvoiceGroupBy = `-- name: UpdateInvoiceGroupBy :exec
+UPDATE invoices
+SET group_by = ?1
+WHERE id = ?2
+`
+
+type UpdateInvoiceGroupByParams struct {
+	GroupBy string `db:"group_by" json:"group_by"`
+	ID      string `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateInvoiceGroupBy(ctx context.Context, arg UpdateInvoiceGroupByParams) error {
+	_, err := q.db.ExecContext(ctx, updateInvoiceGroupBy, arg.GroupBy, arg.ID)
+	return err
+}
+
+const updateInvoiceNotes = `-- name: UpdateInvoiceNotes :exec
+UPDATE invoices
+SET notes = ?1
+WHERE id = ?2
+`
+
+type UpdateInvoiceNotesParams struct {
+	Notes sql.NullString `db:"notes" json:"notes"`
+	ID    string         `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateInvoiceNotes(ctx context.Context, arg UpdateInvoiceNotesParams) error {
+	_, err := q.db.ExecContext(ctx, updateInvoiceNotes, arg.Notes, arg.ID)
+	return err
+}
+
+const updateInvoiceReferences = `-- name: UpdateInvoiceReferences :exec
+UPDATE invoices
+SET po_number = ?1,
+    project_code = ?2
+WHERE id = ?3
+`
+
+type UpdateInvoiceReferencesParams struct {
+	PoNumber    sql.NullString `db:"po_number" json:"po_number"`
+	ProjectCode sql.NullString `db:"project_code" json:"project_code"`
+	ID          string         `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateInvoiceReferences(ctx context.Context, arg UpdateInvoiceReferencesParams) error {
+	_, err := q.db.ExecContext(ctx, updateInvoiceReferences, arg.PoNumber, arg.ProjectCode, arg.ID)
+	return err
+}
+
+const updateInvoiceStatus = `-- name: UpdateInvoiceStatus :exec
+UPDATE invoices
+SET status = ?1,
+    status_reason = ?2,
+    status_changed_at = ?3,
+    amount_written_off = ?4
+WHERE id = ?5
+`
+
+type UpdateInvoiceStatusParams struct {
+	Status           string          `db:"status" json:"status"`
+	StatusReason     sql.NullString  `db:"status_reason" json:"status_reason"`
+	StatusChangedAt  sql.NullTime    `db:"status_changed_at" json:"status_changed_at"`
+	AmountWrittenOff decimal.Decimal `db:"amount_written_off" json:"amount_written_off"`
+	ID               string          `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateInvoiceStatus(ctx context.Context, arg UpdateInvoiceStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateInvoiceStatus,
+		arg.Status,
+		arg.StatusReason,
+		arg.StatusChangedAt,
+		arg.AmountWrittenOff,
+		arg.ID,
 	)
 	return err
 }
@@ -687,7 +1208,7 @@ func (q *Queries) PayInvoice(ctx context.Context, arg PayInvoiceParams) error {
 const updateSessionInvoiceID = `-- name: UpdateSessionInvoiceID :exec
 UPDATE sessions
 SET invoice_id = ?1
-WHERE id = ?2
+WHERE id = ?2 AND deleted_at IS NULL
 `
 
 type UpdateSessionInvoiceIDParams struct {
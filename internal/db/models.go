@@ -12,53 +12,135 @@ import (
 )
 
 type Client struct {
-	ID             string              `db:"id" json:"id"`
-	Name           string              `db:"name" json:"name"`
-	CreatedAt      time.Time           `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time           `db:"updated_at" json:"updated_at"`
-	HourlyRate     decimal.NullDecimal `db:"hourly_rate" json:"hourly_rate"`
-	CompanyName    sql.NullString      `db:"company_name" json:"company_name"`
-	ContactName    sql.NullString      `db:"contact_name" json:"contact_name"`
-	Email          sql.NullString      `db:"email" json:"email"`
-	Phone          sql.NullString      `db:"phone" json:"phone"`
-	AddressLine1   sql.NullString      `db:"address_line1" json:"address_line1"`
-	AddressLine2   sql.NullString      `db:"address_line2" json:"address_line2"`
-	City           sql.NullString      `db:"city" json:"city"`
-	State          sql.NullString      `db:"state" json:"state"`
-	PostalCode     sql.NullString      `db:"postal_code" json:"postal_code"`
-	Country        sql.NullString      `db:"country" json:"country"`
-	Dir            sql.NullString      `db:"dir" json:"dir"`
-	Abn            sql.NullString      `db:"abn" json:"abn"`
-	RetainerAmount decimal.NullDecimal `db:"retainer_amount" json:"retainer_amount"`
-	RetainerHours  sql.NullFloat64     `db:"retainer_hours" json:"retainer_hours"`
-	RetainerBasis  sql.NullString      `db:"retainer_basis" json:"retainer_basis"`
+	ID                   string              `db:"id" json:"id"`
+	Name                 string              `db:"name" json:"name"`
+	CreatedAt            time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt            time.Time           `db:"updated_at" json:"updated_at"`
+	HourlyRate           decimal.NullDecimal `db:"hourly_rate" json:"hourly_rate"`
+	CompanyName          sql.NullString      `db:"company_name" json:"company_name"`
+	ContactName          sql.NullString      `db:"contact_name" json:"contact_name"`
+	Email                sql.NullString      `db:"email" json:"email"`
+	Phone                sql.NullString      `db:"phone" json:"phone"`
+	AddressLine1         sql.NullString      `db:"address_line1" json:"address_line1"`
+	AddressLine2         sql.NullString      `db:"address_line2" json:"address_line2"`
+	City                 sql.NullString      `db:"city" json:"city"`
+	State                sql.NullString      `db:"state" json:"state"`
+	PostalCode           sql.NullString      `db:"postal_code" json:"postal_code"`
+	Country              sql.NullString      `db:"country" json:"country"`
+	Dir                  sql.NullString      `db:"dir" json:"dir"`
+	Abn                  sql.NullString      `db:"abn" json:"abn"`
+	RetainerAmount       decimal.NullDecimal `db:"retainer_amount" json:"retainer_amount"`
+	RetainerHours        sql.NullFloat64     `db:"retainer_hours" json:"retainer_hours"`
+	RetainerBasis        sql.NullString      `db:"retainer_basis" json:"retainer_basis"`
+	Currency             sql.NullString      `db:"currency" json:"currency"`
+	Locale               sql.NullString      `db:"locale" json:"locale"`
+	Source               sql.NullString      `db:"source" json:"source"`
+	WorkLog              sql.NullString      `db:"work_log" json:"work_log"`
+	RetainerStartDate    sql.NullTime        `db:"retainer_start_date" json:"retainer_start_date"`
+	EarlyDiscountPercent sql.NullFloat64     `db:"early_discount_percent" json:"early_discount_percent"`
+	EarlyDiscountDays    sql.NullInt64       `db:"early_discount_days" json:"early_discount_days"`
+	InvoiceNotes         sql.NullString      `db:"invoice_notes" json:"invoice_notes"`
+	PaymentTerms         sql.NullString      `db:"payment_terms" json:"payment_terms"`
+	PoNumber             sql.NullString      `db:"po_number" json:"po_number"`
+	ProjectCode          sql.NullString      `db:"project_code" json:"project_code"`
+	InvoiceRounding      decimal.NullDecimal `db:"invoice_rounding" json:"invoice_rounding"`
+	TaxTreatment         string              `db:"tax_treatment" json:"tax_treatment"`
+	LogoPath             sql.NullString      `db:"logo_path" json:"logo_path"`
+	BrandColour          sql.NullString      `db:"brand_colour" json:"brand_colour"`
+	AnalysisMaxCommits   sql.NullInt64       `db:"analysis_max_commits" json:"analysis_max_commits"`
+	AnalysisIgnore       sql.NullString      `db:"analysis_ignore" json:"analysis_ignore"`
+	AnalysisDetail       sql.NullString      `db:"analysis_detail" json:"analysis_detail"`
+	AnalysisPrompt       sql.NullString      `db:"analysis_prompt" json:"analysis_prompt"`
+}
+
+type ClientContact struct {
+	ID              string         `db:"id" json:"id"`
+	ClientID        string         `db:"client_id" json:"client_id"`
+	ContactType     string         `db:"contact_type" json:"contact_type"`
+	Note            string         `db:"note" json:"note"`
+	ContactedAt     time.Time      `db:"contacted_at" json:"contacted_at"`
+	DurationMinutes sql.NullInt64  `db:"duration_minutes" json:"duration_minutes"`
+	SessionID       sql.NullString `db:"session_id" json:"session_id"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type ClientRate struct {
+	ID            string          `db:"id" json:"id"`
+	ClientID      string          `db:"client_id" json:"client_id"`
+	PreviousRate  decimal.Decimal `db:"previous_rate" json:"previous_rate"`
+	HourlyRate    decimal.Decimal `db:"hourly_rate" json:"hourly_rate"`
+	EffectiveDate time.Time       `db:"effective_date" json:"effective_date"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+}
+
+type CreditNote struct {
+	ID               string          `db:"id" json:"id"`
+	InvoiceID        string          `db:"invoice_id" json:"invoice_id"`
+	CreditNoteNumber string          `db:"credit_note_number" json:"credit_note_number"`
+	Amount           decimal.Decimal `db:"amount" json:"amount"`
+	GstAmount        decimal.Decimal `db:"gst_amount" json:"gst_amount"`
+	Reason           string          `db:"reason" json:"reason"`
+	IssuedDate       time.Time       `db:"issued_date" json:"issued_date"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
 }
 
 type Expense struct {
-	ID          string          `db:"id" json:"id"`
-	Amount      decimal.Decimal `db:"amount" json:"amount"`
-	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time       `db:"updated_at" json:"updated_at"`
-	ExpenseDate time.Time       `db:"expense_date" json:"expense_date"`
-	Reference   sql.NullString  `db:"reference" json:"reference"`
-	ClientID    sql.NullString  `db:"client_id" json:"client_id"`
-	InvoiceID   sql.NullString  `db:"invoice_id" json:"invoice_id"`
-	Description sql.NullString  `db:"description" json:"description"`
+	ID            string              `db:"id" json:"id"`
+	Amount        decimal.Decimal     `db:"amount" json:"amount"`
+	CreatedAt     time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time           `db:"updated_at" json:"updated_at"`
+	ExpenseDate   time.Time           `db:"expense_date" json:"expense_date"`
+	Reference     sql.NullString      `db:"reference" json:"reference"`
+	ClientID      sql.NullString      `db:"client_id" json:"client_id"`
+	InvoiceID     sql.NullString      `db:"invoice_id" json:"invoice_id"`
+	Description   sql.NullString      `db:"description" json:"description"`
+	Category      sql.NullString      `db:"category" json:"category"`
+	MarkupPercent decimal.NullDecimal `db:"markup_percent" json:"markup_percent"`
+	Billable      bool                `db:"billable" json:"billable"`
+	Draft         bool                `db:"draft" json:"draft"`
+	ReceiptPath   sql.NullString      `db:"receipt_path" json:"receipt_path"`
+	DistanceKm    decimal.NullDecimal `db:"distance_km" json:"distance_km"`
+	RatePerKm     decimal.NullDecimal `db:"rate_per_km" json:"rate_per_km"`
+	GstAmount     decimal.NullDecimal `db:"gst_amount" json:"gst_amount"`
+	DeletedAt     sql.NullTime        `db:"deleted_at" json:"deleted_at"`
+}
+
+type ExpenseRule struct {
+	ID        string         `db:"id" json:"id"`
+	Pattern   string         `db:"pattern" json:"pattern"`
+	ClientID  sql.NullString `db:"client_id" json:"client_id"`
+	Category  sql.NullString `db:"category" json:"category"`
+	Billable  bool           `db:"billable" json:"billable"`
+	Skip      bool           `db:"skip" json:"skip"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
 }
 
 type Invoice struct {
-	ID              string          `db:"id" json:"id"`
-	ClientID        string          `db:"client_id" json:"client_id"`
-	InvoiceNumber   string          `db:"invoice_number" json:"invoice_number"`
-	PeriodType      string          `db:"period_type" json:"period_type"`
-	PeriodStartDate time.Time       `db:"period_start_date" json:"period_start_date"`
-	PeriodEndDate   time.Time       `db:"period_end_date" json:"period_end_date"`
-	SubtotalAmount  decimal.Decimal `db:"subtotal_amount" json:"subtotal_amount"`
-	GstAmount       decimal.Decimal `db:"gst_amount" json:"gst_amount"`
-	TotalAmount     decimal.Decimal `db:"total_amount" json:"total_amount"`
-	GeneratedDate   time.Time       `db:"generated_date" json:"generated_date"`
-	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+	ID                string              `db:"id" json:"id"`
+	ClientID          string              `db:"client_id" json:"client_id"`
+	InvoiceNumber     string              `db:"invoice_number" json:"invoice_number"`
+	PeriodType        string              `db:"period_type" json:"period_type"`
+	PeriodStartDate   time.Time           `db:"period_start_date" json:"period_start_date"`
+	PeriodEndDate     time.Time           `db:"period_end_date" json:"period_end_date"`
+	SubtotalAmount    decimal.Decimal     `db:"subtotal_amount" json:"subtotal_amount"`
+	GstAmount         decimal.Decimal     `db:"gst_amount" json:"gst_amount"`
+	TotalAmount       decimal.Decimal     `db:"total_amount" json:"total_amount"`
+	GeneratedDate     time.Time           `db:"generated_date" json:"generated_date"`
+	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
+	GroupBy           string              `db:"group_by" json:"group_by"`
+	NeedsRegeneration bool                `db:"needs_regeneration" json:"needs_regeneration"`
+	Notes             sql.NullString      `db:"notes" json:"notes"`
+	PaymentTerms      sql.NullString      `db:"payment_terms" json:"payment_terms"`
+	PoNumber          sql.NullString      `db:"po_number" json:"po_number"`
+	ProjectCode       sql.NullString      `db:"project_code" json:"project_code"`
+	Status            string              `db:"status" json:"status"`
+	StatusReason      sql.NullString      `db:"status_reason" json:"status_reason"`
+	StatusChangedAt   sql.NullTime        `db:"status_changed_at" json:"status_changed_at"`
+	AmountWrittenOff  decimal.Decimal     `db:"amount_written_off" json:"amount_written_off"`
+	Rounding          decimal.NullDecimal `db:"rounding" json:"rounding"`
 }
 
 type InvoicesBackupBeforeDatetimeMigration struct {
@@ -76,13 +158,37 @@ type InvoicesBackupBeforeDatetimeMigration struct {
 	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
 }
 
+type Leave struct {
+	ID        string         `db:"id" json:"id"`
+	LeaveType string         `db:"leave_type" json:"leave_type"`
+	StartDate time.Time      `db:"start_date" json:"start_date"`
+	EndDate   time.Time      `db:"end_date" json:"end_date"`
+	Note      sql.NullString `db:"note" json:"note"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type Milestone struct {
+	ID            string          `db:"id" json:"id"`
+	ClientID      string          `db:"client_id" json:"client_id"`
+	Name          string          `db:"name" json:"name"`
+	Amount        decimal.Decimal `db:"amount" json:"amount"`
+	DueDate       sql.NullTime    `db:"due_date" json:"due_date"`
+	Status        string          `db:"status" json:"status"`
+	CompletedDate sql.NullTime    `db:"completed_date" json:"completed_date"`
+	InvoiceID     sql.NullString  `db:"invoice_id" json:"invoice_id"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at" json:"updated_at"`
+}
+
 type Payment struct {
-	ID          string          `db:"id" json:"id"`
-	InvoiceID   string          `db:"invoice_id" json:"invoice_id"`
-	Amount      decimal.Decimal `db:"amount" json:"amount"`
-	PaymentDate time.Time       `db:"payment_date" json:"payment_date"`
-	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time       `db:"updated_at" json:"updated_at"`
+	ID             string          `db:"id" json:"id"`
+	InvoiceID      string          `db:"invoice_id" json:"invoice_id"`
+	Amount         decimal.Decimal `db:"amount" json:"amount"`
+	PaymentDate    time.Time       `db:"payment_date" json:"payment_date"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time       `db:"updated_at" json:"updated_at"`
+	DiscountAmount decimal.Decimal `db:"discount_amount" json:"discount_amount"`
 }
 
 type PaymentsBackupBeforeDatetimeMigration struct {
@@ -107,21 +213,74 @@ type Session struct {
 	OutsideGit      sql.NullString      `db:"outside_git" json:"outside_git"`
 	InvoiceID       sql.NullString      `db:"invoice_id" json:"invoice_id"`
 	IncludesGst     bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname        sql.NullString      `db:"hostname" json:"hostname"`
+	BreakSeconds    int64               `db:"break_seconds" json:"break_seconds"`
+	UserID          sql.NullString      `db:"user_id" json:"user_id"`
+	Os              sql.NullString      `db:"os" json:"os"`
+	GitBranch       sql.NullString      `db:"git_branch" json:"git_branch"`
+	DeletedAt       sql.NullTime        `db:"deleted_at" json:"deleted_at"`
+}
+
+type SessionBreak struct {
+	ID        string    `db:"id" json:"id"`
+	SessionID string    `db:"session_id" json:"session_id"`
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+	Source    string    `db:"source" json:"source"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type SessionRepo struct {
+	SessionID   string    `db:"session_id" json:"session_id"`
+	RepoPath    string    `db:"repo_path" json:"repo_path"`
+	CommitCount int64     `db:"commit_count" json:"commit_count"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+type SessionTemplate struct {
+	ID              string         `db:"id" json:"id"`
+	Name            string         `db:"name" json:"name"`
+	ClientID        string         `db:"client_id" json:"client_id"`
+	Description     sql.NullString `db:"description" json:"description"`
+	DurationMinutes sql.NullInt64  `db:"duration_minutes" json:"duration_minutes"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type User struct {
+	ID        string              `db:"id" json:"id"`
+	Name      string              `db:"name" json:"name"`
+	CostRate  decimal.NullDecimal `db:"cost_rate" json:"cost_rate"`
+	CreatedAt time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time           `db:"updated_at" json:"updated_at"`
 }
 
 type VInvoice struct {
-	ID              string          `db:"id" json:"id"`
-	ClientID        string          `db:"client_id" json:"client_id"`
-	InvoiceNumber   string          `db:"invoice_number" json:"invoice_number"`
-	PeriodType      string          `db:"period_type" json:"period_type"`
-	PeriodStartDate time.Time       `db:"period_start_date" json:"period_start_date"`
-	PeriodEndDate   time.Time       `db:"period_end_date" json:"period_end_date"`
-	SubtotalAmount  decimal.Decimal `db:"subtotal_amount" json:"subtotal_amount"`
-	GstAmount       decimal.Decimal `db:"gst_amount" json:"gst_amount"`
-	TotalAmount     decimal.Decimal `db:"total_amount" json:"total_amount"`
-	GeneratedDate   time.Time       `db:"generated_date" json:"generated_date"`
-	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
-	AmountPaid      float64         `db:"amount_paid" json:"amount_paid"`
-	PaymentDate     interface{}     `db:"payment_date" json:"payment_date"`
+	ID                string              `db:"id" json:"id"`
+	ClientID          string              `db:"client_id" json:"client_id"`
+	InvoiceNumber     string              `db:"invoice_number" json:"invoice_number"`
+	PeriodType        string              `db:"period_type" json:"period_type"`
+	PeriodStartDate   time.Time           `db:"period_start_date" json:"period_start_date"`
+	PeriodEndDate     time.Time           `db:"period_end_date" json:"period_end_date"`
+	SubtotalAmount    decimal.Decimal     `db:"subtotal_amount" json:"subtotal_amount"`
+	GstAmount         decimal.Decimal     `db:"gst_amount" json:"gst_amount"`
+	TotalAmount       decimal.Decimal     `db:"total_amount" json:"total_amount"`
+	GeneratedDate     time.Time           `db:"generated_date" json:"generated_date"`
+	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
+	GroupBy           string              `db:"group_by" json:"group_by"`
+	NeedsRegeneration bool                `db:"needs_regeneration" json:"needs_regeneration"`
+	Notes             sql.NullString      `db:"notes" json:"notes"`
+	PaymentTerms      sql.NullString      `db:"payment_terms" json:"payment_terms"`
+	PoNumber          sql.NullString      `db:"po_number" json:"po_number"`
+	ProjectCode       sql.NullString      `db:"project_code" json:"project_code"`
+	Status            string              `db:"status" json:"status"`
+	StatusReason      sql.NullString      `db:"status_reason" json:"status_reason"`
+	StatusChangedAt   sql.NullTime        `db:"status_changed_at" json:"status_changed_at"`
+	AmountWrittenOff  decimal.Decimal     `db:"amount_written_off" json:"amount_written_off"`
+	Rounding          decimal.NullDecimal `db:"rounding" json:"rounding"`
+	AmountPaid        float64             `db:"amount_paid" json:"amount_paid"`
+	PaymentDate       interface{}         `db:"payment_date" json:"payment_date"`
+	AmountCredited    float64             `db:"amount_credited" json:"amount_credited"`
+	AmountDiscounted  float64             `db:"amount_discounted" json:"amount_discounted"`
 }
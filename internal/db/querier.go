@@ -11,22 +11,37 @@ import (
 
 type Querier interface {
 	ClearExpenseInvoiceIDs(ctx context.Context, invoiceID sql.NullString) error
+	ClearMilestoneInvoiceIDs(ctx context.Context, invoiceID sql.NullString) error
+	ClearMissingExpenseInvoiceIDs(ctx context.Context) error
+	ClearMissingSessionInvoiceIDs(ctx context.Context) error
 	ClearSessionInvoiceIDs(ctx context.Context, invoiceID sql.NullString) error
+	CompleteMilestone(ctx context.Context, arg CompleteMilestoneParams) error
+	CountActiveSessions(ctx context.Context) (int64, error)
+	CountInvoicedSessionsByDateRange(ctx context.Context, arg CountInvoicedSessionsByDateRangeParams) (int64, error)
 	CreateClient(ctx context.Context, arg CreateClientParams) (Client, error)
+	CreateClientContact(ctx context.Context, arg CreateClientContactParams) (ClientContact, error)
+	CreateClientRate(ctx context.Context, arg CreateClientRateParams) (ClientRate, error)
 	CreateExpense(ctx context.Context, arg CreateExpenseParams) (Expense, error)
+	CreateExpenseRule(ctx context.Context, arg CreateExpenseRuleParams) (ExpenseRule, error)
 	CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error)
+	CreateMilestone(ctx context.Context, arg CreateMilestoneParams) (Milestone, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	DeleteAllSessions(ctx context.Context) error
-	DeleteExpense(ctx context.Context, id string) error
+	DeleteClientContact(ctx context.Context, id string) error
+	DeleteExpenseRule(ctx context.Context, id string) error
 	DeleteInvoice(ctx context.Context, id string) error
-	DeleteSessionsByDateRange(ctx context.Context, arg DeleteSessionsByDateRangeParams) error
+	DeleteMilestone(ctx context.Context, id string) error
 	GetActiveSession(ctx context.Context) (GetActiveSessionRow, error)
+	GetClientContactByID(ctx context.Context, id string) (GetClientContactByIDRow, error)
 	GetClientByID(ctx context.Context, id string) (Client, error)
 	GetClientByName(ctx context.Context, name string) (Client, error)
 	GetClientsWithDirectories(ctx context.Context) ([]Client, error)
+	GetCompletedMilestonesWithoutInvoice(ctx context.Context, arg GetCompletedMilestonesWithoutInvoiceParams) ([]GetCompletedMilestonesWithoutInvoiceRow, error)
 	GetExpenseByID(ctx context.Context, id string) (Expense, error)
+	GetExpenseRuleByID(ctx context.Context, id string) (GetExpenseRuleByIDRow, error)
 	GetExpensesByInvoiceID(ctx context.Context, invoiceID sql.NullString) ([]Expense, error)
 	GetExpensesByReference(ctx context.Context, reference sql.NullString) ([]Expense, error)
+	GetExpensesWithMissingInvoice(ctx context.Context) ([]Expense, error)
 	GetExpensesWithoutInvoiceByClient(ctx context.Context, clientID sql.NullString) ([]Expense, error)
 	GetExpensesWithoutInvoiceByClientAndDateRange(ctx context.Context, arg GetExpensesWithoutInvoiceByClientAndDateRangeParams) ([]Expense, error)
 	GetInvoiceByID(ctx context.Context, id string) (GetInvoiceByIDRow, error)
@@ -34,26 +49,44 @@ type Querier interface {
 	GetInvoicesByClient(ctx context.Context, clientName string) ([]GetInvoicesByClientRow, error)
 	GetInvoicesByPeriod(ctx context.Context, arg GetInvoicesByPeriodParams) ([]GetInvoicesByPeriodRow, error)
 	GetInvoicesByPeriodAndClient(ctx context.Context, arg GetInvoicesByPeriodAndClientParams) ([]GetInvoicesByPeriodAndClientRow, error)
+	GetMilestoneByID(ctx context.Context, id string) (GetMilestoneByIDRow, error)
+	GetMilestonesByInvoiceID(ctx context.Context, invoiceID sql.NullString) ([]GetMilestonesByInvoiceIDRow, error)
 	GetSessionByID(ctx context.Context, id string) (GetSessionByIDRow, error)
 	GetSessionsByClient(ctx context.Context, clientName string) ([]GetSessionsByClientRow, error)
 	GetSessionsByDateRange(ctx context.Context, arg GetSessionsByDateRangeParams) ([]GetSessionsByDateRangeRow, error)
 	GetSessionsByInvoiceID(ctx context.Context, invoiceID sql.NullString) ([]GetSessionsByInvoiceIDRow, error)
 	GetSessionsForPeriodWithoutInvoice(ctx context.Context, arg GetSessionsForPeriodWithoutInvoiceParams) ([]GetSessionsForPeriodWithoutInvoiceRow, error)
 	GetSessionsForPeriodWithoutInvoiceByClient(ctx context.Context, arg GetSessionsForPeriodWithoutInvoiceByClientParams) ([]GetSessionsForPeriodWithoutInvoiceByClientRow, error)
+	GetSessionsWithMissingInvoice(ctx context.Context) ([]GetSessionsWithMissingInvoiceRow, error)
 	GetSessionsWithoutDescription(ctx context.Context, arg GetSessionsWithoutDescriptionParams) ([]GetSessionsWithoutDescriptionRow, error)
+	ListClientContacts(ctx context.Context, arg ListClientContactsParams) ([]ListClientContactsRow, error)
+	ListClientRates(ctx context.Context, clientID string) ([]ClientRate, error)
 	ListClients(ctx context.Context) ([]Client, error)
+	ListExpenseRules(ctx context.Context) ([]ListExpenseRulesRow, error)
 	ListExpenses(ctx context.Context) ([]Expense, error)
 	ListExpensesByClient(ctx context.Context, clientID sql.NullString) ([]Expense, error)
 	ListExpensesByClientAndDateRange(ctx context.Context, arg ListExpensesByClientAndDateRangeParams) ([]Expense, error)
 	ListExpensesByDateRange(ctx context.Context, arg ListExpensesByDateRangeParams) ([]Expense, error)
 	ListInvoices(ctx context.Context, limitCount int64) ([]ListInvoicesRow, error)
+	ListMilestones(ctx context.Context, clientID sql.NullString) ([]ListMilestonesRow, error)
 	ListRecentSessions(ctx context.Context, limitCount int64) ([]ListRecentSessionsRow, error)
 	ListSessionsWithDateRange(ctx context.Context, arg ListSessionsWithDateRangeParams) ([]ListSessionsWithDateRangeRow, error)
+	ListTrashedExpenses(ctx context.Context) ([]Expense, error)
+	ListTrashedSessions(ctx context.Context) ([]ListTrashedSessionsRow, error)
 	PayInvoice(ctx context.Context, arg PayInvoiceParams) error
+	PurgeExpenses(ctx context.Context, before sql.NullTime) (int64, error)
+	PurgeSessions(ctx context.Context, before sql.NullTime) (int64, error)
+	RestoreExpense(ctx context.Context, id string) (Expense, error)
+	RestoreSession(ctx context.Context, id string) (Session, error)
+	SetClientContactSession(ctx context.Context, arg SetClientContactSessionParams) error
 	StopSession(ctx context.Context, arg StopSessionParams) (Session, error)
+	TrashExpense(ctx context.Context, arg TrashExpenseParams) error
+	TrashSessionsByDateRange(ctx context.Context, arg TrashSessionsByDateRangeParams) (int64, error)
 	UpdateClient(ctx context.Context, arg UpdateClientParams) (Client, error)
+	UpdateClientHourlyRate(ctx context.Context, arg UpdateClientHourlyRateParams) error
 	UpdateExpense(ctx context.Context, arg UpdateExpenseParams) (Expense, error)
 	UpdateExpenseInvoiceID(ctx context.Context, arg UpdateExpenseInvoiceIDParams) error
+	UpdateMilestoneInvoiceID(ctx context.Context, arg UpdateMilestoneInvoiceIDParams) error
 	UpdateSessionDescription(ctx context.Context, arg UpdateSessionDescriptionParams) (Session, error)
 	UpdateSessionInvoiceID(ctx context.Context, arg UpdateSessionInvoiceIDParams) error
 	UpdateSessionOutsideGit(ctx context.Context, arg UpdateSessionOutsideGitParams) (Session, error)
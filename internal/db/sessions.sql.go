@@ -14,9 +14,9 @@ import (
 )
 
 const createSession = `-- name: CreateSession :one
-INSERT INTO sessions (id, client_id, start_time, description, hourly_rate, includes_gst)
-VALUES (?1, ?2, ?3, ?4, ?5, ?6)
-RETURNING id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst
+INSERT INTO sessions (id, client_id, start_time, description, hourly_rate, includes_gst, hostname, user_id, os, git_branch)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10)
+RETURNING id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst, hostname, break_seconds, user_id, os, git_branch, deleted_at
 `
 
 type CreateSessionParams struct {
@@ -26,6 +26,10 @@ type CreateSessionParams struct {
 	Description sql.NullString      `db:"description" json:"description"`
 	HourlyRate  decimal.NullDecimal `db:"hourly_rate" json:"hourly_rate"`
 	IncludesGst bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname    sql.NullString      `db:"hostname" json:"hostname"`
+	UserID      sql.NullString      `db:"user_id" json:"user_id"`
+	Os          sql.NullString      `db:"os" json:"os"`
+	GitBranch   sql.NullString      `db:"git_branch" json:"git_branch"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -36,6 +40,10 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.Description,
 		arg.HourlyRate,
 		arg.IncludesGst,
+		arg.Hostname,
+		arg.UserID,
+		arg.Os,
+		arg.GitBranch,
 	)
 	var i Session
 	err := row.Scan(
@@ -51,10 +59,66 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.OutsideGit,
 		&i.InvoiceID,
 		&i.IncludesGst,
+		&i.Hostname,
+		&i.BreakSeconds,
+		&i.UserID,
+		&i.Os,
+		&i.GitBranch,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const createSessionBreak = `-- name: CreateSessionBreak :one
+INSERT INTO session_breaks (id, session_id, start_time, end_time, source)
+VALUES (?1, ?2, ?3, ?4, ?5)
+RETURNING id, session_id, start_time, end_time, source, created_at
+`
+
+type CreateSessionBreakParams struct {
+	ID        string    `db:"id" json:"id"`
+	SessionID string    `db:"session_id" json:"session_id"`
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+	Source    string    `db:"source" json:"source"`
+}
+
+func (q *Queries) CreateSessionBreak(ctx context.Context, arg CreateSessionBreakParams) (SessionBreak, error) {
+	row := q.db.QueryRowContext(ctx, createSessionBreak,
+		arg.ID,
+		arg.SessionID,
+		arg.StartTime,
+		arg.EndTime,
+		arg.Source,
+	)
+	var i SessionBreak
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Source,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createSessionRepo = `-- name: CreateSessionRepo :exec
+INSERT INTO session_repos (session_id, repo_path, commit_count)
+VALUES (?1, ?2, ?3)
+`
+
+type CreateSessionRepoParams struct {
+	SessionID   string `db:"session_id" json:"session_id"`
+	RepoPath    string `db:"repo_path" json:"repo_path"`
+	CommitCount int64  `db:"commit_count" json:"commit_count"`
+}
+
+func (q *Queries) CreateSessionRepo(ctx context.Context, arg CreateSessionRepoParams) error {
+	_, err := q.db.ExecContext(ctx, createSessionRepo, arg.SessionID, arg.RepoPath, arg.CommitCount)
+	return err
+}
+
 const deleteAllSessions = `-- name: DeleteAllSessions :exec
 DELETE FROM sessions
 `
@@ -64,27 +128,21 @@ func (q *Queries) DeleteAllSessions(ctx context.Context) error {
 	return err
 }
 
-const deleteSessionsByDateRange = `-- name: DeleteSessionsByDateRange :exec
-DELETE FROM sessions
-WHERE (?1 IS NULL OR start_time >= ?1) 
-  AND (?2 IS NULL OR start_time <= ?2)
+const deleteSessionRepos = `-- name: DeleteSessionRepos :exec
+DELETE FROM session_repos
+WHERE session_id = ?1
 `
 
-type DeleteSessionsByDateRangeParams struct {
-	StartDate interface{} `db:"start_date" json:"start_date"`
-	EndDate   interface{} `db:"end_date" json:"end_date"`
-}
-
-func (q *Queries) DeleteSessionsByDateRange(ctx context.Context, arg DeleteSessionsByDateRangeParams) error {
-	_, err := q.db.ExecContext(ctx, deleteSessionsByDateRange, arg.StartDate, arg.EndDate)
+func (q *Queries) DeleteSessionRepos(ctx context.Context, sessionID string) error {
+	_, err := q.db.ExecContext(ctx, deleteSessionRepos, sessionID)
 	return err
 }
 
 const getActiveSession = `-- name: GetActiveSession :one
-SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, c.name as client_name
+SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, s.hostname, s.break_seconds, s.user_id, s.os, s.git_branch, s.deleted_at, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
-WHERE s.end_time IS NULL
+WHERE s.end_time IS NULL AND s.deleted_at IS NULL
 ORDER BY s.start_time DESC
 LIMIT 1
 `
@@ -102,6 +160,12 @@ type GetActiveSessionRow struct {
 	OutsideGit      sql.NullString      `db:"outside_git" json:"outside_git"`
 	InvoiceID       sql.NullString      `db:"invoice_id" json:"invoice_id"`
 	IncludesGst     bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname        sql.NullString      `db:"hostname" json:"hostname"`
+	BreakSeconds    int64               `db:"break_seconds" json:"break_seconds"`
+	UserID          sql.NullString      `db:"user_id" json:"user_id"`
+	Os              sql.NullString      `db:"os" json:"os"`
+	GitBranch       sql.NullString      `db:"git_branch" json:"git_branch"`
+	DeletedAt       sql.NullTime        `db:"deleted_at" json:"deleted_at"`
 	ClientName      string              `db:"client_name" json:"client_name"`
 }
 
@@ -121,16 +185,22 @@ func (q *Queries) GetActiveSession(ctx context.Context) (GetActiveSessionRow, er
 		&i.OutsideGit,
 		&i.InvoiceID,
 		&i.IncludesGst,
+		&i.Hostname,
+		&i.BreakSeconds,
+		&i.UserID,
+		&i.Os,
+		&i.GitBranch,
+		&i.DeletedAt,
 		&i.ClientName,
 	)
 	return i, err
 }
 
 const getSessionByID = `-- name: GetSessionByID :one
-SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, c.name as client_name
+SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, s.hostname, s.break_seconds, s.user_id, s.os, s.git_branch, s.deleted_at, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
-WHERE s.id = ?1
+WHERE s.id = ?1 AND s.deleted_at IS NULL
 `
 
 type GetSessionByIDRow struct {
@@ -146,6 +216,12 @@ type GetSessionByIDRow struct {
 	OutsideGit      sql.NullString      `db:"outside_git" json:"outside_git"`
 	InvoiceID       sql.NullString      `db:"invoice_id" json:"invoice_id"`
 	IncludesGst     bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname        sql.NullString      `db:"hostname" json:"hostname"`
+	BreakSeconds    int64               `db:"break_seconds" json:"break_seconds"`
+	UserID          sql.NullString      `db:"user_id" json:"user_id"`
+	Os              sql.NullString      `db:"os" json:"os"`
+	GitBranch       sql.NullString      `db:"git_branch" json:"git_branch"`
+	DeletedAt       sql.NullTime        `db:"deleted_at" json:"deleted_at"`
 	ClientName      string              `db:"client_name" json:"client_name"`
 }
 
@@ -165,16 +241,22 @@ func (q *Queries) GetSessionByID(ctx context.Context, id string) (GetSessionByID
 		&i.OutsideGit,
 		&i.InvoiceID,
 		&i.IncludesGst,
+		&i.Hostname,
+		&i.BreakSeconds,
+		&i.UserID,
+		&i.Os,
+		&i.GitBranch,
+		&i.DeletedAt,
 		&i.ClientName,
 	)
 	return i, err
 }
 
 const getSessionsByClient = `-- name: GetSessionsByClient :many
-SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, c.name as client_name
+SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, s.hostname, s.break_seconds, s.user_id, s.os, s.git_branch, s.deleted_at, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
-WHERE c.name = ?1
+WHERE c.name = ?1 AND s.deleted_at IS NULL
 ORDER BY s.start_time DESC
 `
 
@@ -191,6 +273,12 @@ type GetSessionsByClientRow struct {
 	OutsideGit      sql.NullString      `db:"outside_git" json:"outside_git"`
 	InvoiceID       sql.NullString      `db:"invoice_id" json:"invoice_id"`
 	IncludesGst     bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname        sql.NullString      `db:"hostname" json:"hostname"`
+	BreakSeconds    int64               `db:"break_seconds" json:"break_seconds"`
+	UserID          sql.NullString      `db:"user_id" json:"user_id"`
+	Os              sql.NullString      `db:"os" json:"os"`
+	GitBranch       sql.NullString      `db:"git_branch" json:"git_branch"`
+	DeletedAt       sql.NullTime        `db:"deleted_at" json:"deleted_at"`
 	ClientName      string              `db:"client_name" json:"client_name"`
 }
 
@@ -216,6 +304,12 @@ func (q *Queries) GetSessionsByClient(ctx context.Context, clientName string) ([
 			&i.OutsideGit,
 			&i.InvoiceID,
 			&i.IncludesGst,
+			&i.Hostname,
+			&i.BreakSeconds,
+			&i.UserID,
+			&i.Os,
+			&i.GitBranch,
+			&i.DeletedAt,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -232,10 +326,10 @@ func (q *Queries) GetSessionsByClient(ctx context.Context, clientName string) ([
 }
 
 const getSessionsByDateRange = `-- name: GetSessionsByDateRange :many
-SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, c.name as client_name
+SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, s.hostname, s.break_seconds, s.user_id, s.os, s.git_branch, s.deleted_at, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
-WHERE s.start_time >= ?1 AND s.start_time <= ?2
+WHERE s.start_time >= ?1 AND s.start_time <= ?2 AND s.deleted_at IS NULL
 ORDER BY s.start_time DESC
 `
 
@@ -257,6 +351,12 @@ type GetSessionsByDateRangeRow struct {
 	OutsideGit      sql.NullString      `db:"outside_git" json:"outside_git"`
 	InvoiceID       sql.NullString      `db:"invoice_id" json:"invoice_id"`
 	IncludesGst     bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname        sql.NullString      `db:"hostname" json:"hostname"`
+	BreakSeconds    int64               `db:"break_seconds" json:"break_seconds"`
+	UserID          sql.NullString      `db:"user_id" json:"user_id"`
+	Os              sql.NullString      `db:"os" json:"os"`
+	GitBranch       sql.NullString      `db:"git_branch" json:"git_branch"`
+	DeletedAt       sql.NullTime        `db:"deleted_at" json:"deleted_at"`
 	ClientName      string              `db:"client_name" json:"client_name"`
 }
 
@@ -282,6 +382,12 @@ func (q *Queries) GetSessionsByDateRange(ctx context.Context, arg GetSessionsByD
 			&i.OutsideGit,
 			&i.InvoiceID,
 			&i.IncludesGst,
+			&i.Hostname,
+			&i.BreakSeconds,
+			&i.UserID,
+			&i.Os,
+			&i.GitBranch,
+			&i.DeletedAt,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -298,10 +404,10 @@ func (q *Queries) GetSessionsByDateRange(ctx context.Context, arg GetSessionsByD
 }
 
 const getSessionsWithoutDescription = `-- name: GetSessionsWithoutDescription :many
-select s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, c.name as client_name
+select s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, s.hostname, s.break_seconds, s.user_id, s.os, s.git_branch, s.deleted_at, c.name as client_name
 from sessions s
 join clients c on s.client_id = c.id
-where s.end_time is not null 
+where s.end_time is not null and s.deleted_at is null 
   and (s.description is null or s.description = '')
   and (?1 is null or c.name = ?1)
   and (?2 is null or s.id = ?2)
@@ -326,6 +432,12 @@ type GetSessionsWithoutDescriptionRow struct {
 	OutsideGit      sql.NullString      `db:"outside_git" json:"outside_git"`
 	InvoiceID       sql.NullString      `db:"invoice_id" json:"invoice_id"`
 	IncludesGst     bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname        sql.NullString      `db:"hostname" json:"hostname"`
+	BreakSeconds    int64               `db:"break_seconds" json:"break_seconds"`
+	UserID          sql.NullString      `db:"user_id" json:"user_id"`
+	Os              sql.NullString      `db:"os" json:"os"`
+	GitBranch       sql.NullString      `db:"git_branch" json:"git_branch"`
+	DeletedAt       sql.NullTime        `db:"deleted_at" json:"deleted_at"`
 	ClientName      string              `db:"client_name" json:"client_name"`
 }
 
@@ -351,6 +463,12 @@ func (q *Queries) GetSessionsWithoutDescription(ctx context.Context, arg GetSess
 			&i.OutsideGit,
 			&i.InvoiceID,
 			&i.IncludesGst,
+			&i.Hostname,
+			&i.BreakSeconds,
+			&i.UserID,
+			&i.Os,
+			&i.GitBranch,
+			&i.DeletedAt,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -366,10 +484,46 @@ func (q *Queries) GetSessionsWithoutDescription(ctx context.Context, arg GetSess
 	return items, nil
 }
 
+const listInvoiceSessionRepos = `-- name: ListInvoiceSessionRepos :many
+SELECT sr.session_id, sr.repo_path, sr.commit_count, sr.created_at FROM session_repos sr
+JOIN sessions s ON sr.session_id = s.id
+WHERE s.invoice_id = ?1 AND s.deleted_at IS NULL
+ORDER BY s.start_time, sr.repo_path
+`
+
+func (q *Queries) ListInvoiceSessionRepos(ctx context.Context, invoiceID string) ([]SessionRepo, error) {
+	rows, err := q.db.QueryContext(ctx, listInvoiceSessionRepos, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SessionRepo
+	for rows.Next() {
+		var i SessionRepo
+		if err := rows.Scan(
+			&i.SessionID,
+			&i.RepoPath,
+			&i.CommitCount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listRecentSessions = `-- name: ListRecentSessions :many
-SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, c.name as client_name
+SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, s.hostname, s.break_seconds, s.user_id, s.os, s.git_branch, s.deleted_at, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
+WHERE s.deleted_at IS NULL
 ORDER BY s.start_time DESC
 LIMIT ?1
 `
@@ -387,6 +541,12 @@ type ListRecentSessionsRow struct {
 	OutsideGit      sql.NullString      `db:"outside_git" json:"outside_git"`
 	InvoiceID       sql.NullString      `db:"invoice_id" json:"invoice_id"`
 	IncludesGst     bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname        sql.NullString      `db:"hostname" json:"hostname"`
+	BreakSeconds    int64               `db:"break_seconds" json:"break_seconds"`
+	UserID          sql.NullString      `db:"user_id" json:"user_id"`
+	Os              sql.NullString      `db:"os" json:"os"`
+	GitBranch       sql.NullString      `db:"git_branch" json:"git_branch"`
+	DeletedAt       sql.NullTime        `db:"deleted_at" json:"deleted_at"`
 	ClientName      string              `db:"client_name" json:"client_name"`
 }
 
@@ -412,6 +572,12 @@ func (q *Queries) ListRecentSessions(ctx context.Context, limitCount int64) ([]L
 			&i.OutsideGit,
 			&i.InvoiceID,
 			&i.IncludesGst,
+			&i.Hostname,
+			&i.BreakSeconds,
+			&i.UserID,
+			&i.Os,
+			&i.GitBranch,
+			&i.DeletedAt,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -427,13 +593,120 @@ func (q *Queries) ListRecentSessions(ctx context.Context, limitCount int64) ([]L
 	return items, nil
 }
 
+const listSessionBreaks = `-- name: ListSessionBreaks :many
+SELECT id, session_id, start_time, end_time, source, created_at FROM session_breaks
+WHERE session_id = ?1
+ORDER BY start_time
+`
+
+func (q *Queries) ListSessionBreaks(ctx context.Context, sessionID string) ([]SessionBreak, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionBreaks, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SessionBreak
+	for rows.Next() {
+		var i SessionBreak
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Source,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSessionIDsByPrefix = `-- name: ListSessionIDsByPrefix :many
+SELECT id
+FROM sessions
+WHERE id LIKE ?1 || '%' AND deleted_at IS NULL
+ORDER BY start_time DESC
+LIMIT ?2
+`
+
+type ListSessionIDsByPrefixParams struct {
+	Prefix     interface{} `db:"prefix" json:"prefix"`
+	LimitCount int64       `db:"limit_count" json:"limit_count"`
+}
+
+func (q *Queries) ListSessionIDsByPrefix(ctx context.Context, arg ListSessionIDsByPrefixParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionIDsByPrefix, arg.Prefix, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSessionRepos = `-- name: ListSessionRepos :many
+SELECT session_id, repo_path, commit_count, created_at FROM session_repos
+WHERE session_id = ?1
+ORDER BY repo_path
+`
+
+func (q *Queries) ListSessionRepos(ctx context.Context, sessionID string) ([]SessionRepo, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionRepos, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SessionRepo
+	for rows.Next() {
+		var i SessionRepo
+		if err := rows.Scan(
+			&i.SessionID,
+			&i.RepoPath,
+			&i.CommitCount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSessionsWithDateRange = `-- name: ListSessionsWithDateRange :many
-SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, c.name as client_name
+SELECT s.id, s.client_id, s.start_time, s.end_time, s.description, s.created_at, s.updated_at, s.hourly_rate, s.full_work_summary, s.outside_git, s.invoice_id, s.includes_gst, s.hostname, s.break_seconds, s.user_id, s.os, s.git_branch, s.deleted_at, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
 WHERE (?1 IS NULL OR s.start_time >= ?1) 
   AND (?2 IS NULL OR s.start_time <= ?2)
   AND (?3 IS NULL OR c.name = ?3)
+  AND s.deleted_at IS NULL
 ORDER BY s.start_time DESC
 LIMIT ?4
 `
@@ -458,6 +731,12 @@ type ListSessionsWithDateRangeRow struct {
 	OutsideGit      sql.NullString      `db:"outside_git" json:"outside_git"`
 	InvoiceID       sql.NullString      `db:"invoice_id" json:"invoice_id"`
 	IncludesGst     bool                `db:"includes_gst" json:"includes_gst"`
+	Hostname        sql.NullString      `db:"hostname" json:"hostname"`
+	BreakSeconds    int64               `db:"break_seconds" json:"break_seconds"`
+	UserID          sql.NullString      `db:"user_id" json:"user_id"`
+	Os              sql.NullString      `db:"os" json:"os"`
+	GitBranch       sql.NullString      `db:"git_branch" json:"git_branch"`
+	DeletedAt       sql.NullTime        `db:"deleted_at" json:"deleted_at"`
 	ClientName      string              `db:"client_name" json:"client_name"`
 }
 
@@ -488,6 +767,12 @@ func (q *Queries) ListSessionsWithDateRange(ctx context.Context, arg ListSession
 			&i.OutsideGit,
 			&i.InvoiceID,
 			&i.IncludesGst,
+			&i.Hostname,
+			&i.BreakSeconds,
+			&i.UserID,
+			&i.Os,
+			&i.GitBranch,
+			&i.DeletedAt,
 			&i.ClientName,
 		); err != nil {
 			return nil, err
@@ -506,8 +791,8 @@ func (q *Queries) ListSessionsWithDateRange(ctx context.Context, arg ListSession
 const stopSession = `-- name: StopSession :one
 UPDATE sessions
 SET end_time = ?1
-WHERE id = ?2
-RETURNING id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst
+WHERE id = ?2 AND deleted_at IS NULL
+RETURNING id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst, hostname, break_seconds, user_id, os, git_branch, deleted_at
 `
 
 type StopSessionParams struct {
@@ -531,15 +816,50 @@ func (q *Queries) StopSession(ctx context.Context, arg StopSessionParams) (Sessi
 		&i.OutsideGit,
 		&i.InvoiceID,
 		&i.IncludesGst,
+		&i.Hostname,
+		&i.BreakSeconds,
+		&i.UserID,
+		&i.Os,
+		&i.GitBranch,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const trashSessionsByDateRange = `-- name: TrashSessionsByDateRange :execrows
+UPDATE sessions
+SET deleted_at = ?1
+WHERE (?2 IS NULL OR start_time >= ?2) 
+  AND (?3 IS NULL OR start_time <= ?3)
+  AND (?4 OR invoice_id IS NULL OR invoice_id NOT IN (SELECT id FROM invoices))
+  AND deleted_at IS NULL
+`
+
+type TrashSessionsByDateRangeParams struct {
+	DeletedAt       sql.NullTime `db:"deleted_at" json:"deleted_at"`
+	StartDate       interface{}  `db:"start_date" json:"start_date"`
+	EndDate         interface{}  `db:"end_date" json:"end_date"`
+	IncludeInvoiced interface{}  `db:"include_invoiced" json:"include_invoiced"`
+}
+
+func (q *Queries) TrashSessionsByDateRange(ctx context.Context, arg TrashSessionsByDateRangeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trashSessionsByDateRange,
+		arg.DeletedAt,
+		arg.StartDate,
+		arg.EndDate,
+		arg.IncludeInvoiced,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateSessionDescription = `-- name: UpdateSessionDescription :one
 UPDATE sessions
 SET description = ?1, full_work_summary = ?2
-WHERE id = ?3
-RETURNING id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst
+WHERE id = ?3 AND deleted_at IS NULL
+RETURNING id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst, hostname, break_seconds, user_id, os, git_branch, deleted_at
 `
 
 type UpdateSessionDescriptionParams struct {
@@ -564,6 +884,12 @@ func (q *Queries) UpdateSessionDescription(ctx context.Context, arg UpdateSessio
 		&i.OutsideGit,
 		&i.InvoiceID,
 		&i.IncludesGst,
+		&i.Hostname,
+		&i.BreakSeconds,
+		&i.UserID,
+		&i.Os,
+		&i.GitBranch,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -571,8 +897,8 @@ func (q *Queries) UpdateSessionDescription(ctx context.Context, arg UpdateSessio
 const updateSessionOutsideGit = `-- name: UpdateSessionOutsideGit :one
 UPDATE sessions
 SET outside_git = ?1
-WHERE id = ?2
-RETURNING id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst
+WHERE id = ?2 AND deleted_at IS NULL
+RETURNING id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst, hostname, break_seconds, user_id, os, git_branch, deleted_at
 `
 
 type UpdateSessionOutsideGitParams struct {
@@ -596,6 +922,128 @@ func (q *Queries) UpdateSessionOutsideGit(ctx context.Context, arg UpdateSession
 		&i.OutsideGit,
 		&i.InvoiceID,
 		&i.IncludesGst,
+		&i.Hostname,
+		&i.BreakSeconds,
+		&i.UserID,
+		&i.Os,
+		&i.GitBranch,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateSessionRate = `-- name: UpdateSessionRate :one
+UPDATE sessions
+SET hourly_rate = ?1, includes_gst = ?2
+WHERE id = ?3 AND deleted_at IS NULL
+RETURNING id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst, hostname, break_seconds, user_id, os, git_branch, deleted_at
+`
+
+type UpdateSessionRateParams struct {
+	HourlyRate  decimal.NullDecimal `db:"hourly_rate" json:"hourly_rate"`
+	IncludesGst bool                `db:"includes_gst" json:"includes_gst"`
+	ID          string              `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateSessionRate(ctx context.Context, arg UpdateSessionRateParams) (Session, error) {
+	row := q.db.QueryRowContext(ctx, updateSessionRate, arg.HourlyRate, arg.IncludesGst, arg.ID)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ClientID,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HourlyRate,
+		&i.FullWorkSummary,
+		&i.OutsideGit,
+		&i.InvoiceID,
+		&i.IncludesGst,
+		&i.Hostname,
+		&i.BreakSeconds,
+		&i.UserID,
+		&i.Os,
+		&i.GitBranch,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateSessionTimes = `-- name: UpdateSessionTimes :one
+UPDATE sessions
+SET start_time = ?1, end_time = ?2
+WHERE id = ?3 AND deleted_at IS NULL
+RETURNING id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst, hostname, break_seconds, user_id, os, git_branch, deleted_at
+`
+
+type UpdateSessionTimesParams struct {
+	StartTime time.Time    `db:"start_time" json:"start_time"`
+	EndTime   sql.NullTime `db:"end_time" json:"end_time"`
+	ID        string       `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateSessionTimes(ctx context.Context, arg UpdateSessionTimesParams) (Session, error) {
+	row := q.db.QueryRowContext(ctx, updateSessionTimes, arg.StartTime, arg.EndTime, arg.ID)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ClientID,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HourlyRate,
+		&i.FullWorkSummary,
+		&i.OutsideGit,
+		&i.InvoiceID,
+		&i.IncludesGst,
+		&i.Hostname,
+		&i.BreakSeconds,
+		&i.UserID,
+		&i.Os,
+		&i.GitBranch,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateSessionUser = `-- name: UpdateSessionUser :one
+UPDATE sessions
+SET user_id = ?1
+WHERE id = ?2 AND deleted_at IS NULL
+RETURNING id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst, hostname, break_seconds, user_id, os, git_branch, deleted_at
+`
+
+type UpdateSessionUserParams struct {
+	UserID sql.NullString `db:"user_id" json:"user_id"`
+	ID     string         `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateSessionUser(ctx context.Context, arg UpdateSessionUserParams) (Session, error) {
+	row := q.db.QueryRowContext(ctx, updateSessionUser, arg.UserID, arg.ID)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ClientID,
+		&i.StartTime,
+		&i.EndTime,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.HourlyRate,
+		&i.FullWorkSummary,
+		&i.OutsideGit,
+		&i.InvoiceID,
+		&i.IncludesGst,
+		&i.Hostname,
+		&i.BreakSeconds,
+		&i.UserID,
+		&i.Os,
+		&i.GitBranch,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,158 @@
+// Package heatmap renders a year of daily tracked hours as a contribution calendar, a column of days for each
+// week coloured by how much was worked, as an SVG or PNG image.
+package heatmap
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Calendar is a year of hours to draw
+type Calendar struct {
+	Year  int
+	Title string
+	Hours map[string]float64 // hours worked on each day, keyed by its date as YYYY-MM-DD
+	// Format shows an amount of hours in a day's tooltip, defaulting to decimal hours such as 6.5h
+	Format func(hours float64) string
+}
+
+// Layout, in pixels of the SVG or of the PNG before it's scaled up
+const (
+	cellSize     = 11
+	cellStep     = 14 // cellSize and the gap between cells
+	leftMargin   = 32 // weekday labels
+	topMargin    = 40 // title and month labels
+	rightMargin  = 12
+	bottomMargin = 30 // legend
+)
+
+// palette colours days with nothing tracked and then each level of hours, lightest to darkest
+var palette = []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+const textColour = "#24292f"
+
+// cell is a day drawn at x, y
+type cell struct {
+	x, y  int
+	date  time.Time
+	hours float64
+	level int
+}
+
+// label is text drawn with its baseline starting at x, y
+type label struct {
+	x, y int
+	text string
+}
+
+// layout places every day of the calendar's year in a column for its week, Monday at the top, and labels the
+// months and weekdays
+type layout struct {
+	width, height int
+	cells         []cell
+	labels        []label
+	legend        []cell // one cell of each level, between "Less" and "More"
+}
+
+func (c Calendar) layout() layout {
+	first := time.Date(c.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(c.Year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	// The first column starts on the Monday of the week the year starts in
+	start := first.AddDate(0, 0, -weekdayRow(first))
+	weeks := int(last.Sub(start).Hours()/24)/7 + 1
+
+	most := 0.0
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		most = math.Max(most, c.Hours[day.Format("2006-01-02")])
+	}
+
+	l := layout{
+		width:  leftMargin + weeks*cellStep + rightMargin,
+		height: topMargin + 7*cellStep + bottomMargin,
+		labels: []label{{0, 14, c.Title}},
+	}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		week := int(day.Sub(start).Hours()/24) / 7
+		x := leftMargin + week*cellStep
+		hours := c.Hours[day.Format("2006-01-02")]
+		l.cells = append(l.cells, cell{x: x, y: topMargin + weekdayRow(day)*cellStep, date: day, hours: hours, level: level(hours, most)})
+		if day.Day() == 1 {
+			l.labels = append(l.labels, label{x, topMargin - 6, day.Format("Jan")})
+		}
+	}
+	for row, name := range []string{"Mon", "", "Wed", "", "Fri"} {
+		if name != "" {
+			l.labels = append(l.labels, label{0, topMargin + row*cellStep + cellSize - 1, name})
+		}
+	}
+
+	legendY := topMargin + 7*cellStep + 10
+	legendX := l.width - rightMargin - 30 - len(palette)*cellStep
+	l.labels = append(l.labels, label{legendX - 30, legendY + cellSize - 1, "Less"})
+	for i := range palette {
+		l.legend = append(l.legend, cell{x: legendX + i*cellStep, y: legendY, level: i})
+	}
+	l.labels = append(l.labels, label{legendX + len(palette)*cellStep + 4, legendY + cellSize - 1, "More"})
+	return l
+}
+
+// weekdayRow is the row a day is drawn in, Monday first
+func weekdayRow(day time.Time) int {
+	return (int(day.Weekday()) + 6) % 7
+}
+
+// level is the shade a day's hours are drawn in, a quarter of the most hours worked in a day apart
+func level(hours, most float64) int {
+	if hours <= 0 || most <= 0 {
+		return 0
+	}
+	return min(max(int(math.Ceil(hours/most*4)), 1), len(palette)-1)
+}
+
+func (c Calendar) format(hours float64) string {
+	if c.Format != nil {
+		return c.Format(hours)
+	}
+	return strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+}
+
+// WriteSVG draws the calendar as an SVG image, with each day's date and hours shown when it's hovered over
+func WriteSVG(w io.Writer, c Calendar) error {
+	l := c.layout()
+	p := &errWriter{w: w}
+	p.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="-apple-system, Segoe UI, Helvetica, Arial, sans-serif" font-size="10">`+"\n",
+		l.width, l.height, l.width, l.height)
+	p.printf(`<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", l.width, l.height)
+	for i, text := range l.labels {
+		size := ""
+		if i == 0 {
+			size = ` font-size="12" font-weight="600"`
+		}
+		p.printf(`<text x="%d" y="%d" fill="%s"%s>%s</text>`+"\n", text.x, text.y, textColour, size, html.EscapeString(text.text))
+	}
+	for _, day := range l.cells {
+		p.printf(`<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s: %s</title></rect>`+"\n",
+			day.x, day.y, cellSize, cellSize, palette[day.level], day.date.Format("Mon 2 Jan 2006"), html.EscapeString(c.format(day.hours)))
+	}
+	for _, key := range l.legend {
+		p.printf(`<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"/>`+"\n", key.x, key.y, cellSize, cellSize, palette[key.level])
+	}
+	p.printf("</svg>\n")
+	return p.err
+}
+
+// errWriter keeps the first error writing, so a document can be written without checking each part
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *errWriter) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
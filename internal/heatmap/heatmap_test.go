@@ -0,0 +1,65 @@
+package heatmap
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestLayoutPlacesEveryDay(t *testing.T) {
+	// 2024 is a leap year starting on a Monday and ending on a Tuesday, across 53 weeks
+	l := Calendar{Year: 2024, Hours: map[string]float64{"2024-03-04": 2, "2024-03-05": 8}}.layout()
+	if len(l.cells) != 366 {
+		t.Fatalf("laid out %d days, want 366", len(l.cells))
+	}
+	first, last := l.cells[0], l.cells[len(l.cells)-1]
+	if first.x != leftMargin || first.y != topMargin {
+		t.Errorf("1 Jan drawn at %d,%d, want the top of the first column at %d,%d", first.x, first.y, leftMargin, topMargin)
+	}
+	if want := leftMargin + 52*cellStep; last.x != want || last.y != topMargin+cellStep {
+		t.Errorf("31 Dec drawn at %d,%d, want %d,%d", last.x, last.y, want, topMargin+cellStep)
+	}
+	for _, day := range l.cells {
+		want := 0
+		switch day.date.Format("2006-01-02") {
+		case "2024-03-04":
+			want = 1
+		case "2024-03-05":
+			want = 4
+		}
+		if day.level != want {
+			t.Errorf("%s shaded %d, want %d", day.date.Format("2006-01-02"), day.level, want)
+		}
+	}
+}
+
+func TestWriteSVGAndPNG(t *testing.T) {
+	c := Calendar{Year: 2025, Title: "2025 <review>", Hours: map[string]float64{"2025-06-02": 6.5}}
+
+	var svg bytes.Buffer
+	if err := WriteSVG(&svg, c); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(svg.String(), "<title>"); n != 365 {
+		t.Errorf("SVG has %d days, want 365", n)
+	}
+	for _, want := range []string{"<title>Mon 2 Jun 2025: 6.5h</title>", "2025 &lt;review&gt;"} {
+		if !strings.Contains(svg.String(), want) {
+			t.Errorf("SVG doesn't contain %q", want)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := WritePNG(&out, c); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := c.layout()
+	if b := img.Bounds(); b.Dx() != l.width*pngScale || b.Dy() != l.height*pngScale {
+		t.Errorf("PNG is %dx%d, want %dx%d", b.Dx(), b.Dy(), l.width*pngScale, l.height*pngScale)
+	}
+}
@@ -0,0 +1,117 @@
+package heatmap
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// pngScale is how many pixels of the PNG each pixel of the layout takes, so it stays sharp on high density
+// screens and in documents
+const pngScale = 2
+
+// WritePNG draws the calendar as a PNG image. Text is drawn in a small built-in capitals font, so it needs no
+// fonts installed.
+func WritePNG(w io.Writer, c Calendar) error {
+	l := c.layout()
+	img := image.NewRGBA(image.Rect(0, 0, l.width*pngScale, l.height*pngScale))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	text := hexColour(textColour)
+	for _, t := range l.labels {
+		drawText(img, t.x, t.y, t.text, text)
+	}
+	for _, day := range append(l.cells, l.legend...) {
+		fill := image.NewUniform(hexColour(palette[day.level]))
+		draw.Draw(img, image.Rect(day.x*pngScale, day.y*pngScale, (day.x+cellSize)*pngScale, (day.y+cellSize)*pngScale), fill, image.Point{}, draw.Src)
+	}
+
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("failed to encode heatmap PNG: %w", err)
+	}
+	return nil
+}
+
+// hexColour reads a colour written as #rrggbb
+func hexColour(hex string) color.RGBA {
+	v, _ := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
+
+// drawText draws text in capitals with its baseline starting at x, y. Characters the font doesn't have are
+// left as spaces.
+func drawText(img *image.RGBA, x, y int, text string, c color.RGBA) {
+	top := y - glyphHeight + 1
+	for _, r := range strings.ToUpper(text) {
+		glyph, ok := font[r]
+		if ok {
+			for row, line := range glyph {
+				for col, bit := range line {
+					if bit != '#' {
+						continue
+					}
+					px, py := (x+col)*pngScale, (top+row)*pngScale
+					draw.Draw(img, image.Rect(px, py, px+pngScale, py+pngScale), image.NewUniform(c), image.Point{}, draw.Src)
+				}
+			}
+		}
+		x += glyphWidth + 1
+	}
+}
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// font is a 5x7 pixel font of the capitals, digits and punctuation used in labels and titles
+var font = map[rune][glyphHeight]string{
+	'A': {".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'B': {"####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."},
+	'C': {".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."},
+	'D': {"####.", "#...#", "#...#", "#...#", "#...#", "#...#", "####."},
+	'E': {"#####", "#....", "#....", "####.", "#....", "#....", "#####"},
+	'F': {"#####", "#....", "#....", "####.", "#....", "#....", "#...."},
+	'G': {".###.", "#...#", "#....", "#.###", "#...#", "#...#", ".###."},
+	'H': {"#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'I': {".###.", "..#..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'J': {"..###", "...#.", "...#.", "...#.", "...#.", "#..#.", ".##.."},
+	'K': {"#...#", "#..#.", "#.#..", "##...", "#.#..", "#..#.", "#...#"},
+	'L': {"#....", "#....", "#....", "#....", "#....", "#....", "#####"},
+	'M': {"#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"},
+	'N': {"#...#", "#...#", "##..#", "#.#.#", "#..##", "#...#", "#...#"},
+	'O': {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'P': {"####.", "#...#", "#...#", "####.", "#....", "#....", "#...."},
+	'Q': {".###.", "#...#", "#...#", "#...#", "#.#.#", "#..#.", ".##.#"},
+	'R': {"####.", "#...#", "#...#", "####.", "#.#..", "#..#.", "#...#"},
+	'S': {".####", "#....", "#....", ".###.", "....#", "....#", "####."},
+	'T': {"#####", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'U': {"#...#", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'V': {"#...#", "#...#", "#...#", "#...#", "#...#", ".#.#.", "..#.."},
+	'W': {"#...#", "#...#", "#...#", "#.#.#", "#.#.#", "#.#.#", ".#.#."},
+	'X': {"#...#", "#...#", ".#.#.", "..#..", ".#.#.", "#...#", "#...#"},
+	'Y': {"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#..", "..#.."},
+	'Z': {"#####", "....#", "...#.", "..#..", ".#...", "#....", "#####"},
+	'0': {".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."},
+	'1': {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'2': {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	'3': {"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+	'4': {"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+	'5': {"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+	'6': {"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+	'7': {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+	'8': {".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+	'9': {".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+	':': {".....", ".##..", ".##..", ".....", ".##..", ".##..", "....."},
+	'.': {".....", ".....", ".....", ".....", ".....", ".##..", ".##.."},
+	',': {".....", ".....", ".....", ".....", ".##..", "..#..", ".#..."},
+	'-': {".....", ".....", ".....", "#####", ".....", ".....", "....."},
+	'(': {"...#.", "..#..", ".#...", ".#...", ".#...", "..#..", "...#."},
+	')': {".#...", "..#..", "...#.", "...#.", "...#.", "..#..", ".#..."},
+	'/': {".....", "....#", "...#.", "..#..", ".#...", "#....", "....."},
+}
@@ -0,0 +1,69 @@
+// Package lock keeps commands that change the database from running at the same time, such as `work start` and
+// `work stop` in two terminals. It uses an operating system file lock, so a crashed command never leaves it held.
+package lock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrLocked is returned when another command holds the lock
+var ErrLocked = errors.New("another work command is running")
+
+// pollInterval is how often a held lock is retried while waiting for it
+const pollInterval = 100 * time.Millisecond
+
+// Lock is an acquired lock, held until it's released or the process exits
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes the lock at path, waiting up to wait for another command to finish with it. The error wraps
+// ErrLocked, naming the process holding it when that's known, if it isn't released in time.
+func Acquire(path string, wait time.Duration) (*Lock, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		file, err := lockFile(path)
+		if err == nil {
+			// Recorded so a command kept waiting can say who it's waiting on
+			if err := file.Truncate(0); err == nil {
+				_, _ = file.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+			}
+			return &Lock{file: file}, nil
+		}
+		if !errors.Is(err, ErrLocked) {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			if pid := holder(path); pid != "" {
+				return nil, fmt.Errorf("%w (process %s), try again once it finishes", ErrLocked, pid)
+			}
+			return nil, fmt.Errorf("%w, try again once it finishes", ErrLocked)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Release lets other commands take the lock. The file is left in place, as removing it could let two commands
+// lock different files at the same path.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// holder reads the process ID written by whoever holds the lock, or "" when it can't be read
+func holder(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
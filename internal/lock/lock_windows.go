@@ -0,0 +1,28 @@
+//go:build windows
+
+package lock
+
+import (
+	"os"
+	"syscall"
+)
+
+// errorSharingViolation is returned when opening a file another process has opened without sharing it
+const errorSharingViolation syscall.Errno = 32
+
+// lockFile opens path without sharing it, so no other process can open it until it's closed
+func lockFile(path string) (*os.File, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil,
+		syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		if err == errorSharingViolation {
+			return nil, ErrLocked
+		}
+		return nil, err
+	}
+	return os.NewFile(uintptr(handle), path), nil
+}
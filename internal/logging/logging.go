@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// Level returns the log level for the global --quiet and --verbose flags
+func Level(quiet, verbose bool) slog.Level {
+	switch {
+	case quiet:
+		return slog.LevelWarn
+	case verbose:
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// New returns a logger writing key=value lines to w, without timestamps since output is read live
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// Terminal serialises writes from concurrent goroutines and, when writing to a terminal, keeps a
+// single status line (such as a progress display) pinned below everything else that's written
+type Terminal struct {
+	mu     sync.Mutex
+	out    io.Writer
+	tty    bool
+	status string
+}
+
+// NewTerminal returns a Terminal writing to f, with status lines enabled if f is a terminal
+func NewTerminal(f *os.File) *Terminal {
+	tty := false
+	if info, err := f.Stat(); err == nil {
+		tty = info.Mode()&os.ModeCharDevice != 0
+	}
+	return &Terminal{out: f, tty: tty}
+}
+
+// IsTTY reports whether status lines will be shown
+func (t *Terminal) IsTTY() bool {
+	return t.tty
+}
+
+// Write writes p above the current status line
+func (t *Terminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.status != "" {
+		io.WriteString(t.out, "\r\033[K")
+	}
+	n, err := t.out.Write(p)
+	if t.status != "" {
+		io.WriteString(t.out, t.status)
+	}
+	return n, err
+}
+
+// SetStatus replaces the status line. It does nothing when not writing to a terminal.
+func (t *Terminal) SetStatus(line string) {
+	if !t.tty {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.status = line
+	io.WriteString(t.out, "\r\033[K"+line)
+}
+
+// ClearStatus removes the status line
+func (t *Terminal) ClearStatus() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.status != "" {
+		io.WriteString(t.out, "\r\033[K")
+		t.status = ""
+	}
+}
@@ -0,0 +1,87 @@
+// Package mail sends email through an SMTP server, such as a monthly report for your own records.
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+// Sender sends email from one address through an SMTP server
+type Sender struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+// NewSender returns a sender for the SMTP server at host:port. Mail is authenticated with username and password
+// when a username is given, which net/smtp only sends over TLS or to localhost.
+func NewSender(host string, port int, username, password, from string) *Sender {
+	return &Sender{host: host, port: port, username: username, password: password, from: from}
+}
+
+// Send emails a message with a plain text body and an HTML alternative, which mail clients show instead when
+// they can
+func (s *Sender) Send(to, subject, text, html string) error {
+	msg, err := s.message(to, subject, text, html, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	if err := smtp.SendMail(addr, auth, s.from, []string{to}, msg); err != nil {
+		return fmt.Errorf("failed to send email to %s through %s: %w", to, addr, err)
+	}
+	return nil
+}
+
+// message builds a multipart/alternative message, with the text part first as RFC 2046 asks
+func (s *Sender) message(to, subject, text, html string, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build email: %w", err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
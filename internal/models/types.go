@@ -26,8 +26,68 @@ type Client struct {
 	RetainerAmount *decimal.Decimal `json:"retainer_amount,omitempty" db:"retainer_amount"`
 	RetainerHours  *float64         `json:"retainer_hours,omitempty" db:"retainer_hours"`
 	RetainerBasis  *string          `json:"retainer_basis,omitempty" db:"retainer_basis"`
-	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time        `json:"updated_at" db:"updated_at"`
+	Currency       *string          `json:"currency,omitempty" db:"currency"`
+	Locale         *string          `json:"locale,omitempty" db:"locale"`
+	Source         *string          `json:"source,omitempty" db:"source"`
+	WorkLog        *string          `json:"work_log,omitempty" db:"work_log"`
+	RetainerStart  *time.Time       `json:"retainer_start_date,omitempty" db:"retainer_start_date"`
+	// Early payment discount terms, e.g. 2% off when paid within 7 days of the invoice being issued
+	EarlyDiscountPercent *float64 `json:"early_discount_percent,omitempty" db:"early_discount_percent"`
+	EarlyDiscountDays    *int64   `json:"early_discount_days,omitempty" db:"early_discount_days"`
+	// Printed in the Notes section of the client's invoices, e.g. a PO number and "Payment due within 14 days"
+	InvoiceNotes *string `json:"invoice_notes,omitempty" db:"invoice_notes"`
+	PaymentTerms *string `json:"payment_terms,omitempty" db:"payment_terms"`
+	// Default purchase order number and project code printed on the client's invoices
+	PoNumber    *string `json:"po_number,omitempty" db:"po_number"`
+	ProjectCode *string `json:"project_code,omitempty" db:"project_code"`
+	// InvoiceRounding is what invoice totals are rounded to the nearest multiple of, e.g. 1 or 5 dollars
+	InvoiceRounding *decimal.Decimal `json:"invoice_rounding,omitempty" db:"invoice_rounding"`
+	// TaxTreatment is TaxTreatmentTaxable, TaxTreatmentGSTFree or TaxTreatmentExport
+	TaxTreatment string `json:"tax_treatment,omitempty" db:"tax_treatment"`
+	// LogoPath is a PNG, JPEG or GIF printed on the client's invoices instead of BILLING_LOGO
+	LogoPath *string `json:"logo_path,omitempty" db:"logo_path"`
+	// BrandColour is the #rrggbb colour of headings on the client's invoices instead of BILLING_BRAND_COLOUR
+	BrandColour *string `json:"brand_colour,omitempty" db:"brand_colour"`
+	// How much git history the AI is given to describe the client's sessions, instead of GIT_ANALYSIS_MAX_COMMITS,
+	// GIT_ANALYSIS_IGNORE and GIT_ANALYSIS_DETAIL. AnalysisIgnore is comma separated.
+	AnalysisMaxCommits *int64  `json:"analysis_max_commits,omitempty" db:"analysis_max_commits"`
+	AnalysisIgnore     *string `json:"analysis_ignore,omitempty" db:"analysis_ignore"`
+	AnalysisDetail     *string `json:"analysis_detail,omitempty" db:"analysis_detail"`
+	// AnalysisPrompt describes the client's sessions from git instead of GIT_ANALYSIS_PROMPT, for the style they want
+	AnalysisPrompt *string   `json:"analysis_prompt,omitempty" db:"analysis_prompt"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// How GST applies to a client's invoices. Only taxable clients are charged GST, and GST-free and export sales are
+// reported on their own BAS labels.
+const (
+	TaxTreatmentTaxable = "taxable"
+	TaxTreatmentGSTFree = "gst_free"
+	TaxTreatmentExport  = "export"
+)
+
+// ClientRate is a change to a client's hourly rate from the day it takes effect, stored as midnight local time.
+// Sessions are billed at the rate in effect on the day they start.
+type ClientRate struct {
+	ID            string          `json:"id" db:"id"`
+	ClientID      string          `json:"client_id" db:"client_id"`
+	PreviousRate  decimal.Decimal `json:"previous_rate" db:"previous_rate"`
+	HourlyRate    decimal.Decimal `json:"hourly_rate" db:"hourly_rate"`
+	EffectiveDate time.Time       `json:"effective_date" db:"effective_date"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+
+	ClientName string `json:"client_name,omitempty" db:"client_name"`
+}
+
+// ClientActivity sums up a client's sessions and invoices over their lifetime
+type ClientActivity struct {
+	SessionCount  int64           `json:"session_count"`
+	TotalHours    float64         `json:"total_hours"`
+	LastSession   *time.Time      `json:"last_session,omitempty"`
+	InvoiceCount  int64           `json:"invoice_count"`
+	TotalInvoiced decimal.Decimal `json:"total_invoiced"`
+	Outstanding   decimal.Decimal `json:"outstanding"`
 }
 
 type WorkSession struct {
@@ -41,6 +101,12 @@ type WorkSession struct {
 	OutsideGit      *string          `json:"outside_git,omitempty" db:"outside_git"`
 	InvoiceID       *string          `json:"invoice_id,omitempty" db:"invoice_id"`
 	IncludesGst     bool             `json:"includes_gst" db:"includes_gst"`
+	Hostname        *string          `json:"hostname,omitempty" db:"hostname"`
+	OS              *string          `json:"os,omitempty" db:"os"`                       // e.g. linux or darwin
+	GitBranch       *string          `json:"git_branch,omitempty" db:"git_branch"`       // checked out where the session was started
+	BreakSeconds    int64            `json:"break_seconds,omitempty" db:"break_seconds"` // idle time taken out of the session
+	UserID          *string          `json:"user_id,omitempty" db:"user_id"`             // who did the work, nil for the owner
+	DeletedAt       *time.Time       `json:"deleted_at,omitempty" db:"deleted_at"`       // when it was moved to the trash
 	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
 
@@ -48,36 +114,224 @@ type WorkSession struct {
 }
 
 type Invoice struct {
-	ID              string          `json:"id" db:"id"`
-	ClientID        string          `json:"client_id" db:"client_id"`
-	InvoiceNumber   string          `json:"invoice_number" db:"invoice_number"`
-	PeriodType      string          `json:"period_type" db:"period_type"`
-	PeriodStartDate time.Time       `json:"period_start_date" db:"period_start_date"`
-	PeriodEndDate   time.Time       `json:"period_end_date" db:"period_end_date"`
-	SubtotalAmount  decimal.Decimal `json:"subtotal_amount" db:"subtotal_amount"`
-	GstAmount       decimal.Decimal `json:"gst_amount" db:"gst_amount"`
-	TotalAmount     decimal.Decimal `json:"total_amount" db:"total_amount"`
-	AmountPaid      decimal.Decimal `json:"amount_paid" db:"amount_paid"`
-	PaymentDate     *time.Time      `json:"payment_date,omitempty" db:"payment_date"`
-	GeneratedDate   time.Time       `json:"generated_date" db:"generated_date"`
-	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+	ID                string           `json:"id" db:"id"`
+	ClientID          string           `json:"client_id" db:"client_id"`
+	InvoiceNumber     string           `json:"invoice_number" db:"invoice_number"`
+	PeriodType        string           `json:"period_type" db:"period_type"`
+	PeriodStartDate   time.Time        `json:"period_start_date" db:"period_start_date"`
+	PeriodEndDate     time.Time        `json:"period_end_date" db:"period_end_date"`
+	SubtotalAmount    decimal.Decimal  `json:"subtotal_amount" db:"subtotal_amount"`
+	GstAmount         decimal.Decimal  `json:"gst_amount" db:"gst_amount"`
+	TotalAmount       decimal.Decimal  `json:"total_amount" db:"total_amount"`
+	AmountPaid        decimal.Decimal  `json:"amount_paid" db:"amount_paid"`
+	PaymentDate       *time.Time       `json:"payment_date,omitempty" db:"payment_date"`
+	AmountCredited    decimal.Decimal  `json:"amount_credited" db:"amount_credited"`
+	AmountDiscounted  decimal.Decimal  `json:"amount_discounted" db:"amount_discounted"` // early payment discounts taken
+	GeneratedDate     time.Time        `json:"generated_date" db:"generated_date"`
+	CreatedAt         time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at" db:"updated_at"`
+	GroupBy           string           `json:"group_by" db:"group_by"`                     // InvoiceGroupBySession, InvoiceGroupByDay or InvoiceGroupByDescription
+	NeedsRegeneration bool             `json:"needs_regeneration" db:"needs_regeneration"` // sessions were changed after it was issued
+	Notes             *string          `json:"notes,omitempty" db:"notes"`                 // printed in its Notes section, kept so regenerating it prints the same
+	PaymentTerms      *string          `json:"payment_terms,omitempty" db:"payment_terms"`
+	PoNumber          *string          `json:"po_number,omitempty" db:"po_number"`         // the client's purchase order number
+	ProjectCode       *string          `json:"project_code,omitempty" db:"project_code"`   // the client's code for the project billed
+	Status            string           `json:"status" db:"status"`                         // one of the InvoiceStatus values
+	StatusReason      *string          `json:"status_reason,omitempty" db:"status_reason"` // why it was written off or voided
+	StatusChangedAt   *time.Time       `json:"status_changed_at,omitempty" db:"status_changed_at"`
+	AmountWrittenOff  decimal.Decimal  `json:"amount_written_off" db:"amount_written_off"` // the balance given up when it was written off
+	Rounding          *decimal.Decimal `json:"rounding,omitempty" db:"rounding"`           // the client's invoice rounding when it was issued
 
 	ClientName string `json:"client_name,omitempty" db:"client_name"`
 }
 
+// How an invoice's sessions are listed on it
+const (
+	InvoiceGroupBySession     = "session"
+	InvoiceGroupByDay         = "day"
+	InvoiceGroupByDescription = "description"
+)
+
+// Where an invoice is in its life. Drafts are held back until they're sent and issued, issued invoices become
+// paid once settled, and written-off and void invoices are closed, owing nothing.
+const (
+	InvoiceStatusDraft      = "draft" // not yet sent to the client
+	InvoiceStatusIssued     = "issued"
+	InvoiceStatusPaid       = "paid"
+	InvoiceStatusWrittenOff = "written-off" // will never be paid, the balance is a bad debt
+	InvoiceStatusVoid       = "void"        // issued in error, treated as never issued
+)
+
 type Expense struct {
+	ID            string           `json:"id" db:"id"`
+	Amount        decimal.Decimal  `json:"amount" db:"amount"`
+	ExpenseDate   time.Time        `json:"expense_date" db:"expense_date"`
+	Reference     *string          `json:"reference,omitempty" db:"reference"`
+	ClientID      *string          `json:"client_id,omitempty" db:"client_id"`
+	InvoiceID     *string          `json:"invoice_id,omitempty" db:"invoice_id"`
+	Description   *string          `json:"description,omitempty" db:"description"`
+	Category      *string          `json:"category,omitempty" db:"category"`
+	MarkupPercent *decimal.Decimal `json:"markup_percent,omitempty" db:"markup_percent"`
+	Billable      bool             `json:"billable" db:"billable"`
+	Draft         bool             `json:"draft" db:"draft"`
+	ReceiptPath   *string          `json:"receipt_path,omitempty" db:"receipt_path"`
+	DistanceKm    *decimal.Decimal `json:"distance_km,omitempty" db:"distance_km"`
+	RatePerKm     *decimal.Decimal `json:"rate_per_km,omitempty" db:"rate_per_km"`
+	GstAmount     *decimal.Decimal `json:"gst_amount,omitempty" db:"gst_amount"`
+	DeletedAt     *time.Time       `json:"deleted_at,omitempty" db:"deleted_at"` // when it was moved to the trash
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+
+	ClientName *string `json:"client_name,omitempty" db:"client_name"`
+}
+
+// ExpenseRule matches card and bank statement transactions by their description when importing statements.
+// A matching transaction becomes an expense for the rule's client and category, unless the rule skips it.
+type ExpenseRule struct {
+	ID        string    `json:"id" db:"id"`
+	Pattern   string    `json:"pattern" db:"pattern"` // a regular expression, matched ignoring case
+	ClientID  *string   `json:"client_id,omitempty" db:"client_id"`
+	Category  *string   `json:"category,omitempty" db:"category"`
+	Billable  bool      `json:"billable" db:"billable"`
+	Skip      bool      `json:"skip" db:"skip"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	ClientName *string `json:"client_name,omitempty" db:"client_name"`
+}
+
+// SessionBreak is idle time taken out of a session
+type SessionBreak struct {
+	ID        string    `json:"id" db:"id"`
+	SessionID string    `json:"session_id" db:"session_id"`
+	StartTime time.Time `json:"start_time" db:"start_time"`
+	EndTime   time.Time `json:"end_time" db:"end_time"`
+	Source    string    `json:"source" db:"source"` // BreakSourceManual or BreakSourceActivityWatch
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Where session breaks were recorded from
+const (
+	BreakSourceManual        = "manual"
+	BreakSourceActivityWatch = "activitywatch"
+)
+
+// SessionRepo is a git repository whose commits went into a session's generated description
+type SessionRepo struct {
+	SessionID   string    `json:"session_id" db:"session_id"`
+	RepoPath    string    `json:"repo_path" db:"repo_path"`
+	CommitCount int64     `json:"commit_count" db:"commit_count"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// User is someone other than the owner who works on client sessions, such as a subcontractor. CostRate is
+// what an hour of their time costs, which the hours billed for their sessions are compared against.
+type User struct {
+	ID        string           `json:"id" db:"id"`
+	Name      string           `json:"name" db:"name"`
+	CostRate  *decimal.Decimal `json:"cost_rate,omitempty" db:"cost_rate"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// Leave is a stretch of days off, from StartDate to EndDate inclusive, each stored as midnight local time
+type Leave struct {
+	ID        string    `json:"id" db:"id"`
+	Type      string    `json:"leave_type" db:"leave_type"` // LeaveTypeAnnual, LeaveTypeSick or LeaveTypePublic
+	StartDate time.Time `json:"start_date" db:"start_date"`
+	EndDate   time.Time `json:"end_date" db:"end_date"`
+	Note      *string   `json:"note,omitempty" db:"note"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// The kinds of leave
+const (
+	LeaveTypeAnnual = "annual"
+	LeaveTypeSick   = "sick"
+	LeaveTypePublic = "public"
+)
+
+// Milestone is a fixed-price piece of work for a client, billed for its amount once it's complete rather than by
+// the hour. InvoiceID is set once it's been billed.
+type Milestone struct {
+	ID            string          `json:"id" db:"id"`
+	ClientID      string          `json:"client_id" db:"client_id"`
+	Name          string          `json:"name" db:"name"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	DueDate       *time.Time      `json:"due_date,omitempty" db:"due_date"`
+	Status        string          `json:"status" db:"status"` // MilestoneStatusPending or MilestoneStatusComplete
+	CompletedDate *time.Time      `json:"completed_date,omitempty" db:"completed_date"`
+	InvoiceID     *string         `json:"invoice_id,omitempty" db:"invoice_id"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+
+	ClientName string `json:"client_name" db:"client_name"`
+}
+
+// The statuses of a milestone. Completed milestones count as invoiced once they have an InvoiceID.
+const (
+	MilestoneStatusPending  = "pending"
+	MilestoneStatusComplete = "complete"
+)
+
+// ClientContact is a call, email or meeting with a client. SessionID is set once it's been billed as a session.
+type ClientContact struct {
+	ID              string    `json:"id" db:"id"`
+	ClientID        string    `json:"client_id" db:"client_id"`
+	Type            string    `json:"contact_type" db:"contact_type"` // ContactTypeCall, ContactTypeEmail or ContactTypeMeeting
+	Note            string    `json:"note" db:"note"`
+	ContactedAt     time.Time `json:"contacted_at" db:"contacted_at"`
+	DurationMinutes *int64    `json:"duration_minutes,omitempty" db:"duration_minutes"`
+	SessionID       *string   `json:"session_id,omitempty" db:"session_id"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+
+	ClientName string `json:"client_name,omitempty" db:"client_name"`
+}
+
+// The kinds of client contact
+const (
+	ContactTypeCall    = "call"
+	ContactTypeEmail   = "email"
+	ContactTypeMeeting = "meeting"
+)
+
+type SessionTemplate struct {
+	ID              string    `json:"id" db:"id"`
+	Name            string    `json:"name" db:"name"`
+	ClientID        string    `json:"client_id" db:"client_id"`
+	Description     *string   `json:"description,omitempty" db:"description"`
+	DurationMinutes *int64    `json:"duration_minutes,omitempty" db:"duration_minutes"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+
+	ClientName string `json:"client_name,omitempty" db:"client_name"`
+}
+
+type Payment struct {
 	ID          string          `json:"id" db:"id"`
+	InvoiceID   string          `json:"invoice_id" db:"invoice_id"`
 	Amount      decimal.Decimal `json:"amount" db:"amount"`
-	ExpenseDate time.Time       `json:"expense_date" db:"expense_date"`
-	Reference   *string         `json:"reference,omitempty" db:"reference"`
-	ClientID    *string         `json:"client_id,omitempty" db:"client_id"`
-	InvoiceID   *string         `json:"invoice_id,omitempty" db:"invoice_id"`
-	Description *string         `json:"description,omitempty" db:"description"`
-	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+	PaymentDate time.Time       `json:"payment_date" db:"payment_date"`
+	// DiscountAmount is the early payment discount taken when the payment settled the invoice for less
+	DiscountAmount decimal.Decimal `json:"discount_amount" db:"discount_amount"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
 
-	ClientName *string `json:"client_name,omitempty" db:"client_name"`
+	InvoiceNumber string `json:"invoice_number,omitempty" db:"invoice_number"`
+	ClientName    string `json:"client_name,omitempty" db:"client_name"`
+}
+
+type CreditNote struct {
+	ID               string          `json:"id" db:"id"`
+	InvoiceID        string          `json:"invoice_id" db:"invoice_id"`
+	CreditNoteNumber string          `json:"credit_note_number" db:"credit_note_number"`
+	Amount           decimal.Decimal `json:"amount" db:"amount"`
+	GstAmount        decimal.Decimal `json:"gst_amount" db:"gst_amount"`
+	Reason           string          `json:"reason" db:"reason"`
+	IssuedDate       time.Time       `json:"issued_date" db:"issued_date"`
+	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at" db:"updated_at"`
 }
 
 func NewUUID() string {
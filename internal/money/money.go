@@ -0,0 +1,180 @@
+package money
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Formatter formats amounts of a currency using a locale's number conventions
+type Formatter struct {
+	Currency string
+	Locale   string
+}
+
+type localeFormat struct {
+	decimalSep  string
+	groupSep    string
+	symbolAfter bool // 1.234,56 € rather than €1,234.56
+	symbolSpace bool // separate the symbol from the number with a space
+}
+
+type currencyFormat struct {
+	symbol string
+	digits int32
+}
+
+var locales = map[string]localeFormat{
+	"en-AU": {".", ",", false, false},
+	"en-NZ": {".", ",", false, false},
+	"en-US": {".", ",", false, false},
+	"en-CA": {".", ",", false, false},
+	"en-GB": {".", ",", false, false},
+	"en-IE": {".", ",", false, false},
+	"en-SG": {".", ",", false, false},
+	"en-IN": {".", ",", false, false},
+	"ja-JP": {".", ",", false, false},
+	"de-DE": {",", ".", true, true},
+	"de-AT": {",", ".", false, true},
+	"de-CH": {".", "'", false, true},
+	"fr-FR": {",", " ", true, true},
+	"fr-CA": {",", " ", true, true},
+	"es-ES": {",", ".", true, true},
+	"it-IT": {",", ".", true, true},
+	"pt-PT": {",", " ", true, true},
+	"pt-BR": {",", ".", false, true},
+	"nl-NL": {",", ".", false, true},
+	"fi-FI": {",", " ", true, true},
+	"sv-SE": {",", " ", true, true},
+	"nb-NO": {",", " ", true, true},
+	"da-DK": {",", ".", true, true},
+}
+
+var currencies = map[string]currencyFormat{
+	"AUD": {"$", 2},
+	"NZD": {"$", 2},
+	"USD": {"$", 2},
+	"CAD": {"$", 2},
+	"SGD": {"$", 2},
+	"HKD": {"$", 2},
+	"EUR": {"€", 2},
+	"GBP": {"£", 2},
+	"JPY": {"¥", 0},
+	"CHF": {"CHF", 2},
+	"SEK": {"kr", 2},
+	"NOK": {"kr", 2},
+	"DKK": {"kr.", 2},
+	"INR": {"₹", 2},
+	"BRL": {"R$", 2},
+}
+
+// New returns a formatter for the currency code and locale, e.g. New("EUR", "de-DE")
+func New(currency, locale string) Formatter {
+	return Formatter{Currency: strings.ToUpper(currency), Locale: normalizeLocale(locale)}
+}
+
+// Validate returns an error if the currency code or locale is not supported
+func Validate(currency, locale string) error {
+	if currency != "" {
+		if _, ok := currencies[strings.ToUpper(currency)]; !ok {
+			return fmt.Errorf("unsupported currency %q, expected one of: %s", currency, strings.Join(supported(currencies), ", "))
+		}
+	}
+	if locale != "" {
+		if _, ok := locales[normalizeLocale(locale)]; !ok {
+			return fmt.Errorf("unsupported locale %q, expected one of: %s", locale, strings.Join(supported(locales), ", "))
+		}
+	}
+	return nil
+}
+
+// Format renders the amount with the currency symbol, e.g. $1,234.56 or 1.234,56 €
+func (f Formatter) Format(amount decimal.Decimal) string {
+	loc := f.locale()
+	cur := f.currency()
+
+	intPart, fracPart, hasFrac := strings.Cut(amount.Abs().StringFixed(cur.digits), ".")
+	number := group(intPart, loc.groupSep)
+	if hasFrac {
+		number += loc.decimalSep + fracPart
+	}
+
+	sign := ""
+	if amount.Round(cur.digits).IsNegative() {
+		sign = "-"
+	}
+
+	space := ""
+	if loc.symbolSpace {
+		space = " "
+	}
+	if loc.symbolAfter {
+		return sign + number + space + cur.symbol
+	}
+	return sign + cur.symbol + space + number
+}
+
+// Number renders the amount without a symbol or grouping, using the locale's decimal separator.
+// Intended for machine-readable output such as CSV.
+func (f Formatter) Number(amount decimal.Decimal) string {
+	number := amount.StringFixed(f.currency().digits)
+	return strings.Replace(number, ".", f.locale().decimalSep, 1)
+}
+
+// Symbol returns the currency symbol, e.g. € for EUR
+func (f Formatter) Symbol() string {
+	return f.currency().symbol
+}
+
+func (f Formatter) locale() localeFormat {
+	if loc, ok := locales[f.Locale]; ok {
+		return loc
+	}
+	return locales["en-AU"]
+}
+
+func (f Formatter) currency() currencyFormat {
+	if cur, ok := currencies[f.Currency]; ok {
+		return cur
+	}
+	return currencies["AUD"]
+}
+
+// group inserts the group separator between every three digits of an integer string
+func group(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+// normalizeLocale accepts en_AU, en-au and similar spellings
+func normalizeLocale(locale string) string {
+	lang, region, ok := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	if !ok {
+		return strings.ToLower(lang)
+	}
+	return strings.ToLower(lang) + "-" + strings.ToUpper(region)
+}
+
+func supported[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
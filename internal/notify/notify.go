@@ -0,0 +1,35 @@
+// Package notify shows desktop notifications, such as a summary of work done in the background.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+)
+
+// ErrUnsupported is returned when there's no way to show notifications on this system
+var ErrUnsupported = errors.New("desktop notifications aren't supported here")
+
+// Desktop shows a notification with notify-send on Linux and the BSDs, or osascript on macOS
+func Desktop(ctx context.Context, title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(title))
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	case "windows":
+		return ErrUnsupported
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return fmt.Errorf("%w: notify-send not found on PATH", ErrUnsupported)
+		}
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=work", title, message)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to show notification: %w: %s", err, output)
+	}
+	return nil
+}
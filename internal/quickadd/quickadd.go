@@ -0,0 +1,328 @@
+// Package quickadd reads sessions written in plain English, such as "2h yesterday for acme fixing the deploy
+// pipeline", for logging work after the fact without spelling out start and end times.
+package quickadd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultStartHour is when sessions on earlier days are taken to have started when no time is given
+const defaultStartHour = 9
+
+// Entry is what was understood from a quick add
+type Entry struct {
+	Duration time.Duration
+	// Date is midnight on the day the work was done
+	Date time.Time
+	// Start is the time the work started that day, or nil when it wasn't given
+	Start       *time.Time
+	ClientQuery string
+	Description string
+}
+
+// Times returns when the session started and ended. Without a start time, work today is taken to have ended
+// now, and work on other days to have started at 9am.
+func (e Entry) Times(now time.Time) (time.Time, time.Time) {
+	if e.Start != nil {
+		return *e.Start, e.Start.Add(e.Duration)
+	}
+	if sameDay(e.Date, now) {
+		return now.Add(-e.Duration), now
+	}
+	start := e.Date.Add(defaultStartHour * time.Hour)
+	return start, start.Add(e.Duration)
+}
+
+var (
+	compactDuration = regexp.MustCompile(`^(?:(\d+(?:\.\d+)?)h)?(?:(\d+)m)?$`)
+	numberedUnit    = regexp.MustCompile(`^(\d+(?:\.\d+)?)(h|hr|hrs|hour|hours|m|min|mins|minute|minutes)$`)
+	clockTime       = regexp.MustCompile(`^(\d{1,2})(?:[:.](\d{2}))?(am|pm)?$`)
+)
+
+// Parse reads a quick add such as "2h yesterday for acme fixing the deploy pipeline". A duration and who the work
+// was for, after "for", are required. The date (today, yesterday, a weekday, "3 days ago" or YYYY-MM-DD) and start
+// time ("at 9:30" or "at 2pm") can come before or straight after the client, and the rest is the description.
+func Parse(input string, now time.Time) (Entry, error) {
+	words := strings.Fields(input)
+	entry := Entry{Date: midnight(now)}
+	p := parser{entry: &entry, now: now}
+
+	i := 0
+	for i < len(words) && !strings.EqualFold(words[i], "for") {
+		n, err := p.detail(words[i:])
+		if err != nil {
+			return Entry{}, err
+		}
+		if n == 0 {
+			return Entry{}, fmt.Errorf("didn't understand %q, start with how long and when, then 'for <client>'", words[i])
+		}
+		i += n
+	}
+	if i+1 >= len(words) {
+		return Entry{}, fmt.Errorf("say who the work was for, e.g. '2h yesterday for acme'")
+	}
+	entry.ClientQuery = words[i+1]
+	i += 2
+
+	// Details can also follow the client, up to the first word of the description
+	for i < len(words) {
+		n, err := p.detail(words[i:])
+		if err != nil {
+			return Entry{}, err
+		}
+		if n == 0 {
+			break
+		}
+		i += n
+	}
+	entry.Description = strings.Join(words[i:], " ")
+
+	if entry.Duration <= 0 {
+		return Entry{}, fmt.Errorf("say how long the work took, e.g. 2h, 90m or 1h30m")
+	}
+	if p.clock != nil {
+		start := entry.Date.Add(*p.clock)
+		entry.Start = &start
+	}
+	return entry, nil
+}
+
+// parser holds what's been read so far, so each detail is only given once
+type parser struct {
+	entry    *Entry
+	now      time.Time
+	clock    *time.Duration // time of day the work started, applied to the date once it's known
+	haveDate bool
+}
+
+// detail reads a duration, date or start time from the start of words, returning how many words it used, or 0
+// when words doesn't start with one
+func (p *parser) detail(words []string) (int, error) {
+	word := strings.ToLower(strings.TrimSuffix(words[0], ","))
+
+	if d, n := parseDuration(words); n > 0 {
+		if p.entry.Duration > 0 {
+			return 0, fmt.Errorf("the duration was given twice, %q is the second", words[0])
+		}
+		p.entry.Duration = d
+		return n, nil
+	}
+
+	if word == "at" && len(words) > 1 {
+		clock, ok := parseClock(words[1])
+		if !ok {
+			return 0, fmt.Errorf("didn't understand the time %q, use e.g. 9:30 or 2pm", words[1])
+		}
+		p.clock = &clock
+		return 2, nil
+	}
+
+	if word == "on" || word == "last" {
+		if len(words) > 1 {
+			if n, err := p.date(words[1:]); n > 0 || err != nil {
+				return n + 1, err
+			}
+		}
+		return 0, nil
+	}
+	return p.date(words)
+}
+
+// date reads a date from the start of words, returning how many words it used
+func (p *parser) date(words []string) (int, error) {
+	word := strings.ToLower(strings.TrimSuffix(words[0], ","))
+	today := midnight(p.now)
+
+	var date time.Time
+	n := 1
+	switch {
+	case word == "today":
+		date = today
+	case word == "yesterday":
+		date = today.AddDate(0, 0, -1)
+	case weekday(word) >= 0:
+		// The most recent such day before today, so "monday" on a Monday means a week ago
+		back := (int(p.now.Weekday())-weekday(word)+6)%7 + 1
+		date = today.AddDate(0, 0, -back)
+	case len(words) >= 3 && (words[1] == "days" || words[1] == "day") && strings.EqualFold(words[2], "ago"):
+		days, err := strconv.Atoi(word)
+		if err != nil || days < 0 {
+			return 0, nil
+		}
+		date = today.AddDate(0, 0, -days)
+		n = 3
+	default:
+		parsed, err := time.ParseInLocation("2006-01-02", word, p.now.Location())
+		if err != nil {
+			return 0, nil
+		}
+		date = parsed
+	}
+
+	if p.haveDate {
+		return 0, fmt.Errorf("the date was given twice, %q is the second", words[0])
+	}
+	p.haveDate = true
+	p.entry.Date = date
+	return n, nil
+}
+
+// parseDuration reads a duration such as 2h, 1.5h, 90m, 1h30m, "2 hours" or "45 mins" from the start of words
+func parseDuration(words []string) (time.Duration, int) {
+	word := strings.ToLower(words[0])
+	if m := compactDuration.FindStringSubmatch(word); m != nil && word != "" {
+		hours, _ := strconv.ParseFloat(orZero(m[1]), 64)
+		minutes, _ := strconv.Atoi(orZero(m[2]))
+		return time.Duration(hours*float64(time.Hour)) + time.Duration(minutes)*time.Minute, 1
+	}
+	if m := numberedUnit.FindStringSubmatch(word); m != nil {
+		return unitDuration(m[1], m[2]), 1
+	}
+	if len(words) > 1 {
+		if _, err := strconv.ParseFloat(word, 64); err == nil {
+			if m := numberedUnit.FindStringSubmatch(word + strings.ToLower(words[1])); m != nil {
+				return unitDuration(m[1], m[2]), 2
+			}
+		}
+	}
+	return 0, 0
+}
+
+func unitDuration(amount, unit string) time.Duration {
+	value, _ := strconv.ParseFloat(amount, 64)
+	if strings.HasPrefix(unit, "h") {
+		return time.Duration(value * float64(time.Hour))
+	}
+	return time.Duration(value * float64(time.Minute))
+}
+
+// parseClock reads a time of day such as 9, 9:30, 14.00 or 2pm as the time since midnight
+func parseClock(value string) (time.Duration, bool) {
+	m := clockTime.FindStringSubmatch(strings.ToLower(value))
+	if m == nil {
+		return 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(orZero(m[2]))
+	switch m[3] {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 || (m[3] != "" && m[1] == "0") {
+		return 0, false
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true
+}
+
+// MatchClient finds the client a quick add was for among names, preferring an exact match, then a name starting
+// with query, then one containing it, then the closest spelling. Ties are reported rather than guessed.
+func MatchClient(query string, names []string) (string, error) {
+	matches := ClientCandidates(query, names)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no client matches '%s'", query)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("'%s' could be any of %s, use more of the name", query, strings.Join(matches, ", "))
+	}
+}
+
+// ClientCandidates returns the names query could mean, in the order MatchClient prefers them: a name equal to it
+// ignoring case, else those starting with it, else those containing it, else those closest in spelling
+func ClientCandidates(query string, names []string) []string {
+	q := strings.ToLower(query)
+	for _, name := range names {
+		if strings.ToLower(name) == q {
+			return []string{name}
+		}
+	}
+
+	for _, matches := range [][]string{
+		filter(names, func(name string) bool { return strings.HasPrefix(name, q) }),
+		filter(names, func(name string) bool { return strings.Contains(name, q) }),
+	} {
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+
+	// Allow about one typo for every three letters
+	best, bestDistance := []string(nil), len(q)/3+1
+	for _, name := range names {
+		d := levenshtein(q, strings.ToLower(name))
+		if d < bestDistance {
+			best, bestDistance = []string{name}, d
+		} else if d == bestDistance && best != nil {
+			best = append(best, name)
+		}
+	}
+	return best
+}
+
+func filter(names []string, keep func(lowerName string) bool) []string {
+	var kept []string
+	for _, name := range names {
+		if keep(strings.ToLower(name)) {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// levenshtein counts the single letter edits to turn a into b
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current := make([]int, len(br)+1)
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(br)]
+}
+
+func weekday(word string) int {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if word == name || word == name[:3] {
+			return int(day)
+		}
+	}
+	return -1
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
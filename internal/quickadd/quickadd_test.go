@@ -0,0 +1,173 @@
+package quickadd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// now is a Wednesday afternoon
+var now = time.Date(2025, time.March, 12, 15, 30, 0, 0, time.UTC)
+
+func day(d int) time.Time {
+	return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input       string
+		duration    time.Duration
+		date        time.Time
+		start       string
+		client      string
+		description string
+	}{
+		{"2h yesterday for acme fixing the deploy pipeline", 2 * time.Hour, day(11), "", "acme", "fixing the deploy pipeline"},
+		{"90m for acme", 90 * time.Minute, day(12), "", "acme", ""},
+		{"1h30m today for acme review", 90 * time.Minute, day(12), "", "acme", "review"},
+		{"1.5h monday for acme", 90 * time.Minute, day(10), "", "acme", ""},
+		{"2 hours last wed for acme planning", 2 * time.Hour, day(5), "", "acme", "planning"},
+		{"45 mins on friday for acme", 45 * time.Minute, day(7), "", "acme", ""},
+		{"3hrs 3 days ago for acme", 3 * time.Hour, day(9), "", "acme", ""},
+		{"2h 2025-03-01 at 2pm for acme", 2 * time.Hour, day(1), "14:00", "acme", ""},
+		{"for acme 2h yesterday at 9:30 standup and planning", 2 * time.Hour, day(11), "09:30", "acme", "standup and planning"},
+		{"1h FOR Acme Tidied up the Friday release", time.Hour, day(12), "", "Acme", "Tidied up the Friday release"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input, now)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.Duration != tt.duration {
+				t.Errorf("Duration = %v, want %v", got.Duration, tt.duration)
+			}
+			if !got.Date.Equal(tt.date) {
+				t.Errorf("Date = %v, want %v", got.Date, tt.date)
+			}
+			var start string
+			if got.Start != nil {
+				start = got.Start.Format("15:04")
+			}
+			if start != tt.start {
+				t.Errorf("Start = %q, want %q", start, tt.start)
+			}
+			if got.ClientQuery != tt.client {
+				t.Errorf("ClientQuery = %q, want %q", got.ClientQuery, tt.client)
+			}
+			if got.Description != tt.description {
+				t.Errorf("Description = %q, want %q", got.Description, tt.description)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"yesterday for acme", "how long"},
+		{"2h yesterday", "who the work was for"},
+		{"2h yesterday for", "who the work was for"},
+		{"2h sometime for acme", "didn't understand \"sometime\""},
+		{"2h 3h for acme", "duration was given twice"},
+		{"2h today yesterday for acme", "date was given twice"},
+		{"2h at noon for acme", "didn't understand the time"},
+		{"2h at 25:00 for acme", "didn't understand the time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input, now)
+			if err == nil {
+				t.Fatalf("Parse() error = nil, want one containing %q", tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryTimes(t *testing.T) {
+	nine := day(11).Add(9 * time.Hour)
+	tests := []struct {
+		name      string
+		entry     Entry
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{"today ends now", Entry{Duration: 2 * time.Hour, Date: day(12)}, now.Add(-2 * time.Hour), now},
+		{"earlier days start at nine", Entry{Duration: 2 * time.Hour, Date: day(11)}, nine, nine.Add(2 * time.Hour)},
+		{"given start", Entry{Duration: time.Hour, Date: day(12), Start: &nine}, nine, nine.Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.entry.Times(now)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("Times() = %v, %v, want %v, %v", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestMatchClient(t *testing.T) {
+	names := []string{"Acme", "Acme Labs", "Globex", "Initech", "Umbrella"}
+	tests := []struct {
+		query   string
+		want    string
+		wantErr string
+	}{
+		{"acme", "Acme", ""},
+		{"glob", "Globex", ""},
+		{"tech", "Initech", ""},
+		{"umbrela", "Umbrella", ""},
+		{"gloebx", "Globex", ""},
+		{"ac", "", "could be any of Acme, Acme Labs"},
+		{"hooli", "", "no client matches"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := MatchClient(tt.query, names)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("MatchClient() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MatchClient() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MatchClient() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientCandidates(t *testing.T) {
+	names := []string{"acme_corp", "Acme Labs", "Globex"}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"ACME_CORP", []string{"acme_corp"}},
+		{"acm", []string{"acme_corp", "Acme Labs"}},
+		{"labs", []string{"Acme Labs"}},
+		{"glbex", []string{"Globex"}},
+		{"hooli", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := ClientCandidates(tt.query, names)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ClientCandidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// Orders for the aging report
+const (
+	AgingSortClient = "client"
+	AgingSortAmount = "amount" // most owed first
+)
+
+// AgingSorts are the orders the aging report can be sorted in
+var AgingSorts = []string{AgingSortClient, AgingSortAmount}
+
+// agingBuckets label the ranges of days overdue unpaid invoices are grouped into, each up to its most days, with
+// the last open ended
+var agingBuckets = []struct {
+	label   string
+	maxDays int
+}{
+	{"Current", 0},
+	{"1-30", 30},
+	{"31-60", 60},
+	{"61-90", 90},
+	{"90+", -1},
+}
+
+// agingBucket is the index of the bucket for an invoice overdue by days
+func agingBucket(days int) int {
+	for i, bucket := range agingBuckets {
+		if bucket.maxDays >= 0 && days <= bucket.maxDays {
+			return i
+		}
+	}
+	return len(agingBuckets) - 1
+}
+
+// clientAging is what a client owes in each aging bucket
+type clientAging struct {
+	name       string
+	m          money.Formatter
+	buckets    []decimal.Decimal
+	total      decimal.Decimal
+	oldestDays int
+	invoices   []*models.Invoice
+}
+
+// ShowInvoiceAging lists what each client owes on unpaid invoices on date, grouped by how many days past due
+// they are, with totals for each currency. Clients are sorted by name or with the most owed first, and each
+// client's invoices are listed beneath it with showInvoices. Drafts that haven't been sent aren't included.
+func (s *TimesheetService) ShowInvoiceAging(ctx context.Context, clientName, date, sortBy string, showInvoices bool) error {
+	if sortBy == "" {
+		sortBy = AgingSortClient
+	}
+	if sortBy != AgingSortClient && sortBy != AgingSortAmount {
+		return fmt.Errorf("sort must be one of %s, got %q", strings.Join(AgingSorts, ", "), sortBy)
+	}
+	asOf := time.Now()
+	if date != "" {
+		var err error
+		if asOf, err = time.ParseInLocation("2006-01-02", date, time.Local); err != nil {
+			return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+		}
+	}
+
+	invoices, err := s.GetInvoices(ctx, -1, clientName, true)
+	if err != nil {
+		return err
+	}
+
+	byClient := make(map[string]*clientAging)
+	for _, invoice := range invoices {
+		if invoice.Status == models.InvoiceStatusDraft || dateOnly(invoice.GeneratedDate).After(dateOnly(asOf)) {
+			continue
+		}
+		aging, ok := byClient[invoice.ClientName]
+		if !ok {
+			aging = &clientAging{name: invoice.ClientName, m: s.clientMoneyByName(invoice.ClientName), buckets: make([]decimal.Decimal, len(agingBuckets))}
+			byClient[invoice.ClientName] = aging
+		}
+		balance := invoiceBalance(invoice)
+		days := s.invoiceDaysOverdue(invoice, asOf)
+		bucket := agingBucket(days)
+		aging.buckets[bucket] = aging.buckets[bucket].Add(balance)
+		aging.total = aging.total.Add(balance)
+		aging.oldestDays = max(aging.oldestDays, days)
+		aging.invoices = append(aging.invoices, invoice)
+	}
+	if len(byClient) == 0 {
+		fmt.Fprintln(s.out, "No unpaid invoices found.")
+		return nil
+	}
+
+	clients := make([]*clientAging, 0, len(byClient))
+	for _, aging := range byClient {
+		clients = append(clients, aging)
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		if sortBy == AgingSortAmount && !clients[i].total.Equal(clients[j].total) {
+			return clients[i].total.GreaterThan(clients[j].total)
+		}
+		return clients[i].name < clients[j].name
+	})
+
+	fmt.Fprintf(s.out, "Unpaid invoices by days overdue on %s\n\n", asOf.Format("2006-01-02"))
+	header := fmt.Sprintf("%-16s", "CLIENT")
+	for _, bucket := range agingBuckets {
+		header += fmt.Sprintf(" %12s", strings.ToUpper(bucket.label))
+	}
+	fmt.Fprintf(s.out, "%s %13s  %s\n", header, "TOTAL", "OLDEST")
+	fmt.Fprintln(s.out, strings.Repeat("-", len(header)+23))
+
+	totals := make(map[string][]decimal.Decimal)
+	formatters := make(map[string]money.Formatter)
+	for _, aging := range clients {
+		row := fmt.Sprintf("%-16s", truncateString(aging.name, 16))
+		for _, amount := range aging.buckets {
+			row += fmt.Sprintf(" %12s", agingAmount(aging.m, amount))
+		}
+		oldest := "-"
+		if aging.oldestDays > 0 {
+			oldest = fmt.Sprintf("%d days", aging.oldestDays)
+		}
+		fmt.Fprintf(s.out, "%s %13s  %s\n", row, aging.m.Format(aging.total), oldest)
+
+		if showInvoices {
+			// Oldest first, the order they'd be chased in
+			sort.Slice(aging.invoices, func(i, j int) bool {
+				return aging.invoices[i].GeneratedDate.Before(aging.invoices[j].GeneratedDate)
+			})
+			for _, invoice := range aging.invoices {
+				overdue := "not yet due"
+				if days := s.invoiceDaysOverdue(invoice, asOf); days > 0 {
+					overdue = fmt.Sprintf("%d days overdue", days)
+				}
+				fmt.Fprintf(s.out, "  %s issued %s, due %s, %s: %s owed\n", invoice.InvoiceNumber, invoice.GeneratedDate.Format("2006-01-02"),
+					s.invoiceDueDate(invoice).Format("2006-01-02"), overdue, aging.m.Format(invoiceBalance(invoice)))
+			}
+		}
+
+		currency := aging.m.Currency
+		if _, ok := totals[currency]; !ok {
+			totals[currency] = make([]decimal.Decimal, len(agingBuckets)+1)
+			formatters[currency] = aging.m
+		}
+		for i, amount := range aging.buckets {
+			totals[currency][i] = totals[currency][i].Add(amount)
+		}
+		totals[currency][len(agingBuckets)] = totals[currency][len(agingBuckets)].Add(aging.total)
+	}
+
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	fmt.Fprintln(s.out, strings.Repeat("-", len(header)+23))
+	for _, currency := range currencies {
+		row := fmt.Sprintf("%-16s", "Total "+currency)
+		for _, amount := range totals[currency][:len(agingBuckets)] {
+			row += fmt.Sprintf(" %12s", agingAmount(formatters[currency], amount))
+		}
+		fmt.Fprintf(s.out, "%s %13s\n", row, formatters[currency].Format(totals[currency][len(agingBuckets)]))
+	}
+	return nil
+}
+
+// agingAmount formats an amount in an aging bucket, with a dash for nothing
+func agingAmount(m money.Formatter, amount decimal.Decimal) string {
+	if amount.IsZero() {
+		return "-"
+	}
+	return m.Format(amount)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// defaultAnalysisConcurrency is used when the config doesn't set a concurrency, e.g. in tests
+const defaultAnalysisConcurrency = 4
+
+// analysisPool bounds how many analysis processes run at once and spaces out calls to each provider
+// so that generating many descriptions doesn't spawn dozens of AI processes at the same time
+type analysisPool struct {
+	slots    chan struct{}
+	limiters map[string]*rateLimiter
+	logger   *slog.Logger
+
+	mu     sync.Mutex
+	queued int
+}
+
+func newAnalysisPool(concurrency int, rateLimits map[string]int, logger *slog.Logger) *analysisPool {
+	if concurrency < 1 {
+		concurrency = defaultAnalysisConcurrency
+	}
+	limiters := make(map[string]*rateLimiter, len(rateLimits))
+	for provider, perMinute := range rateLimits {
+		limiters[provider] = &rateLimiter{interval: time.Minute / time.Duration(perMinute)}
+	}
+	return &analysisPool{
+		slots:    make(chan struct{}, concurrency),
+		limiters: limiters,
+		logger:   logger,
+	}
+}
+
+// acquire waits for a free slot and the provider's rate limit, logging the queue position while waiting.
+// The returned func must be called to free the slot.
+func (p *analysisPool) acquire(ctx context.Context, provider, label string) (func(), error) {
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		p.mu.Lock()
+		p.queued++
+		position := p.queued
+		p.mu.Unlock()
+		p.logger.Info("queued for analysis", "task", label, "position", position, "concurrency", cap(p.slots))
+
+		select {
+		case p.slots <- struct{}{}:
+			p.mu.Lock()
+			p.queued--
+			p.mu.Unlock()
+		case <-ctx.Done():
+			p.mu.Lock()
+			p.queued--
+			p.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+	release := func() { <-p.slots }
+
+	if limiter, ok := p.limiters[provider]; ok {
+		if wait := limiter.reserve(); wait > 0 {
+			p.logger.Debug("waiting for rate limit", "provider", provider, "task", label, "wait", wait.Round(time.Second))
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	return release, nil
+}
+
+// rateLimiter spaces calls evenly so that no more than one happens per interval
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// reserve claims the next call slot and returns how long to wait before making the call
+func (r *rateLimiter) reserve() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	slot := r.next
+	if slot.Before(now) {
+		slot = now
+	}
+	r.next = slot.Add(r.interval)
+	return slot.Sub(now)
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+const (
+	// auditCommitGrace is how long before a session starts or after it ends a commit still counts as made in
+	// it, for work committed just after stopping the timer
+	auditCommitGrace = 30 * time.Minute
+	// auditBurstGap is the longest gap between commits made outside sessions for them to be one stretch of work
+	auditBurstGap = time.Hour
+	// auditBurstLead is how long before the first commit of a stretch of untracked work it's suggested to start
+	auditBurstLead = 30 * time.Minute
+)
+
+// auditCommit is a commit of yours in one of a client's repositories
+type auditCommit struct {
+	repo    string
+	hash    string
+	at      time.Time
+	subject string
+}
+
+// ShowCoverageAudit compares the sessions tracked in the period containing date with the commits you made in
+// each client's repositories, listing commits made outside any session, which may be billable time that was
+// never tracked, and finished sessions without a commit, which may need a description or a note of the work
+// done outside git. Ends with the commands that would fix each.
+func (s *TimesheetService) ShowCoverageAudit(ctx context.Context, period, date, clientName string) error {
+	if err := ValidatePeriod(period); err != nil {
+		return err
+	}
+	target := time.Now()
+	if date != "" {
+		var err error
+		if target, err = time.ParseInLocation("2006-01-02", date, time.Local); err != nil {
+			return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+		}
+	}
+	from, to := s.CalculatePeriodRange(period, target)
+
+	clients, err := s.db.ListClients(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get clients: %w", err)
+	}
+	if clientName != "" {
+		client, err := s.db.GetClientByName(ctx, clientName)
+		if err != nil {
+			return fmt.Errorf("client '%s' not found: %w", clientName, err)
+		}
+		clients = []*models.Client{client}
+	}
+	sessions, err := s.ListSessionsWithDateRange(ctx, from.Format("2006-01-02"), to.Format("2006-01-02"), 100000)
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	fmt.Fprintf(s.out, "Coverage audit for %s to %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	var fixes []string
+	var unchecked []string
+	for _, client := range clients {
+		// Only your own sessions are compared with your commits
+		var clientSessions []*models.WorkSession
+		for _, session := range sessions {
+			if session.ClientID == client.ID && session.UserID == nil {
+				clientSessions = append(clientSessions, session)
+			}
+		}
+		sort.Slice(clientSessions, func(i, j int) bool { return clientSessions[i].StartTime.Before(clientSessions[j].StartTime) })
+		if utils.FromPtr(client.Dir) == "" {
+			if len(clientSessions) > 0 {
+				unchecked = append(unchecked, client.Name)
+			}
+			continue
+		}
+
+		commits, err := s.clientAuditCommits(ctx, client, from, to)
+		if err != nil {
+			return err
+		}
+		if len(commits) == 0 && len(clientSessions) == 0 {
+			continue
+		}
+
+		var outside []auditCommit
+		for _, commit := range commits {
+			if sessionAt(clientSessions, commit.at) == nil {
+				outside = append(outside, commit)
+			}
+		}
+		var quiet []*models.WorkSession
+		for _, session := range clientSessions {
+			if session.EndTime == nil {
+				continue
+			}
+			if !hasCommitIn(commits, session.StartTime, *session.EndTime) {
+				quiet = append(quiet, session)
+			}
+		}
+
+		fmt.Fprintf(s.out, "\n%s: %d sessions, %d commits\n", client.Name, len(clientSessions), len(commits))
+		if len(outside) == 0 && len(quiet) == 0 {
+			fmt.Fprintln(s.out, "  Every commit was made in a session, and every session has commits")
+			continue
+		}
+
+		if len(outside) > 0 {
+			fmt.Fprintf(s.out, "  Commits outside any session (possible untracked time):\n")
+			for _, burst := range commitBursts(outside, auditBurstGap) {
+				first, last := burst[0].at, burst[len(burst)-1].at
+				fmt.Fprintf(s.out, "    %s to %s, %d commits\n", first.Format("2006-01-02 15:04"), last.Format("15:04"), len(burst))
+				for _, commit := range burst {
+					fmt.Fprintf(s.out, "      %s %s %s: %s\n", commit.at.Format("15:04"), commit.repo, commit.hash, commit.subject)
+				}
+				fixes = append(fixes, fmt.Sprintf("work sessions create -c %s -f %q -t %q", client.Name,
+					first.Add(-auditBurstLead).Format("2006-01-02 15:04"), last.Format("2006-01-02 15:04")))
+			}
+		}
+
+		if len(quiet) > 0 {
+			fmt.Fprintf(s.out, "  Sessions without a commit (work outside git, or needing a description):\n")
+			for _, session := range quiet {
+				description := utils.FromPtr(session.Description)
+				if description == "" {
+					description = "no description"
+				}
+				fmt.Fprintf(s.out, "    %s %s to %s (%s) %s: %s\n", session.ID, session.StartTime.Format("2006-01-02 15:04"),
+					session.EndTime.Format("15:04"), s.FormatDurationFor(config.DurationContextList, s.CalculateDuration(session)),
+					client.Name, description)
+				if session.OutsideGit == nil || strings.TrimSpace(*session.OutsideGit) == "" {
+					force := ""
+					if session.InvoiceID != nil {
+						force = " --force"
+					}
+					fixes = append(fixes, fmt.Sprintf("work note --session %s%s \"<what you did>\"", session.ID, force))
+				}
+			}
+		}
+	}
+
+	if len(unchecked) > 0 {
+		fmt.Fprintf(s.out, "\nNot checked, no directory configured: %s\n", strings.Join(unchecked, ", "))
+	}
+	if len(fixes) == 0 {
+		fmt.Fprintln(s.out, "\nNothing to fix")
+		return nil
+	}
+	fmt.Fprintln(s.out, "\nSuggested fixes:")
+	for _, fix := range fixes {
+		fmt.Fprintf(s.out, "  %s\n", fix)
+	}
+	return nil
+}
+
+// clientAuditCommits returns the commits you made in a client's repositories between from and to on any
+// branch, oldest first. Commits are yours when they're by the repository's user.email, or all commits are
+// counted when it isn't set.
+func (s *TimesheetService) clientAuditCommits(ctx context.Context, client *models.Client, from, to time.Time) ([]auditCommit, error) {
+	dir, err := expandClientDir(*client.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []auditCommit
+	for _, repo := range s.findGitRepositoriesWalk(dir) {
+		args := []string{"-C", repo, "log", "--all", "--no-merges",
+			"--since=" + from.Format(time.RFC3339), "--until=" + to.Format(time.RFC3339), "--format=%h %ct %s"}
+		if email, err := exec.CommandContext(ctx, "git", "-C", repo, "config", "user.email").Output(); err == nil && strings.TrimSpace(string(email)) != "" {
+			args = append(args, "--author="+strings.TrimSpace(string(email)))
+		}
+		output, err := exec.CommandContext(ctx, "git", args...).Output()
+		if err != nil {
+			s.logger.Warn("failed to read git log", "repo", repo, "error", err)
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			fields := strings.SplitN(line, " ", 3)
+			if len(fields) < 3 {
+				continue
+			}
+			seconds, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			commits = append(commits, auditCommit{
+				repo:    filepath.Base(repo),
+				hash:    fields[0],
+				at:      time.Unix(seconds, 0).In(from.Location()),
+				subject: strings.TrimSpace(fields[2]),
+			})
+		}
+	}
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].at.Before(commits[j].at) })
+	s.logger.Debug("read commits for audit", "client", client.Name, "dir", dir, "commits", len(commits))
+	return commits, nil
+}
+
+// sessionAt returns the session a commit made at t belongs to, allowing auditCommitGrace either side, with an
+// active session running until now
+func sessionAt(sessions []*models.WorkSession, t time.Time) *models.WorkSession {
+	for _, session := range sessions {
+		if !t.Before(session.StartTime.Add(-auditCommitGrace)) && !t.After(sessionEnd(session).Add(auditCommitGrace)) {
+			return session
+		}
+	}
+	return nil
+}
+
+// hasCommitIn reports whether any commit was made between from and to, allowing auditCommitGrace either side
+func hasCommitIn(commits []auditCommit, from, to time.Time) bool {
+	for _, commit := range commits {
+		if !commit.at.Before(from.Add(-auditCommitGrace)) && !commit.at.After(to.Add(auditCommitGrace)) {
+			return true
+		}
+	}
+	return false
+}
+
+// commitBursts groups commits, oldest first, into stretches of work with no more than gap between one commit
+// and the next
+func commitBursts(commits []auditCommit, gap time.Duration) [][]auditCommit {
+	var bursts [][]auditCommit
+	for i, commit := range commits {
+		if i == 0 || commit.at.Sub(commits[i-1].at) > gap {
+			bursts = append(bursts, nil)
+		}
+		bursts[len(bursts)-1] = append(bursts[len(bursts)-1], commit)
+	}
+	return bursts
+}
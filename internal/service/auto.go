@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// AutoShells are the shells automatic tracking can hook into
+var AutoShells = []string{"bash", "zsh", "fish"}
+
+// autoRCMarker ends the line enabling automatic tracking in a shell's startup file, so it can be found again
+const autoRCMarker = "# work auto tracking"
+
+// autoState is what automatic tracking remembers between prompts: the session it started, so sessions started by
+// hand are never stopped for inactivity, and when a prompt was last shown in that session's client directory
+type autoState struct {
+	SessionID    string    `json:"session_id,omitempty"`
+	ClientName   string    `json:"client_name,omitempty"`
+	LastActivity time.Time `json:"last_activity"`
+}
+
+// AutoTrack is run by the shell hook each time a prompt is shown in dir. A session the hook started is stopped at
+// its last activity once AUTO_IDLE_MINUTES pass without one. Then, in a client's directory, a session is started
+// for the client unless one started by hand is running, switching from one the hook started for another client.
+func (s *TimesheetService) AutoTrack(ctx context.Context, dir string, now time.Time) error {
+	statePath, err := s.autoStatePath()
+	if err != nil {
+		return err
+	}
+	state, err := loadAutoState(statePath)
+	if err != nil {
+		return err
+	}
+
+	active, err := s.db.GetActiveSession(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check for active session: %w", err)
+	}
+	if active == nil || active.ID != state.SessionID {
+		// Stopped or replaced by hand since, so it's no longer the hook's to stop
+		state = autoState{}
+	}
+	if state.SessionID != "" && now.Sub(state.LastActivity) >= s.cfg.AutoIdleTimeout {
+		stopAt := state.LastActivity
+		if !stopAt.After(active.StartTime) {
+			stopAt = active.StartTime.Add(BillingPrecision)
+		}
+		if _, err := s.StopWorkAt(ctx, stopAt); err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "Stopped work session for %s at %s, after %s without activity\n",
+			state.ClientName, stopAt.Format("15:04"), s.FormatDuration(now.Sub(state.LastActivity)))
+		active, state = nil, autoState{}
+	}
+
+	client, err := s.clientForDirectory(ctx, dir)
+	if err != nil {
+		return err
+	}
+	switch {
+	case client == nil:
+		// Time outside client directories counts towards the session going idle
+	case active != nil && active.ClientID == client.ID:
+		if state.SessionID != "" {
+			state.LastActivity = now
+		}
+	case active != nil && state.SessionID == "":
+		// Leave sessions started by hand alone
+	default:
+		session, err := s.StartWork(ctx, client.Name, nil, nil, false)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "Started work session for %s at %s\n", client.Name, session.StartTime.Format("15:04:05"))
+		state = autoState{SessionID: session.ID, ClientName: client.Name, LastActivity: now}
+	}
+
+	return saveAutoState(statePath, state)
+}
+
+// clientForDirectory returns the client whose directory contains dir, the most specific one when client
+// directories are nested, or nil when dir isn't in any
+func (s *TimesheetService) clientForDirectory(ctx context.Context, dir string) (*models.Client, error) {
+	clients, err := s.db.GetClientsWithDirectories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dir = filepath.Clean(dir)
+
+	var match *models.Client
+	matchLength := 0
+	for _, client := range clients {
+		if client.Dir == nil {
+			continue
+		}
+		clientDir, err := expandHomeDir(strings.TrimSpace(*client.Dir))
+		if err != nil {
+			return nil, err
+		}
+		clientDir = filepath.Clean(clientDir)
+		if dir != clientDir && !strings.HasPrefix(dir, clientDir+string(filepath.Separator)) {
+			continue
+		}
+		if len(clientDir) > matchLength {
+			match, matchLength = client, len(clientDir)
+		}
+	}
+	return match, nil
+}
+
+// autoStatePath is where automatic tracking keeps its state for the active database, so switching databases
+// doesn't stop sessions in another
+func (s *TimesheetService) autoStatePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	sum := sha256.Sum256([]byte(s.cfg.DatabaseURL))
+	return filepath.Join(configDir, "work", fmt.Sprintf("auto-%x.json", sum[:8])), nil
+}
+
+func loadAutoState(path string) (autoState, error) {
+	var state autoState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read auto tracking state: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		// A damaged state file only means the hook forgets the session it started
+		return autoState{}, nil
+	}
+	return state, nil
+}
+
+func saveAutoState(path string, state autoState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save auto tracking state: %w", err)
+	}
+	return nil
+}
+
+// AutoHookScript is the shell code that runs the hook each time a prompt is shown, which is what 'work auto init'
+// prints for the shell's startup file to evaluate
+func AutoHookScript(shell, executable string) (string, error) {
+	switch shell {
+	case "zsh":
+		return fmt.Sprintf(`_work_auto_hook() { %q auto hook 2>/dev/null }
+typeset -ag precmd_functions
+if (( ! ${precmd_functions[(I)_work_auto_hook]} )); then
+  precmd_functions+=(_work_auto_hook)
+fi
+`, executable), nil
+	case "bash":
+		return fmt.Sprintf(`_work_auto_hook() { %q auto hook 2>/dev/null; }
+if [[ ";${PROMPT_COMMAND:-};" != *";_work_auto_hook;"* ]]; then
+  PROMPT_COMMAND="_work_auto_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`, executable), nil
+	case "fish":
+		return fmt.Sprintf(`function _work_auto_hook --on-event fish_prompt
+  %q auto hook 2>/dev/null
+end
+`, executable), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q, expected one of %s", shell, strings.Join(AutoShells, ", "))
+	}
+}
+
+// autoRCFile is the startup file for an interactive shell
+func autoRCFile(shell string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting home directory: %w", err)
+	}
+	switch shell {
+	case "zsh":
+		return filepath.Join(home, ".zshrc"), nil
+	case "bash":
+		return filepath.Join(home, ".bashrc"), nil
+	case "fish":
+		return filepath.Join(home, ".config", "fish", "config.fish"), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q, expected one of %s", shell, strings.Join(AutoShells, ", "))
+	}
+}
+
+// EnableAutoTracking adds a line to the shell's startup file that installs the hook, doing nothing if it's
+// already there. The hook takes effect in shells started afterwards.
+func (s *TimesheetService) EnableAutoTracking(ctx context.Context, shell, executable string) error {
+	rcFile, err := autoRCFile(shell)
+	if err != nil {
+		return err
+	}
+	lines, err := readRCLines(rcFile)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if strings.HasSuffix(line, autoRCMarker) {
+			fmt.Fprintf(s.out, "Auto tracking is already enabled in %s\n", rcFile)
+			return nil
+		}
+	}
+
+	line := fmt.Sprintf(`eval "$(%q auto init %s)" %s`, executable, shell, autoRCMarker)
+	if shell == "fish" {
+		line = fmt.Sprintf("%q auto init fish | source %s", executable, autoRCMarker)
+	}
+	if err := os.MkdirAll(filepath.Dir(rcFile), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(rcFile), err)
+	}
+	f, err := os.OpenFile(rcFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", rcFile, err)
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintln(f, line); err != nil {
+		return fmt.Errorf("failed to write %s: %w", rcFile, err)
+	}
+
+	clients, err := s.db.GetClientsWithDirectories(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Enabled auto tracking in %s, open a new shell for it to take effect\n", rcFile)
+	fmt.Fprintf(s.out, "Sessions start in the directories of %d client(s) and stop after %s without a prompt in them\n",
+		len(clients), s.FormatDuration(s.cfg.AutoIdleTimeout))
+	if len(clients) == 0 {
+		fmt.Fprintln(s.out, "Set a client's directory with: work clients update <client> -r <rate> -d <dir>")
+	}
+	return nil
+}
+
+// DisableAutoTracking removes the line EnableAutoTracking added to the shell's startup file
+func (s *TimesheetService) DisableAutoTracking(shell string) error {
+	rcFile, err := autoRCFile(shell)
+	if err != nil {
+		return err
+	}
+	lines, err := readRCLines(rcFile)
+	if err != nil {
+		return err
+	}
+
+	var kept []string
+	for _, line := range lines {
+		if !strings.HasSuffix(line, autoRCMarker) {
+			kept = append(kept, line)
+		}
+	}
+	if len(kept) == len(lines) {
+		fmt.Fprintf(s.out, "Auto tracking isn't enabled in %s\n", rcFile)
+		return nil
+	}
+
+	content := strings.Join(kept, "\n")
+	if len(kept) > 0 {
+		content += "\n"
+	}
+	if err := os.WriteFile(rcFile, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", rcFile, err)
+	}
+	fmt.Fprintf(s.out, "Disabled auto tracking in %s, it stops in shells started afterwards\n", rcFile)
+	return nil
+}
+
+func readRCLines(rcFile string) ([]string, error) {
+	data, err := os.ReadFile(rcFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rcFile, err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+var quarterPattern = regexp.MustCompile(`^(\d{4})[Qq]([1-4])$`)
+
+// ParseQuarter reads a calendar quarter such as 2025Q1 (January to March 2025), returning its first and
+// last days
+func ParseQuarter(quarter string) (time.Time, time.Time, error) {
+	match := quarterPattern.FindStringSubmatch(strings.TrimSpace(quarter))
+	if match == nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("quarter must look like 2025Q1, got %q", quarter)
+	}
+	year, _ := strconv.Atoi(match[1])
+	q, _ := strconv.Atoi(match[2])
+	start := time.Date(year, time.Month((q-1)*3+1), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 3, -1)
+	return start, end, nil
+}
+
+// basLine is one amount counted towards a BAS label, kept for the CSV export
+type basLine struct {
+	label       string
+	date        time.Time
+	reference   string
+	client      string
+	description string
+	amount      decimal.Decimal
+	gst         decimal.Decimal
+}
+
+// basSummary totals GST for a BAS period. Sales and purchases include GST. Export and other GST-free sales
+// are part of total sales as well as being reported on their own.
+type basSummary struct {
+	sales          decimal.Decimal // G1
+	exportSales    decimal.Decimal // G2
+	gstFreeSales   decimal.Decimal // G3
+	gstOnSales     decimal.Decimal // 1A
+	purchases      decimal.Decimal // G11
+	gstOnPurchases decimal.Decimal // 1B
+	lines          []basLine
+	// skipped counts amounts left out because they weren't in the home currency
+	skipped int
+}
+
+// ShowGSTReport totals GST collected on sales and paid on expenses for the quarter, for lodging a BAS.
+// On a cash basis sales are counted when payments are received, and on an accrual basis when invoices
+// are issued, less any credit notes, invoices voided and amounts written off in the quarter. Expenses are counted on their date either way. When csvOutput is set
+// every amount counted is also written there as CSV, or to stdout when it's "-".
+func (s *TimesheetService) ShowGSTReport(ctx context.Context, quarter, basis, csvOutput string) error {
+	start, end, err := ParseQuarter(quarter)
+	if err != nil {
+		return err
+	}
+	if basis == "" {
+		basis = s.gstBasis()
+	}
+	basis = strings.ToLower(basis)
+	if basis != config.GSTBasisCash && basis != config.GSTBasisAccrual {
+		return fmt.Errorf("basis must be %q or %q, got %q", config.GSTBasisCash, config.GSTBasisAccrual, basis)
+	}
+
+	summary, err := s.calculateBAS(ctx, start, end, basis)
+	if err != nil {
+		return err
+	}
+
+	if csvOutput != "" {
+		if err := writeBASCSV(s.out, summary, csvOutput); err != nil {
+			return err
+		}
+		if csvOutput == "-" {
+			return nil
+		}
+	}
+
+	m := s.homeMoney()
+	fmt.Fprintf(s.out, "GST for %s (%s to %s), %s basis\n", strings.ToUpper(quarter), start.Format("2006-01-02"), end.Format("2006-01-02"), basis)
+	if !s.cfg.GSTRegistered {
+		fmt.Fprintln(s.out, "GST_REGISTERED is off, so invoices haven't been charging GST")
+	}
+	fmt.Fprintln(s.out)
+	fmt.Fprintf(s.out, "  %-4s %-32s %15s\n", "G1", "Total sales (including GST)", m.Format(summary.sales))
+	fmt.Fprintf(s.out, "  %-4s %-32s %15s\n", "G2", "Export sales", m.Format(summary.exportSales))
+	fmt.Fprintf(s.out, "  %-4s %-32s %15s\n", "G3", "Other GST-free sales", m.Format(summary.gstFreeSales))
+	fmt.Fprintf(s.out, "  %-4s %-32s %15s\n", "1A", "GST on sales", m.Format(summary.gstOnSales))
+	fmt.Fprintf(s.out, "  %-4s %-32s %15s\n", "G11", "Purchases (including GST)", m.Format(summary.purchases))
+	fmt.Fprintf(s.out, "  %-4s %-32s %15s\n", "1B", "GST on purchases", m.Format(summary.gstOnPurchases))
+
+	net := summary.gstOnSales.Sub(summary.gstOnPurchases)
+	if net.IsNegative() {
+		fmt.Fprintf(s.out, "  %-4s %-32s %15s\n", "", "GST refundable", m.Format(net.Neg()))
+	} else {
+		fmt.Fprintf(s.out, "  %-4s %-32s %15s\n", "", "GST payable", m.Format(net))
+	}
+
+	if summary.skipped > 0 {
+		fmt.Fprintf(s.out, "\n%d amount(s) in other currencies were left out, add them in %s yourself\n", summary.skipped, m.Currency)
+	}
+	if csvOutput != "" {
+		fmt.Fprintf(s.out, "\nWrote %d line(s) to %s\n", len(summary.lines), csvOutput)
+	}
+	return nil
+}
+
+// calculateBAS totals sales and purchases between start and end (inclusive days) on the given basis
+func (s *TimesheetService) calculateBAS(ctx context.Context, start, end time.Time, basis string) (*basSummary, error) {
+	from := start
+	to := end.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	inPeriod := func(t time.Time) bool {
+		return !t.Before(from) && !t.After(to)
+	}
+
+	invoices, err := s.db.ListInvoices(ctx, -1)
+	if err != nil {
+		return nil, err
+	}
+	invoicesByID := make(map[string]*models.Invoice, len(invoices))
+	for _, invoice := range invoices {
+		invoicesByID[invoice.ID] = invoice
+	}
+	clients, err := s.db.ListClients(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+	clientsByID := make(map[string]*models.Client, len(clients))
+	for _, client := range clients {
+		clientsByID[client.ID] = client
+	}
+
+	home := s.homeMoney().Currency
+	summary := &basSummary{}
+	// Sales are labelled G1, or G2 and G3 for export and other GST-free clients' sales that weren't charged GST,
+	// such as those invoiced before the client became GST-free
+	addSale := func(line basLine, clientID string) {
+		client := clientsByID[clientID]
+		if s.clientMoney(client).Currency != home {
+			summary.skipped++
+			return
+		}
+		line.label = "G1"
+		if line.gst.IsZero() && client != nil {
+			switch client.TaxTreatment {
+			case models.TaxTreatmentExport:
+				line.label = "G2"
+				summary.exportSales = summary.exportSales.Add(line.amount)
+			case models.TaxTreatmentGSTFree:
+				line.label = "G3"
+				summary.gstFreeSales = summary.gstFreeSales.Add(line.amount)
+			}
+		}
+		summary.sales = summary.sales.Add(line.amount)
+		summary.gstOnSales = summary.gstOnSales.Add(line.gst)
+		summary.lines = append(summary.lines, line)
+	}
+
+	if basis == config.GSTBasisCash {
+		payments, err := s.db.ListPaymentsByDateRange(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, payment := range payments {
+			invoice, ok := invoicesByID[payment.InvoiceID]
+			if !ok {
+				continue
+			}
+			// Each payment carries the invoice's share of GST
+			gst := decimal.Zero
+			if invoice.TotalAmount.IsPositive() {
+				gst = payment.Amount.Mul(invoice.GstAmount).Div(invoice.TotalAmount).Round(2)
+			}
+			addSale(basLine{
+				date:        payment.PaymentDate,
+				reference:   invoice.InvoiceNumber,
+				client:      invoice.ClientName,
+				description: "Payment received",
+				amount:      payment.Amount,
+				gst:         gst,
+			}, invoice.ClientID)
+		}
+	} else {
+		creditNotes, err := s.db.ListCreditNotes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		creditedGST := make(map[string]decimal.Decimal)
+		for _, creditNote := range creditNotes {
+			creditedGST[creditNote.InvoiceID] = creditedGST[creditNote.InvoiceID].Add(creditNote.GstAmount)
+		}
+
+		for _, invoice := range invoices {
+			if invoice.Status == models.InvoiceStatusDraft {
+				continue
+			}
+			var closedAt time.Time
+			if invoice.StatusChangedAt != nil {
+				closedAt = *invoice.StatusChangedAt
+			}
+			// An invoice voided in the period it was issued is left out altogether
+			if invoice.Status == models.InvoiceStatusVoid && inPeriod(invoice.GeneratedDate) && inPeriod(closedAt) {
+				continue
+			}
+			if inPeriod(invoice.GeneratedDate) {
+				addSale(basLine{
+					date:        invoice.GeneratedDate,
+					reference:   invoice.InvoiceNumber,
+					client:      invoice.ClientName,
+					description: "Invoice issued",
+					amount:      invoice.TotalAmount,
+					gst:         invoice.GstAmount,
+				}, invoice.ClientID)
+			}
+			if !inPeriod(closedAt) {
+				continue
+			}
+			switch invoice.Status {
+			case models.InvoiceStatusVoid:
+				// Voiding reverses what's left of the invoice after any credit notes, in the period it's voided
+				addSale(basLine{
+					date:        closedAt,
+					reference:   invoice.InvoiceNumber,
+					client:      invoice.ClientName,
+					description: "Invoice voided" + statusReasonSuffix(invoice),
+					amount:      invoice.TotalAmount.Sub(invoice.AmountCredited).Neg(),
+					gst:         invoice.GstAmount.Sub(creditedGST[invoice.ID]).Neg(),
+				}, invoice.ClientID)
+			case models.InvoiceStatusWrittenOff:
+				// A bad debt written off is an adjustment to sales in the period it's written off
+				addSale(basLine{
+					date:        closedAt,
+					reference:   invoice.InvoiceNumber,
+					client:      invoice.ClientName,
+					description: "Written off" + statusReasonSuffix(invoice),
+					amount:      invoice.AmountWrittenOff.Neg(),
+					gst:         writtenOffGST(invoice, invoice.AmountWrittenOff).Neg(),
+				}, invoice.ClientID)
+			}
+		}
+
+		for _, creditNote := range creditNotes {
+			invoice, ok := invoicesByID[creditNote.InvoiceID]
+			if !ok || !inPeriod(creditNote.IssuedDate) {
+				continue
+			}
+			// Credit notes are adjustments, taken off sales in the period they're issued
+			addSale(basLine{
+				date:        creditNote.IssuedDate,
+				reference:   creditNote.CreditNoteNumber,
+				client:      invoice.ClientName,
+				description: "Credit note: " + creditNote.Reason,
+				amount:      creditNote.Amount.Neg(),
+				gst:         creditNote.GstAmount.Neg(),
+			}, invoice.ClientID)
+		}
+	}
+
+	expenses, err := s.db.ListExpensesByDateRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, expense := range expenses {
+		if expense.Draft {
+			continue
+		}
+		var client *models.Client
+		if expense.ClientID != nil {
+			client = clientsByID[*expense.ClientID]
+		}
+		if s.clientMoney(client).Currency != home {
+			summary.skipped++
+			continue
+		}
+		gst := decimal.Zero
+		if expense.GstAmount != nil {
+			gst = *expense.GstAmount
+		}
+		line := basLine{
+			label:       "G11",
+			date:        expense.ExpenseDate,
+			reference:   expenseReference(expense),
+			client:      clientName(client),
+			description: utils.FromPtr(expense.Description),
+			amount:      expense.Amount,
+			gst:         gst,
+		}
+		summary.purchases = summary.purchases.Add(line.amount)
+		summary.gstOnPurchases = summary.gstOnPurchases.Add(line.gst)
+		summary.lines = append(summary.lines, line)
+	}
+
+	return summary, nil
+}
+
+// writeBASCSV writes each amount counted on the BAS followed by the label totals, to out when output is "-". Lines
+// labelled G2 and G3 count towards G1 too.
+func writeBASCSV(out io.Writer, summary *basSummary, output string) error {
+	file := out
+	if output != "-" {
+		created, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer created.Close()
+		file = created
+	}
+
+	writer := csv.NewWriter(file)
+	rows := [][]string{{"label", "date", "reference", "client", "description", "amount", "gst"}}
+	for _, line := range summary.lines {
+		rows = append(rows, []string{line.label, line.date.Format("2006-01-02"), line.reference, line.client, line.description,
+			line.amount.StringFixed(2), line.gst.StringFixed(2)})
+	}
+	rows = append(rows,
+		[]string{"G1", "", "", "", "Total sales (including GST)", summary.sales.StringFixed(2), ""},
+		[]string{"G2", "", "", "", "Export sales", summary.exportSales.StringFixed(2), ""},
+		[]string{"G3", "", "", "", "Other GST-free sales", summary.gstFreeSales.StringFixed(2), ""},
+		[]string{"1A", "", "", "", "GST on sales", summary.gstOnSales.StringFixed(2), ""},
+		[]string{"G11", "", "", "", "Purchases (including GST)", summary.purchases.StringFixed(2), ""},
+		[]string{"1B", "", "", "", "GST on purchases", summary.gstOnPurchases.StringFixed(2), ""},
+	)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+func (s *TimesheetService) gstBasis() string {
+	if s.cfg == nil || s.cfg.GSTBasis == "" {
+		return config.GSTBasisCash
+	}
+	return s.cfg.GSTBasis
+}
+
+func clientName(client *models.Client) string {
+	if client == nil {
+		return ""
+	}
+	return client.Name
+}
+
+func expenseReference(expense *models.Expense) string {
+	if expense.Reference != nil && *expense.Reference != "" {
+		return *expense.Reference
+	}
+	return expense.ID
+}
+
+// statusReasonSuffix is ": <reason>" for an invoice written off or voided with a reason
+func statusReasonSuffix(invoice *models.Invoice) string {
+	if invoice.StatusReason == nil {
+		return ""
+	}
+	return ": " + *invoice.StatusReason
+}
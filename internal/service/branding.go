@@ -0,0 +1,124 @@
+package service
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// logoImageTypes maps the image formats logos can be, as image.DecodeConfig names them, to gofpdf's image types
+var logoImageTypes = map[string]string{"png": "PNG", "jpeg": "JPG", "gif": "GIF"}
+
+// Logos are scaled to fit in this box, in mm, at the top right of an invoice's first page
+const (
+	logoMaxWidth  = 50.0
+	logoMaxHeight = 20.0
+)
+
+// invoiceLogo is an image checked to be printable on a PDF invoice
+type invoiceLogo struct {
+	Path string
+	Type string // gofpdf image type, "PNG", "JPG" or "GIF"
+}
+
+// readLogo checks the image at path is a PNG, JPEG or GIF that can be printed on invoices, whatever its extension,
+// and returns it with a leading ~ expanded and the path made absolute
+func readLogo(path string) (*invoiceLogo, error) {
+	path, err := expandHomeDir(strings.TrimSpace(path))
+	if err != nil {
+		return nil, err
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open logo: %w", err)
+	}
+	defer file.Close()
+	_, format, err := image.DecodeConfig(file)
+	if err != nil {
+		return nil, fmt.Errorf("logo %s isn't a PNG, JPEG or GIF image: %w", path, err)
+	}
+	imageType, ok := logoImageTypes[format]
+	if !ok {
+		return nil, fmt.Errorf("logo %s is a %s image, use a PNG, JPEG or GIF", path, format)
+	}
+
+	// Some images decode but can't be embedded, such as interlaced or 16 bit PNGs, so it's tried on a blank PDF
+	probe := gofpdf.New("P", "mm", "A4", "")
+	probe.RegisterImageOptions(path, gofpdf.ImageOptions{ImageType: imageType})
+	if err := probe.Error(); err != nil {
+		return nil, fmt.Errorf("logo %s can't be printed on invoices: %w", path, err)
+	}
+	return &invoiceLogo{Path: path, Type: imageType}, nil
+}
+
+// invoiceBranding returns the logo and heading colour for a client's invoices: the client's own, falling back to
+// BILLING_LOGO and BILLING_BRAND_COLOUR. A logo that can no longer be read is warned about and left off, so
+// invoices are still generated when an image has moved. An empty colour means headings are black.
+func (s *TimesheetService) invoiceBranding(client *models.Client) (*invoiceLogo, string) {
+	var logo *invoiceLogo
+	for _, path := range []string{strings.TrimSpace(utils.FromPtr(client.LogoPath)), s.cfg.BillingLogo} {
+		if path == "" {
+			continue
+		}
+		var err error
+		if logo, err = readLogo(path); err == nil {
+			break
+		}
+		s.logger.Warn("leaving logo off invoice", "client", client.Name, "logo", path, "error", err)
+	}
+
+	colour := s.cfg.BillingBrandColour
+	if client.BrandColour != nil {
+		if clientColour, err := config.ParseColour(*client.BrandColour); err == nil {
+			colour = clientColour
+		}
+	}
+	return logo, colour
+}
+
+// colourRGB splits a #rrggbb colour into its red, green and blue components
+func colourRGB(colour string) (int, int, int) {
+	value, _ := strconv.ParseUint(strings.TrimPrefix(colour, "#"), 16, 32)
+	return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff)
+}
+
+// withBrandColour draws text in the invoice's brand colour, or in black when it doesn't have one
+func withBrandColour(pdf *gofpdf.Fpdf, doc *invoiceDocument, draw func()) {
+	if doc.BrandColour == "" {
+		draw()
+		return
+	}
+	pdf.SetTextColor(colourRGB(doc.BrandColour))
+	draw()
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// renderInvoiceLogo places the invoice's logo at the top right of the current page, scaled to fit in the logo box
+func renderInvoiceLogo(pdf *gofpdf.Fpdf, logo *invoiceLogo) {
+	options := gofpdf.ImageOptions{ImageType: logo.Type, ReadDpi: true}
+	info := pdf.RegisterImageOptions(logo.Path, options)
+	if info == nil || info.Width() <= 0 || info.Height() <= 0 {
+		return
+	}
+	scale := min(logoMaxWidth/info.Width(), logoMaxHeight/info.Height())
+	width, height := info.Width()*scale, info.Height()*scale
+	_, top, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	pdf.ImageOptions(logo.Path, pageWidth-right-width, top, width, height, false, options, 0, "")
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+func TestInvoiceBrandingFallsBackToDefaults(t *testing.T) {
+	dir := t.TempDir()
+	logoPath := filepath.Join(dir, "logo.img")
+	file, err := os.Create(logoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(file, image.NewRGBA(image.Rect(0, 0, 30, 10))); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	notImage := filepath.Join(dir, "logo.png")
+	if err := os.WriteFile(notImage, []byte("not an image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readLogo(notImage); err == nil {
+		t.Errorf("readLogo(%s) succeeded, want an error for a file that isn't an image", notImage)
+	}
+
+	s := NewTimesheetService(nil, &config.Config{BillingLogo: logoPath, BillingBrandColour: "#1f6feb"})
+	colour := "#aa0000"
+	missing := filepath.Join(dir, "moved.png")
+	tests := []struct {
+		name       string
+		client     *models.Client
+		wantLogo   string
+		wantColour string
+	}{
+		{"defaults", &models.Client{Name: "acme"}, logoPath, "#1f6feb"},
+		{"client's own", &models.Client{Name: "acme", LogoPath: &logoPath, BrandColour: &colour}, logoPath, "#aa0000"},
+		{"moved logo", &models.Client{Name: "acme", LogoPath: &missing}, logoPath, "#1f6feb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logo, colour := s.invoiceBranding(tt.client)
+			if logo == nil || logo.Path != tt.wantLogo || logo.Type != "PNG" || colour != tt.wantColour {
+				t.Errorf("invoiceBranding = %+v, %q, want %s as a PNG, %q", logo, colour, tt.wantLogo, tt.wantColour)
+			}
+		})
+	}
+
+	if logo, colour := NewTimesheetService(nil, &config.Config{}).invoiceBranding(&models.Client{Name: "acme"}); logo != nil || colour != "" {
+		t.Errorf("invoiceBranding without branding = %+v, %q, want no logo and the default colour", logo, colour)
+	}
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/quickadd"
+)
+
+// ClientMatches returns the clients a name given on the command line could mean, and whether it's a client's
+// exact name. Unless client matching is strict, a name that isn't exact matches clients ignoring case, by
+// prefix, by substring or by a close spelling, as quick adds do.
+func (s *TimesheetService) ClientMatches(ctx context.Context, name string) ([]string, bool, error) {
+	if s.cfg != nil && s.cfg.ClientMatching == config.ClientMatchingStrict {
+		return []string{name}, true, nil
+	}
+
+	clients, err := s.db.ListClients(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list clients: %w", err)
+	}
+	names := make([]string, len(clients))
+	for i, client := range clients {
+		if client.Name == name {
+			return []string{name}, true, nil
+		}
+		names[i] = client.Name
+	}
+	return quickadd.ClientCandidates(name, names), false, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// sessionRate is the rate a new session starting at start is billed at: the override if there is one, otherwise
+// the client's rate in effect on that day
+func (s *TimesheetService) sessionRate(ctx context.Context, client *models.Client, override *decimal.Decimal, start time.Time) decimal.Decimal {
+	if override != nil {
+		return *override
+	}
+	history, err := s.syncClientRate(ctx, client)
+	if err != nil {
+		s.logger.Warn("failed to get client rate history, using its current rate", "client", client.Name, "error", err)
+		return client.HourlyRate
+	}
+	return clientRateOn(client, history, start)
+}
+
+// syncClientRate brings a client's rate in line with its rate history, so a change scheduled ahead becomes its
+// rate once it takes effect, and returns the history
+func (s *TimesheetService) syncClientRate(ctx context.Context, client *models.Client) ([]*models.ClientRate, error) {
+	history, err := s.db.ListClientRates(ctx, client.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current := clientRateOn(client, history, time.Now()); !current.Equal(client.HourlyRate) {
+		if err := s.db.UpdateClientRate(ctx, client.ID, current); err != nil {
+			return nil, err
+		}
+		client.HourlyRate = current
+	}
+	return history, nil
+}
+
+// clientRateOn is a client's hourly rate on the day at falls on, going by its rate history in the order the
+// changes take effect. Before the first change it's the rate that change replaced, and without any history it's
+// the client's rate.
+func clientRateOn(client *models.Client, history []*models.ClientRate, at time.Time) decimal.Decimal {
+	if len(history) == 0 {
+		return client.HourlyRate
+	}
+	rate := history[0].PreviousRate
+	for _, change := range history {
+		if change.EffectiveDate.After(at) {
+			break
+		}
+		rate = change.HourlyRate
+	}
+	return rate
+}
+
+// ProposeRateChange works out each client's hourly rate after raising it by percent, or lowering it for a
+// negative percent, from effective on. Clients without an hourly rate and those named in exclude are left out.
+func (s *TimesheetService) ProposeRateChange(ctx context.Context, percent float64, effective time.Time, exclude []string) ([]*models.ClientRate, error) {
+	if percent == 0 || percent <= -100 {
+		return nil, fmt.Errorf("percent must be more than -100 and not 0, got %g", percent)
+	}
+	clients, err := s.db.ListClients(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+	excluded := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		excluded[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	effective = startOfDay(effective)
+	factor := decimal.NewFromFloat(percent).Div(decimal.NewFromInt(100)).Add(decimal.NewFromInt(1))
+	var changes []*models.ClientRate
+	for _, client := range clients {
+		if excluded[strings.ToLower(client.Name)] {
+			continue
+		}
+		history, err := s.db.ListClientRates(ctx, client.ID)
+		if err != nil {
+			return nil, err
+		}
+		// Raised from the rate it would otherwise have then, so a rise already scheduled is built on
+		previous := clientRateOn(client, history, effective)
+		if !previous.IsPositive() {
+			continue
+		}
+		changes = append(changes, &models.ClientRate{
+			ClientID:      client.ID,
+			ClientName:    client.Name,
+			PreviousRate:  previous,
+			HourlyRate:    previous.Mul(factor).Round(2),
+			EffectiveDate: effective,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ClientName < changes[j].ClientName })
+	return changes, nil
+}
+
+// DisplayRateChanges prints each client's rate before and after a change, with the difference
+func (s *TimesheetService) DisplayRateChanges(changes []*models.ClientRate) {
+	if len(changes) == 0 {
+		fmt.Fprintln(s.out, "No clients with an hourly rate to change.")
+		return
+	}
+	fmt.Fprintf(s.out, "Rates from %s:\n\n", changes[0].EffectiveDate.Format("2006-01-02"))
+	fmt.Fprintf(s.out, "%-20s %14s %14s %12s\n", "CLIENT", "BEFORE", "AFTER", "CHANGE")
+	fmt.Fprintln(s.out, strings.Repeat("-", 63))
+	for _, change := range changes {
+		m := s.clientMoneyByName(change.ClientName)
+		diff, sign := change.HourlyRate.Sub(change.PreviousRate), "+"
+		if diff.IsNegative() {
+			sign = "-"
+		}
+		fmt.Fprintf(s.out, "%-20s %14s %14s %12s\n", truncateString(change.ClientName, 20), m.Format(change.PreviousRate)+"/hr",
+			m.Format(change.HourlyRate)+"/hr", sign+m.Format(diff.Abs()))
+	}
+}
+
+// ApplyRateChanges records rate changes in each client's rate history. Changes that have already taken effect
+// become the clients' rates straight away, and ones scheduled ahead are picked up by sessions starting from
+// their effective date. Sessions already tracked keep the rates they were created with.
+func (s *TimesheetService) ApplyRateChanges(ctx context.Context, changes []*models.ClientRate) ([]*models.ClientRate, error) {
+	applied, err := s.db.RecordClientRates(ctx, changes)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range applied {
+		client, err := s.db.GetClientByID(ctx, change.ClientID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client: %w", err)
+		}
+		if _, err := s.syncClientRate(ctx, client); err != nil {
+			return nil, fmt.Errorf("failed to update %s's rate: %w", client.Name, err)
+		}
+		if s.belowMinimumRate(client, change.HourlyRate) {
+			s.logger.Warn("new client rate is below your minimum rate",
+				"client", client.Name,
+				"rate", s.FormatClientMoney(client, change.HourlyRate)+"/hr",
+				"minimum", s.formatMinimumRate())
+		}
+	}
+	return applied, nil
+}
+
+// recordClientRateChange adds a client's rate being changed directly to its rate history, taking effect today,
+// so sessions go by it rather than an earlier change
+func (s *TimesheetService) recordClientRateChange(ctx context.Context, client *models.Client, previous decimal.Decimal) error {
+	_, err := s.db.RecordClientRates(ctx, []*models.ClientRate{{
+		ClientID:      client.ID,
+		ClientName:    client.Name,
+		PreviousRate:  previous,
+		HourlyRate:    client.HourlyRate,
+		EffectiveDate: startOfDay(time.Now()),
+	}})
+	return err
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+func TestClientRateOnFollowsHistory(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	client := &models.Client{Name: "acme", HourlyRate: decimal.NewFromInt(220)}
+	history := []*models.ClientRate{
+		{PreviousRate: decimal.NewFromInt(150), HourlyRate: decimal.NewFromInt(200), EffectiveDate: date(2025, 1, 1)},
+		{PreviousRate: decimal.NewFromInt(200), HourlyRate: decimal.NewFromInt(220), EffectiveDate: date(2025, 7, 1)},
+	}
+
+	tests := []struct {
+		name    string
+		history []*models.ClientRate
+		at      time.Time
+		want    string
+	}{
+		{name: "without history", at: date(2024, 6, 1), want: "220"},
+		{name: "before the first change", history: history, at: date(2024, 12, 31), want: "150"},
+		{name: "on the day a change takes effect", history: history, at: date(2025, 1, 1), want: "200"},
+		{name: "between changes", history: history, at: date(2025, 6, 30).Add(23 * time.Hour), want: "200"},
+		{name: "after the last change", history: history, at: date(2026, 1, 1), want: "220"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clientRateOn(client, tt.history, tt.at); got.String() != tt.want {
+				t.Errorf("clientRateOn() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
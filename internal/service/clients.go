@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+)
+
+// averageWeeks is how many recent weeks the average weekly hours are taken over
+const averageWeeks = 12
+
+// recentContacts is how many of a client's most recent contacts are shown with them
+const recentContacts = 5
+
+// ShowClient prints a client's contact and billing details, followed by a summary of the work done for them,
+// what they owe, their progress through any fixed-price milestones and the last few contacts with them
+func (s *TimesheetService) ShowClient(ctx context.Context, clientName string) error {
+	client, err := s.db.GetClientByName(ctx, clientName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("client '%s' does not exist", clientName)
+		}
+		return fmt.Errorf("failed to get client: %w", err)
+	}
+	activity, err := s.db.GetClientActivity(ctx, client.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	monthStart, _ := s.CalculatePeriodRange("month", now)
+	monthHours, err := s.db.GetClientHoursSince(ctx, client.ID, monthStart)
+	if err != nil {
+		return err
+	}
+	recentHours, err := s.db.GetClientHoursSince(ctx, client.ID, now.AddDate(0, 0, -7*averageWeeks))
+	if err != nil {
+		return err
+	}
+
+	hours := func(h float64) string {
+		return s.FormatDurationFor(config.DurationContextReport, time.Duration(h*float64(time.Hour)))
+	}
+
+	s.DisplayClient(ctx, client)
+	fmt.Fprintln(s.out)
+	fmt.Fprintln(s.out, "Activity:")
+	fmt.Fprintf(s.out, "  This month: %s\n", hours(monthHours))
+	fmt.Fprintf(s.out, "  Lifetime: %s across %d session(s)\n", hours(activity.TotalHours), activity.SessionCount)
+	fmt.Fprintf(s.out, "  Average per week: %s over the last %d weeks\n", hours(recentHours/averageWeeks), averageWeeks)
+	if activity.LastSession != nil {
+		days := int(now.Sub(*activity.LastSession).Hours() / 24)
+		fmt.Fprintf(s.out, "  Last session: %s (%d day(s) ago)\n", activity.LastSession.Format("2006-01-02"), days)
+	} else {
+		fmt.Fprintln(s.out, "  Last session: never")
+	}
+
+	if client.RetainerHours != nil && *client.RetainerHours > 0 && client.RetainerBasis != nil {
+		periodStart, _ := s.CalculatePeriodRange(*client.RetainerBasis, now)
+		used, err := s.db.GetClientHoursSince(ctx, client.ID, periodStart)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "  Retainer: %s of %s used this %s (%.0f%%)\n",
+			hours(used), hours(*client.RetainerHours), *client.RetainerBasis, used / *client.RetainerHours * 100)
+	}
+
+	fmt.Fprintln(s.out)
+	fmt.Fprintln(s.out, "Billing:")
+	fmt.Fprintf(s.out, "  Invoiced: %s across %d invoice(s)\n", s.FormatClientMoney(client, activity.TotalInvoiced), activity.InvoiceCount)
+	fmt.Fprintf(s.out, "  Outstanding: %s\n", s.FormatClientMoney(client, activity.Outstanding))
+
+	rates, err := s.db.ListClientRates(ctx, client.ID)
+	if err != nil {
+		return err
+	}
+	if len(rates) > 0 {
+		fmt.Fprintln(s.out)
+		fmt.Fprintln(s.out, "Rate changes:")
+		for _, rate := range rates {
+			scheduled := ""
+			if rate.EffectiveDate.After(now) {
+				scheduled = " (scheduled)"
+			}
+			fmt.Fprintf(s.out, "  %s: %s/hr to %s/hr%s\n", rate.EffectiveDate.Format("2006-01-02"), s.FormatClientMoney(client, rate.PreviousRate),
+				s.FormatClientMoney(client, rate.HourlyRate), scheduled)
+		}
+	}
+
+	if err := s.displayMilestoneProgress(ctx, client, s.clientMoney(client)); err != nil {
+		return err
+	}
+
+	contacts, err := s.db.ListClientContacts(ctx, &client.ID, recentContacts)
+	if err != nil {
+		return err
+	}
+	if len(contacts) > 0 {
+		fmt.Fprintln(s.out)
+		fmt.Fprintln(s.out, "Recent contacts:")
+		for _, contact := range contacts {
+			fmt.Fprintf(s.out, "  %s\n", s.contactSummary(contact))
+		}
+	}
+	return nil
+}
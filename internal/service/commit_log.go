@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// Ways session descriptions can be generated
+const (
+	// DescriptionModeAI summarises each repository's changes with opencode
+	DescriptionModeAI = "ai"
+	// DescriptionModeCommits lists the commit subjects in the session window, without any LLM
+	DescriptionModeCommits = "commits"
+)
+
+// DescriptionModes are the accepted values for descriptions generate --mode
+var DescriptionModes = []string{DescriptionModeAI, DescriptionModeCommits}
+
+// ValidateDescriptionMode returns an error if mode isn't one of DescriptionModes
+func ValidateDescriptionMode(mode string) error {
+	for _, valid := range DescriptionModes {
+		if mode == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid mode %q, expected one of: %s", mode, strings.Join(DescriptionModes, ", "))
+}
+
+// repoCommits are the commit subjects made in one repository during a session, oldest first
+type repoCommits struct {
+	repo     string
+	path     string
+	subjects []string
+	lines    []string // short hash and subject, for the full work summary
+}
+
+// describeFromCommits builds a session description from the subjects of the commits made in the client's
+// repositories during the session. Repeated subjects are listed once, and commits are grouped by repository
+// when there's more than one, so the same commits always make the same description.
+func (s *TimesheetService) describeFromCommits(ctx context.Context, client *models.Client, session *models.WorkSession) (*DescriptionResult, error) {
+	if session.EndTime == nil {
+		return nil, ErrSessionNotFinished
+	}
+	if utils.FromPtr(client.Dir) == "" {
+		return nil, ErrConfiguredClientRequired
+	}
+	dir, err := expandClientDir(*client.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := s.findGitRepositoriesWalk(dir)
+	sort.Strings(repos)
+	var found []repoCommits
+	for _, repo := range repos {
+		commits, err := s.sessionCommits(ctx, repo, session.StartTime, *session.EndTime)
+		if err != nil {
+			s.logger.Warn("failed to read git log", "repo", repo, "error", err)
+			continue
+		}
+		if len(commits.subjects) > 0 {
+			found = append(found, commits)
+		}
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no commits in %s between %s and %s", dir,
+			session.StartTime.Format("2006-01-02 15:04"), session.EndTime.Format("15:04"))
+	}
+
+	// The description is kept to one line so it reads well in session lists and on invoices, e.g.
+	// "api: Add login endpoint; Fix tests. web: Style login form"
+	var groups []string
+	var full strings.Builder
+	var sessionRepos []*models.SessionRepo
+	for _, commits := range found {
+		sessionRepos = append(sessionRepos, &models.SessionRepo{RepoPath: commits.path, CommitCount: int64(len(commits.lines))})
+		group := strings.Join(commits.subjects, "; ")
+		if len(found) > 1 {
+			group = commits.repo + ": " + group
+		}
+		groups = append(groups, group)
+		fmt.Fprintf(&full, "=== %s ===\n%s\n\n", commits.repo, strings.Join(commits.lines, "\n"))
+	}
+
+	return &DescriptionResult{
+		FinalSummary:    strings.Join(groups, ". "),
+		FullWorkSummary: strings.TrimSpace(full.String()),
+		Repos:           sessionRepos,
+	}, nil
+}
+
+// sessionCommits reads the commits made in a repository between from and to on any branch, leaving out merges
+func (s *TimesheetService) sessionCommits(ctx context.Context, repo string, from, to time.Time) (repoCommits, error) {
+	commits := repoCommits{repo: filepath.Base(repo), path: repo}
+	cmd := exec.CommandContext(ctx, "git", "-C", repo, "log", "--all", "--no-merges", "--reverse",
+		"--since="+from.Format(time.RFC3339), "--until="+to.Format(time.RFC3339), "--format=%h %s")
+	output, err := cmd.Output()
+	if err != nil {
+		return commits, err
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		hash, subject, ok := strings.Cut(line, " ")
+		subject = strings.TrimSpace(subject)
+		if !ok || subject == "" {
+			continue
+		}
+		commits.lines = append(commits.lines, hash+" "+subject)
+		if seen[strings.ToLower(subject)] {
+			continue
+		}
+		seen[strings.ToLower(subject)] = true
+		commits.subjects = append(commits.subjects, subject)
+	}
+	return commits, nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// ContactTypes are the kinds of contact with a client that can be logged
+var ContactTypes = []string{models.ContactTypeCall, models.ContactTypeEmail, models.ContactTypeMeeting}
+
+// ValidateContactType returns an error if contactType is not one of ContactTypes
+func ValidateContactType(contactType string) error {
+	for _, known := range ContactTypes {
+		if contactType == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported contact type %q, expected one of: %s", contactType, strings.Join(ContactTypes, ", "))
+}
+
+// LogContact records a call, email or meeting with a client at contactedAt. The duration is optional, and is what
+// the contact is billed for unless another is given when billing it.
+func (s *TimesheetService) LogContact(ctx context.Context, clientName, contactType, note string, contactedAt time.Time, duration time.Duration) (*models.ClientContact, error) {
+	if err := ValidateContactType(contactType); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(note) == "" {
+		return nil, fmt.Errorf("a contact needs a note")
+	}
+	if duration < 0 {
+		return nil, fmt.Errorf("duration can't be negative")
+	}
+	client, err := s.db.GetClientByName(ctx, clientName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("client '%s' does not exist", clientName)
+		}
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	contact := &models.ClientContact{
+		ClientID:    client.ID,
+		ClientName:  client.Name,
+		Type:        contactType,
+		Note:        note,
+		ContactedAt: contactedAt,
+	}
+	if duration > 0 {
+		minutes := int64(duration.Round(time.Minute) / time.Minute)
+		contact.DurationMinutes = &minutes
+	}
+	return s.db.CreateClientContact(ctx, contact)
+}
+
+// DisplayContacts lists the most recent contacts first, limited to one client when clientName isn't empty
+func (s *TimesheetService) DisplayContacts(ctx context.Context, clientName string, limit int32) error {
+	var clientID *string
+	if clientName != "" {
+		client, err := s.db.GetClientByName(ctx, clientName)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("client '%s' does not exist", clientName)
+			}
+			return fmt.Errorf("failed to get client: %w", err)
+		}
+		clientID = &client.ID
+	}
+
+	contacts, err := s.db.ListClientContacts(ctx, clientID, limit)
+	if err != nil {
+		return err
+	}
+	if len(contacts) == 0 {
+		fmt.Fprintln(s.out, "No contacts found. Log one with 'work contacts log'.")
+		return nil
+	}
+	for _, contact := range contacts {
+		fmt.Fprintf(s.out, "%s: %s\n", contact.ID, s.contactSummary(contact))
+	}
+	return nil
+}
+
+// BillContact records a contact as a finished session for its client, starting when the contact was made and
+// described by its note unless description is given. The duration defaults to the contact's own.
+func (s *TimesheetService) BillContact(ctx context.Context, contactID string, duration time.Duration, description *string, includesGst bool) (*models.WorkSession, error) {
+	contact, err := s.db.GetClientContactByID(ctx, contactID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("contact '%s' does not exist, see 'work contacts list'", contactID)
+		}
+		return nil, err
+	}
+	if contact.SessionID != nil {
+		return nil, fmt.Errorf("contact '%s' was already billed as session %s", contactID, *contact.SessionID)
+	}
+	if duration == 0 && contact.DurationMinutes != nil {
+		duration = time.Duration(*contact.DurationMinutes) * time.Minute
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("contact '%s' has no duration, give one with --duration", contactID)
+	}
+
+	client, err := s.db.GetClientByID(ctx, contact.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+	if description == nil {
+		label := strings.ToUpper(contact.Type[:1]) + contact.Type[1:]
+		generated := fmt.Sprintf("%s: %s", label, contact.Note)
+		description = &generated
+	}
+	endTime := contact.ContactedAt.Add(duration)
+	rate := s.sessionRate(ctx, client, nil, contact.ContactedAt)
+	session, err := s.db.BillClientContact(ctx, contact.ID, &models.WorkSession{
+		ClientID:    client.ID,
+		ClientName:  client.Name,
+		StartTime:   contact.ContactedAt,
+		EndTime:     &endTime,
+		Description: description,
+		HourlyRate:  &rate,
+		IncludesGst: includesGst,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.warnIfSessionRateBelowMinimum(client, session)
+	return session, nil
+}
+
+// DeleteContact removes a logged contact by ID. A session it was billed as is kept.
+func (s *TimesheetService) DeleteContact(ctx context.Context, contactID string) (*models.ClientContact, error) {
+	contact, err := s.db.GetClientContactByID(ctx, contactID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("contact '%s' does not exist, see 'work contacts list'", contactID)
+		}
+		return nil, err
+	}
+	if err := s.db.DeleteClientContact(ctx, contact.ID); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// contactSummary describes a contact on one line, e.g. "2025-07-01 14:30 call with acme, 30m: Discussed scope"
+func (s *TimesheetService) contactSummary(contact *models.ClientContact) string {
+	duration := ""
+	if contact.DurationMinutes != nil {
+		duration = ", " + s.FormatDurationFor(config.DurationContextList, time.Duration(*contact.DurationMinutes)*time.Minute)
+	}
+	billed := ""
+	if contact.SessionID != nil {
+		billed = " (billed)"
+	}
+	return fmt.Sprintf("%s %s with %s%s: %s%s", contact.ContactedAt.Format("2006-01-02 15:04"), contact.Type, contact.ClientName,
+		duration, contact.Note, billed)
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// invoiceBalance is what's still owed on an invoice once payments, credit notes and any early payment discount
+// are taken off. A negative balance means the client has paid more than they now owe and is due a refund.
+func invoiceBalance(invoice *models.Invoice) decimal.Decimal {
+	return invoiceOwed(invoice).Sub(invoice.AmountPaid)
+}
+
+// CreditInvoice issues a credit note against an invoice, reducing what the client owes by amount (including
+// GST), and writes a PDF of the credit note. The invoice can be given by ID or number.
+func (s *TimesheetService) CreditInvoice(ctx context.Context, invoiceRef string, amount decimal.Decimal, reason string, date time.Time) error {
+	invoice, err := s.getInvoiceByIDOrNumber(ctx, invoiceRef)
+	if err != nil {
+		return err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return fmt.Errorf("a reason is required for the credit note")
+	}
+	if !amount.IsPositive() {
+		return fmt.Errorf("amount must be greater than 0")
+	}
+
+	if err := checkInvoiceIssued(invoice, "credited"); err != nil {
+		return err
+	}
+
+	m := s.clientMoneyByName(invoice.ClientName)
+	creditable := invoiceOwed(invoice)
+	if !creditable.IsPositive() {
+		return fmt.Errorf("invoice %s has already been fully credited", invoice.InvoiceNumber)
+	}
+	if amount.GreaterThan(creditable) {
+		return fmt.Errorf("credit amount (%s) exceeds what's left to credit on %s (%s)", m.Format(amount), invoice.InvoiceNumber, m.Format(creditable))
+	}
+
+	if date.IsZero() {
+		now := time.Now()
+		date = time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location())
+	}
+
+	// The credit carries the same share of GST as the invoice it reverses
+	gstAmount := decimal.Zero
+	if invoice.GstAmount.IsPositive() && invoice.TotalAmount.IsPositive() {
+		gstAmount = amount.Mul(invoice.GstAmount).Div(invoice.TotalAmount).Round(2)
+	}
+
+	existing, err := s.db.GetCreditNotesByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+	number := creditNoteNumber(invoice.InvoiceNumber, len(existing)+1)
+
+	client, err := s.db.GetClientByID(ctx, invoice.ClientID)
+	if err != nil {
+		return fmt.Errorf("failed to get client for invoice: %w", err)
+	}
+
+	creditNote, err := s.db.CreateCreditNote(ctx, invoice.ID, number, amount, gstAmount, reason, date)
+	if err != nil {
+		return err
+	}
+	if err := s.syncInvoicePaidStatus(ctx, invoice.ID); err != nil {
+		return err
+	}
+
+	fileName := s.sanitizeFileName(fmt.Sprintf("credit_note_%s.pdf", number))
+	fileName, err = s.generateCreditNotePDF(fileName, creditNote, invoice, client)
+	if err != nil {
+		return fmt.Errorf("credit note %s was recorded but its PDF couldn't be written: %w", number, err)
+	}
+
+	fmt.Fprintf(s.out, "Issued credit note %s against %s for %s: %s\n", number, invoice.InvoiceNumber, m.Format(amount), fileName)
+
+	balance := invoiceBalance(invoice).Sub(amount)
+	switch {
+	case balance.IsNegative():
+		fmt.Fprintf(s.out, "%s has now overpaid %s by %s, refund them the difference\n", invoice.ClientName, invoice.InvoiceNumber, m.Format(balance.Neg()))
+	case balance.IsZero():
+		fmt.Fprintf(s.out, "Nothing further is owed on %s\n", invoice.InvoiceNumber)
+	default:
+		fmt.Fprintf(s.out, "%s still owing on %s\n", m.Format(balance), invoice.InvoiceNumber)
+	}
+	return nil
+}
+
+// getInvoiceByIDOrNumber looks up an invoice by its ID, falling back to its invoice number
+func (s *TimesheetService) getInvoiceByIDOrNumber(ctx context.Context, ref string) (*models.Invoice, error) {
+	invoice, err := s.db.GetInvoiceByID(ctx, ref)
+	if err == nil {
+		return invoice, nil
+	}
+	invoice, numberErr := s.db.GetInvoiceByNumber(ctx, ref)
+	if numberErr == nil && invoice != nil {
+		return invoice, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(numberErr, sql.ErrNoRows) {
+		return nil, fmt.Errorf("invoice '%s' does not exist", ref)
+	}
+	return nil, fmt.Errorf("failed to get invoice: %w", err)
+}
+
+// creditNoteNumber numbers the nth credit note against an invoice after the invoice itself, e.g.
+// INV-acme-month-2025-03-01 becomes CN-acme-month-2025-03-01-1
+func creditNoteNumber(invoiceNumber string, n int) string {
+	return fmt.Sprintf("CN-%s-%d", strings.TrimPrefix(invoiceNumber, "INV-"), n)
+}
+
+// generateCreditNotePDF renders a credit note and returns the path it was written to
+func (s *TimesheetService) generateCreditNotePDF(fileName string, creditNote *models.CreditNote, invoice *models.Invoice, client *models.Client) (string, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetKeywords(invoicePDFKeyword(creditNote.CreditNoteNumber), false)
+	pdf.AddPage()
+
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	m := s.clientMoney(client)
+	formatMoney := func(amount decimal.Decimal) string {
+		return tr(m.Format(amount))
+	}
+
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, fmt.Sprintf("Credit Note - %s", s.formatClientName(client.Name)))
+	pdf.Ln(8)
+
+	if s.cfg.BillingCompanyName != "" {
+		pdf.SetFont("Arial", "", 11)
+		pdf.Cell(40, 6, s.cfg.BillingCompanyName)
+		pdf.Ln(6)
+	}
+	if s.cfg.BillingABN != "" {
+		pdf.SetFont("Arial", "", 10)
+		pdf.Cell(40, 6, fmt.Sprintf("ABN %s", s.cfg.BillingABN))
+		pdf.Ln(6)
+	}
+	pdf.Ln(6)
+
+	pdf.SetFont("Arial", "", 11)
+	pdf.Cell(40, 6, fmt.Sprintf("Credit note: %s", creditNote.CreditNoteNumber))
+	pdf.Ln(6)
+	pdf.Cell(40, 6, fmt.Sprintf("Date: %s", creditNote.IssuedDate.Format("2006-01-02")))
+	pdf.Ln(6)
+	pdf.Cell(40, 6, fmt.Sprintf("Original invoice: %s (%s to %s)", invoice.InvoiceNumber,
+		invoice.PeriodStartDate.Format("2006-01-02"), invoice.PeriodEndDate.Format("2006-01-02")))
+	pdf.Ln(12)
+
+	if client.CompanyName != nil || client.ContactName != nil {
+		pdf.SetFont("Arial", "B", 12)
+		pdf.Cell(40, 8, "Credit To:")
+		pdf.Ln(8)
+		pdf.SetFont("Arial", "", 11)
+		if client.ContactName != nil {
+			pdf.Cell(95, 6, *client.ContactName)
+			pdf.Ln(6)
+		}
+		if client.CompanyName != nil {
+			pdf.Cell(95, 6, *client.CompanyName)
+			pdf.Ln(6)
+		}
+		if address := s.formatClientAddress(client); address != "" {
+			pdf.Cell(95, 6, address)
+			pdf.Ln(6)
+		}
+		pdf.Ln(6)
+	}
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(40, 8, "Reason:")
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "", 11)
+	pdf.MultiCell(190, 6, tr(creditNote.Reason), "", "L", false)
+	pdf.Ln(6)
+
+	pdf.SetFont("Arial", "B", 11)
+	if s.cfg.GSTRegistered {
+		pdf.Cell(168, 8, "Credit (excluding GST):")
+		pdf.CellFormat(22, 8, formatMoney(creditNote.Amount.Sub(creditNote.GstAmount)), "", 1, "R", false, 0, "")
+		pdf.Cell(168, 8, "GST (10%):")
+		pdf.CellFormat(22, 8, formatMoney(creditNote.GstAmount), "", 1, "R", false, 0, "")
+	}
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(168, 10, "Total Credit:")
+	pdf.CellFormat(22, 10, formatMoney(creditNote.Amount), "", 1, "R", false, 0, "")
+
+	fileName = s.resolveInvoiceFileName(fileName, creditNote.CreditNoteNumber)
+	if err := s.writePDFAtomically(pdf, fileName); err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
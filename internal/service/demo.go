@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/database"
+	"github.com/jesses-code-adventures/work/internal/db"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// demoClient is a made up client for the demo dataset
+type demoClient struct {
+	name         string
+	rate         string
+	weight       int // how often the client is worked for, relative to the others
+	retainer     string
+	hours        float64
+	details      database.ClientUpdateDetails
+	descriptions []string
+}
+
+var demoClients = []demoClient{
+	{
+		name:   "northwind",
+		rate:   "150",
+		weight: 5,
+		details: database.ClientUpdateDetails{
+			CompanyName:  utils.ToPtr("Northwind Traders Pty Ltd"),
+			ContactName:  utils.ToPtr("Priya Raman"),
+			Email:        utils.ToPtr("accounts@northwind.example"),
+			Phone:        utils.ToPtr("02 9000 1234"),
+			AddressLine1: utils.ToPtr("Level 4, 100 George Street"),
+			City:         utils.ToPtr("Sydney"),
+			State:        utils.ToPtr("NSW"),
+			PostalCode:   utils.ToPtr("2000"),
+			Country:      utils.ToPtr("Australia"),
+			Abn:          utils.ToPtr("51 824 753 556"),
+			Source:       utils.ToPtr("referral"),
+		},
+		descriptions: []string{
+			"- Built the order export to CSV\n- Fixed rounding in the GST totals",
+			"- Migrated the product catalogue to the new schema",
+			"- Added pagination to the orders API\n- Reviewed the warehouse integration PR",
+			"- Investigated slow checkout queries and added indexes",
+			"- Set up CI for the storefront",
+		},
+	},
+	{
+		name:     "bluegum",
+		rate:     "135",
+		weight:   3,
+		retainer: "2000",
+		hours:    15,
+		details: database.ClientUpdateDetails{
+			CompanyName:  utils.ToPtr("Bluegum Health"),
+			ContactName:  utils.ToPtr("Tom Nguyen"),
+			Email:        utils.ToPtr("tom@bluegum.example"),
+			AddressLine1: utils.ToPtr("22 Smith Street"),
+			City:         utils.ToPtr("Fitzroy"),
+			State:        utils.ToPtr("VIC"),
+			PostalCode:   utils.ToPtr("3065"),
+			Country:      utils.ToPtr("Australia"),
+			Source:       utils.ToPtr("linkedin"),
+		},
+		descriptions: []string{
+			"- Patched dependencies and rotated API keys",
+			"- Added appointment reminders by SMS",
+			"- Fixed the booking calendar in Safari",
+			"- Monthly maintenance and uptime review",
+		},
+	},
+	{
+		name:   "harbourlight",
+		rate:   "165",
+		weight: 2,
+		details: database.ClientUpdateDetails{
+			CompanyName: utils.ToPtr("Harbourlight Studio"),
+			ContactName: utils.ToPtr("Ava Williams"),
+			Email:       utils.ToPtr("ava@harbourlight.example"),
+			City:        utils.ToPtr("Hobart"),
+			State:       utils.ToPtr("TAS"),
+			Country:     utils.ToPtr("Australia"),
+			Source:      utils.ToPtr("repeat"),
+		},
+		descriptions: []string{
+			"- Designed the gallery page layout",
+			"- Built the image upload pipeline with resizing",
+			"- Workshop on the new CMS with the team",
+		},
+	},
+}
+
+// SeedDemo fills an empty database with made up clients, months of sessions, expenses, invoices and payments
+// to explore the commands with. The same seed always makes the same data, relative to today.
+func (s *TimesheetService) SeedDemo(ctx context.Context, months int, seed int64) error {
+	if months < 1 {
+		return fmt.Errorf("months must be at least 1")
+	}
+	existing, err := s.db.ListClients(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list clients: %w", err)
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("demo data is for an empty database, %s already has %d client(s), try 'work --db ./demo.db demo seed'", s.cfg.DatabaseURL, len(existing))
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, -months, 0)
+
+	// Clients
+	clients := make([]*models.Client, len(demoClients))
+	totalWeight := 0
+	for i, demo := range demoClients {
+		var retainerAmount *decimal.Decimal
+		var retainerHours *float64
+		var retainerBasis *string
+		if demo.retainer != "" {
+			retainerAmount = utils.ToPtr(decimal.RequireFromString(demo.retainer))
+			retainerHours = utils.ToPtr(demo.hours)
+			retainerBasis = utils.ToPtr("month")
+		}
+		client, err := s.db.CreateClient(ctx, demo.name, decimal.RequireFromString(demo.rate), retainerAmount, retainerHours, retainerBasis, nil)
+		if err != nil {
+			return err
+		}
+		details := demo.details
+		details.HourlyRate = &client.HourlyRate
+		details.RetainerAmount = retainerAmount
+		details.RetainerHours = retainerHours
+		details.RetainerBasis = retainerBasis
+		if clients[i], err = s.db.UpdateClient(ctx, client.ID, &details); err != nil {
+			return err
+		}
+		totalWeight += demo.weight
+	}
+	pickClient := func() int {
+		n := rng.Intn(totalWeight)
+		for i, demo := range demoClients {
+			if n < demo.weight {
+				return i
+			}
+			n -= demo.weight
+		}
+		return 0
+	}
+
+	// Sessions on weekdays up to yesterday, one or two a day
+	sessionCount := 0
+	for day := start; day.Before(today); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday || rng.Intn(10) == 0 {
+			continue
+		}
+		at := day.Add(9*time.Hour + time.Duration(rng.Intn(7))*15*time.Minute)
+		for range 1 + rng.Intn(2) {
+			i := pickClient()
+			demo := demoClients[i]
+			length := time.Duration(6+rng.Intn(11)) * 15 * time.Minute
+			description := demo.descriptions[rng.Intn(len(demo.descriptions))]
+			if _, err := s.db.CreateWorkSessionWithTimes(ctx, clients[i].ID, at, at.Add(length), &description, clients[i].HourlyRate, false); err != nil {
+				return err
+			}
+			sessionCount++
+			at = at.Add(length + time.Hour)
+		}
+	}
+
+	// Expenses, a software subscription each month and the odd trip to a client
+	expenseCount := 0
+	for month := start; month.Before(today); month = month.AddDate(0, 1, 0) {
+		subscription := month.AddDate(0, 0, 2)
+		if subscription.Before(today) {
+			amount := decimal.RequireFromString("49.50")
+			if _, err := s.db.CreateExpense(ctx, amount, subscription, utils.ToPtr("SUB-"+subscription.Format("200601")), nil, nil,
+				utils.ToPtr("Hosting"), utils.ToPtr("software"), nil, false, utils.ToPtr(amount.Div(decimal.NewFromInt(11)).Round(2))); err != nil {
+				return err
+			}
+			expenseCount++
+		}
+		trip := month.AddDate(0, 0, 10+rng.Intn(10))
+		if trip.Before(today) {
+			i := pickClient()
+			amount := decimal.NewFromInt(int64(30 + rng.Intn(60)))
+			if _, err := s.db.CreateExpense(ctx, amount, trip, nil, &clients[i].ID, nil,
+				utils.ToPtr("Parking and tolls for on-site day"), utils.ToPtr("travel"), nil, true, nil); err != nil {
+				return err
+			}
+			expenseCount++
+		}
+	}
+
+	// Monthly invoices for every finished month. Older ones are paid, last month's are partly paid or owing.
+	invoiceCount, paymentCount := 0, 0
+	lastMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	for month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(lastMonth); month = month.AddDate(0, 1, 0) {
+		fromDate, toDate := s.CalculatePeriodRange("month", month)
+		for i, client := range clients {
+			sessions, err := s.db.GetSessionsForPeriodWithoutInvoiceByClient(ctx, fromDate, toDate, client.Name)
+			if err != nil {
+				return err
+			}
+			expenses, err := s.db.GetExpensesWithoutInvoiceByClientAndDateRange(ctx, client.ID, fromDate, toDate)
+			if err != nil {
+				return err
+			}
+			expenses = invoiceableExpenses(expenses)
+			subtotal, gst, total, _ := s.calculateInvoiceAmounts(client, client.InvoiceRounding, sessions, expenses, nil, s.retainerForPeriod(client, "month", fromDate, toDate))
+			if !subtotal.IsPositive() {
+				continue
+			}
+
+			invoiceNumber := s.sanitizeFileName(fmt.Sprintf("INV-%s-month-%s", client.Name, month.Format("2006-01-02")))
+			periodEnd := time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
+			invoice, err := s.db.CreateInvoice(ctx, client.ID, invoiceNumber, "month", fromDate, periodEnd, subtotal, gst, total, models.InvoiceGroupBySession, nil, nil, nil, nil, client.InvoiceRounding)
+			if err != nil {
+				return err
+			}
+			for _, session := range sessions {
+				if err := s.db.UpdateSessionInvoiceID(ctx, session.ID, invoice.ID); err != nil {
+					return err
+				}
+			}
+			for _, expense := range expenses {
+				if err := s.db.UpdateExpenseInvoiceID(ctx, expense.ID, &invoice.ID); err != nil {
+					return err
+				}
+			}
+			invoiceCount++
+
+			amount := total
+			if month.Equal(lastMonth) {
+				// Leave something outstanding to chase
+				if i%2 == 1 {
+					continue
+				}
+				amount = total.Div(decimal.NewFromInt(2)).Round(2)
+			}
+			paid := month.AddDate(0, 1, 7+rng.Intn(14))
+			if paid.After(today) {
+				paid = today
+			}
+			err = s.db.PayInvoice(ctx, db.PayInvoiceParams{
+				ID:          models.NewUUID(),
+				InvoiceID:   invoice.ID,
+				Amount:      amount,
+				PaymentDate: time.Date(paid.Year(), paid.Month(), paid.Day(), 12, 0, 0, 0, time.Local),
+			})
+			if err != nil {
+				return err
+			}
+			paymentCount++
+		}
+	}
+
+	if _, err := s.db.CreateSessionTemplate(ctx, "standup", clients[0].ID, utils.ToPtr("Daily standup"), utils.ToPtr(int64(15))); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.out, "Seeded %s with %d clients, %d sessions, %d expenses, %d invoices and %d payments from %s\n",
+		s.cfg.DatabaseURL, len(clients), sessionCount, expenseCount, invoiceCount, paymentCount, start.Format("2006-01-02"))
+	fmt.Fprintln(s.out, "Try 'work clients list', 'work sessions list', 'work invoices list' or 'work report'")
+	return nil
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -15,10 +18,25 @@ import (
 	"github.com/jesses-code-adventures/work/internal/utils"
 )
 
-// GenerateDescriptions processes clients to generate session descriptions using git analysis
-func (s *TimesheetService) GenerateDescriptions(ctx context.Context, clientName, sessionID string, update bool) error {
+// GenerateDescriptions processes clients to generate session descriptions using git analysis, summarised by
+// opencode or listed from commit subjects depending on mode
+func (s *TimesheetService) GenerateDescriptions(ctx context.Context, clientName, sessionID, mode string, update bool) error {
+	if err := ValidateDescriptionMode(mode); err != nil {
+		return err
+	}
+
+	progress := newDescriptionProgress(s.term, s.logLevel.Level() <= slog.LevelInfo)
+
 	if sessionID != "" {
-		return s.processSession(ctx, sessionID, update)
+		session, client, err := s.getSessionAndClient(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+		progress.add(client.Name, 1)
+		progress.start()
+		err = s.processSessionWithClient(ctx, session, client, mode, update, progress)
+		s.reportDescriptionProgress(progress)
+		return err
 	}
 
 	clients, err := s.getTargetClients(ctx, clientName)
@@ -27,40 +45,115 @@ func (s *TimesheetService) GenerateDescriptions(ctx context.Context, clientName,
 	}
 
 	if len(clients) == 0 {
-		fmt.Println("No clients with directories found.")
+		fmt.Fprintln(s.out, "No clients with directories found.")
 		return nil
 	}
 
 	var wg sync.WaitGroup
+	progress.start()
 	for _, client := range clients {
 		sessions, err := s.db.GetSessionsWithoutDescription(ctx, &client.Name, nil)
 		if err != nil {
-			fmt.Printf("Error getting sessions for client %s: %v\n", client.Name, err)
+			s.logger.Error("failed to get sessions", "client", client.Name, "error", err)
 			continue
 		}
 
 		if len(sessions) == 0 {
-			fmt.Printf("No sessions missing descriptions for client: %s\n", client.Name)
+			s.logger.Info("no sessions missing descriptions", "client", client.Name)
 			continue
 		}
 
+		progress.add(client.Name, len(sessions))
 		for _, session := range sessions {
 			wg.Add(1)
 			go func(sess *models.WorkSession) {
 				defer wg.Done()
-				s.processSessionWithClient(ctx, sess, client, update)
+				s.processSessionWithClient(ctx, sess, client, mode, update, progress)
 			}(session)
 		}
 	}
 
 	wg.Wait()
+	s.reportDescriptionProgress(progress)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("description generation stopped: %w", err)
+	}
 	return nil
 }
 
+// DescriptionBackfill is what a pass of BackfillDescriptions did
+type DescriptionBackfill struct {
+	Described int
+	Failed    int
+	Waiting   int // sessions missing descriptions that ended too recently to describe yet
+}
+
+// BackfillDescriptions generates and saves descriptions for the finished sessions missing them that ended more
+// than olderThan ago, leaving the most recent for you to describe while they're fresh. Sessions are described
+// one at a time so a background run doesn't compete with your work, and invoiced sessions are left alone.
+// Sessions in failed aren't retried and those that fail are added to it, so a watch doesn't keep analysing
+// sessions without commits.
+func (s *TimesheetService) BackfillDescriptions(ctx context.Context, clientName, mode string, olderThan time.Duration, failed map[string]bool) (*DescriptionBackfill, error) {
+	if err := ValidateDescriptionMode(mode); err != nil {
+		return nil, err
+	}
+	clients, err := s.getTargetClients(ctx, clientName)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	backfill := &DescriptionBackfill{}
+	progress := newDescriptionProgress(s.term, false)
+	for _, client := range clients {
+		sessions, err := s.db.GetSessionsWithoutDescription(ctx, &client.Name, nil)
+		if err != nil {
+			return backfill, fmt.Errorf("failed to get sessions for %s: %w", client.Name, err)
+		}
+		for _, session := range sessions {
+			if session.EndTime == nil || session.InvoiceID != nil || failed[session.ID] {
+				continue
+			}
+			if session.EndTime.After(cutoff) {
+				backfill.Waiting++
+				continue
+			}
+			if ctx.Err() != nil {
+				return backfill, nil
+			}
+
+			progress.add(client.Name, 1)
+			if err := s.processSessionWithClient(ctx, session, client, mode, true, progress); err != nil {
+				if failed != nil {
+					failed[session.ID] = true
+				}
+				backfill.Failed++
+				continue
+			}
+			backfill.Described++
+		}
+	}
+	return backfill, nil
+}
+
+// reportDescriptionProgress stops the progress display and prints how many sessions were described
+func (s *TimesheetService) reportDescriptionProgress(progress *descriptionProgress) {
+	done, failed := progress.finish()
+	if done == 0 {
+		return
+	}
+	if failed > 0 {
+		fmt.Fprintf(s.out, "Processed %d session(s), %d failed\n", done, failed)
+		return
+	}
+	fmt.Fprintf(s.out, "Processed %d session(s)\n", done)
+}
+
 // DescriptionResult contains both the final summary and full work details
 type DescriptionResult struct {
 	FinalSummary    string
 	FullWorkSummary string
+	Repos           []*models.SessionRepo // the repositories with commits in the session
 }
 
 var (
@@ -70,52 +163,78 @@ var (
 
 // RepositoryResult holds the result of analyzing a single git repository
 type RepositoryResult struct {
-	RepoPath string
-	Output   string
-	Error    error
+	RepoPath    string
+	Output      string
+	CommitCount int64 // commits made in the analyzed period
+	Error       error
 }
 
-func (s *TimesheetService) processSession(ctx context.Context, sessionID string, update bool) error {
+func (s *TimesheetService) getSessionAndClient(ctx context.Context, sessionID string) (*models.WorkSession, *models.Client, error) {
 	session, err := s.db.GetSessionByID(ctx, sessionID)
 	if err != nil {
-		return fmt.Errorf("failed to get session '%s': %w", sessionID, err)
+		return nil, nil, fmt.Errorf("failed to get session '%s': %w", sessionID, err)
 	}
 	if session == nil {
-		return fmt.Errorf("session '%s' does not exist", sessionID)
+		return nil, nil, fmt.Errorf("session '%s' does not exist", sessionID)
 	}
 
 	client, err := s.db.GetClientByID(ctx, session.ClientID)
 	if err != nil {
-		return fmt.Errorf("failed to get client: %w", err)
+		return nil, nil, fmt.Errorf("failed to get client: %w", err)
 	}
 
-	return s.processSessionWithClient(ctx, session, client, update)
+	return session, client, nil
 }
 
-func (s *TimesheetService) processSessionWithClient(ctx context.Context, session *models.WorkSession, client *models.Client, update bool) error {
+func (s *TimesheetService) processSessionWithClient(ctx context.Context, session *models.WorkSession, client *models.Client, mode string, update bool, progress *descriptionProgress) (err error) {
+	logger := s.logger.With("client", client.Name, "session", session.ID)
+	started := time.Now()
+	var result *DescriptionResult
+	defer func() {
+		done, total := progress.complete(client.Name, err)
+		if result != nil {
+			logger.Info("generated description", "description", result.FinalSummary, "updated", update,
+				"took", time.Since(started).Round(time.Second), "progress", fmt.Sprintf("%d/%d", done, total))
+		}
+	}()
+
 	if session.EndTime == nil {
-		fmt.Printf("  Skipping active session %s (not ended)\n", session.ID)
+		logger.Info("skipping active session")
+		return nil
+	}
+	if update && session.InvoiceID != nil {
+		logger.Info("skipping invoiced session")
 		return nil
 	}
 
-	fmt.Printf("  Processing session %s (%s to %s)\n",
-		session.ID,
-		session.StartTime.Format("2006-01-02 15:04"),
-		session.EndTime.Format("2006-01-02 15:04"))
+	logger.Debug("analyzing session",
+		"from", session.StartTime.Format("2006-01-02 15:04"),
+		"to", session.EndTime.Format("2006-01-02 15:04"))
 
-	result, err := s.analyzeSession(ctx, client, session)
+	var analysis *DescriptionResult
+	if mode == DescriptionModeCommits {
+		analysis, err = s.describeFromCommits(ctx, client, session)
+	} else {
+		analysis, err = s.analyzeSession(ctx, client, session)
+	}
 	if err != nil {
-		fmt.Printf("    Error analyzing session: %v\n", err)
+		logger.Error("failed to analyze session", "error", err)
 		return err
 	}
 
 	if update {
-		_, err = s.db.UpdateSessionDescription(ctx, session.ID, result.FinalSummary, &result.FullWorkSummary)
+		_, err = s.db.UpdateSessionDescription(ctx, session.ID, analysis.FinalSummary, &analysis.FullWorkSummary, false)
 		if err != nil {
+			logger.Error("failed to update session description", "error", err)
 			return fmt.Errorf("failed to update session description: %w", err)
 		}
+		if err = s.db.ReplaceSessionRepos(ctx, session.ID, analysis.Repos, false); err != nil {
+			logger.Error("failed to record session repos", "error", err)
+			return fmt.Errorf("failed to record session repos: %w", err)
+		}
 	}
 
+	result = analysis
 	return nil
 }
 
@@ -157,7 +276,7 @@ func (s *TimesheetService) analyzeSession(ctx context.Context, client *models.Cl
 	defer os.RemoveAll(tempDir)
 
 	// Run the analysis for this specific client and time period
-	result, err := s.performAnalysis(session.StartTime, *session.EndTime, client, tempDir)
+	result, err := s.performAnalysis(ctx, session.StartTime, *session.EndTime, client, tempDir)
 	if err != nil {
 		return nil, err
 	}
@@ -166,19 +285,19 @@ func (s *TimesheetService) analyzeSession(ctx context.Context, client *models.Cl
 }
 
 // performAnalysis runs the git analysis and returns structured results for a single client
-func (s *TimesheetService) performAnalysis(fromDate, toDate time.Time, client *models.Client, tempDir string) (*DescriptionResult, error) {
+func (s *TimesheetService) performAnalysis(ctx context.Context, fromDate, toDate time.Time, client *models.Client, tempDir string) (*DescriptionResult, error) {
 	if client == nil || utils.FromPtr(client.Dir) == "" {
 		return nil, ErrConfiguredClientRequired
 	}
 
 	// Process the client directory
-	err := s.processDirectory(client.Name, *client.Dir, fromDate, toDate, tempDir)
+	repoResults, err := s.processDirectory(ctx, client, fromDate, toDate, tempDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process directory: %w", err)
 	}
 
 	// Generate brief description for the session
-	briefDescription, err := s.generateBriefDescription(tempDir)
+	briefDescription, err := s.generateBriefDescription(ctx, client, tempDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate brief description: %w", err)
 	}
@@ -189,35 +308,35 @@ func (s *TimesheetService) performAnalysis(fromDate, toDate time.Time, client *m
 		return nil, fmt.Errorf("failed to generate detailed summary: %w", err)
 	}
 
+	var repos []*models.SessionRepo
+	for _, result := range repoResults {
+		if result.CommitCount > 0 {
+			repos = append(repos, &models.SessionRepo{RepoPath: result.RepoPath, CommitCount: result.CommitCount})
+		}
+	}
+
 	return &DescriptionResult{
 		FinalSummary:    briefDescription,
 		FullWorkSummary: fullWorkSummary,
+		Repos:           repos,
 	}, nil
 }
 
-// processDirectory finds git repositories in the client directory and analyzes each one
-func (s *TimesheetService) processDirectory(clientName, dir string, fromDate, toDate time.Time, tempDir string) error {
-	// Trim whitespace from the directory path
-	dir = strings.TrimSpace(dir)
-	if strings.HasPrefix(dir, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("error getting home directory: %v", err)
-		}
-		dir = filepath.Join(homeDir, dir[2:])
-	}
-
-	// Check if directory exists
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return fmt.Errorf("directory does not exist: %s", dir)
+// processDirectory finds git repositories in the client's directory and analyzes each one with the client's
+// prompt and depth, returning the result for each repository
+func (s *TimesheetService) processDirectory(ctx context.Context, client *models.Client, fromDate, toDate time.Time, tempDir string) ([]RepositoryResult, error) {
+	dir, err := expandClientDir(*client.Dir)
+	if err != nil {
+		return nil, err
 	}
 
 	// Find all git repositories in subdirectories
 	gitRepos := s.findGitRepositories(dir)
 
 	if len(gitRepos) == 0 {
-		return fmt.Errorf("no git repositories found in %s", dir)
+		return nil, fmt.Errorf("no git repositories found in %s", dir)
 	}
+	s.logger.Debug("found git repositories", "client", client.Name, "dir", dir, "count", len(gitRepos))
 
 	// Process each git repository in parallel
 	var wg sync.WaitGroup
@@ -227,7 +346,7 @@ func (s *TimesheetService) processDirectory(clientName, dir string, fromDate, to
 		wg.Add(1)
 		go func(repoPath string) {
 			defer wg.Done()
-			result := s.analyzeGitRepository(repoPath, fromDate, toDate)
+			result := s.analyzeGitRepository(ctx, client, repoPath, fromDate, toDate)
 			results <- result
 		}(repoDir)
 	}
@@ -238,22 +357,64 @@ func (s *TimesheetService) processDirectory(clientName, dir string, fromDate, to
 		close(results)
 	}()
 
-	// Collect all results
+	// Collect all results. A repository that fails to analyze is left out of the summary rather than failing the
+	// session, unless they all fail.
 	var allResults []RepositoryResult
+	failed := 0
+	var firstErr error
 	for result := range results {
 		allResults = append(allResults, result)
+		if result.Error != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = result.Error
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if failed == len(allResults) {
+		return nil, fmt.Errorf("all %d repositories failed to analyze: %w", failed, firstErr)
 	}
 
 	// Combine results into a single output
-	combinedOutput := s.combineRepositoryResults(clientName, allResults)
+	combinedOutput := s.combineRepositoryResults(client.Name, allResults)
 
 	// Write combined output to file
-	outputFile := filepath.Join(tempDir, s.sanitizeClientName(clientName, fromDate, toDate)+".txt")
-	err := os.WriteFile(outputFile, []byte(combinedOutput), 0644)
+	outputFile := filepath.Join(tempDir, s.sanitizeClientName(client.Name, fromDate, toDate)+".txt")
+	err = os.WriteFile(outputFile, []byte(combinedOutput), 0644)
 	if err != nil {
-		return fmt.Errorf("error writing output file for %s: %v", clientName, err)
+		return nil, fmt.Errorf("error writing output file for %s: %v", client.Name, err)
 	}
-	return nil
+	return allResults, nil
+}
+
+// expandClientDir resolves a client's configured directory, expanding a leading ~, and checks it exists
+func expandClientDir(dir string) (string, error) {
+	dir, err := expandHomeDir(strings.TrimSpace(dir))
+	if err != nil {
+		return "", err
+	}
+
+	// Check if directory exists
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return "", fmt.Errorf("directory does not exist: %s", dir)
+	}
+	return dir, nil
+}
+
+// expandHomeDir expands a leading ~ to the user's home directory. Either separator can follow it, so
+// directories written as ~\code on Windows work as well as ~/code.
+func expandHomeDir(dir string) (string, error) {
+	if dir != "~" && !strings.HasPrefix(dir, "~/") && !strings.HasPrefix(dir, `~\`) {
+		return dir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting home directory: %w", err)
+	}
+	return filepath.Join(homeDir, filepath.FromSlash(dir[1:])), nil
 }
 
 // sanitizeClientName creates a safe filename from client name
@@ -267,134 +428,122 @@ func (s *TimesheetService) sanitizeClientName(clientName string, fromDate, toDat
 	return result
 }
 
-// findGitRepositories searches for .git directories in the given directory and its subdirectories
-// Uses find command with time-based filtering to only check recently modified repositories
+// recentRepositoryAge is how recently a repository's .git directory must have changed for it to be analyzed
+// without checking its log
+const recentRepositoryAge = 30 * 24 * time.Hour
+
+// findGitRepositories searches for git repositories in the given directory and up to two levels below it,
+// keeping those whose .git directory changed in the last 30 days. This is much faster than reading each
+// repository's log, which is only done when none have changed recently.
 func (s *TimesheetService) findGitRepositories(root string) []string {
 	var gitRepos []string
-
-	// Use find command to locate .git directories modified in the last 30 days
-	// This is much faster than walking through all directories
-	cmd := exec.Command("find", root, "-type", "d", "-name", ".git", "-mtime", "-30", "-maxdepth", "3")
-	output, err := cmd.Output()
-	if err != nil {
-		fmt.Printf("  Warning: find command failed, falling back to directory walk: %v\n", err)
-		return s.findGitRepositoriesWalk(root)
-	}
-
-	// Parse find output to get repository directories
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line != "" {
-			// Get the parent directory (the actual repository directory)
-			repoDir := filepath.Dir(line)
+	cutoff := time.Now().Add(-recentRepositoryAge)
+	for _, repoDir := range gitRepositoriesUnder(root, 3) {
+		info, err := os.Stat(filepath.Join(repoDir, ".git"))
+		if err == nil && info.ModTime().After(cutoff) {
 			gitRepos = append(gitRepos, repoDir)
 		}
 	}
 
 	// If no recently modified repos found, also check for repos with recent commits
 	if len(gitRepos) == 0 {
-		fmt.Printf("  No recently modified .git directories found, checking for repos with recent commits...\n")
+		s.logger.Debug("no recently modified .git directories found, checking for repos with recent commits", "dir", root)
 		gitRepos = s.findGitRepositoriesWithRecentCommits(root)
 	}
 
 	return gitRepos
 }
 
-// findGitRepositoriesWalk is the original implementation as fallback
+// findGitRepositoriesWalk finds every git repository in the given directory and its immediate subdirectories
 func (s *TimesheetService) findGitRepositoriesWalk(root string) []string {
-	var gitRepos []string
-	maxDepth := 2
-
-	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		rel, _ := filepath.Rel(root, path)
-		depth := len(strings.Split(rel, string(filepath.Separator)))
-
-		if depth > maxDepth {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
+	return gitRepositoriesUnder(root, 2)
+}
 
-		// Check if this is a .git directory
-		if info.IsDir() && info.Name() == ".git" {
-			// Add the parent directory (the actual repository directory)
-			repoDir := filepath.Dir(path)
+// findGitRepositoriesWithRecentCommits finds git repos that have commits in the last month
+func (s *TimesheetService) findGitRepositoriesWithRecentCommits(root string) []string {
+	var gitRepos []string
+	for _, repoDir := range gitRepositoriesUnder(root, 2) {
+		cmd := exec.Command("git", "-C", repoDir, "log", "--since=1 month ago", "--oneline", "-n", "1")
+		output, err := cmd.Output()
+		if err == nil && len(strings.TrimSpace(string(output))) > 0 {
 			gitRepos = append(gitRepos, repoDir)
-			return filepath.SkipDir // Don't traverse into .git directory
 		}
-
-		return nil
-	})
-
+	}
 	return gitRepos
 }
 
-// findGitRepositoriesWithRecentCommits finds git repos that have commits in the last month
-func (s *TimesheetService) findGitRepositoriesWithRecentCommits(root string) []string {
+// gitRepositoriesUnder lists the directories under root with a .git directory, looking at most maxDepth levels
+// down including the .git directory itself, so a maxDepth of 2 finds root and its immediate subdirectories
+func gitRepositoriesUnder(root string, maxDepth int) []string {
 	var gitRepos []string
-	maxDepth := 2
-
-	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
+	filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
 			return nil
 		}
 
 		rel, _ := filepath.Rel(root, path)
-		depth := len(strings.Split(rel, string(filepath.Separator)))
-
-		if depth > maxDepth {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
+		if depth := len(strings.Split(rel, string(filepath.Separator))); depth > maxDepth {
+			return filepath.SkipDir
 		}
 
-		// Check if this is a .git directory
-		if info.IsDir() && info.Name() == ".git" {
-			repoDir := filepath.Dir(path)
-
-			// Check if this repo has commits in the last month
-			cmd := exec.Command("git", "-C", repoDir, "log", "--since=1 month ago", "--oneline", "-n", "1")
-			output, err := cmd.Output()
-			if err == nil && len(strings.TrimSpace(string(output))) > 0 {
-				gitRepos = append(gitRepos, repoDir)
-			}
-
+		if entry.Name() == ".git" {
+			// Add the parent directory (the actual repository directory)
+			gitRepos = append(gitRepos, filepath.Dir(path))
 			return filepath.SkipDir // Don't traverse into .git directory
 		}
-
 		return nil
 	})
-
 	return gitRepos
 }
 
 // analyzeGitRepository runs git analysis on a single repository
-func (s *TimesheetService) analyzeGitRepository(repoDir string, fromDate, toDate time.Time) RepositoryResult {
-	// Create prompt with actual dates
-	prompt := strings.ReplaceAll(s.cfg.GitAnalysisPrompt, "{from_date}", fromDate.Format("2006-01-02 15:04"))
-	prompt = strings.ReplaceAll(prompt, "{to_date}", toDate.Format("2006-01-02 15:04"))
-
-	// Create the shell command to cd into repository directory and run opencode
-	cmd := exec.Command("sh", "-c", fmt.Sprintf("cd %s && echo %s | opencode run",
-		s.shellescape(repoDir),
-		s.shellescape(prompt)))
+func (s *TimesheetService) analyzeGitRepository(ctx context.Context, client *models.Client, repoDir string, fromDate, toDate time.Time) RepositoryResult {
+	// The prompt has the history in it when the client's depth is limited
+	prompt, hasCommits, err := s.gitAnalysisPrompt(ctx, client, repoDir, fromDate, toDate)
+
+	var output []byte
+	switch {
+	case err != nil:
+		s.logger.Warn("failed to analyze repository", "repo", repoDir, "error", err)
+	case !hasCommits:
+		// There's nothing left to describe once the history is cut down, so it isn't worth running the AI
+		output = []byte("NO COMMITS")
+	default:
+		started := time.Now()
+		output, err = s.runOpencode(ctx, repoDir, prompt, "analyze "+filepath.Base(repoDir))
+		if err != nil {
+			s.logger.Warn("failed to analyze repository", "repo", repoDir, "error", err)
+		} else {
+			s.logger.Debug("analyzed repository", "repo", repoDir, "took", time.Since(started).Round(time.Second))
+		}
+	}
 
-	// Execute the command and capture output
-	output, err := cmd.CombinedOutput()
+	// Only recorded against the session, so a repository whose commits can't be counted is just left out
+	commitCount, countErr := countCommits(ctx, repoDir, fromDate, toDate)
+	if countErr != nil {
+		s.logger.Warn("failed to count commits", "repo", repoDir, "error", countErr)
+	}
 
 	return RepositoryResult{
-		RepoPath: repoDir,
-		Output:   string(output),
-		Error:    err,
+		RepoPath:    repoDir,
+		Output:      string(output),
+		CommitCount: commitCount,
+		Error:       err,
 	}
 }
 
+// countCommits counts the commits made in a repository between from and to on any branch, leaving out merges
+// as sessionCommits does
+func countCommits(ctx context.Context, repo string, from, to time.Time) (int64, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", repo, "rev-list", "--count", "--all", "--no-merges",
+		"--since="+from.Format(time.RFC3339), "--until="+to.Format(time.RFC3339))
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+}
+
 // combineRepositoryResults combines results from multiple repositories into a single output
 func (s *TimesheetService) combineRepositoryResults(clientName string, results []RepositoryResult) string {
 	if len(results) == 0 {
@@ -407,8 +556,8 @@ func (s *TimesheetService) combineRepositoryResults(clientName string, results [
 	for _, result := range results {
 		repoName := filepath.Base(result.RepoPath)
 
+		// Already logged, and left out so the summary only describes work that was analyzed
 		if result.Error != nil {
-			combinedOutput.WriteString(fmt.Sprintf("ERROR analyzing %s: %v\n", repoName, result.Error))
 			continue
 		}
 
@@ -491,20 +640,87 @@ func (s *TimesheetService) cleanRepositoryOutput(output string) string {
 	return strings.Join(cleanLines, "\n")
 }
 
-// shellescape escapes a string for safe use in shell commands
-func (s *TimesheetService) shellescape(str string) string {
-	return "'" + strings.ReplaceAll(str, "'", "'\"'\"'") + "'"
+// opencodeProvider is the analysis pool provider name used for ANALYSIS_RATE_LIMITS
+const opencodeProvider = "opencode"
+
+// opencodeRetryDelay is how long the first retry of a failed opencode run waits, doubling each time
+const opencodeRetryDelay = 5 * time.Second
+
+// errOpencodeTimeout is returned when an opencode run takes longer than ANALYSIS_TIMEOUT
+var errOpencodeTimeout = errors.New("opencode timed out")
+
+// runOpencode runs opencode with the prompt in dir once a slot in the analysis pool is free. Runs that fail or
+// time out are retried up to ANALYSIS_RETRIES times with a growing delay, giving up the slot while waiting, and
+// cancelling ctx, e.g. with Ctrl+C, stops straight away.
+func (s *TimesheetService) runOpencode(ctx context.Context, dir, prompt, label string) ([]byte, error) {
+	delay := opencodeRetryDelay
+	for attempt := 0; ; attempt++ {
+		output, err := s.runOpencodeOnce(ctx, dir, prompt, label)
+		if err == nil || ctx.Err() != nil || attempt >= s.cfg.AnalysisRetries || !isTransientOpencodeError(err) {
+			return output, err
+		}
+		s.logger.Warn("opencode failed, retrying", "task", label, "attempt", attempt+1, "wait", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return output, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// runOpencodeOnce runs opencode once, stopping it after ANALYSIS_TIMEOUT
+func (s *TimesheetService) runOpencodeOnce(ctx context.Context, dir, prompt, label string) ([]byte, error) {
+	release, err := s.analysis.acquire(ctx, opencodeProvider, label)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	runCtx := ctx
+	if s.cfg.AnalysisTimeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, s.cfg.AnalysisTimeout)
+		defer cancel()
+	}
+
+	s.logger.Debug("running opencode", "task", label, "dir", dir)
+	output, err := opencodeCommand(runCtx, dir, prompt).CombinedOutput()
+	if err != nil && ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		return output, fmt.Errorf("%w after %s", errOpencodeTimeout, s.cfg.AnalysisTimeout)
+	}
+	return output, err
+}
+
+// isTransientOpencodeError reports whether an opencode run might succeed if tried again: it timed out or exited
+// with an error, as it does when the model provider is unavailable or rate limiting. Failing to start, such as
+// when opencode isn't installed, won't go away on its own.
+func isTransientOpencodeError(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.Is(err, errOpencodeTimeout) || errors.As(err, &exitErr)
 }
 
-// generateBriefDescription creates a concise 1-2 sentence description suitable for a line item
-func (s *TimesheetService) generateBriefDescription(tempDir string) (string, error) {
-	briefPrompt := "Read all .txt files in this directory and provide ONLY a single, concise line item description (maximum 1-2 sentences) of the work done. Focus on business value, not technical details. Do not show your thinking or tool usage. Output only the final description. If no work was done, respond 'No development activity'."
+// opencodeCommand runs opencode in dir with the prompt on stdin, without a shell so it works the same everywhere.
+// When ctx is done opencode is killed, and anything it started that still holds its output is given a few
+// seconds before the output is closed, so a hung tool can't block the run.
+func opencodeCommand(ctx context.Context, dir, prompt string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "opencode", "run")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(prompt + "\n")
+	cmd.WaitDelay = 5 * time.Second
+	return cmd
+}
 
-	cmd := exec.Command("sh", "-c", fmt.Sprintf("cd %s && echo %s | opencode run",
-		s.shellescape(tempDir),
-		s.shellescape(briefPrompt)))
+// generateBriefDescription creates a concise 1-2 sentence description suitable for a line item. Clients with
+// their own analysis prompt get it in the style that asks for, rather than focused on business value.
+func (s *TimesheetService) generateBriefDescription(ctx context.Context, client *models.Client, tempDir string) (string, error) {
+	style := "Focus on business value, not technical details."
+	if client.AnalysisPrompt != nil && strings.TrimSpace(*client.AnalysisPrompt) != "" {
+		style = fmt.Sprintf("Write it in the style and tone these instructions for the client ask for, ignoring anything in them about running git: %q.", strings.TrimSpace(*client.AnalysisPrompt))
+	}
+	briefPrompt := "Read all .txt files in this directory and provide ONLY a single, concise line item description (maximum 1-2 sentences) of the work done. " + style + " Do not show your thinking or tool usage. Output only the final description. If no work was done, respond 'No development activity'."
 
-	output, err := cmd.CombinedOutput()
+	output, err := s.runOpencode(ctx, tempDir, briefPrompt, "summarize")
 	if err != nil {
 		return "", fmt.Errorf("failed to generate brief description: %v\nOutput: %s", err, string(output))
 	}
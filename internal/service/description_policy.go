@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// ErrDescriptionRequired is returned when stopping a session without a description while DESCRIPTION_POLICY is block
+var ErrDescriptionRequired = errors.New("the session needs a description before it's stopped (DESCRIPTION_POLICY=block), give one with --description, add notes with 'work note <text>' or generate one from git with --ai")
+
+// sessionDescribed reports whether a session has a description or notes to show on its invoice
+func sessionDescribed(session *models.WorkSession) bool {
+	return strings.TrimSpace(utils.FromPtr(session.Description)) != "" || strings.TrimSpace(utils.FromPtr(session.OutsideGit)) != ""
+}
+
+// FilterSessionsMissingDescription returns the sessions with neither a description nor notes
+func (s *TimesheetService) FilterSessionsMissingDescription(sessions []*models.WorkSession) []*models.WorkSession {
+	var filtered []*models.WorkSession
+	for _, session := range sessions {
+		if !sessionDescribed(session) {
+			filtered = append(filtered, session)
+		}
+	}
+	return filtered
+}
+
+// NeedsStopDescription reports whether a session about to be stopped should be described first under
+// DESCRIPTION_POLICY
+func (s *TimesheetService) NeedsStopDescription(session *models.WorkSession) bool {
+	return s.cfg.DescriptionPolicy != config.DescriptionPolicyOff && !sessionDescribed(session)
+}
+
+// CheckStopDescription checks a session can be stopped with the description given, or with its description
+// generated from git when ai is set. Under DESCRIPTION_POLICY=block a session has to be described one of those
+// ways, and generating a description needs the client's directory.
+func (s *TimesheetService) CheckStopDescription(ctx context.Context, session *models.WorkSession, description string, ai bool) error {
+	if ai {
+		client, err := s.db.GetClientByID(ctx, session.ClientID)
+		if err != nil {
+			return fmt.Errorf("failed to get client for session: %w", err)
+		}
+		if utils.FromPtr(client.Dir) == "" {
+			return fmt.Errorf("%w, so a description can't be generated from git", ErrConfiguredClientRequired)
+		}
+		return nil
+	}
+	if s.cfg.DescriptionPolicy == config.DescriptionPolicyBlock && strings.TrimSpace(description) == "" && !sessionDescribed(session) {
+		return ErrDescriptionRequired
+	}
+	return nil
+}
+
+// DescribeStoppedSession saves the description given for a session that's just been stopped, or generates one
+// from the git history of its time when ai is set
+func (s *TimesheetService) DescribeStoppedSession(ctx context.Context, session *models.WorkSession, description string, ai bool) (*models.WorkSession, error) {
+	clientName := session.ClientName
+	if ai {
+		if err := s.GenerateDescriptions(ctx, "", session.ID, DescriptionModeAI, true); err != nil {
+			return session, fmt.Errorf("the session was stopped, but its description couldn't be generated: %w", err)
+		}
+		updated, err := s.db.GetSessionByID(ctx, session.ID)
+		if err != nil {
+			return session, err
+		}
+		updated.ClientName = clientName
+		return updated, nil
+	}
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return session, nil
+	}
+	updated, err := s.UpdateSessionDescription(ctx, session.ID, description, nil)
+	if err != nil {
+		return session, fmt.Errorf("the session was stopped, but its description couldn't be saved: %w", err)
+	}
+	updated.ClientName = clientName
+	return updated, nil
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// requiredColumns lists columns added by the most recent migrations for each table, so a missing
+// column means the database is behind the migrations directory
+var requiredColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"clients", []string{"dir", "abn", "retainer_amount", "retainer_hours", "retainer_basis", "currency", "locale", "source", "work_log", "retainer_start_date", "early_discount_percent", "early_discount_days", "invoice_notes", "payment_terms", "po_number", "project_code", "invoice_rounding", "tax_treatment", "logo_path", "brand_colour", "analysis_max_commits", "analysis_ignore", "analysis_detail", "analysis_prompt"}},
+	{"sessions", []string{"full_work_summary", "outside_git", "invoice_id", "includes_gst", "hostname", "break_seconds", "user_id", "os", "git_branch", "deleted_at"}},
+	{"invoices", []string{"invoice_number", "period_type", "subtotal_amount", "gst_amount", "total_amount", "group_by", "needs_regeneration", "notes", "payment_terms", "po_number", "project_code", "status", "status_reason", "status_changed_at", "amount_written_off", "rounding"}},
+	{"payments", []string{"invoice_id", "amount", "payment_date", "discount_amount"}},
+	{"expenses", []string{"reference", "client_id", "invoice_id", "description", "category", "markup_percent", "billable", "draft", "receipt_path", "distance_km", "rate_per_km", "gst_amount", "deleted_at"}},
+	{"session_templates", []string{"name", "client_id", "description", "duration_minutes"}},
+	{"credit_notes", []string{"invoice_id", "credit_note_number", "amount", "gst_amount", "reason", "issued_date"}},
+	{"session_breaks", []string{"session_id", "start_time", "end_time", "source"}},
+	{"session_repos", []string{"session_id", "repo_path", "commit_count"}},
+	{"users", []string{"name", "cost_rate"}},
+	{"leave", []string{"leave_type", "start_date", "end_date", "note"}},
+	{"client_rates", []string{"client_id", "previous_rate", "hourly_rate", "effective_date"}},
+	{"client_contacts", []string{"client_id", "contact_type", "note", "contacted_at", "duration_minutes", "session_id"}},
+	{"expense_rules", []string{"pattern", "client_id", "category", "billable", "skip"}},
+	{"milestones", []string{"client_id", "name", "amount", "due_date", "status", "completed_date", "invoice_id"}},
+}
+
+// doctorTools are the external commands the CLI shells out to, and what they're needed for
+var doctorTools = []struct {
+	name    string
+	purpose string
+}{
+	{"git", "reading commit history for descriptions"},
+	{"opencode", "generating session descriptions"},
+}
+
+// longRunningSessionThreshold is how long a session can be active before doctor suggests it was left running
+const longRunningSessionThreshold = 12 * time.Hour
+
+type doctorReport struct {
+	out      io.Writer
+	failures int
+	warnings int
+}
+
+func (r *doctorReport) ok(msg string) {
+	fmt.Fprintf(r.out, "✓ %s\n", msg)
+}
+
+func (r *doctorReport) warn(msg, fix string) {
+	r.warnings++
+	fmt.Fprintf(r.out, "! %s\n", msg)
+	if fix != "" {
+		fmt.Fprintf(r.out, "    fix: %s\n", fix)
+	}
+}
+
+func (r *doctorReport) fail(msg, fix string) {
+	r.failures++
+	fmt.Fprintf(r.out, "✗ %s\n", msg)
+	if fix != "" {
+		fmt.Fprintf(r.out, "    fix: %s\n", fix)
+	}
+}
+
+// RunDoctor checks the health of the environment and database and prints actionable fixes.
+// When fix is true, safe repairs (unlinking records from deleted invoices) are applied.
+func (s *TimesheetService) RunDoctor(ctx context.Context, fix bool) error {
+	report := &doctorReport{out: s.out}
+
+	fmt.Fprintln(s.out, "Database:")
+	if err := s.db.Ping(ctx); err != nil {
+		report.fail(fmt.Sprintf("Cannot reach database %s (%s): %v", s.cfg.DatabaseURL, s.cfg.DatabaseDriver, err),
+			"check --db, WORK_DB, DATABASE_URL and DATABASE_DRIVER, and that the database file or server is accessible")
+		fmt.Fprintf(s.out, "\n%d problem(s) found\n", report.failures)
+		return fmt.Errorf("doctor found %d problem(s)", report.failures)
+	}
+	report.ok(fmt.Sprintf("Connected to %s (%s, from %s)", s.cfg.DatabaseURL, s.cfg.DatabaseDriver, s.cfg.DatabaseSource))
+
+	migrated, err := s.doctorCheckSchema(ctx, report)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(s.out, "\nTools:")
+	s.doctorCheckTools(report)
+
+	// The remaining checks query tables that may not exist on an unmigrated database
+	if migrated {
+		fmt.Fprintln(s.out, "\nClients:")
+		if err := s.doctorCheckClientDirs(ctx, report); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(s.out, "\nSessions:")
+		if err := s.doctorCheckActiveSessions(ctx, report); err != nil {
+			return err
+		}
+		if err := s.doctorCheckMissingInvoices(ctx, report, fix); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(s.out, "\nInvoices:")
+		if err := s.doctorCheckInvoiceTotals(ctx, report); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(s.out)
+	if report.failures > 0 {
+		fmt.Fprintf(s.out, "%d problem(s), %d warning(s) found\n", report.failures, report.warnings)
+		return fmt.Errorf("doctor found %d problem(s)", report.failures)
+	}
+	if report.warnings > 0 {
+		fmt.Fprintf(s.out, "No problems found, %d warning(s)\n", report.warnings)
+		return nil
+	}
+	fmt.Fprintln(s.out, "No problems found")
+	return nil
+}
+
+func (s *TimesheetService) doctorCheckSchema(ctx context.Context, report *doctorReport) (bool, error) {
+	migrated := true
+	for _, required := range requiredColumns {
+		columns, err := s.db.GetTableColumns(ctx, required.table)
+		if err != nil {
+			return false, err
+		}
+		if len(columns) == 0 {
+			migrated = false
+			report.fail(fmt.Sprintf("Table %s is missing", required.table), "apply the files in migrations/ in order")
+			continue
+		}
+
+		existing := make(map[string]bool, len(columns))
+		for _, column := range columns {
+			existing[column] = true
+		}
+		var missing []string
+		for _, column := range required.columns {
+			if !existing[column] {
+				missing = append(missing, column)
+			}
+		}
+		if len(missing) > 0 {
+			migrated = false
+			report.fail(fmt.Sprintf("Table %s is missing columns: %s", required.table, strings.Join(missing, ", ")),
+				"apply the pending files in migrations/ in order")
+		}
+	}
+
+	if migrated {
+		report.ok("Schema is up to date")
+	}
+	return migrated, nil
+}
+
+func (s *TimesheetService) doctorCheckTools(report *doctorReport) {
+	for _, tool := range doctorTools {
+		path, err := exec.LookPath(tool.name)
+		if err != nil {
+			report.warn(fmt.Sprintf("%s not found on PATH (used for %s)", tool.name, tool.purpose),
+				fmt.Sprintf("install %s or add it to PATH", tool.name))
+			continue
+		}
+		report.ok(fmt.Sprintf("%s found at %s", tool.name, path))
+	}
+}
+
+func (s *TimesheetService) doctorCheckClientDirs(ctx context.Context, report *doctorReport) error {
+	clients, err := s.db.GetClientsWithDirectories(ctx)
+	if err != nil {
+		return err
+	}
+
+	problems := 0
+	for _, client := range clients {
+		if client.Dir == nil {
+			continue
+		}
+		dir, err := expandHomeDir(strings.TrimSpace(*client.Dir))
+		if err != nil {
+			return err
+		}
+
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			problems++
+			report.warn(fmt.Sprintf("Directory for client %s does not exist: %s", client.Name, dir),
+				fmt.Sprintf("work clients update %s --dir <path>", client.Name))
+		}
+	}
+
+	if problems == 0 {
+		report.ok(fmt.Sprintf("All %d client directories exist", len(clients)))
+	}
+	return nil
+}
+
+func (s *TimesheetService) doctorCheckActiveSessions(ctx context.Context, report *doctorReport) error {
+	count, err := s.db.CountActiveSessions(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 1 {
+		report.fail(fmt.Sprintf("%d sessions are active at once, only one is expected", count),
+			"run `work stop` until `work status` shows no active session")
+	}
+
+	active, err := s.db.GetActiveSession(ctx)
+	if err != nil {
+		return err
+	}
+	if active == nil {
+		report.ok("No active session")
+		return nil
+	}
+
+	running := time.Since(active.StartTime)
+	switch {
+	case running < 0:
+		report.fail(fmt.Sprintf("Active session for %s starts in the future (%s)", active.ClientName, active.StartTime.Format("2006-01-02 15:04")),
+			"run `work stop` and recreate the session with `work sessions create`")
+	case running > longRunningSessionThreshold:
+		report.warn(fmt.Sprintf("Active session for %s has been running for %s", active.ClientName, s.FormatDuration(running)),
+			"if it was left running, `work stop` it and fix the end time")
+	default:
+		if count <= 1 {
+			report.ok(fmt.Sprintf("Active session for %s looks sane (%s)", active.ClientName, s.FormatDuration(running)))
+		}
+	}
+	return nil
+}
+
+func (s *TimesheetService) doctorCheckMissingInvoices(ctx context.Context, report *doctorReport, fix bool) error {
+	sessions, err := s.db.GetSessionsWithMissingInvoice(ctx)
+	if err != nil {
+		return err
+	}
+	expenses, err := s.db.GetExpensesWithMissingInvoice(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(sessions) == 0 && len(expenses) == 0 {
+		report.ok("No sessions or expenses linked to deleted invoices")
+		return nil
+	}
+
+	for _, session := range sessions {
+		fmt.Fprintf(s.out, "    session %s (%s, %s) -> missing invoice %s\n", session.ID, session.ClientName, session.StartTime.Format("2006-01-02"), *session.InvoiceID)
+	}
+	for _, expense := range expenses {
+		fmt.Fprintf(s.out, "    expense %s (%s) -> missing invoice %s\n", expense.ID, expense.ExpenseDate.Format("2006-01-02"), *expense.InvoiceID)
+	}
+
+	if fix {
+		if err := s.db.ClearMissingInvoiceIDs(ctx); err != nil {
+			return err
+		}
+		report.ok(fmt.Sprintf("Unlinked %d session(s) and %d expense(s) from deleted invoices", len(sessions), len(expenses)))
+		return nil
+	}
+
+	report.fail(fmt.Sprintf("%d session(s) and %d expense(s) are linked to deleted invoices and will never be billed", len(sessions), len(expenses)),
+		"run `work doctor --fix` to unlink them so they can be invoiced again")
+	return nil
+}
+
+func (s *TimesheetService) doctorCheckInvoiceTotals(ctx context.Context, report *doctorReport) error {
+	invoices, err := s.db.ListInvoices(ctx, -1)
+	if err != nil {
+		return err
+	}
+
+	mismatches := 0
+	for _, invoice := range invoices {
+		if invoice.Status == models.InvoiceStatusVoid {
+			continue
+		}
+		client, expected, err := s.expectedInvoiceAmounts(ctx, invoice)
+		if err != nil {
+			return err
+		}
+
+		subtotal := expected.subtotal
+		if !subtotal.Round(2).Equal(invoice.SubtotalAmount.Round(2)) {
+			mismatches++
+			report.fail(fmt.Sprintf("Invoice %s subtotal is %s but its sessions and expenses add up to %s",
+				invoice.InvoiceNumber, s.FormatClientMoney(client, invoice.SubtotalAmount), s.FormatClientMoney(client, subtotal)),
+				"run `work invoices verify --fix` to recalculate it, or `"+regenerateInvoiceCommand(invoice)+"` to reissue it")
+		}
+		if balance := invoiceBalance(invoice); balance.IsNegative() {
+			report.warn(fmt.Sprintf("Invoice %s has been overpaid by %s", invoice.InvoiceNumber, s.FormatClientMoney(client, balance.Neg())),
+				"refund the difference to the client")
+		}
+	}
+
+	if mismatches == 0 {
+		report.ok(fmt.Sprintf("All %d invoice totals match their sessions and expenses", len(invoices)))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// invoiceOwed is what the client owes on an invoice altogether, once credit notes, any early payment discount
+// taken and any amount written off are subtracted from the total. Nothing is owed on a void invoice.
+func invoiceOwed(invoice *models.Invoice) decimal.Decimal {
+	if invoice.Status == models.InvoiceStatusVoid {
+		return decimal.Zero
+	}
+	return invoice.TotalAmount.Sub(invoice.AmountCredited).Sub(invoice.AmountDiscounted).Sub(invoice.AmountWrittenOff)
+}
+
+// hasEarlyDiscount reports whether the client is offered a discount for paying invoices early
+func hasEarlyDiscount(client *models.Client) bool {
+	return client != nil && client.EarlyDiscountPercent != nil && *client.EarlyDiscountPercent > 0 &&
+		client.EarlyDiscountDays != nil && *client.EarlyDiscountDays > 0
+}
+
+// earlyDiscountDeadline is the last day an invoice can be paid on to get the early payment discount
+func earlyDiscountDeadline(client *models.Client, invoice *models.Invoice) time.Time {
+	issued := invoice.GeneratedDate
+	return time.Date(issued.Year(), issued.Month(), issued.Day(), 0, 0, 0, 0, issued.Location()).
+		AddDate(0, 0, int(*client.EarlyDiscountDays))
+}
+
+// earlyDiscountAmount is the discount offered on an amount owed, rounded to the cent
+func earlyDiscountAmount(client *models.Client, owed decimal.Decimal) decimal.Decimal {
+	return owed.Mul(decimal.NewFromFloat(*client.EarlyDiscountPercent)).Div(decimal.NewFromInt(100)).Round(2)
+}
+
+// earlyPaymentDiscount is the discount the client can take on an invoice paid on date: nothing if the client
+// has no early payment terms, the deadline has passed, or a discount has already been taken
+func earlyPaymentDiscount(client *models.Client, invoice *models.Invoice, date time.Time) decimal.Decimal {
+	if !hasEarlyDiscount(client) || !invoice.AmountDiscounted.IsZero() {
+		return decimal.Zero
+	}
+	if date.After(earlyDiscountDeadline(client, invoice).AddDate(0, 0, 1)) {
+		return decimal.Zero
+	}
+	return earlyDiscountAmount(client, invoice.TotalAmount.Sub(invoice.AmountCredited))
+}
+
+// discountTaken is how much of an available early payment discount a payment settles the invoice with. A
+// payment short of the remaining balance by no more than the discount clears the invoice, and anything smaller
+// is an ordinary part payment.
+func discountTaken(amount, remaining, discount decimal.Decimal) decimal.Decimal {
+	if !discount.IsPositive() || amount.GreaterThanOrEqual(remaining) || amount.LessThan(remaining.Sub(discount)) {
+		return decimal.Zero
+	}
+	return remaining.Sub(amount)
+}
+
+// earlyDiscountTerms describes a client's early payment discount, e.g. "2% within 7 days"
+func earlyDiscountTerms(client *models.Client) string {
+	return fmt.Sprintf("%s%% within %d days", decimal.NewFromFloat(*client.EarlyDiscountPercent).String(), *client.EarlyDiscountDays)
+}
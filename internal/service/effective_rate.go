@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// reportPeriod is one of the periods a trend report is broken into
+type reportPeriod struct {
+	label    string
+	from, to time.Time
+}
+
+// effectiveRate is a client's revenue and the hours worked for it, in one period or overall
+type effectiveRate struct {
+	revenue decimal.Decimal
+	worked  time.Duration
+}
+
+// rate is revenue per hour worked, or false when no time was worked
+func (r effectiveRate) rate() (decimal.Decimal, bool) {
+	hours := decimal.NewFromFloat(r.worked.Hours())
+	if !hours.IsPositive() {
+		return decimal.Zero, false
+	}
+	return r.revenue.Div(hours), true
+}
+
+type clientEffectiveRates struct {
+	name    string
+	m       money.Formatter
+	periods []effectiveRate
+	overall effectiveRate
+}
+
+// ShowEffectiveRates displays what each client actually paid per hour worked for them over the last count
+// periods up to the one containing date: revenue excluding GST and billed expenses, divided by every hour worked
+// including unbillable time and hours covered by a retainer. Revenue is what was invoiced, or only what's been
+// paid of it when paidOnly is set. Invoices count towards the periods their own period overlaps, shared out by
+// days, so a monthly invoice is spread across a weekly report.
+func (s *TimesheetService) ShowEffectiveRates(ctx context.Context, clientName, period, date string, count int, paidOnly bool) error {
+	if err := ValidatePeriod(period); err != nil {
+		return err
+	}
+	if count < 1 {
+		return fmt.Errorf("--periods must be at least 1")
+	}
+	target := time.Now()
+	if date != "" {
+		var err error
+		if target, err = time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+		}
+	}
+	periods := s.trailingPeriods(period, target, count)
+	from, to := periods[0].from, periods[len(periods)-1].to
+
+	var clientID string
+	if clientName != "" {
+		client, err := s.db.GetClientByName(ctx, clientName)
+		if err != nil {
+			return fmt.Errorf("client '%s' does not exist", clientName)
+		}
+		clientID = client.ID
+	}
+	clients, err := s.db.ListClients(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list clients: %w", err)
+	}
+	// Sessions are found by start time, so include the day before for any running past midnight into the range
+	sessions, err := s.ListSessionsWithDateRange(ctx, from.AddDate(0, 0, -1).Format("2006-01-02"), to.Format("2006-01-02"), 100000)
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %w", err)
+	}
+	invoices, err := s.db.ListInvoices(ctx, -1)
+	if err != nil {
+		return err
+	}
+	expenses, err := s.db.ListExpenses(ctx)
+	if err != nil {
+		return err
+	}
+
+	rates := make(map[string]*clientEffectiveRates)
+	ratesFor := func(id string) *clientEffectiveRates {
+		if r, ok := rates[id]; ok {
+			return r
+		}
+		r := &clientEffectiveRates{periods: make([]effectiveRate, len(periods))}
+		for _, client := range clients {
+			if client.ID == id {
+				r.name, r.m = client.Name, s.clientMoney(client)
+			}
+		}
+		rates[id] = r
+		return r
+	}
+
+	for _, session := range sessions {
+		if session.EndTime == nil || (clientID != "" && session.ClientID != clientID) {
+			continue
+		}
+		for _, day := range s.sessionDays(session) {
+			for i, p := range periods {
+				if !day.date.Before(startOfDay(p.from)) && !day.date.After(p.to) {
+					r := ratesFor(session.ClientID)
+					r.periods[i].worked += day.worked
+					r.overall.worked += day.worked
+					break
+				}
+			}
+		}
+	}
+
+	billedExpenses := make(map[string]decimal.Decimal)
+	for _, expense := range expenses {
+		if expense.InvoiceID != nil {
+			billedExpenses[*expense.InvoiceID] = billedExpenses[*expense.InvoiceID].Add(ExpenseBilledAmount(expense))
+		}
+	}
+	for _, invoice := range invoices {
+		if (clientID != "" && invoice.ClientID != clientID) || invoice.Status == models.InvoiceStatusVoid {
+			continue
+		}
+		revenue := invoiceRevenue(invoice, billedExpenses[invoice.ID], paidOnly)
+		invoiceFrom, invoiceTo := dateOnly(invoice.PeriodStartDate), dateOnly(invoice.PeriodEndDate)
+		invoiceDays := decimal.NewFromInt(int64(countDays(invoiceFrom, invoiceTo)))
+		for i, p := range periods {
+			overlapFrom, overlapTo := dateOnly(p.from), dateOnly(p.to)
+			if invoiceFrom.After(overlapFrom) {
+				overlapFrom = invoiceFrom
+			}
+			if invoiceTo.Before(overlapTo) {
+				overlapTo = invoiceTo
+			}
+			if overlapTo.Before(overlapFrom) {
+				continue
+			}
+			share := revenue.Mul(decimal.NewFromInt(int64(countDays(overlapFrom, overlapTo)))).Div(invoiceDays)
+			r := ratesFor(invoice.ClientID)
+			r.periods[i].revenue = r.periods[i].revenue.Add(share)
+			r.overall.revenue = r.overall.revenue.Add(share)
+		}
+	}
+
+	basis := "invoiced"
+	if paidOnly {
+		basis = "paid"
+	}
+	if len(rates) == 0 {
+		fmt.Fprintf(s.out, "No sessions or invoices found from %s to %s\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		return nil
+	}
+
+	ordered := make([]*clientEffectiveRates, 0, len(rates))
+	for _, r := range rates {
+		ordered = append(ordered, r)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		ri, oki := ordered[i].overall.rate()
+		rj, okj := ordered[j].overall.rate()
+		if oki != okj || !ri.Equal(rj) {
+			return oki && (!okj || ri.GreaterThan(rj))
+		}
+		return ordered[i].name < ordered[j].name
+	})
+
+	fmt.Fprintf(s.out, "Effective hourly rate by %s (%s revenue excluding GST and expenses, over all hours worked)\n\n", period, basis)
+	header := fmt.Sprintf("%-16s", "Client")
+	for _, p := range periods {
+		header += fmt.Sprintf(" %11s", p.label)
+	}
+	fmt.Fprintf(s.out, "%s %11s %9s %14s  %s\n", header, "Overall", "Hours", "Revenue", "Trend")
+	for _, r := range ordered {
+		row := fmt.Sprintf("%-16s", truncateString(r.name, 16))
+		var rated []decimal.Decimal
+		for _, p := range r.periods {
+			rate, ok := p.rate()
+			if !ok {
+				row += fmt.Sprintf(" %11s", "-")
+				continue
+			}
+			rated = append(rated, rate)
+			row += fmt.Sprintf(" %11s", r.m.Format(rate.Round(2)))
+		}
+		overall := "-"
+		if rate, ok := r.overall.rate(); ok {
+			overall = r.m.Format(rate.Round(2))
+		}
+		fmt.Fprintf(s.out, "%s %11s %9s %14s  %s\n", row, overall, s.FormatDurationFor(config.DurationContextReport, r.overall.worked),
+			r.m.Format(r.overall.revenue.Round(2)), rateTrend(rated))
+	}
+	return nil
+}
+
+// invoiceRevenue is what an invoice earned for the work on it: its subtotal less the expenses billed on it, or
+// the share of that paid so far when paidOnly is set
+func invoiceRevenue(invoice *models.Invoice, billedExpenses decimal.Decimal, paidOnly bool) decimal.Decimal {
+	revenue := invoice.SubtotalAmount.Sub(billedExpenses)
+	if !paidOnly {
+		return revenue
+	}
+	if !invoice.TotalAmount.IsPositive() {
+		return decimal.Zero
+	}
+	paid := invoice.AmountPaid.Div(invoice.TotalAmount)
+	if paid.GreaterThan(decimal.NewFromInt(1)) {
+		paid = decimal.NewFromInt(1)
+	}
+	return revenue.Mul(paid)
+}
+
+// rateTrend compares the first and last rates, e.g. "up 12.5%", or is empty with fewer than two
+func rateTrend(rates []decimal.Decimal) string {
+	if len(rates) < 2 || !rates[0].IsPositive() {
+		return ""
+	}
+	change := rates[len(rates)-1].Sub(rates[0]).Div(rates[0]).Mul(decimal.NewFromInt(100))
+	switch {
+	case change.Round(1).IsZero():
+		return "flat"
+	case change.IsPositive():
+		return "up " + change.StringFixed(1) + "%"
+	default:
+		return "down " + change.Neg().StringFixed(1) + "%"
+	}
+}
+
+// trailingPeriods returns the count periods of the given type ending with the one containing target, oldest first
+func (s *TimesheetService) trailingPeriods(period string, target time.Time, count int) []reportPeriod {
+	periods := make([]reportPeriod, count)
+	day := target
+	for i := count - 1; i >= 0; i-- {
+		from, to := s.CalculatePeriodRange(period, day)
+		periods[i] = reportPeriod{label: s.periodLabel(period, from), from: from, to: to}
+		day = from.AddDate(0, 0, -1)
+	}
+	return periods
+}
+
+// periodLabel is a short column heading for the period starting at from
+func (s *TimesheetService) periodLabel(period string, from time.Time) string {
+	switch period {
+	case "month":
+		return from.Format("2006-01")
+	case "quarter":
+		// Numbered within the financial year, e.g. Q1 2026 for July to September 2025 with a July start
+		quarter := (int(from.Month())-int(s.financialYearStartMonth())+12)%12/3 + 1
+		return fmt.Sprintf("Q%d %s", quarter, strings.TrimPrefix(s.financialYearLabel(s.financialYear(from)), "FY"))
+	case "year":
+		return s.financialYearLabel(s.financialYear(from))
+	default:
+		return from.Format("2006-01-02")
+	}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+// EncryptClientDetails encrypts the contact details of clients saved before ENCRYPTION_KEY was set. Details
+// saved after it's set are encrypted as they're saved.
+func (s *TimesheetService) EncryptClientDetails(ctx context.Context) error {
+	changed, err := s.db.EncryptClientFields(ctx)
+	if err != nil {
+		return err
+	}
+	if changed == 0 {
+		fmt.Fprintln(s.out, "No plaintext client details to encrypt")
+		return nil
+	}
+	fmt.Fprintf(s.out, "Encrypted contact details for %d client(s), keep ENCRYPTION_KEY safe as they can't be read without it\n", changed)
+	return nil
+}
+
+// DecryptClientDetails stores encrypted client contact details as plaintext again, such as before turning
+// encryption off or changing keys
+func (s *TimesheetService) DecryptClientDetails(ctx context.Context) error {
+	changed, err := s.db.DecryptClientFields(ctx)
+	if err != nil {
+		return err
+	}
+	if changed == 0 {
+		fmt.Fprintln(s.out, "No encrypted client details to decrypt")
+		return nil
+	}
+	fmt.Fprintf(s.out, "Decrypted contact details for %d client(s)\n", changed)
+	return nil
+}
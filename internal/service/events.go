@@ -0,0 +1,179 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// Events published by the service, matching config.WebhookEvents
+const (
+	EventSessionStarted   = "session.started"
+	EventSessionStopped   = "session.stopped"
+	EventInvoiceGenerated = "invoice.generated"
+	EventInvoicePaid      = "invoice.paid"
+)
+
+// Event is something that happened worth telling other tools about. It's sent to webhooks as JSON, or used as
+// the data for a webhook's template.
+type Event struct {
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+	Message       string    `json:"message"`
+	Client        string    `json:"client,omitempty"`
+	SessionID     string    `json:"session_id,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	Duration      string    `json:"duration,omitempty"`
+	InvoiceID     string    `json:"invoice_id,omitempty"`
+	InvoiceNumber string    `json:"invoice_number,omitempty"`
+	Amount        string    `json:"amount,omitempty"`
+	Status        string    `json:"status,omitempty"`
+}
+
+// EventHandler is called with each event it's subscribed to
+type EventHandler func(ctx context.Context, event Event)
+
+// EventBus passes events to the handlers subscribed to them, in the order they subscribed
+type EventBus struct {
+	mu       sync.Mutex
+	handlers map[string][]EventHandler
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[string][]EventHandler)}
+}
+
+// Subscribe calls handler for every event of eventType, or for every event when eventType is "*"
+func (b *EventBus) Subscribe(eventType string, handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish calls the handlers for event before returning, since the CLI exits as soon as a command is done
+func (b *EventBus) Publish(ctx context.Context, event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.mu.Lock()
+	handlers := append(append([]EventHandler{}, b.handlers[event.Type]...), b.handlers["*"]...)
+	b.mu.Unlock()
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
+
+// Events returns the bus the service publishes session and invoice events to
+func (s *TimesheetService) Events() *EventBus {
+	return s.events
+}
+
+func (s *TimesheetService) publishSessionStarted(ctx context.Context, session *models.WorkSession) {
+	event := Event{
+		Type:      EventSessionStarted,
+		Time:      session.StartTime,
+		Message:   fmt.Sprintf("Started work for %s", session.ClientName),
+		Client:    session.ClientName,
+		SessionID: session.ID,
+	}
+	if session.Description != nil {
+		event.Description = *session.Description
+		event.Message += ": " + *session.Description
+	}
+	s.events.Publish(ctx, event)
+}
+
+func (s *TimesheetService) publishSessionStopped(ctx context.Context, session *models.WorkSession) {
+	duration := s.FormatDuration(s.CalculateDuration(session))
+	event := Event{
+		Type:      EventSessionStopped,
+		Message:   fmt.Sprintf("Stopped work for %s after %s", session.ClientName, duration),
+		Client:    session.ClientName,
+		SessionID: session.ID,
+		Duration:  duration,
+	}
+	if session.EndTime != nil {
+		event.Time = *session.EndTime
+	}
+	if session.Description != nil {
+		event.Description = *session.Description
+	}
+	s.events.Publish(ctx, event)
+}
+
+func (s *TimesheetService) publishInvoiceGenerated(ctx context.Context, client *models.Client, invoice *models.Invoice) {
+	amount := s.clientMoney(client).Format(invoice.TotalAmount)
+	s.events.Publish(ctx, Event{
+		Type:          EventInvoiceGenerated,
+		Message:       fmt.Sprintf("Generated invoice %s for %s: %s", invoice.InvoiceNumber, client.Name, amount),
+		Client:        client.Name,
+		InvoiceID:     invoice.ID,
+		InvoiceNumber: invoice.InvoiceNumber,
+		Amount:        amount,
+	})
+}
+
+func (s *TimesheetService) publishInvoicePaid(ctx context.Context, invoice *models.Invoice, amount, paidToDate, owed, status string) {
+	s.events.Publish(ctx, Event{
+		Type:          EventInvoicePaid,
+		Message:       fmt.Sprintf("%s paid %s on %s (%s: %s/%s)", invoice.ClientName, amount, invoice.InvoiceNumber, status, paidToDate, owed),
+		Client:        invoice.ClientName,
+		InvoiceID:     invoice.ID,
+		InvoiceNumber: invoice.InvoiceNumber,
+		Amount:        amount,
+		Status:        status,
+	})
+}
+
+// webhookTimeout bounds each webhook call, so a slow endpoint can't hold up the command that fired it
+const webhookTimeout = 10 * time.Second
+
+// subscribeWebhooks posts events to each configured webhook. Failures are logged rather than returned, since
+// the work that fired the event has already been saved.
+func subscribeWebhooks(bus *EventBus, webhooks []config.Webhook, logger *slog.Logger) {
+	client := &http.Client{Timeout: webhookTimeout}
+	for _, webhook := range webhooks {
+		bus.Subscribe(webhook.Event, func(ctx context.Context, event Event) {
+			if err := postWebhook(ctx, client, webhook, event); err != nil {
+				logger.Warn("webhook failed", "event", event.Type, "url", webhook.URL, "error", err)
+				return
+			}
+			logger.Debug("webhook sent", "event", event.Type, "url", webhook.URL)
+		})
+	}
+}
+
+func postWebhook(ctx context.Context, client *http.Client, webhook config.Webhook, event Event) error {
+	var body bytes.Buffer
+	if webhook.Template != nil {
+		if err := webhook.Template.Execute(&body, event); err != nil {
+			return fmt.Errorf("failed to render template: %w", err)
+		}
+	} else if err := json.NewEncoder(&body).Encode(event); err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// ExpenseCategories are the categories an expense can be filed under
+var ExpenseCategories = []string{"travel", "software", "hardware", "other"}
+
+// uncategorisedExpense labels expenses without a category in subtotals
+const uncategorisedExpense = "uncategorised"
+
+// ValidateExpenseCategory returns an error if category is not one of ExpenseCategories
+func ValidateExpenseCategory(category string) error {
+	if category == "" {
+		return nil
+	}
+	for _, known := range ExpenseCategories {
+		if strings.EqualFold(category, known) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported category %q, expected one of: %s", category, strings.Join(ExpenseCategories, ", "))
+}
+
+// FilterExpensesByCategory returns the expenses filed under category, or without a category when
+// category is "uncategorised"
+func FilterExpensesByCategory(expenses []*models.Expense, category string) []*models.Expense {
+	var filtered []*models.Expense
+	for _, expense := range expenses {
+		if strings.EqualFold(expenseCategory(expense), category) {
+			filtered = append(filtered, expense)
+		}
+	}
+	return filtered
+}
+
+// ExpenseBilledAmount is what the client is charged for an expense, its cost plus any markup, or nothing
+// when the expense isn't billable
+func ExpenseBilledAmount(expense *models.Expense) decimal.Decimal {
+	if !expense.Billable {
+		return decimal.Zero
+	}
+	if expense.MarkupPercent == nil || expense.MarkupPercent.IsZero() {
+		return expense.Amount
+	}
+	markup := expense.Amount.Mul(*expense.MarkupPercent).Div(decimal.NewFromInt(100))
+	return expense.Amount.Add(markup).Round(2)
+}
+
+// invoiceableExpenses drops expenses that aren't passed on to the client, and drafts imported from
+// receipts that haven't been reviewed yet
+func invoiceableExpenses(expenses []*models.Expense) []*models.Expense {
+	var billable []*models.Expense
+	for _, expense := range expenses {
+		if expense.Billable && !expense.Draft {
+			billable = append(billable, expense)
+		}
+	}
+	return billable
+}
+
+// CreateMileageExpense records a travel expense for a client, charging distanceKm at ratePerKm, or at the
+// configured MILEAGE_RATE when ratePerKm is nil
+func (s *TimesheetService) CreateMileageExpense(ctx context.Context, clientName string, distanceKm decimal.Decimal, ratePerKm *decimal.Decimal, expenseDate time.Time, reference *string, description *string, billable bool) (*models.Expense, error) {
+	if !distanceKm.IsPositive() {
+		return nil, fmt.Errorf("distance must be greater than 0")
+	}
+
+	client, err := s.db.GetClientByName(ctx, clientName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("client '%s' does not exist", clientName)
+		}
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	var rate decimal.Decimal
+	switch {
+	case ratePerKm != nil:
+		rate = *ratePerKm
+	case s.cfg != nil:
+		rate = s.cfg.MileageRate
+	}
+	if !rate.IsPositive() {
+		return nil, fmt.Errorf("rate per kilometre must be greater than 0")
+	}
+
+	amount := distanceKm.Mul(rate).Round(2)
+	return s.db.CreateMileageExpense(ctx, client.ID, amount, distanceKm, rate, expenseDate, reference, description, billable)
+}
+
+// mileageLabel describes the distance and rate of a mileage expense, e.g. "Travel 42km @ $0.85", using
+// format for the rate. Expenses that weren't recorded as mileage have no label.
+func mileageLabel(expense *models.Expense, format func(decimal.Decimal) string) string {
+	if expense.DistanceKm == nil || expense.RatePerKm == nil {
+		return ""
+	}
+	return fmt.Sprintf("Travel %skm @ %s", expense.DistanceKm.String(), format(*expense.RatePerKm))
+}
+
+// FormatMileage describes the distance and rate of a mileage expense in its client's currency, or
+// returns an empty string for other expenses
+func (s *TimesheetService) FormatMileage(expense *models.Expense) string {
+	return mileageLabel(expense, s.clientMoneyByID(expense.ClientID).Format)
+}
+
+// DeleteExpense moves an expense to the trash. Expenses already on an invoice are only deleted when force is set,
+// since the invoice's totals will no longer match until it's regenerated.
+func (s *TimesheetService) DeleteExpense(ctx context.Context, expenseID string, force bool) error {
+	expense, err := s.db.GetExpenseByID(ctx, expenseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("expense '%s' does not exist", expenseID)
+		}
+		return err
+	}
+
+	var invoice *models.Invoice
+	if expense.InvoiceID != nil {
+		// The invoice may have been deleted without unlinking its expenses
+		invoice, err = s.db.GetInvoiceByID(ctx, *expense.InvoiceID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to get invoice for expense: %w", err)
+		}
+	}
+
+	if invoice != nil && !force {
+		return fmt.Errorf("expense '%s' is on invoice %s, use --force to delete it anyway and then regenerate the invoice", expenseID, invoice.InvoiceNumber)
+	}
+
+	if err := s.db.DeleteExpense(ctx, expenseID); err != nil {
+		return err
+	}
+
+	if invoice != nil {
+		fmt.Fprintf(s.out, "Deleted expense %s, invoice %s no longer matches its expenses\n", expenseID, invoice.InvoiceNumber)
+		fmt.Fprintf(s.out, "Regenerate it with: %s\n", regenerateInvoiceCommand(invoice))
+		return nil
+	}
+	fmt.Fprintf(s.out, "Deleted expense %s, restore it from the trash with 'work trash restore %s'\n", expenseID, expenseID)
+	return nil
+}
+
+// expenseCategorySubtotal is the total of expenses in one category
+type expenseCategorySubtotal struct {
+	category string
+	count    int
+	total    decimal.Decimal
+}
+
+// calculateExpenseCategorySubtotals groups expenses by category, largest total first, totalling the
+// amount returned for each expense
+func (s *TimesheetService) calculateExpenseCategorySubtotals(expenses []*models.Expense, amount func(*models.Expense) decimal.Decimal) []expenseCategorySubtotal {
+	byCategory := make(map[string]*expenseCategorySubtotal)
+	for _, expense := range expenses {
+		category := expenseCategory(expense)
+		subtotal, ok := byCategory[category]
+		if !ok {
+			subtotal = &expenseCategorySubtotal{category: category}
+			byCategory[category] = subtotal
+		}
+		subtotal.count++
+		subtotal.total = subtotal.total.Add(amount(expense))
+	}
+
+	subtotals := make([]expenseCategorySubtotal, 0, len(byCategory))
+	for _, subtotal := range byCategory {
+		subtotals = append(subtotals, *subtotal)
+	}
+	sort.Slice(subtotals, func(i, j int) bool {
+		if !subtotals[i].total.Equal(subtotals[j].total) {
+			return subtotals[i].total.GreaterThan(subtotals[j].total)
+		}
+		return subtotals[i].category < subtotals[j].category
+	})
+	return subtotals
+}
+
+// ShowExpensesByCategory displays what expenses cost by category for each year, optionally for one client.
+// Years are financial years when byFinancialYear is set. A year of 0 shows every year.
+func (s *TimesheetService) ShowExpensesByCategory(ctx context.Context, clientName string, year int, byFinancialYear bool) error {
+	var expenses []*models.Expense
+	var err error
+	if clientName != "" {
+		expenses, err = s.ListExpensesByClient(ctx, clientName)
+	} else {
+		expenses, err = s.db.ListExpenses(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Amounts in different currencies can't be summed, so each year is split by currency
+	type yearCurrency struct {
+		year     int
+		currency string
+	}
+	groups := make(map[yearCurrency][]*models.Expense)
+	formatters := make(map[string]money.Formatter)
+	for _, expense := range expenses {
+		expenseYear := s.reportYear(expense.ExpenseDate, byFinancialYear)
+		if expense.Draft || (year != 0 && expenseYear != year) {
+			continue
+		}
+		m := s.clientMoneyByID(expense.ClientID)
+		formatters[m.Currency] = m
+		key := yearCurrency{expenseYear, m.Currency}
+		groups[key] = append(groups[key], expense)
+	}
+
+	if len(groups) == 0 {
+		fmt.Fprintln(s.out, "No expenses found")
+		return nil
+	}
+
+	keys := make([]yearCurrency, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].year != keys[j].year {
+			return keys[i].year > keys[j].year
+		}
+		return keys[i].currency < keys[j].currency
+	})
+
+	home := s.homeMoney()
+	fmt.Fprintln(s.out, "Expenses by category")
+	for _, key := range keys {
+		m := formatters[key.currency]
+		if key.currency != home.Currency {
+			fmt.Fprintf(s.out, "\n%s (%s)\n", s.reportYearLabel(key.year, byFinancialYear), key.currency)
+		} else {
+			fmt.Fprintf(s.out, "\n%s\n", s.reportYearLabel(key.year, byFinancialYear))
+		}
+		fmt.Fprintf(s.out, "  %-14s %8s %15s\n", "Category", "Count", "Total")
+		total := decimal.Zero
+		count := 0
+		for _, subtotal := range s.calculateExpenseCategorySubtotals(groups[key], expenseCost) {
+			fmt.Fprintf(s.out, "  %-14s %8d %15s\n", subtotal.category, subtotal.count, m.Format(subtotal.total))
+			total = total.Add(subtotal.total)
+			count += subtotal.count
+		}
+		fmt.Fprintf(s.out, "  %-14s %8d %15s\n", "Total", count, m.Format(total))
+	}
+
+	return nil
+}
+
+func expenseCategory(expense *models.Expense) string {
+	if expense.Category == nil || *expense.Category == "" {
+		return uncategorisedExpense
+	}
+	return *expense.Category
+}
+
+func expenseCost(expense *models.Expense) decimal.Decimal {
+	return expense.Amount
+}
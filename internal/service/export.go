@@ -0,0 +1,497 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// ExportSchemaVersion is written to the manifest of a full export and bumped whenever a field is renamed or
+// removed, so imports can tell what they're reading. Adding fields doesn't change it.
+const ExportSchemaVersion = 1
+
+// Formats data can be exported in
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+)
+
+// ExportEntities are the kinds of records that can be exported, in the order they depend on each other. Each is
+// written to a file of the same name in a full export.
+var ExportEntities = []string{"clients", "users", "sessions", "invoices", "payments", "credit_notes", "expenses", "session_templates",
+	"client_rates", "client_contacts", "expense_rules", "milestones", "session_repos",
+	"leave"}
+
+// exportManifestFile describes a full export
+const exportManifestFile = "manifest.json"
+
+// exportManifest is written alongside the records of a full export
+type exportManifest struct {
+	SchemaVersion int            `json:"schema_version"`
+	Format        string         `json:"format"`
+	ExportedAt    time.Time      `json:"exported_at"`
+	Counts        map[string]int `json:"counts"`
+}
+
+// exportSession is a session as exported, with the breaks taken out of it
+type exportSession struct {
+	*models.WorkSession
+	Breaks []*models.SessionBreak `json:"breaks,omitempty"`
+}
+
+// exportTable holds one kind of record ready to write. JSON exports write records as an array with the json
+// tags of the models as field names, and CSV exports write the header and rows, using the same names.
+type exportTable struct {
+	entity  string
+	records any
+	count   int
+	header  []string
+	rows    [][]string
+}
+
+// ValidateExportFormat checks an export format is one that can be written
+func ValidateExportFormat(format string) error {
+	if format != ExportFormatJSON && format != ExportFormatCSV {
+		return fmt.Errorf("invalid format %q, expected %s or %s", format, ExportFormatJSON, ExportFormatCSV)
+	}
+	return nil
+}
+
+// ExportEntity writes every record of one kind to output, or stdout when output is empty or "-"
+func (s *TimesheetService) ExportEntity(ctx context.Context, entity, format, output string) error {
+	if err := ValidateExportFormat(format); err != nil {
+		return err
+	}
+	table, err := s.loadExportTable(ctx, entity)
+	if err != nil {
+		return err
+	}
+
+	if output == "" || output == "-" {
+		return table.write(s.out, format)
+	}
+	if err := table.writeFile(output, format); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Exported %d %s to %s\n", table.count, strings.ReplaceAll(entity, "_", " "), output)
+	return nil
+}
+
+// ExportAll writes every client, user, session, invoice, payment, credit note, expense, session template, client
+// rate change, client contact, expense rule, milestone, session repository and day of leave to a file per kind in
+// dir, with a manifest of the schema version and counts, for backup or moving to another machine or tool
+func (s *TimesheetService) ExportAll(ctx context.Context, format, dir string) error {
+	if err := ValidateExportFormat(format); err != nil {
+		return err
+	}
+	if dir == "" {
+		return fmt.Errorf("an output directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	manifest := exportManifest{
+		SchemaVersion: ExportSchemaVersion,
+		Format:        format,
+		ExportedAt:    time.Now(),
+		Counts:        make(map[string]int),
+	}
+	for _, entity := range ExportEntities {
+		table, err := s.loadExportTable(ctx, entity)
+		if err != nil {
+			return err
+		}
+		if err := table.writeFile(filepath.Join(dir, entity+"."+format), format); err != nil {
+			return err
+		}
+		manifest.Counts[entity] = table.count
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, exportManifestFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	fmt.Fprintf(s.out, "Exported to %s:\n", dir)
+	for _, entity := range ExportEntities {
+		fmt.Fprintf(s.out, "  %-18s %d\n", entity, manifest.Counts[entity])
+	}
+	return nil
+}
+
+// loadExportTable reads every record of one kind, ordered by ID so repeated exports of the same data match
+func (s *TimesheetService) loadExportTable(ctx context.Context, entity string) (*exportTable, error) {
+	table := &exportTable{entity: entity}
+
+	switch entity {
+	case "clients":
+		clients, err := s.db.ListClients(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+		table.records, table.count = clients, len(clients)
+		table.header = []string{"id", "name", "hourly_rate", "company_name", "contact_name", "email", "phone",
+			"address_line1", "address_line2", "city", "state", "postal_code", "country", "abn", "dir",
+			"retainer_amount", "retainer_hours", "retainer_basis", "retainer_start_date", "currency", "locale", "source",
+			"work_log", "early_discount_percent", "early_discount_days", "invoice_notes", "payment_terms", "po_number",
+			"project_code", "invoice_rounding", "tax_treatment", "logo_path", "brand_colour",
+			"analysis_max_commits", "analysis_ignore", "analysis_detail", "analysis_prompt", "created_at", "updated_at"}
+		for _, c := range clients {
+			table.rows = append(table.rows, []string{c.ID, c.Name, c.HourlyRate.String(), csvString(c.CompanyName),
+				csvString(c.ContactName), csvString(c.Email), csvString(c.Phone), csvString(c.AddressLine1),
+				csvString(c.AddressLine2), csvString(c.City), csvString(c.State), csvString(c.PostalCode),
+				csvString(c.Country), csvString(c.Abn), csvString(c.Dir), csvDecimal(c.RetainerAmount),
+				csvFloat(c.RetainerHours), csvString(c.RetainerBasis), csvTime(c.RetainerStart), csvString(c.Currency),
+				csvString(c.Locale), csvString(c.Source), csvString(c.WorkLog), csvFloat(c.EarlyDiscountPercent),
+				csvInt(c.EarlyDiscountDays), csvString(c.InvoiceNotes), csvString(c.PaymentTerms), csvString(c.PoNumber),
+				csvString(c.ProjectCode), csvDecimal(c.InvoiceRounding), c.TaxTreatment, csvString(c.LogoPath), csvString(c.BrandColour), csvInt(c.AnalysisMaxCommits), csvString(c.AnalysisIgnore),
+				csvString(c.AnalysisDetail), csvString(c.AnalysisPrompt), csvTime(&c.CreatedAt), csvTime(&c.UpdatedAt)})
+		}
+
+	case "users":
+		users, err := s.db.ListUsers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+		table.records, table.count = users, len(users)
+		table.header = []string{"id", "name", "cost_rate", "created_at", "updated_at"}
+		for _, user := range users {
+			table.rows = append(table.rows, []string{user.ID, user.Name, csvDecimal(user.CostRate),
+				csvTime(&user.CreatedAt), csvTime(&user.UpdatedAt)})
+		}
+
+	case "sessions":
+		sessions, err := s.db.ListRecentSessions(ctx, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+		records := make([]exportSession, len(sessions))
+		for i, session := range sessions {
+			records[i].WorkSession = session
+			if session.BreakSeconds > 0 {
+				if records[i].Breaks, err = s.db.ListSessionBreaks(ctx, session.ID); err != nil {
+					return nil, err
+				}
+			}
+		}
+		table.records, table.count = records, len(records)
+		table.header = []string{"id", "client_id", "client_name", "user_id", "start_time", "end_time", "description",
+			"hourly_rate", "includes_gst", "full_work_summary", "outside_git", "invoice_id", "hostname",
+			"os", "git_branch", "break_seconds", "created_at", "updated_at"}
+		for _, session := range sessions {
+			table.rows = append(table.rows, []string{session.ID, session.ClientID, session.ClientName,
+				csvString(session.UserID), csvTime(&session.StartTime), csvTime(session.EndTime), csvString(session.Description),
+				csvDecimal(session.HourlyRate), strconv.FormatBool(session.IncludesGst),
+				csvString(session.FullWorkSummary), csvString(session.OutsideGit), csvString(session.InvoiceID),
+				csvString(session.Hostname), csvString(session.OS), csvString(session.GitBranch), strconv.FormatInt(session.BreakSeconds, 10), csvTime(&session.CreatedAt),
+				csvTime(&session.UpdatedAt)})
+		}
+
+	case "invoices":
+		invoices, err := s.db.ListInvoices(ctx, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(invoices, func(i, j int) bool { return invoices[i].ID < invoices[j].ID })
+		table.records, table.count = invoices, len(invoices)
+		table.header = []string{"id", "client_id", "client_name", "invoice_number", "period_type",
+			"period_start_date", "period_end_date", "subtotal_amount", "gst_amount", "total_amount", "group_by",
+			"generated_date", "needs_regeneration", "amount_paid", "payment_date", "amount_credited",
+			"amount_discounted", "notes", "payment_terms", "po_number", "project_code", "status", "status_reason",
+			"status_changed_at", "amount_written_off", "rounding", "created_at", "updated_at"}
+		for _, invoice := range invoices {
+			table.rows = append(table.rows, []string{invoice.ID, invoice.ClientID, invoice.ClientName,
+				invoice.InvoiceNumber, invoice.PeriodType, csvTime(&invoice.PeriodStartDate),
+				csvTime(&invoice.PeriodEndDate), invoice.SubtotalAmount.String(), invoice.GstAmount.String(),
+				invoice.TotalAmount.String(), invoice.GroupBy, csvTime(&invoice.GeneratedDate),
+				strconv.FormatBool(invoice.NeedsRegeneration), invoice.AmountPaid.String(), csvTime(invoice.PaymentDate),
+				invoice.AmountCredited.String(), invoice.AmountDiscounted.String(), csvString(invoice.Notes),
+				csvString(invoice.PaymentTerms), csvString(invoice.PoNumber), csvString(invoice.ProjectCode),
+				invoice.Status, csvString(invoice.StatusReason), csvTime(invoice.StatusChangedAt),
+				invoice.AmountWrittenOff.String(), csvDecimal(invoice.Rounding), csvTime(&invoice.CreatedAt),
+				csvTime(&invoice.UpdatedAt)})
+		}
+
+	case "payments":
+		payments, err := s.db.ListPayments(ctx, "", "")
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(payments, func(i, j int) bool { return payments[i].ID < payments[j].ID })
+		table.records, table.count = payments, len(payments)
+		table.header = []string{"id", "invoice_id", "invoice_number", "client_name", "amount", "payment_date",
+			"discount_amount", "created_at", "updated_at"}
+		for _, payment := range payments {
+			table.rows = append(table.rows, []string{payment.ID, payment.InvoiceID, payment.InvoiceNumber,
+				payment.ClientName, payment.Amount.String(), csvTime(&payment.PaymentDate),
+				payment.DiscountAmount.String(), csvTime(&payment.CreatedAt), csvTime(&payment.UpdatedAt)})
+		}
+
+	case "credit_notes":
+		creditNotes, err := s.db.ListCreditNotes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(creditNotes, func(i, j int) bool { return creditNotes[i].ID < creditNotes[j].ID })
+		table.records, table.count = creditNotes, len(creditNotes)
+		table.header = []string{"id", "invoice_id", "credit_note_number", "amount", "gst_amount", "reason",
+			"issued_date", "created_at", "updated_at"}
+		for _, creditNote := range creditNotes {
+			table.rows = append(table.rows, []string{creditNote.ID, creditNote.InvoiceID,
+				creditNote.CreditNoteNumber, creditNote.Amount.String(), creditNote.GstAmount.String(),
+				creditNote.Reason, csvTime(&creditNote.IssuedDate), csvTime(&creditNote.CreatedAt),
+				csvTime(&creditNote.UpdatedAt)})
+		}
+
+	case "expenses":
+		expenses, err := s.db.ListExpenses(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(expenses, func(i, j int) bool { return expenses[i].ID < expenses[j].ID })
+		table.records, table.count = expenses, len(expenses)
+		table.header = []string{"id", "client_id", "invoice_id", "amount", "gst_amount", "expense_date",
+			"reference", "description", "category", "markup_percent", "billable", "draft", "receipt_path",
+			"distance_km", "rate_per_km", "created_at", "updated_at"}
+		for _, expense := range expenses {
+			table.rows = append(table.rows, []string{expense.ID, csvString(expense.ClientID),
+				csvString(expense.InvoiceID), expense.Amount.String(), csvDecimal(expense.GstAmount),
+				csvTime(&expense.ExpenseDate), csvString(expense.Reference), csvString(expense.Description),
+				csvString(expense.Category), csvDecimal(expense.MarkupPercent), strconv.FormatBool(expense.Billable),
+				strconv.FormatBool(expense.Draft), csvString(expense.ReceiptPath), csvDecimal(expense.DistanceKm),
+				csvDecimal(expense.RatePerKm), csvTime(&expense.CreatedAt), csvTime(&expense.UpdatedAt)})
+		}
+
+	case "session_templates":
+		templates, err := s.db.ListSessionTemplates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+		table.records, table.count = templates, len(templates)
+		table.header = []string{"id", "name", "client_id", "client_name", "description", "duration_minutes",
+			"created_at", "updated_at"}
+		for _, template := range templates {
+			table.rows = append(table.rows, []string{template.ID, template.Name, template.ClientID,
+				template.ClientName, csvString(template.Description), csvInt(template.DurationMinutes),
+				csvTime(&template.CreatedAt), csvTime(&template.UpdatedAt)})
+		}
+
+	case "client_rates":
+		clients, err := s.db.ListClients(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var rates []*models.ClientRate
+		for _, client := range clients {
+			history, err := s.db.ListClientRates(ctx, client.ID)
+			if err != nil {
+				return nil, err
+			}
+			rates = append(rates, history...)
+		}
+		sort.Slice(rates, func(i, j int) bool { return rates[i].ID < rates[j].ID })
+		table.records, table.count = rates, len(rates)
+		table.header = []string{"id", "client_id", "previous_rate", "hourly_rate", "effective_date", "created_at"}
+		for _, rate := range rates {
+			table.rows = append(table.rows, []string{rate.ID, rate.ClientID, rate.PreviousRate.String(),
+				rate.HourlyRate.String(), csvTime(&rate.EffectiveDate), csvTime(&rate.CreatedAt)})
+		}
+
+	case "client_contacts":
+		contacts, err := s.db.ListClientContacts(ctx, nil, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		// Sessions in the trash aren't exported, so contacts billed as one are exported as not yet billed
+		sessions, err := s.db.ListRecentSessions(ctx, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		exported := make(map[string]bool, len(sessions))
+		for _, session := range sessions {
+			exported[session.ID] = true
+		}
+		for _, contact := range contacts {
+			if contact.SessionID != nil && !exported[*contact.SessionID] {
+				contact.SessionID = nil
+			}
+		}
+		sort.Slice(contacts, func(i, j int) bool { return contacts[i].ID < contacts[j].ID })
+		table.records, table.count = contacts, len(contacts)
+		table.header = []string{"id", "client_id", "client_name", "contact_type", "note", "contacted_at",
+			"duration_minutes", "session_id", "created_at", "updated_at"}
+		for _, contact := range contacts {
+			table.rows = append(table.rows, []string{contact.ID, contact.ClientID, contact.ClientName, contact.Type,
+				contact.Note, csvTime(&contact.ContactedAt), csvInt(contact.DurationMinutes), csvString(contact.SessionID),
+				csvTime(&contact.CreatedAt), csvTime(&contact.UpdatedAt)})
+		}
+
+	case "expense_rules":
+		rules, err := s.db.ListExpenseRules(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+		table.records, table.count = rules, len(rules)
+		table.header = []string{"id", "pattern", "client_id", "client_name", "category", "billable", "skip", "created_at"}
+		for _, rule := range rules {
+			table.rows = append(table.rows, []string{rule.ID, rule.Pattern, csvString(rule.ClientID),
+				csvString(rule.ClientName), csvString(rule.Category), strconv.FormatBool(rule.Billable),
+				strconv.FormatBool(rule.Skip), csvTime(&rule.CreatedAt)})
+		}
+
+	case "milestones":
+		milestones, err := s.db.ListMilestones(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(milestones, func(i, j int) bool { return milestones[i].ID < milestones[j].ID })
+		table.records, table.count = milestones, len(milestones)
+		table.header = []string{"id", "client_id", "client_name", "name", "amount", "due_date", "status",
+			"completed_date", "invoice_id", "created_at", "updated_at"}
+		for _, milestone := range milestones {
+			table.rows = append(table.rows, []string{milestone.ID, milestone.ClientID, milestone.ClientName,
+				milestone.Name, milestone.Amount.String(), csvTime(milestone.DueDate), milestone.Status,
+				csvTime(milestone.CompletedDate), csvString(milestone.InvoiceID), csvTime(&milestone.CreatedAt),
+				csvTime(&milestone.UpdatedAt)})
+		}
+
+	case "session_repos":
+		sessions, err := s.db.ListRecentSessions(ctx, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		var repos []*models.SessionRepo
+		for _, session := range sessions {
+			sessionRepos, err := s.db.ListSessionRepos(ctx, session.ID)
+			if err != nil {
+				return nil, err
+			}
+			repos = append(repos, sessionRepos...)
+		}
+		sort.Slice(repos, func(i, j int) bool {
+			if repos[i].SessionID != repos[j].SessionID {
+				return repos[i].SessionID < repos[j].SessionID
+			}
+			return repos[i].RepoPath < repos[j].RepoPath
+		})
+		table.records, table.count = repos, len(repos)
+		table.header = []string{"session_id", "repo_path", "commit_count", "created_at"}
+		for _, repo := range repos {
+			table.rows = append(table.rows, []string{repo.SessionID, repo.RepoPath, strconv.FormatInt(repo.CommitCount, 10),
+				csvTime(&repo.CreatedAt)})
+		}
+
+	case "leave":
+		leave, err := s.db.ListLeave(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(leave, func(i, j int) bool { return leave[i].ID < leave[j].ID })
+		table.records, table.count = leave, len(leave)
+		table.header = []string{"id", "leave_type", "start_date", "end_date", "note", "created_at", "updated_at"}
+		for _, days := range leave {
+			table.rows = append(table.rows, []string{days.ID, days.Type, csvTime(&days.StartDate), csvTime(&days.EndDate),
+				csvString(days.Note), csvTime(&days.CreatedAt), csvTime(&days.UpdatedAt)})
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown export %q, expected one of: %s", entity, strings.Join(ExportEntities, ", "))
+	}
+
+	return table, nil
+}
+
+func (t *exportTable) writeFile(fileName, format string) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", fileName, err)
+	}
+	defer file.Close()
+
+	if err := t.write(file, format); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func (t *exportTable) write(w io.Writer, format string) error {
+	if format == ExportFormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		records := t.records
+		if t.count == 0 {
+			records = []any{}
+		}
+		if err := encoder.Encode(records); err != nil {
+			return fmt.Errorf("failed to write %s: %w", t.entity, err)
+		}
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(t.header); err != nil {
+		return fmt.Errorf("failed to write %s header: %w", t.entity, err)
+	}
+	if err := writer.WriteAll(t.rows); err != nil {
+		return fmt.Errorf("failed to write %s: %w", t.entity, err)
+	}
+	return nil
+}
+
+func csvString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func csvDecimal(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
+
+func csvFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
+func csvInt(i *int64) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.FormatInt(*i, 10)
+}
+
+// csvTime writes times in RFC 3339 so they read back exactly
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// gitAnalysisDepth is how much git history the AI is given to describe a client's sessions
+type gitAnalysisDepth struct {
+	maxCommits int      // most recent commits per repository, 0 for all of them
+	ignore     []string // paths whose changes are left out, matched like .gitignore
+	detail     string   // config.GitAnalysisDiffs or config.GitAnalysisMessages
+}
+
+// limited reports whether the history is cut down at all. When it isn't the AI reads git itself, as it always
+// has, and otherwise the history is read here and given to it so the limits hold.
+func (d gitAnalysisDepth) limited() bool {
+	return d.maxCommits > 0 || len(d.ignore) > 0 || d.detail == config.GitAnalysisMessages
+}
+
+// String describes the depth for git check, e.g. "the 20 most recent commits, messages only, ignoring vendor/"
+func (d gitAnalysisDepth) String() string {
+	commits := "every commit"
+	if d.maxCommits > 0 {
+		commits = fmt.Sprintf("the %d most recent commits", d.maxCommits)
+	}
+	detail := "with diffs"
+	if d.detail == config.GitAnalysisMessages {
+		detail = "messages only"
+	}
+	description := commits + ", " + detail
+	if len(d.ignore) > 0 {
+		description += ", ignoring " + strings.Join(d.ignore, ", ")
+	}
+	return description
+}
+
+// gitAnalysisDepth returns the client's analysis depth, each setting falling back to GIT_ANALYSIS_MAX_COMMITS,
+// GIT_ANALYSIS_IGNORE and GIT_ANALYSIS_DETAIL when the client doesn't have its own
+func (s *TimesheetService) gitAnalysisDepth(client *models.Client) gitAnalysisDepth {
+	depth := gitAnalysisDepth{maxCommits: s.cfg.AnalysisMaxCommits, ignore: s.cfg.AnalysisIgnore, detail: s.cfg.AnalysisDetail}
+	if client.AnalysisMaxCommits != nil {
+		depth.maxCommits = int(*client.AnalysisMaxCommits)
+	}
+	if client.AnalysisIgnore != nil {
+		depth.ignore = config.ParseIgnorePatterns(*client.AnalysisIgnore)
+	}
+	if client.AnalysisDetail != nil {
+		depth.detail = *client.AnalysisDetail
+	}
+	return depth
+}
+
+// clientAnalysisPrompt is the prompt the client's sessions are described from git with: their own, falling back
+// to GIT_ANALYSIS_PROMPT
+func (s *TimesheetService) clientAnalysisPrompt(client *models.Client) string {
+	if client.AnalysisPrompt != nil && strings.TrimSpace(*client.AnalysisPrompt) != "" {
+		return *client.AnalysisPrompt
+	}
+	return s.cfg.GitAnalysisPrompt
+}
+
+// gitAnalysisPrompt is the prompt for analyzing a client's commits in a repository between two times, and whether
+// there's anything to analyze. When the client's depth is limited the history is read here and added to the
+// prompt, so when there are no commits to give it the AI doesn't need to be run at all.
+func (s *TimesheetService) gitAnalysisPrompt(ctx context.Context, client *models.Client, repoDir string, fromDate, toDate time.Time) (string, bool, error) {
+	prompt := strings.ReplaceAll(s.clientAnalysisPrompt(client), "{from_date}", fromDate.Format("2006-01-02 15:04"))
+	prompt = strings.ReplaceAll(prompt, "{to_date}", toDate.Format("2006-01-02 15:04"))
+	depth := s.gitAnalysisDepth(client)
+	if !depth.limited() {
+		return prompt, true, nil
+	}
+
+	history, err := readGitHistory(ctx, repoDir, fromDate, toDate, depth)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read git history: %w", err)
+	}
+	if strings.TrimSpace(history) == "" {
+		return prompt, false, nil
+	}
+
+	var header strings.Builder
+	header.WriteString("The commits have already been read from git below, so use only them and don't run git or read any files.")
+	if depth.maxCommits > 0 {
+		fmt.Fprintf(&header, " Only the %d most recent commits are included.", depth.maxCommits)
+	}
+	if depth.detail == config.GitAnalysisMessages {
+		header.WriteString(" Only commit messages are included, so describe the work from them.")
+	}
+	return prompt + "\n\n" + header.String() + "\n\n" + history, true, nil
+}
+
+// readGitHistory reads the commits in a repository between two times on any branch, leaving out merges as
+// countCommits does, cut down to the analysis depth
+func readGitHistory(ctx context.Context, repoDir string, fromDate, toDate time.Time, depth gitAnalysisDepth) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", gitHistoryArgs(repoDir, fromDate, toDate, depth)...).Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// gitHistoryArgs are the git arguments readGitHistory runs
+func gitHistoryArgs(repoDir string, fromDate, toDate time.Time, depth gitAnalysisDepth) []string {
+	args := []string{"-C", repoDir, "log", "--all", "--no-merges", "--date=iso",
+		"--since=" + fromDate.Format(time.RFC3339), "--until=" + toDate.Format(time.RFC3339),
+		"--format=commit %h%nDate: %ad%n%n%w(0,4,4)%B"}
+	if depth.maxCommits > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(depth.maxCommits))
+	}
+	if depth.detail != config.GitAnalysisMessages {
+		args = append(args, "--stat", "--patch")
+	}
+	if len(depth.ignore) > 0 {
+		args = append(args, "--")
+		for _, pattern := range depth.ignore {
+			args = append(args, ignorePathspec(pattern))
+		}
+	}
+	return args
+}
+
+// ignorePathspec turns a path matched like .gitignore into a git pathspec excluding it: a name without a slash,
+// such as *.lock, matches at any depth, one with a slash before its end is from the repository root, and a
+// trailing slash matches everything in a directory, so vendor/ leaves out every vendor directory
+func ignorePathspec(pattern string) string {
+	pattern = strings.TrimSpace(pattern)
+	dir := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	if dir {
+		pattern += "/**"
+	}
+	return ":(exclude,glob)" + pattern
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+func TestGitAnalysisDepthFallsBackToConfig(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{AnalysisMaxCommits: 50, AnalysisIgnore: []string{"vendor/"}, AnalysisDetail: config.GitAnalysisDiffs})
+	maxCommits, noIgnore, messages := int64(0), "", config.GitAnalysisMessages
+
+	depth := s.gitAnalysisDepth(&models.Client{})
+	if depth.maxCommits != 50 || len(depth.ignore) != 1 || depth.detail != config.GitAnalysisDiffs {
+		t.Errorf("depth without overrides = %+v, want the config's", depth)
+	}
+	depth = s.gitAnalysisDepth(&models.Client{AnalysisMaxCommits: &maxCommits, AnalysisIgnore: &noIgnore, AnalysisDetail: &messages})
+	if depth.maxCommits != 0 || len(depth.ignore) != 0 || depth.detail != config.GitAnalysisMessages {
+		t.Errorf("depth with overrides = %+v, want every commit, nothing ignored and messages only", depth)
+	}
+	if !depth.limited() {
+		t.Errorf("messages only depth isn't limited")
+	}
+}
+
+func TestIgnorePathspec(t *testing.T) {
+	tests := map[string]string{
+		"vendor/":          ":(exclude,glob)**/vendor/**",
+		"*.lock":           ":(exclude,glob)**/*.lock",
+		"web/dist/":        ":(exclude,glob)web/dist/**",
+		"/package.json":    ":(exclude,glob)package.json",
+		"docs/api/*.json ": ":(exclude,glob)docs/api/*.json",
+	}
+	for pattern, want := range tests {
+		if got := ignorePathspec(pattern); got != want {
+			t.Errorf("ignorePathspec(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+func TestReadGitHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	repo := t.TempDir()
+	git := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	commit := func(when time.Time, path, message string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(repo, path)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, path), []byte(message+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		date := when.Format(time.RFC3339)
+		git(nil, "add", path)
+		git([]string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date}, "commit", "-q", "-m", message)
+	}
+	git(nil, "init", "-q")
+	git(nil, "config", "user.email", "dev@example.com")
+	git(nil, "config", "user.name", "Dev")
+
+	start := time.Date(2025, 11, 3, 9, 0, 0, 0, time.Local)
+	commit(start.Add(10*time.Minute), "app/main.go", "Add the login page")
+	commit(start.Add(20*time.Minute), "vendor/lib/lib.go", "Vendor the auth library")
+	commit(start.Add(30*time.Minute), "go.lock", "Bump dependencies")
+	commit(start.Add(40*time.Minute), "app/auth.go", "Check passwords against the auth library")
+	commit(start.Add(5*time.Hour), "app/late.go", "Commit after the session")
+	end := start.Add(time.Hour)
+
+	ctx := context.Background()
+	history, err := readGitHistory(ctx, repo, start, end, gitAnalysisDepth{ignore: []string{"vendor/", "*.lock"}, detail: config.GitAnalysisMessages})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Add the login page", "Check passwords against the auth library"} {
+		if !strings.Contains(history, want) {
+			t.Errorf("history is missing %q:\n%s", want, history)
+		}
+	}
+	for _, unwanted := range []string{"Vendor the auth library", "Bump dependencies", "Commit after the session", "diff --git"} {
+		if strings.Contains(history, unwanted) {
+			t.Errorf("history has %q, which should have been left out:\n%s", unwanted, history)
+		}
+	}
+
+	history, err = readGitHistory(ctx, repo, start, end, gitAnalysisDepth{maxCommits: 1, detail: config.GitAnalysisDiffs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(history, "Check passwords against the auth library") || strings.Contains(history, "Add the login page") || !strings.Contains(history, "diff --git") {
+		t.Errorf("history with one commit and diffs = %s, want only the latest commit with its diff", history)
+	}
+
+	s := NewTimesheetService(nil, &config.Config{GitAnalysisPrompt: "summarise {from_date} to {to_date}"})
+	messages, formal := config.GitAnalysisMessages, "describe formally from {from_date}"
+	client := &models.Client{AnalysisDetail: &messages}
+	if _, hasCommits, err := s.gitAnalysisPrompt(ctx, client, repo, end, end.Add(time.Hour)); err != nil || hasCommits {
+		t.Errorf("gitAnalysisPrompt without commits in range = %t, %v, want no commits", hasCommits, err)
+	}
+	client.AnalysisPrompt = &formal
+	prompt, hasCommits, err := s.gitAnalysisPrompt(ctx, client, repo, start, end)
+	if err != nil || !hasCommits || !strings.HasPrefix(prompt, "describe formally from 2025-11-03 09:00") || !strings.Contains(prompt, "Add the login page") {
+		t.Errorf("gitAnalysisPrompt with the client's prompt = %q, %t, %v, want it with the history", prompt, hasCommits, err)
+	}
+}
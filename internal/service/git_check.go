@@ -1,193 +1,139 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/jesses-code-adventures/work/internal/utils"
 )
 
 // GitCheckSession performs debugging of git commands for a specific session
-func (s *TimesheetService) GitCheckSession(sessionID string) error {
-	// Use SQLite command to get session and client info together
-	sqlCmd := fmt.Sprintf(`sqlite3 work.db "SELECT s.id, c.name, s.start_time, s.end_time, c.dir FROM sessions s JOIN clients c ON s.client_id = c.id WHERE s.id = '%s';"`, sessionID)
-
-	cmd := exec.Command("sh", "-c", sqlCmd)
-	output, err := cmd.Output()
+func (s *TimesheetService) GitCheckSession(ctx context.Context, sessionID string) error {
+	session, err := s.db.GetSessionByID(ctx, sessionID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("session '%s' not found", sessionID)
+		}
 		return fmt.Errorf("failed to query session '%s': %w", sessionID, err)
 	}
-
-	if len(output) == 0 {
-		return fmt.Errorf("session '%s' not found", sessionID)
-	}
-
-	// Parse the output: id|client_name|start_time|end_time|dir
-	parts := strings.Split(strings.TrimSpace(string(output)), "|")
-	if len(parts) < 5 {
-		return fmt.Errorf("invalid session data returned: %s", string(output))
-	}
-
-	sessionIDResult := parts[0]
-	clientName := parts[1]
-	startTime := parts[2]
-	endTime := parts[3]
-	clientDir := parts[4]
-
-	if endTime == "" {
+	if session.EndTime == nil {
 		return fmt.Errorf("session '%s' is still active (no end time)", sessionID)
 	}
-
-	fmt.Printf("=== GIT CHECK FOR SESSION ===\n")
-	fmt.Printf("Session ID: %s\n", sessionIDResult)
-	fmt.Printf("Client: %s\n", clientName)
-	fmt.Printf("Session Time: %s to %s\n", startTime, endTime)
-
-	// Parse start time to get the date (handle multiple formats)
-	var startTimeParsed time.Time
-	var parseErr error
-
-	// Try different time formats
-	formats := []string{
-		"2006-01-02 15:04:05.000000-07:00",
-		"2006-01-02 15:04:05.000000+07:00",
-		"2006-01-02 15:04:05",
-		"2006-01-02T15:04:05Z",
-	}
-
-	for _, format := range formats {
-		startTimeParsed, parseErr = time.Parse(format, startTime)
-		if parseErr == nil {
-			break
-		}
-	}
-
-	if parseErr != nil {
-		// If all parsing fails, extract just the date part
-		if len(startTime) >= 10 {
-			dateOnly := startTime[:10]
-			startTimeParsed, parseErr = time.Parse("2006-01-02", dateOnly)
-			if parseErr != nil {
-				return fmt.Errorf("failed to parse start time '%s': %w", startTime, parseErr)
-			}
-		} else {
-			return fmt.Errorf("failed to parse start time '%s': %w", startTime, parseErr)
-		}
+	client, err := s.db.GetClientByID(ctx, session.ClientID)
+	if err != nil {
+		return fmt.Errorf("failed to get client for session '%s': %w", sessionID, err)
 	}
+	clientDir := utils.FromPtr(client.Dir)
 
-	// Parse end time as well
-	var endTimeParsed time.Time
-	for _, format := range formats {
-		endTimeParsed, parseErr = time.Parse(format, endTime)
-		if parseErr == nil {
-			break
-		}
-	}
-
-	if parseErr != nil {
-		// If all parsing fails, extract just the date part
-		if len(endTime) >= 10 {
-			dateOnly := endTime[:10] + " 23:59:59"
-			endTimeParsed, parseErr = time.Parse("2006-01-02 15:04:05", dateOnly)
-			if parseErr != nil {
-				return fmt.Errorf("failed to parse end time '%s': %w", endTime, parseErr)
-			}
-		} else {
-			return fmt.Errorf("failed to parse end time '%s': %w", endTime, parseErr)
-		}
-	}
+	fmt.Fprintf(s.out, "=== GIT CHECK FOR SESSION ===\n")
+	fmt.Fprintf(s.out, "Session ID: %s\n", session.ID)
+	fmt.Fprintf(s.out, "Client: %s\n", client.Name)
+	fmt.Fprintf(s.out, "Session Time: %s to %s\n", session.StartTime.Format("2006-01-02 15:04:05"), session.EndTime.Format("2006-01-02 15:04:05"))
 
 	// Use session start and end times for precise git analysis
-	fromDateTime := startTimeParsed.Format("2006-01-02 15:04")
-	toDateTime := endTimeParsed.Format("2006-01-02 15:04")
+	fromDateTime := session.StartTime.Format("2006-01-02 15:04")
+	toDateTime := session.EndTime.Format("2006-01-02 15:04")
 
-	fmt.Printf("Git Time Range: %s to %s\n", fromDateTime, toDateTime)
+	fmt.Fprintf(s.out, "Git Time Range: %s to %s\n", fromDateTime, toDateTime)
 
 	// Process the directory
 	dir := strings.TrimSpace(clientDir)
-	fmt.Printf("Client Directory (raw): '%s'\n", clientDir)
-	fmt.Printf("Client Directory (trimmed): '%s'\n", dir)
+	fmt.Fprintf(s.out, "Client Directory (raw): '%s'\n", clientDir)
+	fmt.Fprintf(s.out, "Client Directory (trimmed): '%s'\n", dir)
 
 	// Expand tilde
-	if strings.HasPrefix(dir, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("error getting home directory: %w", err)
-		}
-		expandedDir := filepath.Join(homeDir, dir[2:])
-		fmt.Printf("Directory (expanded): %s\n", expandedDir)
-		dir = expandedDir
+	dir, err = expandHomeDir(dir)
+	if err != nil {
+		return err
 	}
+	fmt.Fprintf(s.out, "Directory (expanded): %s\n", dir)
 
 	// Check if directory exists
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
 		return fmt.Errorf("directory does not exist: %s", dir)
 	}
 
-	fmt.Printf("Directory exists: ✓\n")
+	fmt.Fprintf(s.out, "Directory exists: ✓\n")
 
 	// Find git repositories
-	fmt.Printf("\n=== FINDING GIT REPOSITORIES ===\n")
+	fmt.Fprintf(s.out, "\n=== FINDING GIT REPOSITORIES ===\n")
 	gitRepos := s.findGitRepositoriesDebug(dir)
 
 	if len(gitRepos) == 0 {
-		fmt.Printf("No git repositories found in %s\n", dir)
+		fmt.Fprintf(s.out, "No git repositories found in %s\n", dir)
 		return nil
 	}
 
-	fmt.Printf("Found %d git repositories:\n", len(gitRepos))
+	fmt.Fprintf(s.out, "Found %d git repositories:\n", len(gitRepos))
 	for i, repo := range gitRepos {
-		fmt.Printf("  %d. %s\n", i+1, repo)
+		fmt.Fprintf(s.out, "  %d. %s\n", i+1, repo)
 	}
 
-	// Get the git analysis prompt
-	gitPrompt := s.cfg.GitAnalysisPrompt
-	actualPrompt := strings.ReplaceAll(gitPrompt, "{from_date}", fromDateTime)
-	actualPrompt = strings.ReplaceAll(actualPrompt, "{to_date}", toDateTime)
-
-	fmt.Printf("\n=== GIT ANALYSIS PROMPT ===\n")
-	fmt.Printf("%s\n", actualPrompt)
+	depth := s.gitAnalysisDepth(client)
+	if client.AnalysisPrompt != nil {
+		fmt.Fprintf(s.out, "\nUsing %s's own analysis prompt instead of GIT_ANALYSIS_PROMPT\n", client.Name)
+	}
+	fmt.Fprintf(s.out, "\n=== GIT ANALYSIS DEPTH ===\n")
+	fmt.Fprintf(s.out, "%s\n", depth)
+	if depth.limited() {
+		fmt.Fprintf(s.out, "The history is read by work and given to the AI in the prompt\n")
+	}
 
 	// Process each repository
 	for i, repoDir := range gitRepos {
-		fmt.Printf("\n=== REPOSITORY %d: %s ===\n", i+1, filepath.Base(repoDir))
-		fmt.Printf("Full path: %s\n", repoDir)
+		fmt.Fprintf(s.out, "\n=== REPOSITORY %d: %s ===\n", i+1, filepath.Base(repoDir))
+		fmt.Fprintf(s.out, "Full path: %s\n", repoDir)
 
 		// Check if it's actually a git repository
 		gitDir := filepath.Join(repoDir, ".git")
 		if _, err := os.Stat(gitDir); os.IsNotExist(err) {
-			fmt.Printf("❌ Not a git repository (no .git directory)\n")
+			fmt.Fprintf(s.out, "❌ Not a git repository (no .git directory)\n")
 			continue
 		}
-		fmt.Printf("✓ Valid git repository\n")
+		fmt.Fprintf(s.out, "✓ Valid git repository\n")
 
 		// Run basic git commands to show repository state
-		fmt.Printf("\n--- Git Status ---\n")
+		fmt.Fprintf(s.out, "\n--- Git Status ---\n")
 		s.runGitCommand(repoDir, "git", "status", "--porcelain")
 
-		fmt.Printf("\n--- Git Log for Time Range ---\n")
+		fmt.Fprintf(s.out, "\n--- Git Log for Time Range ---\n")
 		logCmd := fmt.Sprintf("git log --since=\"%s\" --until=\"%s\" --oneline", fromDateTime, toDateTime)
-		fmt.Printf("Command: %s\n", logCmd)
+		fmt.Fprintf(s.out, "Command: %s\n", logCmd)
 		s.runGitCommand(repoDir, "git", "log", fmt.Sprintf("--since=%s", fromDateTime), fmt.Sprintf("--until=%s", toDateTime), "--oneline")
 
-		fmt.Printf("\n--- Git Log with Details ---\n")
+		fmt.Fprintf(s.out, "\n--- Git Log with Details ---\n")
 		s.runGitCommand(repoDir, "git", "log", fmt.Sprintf("--since=%s", fromDateTime), fmt.Sprintf("--until=%s", toDateTime), "--stat")
 
-		fmt.Printf("\n--- Recent Git Log (last 5 commits) ---\n")
+		fmt.Fprintf(s.out, "\n--- Recent Git Log (last 5 commits) ---\n")
 		s.runGitCommand(repoDir, "git", "log", "--oneline", "-5")
 
-		fmt.Printf("\n--- Recent Git Log with Timestamps ---\n")
+		fmt.Fprintf(s.out, "\n--- Recent Git Log with Timestamps ---\n")
 		s.runGitCommand(repoDir, "git", "log", "--pretty=format:%h %cd %s", "--date=iso", "-5")
 
 		// Test the actual opencode command that would be run
-		fmt.Printf("\n--- Testing OpenCode Command ---\n")
-		fmt.Printf("Would run in directory: %s\n", repoDir)
-		fmt.Printf("Command: cd %s && echo '%s' | opencode run\n", repoDir, actualPrompt)
+		fmt.Fprintf(s.out, "\n--- Testing OpenCode Command ---\n")
+		fmt.Fprintf(s.out, "Would run in directory: %s\n", repoDir)
+		actualPrompt, hasCommits, err := s.gitAnalysisPrompt(ctx, client, repoDir, session.StartTime, *session.EndTime)
+		if err != nil {
+			fmt.Fprintf(s.out, "❌ %v\n", err)
+			continue
+		}
+		fmt.Fprintf(s.out, "\n--- Git Analysis Prompt ---\n")
+		fmt.Fprintf(s.out, "%s\n", actualPrompt)
+		if !hasCommits {
+			fmt.Fprintf(s.out, "No commits within the analysis depth, so OpenCode wouldn't be run\n")
+			continue
+		}
 
 		// Actually run the opencode command to see what happens
-		fmt.Printf("\n--- OpenCode Output ---\n")
+		fmt.Fprintf(s.out, "\n--- OpenCode Output ---\n")
 		s.runOpenCodeCommand(repoDir, actualPrompt)
 	}
 
@@ -197,36 +143,28 @@ func (s *TimesheetService) GitCheckSession(sessionID string) error {
 func (s *TimesheetService) findGitRepositoriesDebug(root string) []string {
 	var gitRepos []string
 
-	fmt.Printf("Searching for git repositories in: %s\n", root)
+	fmt.Fprintf(s.out, "Searching for git repositories in: %s\n", root)
 
-	// Try the find command first (like the original code does)
-	fmt.Printf("Running find command...\n")
-	cmd := exec.Command("find", root, "-type", "d", "-name", ".git", "-mtime", "-30", "-maxdepth", "3")
-	fmt.Printf("Command: %s\n", strings.Join(cmd.Args, " "))
-
-	output, err := cmd.Output()
-	if err != nil {
-		fmt.Printf("Find command failed: %v\n", err)
-		fmt.Printf("Falling back to directory walk...\n")
-		return s.findGitRepositoriesWalkDebug(root)
-	}
-
-	fmt.Printf("Find command output:\n%s\n", string(output))
-
-	// Parse find output to get repository directories
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line != "" {
-			// Get the parent directory (the actual repository directory)
-			repoDir := filepath.Dir(line)
-			gitRepos = append(gitRepos, repoDir)
-			fmt.Printf("Found git repo: %s\n", repoDir)
+	// Look for recently changed repositories first, like the description generator does
+	fmt.Fprintf(s.out, "Looking for .git directories changed in the last %d days...\n", int(recentRepositoryAge.Hours()/24))
+	cutoff := time.Now().Add(-recentRepositoryAge)
+	for _, repoDir := range gitRepositoriesUnder(root, 3) {
+		info, err := os.Stat(filepath.Join(repoDir, ".git"))
+		if err != nil {
+			fmt.Fprintf(s.out, "Failed to check %s: %v\n", repoDir, err)
+			continue
+		}
+		if !info.ModTime().After(cutoff) {
+			fmt.Fprintf(s.out, "Skipping %s, last changed %s\n", repoDir, info.ModTime().Format("2006-01-02"))
+			continue
 		}
+		gitRepos = append(gitRepos, repoDir)
+		fmt.Fprintf(s.out, "Found git repo: %s\n", repoDir)
 	}
 
 	// If no recently modified repos found, check for repos with recent commits
 	if len(gitRepos) == 0 {
-		fmt.Printf("No recently modified .git directories found, checking for repos with recent commits...\n")
+		fmt.Fprintf(s.out, "No recently modified .git directories found, checking for repos with recent commits...\n")
 		return s.findGitRepositoriesWithRecentCommitsDebug(root)
 	}
 
@@ -234,13 +172,13 @@ func (s *TimesheetService) findGitRepositoriesDebug(root string) []string {
 }
 
 func (s *TimesheetService) findGitRepositoriesWalkDebug(root string) []string {
-	fmt.Printf("Walking directory tree...\n")
+	fmt.Fprintf(s.out, "Walking directory tree...\n")
 	var gitRepos []string
 	maxDepth := 2
 
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			fmt.Printf("Walk error at %s: %v\n", path, err)
+			fmt.Fprintf(s.out, "Walk error at %s: %v\n", path, err)
 			return nil
 		}
 
@@ -249,7 +187,7 @@ func (s *TimesheetService) findGitRepositoriesWalkDebug(root string) []string {
 
 		if depth > maxDepth {
 			if info.IsDir() {
-				fmt.Printf("Skipping deep directory: %s (depth %d)\n", path, depth)
+				fmt.Fprintf(s.out, "Skipping deep directory: %s (depth %d)\n", path, depth)
 				return filepath.SkipDir
 			}
 			return nil
@@ -259,7 +197,7 @@ func (s *TimesheetService) findGitRepositoriesWalkDebug(root string) []string {
 		if info.IsDir() && info.Name() == ".git" {
 			repoDir := filepath.Dir(path)
 			gitRepos = append(gitRepos, repoDir)
-			fmt.Printf("Found git repo (walk): %s\n", repoDir)
+			fmt.Fprintf(s.out, "Found git repo (walk): %s\n", repoDir)
 			return filepath.SkipDir
 		}
 
@@ -270,7 +208,7 @@ func (s *TimesheetService) findGitRepositoriesWalkDebug(root string) []string {
 }
 
 func (s *TimesheetService) findGitRepositoriesWithRecentCommitsDebug(root string) []string {
-	fmt.Printf("Checking for repositories with recent commits...\n")
+	fmt.Fprintf(s.out, "Checking for repositories with recent commits...\n")
 	var gitRepos []string
 	maxDepth := 2
 
@@ -294,15 +232,15 @@ func (s *TimesheetService) findGitRepositoriesWithRecentCommitsDebug(root string
 			repoDir := filepath.Dir(path)
 
 			// Check if this repo has commits in the last week
-			fmt.Printf("Checking recent commits in: %s\n", repoDir)
+			fmt.Fprintf(s.out, "Checking recent commits in: %s\n", repoDir)
 			cmd := exec.Command("git", "-C", repoDir, "log", "--since=1 week ago", "--oneline", "-n", "1")
 			output, err := cmd.Output()
 			if err == nil && len(strings.TrimSpace(string(output))) > 0 {
 				gitRepos = append(gitRepos, repoDir)
-				fmt.Printf("Found repo with recent commits: %s\n", repoDir)
-				fmt.Printf("Recent commit: %s\n", strings.TrimSpace(string(output)))
+				fmt.Fprintf(s.out, "Found repo with recent commits: %s\n", repoDir)
+				fmt.Fprintf(s.out, "Recent commit: %s\n", strings.TrimSpace(string(output)))
 			} else {
-				fmt.Printf("No recent commits in: %s\n", repoDir)
+				fmt.Fprintf(s.out, "No recent commits in: %s\n", repoDir)
 			}
 
 			return filepath.SkipDir
@@ -318,41 +256,35 @@ func (s *TimesheetService) runGitCommand(repoDir string, args ...string) {
 	cmd := exec.Command(args[0], args[1:]...)
 	cmd.Dir = repoDir
 
-	fmt.Printf("Running: %s (in %s)\n", strings.Join(args, " "), repoDir)
+	fmt.Fprintf(s.out, "Running: %s (in %s)\n", strings.Join(args, " "), repoDir)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Printf("❌ Command failed: %v\n", err)
+		fmt.Fprintf(s.out, "❌ Command failed: %v\n", err)
 		if len(output) > 0 {
-			fmt.Printf("Output: %s\n", string(output))
+			fmt.Fprintf(s.out, "Output: %s\n", string(output))
 		}
 	} else {
 		if len(output) > 0 {
-			fmt.Printf("Output:\n%s\n", string(output))
+			fmt.Fprintf(s.out, "Output:\n%s\n", string(output))
 		} else {
-			fmt.Printf("(no output)\n")
+			fmt.Fprintf(s.out, "(no output)\n")
 		}
 	}
 }
 
 func (s *TimesheetService) runOpenCodeCommand(repoDir, prompt string) {
-	// Create the shell command to cd into repository directory and run opencode
-	shellCmd := fmt.Sprintf("cd %s && echo %s | opencode run",
-		s.shellescape(repoDir),
-		s.shellescape(prompt))
-
-	fmt.Printf("Shell command: %s\n", shellCmd)
-
-	cmd := exec.Command("sh", "-c", shellCmd)
+	cmd := opencodeCommand(context.Background(), repoDir, prompt)
+	fmt.Fprintf(s.out, "Command: %s (in %s, prompt on stdin)\n", strings.Join(cmd.Args, " "), repoDir)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Printf("❌ OpenCode command failed: %v\n", err)
+		fmt.Fprintf(s.out, "❌ OpenCode command failed: %v\n", err)
 	}
 
 	if len(output) > 0 {
-		fmt.Printf("OpenCode output:\n%s\n", string(output))
+		fmt.Fprintf(s.out, "OpenCode output:\n%s\n", string(output))
 	} else {
-		fmt.Printf("(no opencode output)\n")
+		fmt.Fprintf(s.out, "(no opencode output)\n")
 	}
 }
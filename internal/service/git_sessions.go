@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// DefaultGitSessionGap is the longest gap between commits for them to be proposed as one session
+const DefaultGitSessionGap = time.Hour
+
+// GitSessionProposal is the sessions proposed for a client from the commits made outside any tracked session
+type GitSessionProposal struct {
+	Client   *models.Client
+	From, To time.Time
+	Sessions []ProposedSession
+	// Tracked counts commits already covered by a session, which aren't proposed again
+	Tracked int
+}
+
+// ProposedSession is a stretch of commits to log as a session, starting auditBurstLead before the first
+// commit and ending at the last
+type ProposedSession struct {
+	Start       time.Time
+	End         time.Time
+	Commits     int
+	Description string
+	// Overlaps is the session that the proposal would overlap, when it can't be created
+	Overlaps *models.WorkSession
+}
+
+// ProposeSessionsFromGit clusters the commits you made in a client's repositories between from and to into
+// proposed sessions, with a new session wherever there's more than gap between one commit and the next.
+// Commits made in or near a tracked session are left out, as in the coverage audit, and a proposal that would
+// overlap one of your sessions for any client starts after it or is marked as overlapping.
+func (s *TimesheetService) ProposeSessionsFromGit(ctx context.Context, clientName string, from, to time.Time, gap time.Duration) (*GitSessionProposal, error) {
+	if gap <= 0 {
+		return nil, fmt.Errorf("gap must be positive")
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("--to must be after --from")
+	}
+	client, err := s.db.GetClientByName(ctx, clientName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("client '%s' does not exist", clientName)
+		}
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+	if utils.FromPtr(client.Dir) == "" {
+		return nil, fmt.Errorf("client '%s' has no directory configured, set one with 'work clients update %s --dir <dir>'", client.Name, client.Name)
+	}
+
+	commits, err := s.clientAuditCommits(ctx, client, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// Sessions either side of the range can cover commits near its ends
+	sessions, err := s.ListSessionsWithDateRange(ctx, from.AddDate(0, 0, -1).Format("2006-01-02"), to.AddDate(0, 0, 1).Format("2006-01-02"), 100000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	var own, clientSessions []*models.WorkSession
+	for _, session := range sessions {
+		if session.UserID != nil {
+			continue
+		}
+		own = append(own, session)
+		if session.ClientID == client.ID {
+			clientSessions = append(clientSessions, session)
+		}
+	}
+
+	proposal := &GitSessionProposal{Client: client, From: from, To: to}
+	var untracked []auditCommit
+	for _, commit := range commits {
+		if sessionAt(clientSessions, commit.at) != nil {
+			proposal.Tracked++
+			continue
+		}
+		untracked = append(untracked, commit)
+	}
+
+	for _, burst := range commitBursts(untracked, gap) {
+		first, last := burst[0].at, burst[len(burst)-1].at
+		proposed := ProposedSession{
+			Start:       first.Add(-auditBurstLead),
+			End:         last,
+			Commits:     len(burst),
+			Description: commitSubjects(burst),
+		}
+		// Work done straight after another session starts when it ended, rather than overlapping it
+		var before *models.WorkSession
+		for _, session := range own {
+			if end := sessionEnd(session); end.After(proposed.Start) && !end.After(first) {
+				proposed.Start, before = end, session
+			}
+		}
+		for _, session := range own {
+			if session.StartTime.Before(proposed.End) && sessionEnd(session).After(proposed.Start) {
+				proposed.Overlaps = session
+				break
+			}
+		}
+		if !proposed.End.After(proposed.Start) {
+			proposed.Overlaps = before
+		}
+		proposal.Sessions = append(proposal.Sessions, proposed)
+	}
+	return proposal, nil
+}
+
+// DisplayGitSessionProposal shows the sessions that would be created from git history
+func (s *TimesheetService) DisplayGitSessionProposal(proposal *GitSessionProposal) {
+	fmt.Fprintf(s.out, "Commits for %s from %s to %s", proposal.Client.Name, proposal.From.Format("2006-01-02"), proposal.To.Format("2006-01-02"))
+	if proposal.Tracked > 0 {
+		fmt.Fprintf(s.out, ", leaving out %d already in a session", proposal.Tracked)
+	}
+	fmt.Fprintln(s.out)
+	if len(proposal.Sessions) == 0 {
+		fmt.Fprintln(s.out, "No untracked commits, nothing to create")
+		return
+	}
+
+	var total time.Duration
+	fmt.Fprintf(s.out, "\nProposed sessions:\n")
+	for _, proposed := range proposal.Sessions {
+		duration := proposed.End.Sub(proposed.Start)
+		fmt.Fprintf(s.out, "  %s - %s (%s), %d commits: %s\n", proposed.Start.Format("2006-01-02 15:04"), proposed.End.Format("15:04"),
+			s.FormatDurationFor(config.DurationContextList, duration), proposed.Commits, truncateString(proposed.Description, 60))
+		if proposed.Overlaps != nil {
+			fmt.Fprintf(s.out, "    skipped, overlaps the %s session %s\n", proposed.Overlaps.ClientName, proposed.Overlaps.ID)
+			continue
+		}
+		total += duration
+	}
+	fmt.Fprintf(s.out, "Total: %s\n", s.FormatDurationFor(config.DurationContextList, total))
+}
+
+// CreateSessionsFromGit creates the proposed sessions that don't overlap an existing one, described by the
+// subjects of their commits
+func (s *TimesheetService) CreateSessionsFromGit(ctx context.Context, proposal *GitSessionProposal) ([]*models.WorkSession, error) {
+	var created []*models.WorkSession
+	for _, proposed := range proposal.Sessions {
+		if proposed.Overlaps != nil {
+			continue
+		}
+		description := proposed.Description
+		session, err := s.CreateSessionWithTimes(ctx, proposal.Client.Name, proposed.Start, proposed.End, &description, false)
+		if err != nil {
+			return created, err
+		}
+		created = append(created, session)
+	}
+	return created, nil
+}
+
+// commitSubjects describes commits by their subjects, each listed once, e.g. "Add login endpoint; Fix tests"
+func commitSubjects(commits []auditCommit) string {
+	seen := make(map[string]bool)
+	var subjects []string
+	for _, commit := range commits {
+		if seen[strings.ToLower(commit.subject)] {
+			continue
+		}
+		seen[strings.ToLower(commit.subject)] = true
+		subjects = append(subjects, commit.subject)
+	}
+	return strings.Join(subjects, "; ")
+}
+
+// sessionEnd is when a session ended, or now while it's active
+func sessionEnd(session *models.WorkSession) time.Time {
+	if session.EndTime != nil {
+		return *session.EndTime
+	}
+	return time.Now()
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+var (
+	gstRate = decimal.New(1, -1) // 10%
+	// gstFraction is the share of a GST inclusive amount that is GST, one eleventh at 10%
+	gstFraction = gstRate.Div(gstRate.Add(decimal.NewFromInt(1)))
+)
+
+// TaxTreatments are the ways GST can apply to a client's invoices
+var TaxTreatments = []string{models.TaxTreatmentTaxable, models.TaxTreatmentGSTFree, models.TaxTreatmentExport}
+
+// ValidateTaxTreatment returns an error if treatment is not one of TaxTreatments
+func ValidateTaxTreatment(treatment string) error {
+	for _, known := range TaxTreatments {
+		if treatment == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported tax treatment %q, expected one of: %s", treatment, strings.Join(TaxTreatments, ", "))
+}
+
+// chargesGST reports whether a client's invoices have GST added, which needs GST registration and a taxable
+// client. Amounts without a client are treated as taxable.
+func (s *TimesheetService) chargesGST(client *models.Client) bool {
+	if !s.cfg.GSTRegistered {
+		return false
+	}
+	return client == nil || client.TaxTreatment == "" || client.TaxTreatment == models.TaxTreatmentTaxable
+}
+
+// gstFreeLabel says why a GST-free or export client's invoices don't charge GST, e.g. "export, GST-free". It's
+// empty for clients charged GST, and for every client when not registered for GST.
+func (s *TimesheetService) gstFreeLabel(client *models.Client) string {
+	if !s.cfg.GSTRegistered || client == nil {
+		return ""
+	}
+	switch client.TaxTreatment {
+	case models.TaxTreatmentGSTFree:
+		return "GST-free"
+	case models.TaxTreatmentExport:
+		return "export, GST-free"
+	}
+	return ""
+}
+
+// gstLine is one billed amount on an invoice: a session, the retainer, an expense or a milestone
+type gstLine struct {
+	amount      decimal.Decimal
+	includesGst bool
+}
+
+// invoiceTotals are the rounded amounts shown on an invoice. Sessions, retainer, expenses and milestones
+// exclude GST and add up to subtotal, and subtotal plus gst plus any totalAdjustment is total.
+type invoiceTotals struct {
+	sessions   decimal.Decimal
+	retainer   decimal.Decimal
+	expenses   decimal.Decimal
+	milestones decimal.Decimal
+	subtotal   decimal.Decimal
+	gst        decimal.Decimal
+	total      decimal.Decimal
+	// gstAdjustment is how far per line GST differs from GST on the subtotal, shown as a rounding line
+	gstAdjustment decimal.Decimal
+	// totalAdjustment is what was added to the total (or taken off, when negative) to round it to the client's
+	// invoice rounding, shown as its own line
+	totalAdjustment decimal.Decimal
+}
+
+// roundTotal rounds the total to the nearest multiple of rounding, an invoice's or its client's invoice rounding,
+// halves rounding up, keeping the difference as totalAdjustment. Without rounding the total is left to the cent.
+func (t *invoiceTotals) roundTotal(rounding *decimal.Decimal) {
+	if rounding == nil || !rounding.IsPositive() {
+		return
+	}
+	rounded := t.total.Div(*rounding).Round(0).Mul(*rounding).Round(2)
+	t.totalAdjustment = rounded.Sub(t.total)
+	t.total = rounded
+}
+
+// invoiceTotalAdjustment is the rounding adjustment on an issued invoice, whatever its total differs from its
+// subtotal plus GST by
+func invoiceTotalAdjustment(invoice *models.Invoice) decimal.Decimal {
+	return invoice.TotalAmount.Sub(invoice.SubtotalAmount).Sub(invoice.GstAmount)
+}
+
+// calculateInvoiceTotals works out an invoice's amounts to the cent using the configured GST_ROUNDING method.
+// The ATO accepts GST rounded once on the invoice total (the default) or rounded on each line, provided the
+// same method is always used. GST is only added for clients it's charged to.
+func (s *TimesheetService) calculateInvoiceTotals(client *models.Client, sessions []*models.WorkSession, expenses []*models.Expense, milestones []*models.Milestone, retainer retainerTerms) invoiceTotals {
+	chargeGST := s.chargesGST(client)
+	sessionLines := s.sessionGSTLines(sessions, retainer)
+	retainerLines := []gstLine{{amount: retainer.amount}}
+	expenseLines := make([]gstLine, len(expenses))
+	for i, expense := range expenses {
+		expenseLines[i] = gstLine{amount: ExpenseBilledAmount(expense)}
+	}
+	milestoneLines := make([]gstLine, len(milestones))
+	for i, milestone := range milestones {
+		milestoneLines[i] = gstLine{amount: milestone.Amount}
+	}
+
+	var totals invoiceTotals
+	if s.gstRounding() == config.GSTRoundingLine {
+		var sessionsGST, retainerGST, expensesGST, milestonesGST decimal.Decimal
+		totals.sessions, sessionsGST = roundLines(sessionLines, chargeGST)
+		totals.retainer, retainerGST = roundLines(retainerLines, chargeGST)
+		totals.expenses, expensesGST = roundLines(expenseLines, chargeGST)
+		totals.milestones, milestonesGST = roundLines(milestoneLines, chargeGST)
+		totals.subtotal = totals.sessions.Add(totals.retainer).Add(totals.expenses).Add(totals.milestones)
+		totals.gst = sessionsGST.Add(retainerGST).Add(expensesGST).Add(milestonesGST)
+		if chargeGST {
+			totals.gstAdjustment = totals.gst.Sub(totals.subtotal.Mul(gstRate).Round(2))
+		}
+	} else {
+		totals.sessions = exclusiveTotal(sessionLines, chargeGST).Round(2)
+		totals.retainer = exclusiveTotal(retainerLines, chargeGST).Round(2)
+		totals.expenses = exclusiveTotal(expenseLines, chargeGST).Round(2)
+		totals.milestones = exclusiveTotal(milestoneLines, chargeGST).Round(2)
+		totals.subtotal = totals.sessions.Add(totals.retainer).Add(totals.expenses).Add(totals.milestones)
+		if chargeGST {
+			totals.gst = totals.subtotal.Mul(gstRate).Round(2)
+		}
+	}
+	totals.total = totals.subtotal.Add(totals.gst)
+	return totals
+}
+
+// roundLines rounds each line and its GST to the cent, returning their totals excluding GST and the GST. Without
+// chargeGST there's no GST, and GST inclusive lines are billed as they are.
+func roundLines(lines []gstLine, chargeGST bool) (decimal.Decimal, decimal.Decimal) {
+	var exclusive, gst decimal.Decimal
+	for _, line := range lines {
+		amount := line.amount.Round(2)
+		var lineGST decimal.Decimal
+		switch {
+		case !chargeGST:
+		case line.includesGst:
+			lineGST = amount.Mul(gstFraction).Round(2)
+			amount = amount.Sub(lineGST)
+		default:
+			lineGST = amount.Mul(gstRate).Round(2)
+		}
+		exclusive = exclusive.Add(amount)
+		gst = gst.Add(lineGST)
+	}
+	return exclusive, gst
+}
+
+// exclusiveTotal adds up lines without rounding, taking GST out of GST inclusive lines when GST is charged
+func exclusiveTotal(lines []gstLine, chargeGST bool) decimal.Decimal {
+	var total decimal.Decimal
+	for _, line := range lines {
+		if line.includesGst && chargeGST {
+			total = total.Add(line.amount.Sub(line.amount.Mul(gstFraction)))
+		} else {
+			total = total.Add(line.amount)
+		}
+	}
+	return total
+}
+
+func (s *TimesheetService) gstRounding() string {
+	if s.cfg == nil || s.cfg.GSTRounding == "" {
+		return config.GSTRoundingInvoice
+	}
+	return s.cfg.GSTRounding
+}
+
+// sessionGSTLines returns the billed amount of each session, leaving out hours covered by the retainer
+func (s *TimesheetService) sessionGSTLines(sessions []*models.WorkSession, retainer retainerTerms) []gstLine {
+	var lines []gstLine
+	var totalHours decimal.Decimal
+	for _, session := range sessions {
+		sessionHours := durationHours(s.CalculateDuration(session))
+		totalHours = sessionHours.Add(totalHours)
+
+		// Apply retainer hours at $0 rate first
+		if retainer.applies() && totalHours.LessThanOrEqual(retainer.hours) {
+			// Session hours are covered by retainer, bill at $0
+			continue
+		} else if retainer.applies() && (totalHours.Sub(sessionHours)).LessThan(retainer.hours) {
+			// Partial session covered by retainer
+			retainerCoveredHours := retainer.hours.Sub((totalHours.Sub(sessionHours)))
+			billableHours := sessionHours.Sub(retainerCoveredHours)
+
+			if session.HourlyRate != nil && session.HourlyRate.GreaterThan(decimal.Zero) {
+				lines = append(lines, gstLine{amount: billableHours.Mul(*session.HourlyRate).Round(2), includesGst: session.IncludesGst})
+			}
+		} else {
+			// Session fully billable
+			lines = append(lines, gstLine{amount: s.CalculateBillableAmount(session), includesGst: session.IncludesGst})
+		}
+	}
+
+	return lines
+}
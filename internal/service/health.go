@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/database"
+)
+
+// CheckDatabase makes sure a remote database can be reached before a command uses it. When it can't, commands
+// that don't change anything fall back to the read-only DB_SNAPSHOT if there is one, with a warning saying how
+// old it is. Local databases aren't checked.
+func (s *TimesheetService) CheckDatabase(ctx context.Context, mutating bool) error {
+	if !database.IsRemote(s.cfg) || s.db.ReadOnlySnapshot() != "" {
+		return nil
+	}
+	err := s.db.CheckConnection(ctx, s.cfg.ConnectTimeout)
+	if err == nil {
+		return nil
+	}
+	if s.cfg.DatabaseSnapshot == "" {
+		return fmt.Errorf("%w. Set DB_SNAPSHOT and run 'work db snapshot' while online to read from a local copy when this happens", err)
+	}
+	if mutating {
+		return fmt.Errorf("%w. This command changes data, so it can't run on the read-only snapshot", err)
+	}
+
+	path, pathErr := expandHomeDir(s.cfg.DatabaseSnapshot)
+	if pathErr != nil {
+		return fmt.Errorf("%w, and the snapshot can't be found: %v", err, pathErr)
+	}
+	savedAt, snapshotErr := s.db.UseSnapshot(path)
+	if snapshotErr != nil {
+		return fmt.Errorf("%w, and the snapshot couldn't be used: %v", err, snapshotErr)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %v\nShowing the read-only snapshot saved %s (%s ago)\n\n", err,
+		savedAt.Format("2006-01-02 15:04"), s.FormatDuration(time.Since(savedAt)))
+	return nil
+}
+
+// PingDatabase connects to the database count times, showing how long each took and the driver and server
+// details, or why it couldn't be reached
+func (s *TimesheetService) PingDatabase(ctx context.Context, count int) error {
+	if count < 1 {
+		return fmt.Errorf("count must be at least 1")
+	}
+	location := "local file"
+	if database.IsRemote(s.cfg) {
+		location = "remote"
+	}
+	fmt.Fprintf(s.out, "Database: %s (from %s)\n", database.RedactURL(s.cfg.DatabaseURL), s.cfg.DatabaseSource)
+	fmt.Fprintf(s.out, "Driver: %s, %s\n", s.cfg.DatabaseDriver, location)
+
+	var latencies []time.Duration
+	for i := 0; i < count; i++ {
+		start := time.Now()
+		if err := s.db.CheckConnection(ctx, s.cfg.ConnectTimeout); err != nil {
+			if s.cfg.DatabaseSnapshot != "" {
+				fmt.Fprintf(s.out, "Snapshot: %s, used by commands that don't change anything while the database is unreachable\n", s.cfg.DatabaseSnapshot)
+			}
+			return err
+		}
+		latency := time.Since(start)
+		latencies = append(latencies, latency)
+		fmt.Fprintf(s.out, "Ping %d: %s\n", i+1, latency.Round(10*time.Microsecond))
+	}
+	if count > 1 {
+		least, most, total := latencies[0], latencies[0], time.Duration(0)
+		for _, latency := range latencies {
+			least, most, total = min(least, latency), max(most, latency), total+latency
+		}
+		fmt.Fprintf(s.out, "Latency: min %s, avg %s, max %s\n", least.Round(10*time.Microsecond),
+			(total / time.Duration(count)).Round(10*time.Microsecond), most.Round(10*time.Microsecond))
+	}
+
+	version, err := s.db.Version(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "SQLite version: %s\n", version)
+	if status, err := s.db.SyncStatus(ctx); err == nil && !status.LastSync.IsZero() {
+		fmt.Fprintf(s.out, "Replica last synced: %s\n", status.LastSync.Format("2006-01-02 15:04:05"))
+	} else if err != nil && !errors.Is(err, database.ErrNoReplica) {
+		return err
+	}
+	if s.cfg.DatabaseSnapshot != "" {
+		if info, err := s.snapshotInfo(); err == nil {
+			fmt.Fprintf(s.out, "Snapshot: %s, saved %s\n", s.cfg.DatabaseSnapshot, info.ModTime().Format("2006-01-02 15:04"))
+		} else {
+			fmt.Fprintf(s.out, "Snapshot: %s, not saved yet, run 'work db snapshot'\n", s.cfg.DatabaseSnapshot)
+		}
+	}
+	return nil
+}
+
+// SaveDatabaseSnapshot copies the database to DB_SNAPSHOT, for commands that don't change anything to read
+// while it can't be reached
+func (s *TimesheetService) SaveDatabaseSnapshot(ctx context.Context) error {
+	if s.cfg.DatabaseSnapshot == "" {
+		return fmt.Errorf("set DB_SNAPSHOT to where the snapshot should be saved, e.g. DB_SNAPSHOT=~/.local/share/work/snapshot.db")
+	}
+	if database.IsRemote(s.cfg) {
+		if err := s.db.CheckConnection(ctx, s.cfg.ConnectTimeout); err != nil {
+			return err
+		}
+	}
+	path, err := expandHomeDir(s.cfg.DatabaseSnapshot)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	rows, err := s.db.SaveSnapshot(ctx, path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Saved a snapshot of %d rows to %s\n", rows, path)
+	return nil
+}
+
+func (s *TimesheetService) snapshotInfo() (os.FileInfo, error) {
+	path, err := expandHomeDir(s.cfg.DatabaseSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(path)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/heatmap"
+)
+
+// Image formats the heatmap can be written as
+const (
+	HeatmapFormatSVG = "svg"
+	HeatmapFormatPNG = "png"
+)
+
+// HeatmapFileName works out the file a heatmap is written to and its format. Output can be a format, written
+// to heatmap-<year> with its extension, or a file name ending in one.
+func HeatmapFileName(output string, year int) (string, string, error) {
+	if output == "" {
+		output = HeatmapFormatSVG
+	}
+	if format := strings.ToLower(output); format == HeatmapFormatSVG || format == HeatmapFormatPNG {
+		return fmt.Sprintf("heatmap-%d.%s", year, format), format, nil
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(output)), ".")
+	if format != HeatmapFormatSVG && format != HeatmapFormatPNG {
+		return "", "", fmt.Errorf("--output must be svg, png or a file name ending in .svg or .png, got %q", output)
+	}
+	return output, format, nil
+}
+
+// ExportHeatmap draws the hours tracked on each day of a year as a contribution calendar, shaded by how much
+// was worked, and writes it to output as an SVG or PNG. Sessions running past midnight count towards each day
+// they ran on. Given a client, only its sessions are counted.
+func (s *TimesheetService) ExportHeatmap(ctx context.Context, year int, clientName, output string) error {
+	if year < 1 || year > 9999 {
+		return fmt.Errorf("year must be a four digit year, got %d", year)
+	}
+	fileName, format, err := HeatmapFileName(output, year)
+	if err != nil {
+		return err
+	}
+
+	var clientID string
+	if clientName != "" {
+		client, err := s.db.GetClientByName(ctx, clientName)
+		if err != nil {
+			return fmt.Errorf("client '%s' does not exist", clientName)
+		}
+		clientID = client.ID
+	}
+	// Sessions are found by start time, so include the day before for any running past midnight into the year
+	sessions, err := s.ListSessionsWithDateRange(ctx, fmt.Sprintf("%d-12-31", year-1), fmt.Sprintf("%d-12-31", year), 100000)
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	hours := make(map[string]float64)
+	var total time.Duration
+	for _, session := range sessions {
+		if clientID != "" && session.ClientID != clientID {
+			continue
+		}
+		for _, day := range s.sessionDays(session) {
+			if day.date.Year() == year && day.worked > 0 {
+				hours[day.date.Format("2006-01-02")] += day.worked.Hours()
+				total += day.worked
+			}
+		}
+	}
+
+	title := fmt.Sprintf("%d: %s tracked on %d days", year, s.FormatDurationFor(config.DurationContextReport, total), len(hours))
+	if clientName != "" {
+		title = fmt.Sprintf("%d for %s: %s tracked on %d days", year, clientName, s.FormatDurationFor(config.DurationContextReport, total), len(hours))
+	}
+	calendar := heatmap.Calendar{
+		Year:  year,
+		Title: title,
+		Hours: hours,
+		Format: func(h float64) string {
+			return s.FormatDurationFor(config.DurationContextReport, time.Duration(h*float64(time.Hour)).Round(time.Minute))
+		},
+	}
+	write := func(w io.Writer) error { return heatmap.WriteSVG(w, calendar) }
+	if format == HeatmapFormatPNG {
+		write = func(w io.Writer) error { return heatmap.WritePNG(w, calendar) }
+	}
+	if err := writeFileAtomically(fileName, write); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Heatmap written to %s (%s)\n", fileName, title)
+	return nil
+}
@@ -3,93 +3,80 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/jesses-code-adventures/work/internal/config"
 	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
 	"github.com/shopspring/decimal"
 )
 
-// ShowTotalHours displays total worked hours with optional filtering
-func (s *TimesheetService) ShowTotalHours(ctx context.Context, client, period, periodDate, fromDate, toDate string) error {
-	// Handle period filtering
-	if period != "" {
-		var targetDate time.Time
-		var err error
-
-		if periodDate == "" {
-			// Default to today's date
-			targetDate = time.Now()
-		} else {
-			targetDate, err = time.Parse("2006-01-02", periodDate)
-			if err != nil {
-				return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
-			}
-		}
+// HoursTotal is the time worked in a period and what it's billed, before GST
+type HoursTotal struct {
+	Worked   time.Duration
+	Billable decimal.Decimal
+}
 
-		fromDateTime, toDateTime := s.CalculatePeriodRange(period, targetDate)
-		fromDate = fromDateTime.Format("2006-01-02")
-		toDate = toDateTime.Format("2006-01-02")
+// TotalHours totals the hours worked with optional filtering, including by the user who did the work
+func (s *TimesheetService) TotalHours(ctx context.Context, client, user, period, periodDate, fromDate, toDate string) (*HoursTotal, error) {
+	fromDate, toDate, err := s.ResolvePeriodRange(period, periodDate, fromDate, toDate)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get sessions based on filters
+	// Get sessions based on filters. Sessions are found by start time, so those from the day before are included
+	// for any running past midnight into the range, and only their time within it is counted.
 	var sessions []*models.WorkSession
-	var err error
+	queryFrom := dayBefore(fromDate)
 
 	if client != "" {
 		if fromDate != "" || toDate != "" {
 			// Get all sessions for client, then filter by date range
 			allSessions, err := s.ListSessionsByClient(ctx, client, 10000)
 			if err != nil {
-				return fmt.Errorf("failed to get sessions for client: %w", err)
+				return nil, fmt.Errorf("failed to get sessions for client: %w", err)
 			}
-			sessions = s.FilterSessionsByDateRange(allSessions, fromDate, toDate)
+			sessions = s.FilterSessionsByDateRange(allSessions, queryFrom, toDate)
 		} else {
 			sessions, err = s.ListSessionsByClient(ctx, client, 10000)
 			if err != nil {
-				return fmt.Errorf("failed to get sessions for client: %w", err)
+				return nil, fmt.Errorf("failed to get sessions for client: %w", err)
 			}
 		}
 	} else if fromDate != "" || toDate != "" {
-		if fromDate == "" {
-			fromDate = "1900-01-01"
+		if queryFrom == "" {
+			queryFrom = "1900-01-01"
 		}
-		if toDate == "" {
-			toDate = "2099-12-31"
+		queryTo := toDate
+		if queryTo == "" {
+			queryTo = "2099-12-31"
 		}
-		sessions, err = s.ListSessionsWithDateRange(ctx, fromDate, toDate, 10000)
+		sessions, err = s.ListSessionsWithDateRange(ctx, queryFrom, queryTo, 10000)
 		if err != nil {
-			return fmt.Errorf("failed to get sessions: %w", err)
+			return nil, fmt.Errorf("failed to get sessions: %w", err)
 		}
 	} else {
 		sessions, err = s.ListRecentSessions(ctx, 10000)
 		if err != nil {
-			return fmt.Errorf("failed to get sessions: %w", err)
+			return nil, fmt.Errorf("failed to get sessions: %w", err)
 		}
 	}
 
-	if len(sessions) == 0 {
-		fmt.Println("0.0")
-		return nil
+	if user != "" {
+		if sessions, err = s.FilterSessionsByUser(ctx, sessions, user); err != nil {
+			return nil, err
+		}
 	}
 
-	// Calculate total hours and billable amount
-	totalDuration := time.Duration(0)
-	totalBillable := decimal.Zero
+	total := &HoursTotal{}
 	for _, session := range sessions {
-		duration := s.CalculateDuration(session)
-		totalDuration += duration
-		totalBillable = totalBillable.Add(s.CalculateBillableAmount(session))
-	}
-
-	totalHours := totalDuration.Hours()
-	fmt.Printf("%.1f hours", totalHours)
-
-	if totalBillable.GreaterThan(decimal.Zero) {
-		fmt.Printf(" | %s", s.FormatBillableAmountWithGST(totalBillable))
+		worked := s.sessionWorkedBetween(session, fromDate, toDate)
+		total.Worked += worked
+		total.Billable = total.Billable.Add(s.sessionBillableShare(session, worked))
 	}
-	fmt.Println()
-
-	return nil
+	return total, nil
 }
 
 func (s *TimesheetService) FilterSessionsByDateRange(sessions []*models.WorkSession, fromDate, toDate string) []*models.WorkSession {
@@ -165,6 +152,17 @@ func (s *TimesheetService) CalculatePeriodRange(period string, targetDate time.T
 		start := time.Date(targetDate.Year(), targetDate.Month(), 1, 0, 0, 0, 0, targetDate.Location())
 		end := start.AddDate(0, 1, 0).Add(-time.Nanosecond)
 		return start, end
+	case "quarter":
+		// Quarters are counted from the start of the financial year
+		start := s.financialYearStart(targetDate)
+		monthsIn := (int(targetDate.Month()) - int(start.Month()) + 12) % 12
+		start = start.AddDate(0, monthsIn/3*3, 0)
+		end := start.AddDate(0, 3, 0).Add(-time.Nanosecond)
+		return start, end
+	case "year":
+		start := s.financialYearStart(targetDate)
+		end := start.AddDate(1, 0, 0).Add(-time.Nanosecond)
+		return start, end
 	default:
 		// Default to day if unknown period
 		start := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), 0, 0, 0, 0, targetDate.Location())
@@ -172,3 +170,42 @@ func (s *TimesheetService) CalculatePeriodRange(period string, targetDate time.T
 		return start, end
 	}
 }
+
+// ShowUnbilledExposure prints the hours and value of completed sessions that haven't been invoiced yet,
+// across all clients. Values are at session rates, before retainers and GST.
+func (s *TimesheetService) ShowUnbilledExposure(ctx context.Context) error {
+	sessions, err := s.db.GetSessionsForPeriodWithoutInvoice(ctx, time.Time{}, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get uninvoiced sessions: %w", err)
+	}
+
+	var worked time.Duration
+	clients := make(map[string]bool)
+	totals := make(map[string]decimal.Decimal)
+	formatters := make(map[string]money.Formatter)
+	for _, session := range sessions {
+		worked += s.CalculateDuration(session)
+		clients[session.ClientID] = true
+		m := s.clientMoneyByID(&session.ClientID)
+		totals[m.Currency] = totals[m.Currency].Add(s.CalculateBillableAmount(session))
+		formatters[m.Currency] = m
+	}
+
+	if len(sessions) == 0 {
+		fmt.Fprintln(s.out, "Unbilled: nothing, every completed session is invoiced")
+		return nil
+	}
+
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	amounts := make([]string, len(currencies))
+	for i, currency := range currencies {
+		amounts[i] = formatters[currency].Format(totals[currency])
+	}
+
+	fmt.Fprintf(s.out, "Unbilled: %s across %d client(s) (%s)\n", s.FormatDurationFor(config.DurationContextReport, worked), len(clients), strings.Join(amounts, " + "))
+	return nil
+}
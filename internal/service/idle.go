@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/activitywatch"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// IdleTrimEnabled reports whether idle periods are looked for when sessions stop, set with IDLE_TRIM_MINUTES
+func (s *TimesheetService) IdleTrimEnabled() bool {
+	return s.cfg != nil && s.cfg.IdleTrimThreshold > 0
+}
+
+// FindIdlePeriods asks ActivityWatch when the computer the session was worked on was idle during it, for at least
+// IDLE_TRIM_MINUTES at a time
+func (s *TimesheetService) FindIdlePeriods(ctx context.Context, session *models.WorkSession) ([]activitywatch.Period, error) {
+	if session.EndTime == nil {
+		return nil, ErrSessionNotFinished
+	}
+	hostname := utils.FromPtr(session.Hostname)
+	if hostname == "" {
+		var err error
+		if hostname, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("failed to get hostname: %w", err)
+		}
+	}
+
+	client := activitywatch.NewClient(s.cfg.ActivityWatchURL)
+	periods, err := client.IdlePeriods(ctx, hostname, session.StartTime, *session.EndTime, s.cfg.IdleTrimThreshold)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Debug("read idle periods", "session", session.ID, "hostname", hostname, "periods", len(periods))
+	return periods, nil
+}
+
+// DisplayIdlePeriods lists idle periods found during a session and how long they add up to
+func (s *TimesheetService) DisplayIdlePeriods(periods []activitywatch.Period) {
+	var total time.Duration
+	for _, period := range periods {
+		total += period.Duration()
+	}
+	fmt.Fprintf(s.out, "Idle for %s during the session:\n", s.FormatDuration(total))
+	for _, period := range periods {
+		fmt.Fprintf(s.out, "  %s - %s (%s)\n", period.Start.Format("15:04"), period.End.Format("15:04"), s.FormatDuration(period.Duration()))
+	}
+}
+
+// TrimIdlePeriods takes idle periods out of a session by recording them as breaks, returning the session with
+// its reduced duration
+func (s *TimesheetService) TrimIdlePeriods(ctx context.Context, session *models.WorkSession, periods []activitywatch.Period) (*models.WorkSession, error) {
+	if session.InvoiceID != nil {
+		return nil, fmt.Errorf("session '%s' has already been invoiced", session.ID)
+	}
+	breaks := make([]*models.SessionBreak, len(periods))
+	for i, period := range periods {
+		breaks[i] = &models.SessionBreak{
+			StartTime: period.Start,
+			EndTime:   period.End,
+			Source:    models.BreakSourceActivityWatch,
+		}
+	}
+	updated, err := s.db.AddSessionBreaks(ctx, session.ID, breaks, false)
+	if err != nil {
+		return nil, err
+	}
+	updated.ClientName = session.ClientName
+	return updated, nil
+}
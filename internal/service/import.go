@@ -0,0 +1,655 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/database"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// How an import combines with what's already in the database
+const (
+	ImportModeMerge   = "merge"
+	ImportModeReplace = "replace"
+)
+
+// maxImportProblems is how many integrity problems are listed when an export can't be imported
+const maxImportProblems = 10
+
+// importSet is a full export as read back in
+type importSet struct {
+	clients     []*models.Client
+	users       []*models.User
+	sessions    []exportSession
+	invoices    []*models.Invoice
+	payments    []*models.Payment
+	creditNotes []*models.CreditNote
+	expenses    []*models.Expense
+	templates   []*models.SessionTemplate
+	clientRates []*models.ClientRate
+	contacts    []*models.ClientContact
+	rules       []*models.ExpenseRule
+	milestones  []*models.Milestone
+	repos       []*models.SessionRepo
+	leave       []*models.Leave
+}
+
+// importCount is how many of one kind of record an import added and how many it skipped as already present
+type importCount struct {
+	imported int
+	skipped  int
+}
+
+// ImportData restores a full JSON export written by ExportAll. The export is checked for references to records
+// it doesn't contain before anything is written. Merging keeps what's in the database, matching clients, users
+// and templates by name, invoices and credit notes by number, sessions by client and start time, rate changes by
+// client, day and rate, expense rules by pattern, milestones by client and name, leave by kind and days and
+// everything by ID, trashed sessions and expenses included, skipping those already present and pointing the rest
+// at the records they match. Replacing deletes everything first. Either way the import happens in a single transaction.
+func (s *TimesheetService) ImportData(ctx context.Context, dir, mode string) error {
+	if mode != ImportModeMerge && mode != ImportModeReplace {
+		return fmt.Errorf("invalid import mode %q, expected %s or %s", mode, ImportModeMerge, ImportModeReplace)
+	}
+
+	set, err := readImportSet(dir)
+	if err != nil {
+		return err
+	}
+	if err := set.validate(); err != nil {
+		return err
+	}
+
+	data := &database.ImportData{
+		Clients:     set.clients,
+		Users:       set.users,
+		Invoices:    set.invoices,
+		Payments:    set.payments,
+		CreditNotes: set.creditNotes,
+		Expenses:    set.expenses,
+		Templates:   set.templates,
+		ClientRates: set.clientRates,
+		Contacts:    set.contacts,
+		Rules:       set.rules,
+		Milestones:  set.milestones,
+		Repos:       set.repos,
+		Leave:       set.leave,
+	}
+	for _, session := range set.sessions {
+		data.Sessions = append(data.Sessions, session.WorkSession)
+		data.Breaks = append(data.Breaks, session.Breaks...)
+	}
+
+	counts := make(map[string]importCount)
+	if mode == ImportModeMerge {
+		if data, counts, err = s.mergeImport(ctx, data); err != nil {
+			return err
+		}
+	} else {
+		counts["clients"] = importCount{imported: len(data.Clients)}
+		counts["users"] = importCount{imported: len(data.Users)}
+		counts["sessions"] = importCount{imported: len(data.Sessions)}
+		counts["invoices"] = importCount{imported: len(data.Invoices)}
+		counts["payments"] = importCount{imported: len(data.Payments)}
+		counts["credit_notes"] = importCount{imported: len(data.CreditNotes)}
+		counts["expenses"] = importCount{imported: len(data.Expenses)}
+		counts["session_templates"] = importCount{imported: len(data.Templates)}
+		counts["client_rates"] = importCount{imported: len(data.ClientRates)}
+		counts["client_contacts"] = importCount{imported: len(data.Contacts)}
+		counts["expense_rules"] = importCount{imported: len(data.Rules)}
+		counts["milestones"] = importCount{imported: len(data.Milestones)}
+		counts["session_repos"] = importCount{imported: len(data.Repos)}
+		counts["leave"] = importCount{imported: len(data.Leave)}
+	}
+
+	if err := s.db.ImportData(ctx, data, mode == ImportModeReplace); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.out, "Imported from %s (%s):\n", dir, mode)
+	for _, entity := range ExportEntities {
+		count := counts[entity]
+		if count.skipped > 0 {
+			fmt.Fprintf(s.out, "  %-18s %d imported, %d already present\n", entity, count.imported, count.skipped)
+		} else {
+			fmt.Fprintf(s.out, "  %-18s %d imported\n", entity, count.imported)
+		}
+	}
+	return nil
+}
+
+// readImportSet reads a full export, checking its manifest says it's one this version can read
+func readImportSet(dir string) (*importSet, error) {
+	data, err := os.ReadFile(filepath.Join(dir, exportManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no %s in %s, expected a directory written by 'work export all'", exportManifestFile, dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var manifest exportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if manifest.SchemaVersion > ExportSchemaVersion {
+		return nil, fmt.Errorf("export has schema version %d, newer than the %d this version of work reads", manifest.SchemaVersion, ExportSchemaVersion)
+	}
+	if manifest.Format != ExportFormatJSON {
+		return nil, fmt.Errorf("only JSON exports can be imported, export with --format %s", ExportFormatJSON)
+	}
+
+	set := &importSet{}
+	targets := map[string]any{
+		"clients":           &set.clients,
+		"users":             &set.users,
+		"sessions":          &set.sessions,
+		"invoices":          &set.invoices,
+		"payments":          &set.payments,
+		"credit_notes":      &set.creditNotes,
+		"expenses":          &set.expenses,
+		"session_templates": &set.templates,
+		"client_rates":      &set.clientRates,
+		"client_contacts":   &set.contacts,
+		"expense_rules":     &set.rules,
+		"milestones":        &set.milestones,
+		"session_repos":     &set.repos,
+		"leave":             &set.leave,
+	}
+	for _, entity := range ExportEntities {
+		fileName := filepath.Join(dir, entity+"."+ExportFormatJSON)
+		data, err := os.ReadFile(fileName)
+		if errors.Is(err, os.ErrNotExist) && manifest.Counts[entity] == 0 {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", fileName, err)
+		}
+		if err := json.Unmarshal(data, targets[entity]); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", fileName, err)
+		}
+	}
+	return set, nil
+}
+
+// validate checks every reference in the export is to a record in it, and that no ID appears twice
+func (set *importSet) validate() error {
+	var problems []string
+	ids := func(kind string, records int, id func(int) string) map[string]bool {
+		seen := make(map[string]bool, records)
+		for i := 0; i < records; i++ {
+			if seen[id(i)] {
+				problems = append(problems, fmt.Sprintf("%s %s appears more than once", kind, id(i)))
+			}
+			seen[id(i)] = true
+		}
+		return seen
+	}
+	refers := func(kind, id, refKind, ref string, known map[string]bool) {
+		if !known[ref] {
+			problems = append(problems, fmt.Sprintf("%s %s refers to %s %s, which isn't in the export", kind, id, refKind, ref))
+		}
+	}
+
+	clients := ids("client", len(set.clients), func(i int) string { return set.clients[i].ID })
+	users := ids("user", len(set.users), func(i int) string { return set.users[i].ID })
+	invoices := ids("invoice", len(set.invoices), func(i int) string { return set.invoices[i].ID })
+	sessions := ids("session", len(set.sessions), func(i int) string { return set.sessions[i].ID })
+	ids("payment", len(set.payments), func(i int) string { return set.payments[i].ID })
+	ids("credit note", len(set.creditNotes), func(i int) string { return set.creditNotes[i].ID })
+	ids("expense", len(set.expenses), func(i int) string { return set.expenses[i].ID })
+	ids("session template", len(set.templates), func(i int) string { return set.templates[i].ID })
+	ids("client rate", len(set.clientRates), func(i int) string { return set.clientRates[i].ID })
+	ids("client contact", len(set.contacts), func(i int) string { return set.contacts[i].ID })
+	ids("expense rule", len(set.rules), func(i int) string { return set.rules[i].ID })
+	ids("milestone", len(set.milestones), func(i int) string { return set.milestones[i].ID })
+	ids("session repository", len(set.repos), func(i int) string { return set.repos[i].SessionID + " " + set.repos[i].RepoPath })
+	ids("leave", len(set.leave), func(i int) string { return set.leave[i].ID })
+
+	for _, invoice := range set.invoices {
+		refers("invoice", invoice.InvoiceNumber, "client", invoice.ClientID, clients)
+	}
+	for _, session := range set.sessions {
+		refers("session", session.ID, "client", session.ClientID, clients)
+		if session.UserID != nil {
+			refers("session", session.ID, "user", *session.UserID, users)
+		}
+		if session.InvoiceID != nil {
+			refers("session", session.ID, "invoice", *session.InvoiceID, invoices)
+		}
+		for _, sessionBreak := range session.Breaks {
+			if sessionBreak.SessionID != session.ID {
+				refers("break", sessionBreak.ID, "session", sessionBreak.SessionID, sessions)
+			}
+		}
+	}
+	for _, payment := range set.payments {
+		refers("payment", payment.ID, "invoice", payment.InvoiceID, invoices)
+	}
+	for _, creditNote := range set.creditNotes {
+		refers("credit note", creditNote.CreditNoteNumber, "invoice", creditNote.InvoiceID, invoices)
+	}
+	for _, expense := range set.expenses {
+		if expense.ClientID != nil {
+			refers("expense", expense.ID, "client", *expense.ClientID, clients)
+		}
+		if expense.InvoiceID != nil {
+			refers("expense", expense.ID, "invoice", *expense.InvoiceID, invoices)
+		}
+	}
+	for _, template := range set.templates {
+		refers("session template", template.Name, "client", template.ClientID, clients)
+	}
+	for _, rate := range set.clientRates {
+		refers("client rate", rate.ID, "client", rate.ClientID, clients)
+	}
+	for _, contact := range set.contacts {
+		refers("client contact", contact.ID, "client", contact.ClientID, clients)
+		if contact.SessionID != nil {
+			refers("client contact", contact.ID, "session", *contact.SessionID, sessions)
+		}
+	}
+	for _, rule := range set.rules {
+		if rule.ClientID != nil {
+			refers("expense rule", rule.Pattern, "client", *rule.ClientID, clients)
+		}
+	}
+	for _, milestone := range set.milestones {
+		refers("milestone", milestone.Name, "client", milestone.ClientID, clients)
+		if milestone.InvoiceID != nil {
+			refers("milestone", milestone.Name, "invoice", *milestone.InvoiceID, invoices)
+		}
+	}
+	for _, repo := range set.repos {
+		refers("session repository", repo.RepoPath, "session", repo.SessionID, sessions)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	if len(problems) > maxImportProblems {
+		problems = append(problems[:maxImportProblems], fmt.Sprintf("and %d more", len(problems)-maxImportProblems))
+	}
+	return fmt.Errorf("export can't be imported:\n  %s", strings.Join(problems, "\n  "))
+}
+
+// mergeImport drops records already in the database from an import and points references to them at the
+// records they match, returning what's left to insert and the counts for each kind
+func (s *TimesheetService) mergeImport(ctx context.Context, data *database.ImportData) (*database.ImportData, map[string]importCount, error) {
+	counts := make(map[string]importCount)
+	merged := &database.ImportData{}
+
+	existingClients, err := s.db.ListClients(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientIDs := make(map[string]string) // imported ID to the ID in the database
+	clientsByName := make(map[string]string)
+	for _, client := range existingClients {
+		clientIDs[client.ID] = client.ID
+		clientsByName[client.Name] = client.ID
+	}
+	count := importCount{}
+	for _, client := range data.Clients {
+		if id, ok := clientIDs[client.ID]; ok {
+			clientIDs[client.ID] = id
+			count.skipped++
+		} else if id, ok := clientsByName[client.Name]; ok {
+			clientIDs[client.ID] = id
+			count.skipped++
+		} else {
+			clientIDs[client.ID] = client.ID
+			merged.Clients = append(merged.Clients, client)
+			count.imported++
+		}
+	}
+	counts["clients"] = count
+
+	existingUsers, err := s.db.ListUsers(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	userIDs := make(map[string]string)
+	usersByName := make(map[string]string)
+	for _, user := range existingUsers {
+		userIDs[user.ID] = user.ID
+		usersByName[user.Name] = user.ID
+	}
+	count = importCount{}
+	for _, user := range data.Users {
+		if id, ok := userIDs[user.ID]; ok {
+			userIDs[user.ID] = id
+			count.skipped++
+		} else if id, ok := usersByName[user.Name]; ok {
+			userIDs[user.ID] = id
+			count.skipped++
+		} else {
+			userIDs[user.ID] = user.ID
+			merged.Users = append(merged.Users, user)
+			count.imported++
+		}
+	}
+	counts["users"] = count
+
+	existingInvoices, err := s.db.ListInvoices(ctx, math.MaxInt32)
+	if err != nil {
+		return nil, nil, err
+	}
+	invoiceIDs := make(map[string]string)
+	invoicesByNumber := make(map[string]string)
+	for _, invoice := range existingInvoices {
+		invoiceIDs[invoice.ID] = invoice.ID
+		invoicesByNumber[invoice.InvoiceNumber] = invoice.ID
+	}
+	count = importCount{}
+	for _, invoice := range data.Invoices {
+		if id, ok := invoiceIDs[invoice.ID]; ok {
+			invoiceIDs[invoice.ID] = id
+			count.skipped++
+		} else if id, ok := invoicesByNumber[invoice.InvoiceNumber]; ok {
+			invoiceIDs[invoice.ID] = id
+			count.skipped++
+		} else {
+			invoiceIDs[invoice.ID] = invoice.ID
+			invoice.ClientID = clientIDs[invoice.ClientID]
+			merged.Invoices = append(merged.Invoices, invoice)
+			count.imported++
+		}
+	}
+	counts["invoices"] = count
+
+	existingSessions, err := s.db.ListRecentSessions(ctx, math.MaxInt32)
+	if err != nil {
+		return nil, nil, err
+	}
+	sessionIDs := make(map[string]string)
+	sessionStarts := make(map[string]string) // client ID and start time to the session's ID
+	sessionStart := func(clientID string, start time.Time) string {
+		return clientID + "@" + start.UTC().Format(time.RFC3339)
+	}
+	for _, session := range existingSessions {
+		sessionIDs[session.ID] = session.ID
+		sessionStarts[sessionStart(session.ClientID, session.StartTime)] = session.ID
+	}
+	// Sessions in the trash still hold their IDs, so they're already present too
+	trashedSessions, err := s.db.ListTrashedSessions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, session := range trashedSessions {
+		sessionIDs[session.ID] = session.ID
+	}
+	count = importCount{}
+	imported := make(map[string]bool)
+	for _, session := range data.Sessions {
+		session.ClientID = clientIDs[session.ClientID]
+		if id, ok := sessionIDs[session.ID]; ok {
+			sessionIDs[session.ID] = id
+			count.skipped++
+			continue
+		}
+		if id, ok := sessionStarts[sessionStart(session.ClientID, session.StartTime)]; ok {
+			sessionIDs[session.ID] = id
+			count.skipped++
+			continue
+		}
+		sessionIDs[session.ID] = session.ID
+		if session.InvoiceID != nil {
+			invoiceID := invoiceIDs[*session.InvoiceID]
+			session.InvoiceID = &invoiceID
+		}
+		if session.UserID != nil {
+			userID := userIDs[*session.UserID]
+			session.UserID = &userID
+		}
+		imported[session.ID] = true
+		merged.Sessions = append(merged.Sessions, session)
+		count.imported++
+	}
+	counts["sessions"] = count
+	for _, sessionBreak := range data.Breaks {
+		if imported[sessionBreak.SessionID] {
+			merged.Breaks = append(merged.Breaks, sessionBreak)
+		}
+	}
+	// Sessions already present keep the repositories they have
+	count = importCount{}
+	for _, repo := range data.Repos {
+		if !imported[repo.SessionID] {
+			count.skipped++
+			continue
+		}
+		merged.Repos = append(merged.Repos, repo)
+		count.imported++
+	}
+	counts["session_repos"] = count
+
+	existingPayments, err := s.db.ListPayments(ctx, "", "")
+	if err != nil {
+		return nil, nil, err
+	}
+	paymentIDs := make(map[string]bool)
+	for _, payment := range existingPayments {
+		paymentIDs[payment.ID] = true
+	}
+	count = importCount{}
+	for _, payment := range data.Payments {
+		if paymentIDs[payment.ID] {
+			count.skipped++
+			continue
+		}
+		payment.InvoiceID = invoiceIDs[payment.InvoiceID]
+		merged.Payments = append(merged.Payments, payment)
+		count.imported++
+	}
+	counts["payments"] = count
+
+	existingCreditNotes, err := s.db.ListCreditNotes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	creditNotes := make(map[string]bool) // IDs and numbers
+	for _, creditNote := range existingCreditNotes {
+		creditNotes[creditNote.ID] = true
+		creditNotes[creditNote.CreditNoteNumber] = true
+	}
+	count = importCount{}
+	for _, creditNote := range data.CreditNotes {
+		if creditNotes[creditNote.ID] || creditNotes[creditNote.CreditNoteNumber] {
+			count.skipped++
+			continue
+		}
+		creditNote.InvoiceID = invoiceIDs[creditNote.InvoiceID]
+		merged.CreditNotes = append(merged.CreditNotes, creditNote)
+		count.imported++
+	}
+	counts["credit_notes"] = count
+
+	existingExpenses, err := s.db.ListExpenses(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	expenseIDs := make(map[string]bool)
+	for _, expense := range existingExpenses {
+		expenseIDs[expense.ID] = true
+	}
+	trashedExpenses, err := s.db.ListTrashedExpenses(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, expense := range trashedExpenses {
+		expenseIDs[expense.ID] = true
+	}
+	count = importCount{}
+	for _, expense := range data.Expenses {
+		if expenseIDs[expense.ID] {
+			count.skipped++
+			continue
+		}
+		if expense.ClientID != nil {
+			clientID := clientIDs[*expense.ClientID]
+			expense.ClientID = &clientID
+		}
+		if expense.InvoiceID != nil {
+			invoiceID := invoiceIDs[*expense.InvoiceID]
+			expense.InvoiceID = &invoiceID
+		}
+		merged.Expenses = append(merged.Expenses, expense)
+		count.imported++
+	}
+	counts["expenses"] = count
+
+	existingTemplates, err := s.db.ListSessionTemplates(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	templates := make(map[string]bool) // IDs and names
+	for _, template := range existingTemplates {
+		templates[template.ID] = true
+		templates[template.Name] = true
+	}
+	count = importCount{}
+	for _, template := range data.Templates {
+		if templates[template.ID] || templates[template.Name] {
+			count.skipped++
+			continue
+		}
+		template.ClientID = clientIDs[template.ClientID]
+		merged.Templates = append(merged.Templates, template)
+		count.imported++
+	}
+	counts["session_templates"] = count
+
+	rates := make(map[string]bool) // IDs, and client IDs with the day and rate of each change
+	rateChange := func(rate *models.ClientRate) string {
+		return rate.ClientID + "@" + rate.EffectiveDate.UTC().Format(time.RFC3339) + "=" + rate.HourlyRate.String()
+	}
+	for _, client := range existingClients {
+		history, err := s.db.ListClientRates(ctx, client.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, rate := range history {
+			rates[rate.ID] = true
+			rates[rateChange(rate)] = true
+		}
+	}
+	count = importCount{}
+	for _, rate := range data.ClientRates {
+		rate.ClientID = clientIDs[rate.ClientID]
+		if rates[rate.ID] || rates[rateChange(rate)] {
+			count.skipped++
+			continue
+		}
+		merged.ClientRates = append(merged.ClientRates, rate)
+		count.imported++
+	}
+	counts["client_rates"] = count
+
+	existingContacts, err := s.db.ListClientContacts(ctx, nil, math.MaxInt32)
+	if err != nil {
+		return nil, nil, err
+	}
+	contactIDs := make(map[string]bool)
+	for _, contact := range existingContacts {
+		contactIDs[contact.ID] = true
+	}
+	count = importCount{}
+	for _, contact := range data.Contacts {
+		if contactIDs[contact.ID] {
+			count.skipped++
+			continue
+		}
+		contact.ClientID = clientIDs[contact.ClientID]
+		if contact.SessionID != nil {
+			sessionID := sessionIDs[*contact.SessionID]
+			contact.SessionID = &sessionID
+		}
+		merged.Contacts = append(merged.Contacts, contact)
+		count.imported++
+	}
+	counts["client_contacts"] = count
+
+	existingRules, err := s.db.ListExpenseRules(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	rules := make(map[string]bool) // IDs and patterns
+	for _, rule := range existingRules {
+		rules[rule.ID] = true
+		rules[rule.Pattern] = true
+	}
+	count = importCount{}
+	for _, rule := range data.Rules {
+		if rules[rule.ID] || rules[rule.Pattern] {
+			count.skipped++
+			continue
+		}
+		if rule.ClientID != nil {
+			clientID := clientIDs[*rule.ClientID]
+			rule.ClientID = &clientID
+		}
+		merged.Rules = append(merged.Rules, rule)
+		count.imported++
+	}
+	counts["expense_rules"] = count
+
+	existingMilestones, err := s.db.ListMilestones(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	milestones := make(map[string]bool) // IDs, and client IDs with the name of each milestone
+	milestoneName := func(milestone *models.Milestone) string {
+		return milestone.ClientID + "/" + milestone.Name
+	}
+	for _, milestone := range existingMilestones {
+		milestones[milestone.ID] = true
+		milestones[milestoneName(milestone)] = true
+	}
+	count = importCount{}
+	for _, milestone := range data.Milestones {
+		milestone.ClientID = clientIDs[milestone.ClientID]
+		if milestones[milestone.ID] || milestones[milestoneName(milestone)] {
+			count.skipped++
+			continue
+		}
+		if milestone.InvoiceID != nil {
+			invoiceID := invoiceIDs[*milestone.InvoiceID]
+			milestone.InvoiceID = &invoiceID
+		}
+		merged.Milestones = append(merged.Milestones, milestone)
+		count.imported++
+	}
+	counts["milestones"] = count
+
+	existingLeave, err := s.db.ListLeave(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	leave := make(map[string]bool) // IDs, and the kind and days of each stretch of leave
+	leaveDays := func(days *models.Leave) string {
+		return days.Type + "@" + days.StartDate.Format("2006-01-02") + "/" + days.EndDate.Format("2006-01-02")
+	}
+	for _, days := range existingLeave {
+		leave[days.ID] = true
+		leave[leaveDays(days)] = true
+	}
+	count = importCount{}
+	for _, days := range data.Leave {
+		if leave[days.ID] || leave[leaveDays(days)] {
+			count.skipped++
+			continue
+		}
+		merged.Leave = append(merged.Leave, days)
+		count.imported++
+	}
+	counts["leave"] = count
+
+	return merged, counts, nil
+}
@@ -0,0 +1,382 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// Invoice file formats, each rendered from the same invoiceDocument
+const (
+	InvoiceFormatPDF  = "pdf"
+	InvoiceFormatHTML = "html"
+)
+
+// InvoiceFormats are the accepted values for invoices generate --format
+var InvoiceFormats = []string{InvoiceFormatPDF, InvoiceFormatHTML}
+
+// ValidateInvoiceFormats returns an error if there are no formats or any isn't one of InvoiceFormats
+func ValidateInvoiceFormats(formats []string) error {
+	if len(formats) == 0 {
+		return fmt.Errorf("at least one invoice format is required, expected: %s", strings.Join(InvoiceFormats, ", "))
+	}
+	for _, format := range formats {
+		if !containsFold(InvoiceFormats, format) {
+			return fmt.Errorf("invalid invoice format %q, expected: %s", format, strings.Join(InvoiceFormats, ", "))
+		}
+	}
+	return nil
+}
+
+// invoiceDocument is everything printed on an invoice, worked out once and laid out by each format. Amounts
+// are formatted in the client's currency. Fields are exported for the HTML template.
+type invoiceDocument struct {
+	Number      string
+	Title       string
+	CompanyName string
+	ABN         string         // the business's ABN, and ACN when there is one
+	References  []invoiceField // the client's PO number and project code, when the invoice has them
+	Logo        *invoiceLogo   // printed at the top of PDFs, nil for none
+	BrandColour string         // #rrggbb colour of headings and the total, empty for the default
+	BillTo      *invoiceParty
+	Payment     []invoiceField
+	Totals      []invoiceTotal
+	// EarlyDiscount describes the discount for paying early, e.g. "Early payment discount: 2% within 7 days ($20.00) ..."
+	EarlyDiscount string
+	PaymentTerms  string
+	Notes         string
+
+	SessionsHeading  string
+	GroupBy          string
+	Lines            []invoiceDocumentLine
+	Milestones       []invoiceDocumentMilestone
+	Expenses         []invoiceDocumentExpense
+	ExpenseSubtotals []invoiceField // by category, only when expenses have been categorised
+	RetainerNote     string
+	// Appendix has the full work summaries of the sessions on each line, when asked for. Lines are numbered
+	// when there's an appendix so its entries can refer to them.
+	Appendix []invoiceAppendixEntry
+}
+
+// invoiceParty is who an invoice is billed to, with fields left empty when the client doesn't have them
+type invoiceParty struct {
+	Contact string
+	Company string
+	Address string
+	Email   string
+	Phone   string
+	ABN     string
+}
+
+type invoiceField struct {
+	Label string
+	Value string
+}
+
+// invoiceTotal is a line of the totals, with Grand set on the total owed
+type invoiceTotal struct {
+	Label  string
+	Amount string
+	Grand  bool
+}
+
+// invoiceDocumentLine is a row of the session details, with End empty for lines grouped by day or description.
+// Subtotal rows total the day before them, labelled in Start, with only a duration and amount.
+type invoiceDocumentLine struct {
+	Start       string
+	End         string
+	Duration    string
+	Rate        string
+	Description string
+	Amount      string
+	Subtotal    bool
+	Number      int  // the line's number, counting only session lines, when work summaries were asked for
+	Summarised  bool // the line has an entry in the appendix
+}
+
+// invoiceAppendixEntry is the full work summaries of the sessions billed on one line of the session details
+type invoiceAppendixEntry struct {
+	Line     int
+	Sessions []invoiceAppendixSession
+}
+
+// Anchor is the entry's id in the HTML, linked to from its line's number
+func (e invoiceAppendixEntry) Anchor() string {
+	return fmt.Sprintf("line-%d", e.Line)
+}
+
+// invoiceAppendixSession is a session's full work summary, cleaned up to print, with one line of text per Lines
+// entry and empty entries between paragraphs
+type invoiceAppendixSession struct {
+	When  string
+	Lines []string
+}
+
+// invoiceDocumentMilestone is a fixed-price milestone billed on the invoice, independent of the hours worked
+type invoiceDocumentMilestone struct {
+	Completed string
+	Name      string
+	Amount    string
+}
+
+type invoiceDocumentExpense struct {
+	Date      string
+	Category  string
+	Cost      string
+	Billed    string
+	Reference string
+}
+
+// BySession reports whether each session is listed on its own line, with a start and end
+func (d *invoiceDocument) BySession() bool {
+	return d.GroupBy == models.InvoiceGroupBySession || d.GroupBy == ""
+}
+
+// Numbered reports whether lines are numbered, which they are when there's an appendix referring to them
+func (d *invoiceDocument) Numbered() bool {
+	return len(d.Appendix) > 0
+}
+
+// WhenHeading is the heading of the column saying when a grouped line's work was done
+func (d *invoiceDocument) WhenHeading() string {
+	if d.GroupBy == models.InvoiceGroupByDay {
+		return "Date"
+	}
+	return "Dates"
+}
+
+// buildInvoiceDocument works out what an invoice shows. Totals are calculated the same way as the stored
+// invoice, so every format matches it to the cent. With withAppendix, the sessions' full work summaries are
+// added as an appendix after the session details.
+func (s *TimesheetService) buildInvoiceDocument(invoice *models.Invoice, client *models.Client, sessions []*models.WorkSession, expenses []*models.Expense, milestones []*models.Milestone, period string, fromDate, toDate time.Time, withAppendix bool) *invoiceDocument {
+	m := s.clientMoney(client)
+	doc := &invoiceDocument{
+		Number:          invoice.InvoiceNumber,
+		Title:           fmt.Sprintf("Invoice - %s", s.formatClientName(client.Name)),
+		CompanyName:     s.cfg.BillingCompanyName,
+		SessionsHeading: fmt.Sprintf("Session Details (%s to %s)", fromDate.Format("2006-01-02"), toDate.Format("2006-01-02")),
+		GroupBy:         invoice.GroupBy,
+		Payment: []invoiceField{
+			{"Bank", s.cfg.BillingBank},
+			{"Account Name", s.cfg.BillingAccountName},
+			{"Account Number", s.cfg.BillingAccountNumber},
+			{"BSB", s.cfg.BillingBSB},
+		},
+	}
+
+	doc.Logo, doc.BrandColour = s.invoiceBranding(client)
+
+	if s.cfg.BillingABN != "" {
+		doc.ABN = fmt.Sprintf("ABN %s", s.cfg.BillingABN)
+		if s.cfg.BillingACN != "" {
+			doc.ABN = fmt.Sprintf("ABN %s (includes ACN %s)", s.cfg.BillingABN, s.cfg.BillingACN)
+		}
+	}
+
+	if invoice.PoNumber != nil && *invoice.PoNumber != "" {
+		doc.References = append(doc.References, invoiceField{"PO Number", *invoice.PoNumber})
+	}
+	if invoice.ProjectCode != nil && *invoice.ProjectCode != "" {
+		doc.References = append(doc.References, invoiceField{"Project Code", *invoice.ProjectCode})
+	}
+
+	if client.CompanyName != nil || client.ContactName != nil {
+		doc.BillTo = &invoiceParty{
+			Contact: utils.FromPtr(client.ContactName),
+			Company: utils.FromPtr(client.CompanyName),
+			Address: s.formatClientAddress(client),
+			Email:   utils.FromPtr(client.Email),
+			Phone:   utils.FromPtr(client.Phone),
+			ABN:     utils.FromPtr(client.Abn),
+		}
+	}
+
+	retainer := s.retainerForPeriod(client, period, fromDate, toDate)
+	totals := s.calculateInvoiceTotals(client, sessions, expenses, milestones, retainer)
+	totals.roundTotal(invoice.Rounding)
+	if totals.retainer.GreaterThan(decimal.Zero) {
+		doc.Totals = append(doc.Totals, invoiceTotal{Label: fmt.Sprintf("Retainer (%s)", retainer.label(period, s.retainerProration())), Amount: m.Format(totals.retainer)})
+	}
+	if totals.sessions.GreaterThan(decimal.Zero) {
+		doc.Totals = append(doc.Totals, invoiceTotal{Label: "Session Work", Amount: m.Format(totals.sessions)})
+	}
+	if totals.milestones.GreaterThan(decimal.Zero) {
+		doc.Totals = append(doc.Totals, invoiceTotal{Label: "Milestones", Amount: m.Format(totals.milestones)})
+	}
+	if totals.expenses.GreaterThan(decimal.Zero) {
+		doc.Totals = append(doc.Totals, invoiceTotal{Label: "Expenses", Amount: m.Format(totals.expenses)})
+	}
+	doc.Totals = append(doc.Totals, invoiceTotal{Label: "Subtotal", Amount: m.Format(totals.subtotal)})
+	// GST rounded per line can differ from 10% of the subtotal by a few cents, which is shown as an adjustment
+	// so each line adds up
+	if s.chargesGST(client) {
+		doc.Totals = append(doc.Totals, invoiceTotal{Label: "GST (10%)", Amount: m.Format(totals.gst.Sub(totals.gstAdjustment))})
+		if !totals.gstAdjustment.IsZero() {
+			doc.Totals = append(doc.Totals, invoiceTotal{Label: "GST rounding adjustment", Amount: m.Format(totals.gstAdjustment)})
+		}
+	} else if label := s.gstFreeLabel(client); label != "" {
+		doc.Totals = append(doc.Totals, invoiceTotal{Label: fmt.Sprintf("GST (%s)", label), Amount: m.Format(decimal.Zero)})
+	}
+	if !totals.totalAdjustment.IsZero() {
+		doc.Totals = append(doc.Totals, invoiceTotal{Label: "Rounding adjustment", Amount: m.Format(totals.totalAdjustment)})
+	}
+	doc.Totals = append(doc.Totals, invoiceTotal{Label: "Total", Amount: m.Format(totals.total), Grand: true})
+
+	if hasEarlyDiscount(client) {
+		discount := earlyDiscountAmount(client, totals.total)
+		doc.EarlyDiscount = fmt.Sprintf("Early payment discount: %s (%s) if paid by %s, making the total %s",
+			earlyDiscountTerms(client), m.Format(discount), earlyDiscountDeadline(client, invoice).Format("2 January 2006"),
+			m.Format(totals.total.Sub(discount)))
+	}
+	doc.PaymentTerms = utils.FromPtr(invoice.PaymentTerms)
+	doc.Notes = utils.FromPtr(invoice.Notes)
+
+	// Days with more than one session are subtotalled when asked for, which only applies to sessions listed one
+	// per line as they're in order of when they started
+	daySubtotals := s.cfg.InvoiceDaySubtotals && doc.BySession()
+	var dayDuration time.Duration
+	dayAmount, dayLines := decimal.Zero, 0
+	lines := s.invoiceLines(sessions, retainer, invoice.GroupBy, m.Format)
+	for i, line := range lines {
+		start, end := line.when(invoice.GroupBy)
+		documentLine := invoiceDocumentLine{
+			Start:       start,
+			End:         end,
+			Duration:    s.FormatDurationFor(config.DurationContextInvoice, line.duration),
+			Rate:        line.rate,
+			Description: line.description(),
+			Amount:      m.Format(line.amount),
+		}
+		if withAppendix {
+			documentLine.Number = i + 1
+			if entry := s.invoiceAppendixEntry(documentLine.Number, line); entry != nil {
+				doc.Appendix = append(doc.Appendix, *entry)
+				documentLine.Summarised = true
+			}
+		}
+		doc.Lines = append(doc.Lines, documentLine)
+
+		if !daySubtotals {
+			continue
+		}
+		dayDuration += line.duration
+		dayAmount = dayAmount.Add(line.amount)
+		dayLines++
+		day := line.from.Format("2006-01-02")
+		if i+1 < len(lines) && lines[i+1].from.Format("2006-01-02") == day {
+			continue
+		}
+		if dayLines > 1 {
+			doc.Lines = append(doc.Lines, invoiceDocumentLine{
+				Start:    "Subtotal " + day,
+				Duration: s.FormatDurationFor(config.DurationContextInvoice, dayDuration),
+				Amount:   m.Format(dayAmount),
+				Subtotal: true,
+			})
+		}
+		dayDuration, dayAmount, dayLines = 0, decimal.Zero, 0
+	}
+
+	for _, milestone := range milestones {
+		completed := ""
+		if milestone.CompletedDate != nil {
+			completed = milestone.CompletedDate.Format("2006-01-02")
+		}
+		doc.Milestones = append(doc.Milestones, invoiceDocumentMilestone{
+			Completed: completed,
+			Name:      milestone.Name,
+			Amount:    m.Format(milestone.Amount),
+		})
+	}
+
+	categorised := false
+	for _, expense := range expenses {
+		category := ""
+		if expense.Category != nil && *expense.Category != "" {
+			category = *expense.Category
+			categorised = true
+		}
+		reference := utils.FromPtr(expense.Reference)
+		if mileage := mileageLabel(expense, m.Format); mileage != "" && reference != "" {
+			reference = mileage + " - " + reference
+		} else if mileage != "" {
+			reference = mileage
+		}
+		doc.Expenses = append(doc.Expenses, invoiceDocumentExpense{
+			Date:      expense.ExpenseDate.Format("2006-01-02"),
+			Category:  category,
+			Cost:      m.Format(expense.Amount),
+			Billed:    m.Format(ExpenseBilledAmount(expense)),
+			Reference: reference,
+		})
+	}
+	if categorised {
+		for _, subtotal := range s.calculateExpenseCategorySubtotals(expenses, ExpenseBilledAmount) {
+			doc.ExpenseSubtotals = append(doc.ExpenseSubtotals, invoiceField{subtotal.category, m.Format(subtotal.total)})
+		}
+	}
+
+	if retainer.applies() {
+		doc.RetainerNote = fmt.Sprintf("* First %s hours covered by %s retainer", retainer.hours.StringFixed(1), period)
+		if retainer.prorated() {
+			doc.RetainerNote += fmt.Sprintf(", prorated from %s as it started on %s", m.Format(*client.RetainerAmount), client.RetainerStart.Format("2 January 2006"))
+		}
+	}
+	return doc
+}
+
+// invoiceAppendixEntry returns the full work summaries of the sessions on a line, or nil when none of them has one
+func (s *TimesheetService) invoiceAppendixEntry(number int, line invoiceLine) *invoiceAppendixEntry {
+	entry := &invoiceAppendixEntry{Line: number}
+	for _, session := range line.sessions {
+		if session.FullWorkSummary == nil {
+			continue
+		}
+		summary := s.cleanWorkSummary(*session.FullWorkSummary)
+		if len(summary) == 0 {
+			continue
+		}
+		when := session.StartTime.Format("2006-01-02 15:04")
+		if session.EndTime != nil {
+			when += " to " + session.EndTime.Format("15:04")
+		}
+		entry.Sessions = append(entry.Sessions, invoiceAppendixSession{When: when, Lines: summary})
+	}
+	if len(entry.Sessions) == 0 {
+		return nil
+	}
+	return entry
+}
+
+// cleanWorkSummary turns a full work summary into plain lines of text for a client to read, with markdown
+// headings, emphasis and code marks and the "=== repo ===" banners of commit logs removed. Runs of blank lines
+// become a single empty line between paragraphs.
+func (s *TimesheetService) cleanWorkSummary(summary string) []string {
+	var lines []string
+	for _, line := range strings.Split(s.formatSummaryWithBreaks(summary), "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimLeft(line, "#"))
+		if strings.HasPrefix(line, "===") && strings.HasSuffix(line, "===") {
+			line = strings.TrimSpace(strings.Trim(line, "="))
+		}
+		line = strings.NewReplacer("**", "", "__", "", "`", "").Replace(line)
+		if strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "• ") {
+			line = "- " + strings.TrimSpace(line[strings.Index(line, " ")+1:])
+		}
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
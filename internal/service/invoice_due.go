@@ -0,0 +1,50 @@
+package service
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// paymentTermsDaysPattern finds the number of days given to pay in payment terms, e.g. "Payment due within 14
+// days" or "Net 30"
+var paymentTermsDaysPattern = regexp.MustCompile(`(?i)(?:\bnet\s*(\d+)\b|\b(\d+)\s*days?\b)`)
+
+// paymentTermsDays is the number of days after issue payment terms give to pay, and whether they say
+func paymentTermsDays(terms string) (int, bool) {
+	if strings.Contains(strings.ToLower(terms), "on receipt") {
+		return 0, true
+	}
+	match := paymentTermsDaysPattern.FindStringSubmatch(terms)
+	if match == nil {
+		return 0, false
+	}
+	days, err := strconv.Atoi(match[1] + match[2])
+	if err != nil {
+		return 0, false
+	}
+	return days, true
+}
+
+// invoiceDueDate is the last day an invoice can be paid on before it's overdue: the number of days its payment
+// terms give after it was issued, or PAYMENT_DUE_DAYS when they don't say
+func (s *TimesheetService) invoiceDueDate(invoice *models.Invoice) time.Time {
+	days, ok := paymentTermsDays(utils.FromPtr(invoice.PaymentTerms))
+	if !ok && s.cfg != nil {
+		days = s.cfg.PaymentDueDays
+	}
+	return dateOnly(invoice.GeneratedDate).AddDate(0, 0, days)
+}
+
+// invoiceDaysOverdue is how many days past its due date an invoice is on date, zero when it isn't due yet,
+// nothing is owed on it or it's a draft that hasn't been sent
+func (s *TimesheetService) invoiceDaysOverdue(invoice *models.Invoice, date time.Time) int {
+	if invoice.Status == models.InvoiceStatusDraft || !invoiceBalance(invoice).IsPositive() {
+		return 0
+	}
+	return max(countDays(s.invoiceDueDate(invoice), dateOnly(date))-1, 0)
+}
@@ -0,0 +1,32 @@
+package service
+
+import "testing"
+
+func TestPaymentTermsDays(t *testing.T) {
+	tests := []struct {
+		terms string
+		days  int
+		ok    bool
+	}{
+		{"Payment due within 14 days", 14, true},
+		{"Net 30", 30, true},
+		{"net30, by bank transfer", 30, true},
+		{"Due on receipt", 0, true},
+		{"Please pay by bank transfer", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		days, ok := paymentTermsDays(tt.terms)
+		if days != tt.days || ok != tt.ok {
+			t.Errorf("paymentTermsDays(%q) = %d, %v, want %d, %v", tt.terms, days, ok, tt.days, tt.ok)
+		}
+	}
+}
+
+func TestAgingBucket(t *testing.T) {
+	for days, want := range map[int]string{0: "Current", 1: "1-30", 30: "1-30", 31: "31-60", 90: "61-90", 91: "90+", 400: "90+"} {
+		if got := agingBuckets[agingBucket(days)].label; got != want {
+			t.Errorf("agingBucket(%d) = %s, want %s", days, got, want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// invoiceHTMLTemplate lays out an invoiceDocument as a single page that can be hosted or pasted into an email
+// body. Styles are inline because most email clients drop <style> blocks, and the tables shrink to the width of
+// a phone with the session details scrolling sideways. Headings are in the brand colour, but the logo is left to
+// PDFs as a local image can't be shown once the page is sent.
+var invoiceHTMLTemplate = template.Must(template.New("invoice").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="keywords" content="invoice:{{.Number}}">
+<title>{{.Title}} ({{.Number}})</title>
+</head>
+<body style="margin:0;padding:0;background:#f4f4f4;font-family:Arial,Helvetica,sans-serif;color:#222;">
+<div style="max-width:720px;margin:0 auto;padding:16px;background:#fff;">
+<h1 style="font-size:22px;margin:0 0 4px;{{with $.BrandColour}}color:{{.}};{{end}}">{{.CompanyName}}</h1>
+{{- if .ABN}}
+<p style="font-size:13px;margin:0 0 16px;">{{.ABN}}</p>
+{{- end}}
+<h2 style="font-size:18px;margin:16px 0 4px;{{with $.BrandColour}}color:{{.}};{{end}}">{{.Title}}</h2>
+<p style="font-size:14px;margin:0 0 16px;">Invoice Number: {{.Number}}
+{{- range .References}}<br><strong>{{.Label}}: {{.Value}}</strong>{{end}}</p>
+{{- with .BillTo}}
+<h3 style="font-size:15px;margin:16px 0 4px;{{with $.BrandColour}}color:{{.}};{{end}}">Bill To:</h3>
+<p style="font-size:14px;margin:0 0 16px;line-height:1.4;">
+{{- if .Contact}}{{.Contact}}<br>{{end}}
+{{- if .Company}}{{.Company}}<br>{{end}}
+{{- if .Address}}{{.Address}}<br>{{end}}
+{{- if .Email}}{{.Email}}<br>{{end}}
+{{- if .Phone}}{{.Phone}}<br>{{end}}
+{{- if .ABN}}ABN {{.ABN}}{{end}}
+</p>
+{{- end}}
+<table role="presentation" style="width:100%;border-collapse:collapse;font-size:14px;margin:0 0 16px;">
+{{- range .Totals}}
+<tr{{if .Grand}} style="font-weight:bold;font-size:16px;border-top:1px solid #222;{{with $.BrandColour}}color:{{.}};{{end}}"{{end}}>
+<td style="padding:4px 0;">{{.Label}}:</td>
+<td style="padding:4px 0;text-align:right;white-space:nowrap;">{{.Amount}}</td>
+</tr>
+{{- end}}
+</table>
+{{- if .EarlyDiscount}}
+<p style="font-size:13px;margin:0 0 16px;">{{.EarlyDiscount}}</p>
+{{- end}}
+<h3 style="font-size:15px;margin:16px 0 4px;{{with $.BrandColour}}color:{{.}};{{end}}">Payment Details</h3>
+<table role="presentation" style="border-collapse:collapse;font-size:14px;margin:0 0 16px;">
+{{- range .Payment}}
+<tr><td style="padding:2px 12px 2px 0;">{{.Label}}:</td><td style="padding:2px 0;">{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- if .PaymentTerms}}
+<h3 style="font-size:15px;margin:16px 0 4px;{{with $.BrandColour}}color:{{.}};{{end}}">Payment Terms</h3>
+<p style="font-size:14px;margin:0 0 16px;white-space:pre-line;">{{.PaymentTerms}}</p>
+{{- end}}
+{{- if .Notes}}
+<h3 style="font-size:15px;margin:16px 0 4px;{{with $.BrandColour}}color:{{.}};{{end}}">Notes</h3>
+<p style="font-size:14px;margin:0 0 16px;white-space:pre-line;">{{.Notes}}</p>
+{{- end}}
+{{- if .Lines}}
+<h3 style="font-size:15px;margin:24px 0 4px;{{with $.BrandColour}}color:{{.}};{{end}}">{{.SessionsHeading}}</h3>
+<div style="overflow-x:auto;">
+<table style="width:100%;border-collapse:collapse;font-size:13px;">
+<tr style="background:#eee;text-align:left;">
+{{- if .Numbered}}
+<th style="padding:6px;">#</th>
+{{- end}}
+{{- if .BySession}}
+<th style="padding:6px;">Start</th><th style="padding:6px;">End</th>
+{{- else}}
+<th style="padding:6px;">{{.WhenHeading}}</th>
+{{- end}}
+<th style="padding:6px;">Duration</th><th style="padding:6px;">Rate</th><th style="padding:6px;">Description</th><th style="padding:6px;text-align:right;">Amount</th>
+</tr>
+{{- range .Lines}}
+{{- if .Subtotal}}
+<tr style="border-bottom:1px solid #ddd;font-weight:bold;">
+<td colspan="{{if $.Numbered}}3{{else}}2{{end}}" style="padding:6px;text-align:right;white-space:nowrap;">{{.Start}}</td>
+<td style="padding:6px;white-space:nowrap;">{{.Duration}}</td>
+<td colspan="2" style="padding:6px;"></td>
+<td style="padding:6px;text-align:right;white-space:nowrap;">{{.Amount}}</td>
+</tr>
+{{- else}}
+<tr style="border-bottom:1px solid #ddd;vertical-align:top;">
+{{- if $.Numbered}}
+<td style="padding:6px;">{{if .Summarised}}<a href="#line-{{.Number}}">{{.Number}}</a>{{else}}{{.Number}}{{end}}</td>
+{{- end}}
+{{- if $.BySession}}
+<td style="padding:6px;white-space:nowrap;">{{.Start}}</td><td style="padding:6px;white-space:nowrap;">{{.End}}</td>
+{{- else}}
+<td style="padding:6px;white-space:nowrap;">{{.Start}}</td>
+{{- end}}
+<td style="padding:6px;white-space:nowrap;">{{.Duration}}</td>
+<td style="padding:6px;white-space:nowrap;">{{.Rate}}</td>
+<td style="padding:6px;">{{.Description}}</td>
+<td style="padding:6px;text-align:right;white-space:nowrap;">{{.Amount}}</td>
+</tr>
+{{- end}}
+{{- end}}
+</table>
+</div>
+{{- end}}
+{{- if .Milestones}}
+<h3 style="font-size:15px;margin:24px 0 4px;{{with $.BrandColour}}color:{{.}};{{end}}">Milestones</h3>
+<div style="overflow-x:auto;">
+<table style="width:100%;border-collapse:collapse;font-size:13px;">
+<tr style="background:#eee;text-align:left;">
+<th style="padding:6px;">Completed</th><th style="padding:6px;">Milestone</th><th style="padding:6px;text-align:right;">Amount</th>
+</tr>
+{{- range .Milestones}}
+<tr style="border-bottom:1px solid #ddd;vertical-align:top;">
+<td style="padding:6px;white-space:nowrap;">{{.Completed}}</td>
+<td style="padding:6px;">{{.Name}}</td>
+<td style="padding:6px;text-align:right;white-space:nowrap;">{{.Amount}}</td>
+</tr>
+{{- end}}
+</table>
+</div>
+{{- end}}
+{{- if .Expenses}}
+<h3 style="font-size:15px;margin:24px 0 4px;{{with $.BrandColour}}color:{{.}};{{end}}">Expenses</h3>
+<div style="overflow-x:auto;">
+<table style="width:100%;border-collapse:collapse;font-size:13px;">
+<tr style="background:#eee;text-align:left;">
+<th style="padding:6px;">Date</th><th style="padding:6px;">Category</th><th style="padding:6px;text-align:right;">Cost</th><th style="padding:6px;text-align:right;">Billed</th><th style="padding:6px;">Reference</th>
+</tr>
+{{- range .Expenses}}
+<tr style="border-bottom:1px solid #ddd;vertical-align:top;">
+<td style="padding:6px;white-space:nowrap;">{{.Date}}</td>
+<td style="padding:6px;">{{.Category}}</td>
+<td style="padding:6px;text-align:right;white-space:nowrap;">{{.Cost}}</td>
+<td style="padding:6px;text-align:right;white-space:nowrap;">{{.Billed}}</td>
+<td style="padding:6px;">{{.Reference}}</td>
+</tr>
+{{- end}}
+</table>
+</div>
+{{- if .ExpenseSubtotals}}
+<table role="presentation" style="border-collapse:collapse;font-size:13px;margin:8px 0 0;">
+{{- range .ExpenseSubtotals}}
+<tr><td style="padding:2px 12px 2px 0;">{{.Label}}:</td><td style="padding:2px 0;text-align:right;">{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+{{- if .RetainerNote}}
+<p style="font-size:12px;font-style:italic;margin:16px 0 0;">{{.RetainerNote}}</p>
+{{- end}}
+{{- if .Appendix}}
+<h3 style="font-size:15px;margin:24px 0 4px;{{with $.BrandColour}}color:{{.}};{{end}}">Appendix: Work Summaries</h3>
+{{- range .Appendix}}
+<h4 id="{{.Anchor}}" style="font-size:14px;margin:16px 0 4px;">Line {{.Line}}</h4>
+{{- range .Sessions}}
+<p style="font-size:13px;font-weight:bold;margin:8px 0 2px;">{{.When}}</p>
+<p style="font-size:13px;margin:0 0 8px;white-space:pre-line;">{{range $i, $line := .Lines}}{{if $i}}
+{{end}}{{$line}}{{end}}</p>
+{{- end}}
+{{- end}}
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+// renderInvoiceHTML writes the invoice as a standalone HTML page
+func renderInvoiceHTML(w io.Writer, doc *invoiceDocument) error {
+	if err := invoiceHTMLTemplate.Execute(w, doc); err != nil {
+		return fmt.Errorf("failed to render invoice HTML: %w", err)
+	}
+	return nil
+}
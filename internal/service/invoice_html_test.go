@@ -0,0 +1,115 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+func TestRenderInvoiceHTML(t *testing.T) {
+	doc := &invoiceDocument{
+		Number: "INV-acme-month-2025-10-01",
+		Title:  "Invoice - Acme",
+		Totals: []invoiceTotal{{Label: "Total", Amount: "$1,300.00", Grand: true}},
+		Lines:  []invoiceDocumentLine{{Start: "2025-10-01 09:00", End: "2025-10-01 11:00", Duration: "2h", Rate: "$200.00", Description: "Fix <script> & styles", Amount: "$400.00"}},
+	}
+
+	var out strings.Builder
+	if err := renderInvoiceHTML(&out, doc); err != nil {
+		t.Fatalf("renderInvoiceHTML() error = %v", err)
+	}
+	html := out.String()
+
+	// The keywords let resolveInvoiceFileName recognise the file as this invoice's when regenerating
+	if !strings.Contains(html, invoicePDFKeyword(doc.Number)) {
+		t.Errorf("HTML is missing the invoice keyword %q", invoicePDFKeyword(doc.Number))
+	}
+	if !strings.Contains(html, "Fix &lt;script&gt; &amp; styles") {
+		t.Error("session description was not escaped")
+	}
+	if !strings.Contains(html, "$1,300.00") {
+		t.Error("HTML is missing the total")
+	}
+}
+
+func TestInvoiceDocumentSubtotalsDaysWithSeveralSessions(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{BillingCurrency: "AUD", BillingLocale: "en-AU", InvoiceDaySubtotals: true})
+	day := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+	sessions := []*models.WorkSession{
+		newPrecisionSession(day, time.Hour, 0, "100"),
+		newPrecisionSession(day.Add(2*time.Hour), 30*time.Minute, 0, "100"),
+		newPrecisionSession(day.AddDate(0, 0, 1), time.Hour, 0, "100"),
+	}
+	invoice := &models.Invoice{InvoiceNumber: "INV-acme-month-2025-10-01", GroupBy: models.InvoiceGroupBySession}
+
+	doc := s.buildInvoiceDocument(invoice, &models.Client{Name: "acme"}, sessions, nil, nil, "month", day, day.AddDate(0, 1, -1), false)
+	var subtotals []invoiceDocumentLine
+	for _, line := range doc.Lines {
+		if line.Subtotal {
+			subtotals = append(subtotals, line)
+		}
+	}
+	if len(doc.Lines) != 4 || len(subtotals) != 1 || !doc.Lines[2].Subtotal {
+		t.Fatalf("lines = %+v, want a subtotal after the first day's two sessions only", doc.Lines)
+	}
+	if subtotals[0].Start != "Subtotal 2025-10-01" || subtotals[0].Amount != "$150.00" {
+		t.Errorf("subtotal = %+v, want Subtotal 2025-10-01 of $150.00", subtotals[0])
+	}
+}
+
+func TestInvoiceDocumentAppendixReferencesLines(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{BillingCurrency: "AUD", BillingLocale: "en-AU"})
+	day := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+	summary := "## Login\n\n**Added** the `login` endpoint\n\n\n* Fixed tests\n"
+	sessions := []*models.WorkSession{
+		newPrecisionSession(day, time.Hour, 0, "100"),
+		newPrecisionSession(day.Add(2*time.Hour), time.Hour, 0, "100"),
+	}
+	sessions[1].FullWorkSummary = &summary
+	invoice := &models.Invoice{InvoiceNumber: "INV-acme-month-2025-10-01", GroupBy: models.InvoiceGroupBySession}
+
+	doc := s.buildInvoiceDocument(invoice, &models.Client{Name: "acme"}, sessions, nil, nil, "month", day, day.AddDate(0, 1, -1), true)
+	if doc.Lines[0].Number != 1 || doc.Lines[0].Summarised || doc.Lines[1].Number != 2 || !doc.Lines[1].Summarised {
+		t.Fatalf("lines = %+v, want both numbered and only the second summarised", doc.Lines)
+	}
+	if len(doc.Appendix) != 1 || doc.Appendix[0].Line != 2 || len(doc.Appendix[0].Sessions) != 1 {
+		t.Fatalf("appendix = %+v, want one entry for line 2", doc.Appendix)
+	}
+	want := []string{"Login", "", "Added the login endpoint", "", "- Fixed tests"}
+	if got := doc.Appendix[0].Sessions[0].Lines; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("summary lines = %q, want %q", got, want)
+	}
+
+	var out strings.Builder
+	if err := renderInvoiceHTML(&out, doc); err != nil {
+		t.Fatalf("renderInvoiceHTML() error = %v", err)
+	}
+	if html := out.String(); !strings.Contains(html, `<a href="#line-2">2</a>`) || !strings.Contains(html, `id="line-2"`) {
+		t.Error("HTML doesn't link line 2 to its appendix entry")
+	}
+}
+
+func TestInvoiceDocumentRoundsAsIssued(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{BillingCurrency: "AUD", BillingLocale: "en-AU"})
+	day := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+	sessions := []*models.WorkSession{newPrecisionSession(day, time.Hour, 0, "101.30")}
+	issuedRounding, currentRounding := decimal.NewFromInt(5), decimal.NewFromInt(1)
+	invoice := &models.Invoice{InvoiceNumber: "INV-acme-month-2025-10-01", GroupBy: models.InvoiceGroupBySession, Rounding: &issuedRounding}
+
+	// The client's rounding has changed since, which mustn't change the total on the regenerated invoice
+	doc := s.buildInvoiceDocument(invoice, &models.Client{Name: "acme", InvoiceRounding: &currentRounding}, sessions, nil, nil, "month", day, day.AddDate(0, 1, -1), false)
+	var total string
+	for _, line := range doc.Totals {
+		if line.Grand {
+			total = line.Amount
+		}
+	}
+	if total != "$100.00" {
+		t.Errorf("total = %q, want $100.00 rounded to the nearest 5 it was issued with", total)
+	}
+}
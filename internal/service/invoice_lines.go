@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// InvoiceGroupings are the accepted values for invoices generate --group-by
+var InvoiceGroupings = []string{models.InvoiceGroupBySession, models.InvoiceGroupByDay, models.InvoiceGroupByDescription}
+
+// ValidateInvoiceGroupBy returns an error if groupBy isn't one of InvoiceGroupings
+func ValidateInvoiceGroupBy(groupBy string) error {
+	for _, valid := range InvoiceGroupings {
+		if groupBy == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid grouping %q, expected one of: %s", groupBy, strings.Join(InvoiceGroupings, ", "))
+}
+
+// invoiceLine is a row of an invoice's session details, either one session or the sessions grouped into it
+type invoiceLine struct {
+	from, to     time.Time // the session's start and end, or the first and last session grouped into the line
+	duration     time.Duration
+	rate         string
+	descriptions []string
+	amount       decimal.Decimal
+	sessions     []*models.WorkSession // the sessions billed on the line, in order
+}
+
+// when is what the line covers: the session's start and end, the day, or the range of days sessions with the
+// same description were worked on
+func (l invoiceLine) when(groupBy string) (string, string) {
+	switch groupBy {
+	case models.InvoiceGroupByDay:
+		return l.from.Format("2006-01-02"), ""
+	case models.InvoiceGroupByDescription:
+		if first, last := l.from.Format("2006-01-02"), l.to.Format("2006-01-02"); first != last {
+			return first + " to " + last, ""
+		}
+		return l.from.Format("2006-01-02"), ""
+	default:
+		return l.from.Format("2006-01-02 15:04"), l.to.Format("2006-01-02 15:04")
+	}
+}
+
+func (l invoiceLine) description() string {
+	return strings.Join(l.descriptions, "; ")
+}
+
+// invoiceLines works out each session's billed amount, with hours covered by the retainer billed at nothing, and
+// returns them as invoice rows, summing durations and amounts into one row per day or per description when
+// grouped. Rows are in the order of their first session.
+func (s *TimesheetService) invoiceLines(sessions []*models.WorkSession, retainer retainerTerms, groupBy string, formatMoney func(decimal.Decimal) string) []invoiceLine {
+	var lines []invoiceLine
+	groups := make(map[string]int)
+
+	// Track cumulative hours for retainer calculation
+	var cumulativeHours decimal.Decimal
+
+	for _, session := range sessions {
+		duration := s.CalculateDuration(session)
+		sessionHours := durationHours(duration)
+
+		// Calculate effective rate and amount considering retainer
+		effectiveRate := decimal.Zero
+		amount := decimal.Zero
+
+		if session.HourlyRate != nil && session.HourlyRate.GreaterThan(decimal.Zero) {
+			if retainer.applies() && cumulativeHours.LessThan(retainer.hours) {
+				// Session hours covered by retainer
+				if cumulativeHours.Add(sessionHours).LessThanOrEqual(retainer.hours) {
+					// Fully covered by retainer
+					effectiveRate = decimal.Zero
+					amount = decimal.Zero
+				} else {
+					// Partially covered by retainer
+					retainerCoveredHours := retainer.hours.Sub(cumulativeHours)
+					billableHours := sessionHours.Sub(retainerCoveredHours)
+					effectiveRate = *session.HourlyRate // Show original rate
+					amount = billableHours.Mul(*session.HourlyRate).Round(2)
+				}
+			} else {
+				// Not covered by retainer
+				effectiveRate = *session.HourlyRate
+				amount = billedAmount(duration, *session.HourlyRate)
+			}
+		}
+
+		cumulativeHours = sessionHours.Add(cumulativeHours)
+
+		// Show effective rate (retainer-adjusted)
+		rate := ""
+		if effectiveRate.GreaterThan(decimal.Zero) {
+			rate = formatMoney(effectiveRate)
+		} else if retainer.applies() && cumulativeHours.LessThanOrEqual(retainer.hours) {
+			rate = formatMoney(decimal.Zero) + "*" // Indicate retainer coverage
+		}
+
+		description := ""
+		if session.Description != nil {
+			description = *session.Description
+		}
+		// Add outside_git notes to description
+		if notes := sessionNotesText(session.OutsideGit, s.cfg.InvoiceNoteTimes); notes != "" {
+			if description != "" {
+				description += "\n"
+			}
+			description += notes
+		}
+
+		line := invoiceLine{
+			from:     session.StartTime,
+			to:       session.StartTime,
+			duration: duration,
+			rate:     rate,
+			amount:   amount,
+			sessions: []*models.WorkSession{session},
+		}
+		if session.EndTime != nil {
+			line.to = *session.EndTime
+		}
+		if description != "" {
+			line.descriptions = []string{description}
+		}
+
+		var key string
+		switch groupBy {
+		case models.InvoiceGroupByDay:
+			key = session.StartTime.Format("2006-01-02")
+		case models.InvoiceGroupByDescription:
+			key = strings.ToLower(strings.TrimSpace(description))
+		default:
+			lines = append(lines, line)
+			continue
+		}
+
+		i, ok := groups[key]
+		if !ok {
+			groups[key] = len(lines)
+			lines = append(lines, line)
+			continue
+		}
+		group := &lines[i]
+		group.to = line.to
+		group.duration += line.duration
+		group.amount = group.amount.Add(line.amount)
+		group.sessions = append(group.sessions, session)
+		if group.rate != line.rate {
+			group.rate = "Various"
+		}
+		if description != "" && !containsFold(group.descriptions, description) {
+			group.descriptions = append(group.descriptions, description)
+		}
+	}
+	return lines
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
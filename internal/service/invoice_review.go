@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// InvoiceReview is what to do with a new invoice after reviewing its preview
+type InvoiceReview int
+
+const (
+	// InvoiceReviewAccept creates the invoice and writes its files
+	InvoiceReviewAccept InvoiceReview = iota
+	// InvoiceReviewSkip leaves the client's work uninvoiced and moves on to the next client
+	InvoiceReviewSkip
+	// InvoiceReviewAbort stops generating, keeping the invoices already accepted
+	InvoiceReviewAbort
+)
+
+// ErrInvoiceReviewAborted is returned when generating is aborted during review. Invoices accepted before
+// it are kept and returned in the run.
+var ErrInvoiceReviewAborted = errors.New("invoice generation aborted during review")
+
+// InvoiceReviewer is shown each new invoice before it's created in the database, and decides what to do with it.
+// Session descriptions can be corrected with EditInvoicePreviewDescription before accepting.
+type InvoiceReviewer func(ctx context.Context, preview *InvoicePreview) (InvoiceReview, error)
+
+// InvoicePreview is a client's invoice as it will be created, before it has been given an ID
+type InvoicePreview struct {
+	Invoice  *models.Invoice
+	Client   *models.Client
+	Sessions []*models.WorkSession
+
+	expenses   []*models.Expense
+	milestones []*models.Milestone
+	period     string
+	fromDate   time.Time
+	toDate     time.Time
+}
+
+// DisplayInvoicePreview shows what a new invoice will have on it: its lines as they'll be printed, milestones,
+// expenses, retainer and totals, worked out the same way as the invoice files
+func (s *TimesheetService) DisplayInvoicePreview(preview *InvoicePreview) {
+	doc := s.buildInvoiceDocument(preview.Invoice, preview.Client, preview.Sessions, preview.expenses, preview.milestones, preview.period, preview.fromDate, preview.toDate, false)
+
+	fmt.Fprintf(s.out, "\n%s for %s\n", doc.Number, preview.Client.Name)
+	fmt.Fprintln(s.out, strings.Repeat("-", 80))
+	for _, reference := range doc.References {
+		fmt.Fprintf(s.out, "%s: %s\n", reference.Label, reference.Value)
+	}
+
+	if len(doc.Lines) > 0 {
+		fmt.Fprintln(s.out, doc.SessionsHeading)
+		for _, line := range doc.Lines {
+			when := line.Start
+			if line.End != "" {
+				when += " - " + line.End
+			}
+			fmt.Fprintf(s.out, "  %-35s %8s %12s %12s  %s\n", when, line.Duration, line.Rate, line.Amount, line.Description)
+		}
+	}
+	if len(doc.Milestones) > 0 {
+		fmt.Fprintln(s.out, "Milestones")
+		for _, milestone := range doc.Milestones {
+			fmt.Fprintf(s.out, "  %-10s %-45s %12s\n", milestone.Completed, milestone.Name, milestone.Amount)
+		}
+	}
+	if len(doc.Expenses) > 0 {
+		fmt.Fprintln(s.out, "Expenses")
+		for _, expense := range doc.Expenses {
+			fmt.Fprintf(s.out, "  %-10s %-20s %12s %12s  %s\n", expense.Date, expense.Category, expense.Cost, expense.Billed, expense.Reference)
+		}
+	}
+
+	fmt.Fprintln(s.out)
+	for _, total := range doc.Totals {
+		fmt.Fprintf(s.out, "  %-45s %14s\n", total.Label, total.Amount)
+	}
+	for _, note := range []string{doc.RetainerNote, doc.EarlyDiscount, doc.PaymentTerms, doc.Notes} {
+		if note != "" {
+			fmt.Fprintln(s.out, note)
+		}
+	}
+}
+
+// EditInvoicePreviewDescription changes the description of the preview's session at index, saving it to the
+// session so the invoice is created with it
+func (s *TimesheetService) EditInvoicePreviewDescription(ctx context.Context, preview *InvoicePreview, index int, description string) error {
+	if index < 0 || index >= len(preview.Sessions) {
+		return fmt.Errorf("there's no session %d on the invoice, choose from 1 to %d", index+1, len(preview.Sessions))
+	}
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return fmt.Errorf("a description is required")
+	}
+	session := preview.Sessions[index]
+	updated, err := s.db.UpdateSessionDescription(ctx, session.ID, description, session.FullWorkSummary, false)
+	if err != nil {
+		return err
+	}
+	session.Description = updated.Description
+	return nil
+}
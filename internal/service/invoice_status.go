@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// invoiceClosed reports whether an invoice has been written off or voided, so nothing more is owed on it
+func invoiceClosed(invoice *models.Invoice) bool {
+	return invoice.Status == models.InvoiceStatusWrittenOff || invoice.Status == models.InvoiceStatusVoid
+}
+
+// checkInvoiceOpen returns an error saying why a closed invoice can't be changed by action, e.g. "paid"
+func checkInvoiceOpen(invoice *models.Invoice, action string) error {
+	switch invoice.Status {
+	case models.InvoiceStatusVoid:
+		return fmt.Errorf("invoice %s is void and can't be %s", invoice.InvoiceNumber, action)
+	case models.InvoiceStatusWrittenOff:
+		return fmt.Errorf("invoice %s has been written off and can't be %s", invoice.InvoiceNumber, action)
+	}
+	return nil
+}
+
+// checkInvoiceIssued is checkInvoiceOpen for payments and credit notes, which also can't go against a draft until
+// it's been issued
+func checkInvoiceIssued(invoice *models.Invoice, action string) error {
+	if invoice.Status == models.InvoiceStatusDraft {
+		return fmt.Errorf("invoice %s is a draft and can't be %s until it's issued with 'work invoices issue %s'",
+			invoice.InvoiceNumber, action, invoice.InvoiceNumber)
+	}
+	return checkInvoiceOpen(invoice, action)
+}
+
+// invoiceClosedSummary describes when and why an invoice was written off or voided, or is empty when it's open
+func invoiceClosedSummary(m money.Formatter, invoice *models.Invoice) string {
+	if !invoiceClosed(invoice) {
+		return ""
+	}
+	summary := "voided"
+	if invoice.Status == models.InvoiceStatusWrittenOff {
+		summary = fmt.Sprintf("wrote off %s", m.Format(invoice.AmountWrittenOff))
+	}
+	if invoice.StatusChangedAt != nil {
+		summary += " on " + invoice.StatusChangedAt.Format("2006-01-02")
+	}
+	if invoice.StatusReason != nil {
+		summary += ": " + *invoice.StatusReason
+	}
+	return summary
+}
+
+// VoidInvoice marks an invoice issued in error as void. It's treated as never issued, so it owes nothing and
+// is reversed out of GST in the period it's voided, and its sessions, expenses and milestones are taken off it so
+// they can be invoiced again. Invoices with payments can't be voided, credit them instead. The invoice can be given by
+// ID or number.
+func (s *TimesheetService) VoidInvoice(ctx context.Context, invoiceRef, reason string) error {
+	invoice, err := s.getInvoiceByIDOrNumber(ctx, invoiceRef)
+	if err != nil {
+		return err
+	}
+	if err := checkInvoiceOpen(invoice, "voided"); err != nil {
+		return err
+	}
+	m := s.clientMoneyByName(invoice.ClientName)
+	if invoice.AmountPaid.IsPositive() {
+		return fmt.Errorf("invoice %s has %s paid against it, credit it with 'work invoices credit %s' or delete the payments with 'work payments delete <payment-id>' first",
+			invoice.InvoiceNumber, m.Format(invoice.AmountPaid), invoice.InvoiceNumber)
+	}
+
+	sessions, err := s.db.GetSessionsByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get sessions for invoice %s: %w", invoice.InvoiceNumber, err)
+	}
+	expenses, err := s.db.GetExpensesByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get expenses for invoice %s: %w", invoice.InvoiceNumber, err)
+	}
+	milestones, err := s.db.GetMilestonesByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get milestones for invoice %s: %w", invoice.InvoiceNumber, err)
+	}
+
+	var reasonPtr *string
+	if reason = strings.TrimSpace(reason); reason != "" {
+		reasonPtr = &reason
+	}
+	if err := s.db.VoidInvoice(ctx, invoice.ID, reasonPtr, time.Now()); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.out, "Voided invoice %s for %s (%s)\n", invoice.InvoiceNumber, invoice.ClientName, m.Format(invoice.TotalAmount))
+	if len(sessions) > 0 || len(expenses) > 0 {
+		fmt.Fprintf(s.out, "%d session(s) and %d expense(s) were taken off it and can be invoiced again\n", len(sessions), len(expenses))
+	}
+	if len(milestones) > 0 {
+		fmt.Fprintf(s.out, "%d milestone(s) were taken off it and will be billed on the client's next invoice\n", len(milestones))
+	}
+	return nil
+}
+
+// WriteOffInvoice closes an invoice that will never be paid, giving up its remaining balance as a bad debt.
+// The reason is kept with the invoice. On an accrual basis the GST on the amount written off comes off GST on
+// sales in the period it's written off. The invoice can be given by ID or number.
+func (s *TimesheetService) WriteOffInvoice(ctx context.Context, invoiceRef, reason string, date time.Time) error {
+	invoice, err := s.getInvoiceByIDOrNumber(ctx, invoiceRef)
+	if err != nil {
+		return err
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return fmt.Errorf("a reason is required to write off an invoice")
+	}
+	if err := checkInvoiceOpen(invoice, "written off"); err != nil {
+		return err
+	}
+	m := s.clientMoneyByName(invoice.ClientName)
+	balance := invoiceBalance(invoice)
+	if !balance.IsPositive() {
+		return fmt.Errorf("invoice %s has nothing owed on it to write off", invoice.InvoiceNumber)
+	}
+
+	if date.IsZero() {
+		now := time.Now()
+		date = time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location())
+	}
+	if err := s.db.UpdateInvoiceStatus(ctx, invoice.ID, models.InvoiceStatusWrittenOff, &reason, date, balance); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.out, "Wrote off %s owed on invoice %s for %s: %s\n", m.Format(balance), invoice.InvoiceNumber, invoice.ClientName, reason)
+	if gst := writtenOffGST(invoice, balance); gst.IsPositive() {
+		fmt.Fprintf(s.out, "GST of %s on it comes off GST on sales for %s on an accrual basis\n", m.Format(gst), basQuarter(date))
+	}
+	return nil
+}
+
+// DraftInvoice marks an issued invoice that hasn't been sent to the client as a draft, so it isn't overdue or
+// reported for GST until it's issued. Invoices with payments or credit notes against them have already been sent.
+// The invoice can be given by ID or number.
+func (s *TimesheetService) DraftInvoice(ctx context.Context, invoiceRef string) error {
+	invoice, err := s.getInvoiceByIDOrNumber(ctx, invoiceRef)
+	if err != nil {
+		return err
+	}
+	if err := checkInvoiceOpen(invoice, "made a draft"); err != nil {
+		return err
+	}
+	switch {
+	case invoice.Status == models.InvoiceStatusDraft:
+		return fmt.Errorf("invoice %s is already a draft", invoice.InvoiceNumber)
+	case invoice.AmountPaid.IsPositive() || invoice.AmountCredited.IsPositive():
+		return fmt.Errorf("invoice %s has payments or credit notes against it, so it's already been sent", invoice.InvoiceNumber)
+	}
+
+	if err := s.db.UpdateInvoiceStatus(ctx, invoice.ID, models.InvoiceStatusDraft, nil, time.Now(), decimal.Zero); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Invoice %s for %s is a draft, issue it with 'work invoices issue %s' once it's sent\n",
+		invoice.InvoiceNumber, invoice.ClientName, invoice.InvoiceNumber)
+	return nil
+}
+
+// IssueInvoice marks a draft invoice issued once it's been sent to the client. The invoice can be given by ID or
+// number.
+func (s *TimesheetService) IssueInvoice(ctx context.Context, invoiceRef string) error {
+	invoice, err := s.getInvoiceByIDOrNumber(ctx, invoiceRef)
+	if err != nil {
+		return err
+	}
+	if invoice.Status != models.InvoiceStatusDraft {
+		return fmt.Errorf("invoice %s isn't a draft", invoice.InvoiceNumber)
+	}
+
+	if err := s.db.UpdateInvoiceStatus(ctx, invoice.ID, models.InvoiceStatusIssued, nil, time.Now(), decimal.Zero); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Issued invoice %s for %s\n", invoice.InvoiceNumber, invoice.ClientName)
+	return nil
+}
+
+// syncInvoicePaidStatus marks an issued invoice paid once payments settle it, and a paid one issued again when
+// a payment is deleted so something is owed
+func (s *TimesheetService) syncInvoicePaidStatus(ctx context.Context, invoiceID string) error {
+	invoice, err := s.db.GetInvoiceByID(ctx, invoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to get invoice: %w", err)
+	}
+	settled := invoice.AmountPaid.IsPositive() && !invoiceBalance(invoice).IsPositive()
+	switch {
+	case settled && invoice.Status == models.InvoiceStatusIssued:
+		return s.db.UpdateInvoiceStatus(ctx, invoice.ID, models.InvoiceStatusPaid, nil, time.Now(), decimal.Zero)
+	case !settled && invoice.Status == models.InvoiceStatusPaid:
+		return s.db.UpdateInvoiceStatus(ctx, invoice.ID, models.InvoiceStatusIssued, nil, time.Now(), decimal.Zero)
+	}
+	return nil
+}
+
+// writtenOffGST is the share of an invoice's GST in an amount written off it
+func writtenOffGST(invoice *models.Invoice, amount decimal.Decimal) decimal.Decimal {
+	if !invoice.GstAmount.IsPositive() || !invoice.TotalAmount.IsPositive() {
+		return decimal.Zero
+	}
+	return amount.Mul(invoice.GstAmount).Div(invoice.TotalAmount).Round(2)
+}
+
+// basQuarter names the calendar quarter containing t, e.g. 2025Q1
+func basQuarter(t time.Time) string {
+	return fmt.Sprintf("%dQ%d", t.Year(), (int(t.Month())-1)/3+1)
+}
@@ -1,8 +1,15 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,19 +18,58 @@ import (
 
 	"github.com/jesses-code-adventures/work/internal/db"
 	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
 )
 
-// GenerateInvoices generates PDF invoices for clients with billable hours
-func (s *TimesheetService) GenerateInvoices(ctx context.Context, period, date, clientName string) error {
-	// Parse the date
-	targetDate, err := time.Parse("2006-01-02", date)
+// InvoiceRun is what generating or regenerating invoices for a period did
+type InvoiceRun struct {
+	// Deleted are the invoices deleted to be generated again
+	Deleted []*models.Invoice
+	// Invoices are the invoices generated, or found already issued for the period and written again
+	Invoices []*GeneratedInvoice
+	// Overlapping are invoices for other ranges within the period, whose sessions stay on them
+	Overlapping []*models.Invoice
+	// Skipped are the clients whose new invoice was skipped during review, leaving their work uninvoiced
+	Skipped []*models.Client
+}
+
+// GeneratedInvoice is an invoice whose files were written by an InvoiceRun
+type GeneratedInvoice struct {
+	Invoice *models.Invoice
+	Client  *models.Client
+	Files   []string
+	// Existing is set when the invoice had already been issued for the period, and only its files were written
+	Existing bool
+}
+
+// GenerateInvoices generates invoices for clients with billable hours, either for the period containing date
+// or, when period is CustomPeriod, for the range from and to, writing a file in each of formats. Sessions are listed one per line or grouped
+// by groupBy, which is kept on the invoice. An empty groupBy keeps an existing invoice's grouping, and lists
+// new invoices one session per line. Notes are printed on the invoice in place of the client's invoice notes.
+// With withAppendix, each session's full work summary is appended after the session details. When review is
+// given, each new invoice is previewed with it before it's created. On an error, the run so far is returned with it.
+func (s *TimesheetService) GenerateInvoices(ctx context.Context, period, date, from, to, clientName, groupBy, notes string, references InvoiceReferences, formats []string, withAppendix bool, review InvoiceReviewer) (*InvoiceRun, error) {
+	run := &InvoiceRun{}
+	err := s.generateInvoices(ctx, run, period, date, from, to, clientName, groupBy, notes, references, formats, withAppendix, nil, review)
+	return run, err
+}
+
+// generateInvoices generates invoices as GenerateInvoices does, adding them to run and falling back to the
+// grouping, notes and payment terms of the previous invoices being regenerated, keyed by client ID
+func (s *TimesheetService) generateInvoices(ctx context.Context, run *InvoiceRun, period, date, from, to, clientName, groupBy, notes string, references InvoiceReferences, formats []string, withAppendix bool, previous map[string]*models.Invoice, review InvoiceReviewer) error {
+	if err := ValidateInvoiceFormats(formats); err != nil {
+		return err
+	}
+	if groupBy != "" {
+		if err := ValidateInvoiceGroupBy(groupBy); err != nil {
+			return err
+		}
+	}
+	fromDate, toDate, label, err := s.resolveInvoicePeriod(period, date, from, to)
 	if err != nil {
-		return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+		return err
 	}
 
-	// Calculate date range based on period
-	fromDate, toDate := s.CalculatePeriodRange(period, targetDate)
-
 	// Get sessions for the period that haven't been invoiced yet
 	var sessions []*models.WorkSession
 
@@ -66,15 +112,29 @@ func (s *TimesheetService) GenerateInvoices(ctx context.Context, period, date, c
 		allExpenses = filteredExpenses
 	}
 
+	// Completed milestones that haven't been invoiced go on the client's next invoice, whenever they were completed
+	// before the period ended
+	var clientID *string
+	if clientName != "" {
+		client, err := s.GetClientByName(ctx, clientName)
+		if err != nil {
+			return fmt.Errorf("failed to get client for milestones: %w", err)
+		}
+		clientID = &client.ID
+	}
+	milestones, err := s.db.GetCompletedMilestonesWithoutInvoice(ctx, toDate, clientID)
+	if err != nil {
+		return fmt.Errorf("failed to get uninvoiced milestones: %w", err)
+	}
+
 	// Group sessions by client and calculate totals
 	clientSessions := s.groupSessionsByClient(sessions)
+	clientMilestones := groupMilestonesByClient(milestones)
 
-	// Group expenses by client
-	clientExpenses := s.groupExpensesByClient(allExpenses)
-
-	invoiceCount := 0
+	// Group expenses by client, leaving unreviewed drafts and expenses that aren't passed on off the invoice
+	clientExpenses := s.groupExpensesByClient(invoiceableExpenses(allExpenses))
 
-	// Process all clients (from sessions and expenses)
+	// Process all clients (from sessions, expenses and milestones)
 	allClients := make(map[string]bool)
 	for clientName := range clientSessions {
 		allClients[clientName] = true
@@ -82,8 +142,29 @@ func (s *TimesheetService) GenerateInvoices(ctx context.Context, period, date, c
 	for clientName := range clientExpenses {
 		allClients[clientName] = true
 	}
+	for clientName := range clientMilestones {
+		allClients[clientName] = true
+	}
 
-	for clientName := range allClients {
+	// Clients already invoiced for exactly this range have their invoice's files written again, even once
+	// every session in it is invoiced
+	periodStartDate := time.Date(fromDate.Year(), fromDate.Month(), fromDate.Day(), 0, 0, 0, 0, fromDate.Location())
+	periodEndDate := time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
+	var invoicedClients []*models.Invoice
+	if clientName != "" {
+		invoicedClients, err = s.db.GetInvoicesByPeriodAndClient(ctx, periodStartDate, periodEndDate, period, clientName)
+	} else {
+		invoicedClients, err = s.db.GetInvoicesByPeriod(ctx, periodStartDate, periodEndDate, period)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check for existing invoices: %w", err)
+	}
+	for _, invoice := range invoicedClients {
+		allClients[invoice.ClientName] = true
+	}
+
+	// Clients are in order of name, so reviewing goes through them alphabetically
+	for _, clientName := range slices.Sorted(maps.Keys(allClients)) {
 		// Get client details for billing information first
 		client, err := s.GetClientByName(ctx, clientName)
 		if err != nil {
@@ -92,55 +173,99 @@ func (s *TimesheetService) GenerateInvoices(ctx context.Context, period, date, c
 
 		clientSessionList := clientSessions[clientName]
 		clientExpenseList := clientExpenses[clientName]
+		clientMilestoneList := clientMilestones[clientName]
 
-		// Calculate billable amounts with retainer consideration, separating GST-inclusive and GST-exclusive sessions
-		gstExclusiveSubtotal, gstInclusiveSubtotal, gstFromInclusiveSessions, retainerAmount := s.calculateClientTotalWithGSTSeparation(clientSessionList, client, period)
-
-		// Add expenses to GST-exclusive subtotal (expenses are typically GST-exclusive)
-		expenseTotal := s.calculateExpenseTotal(clientExpenseList)
-		gstExclusiveSubtotal = gstExclusiveSubtotal.Add(expenseTotal)
+		totalSubtotal, gstAmount, total, _ := s.calculateInvoiceAmounts(client, client.InvoiceRounding, clientSessionList, clientExpenseList, clientMilestoneList, s.retainerForPeriod(client, period, fromDate, toDate))
 
-		// Total subtotal (all GST-exclusive amounts)
-		totalSubtotal := gstExclusiveSubtotal.Add(gstInclusiveSubtotal).Add(retainerAmount)
+		// Check if invoice already exists for this period and client
+		existingInvoices, err := s.db.GetInvoicesByPeriodAndClient(ctx, periodStartDate, periodEndDate, period, clientName)
+		if err != nil {
+			return fmt.Errorf("failed to check for existing invoices for client %s: %w", clientName, err)
+		}
 
 		// Skip if no billable hours and no retainer
-		if totalSubtotal.LessThanOrEqual(decimal.Zero) {
+		if totalSubtotal.LessThanOrEqual(decimal.Zero) && len(existingInvoices) == 0 {
 			continue
 		}
 
-		// Calculate GST and total
-		var gstAmount decimal.Decimal
-		var total decimal.Decimal
-		if s.cfg.GSTRegistered {
-			// Calculate GST only on amounts that don't already include GST
-			gstFromExclusiveSessions := gstExclusiveSubtotal.Add(retainerAmount).Mul(decimal.NewFromFloat(0.1))
-			gstAmount = gstFromExclusiveSessions.Add(gstFromInclusiveSessions)
-			total = totalSubtotal.Add(gstAmount)
-		} else {
-			total = totalSubtotal
-		}
-
-		// Check if invoice already exists for this period and client
-		// Normalize dates for database queries
-		periodStartDate := time.Date(fromDate.Year(), fromDate.Month(), fromDate.Day(), 0, 0, 0, 0, fromDate.Location())
-		periodEndDate := time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
-
-		existingInvoices, err := s.db.GetInvoicesByPeriodAndClient(ctx, periodStartDate, periodEndDate, period, clientName)
-		if err != nil {
-			return fmt.Errorf("failed to check for existing invoices for client %s: %w", clientName, err)
+		previousInvoice := previous[client.ID]
+		clientGroupBy := groupBy
+		if clientGroupBy == "" && previousInvoice != nil {
+			clientGroupBy = previousInvoice.GroupBy
 		}
 
 		var invoice *models.Invoice
 		if len(existingInvoices) > 0 {
 			// Use existing invoice
 			invoice = existingInvoices[0]
-			fmt.Printf("Found existing invoice for %s: %s\n", clientName, invoice.InvoiceNumber)
+			if clientGroupBy != "" && clientGroupBy != invoice.GroupBy {
+				if err := s.db.UpdateInvoiceGroupBy(ctx, invoice.ID, clientGroupBy); err != nil {
+					return err
+				}
+				invoice.GroupBy = clientGroupBy
+			}
+			if notes != "" && (invoice.Notes == nil || *invoice.Notes != notes) {
+				if err := s.db.UpdateInvoiceNotes(ctx, invoice.ID, &notes); err != nil {
+					return err
+				}
+				invoice.Notes = &notes
+			}
+			if poNumber, projectCode, changed := references.applyTo(invoice); changed {
+				if err := s.db.UpdateInvoiceReferences(ctx, invoice.ID, poNumber, projectCode); err != nil {
+					return err
+				}
+				invoice.PoNumber, invoice.ProjectCode = poNumber, projectCode
+			}
 		} else {
 			// Generate invoice number and create new invoice
-			invoiceNumber := fmt.Sprintf("INV-%s-%s-%s", clientName, period, date)
+			invoiceNumber := fmt.Sprintf("INV-%s-%s-%s", clientName, period, label)
 			invoiceNumber = s.sanitizeFileName(invoiceNumber)
 
-			createdInvoice, err := s.db.CreateInvoice(ctx, client.ID, invoiceNumber, period, periodStartDate, periodEndDate, totalSubtotal, gstAmount, total)
+			if clientGroupBy == "" {
+				clientGroupBy = models.InvoiceGroupBySession
+			}
+			invoiceNotes, paymentTerms := invoiceNotesAndTerms(client, previousInvoice, notes)
+			poNumber, projectCode := references.forNewInvoice(client, previousInvoice)
+
+			if review != nil {
+				preview := &InvoicePreview{
+					Invoice: &models.Invoice{
+						ClientID:        client.ID,
+						InvoiceNumber:   invoiceNumber,
+						PeriodType:      period,
+						PeriodStartDate: periodStartDate,
+						PeriodEndDate:   periodEndDate,
+						GeneratedDate:   time.Now(),
+						GroupBy:         clientGroupBy,
+						Notes:           invoiceNotes,
+						PaymentTerms:    paymentTerms,
+						PoNumber:        poNumber,
+						ProjectCode:     projectCode,
+						Rounding:        client.InvoiceRounding,
+						ClientName:      clientName,
+					},
+					Client:     client,
+					Sessions:   clientSessionList,
+					expenses:   clientExpenseList,
+					milestones: clientMilestoneList,
+					period:     period,
+					fromDate:   fromDate,
+					toDate:     toDate,
+				}
+				decision, err := review(ctx, preview)
+				if err != nil {
+					return err
+				}
+				switch decision {
+				case InvoiceReviewSkip:
+					run.Skipped = append(run.Skipped, client)
+					continue
+				case InvoiceReviewAbort:
+					return ErrInvoiceReviewAborted
+				}
+			}
+
+			createdInvoice, err := s.db.CreateInvoice(ctx, client.ID, invoiceNumber, period, periodStartDate, periodEndDate, totalSubtotal, gstAmount, total, clientGroupBy, invoiceNotes, paymentTerms, poNumber, projectCode, client.InvoiceRounding)
 			if err != nil {
 				return fmt.Errorf("failed to create invoice record for %s: %w", clientName, err)
 			}
@@ -157,6 +282,12 @@ func (s *TimesheetService) GenerateInvoices(ctx context.Context, period, date, c
 				GeneratedDate:   createdInvoice.GeneratedDate,
 				CreatedAt:       createdInvoice.CreatedAt,
 				UpdatedAt:       createdInvoice.UpdatedAt,
+				GroupBy:         createdInvoice.GroupBy,
+				Notes:           createdInvoice.Notes,
+				PaymentTerms:    createdInvoice.PaymentTerms,
+				PoNumber:        createdInvoice.PoNumber,
+				ProjectCode:     createdInvoice.ProjectCode,
+				Rounding:        createdInvoice.Rounding,
 				ClientName:      clientName,
 			}
 
@@ -175,64 +306,104 @@ func (s *TimesheetService) GenerateInvoices(ctx context.Context, period, date, c
 					return fmt.Errorf("failed to update expense %s with invoice ID: %w", expense.ID, err)
 				}
 			}
+
+			for _, milestone := range clientMilestoneList {
+				err = s.db.UpdateMilestoneInvoiceID(ctx, milestone.ID, &invoice.ID)
+				if err != nil {
+					return fmt.Errorf("failed to update milestone %s with invoice ID: %w", milestone.ID, err)
+				}
+			}
 		}
 
 		// Get sessions for PDF generation (either from current period or from existing invoice)
 		var sessionsForPDF []*models.WorkSession
 		if len(existingInvoices) > 0 {
-			// For existing invoices, get sessions by invoice ID
+			// For existing invoices, get sessions, expenses and milestones by invoice ID
 			sessionsForPDF, err = s.db.GetSessionsByInvoiceID(ctx, invoice.ID)
 			if err != nil {
 				return fmt.Errorf("failed to get sessions for existing invoice %s: %w", invoice.ID, err)
 			}
+			clientExpenseList, err = s.db.GetExpensesByInvoiceID(ctx, invoice.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get expenses for existing invoice %s: %w", invoice.ID, err)
+			}
+			clientMilestoneList, err = s.db.GetMilestonesByInvoiceID(ctx, invoice.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get milestones for existing invoice %s: %w", invoice.ID, err)
+			}
 		} else {
 			// For new invoices, use the current period sessions
 			sessionsForPDF = clientSessionList
 		}
 
-		// Generate PDF invoice
-		fileName := fmt.Sprintf("invoice_%s_%s_%s.pdf", clientName, period, date)
+		// Generate the invoice files, named without an extension so each format adds its own
+		fileName := fmt.Sprintf("invoice_%s_%s_%s", clientName, period, label)
 		fileName = s.sanitizeFileName(fileName)
 
-		err = s.generateInvoicePDF(fileName, client, sessionsForPDF, clientExpenseList, period, fromDate, toDate, retainerAmount)
+		fileNames, err := s.generateInvoiceFiles(fileName, formats, invoice, client, sessionsForPDF, clientExpenseList, clientMilestoneList, period, fromDate, toDate, withAppendix)
 		if err != nil {
 			return fmt.Errorf("failed to generate invoice for %s: %w", clientName, err)
 		}
 
-		// Use invoice amounts for display (from database for existing, calculated for new)
-		var totalDisplay string
-		if s.cfg.GSTRegistered {
-			totalDisplay = fmt.Sprintf("$%s ($%s inc. GST)", invoice.SubtotalAmount.StringFixed(2), invoice.TotalAmount.StringFixed(2))
-		} else {
-			totalDisplay = fmt.Sprintf("$%s", invoice.TotalAmount.StringFixed(2))
-		}
-
-		if len(existingInvoices) > 0 {
-			fmt.Printf("Regenerated PDF for existing invoice: %s (Total: %s)\n", fileName, totalDisplay)
-		} else {
-			fmt.Printf("Generated invoice: %s (Total: %s)\n", fileName, totalDisplay)
+		run.Invoices = append(run.Invoices, &GeneratedInvoice{Invoice: invoice, Client: client, Files: fileNames, Existing: len(existingInvoices) > 0})
+		s.warnIfInvoiceRateBelowMinimum(client, invoice.InvoiceNumber, sessionsForPDF, clientExpenseList, invoice.SubtotalAmount)
+		if len(existingInvoices) == 0 {
+			s.publishInvoiceGenerated(ctx, client, invoice)
+			s.publishInvoiceWorkLog(ctx, client, invoice, sessionsForPDF)
 		}
-		invoiceCount++
 	}
 
-	if invoiceCount == 0 {
-		fmt.Println("No invoices generated - no clients with billable hours > 0 for the specified period")
+	// Sessions on invoices for other ranges, such as a custom range within a month, stay on those invoices
+	run.Overlapping, err = s.overlappingInvoices(ctx, period, fromDate, toDate, clientName)
+	return err
+}
+
+// overlappingInvoices returns the open invoices, for clientName when it's given, whose range overlaps fromDate to
+// toDate without being the invoice for exactly that period and range. Written off and void invoices are left out,
+// as nothing more is billed on them.
+func (s *TimesheetService) overlappingInvoices(ctx context.Context, period string, fromDate, toDate time.Time, clientName string) ([]*models.Invoice, error) {
+	invoices, err := s.db.ListInvoices(ctx, -1)
+	if err != nil {
+		return nil, err
 	}
+	from, to := dateOnly(fromDate), dateOnly(toDate)
+	var overlapping []*models.Invoice
+	for i := len(invoices) - 1; i >= 0; i-- {
+		invoice := invoices[i]
+		if (clientName != "" && invoice.ClientName != clientName) || invoiceClosed(invoice) {
+			continue
+		}
+		invoiceFrom, invoiceTo := dateOnly(invoice.PeriodStartDate), dateOnly(invoice.PeriodEndDate)
+		if invoiceFrom.After(to) || invoiceTo.Before(from) {
+			continue
+		}
+		if invoice.PeriodType == period && invoiceFrom.Equal(from) && invoiceTo.Equal(to) {
+			continue
+		}
+		overlapping = append(overlapping, invoice)
+	}
+	return overlapping, nil
+}
 
-	return nil
+// RegenerateInvoices deletes existing invoices for a period, or a custom range, and regenerates them. Each
+// keeps the grouping, notes and payment terms it had unless groupBy or notes are given, and has an appendix of
+// work summaries only when withAppendix is set. On an error, the run so far is returned with it.
+func (s *TimesheetService) RegenerateInvoices(ctx context.Context, period, date, from, to, clientName, groupBy, notes string, references InvoiceReferences, formats []string, withAppendix bool) (*InvoiceRun, error) {
+	run := &InvoiceRun{}
+	err := s.regenerateInvoices(ctx, run, period, date, from, to, clientName, groupBy, notes, references, formats, withAppendix)
+	return run, err
 }
 
-// RegenerateInvoices deletes existing invoices for a period and regenerates them
-func (s *TimesheetService) RegenerateInvoices(ctx context.Context, period, date, clientName string) error {
-	// Parse the date
-	targetDate, err := time.Parse("2006-01-02", date)
+func (s *TimesheetService) regenerateInvoices(ctx context.Context, run *InvoiceRun, period, date, from, to, clientName, groupBy, notes string, references InvoiceReferences, formats []string, withAppendix bool) error {
+	// Checked before anything is deleted
+	if err := ValidateInvoiceFormats(formats); err != nil {
+		return err
+	}
+	fromDate, toDate, _, err := s.resolveInvoicePeriod(period, date, from, to)
 	if err != nil {
-		return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+		return err
 	}
 
-	// Calculate date range based on period
-	fromDate, toDate := s.CalculatePeriodRange(period, targetDate)
-
 	// Normalize dates for database queries
 	periodStartDate := time.Date(fromDate.Year(), fromDate.Month(), fromDate.Day(), 0, 0, 0, 0, fromDate.Location())
 	periodEndDate := time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
@@ -252,25 +423,148 @@ func (s *TimesheetService) RegenerateInvoices(ctx context.Context, period, date,
 		}
 	}
 
+	// A range that overlaps invoices without matching any of them was most likely meant to be one of theirs, such
+	// as a custom range typed a day out, so nothing is deleted or reissued over the top of them
+	if len(existingInvoices) == 0 {
+		overlapping, err := s.overlappingInvoices(ctx, period, fromDate, toDate, clientName)
+		if err != nil {
+			return err
+		}
+		if len(overlapping) > 0 {
+			var commands []string
+			for _, invoice := range overlapping {
+				commands = append(commands, fmt.Sprintf("  %s (%s to %s): %s", invoice.InvoiceNumber, invoice.PeriodStartDate.Format("2006-01-02"),
+					invoice.PeriodEndDate.Format("2006-01-02"), regenerateInvoiceCommand(invoice)))
+			}
+			return fmt.Errorf("no invoice covers exactly %s to %s, but these overlap it and can be regenerated with:\n%s",
+				fromDate.Format("2006-01-02"), toDate.Format("2006-01-02"), strings.Join(commands, "\n"))
+		}
+	}
+
+	// Credit notes have been sent to the client, so an invoice they were issued against can't be replaced
+	for _, invoice := range existingInvoices {
+		creditNotes, err := s.db.GetCreditNotesByInvoiceID(ctx, invoice.ID)
+		if err != nil {
+			return err
+		}
+		if len(creditNotes) > 0 {
+			return fmt.Errorf("invoice %s has %d credit note(s) issued against it and can't be regenerated", invoice.InvoiceNumber, len(creditNotes))
+		}
+	}
+
 	// Clear sessions' invoice_id for existing invoices and delete the invoices
+	previous := make(map[string]*models.Invoice)
 	for _, invoice := range existingInvoices {
+		previous[invoice.ClientID] = invoice
+
 		// Clear session invoice IDs
 		err = s.db.ClearSessionInvoiceIDs(ctx, invoice.ID)
 		if err != nil {
 			return fmt.Errorf("failed to clear session invoice IDs for invoice %s: %w", invoice.ID, err)
 		}
 
+		// Milestones are billed again on the regenerated invoice
+		err = s.db.ClearMilestoneInvoiceIDs(ctx, invoice.ID)
+		if err != nil {
+			return fmt.Errorf("failed to clear milestone invoice IDs for invoice %s: %w", invoice.ID, err)
+		}
+
 		// Delete the invoice
 		err = s.db.DeleteInvoice(ctx, invoice.ID)
 		if err != nil {
 			return fmt.Errorf("failed to delete invoice %s: %w", invoice.ID, err)
 		}
 
-		fmt.Printf("Deleted existing invoice: %s\n", invoice.InvoiceNumber)
+		run.Deleted = append(run.Deleted, invoice)
 	}
 
 	// Now generate new invoices
-	return s.GenerateInvoices(ctx, period, date, clientName)
+	return s.generateInvoices(ctx, run, period, date, from, to, clientName, groupBy, notes, references, formats, withAppendix, previous, nil)
+}
+
+// invoiceNotesAndTerms returns the notes and payment terms a new invoice is issued with. An invoice being
+// regenerated keeps those it was issued with, others take the client's, and notes given replace either.
+func invoiceNotesAndTerms(client *models.Client, previous *models.Invoice, notes string) (*string, *string) {
+	invoiceNotes, paymentTerms := client.InvoiceNotes, client.PaymentTerms
+	if previous != nil {
+		invoiceNotes, paymentTerms = previous.Notes, previous.PaymentTerms
+	}
+	if notes != "" {
+		invoiceNotes = &notes
+	}
+	return invoiceNotes, paymentTerms
+}
+
+// InvoiceReferences are the client's purchase order number and project code to print on an invoice, with
+// empty fields falling back to the invoice's own or the client's defaults
+type InvoiceReferences struct {
+	PoNumber    string
+	ProjectCode string
+}
+
+// forNewInvoice returns the references a new invoice is issued with. An invoice being regenerated keeps those
+// it was issued with, others take the client's, and references given replace either.
+func (r InvoiceReferences) forNewInvoice(client *models.Client, previous *models.Invoice) (*string, *string) {
+	poNumber, projectCode := client.PoNumber, client.ProjectCode
+	if previous != nil {
+		poNumber, projectCode = previous.PoNumber, previous.ProjectCode
+	}
+	if r.PoNumber != "" {
+		poNumber = &r.PoNumber
+	}
+	if r.ProjectCode != "" {
+		projectCode = &r.ProjectCode
+	}
+	return poNumber, projectCode
+}
+
+// applyTo returns an existing invoice's references with those given replacing its own, and whether that
+// changed either of them
+func (r InvoiceReferences) applyTo(invoice *models.Invoice) (*string, *string, bool) {
+	poNumber, projectCode := invoice.PoNumber, invoice.ProjectCode
+	changed := false
+	if r.PoNumber != "" && (poNumber == nil || *poNumber != r.PoNumber) {
+		poNumber, changed = &r.PoNumber, true
+	}
+	if r.ProjectCode != "" && (projectCode == nil || *projectCode != r.ProjectCode) {
+		projectCode, changed = &r.ProjectCode, true
+	}
+	return poNumber, projectCode, changed
+}
+
+// resolveInvoicePeriod returns the range an invoice covers and the label used in its number and file name,
+// which is the date given for a period or the from and to dates of a custom range
+func (s *TimesheetService) resolveInvoicePeriod(period, date, from, to string) (time.Time, time.Time, string, error) {
+	if period == CustomPeriod {
+		if from == "" || to == "" {
+			return time.Time{}, time.Time{}, "", fmt.Errorf("a custom invoice range needs both --from and --to")
+		}
+		fromDate, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", fmt.Errorf("invalid from date %q, expected YYYY-MM-DD", from)
+		}
+		toDate, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", fmt.Errorf("invalid to date %q, expected YYYY-MM-DD", to)
+		}
+		if toDate.Before(fromDate) {
+			return time.Time{}, time.Time{}, "", fmt.Errorf("--to (%s) is before --from (%s)", to, from)
+		}
+		return fromDate, toDate.AddDate(0, 0, 1).Add(-time.Nanosecond), from + "_to_" + to, nil
+	}
+
+	if err := ValidatePeriod(period); err != nil {
+		return time.Time{}, time.Time{}, "", err
+	}
+	if date == "" {
+		return time.Time{}, time.Time{}, "", fmt.Errorf("--date is required with --period, or give a range with --from and --to")
+	}
+	targetDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Time{}, time.Time{}, "", fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+	}
+	fromDate, toDate := s.CalculatePeriodRange(period, targetDate)
+	return fromDate, toDate, date, nil
 }
 
 func (s *TimesheetService) sanitizeFileName(fileName string) string {
@@ -286,89 +580,169 @@ func (s *TimesheetService) sanitizeFileName(fileName string) string {
 	return result
 }
 
-func (s *TimesheetService) generateInvoicePDF(fileName string, client *models.Client, sessions []*models.WorkSession, expenses []*models.Expense, period string, fromDate, toDate time.Time, retainerAmount decimal.Decimal) error {
+// resolveInvoiceFileName returns a path the invoice PDF can be written to without clobbering
+// a different invoice. Existing files tagged with the same invoice number are reused so that
+// regeneration overwrites in place; otherwise a numeric suffix is appended.
+func (s *TimesheetService) resolveInvoiceFileName(fileName, invoiceNumber string) string {
+	ext := filepath.Ext(fileName)
+	base := strings.TrimSuffix(fileName, ext)
+
+	candidate := fileName
+	for i := 2; ; i++ {
+		contents, err := os.ReadFile(candidate)
+		if err != nil {
+			// Missing (or unreadable) files are free to be written
+			return candidate
+		}
+		if bytes.Contains(contents, []byte(invoicePDFKeyword(invoiceNumber))) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s_%d%s", base, i, ext)
+	}
+}
+
+// invoicePDFKeyword is the marker stored in the PDF keywords to identify which invoice a file belongs to
+func invoicePDFKeyword(invoiceNumber string) string {
+	return fmt.Sprintf("invoice:%s", invoiceNumber)
+}
+
+// writePDFAtomically writes the PDF to a temp file in the destination directory and renames it
+// into place, so a crash never leaves a half-written invoice behind
+func (s *TimesheetService) writePDFAtomically(pdf *gofpdf.Fpdf, fileName string) error {
+	return writeFileAtomically(fileName, pdf.Output)
+}
+
+// writeFileAtomically writes a file through write to a temp file in the destination directory and renames it
+// into place
+func writeFileAtomically(fileName string, write func(io.Writer) error) error {
+	dir := filepath.Dir(fileName)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // No-op once the rename has succeeded
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(fileName), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %s: %w", filepath.Base(fileName), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", filepath.Base(fileName), err)
+	}
+
+	if err := os.Rename(tmpName, fileName); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", filepath.Base(fileName), err)
+	}
+	return nil
+}
+
+// generateInvoiceFiles renders the invoice in each of formats, named fileName with the format's extension,
+// and returns the paths written, which may differ from fileName if another invoice already occupies that name
+func (s *TimesheetService) generateInvoiceFiles(fileName string, formats []string, invoice *models.Invoice, client *models.Client, sessions []*models.WorkSession, expenses []*models.Expense, milestones []*models.Milestone, period string, fromDate, toDate time.Time, withAppendix bool) ([]string, error) {
+	doc := s.buildInvoiceDocument(invoice, client, sessions, expenses, milestones, period, fromDate, toDate, withAppendix)
+	var written []string
+	for _, format := range formats {
+		name := s.resolveInvoiceFileName(fileName+"."+strings.ToLower(format), invoice.InvoiceNumber)
+		var err error
+		switch strings.ToLower(format) {
+		case InvoiceFormatHTML:
+			err = writeFileAtomically(name, func(w io.Writer) error { return renderInvoiceHTML(w, doc) })
+		default:
+			err = s.writePDFAtomically(s.renderInvoicePDF(doc), name)
+		}
+		if err != nil {
+			return written, err
+		}
+		written = append(written, name)
+	}
+	return written, nil
+}
+
+// renderInvoicePDF lays out an invoice as an A4 PDF: the totals and payment details on the first page, and
+// the session details and expenses after. The client's logo goes at the top right, and headings are in their
+// brand colour.
+func (s *TimesheetService) renderInvoicePDF(doc *invoiceDocument) *gofpdf.Fpdf {
 	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetKeywords(invoicePDFKeyword(doc.Number), false)
+	// Every page is numbered with the invoice it belongs to, so pages that come apart can be put back together
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Arial", "", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("%s - Page %d of {nb}", doc.Number, pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
 	pdf.AddPage()
+	if doc.Logo != nil {
+		renderInvoiceLogo(pdf, doc.Logo)
+	}
+
+	// Core fonts are cp1252 encoded, so currency symbols such as € need translating
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
 	pdf.SetFont("Arial", "B", 16)
 
 	// Header with company name
-	pdf.Cell(40, 10, fmt.Sprintf("Invoice - %s", s.formatClientName(client.Name)))
+	withBrandColour(pdf, doc, func() { pdf.Cell(40, 10, tr(doc.Title)) })
 	pdf.Ln(8)
 
 	// Billing company name and ABN/ACN
-	if s.cfg.BillingCompanyName != "" {
+	if doc.CompanyName != "" {
 		pdf.SetFont("Arial", "", 11)
-		pdf.Cell(40, 6, s.cfg.BillingCompanyName)
+		pdf.Cell(40, 6, tr(doc.CompanyName))
 		pdf.Ln(6)
 	}
 
-	if s.cfg.BillingABN != "" {
+	if doc.ABN != "" {
 		pdf.SetFont("Arial", "", 10)
-		abnText := fmt.Sprintf("ABN %s", s.cfg.BillingABN)
-		if s.cfg.BillingACN != "" {
-			abnText = fmt.Sprintf("ABN %s (includes ACN %s)", s.cfg.BillingABN, s.cfg.BillingACN)
-		}
-		pdf.Cell(40, 6, abnText)
+		pdf.Cell(40, 6, doc.ABN)
 		pdf.Ln(12)
 	}
 
+	// Purchase order number and project code, which some clients won't pay an invoice without
+	if len(doc.References) > 0 {
+		pdf.SetFont("Arial", "B", 10)
+		for _, reference := range doc.References {
+			pdf.Cell(40, 6, tr(reference.Label+": "+reference.Value))
+			pdf.Ln(6)
+		}
+		pdf.Ln(6)
+	}
+
 	pdf.SetFont("Arial", "B", 16)
 
 	// Client billing details in two columns
-	if client.CompanyName != nil || client.ContactName != nil {
+	if doc.BillTo != nil {
 		pdf.SetFont("Arial", "B", 12)
-		pdf.Cell(40, 8, "Bill To:")
+		withBrandColour(pdf, doc, func() { pdf.Cell(40, 8, "Bill To:") })
 		pdf.Ln(8)
 
 		pdf.SetFont("Arial", "", 11)
 
-		// Left column items
+		// Left column items: the contact above the company, then the address as a single line
 		leftColY := pdf.GetY()
 		leftEndY := leftColY
-
-		// Contact name first (person above company)
-		if client.ContactName != nil {
-			pdf.Cell(95, 6, *client.ContactName)
-			pdf.Ln(6)
-			leftEndY = pdf.GetY()
-		}
-
-		// Then company name
-		if client.CompanyName != nil {
-			pdf.Cell(95, 6, *client.CompanyName)
-			pdf.Ln(6)
-			leftEndY = pdf.GetY()
-		}
-
-		// Address as single line
-		address := s.formatClientAddress(client)
-		if address != "" {
-			pdf.Cell(95, 6, address)
-			pdf.Ln(6)
-			leftEndY = pdf.GetY()
+		for _, text := range []string{doc.BillTo.Contact, doc.BillTo.Company, doc.BillTo.Address} {
+			if text != "" {
+				pdf.Cell(95, 6, tr(text))
+				pdf.Ln(6)
+				leftEndY = pdf.GetY()
+			}
 		}
 
 		// Right column items
 		rightColY := leftColY
 		rightEndY := rightColY
 		pdf.SetXY(105, rightColY)
-
-		if client.Email != nil {
-			pdf.Cell(85, 6, fmt.Sprintf("Email: %s", *client.Email))
-			rightEndY = pdf.GetY() + 6
-			pdf.SetXY(105, rightEndY)
-		}
-
-		if client.Phone != nil {
-			pdf.Cell(85, 6, fmt.Sprintf("Phone: %s", *client.Phone))
-			rightEndY = pdf.GetY() + 6
-			pdf.SetXY(105, rightEndY)
-		}
-
-		if client.Abn != nil {
-			pdf.Cell(85, 6, fmt.Sprintf("ABN: %s", *client.Abn))
-			rightEndY = pdf.GetY() + 6
-			pdf.SetXY(105, rightEndY)
+		for _, field := range []invoiceField{{"Email", doc.BillTo.Email}, {"Phone", doc.BillTo.Phone}, {"ABN", doc.BillTo.ABN}} {
+			if field.Value != "" {
+				pdf.Cell(85, 6, tr(fmt.Sprintf("%s: %s", field.Label, field.Value)))
+				rightEndY = pdf.GetY() + 6
+				pdf.SetXY(105, rightEndY)
+			}
 		}
 
 		// Set Y position to the maximum of both columns
@@ -384,215 +758,288 @@ func (s *TimesheetService) generateInvoicePDF(fileName string, client *models.Cl
 
 	// Payment Details (moved before totals)
 	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(40, 8, "Payment Details:")
+	withBrandColour(pdf, doc, func() { pdf.Cell(40, 8, "Payment Details:") })
 	pdf.Ln(10)
 
 	pdf.SetFont("Arial", "", 11)
-	pdf.Cell(40, 6, fmt.Sprintf("Bank: %s", s.cfg.BillingBank))
-	pdf.Ln(6)
-	pdf.Cell(40, 6, fmt.Sprintf("Account Name: %s", s.cfg.BillingAccountName))
-	pdf.Ln(6)
-	pdf.Cell(40, 6, fmt.Sprintf("Account Number: %s", s.cfg.BillingAccountNumber))
-	pdf.Ln(6)
-	pdf.Cell(40, 6, fmt.Sprintf("BSB: %s", s.cfg.BillingBSB))
-	pdf.Ln(12) // Add space before totals
-
-	// Calculate session totals with retainer consideration
-	gstExclusiveSubtotal, gstInclusiveSubtotal, _, _ := s.calculateClientTotalWithGSTSeparation(sessions, client, period)
-	sessionSubtotal := gstExclusiveSubtotal.Add(gstInclusiveSubtotal)
-
-	// Totals section on first page
-	pdf.SetFont("Arial", "B", 11)
-
-	// Show retainer if applicable
-	if retainerAmount.GreaterThan(decimal.Zero) {
-		pdf.Cell(168, 8, fmt.Sprintf("Retainer (%s):", period))
-		pdf.CellFormat(22, 8, fmt.Sprintf("$%s", retainerAmount.StringFixed(2)), "", 1, "R", false, 0, "")
+	for _, field := range doc.Payment {
+		pdf.Cell(40, 6, tr(fmt.Sprintf("%s: %s", field.Label, field.Value)))
+		pdf.Ln(6)
 	}
+	pdf.Ln(6) // Add space before totals
 
-	// Session work subtotal
-	if sessionSubtotal.GreaterThan(decimal.Zero) {
-		pdf.Cell(168, 8, "Session Work:")
-		pdf.CellFormat(22, 8, fmt.Sprintf("$%s", sessionSubtotal.StringFixed(2)), "", 1, "R", false, 0, "")
+	// Totals section on first page
+	for _, total := range doc.Totals {
+		if total.Grand {
+			pdf.SetFont("Arial", "B", 12)
+			withBrandColour(pdf, doc, func() {
+				pdf.Cell(168, 10, tr(total.Label+":"))
+				pdf.CellFormat(22, 10, tr(total.Amount), "", 1, "R", false, 0, "")
+			})
+			continue
+		}
+		pdf.SetFont("Arial", "B", 11)
+		pdf.Cell(168, 8, tr(total.Label+":"))
+		pdf.CellFormat(22, 8, tr(total.Amount), "", 1, "R", false, 0, "")
 	}
 
-	// Expenses subtotal
-	expenseSubtotal := s.calculateExpenseTotal(expenses)
-	if expenseSubtotal.GreaterThan(decimal.Zero) {
-		pdf.Cell(168, 8, "Expenses:")
-		pdf.CellFormat(22, 8, fmt.Sprintf("$%s", expenseSubtotal.StringFixed(2)), "", 1, "R", false, 0, "")
+	// Early payment discount terms
+	if doc.EarlyDiscount != "" {
+		pdf.SetFont("Arial", "", 10)
+		pdf.Cell(190, 8, tr(doc.EarlyDiscount))
+		pdf.Ln(8)
 	}
 
-	// Total before GST
-	subtotal := sessionSubtotal.Add(retainerAmount).Add(expenseSubtotal)
-	pdf.Cell(168, 8, "Subtotal:")
-	pdf.CellFormat(22, 8, fmt.Sprintf("$%s", subtotal.StringFixed(2)), "", 1, "R", false, 0, "")
+	// Notes and payment terms stored on the invoice, e.g. a PO number and when payment is due
+	if doc.PaymentTerms != "" || doc.Notes != "" {
+		pdf.Ln(4)
+		pdf.SetFont("Arial", "B", 12)
+		withBrandColour(pdf, doc, func() { pdf.Cell(40, 8, "Notes:") })
+		pdf.Ln(8)
 
-	// GST (10%) - only if GST registered
-	var total decimal.Decimal
-	if s.cfg.GSTRegistered {
-		gst := subtotal.Mul(decimal.NewFromFloat(0.1))
-		pdf.Cell(168, 8, "GST (10%):")
-		pdf.CellFormat(22, 8, fmt.Sprintf("$%s", gst.StringFixed(2)), "", 1, "R", false, 0, "")
-		total = subtotal.Add(gst)
-	} else {
-		total = subtotal
+		pdf.SetFont("Arial", "", 10)
+		if doc.PaymentTerms != "" {
+			pdf.MultiCell(190, 6, tr(doc.PaymentTerms), "", "L", false)
+		}
+		if doc.Notes != "" {
+			pdf.MultiCell(190, 6, tr(doc.Notes), "", "L", false)
+		}
 	}
 
-	// Total
-	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(168, 10, "Total:")
-	pdf.CellFormat(22, 10, fmt.Sprintf("$%s", total.StringFixed(2)), "", 1, "R", false, 0, "")
-
 	// Start new page for the session details table
 	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 14)
-	pdf.Cell(40, 10, fmt.Sprintf("Session Details (%s to %s)", fromDate.Format("2006-01-02"), toDate.Format("2006-01-02")))
-	pdf.Ln(12)
-
-	// Table headers - adjusted widths to fit A4 (total ~190mm)
-	pdf.SetFont("Arial", "B", 9)
-	pdf.CellFormat(35, 8, "Start", "1", 0, "C", false, 0, "")
-	pdf.CellFormat(35, 8, "End", "1", 0, "C", false, 0, "")
-	pdf.CellFormat(20, 8, "Duration", "1", 0, "C", false, 0, "")
-	pdf.CellFormat(18, 8, "Rate", "1", 0, "C", false, 0, "")
-	pdf.CellFormat(60, 8, "Description", "1", 0, "C", false, 0, "")
-	pdf.CellFormat(22, 8, "Amount", "1", 1, "C", false, 0, "")
+	links := s.renderInvoiceSessionTable(pdf, tr, doc)
 
-	// Table rows
-	pdf.SetFont("Arial", "", 8)
+	// Add milestones table if any fixed-price milestones are billed
+	if len(doc.Milestones) > 0 {
+		pdf.Ln(12)
+		pdf.SetFont("Arial", "B", 14)
+		withBrandColour(pdf, doc, func() { pdf.Cell(40, 10, "Milestones") })
+		pdf.Ln(12)
 
-	// Track cumulative hours for retainer calculation
-	var cumulativeHours decimal.Decimal
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(28, 8, "Completed", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(137, 8, "Milestone", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(25, 8, "Amount", "1", 1, "C", false, 0, "")
 
-	for _, session := range sessions {
-		duration := s.CalculateDuration(session)
-		sessionHours := duration.Hours()
-
-		// Calculate effective rate and amount considering retainer
-		effectiveRate := decimal.Zero
-		amount := decimal.Zero
-
-		if session.HourlyRate != nil && session.HourlyRate.GreaterThan(decimal.Zero) {
-			if retainerAmount.GreaterThan(decimal.Zero) && client.RetainerHours != nil && (cumulativeHours.LessThan(decimal.NewFromFloat(*client.RetainerHours))) {
-				// Session hours covered by retainer
-				if cumulativeHours.Add(decimal.NewFromFloat(sessionHours)).LessThanOrEqual(decimal.NewFromFloat(*client.RetainerHours)) {
-					// Fully covered by retainer
-					effectiveRate = decimal.Zero
-					amount = decimal.Zero
-				} else {
-					// Partially covered by retainer
-					retainerCoveredHours := decimal.NewFromFloat(*client.RetainerHours).Sub(cumulativeHours)
-					billableHours := decimal.NewFromFloat(sessionHours).Sub(retainerCoveredHours)
-					effectiveRate = *session.HourlyRate // Show original rate
-					amount = billableHours.Mul(*session.HourlyRate)
-				}
-			} else {
-				// Not covered by retainer
-				effectiveRate = *session.HourlyRate
-				amount = decimal.NewFromFloat(sessionHours).Mul(*session.HourlyRate)
-			}
+		pdf.SetFont("Arial", "", 9)
+		for _, milestone := range doc.Milestones {
+			pdf.CellFormat(28, 6, milestone.Completed, "1", 0, "C", false, 0, "")
+			pdf.CellFormat(137, 6, tr(milestone.Name), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(25, 6, tr(milestone.Amount), "1", 1, "R", false, 0, "")
 		}
+	}
 
-		cumulativeHours = decimal.NewFromFloat(sessionHours).Add(cumulativeHours)
+	// Add expenses table if there are any expenses
+	if len(doc.Expenses) > 0 {
+		pdf.Ln(12)
+		pdf.SetFont("Arial", "B", 14)
+		withBrandColour(pdf, doc, func() { pdf.Cell(40, 10, "Expenses") })
+		pdf.Ln(12)
 
-		// Prepare description lines with text wrapping
-		description := ""
-		if session.Description != nil {
-			description = *session.Description
+		// Expense table headers
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(28, 8, "Date", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(28, 8, "Category", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(25, 8, "Cost", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(25, 8, "Billed", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(84, 8, "Reference", "1", 1, "C", false, 0, "")
+
+		// Expense table rows
+		pdf.SetFont("Arial", "", 9)
+		for _, expense := range doc.Expenses {
+			pdf.CellFormat(28, 6, expense.Date, "1", 0, "C", false, 0, "")
+			pdf.CellFormat(28, 6, tr(expense.Category), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(25, 6, tr(expense.Cost), "1", 0, "R", false, 0, "")
+			pdf.CellFormat(25, 6, tr(expense.Billed), "1", 0, "R", false, 0, "")
+			pdf.CellFormat(84, 6, tr(expense.Reference), "1", 1, "L", false, 0, "")
 		}
 
-		// Add outside_git notes to description
-		if session.OutsideGit != nil && *session.OutsideGit != "" {
-			if description != "" {
-				description += "\n"
+		// Category subtotals, only worth showing once expenses have been categorised
+		if len(doc.ExpenseSubtotals) > 0 {
+			pdf.Ln(4)
+			pdf.SetFont("Arial", "", 9)
+			for _, subtotal := range doc.ExpenseSubtotals {
+				pdf.CellFormat(81, 6, tr(subtotal.Label+":"), "", 0, "R", false, 0, "")
+				pdf.CellFormat(25, 6, tr(subtotal.Value), "", 1, "R", false, 0, "")
 			}
-			description += *session.OutsideGit
 		}
+	}
+
+	// Add note about retainer if applicable
+	if doc.RetainerNote != "" {
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "", 8)
+		pdf.Cell(190, 6, tr(doc.RetainerNote))
+	}
+
+	if len(doc.Appendix) > 0 {
+		s.renderInvoiceAppendix(pdf, tr, doc, links)
+	}
 
-		descriptionLines := s.wrapDescriptionText(description, 28)
+	return pdf
+}
+
+// invoiceTableRowHeight is the height of each line of text in the session details table
+const invoiceTableRowHeight = 6
+
+// renderInvoiceSessionTable lays out the session details from the current page onwards. Rows are never split
+// across pages: a row that won't fit moves to the next page, which repeats the heading and column headers
+// with the previous page marked as continued. When the invoice has an appendix, lines are numbered, and the
+// links from numbers of lines with work summaries are returned by line number for the appendix to set.
+func (s *TimesheetService) renderInvoiceSessionTable(pdf *gofpdf.Fpdf, tr func(string) string, doc *invoiceDocument) map[int]int {
+	_, pageHeight := pdf.GetPageSize()
+	_, bottomMargin := pdf.GetAutoPageBreak()
+	// Room is left at the bottom of each page for the continued marker
+	pageBottom := pageHeight - bottomMargin - invoiceTableRowHeight
+
+	// The number column is taken from the description's width
+	numbered := doc.Numbered()
+	numberWidth, descriptionWidth, descriptionChars := 0.0, 60.0, 28
+	if numbered {
+		numberWidth, descriptionWidth, descriptionChars = 8, 52, 24
+	}
+	links := make(map[int]int)
+
+	heading := func(title string) {
+		pdf.SetFont("Arial", "B", 14)
+		withBrandColour(pdf, doc, func() { pdf.Cell(40, 10, title) })
+		pdf.Ln(12)
+
+		// Table headers - adjusted widths to fit A4 (total ~190mm). Grouped lines show the date or dates they
+		// cover across the start and end columns.
+		pdf.SetFont("Arial", "B", 9)
+		if numbered {
+			pdf.CellFormat(numberWidth, 8, "#", "1", 0, "C", false, 0, "")
+		}
+		if doc.BySession() {
+			pdf.CellFormat(35, 8, "Start", "1", 0, "C", false, 0, "")
+			pdf.CellFormat(35, 8, "End", "1", 0, "C", false, 0, "")
+		} else {
+			pdf.CellFormat(70, 8, doc.WhenHeading(), "1", 0, "C", false, 0, "")
+		}
+		pdf.CellFormat(20, 8, "Duration", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(18, 8, "Rate", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(descriptionWidth, 8, "Description", "1", 0, "C", false, 0, "")
+		pdf.CellFormat(22, 8, "Amount", "1", 1, "C", false, 0, "")
+	}
+	heading(doc.SessionsHeading)
+
+	for _, line := range doc.Lines {
+		// Prepare description lines with text wrapping
+		descriptionLines := s.wrapDescriptionText(line.Description, descriptionChars)
 
 		// Calculate row height based on number of description lines
-		rowHeight := float64(len(descriptionLines)) * 6
-		if rowHeight < 6 {
-			rowHeight = 6
+		rowHeight := float64(len(descriptionLines)) * invoiceTableRowHeight
+		if rowHeight < invoiceTableRowHeight {
+			rowHeight = invoiceTableRowHeight
 		}
 
-		// Start datetime with minute precision
-		startDateTime := session.StartTime.Format("2006-01-02 15:04")
-		pdf.CellFormat(35, rowHeight, startDateTime, "1", 0, "L", false, 0, "")
+		if pdf.GetY()+rowHeight > pageBottom {
+			pdf.SetFont("Arial", "I", 8)
+			pdf.CellFormat(190, invoiceTableRowHeight, "Continued on next page", "", 1, "R", false, 0, "")
+			pdf.AddPage()
+			heading(doc.SessionsHeading + " (continued)")
+		}
 
-		// End datetime with minute precision
-		endDateTime := ""
-		if session.EndTime != nil {
-			endDateTime = session.EndTime.Format("2006-01-02 15:04")
+		if line.Subtotal {
+			pdf.SetFont("Arial", "B", 8)
+			pdf.CellFormat(numberWidth+70, rowHeight, line.Start, "1", 0, "R", false, 0, "")
+			pdf.CellFormat(20, rowHeight, line.Duration, "1", 0, "C", false, 0, "")
+			pdf.CellFormat(18+descriptionWidth, rowHeight, "", "1", 0, "", false, 0, "")
+			pdf.CellFormat(22, rowHeight, tr(line.Amount), "1", 1, "R", false, 0, "")
+			continue
 		}
-		pdf.CellFormat(35, rowHeight, endDateTime, "1", 0, "L", false, 0, "")
+		pdf.SetFont("Arial", "", 8)
 
-		pdf.CellFormat(20, rowHeight, fmt.Sprintf("%.1fh", duration.Hours()), "1", 0, "C", false, 0, "")
+		// The line's number links to its work summaries
+		if numbered {
+			link := 0
+			if line.Summarised {
+				link = pdf.AddLink()
+				links[line.Number] = link
+			}
+			pdf.CellFormat(numberWidth, rowHeight, strconv.Itoa(line.Number), "1", 0, "C", false, link, "")
+		}
 
-		// Show effective rate (retainer-adjusted)
-		rateText := ""
-		if effectiveRate.GreaterThan(decimal.Zero) {
-			rateText = fmt.Sprintf("$%s", effectiveRate.StringFixed(0))
-		} else if retainerAmount.GreaterThan(decimal.Zero) && cumulativeHours.LessThanOrEqual(decimal.NewFromFloat(*client.RetainerHours)) {
-			rateText = "$0*" // Indicate retainer coverage
+		// Start and end with minute precision, or the dates a grouped line covers
+		if doc.BySession() {
+			pdf.CellFormat(35, rowHeight, line.Start, "1", 0, "L", false, 0, "")
+			pdf.CellFormat(35, rowHeight, line.End, "1", 0, "L", false, 0, "")
+		} else {
+			pdf.CellFormat(70, rowHeight, line.Start, "1", 0, "L", false, 0, "")
 		}
-		pdf.CellFormat(18, rowHeight, rateText, "1", 0, "C", false, 0, "")
+
+		pdf.CellFormat(20, rowHeight, line.Duration, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(18, rowHeight, tr(line.Rate), "1", 0, "C", false, 0, "")
 
 		// Handle multi-line description
 		currentX := pdf.GetX()
 		currentY := pdf.GetY()
 
 		// Draw description cell border
-		pdf.Rect(currentX, currentY, 60, rowHeight, "D")
+		pdf.Rect(currentX, currentY, descriptionWidth, rowHeight, "D")
 
 		// Write each line of description
-		for i, line := range descriptionLines {
-			pdf.SetXY(currentX+1, currentY+float64(i)*6+1)
-			pdf.Cell(58, 6, line)
+		for i, text := range descriptionLines {
+			pdf.SetXY(currentX+1, currentY+float64(i)*invoiceTableRowHeight+1)
+			pdf.Cell(descriptionWidth-2, invoiceTableRowHeight, tr(text))
 		}
 
 		// Move to amount column
-		pdf.SetXY(currentX+60, currentY)
-		pdf.CellFormat(22, rowHeight, fmt.Sprintf("$%s", amount.StringFixed(2)), "1", 1, "R", false, 0, "")
+		pdf.SetXY(currentX+descriptionWidth, currentY)
+		pdf.CellFormat(22, rowHeight, tr(line.Amount), "1", 1, "R", false, 0, "")
 	}
+	return links
+}
 
-	// Add expenses table if there are any expenses
-	if len(expenses) > 0 {
-		pdf.Ln(12)
-		pdf.SetFont("Arial", "B", 14)
-		pdf.Cell(40, 10, "Expenses")
-		pdf.Ln(12)
+// renderInvoiceAppendix lays out the work summaries from a new page, each entry headed by the number of its line
+// in the session details and set as the target of that line's link
+func (s *TimesheetService) renderInvoiceAppendix(pdf *gofpdf.Fpdf, tr func(string) string, doc *invoiceDocument, links map[int]int) {
+	_, pageHeight := pdf.GetPageSize()
+	_, bottomMargin := pdf.GetAutoPageBreak()
 
-		// Expense table headers
-		pdf.SetFont("Arial", "B", 9)
-		pdf.CellFormat(40, 8, "Date", "1", 0, "C", false, 0, "")
-		pdf.CellFormat(25, 8, "Amount", "1", 0, "C", false, 0, "")
-		pdf.CellFormat(125, 8, "Reference", "1", 1, "C", false, 0, "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 14)
+	withBrandColour(pdf, doc, func() { pdf.Cell(40, 10, "Appendix: Work Summaries") })
+	pdf.Ln(12)
 
-		// Expense table rows
-		pdf.SetFont("Arial", "", 9)
-		for _, expense := range expenses {
-			pdf.CellFormat(40, 6, expense.ExpenseDate.Format("2006-01-02"), "1", 0, "C", false, 0, "")
-			pdf.CellFormat(25, 6, fmt.Sprintf("$%s", expense.Amount.StringFixed(2)), "1", 0, "R", false, 0, "")
+	for _, entry := range doc.Appendix {
+		// An entry's heading isn't left at the bottom of a page without any of its summary
+		if pdf.GetY()+24 > pageHeight-bottomMargin {
+			pdf.AddPage()
+		}
+		if link, ok := links[entry.Line]; ok {
+			pdf.SetLink(link, pdf.GetY(), -1)
+		}
+		pdf.SetFont("Arial", "B", 11)
+		pdf.Cell(190, 8, fmt.Sprintf("Line %d", entry.Line))
+		pdf.Ln(8)
 
-			reference := ""
-			if expense.Reference != nil {
-				reference = *expense.Reference
+		for _, session := range entry.Sessions {
+			pdf.SetFont("Arial", "B", 9)
+			pdf.Cell(190, 6, session.When)
+			pdf.Ln(6)
+			pdf.SetFont("Arial", "", 9)
+			for _, text := range session.Lines {
+				if text == "" {
+					pdf.Ln(2)
+					continue
+				}
+				pdf.MultiCell(190, 5, tr(text), "", "L", false)
 			}
-			pdf.CellFormat(125, 6, reference, "1", 1, "L", false, 0, "")
+			pdf.Ln(3)
 		}
+		pdf.Ln(3)
 	}
+}
 
-	// Add note about retainer if applicable
-	if retainerAmount.GreaterThan(decimal.Zero) && client.RetainerHours != nil {
-		pdf.Ln(6)
-		pdf.SetFont("Arial", "", 8)
-		pdf.Cell(190, 6, fmt.Sprintf("* First %.1f hours covered by %s retainer", *client.RetainerHours, period))
-	}
-
-	return pdf.OutputFileAndClose(fileName)
+// calculateInvoiceAmounts returns the subtotal, GST, total and retainer amount for a client's sessions,
+// expenses and milestones, with the total rounded to the nearest multiple of rounding
+func (s *TimesheetService) calculateInvoiceAmounts(client *models.Client, rounding *decimal.Decimal, sessions []*models.WorkSession, expenses []*models.Expense, milestones []*models.Milestone, retainer retainerTerms) (decimal.Decimal, decimal.Decimal, decimal.Decimal, decimal.Decimal) {
+	totals := s.calculateInvoiceTotals(client, sessions, expenses, milestones, retainer)
+	totals.roundTotal(rounding)
+	return totals.subtotal, totals.gst, totals.total, totals.retainer
 }
 
 func (s *TimesheetService) groupSessionsByClient(sessions []*models.WorkSession) map[string][]*models.WorkSession {
@@ -619,10 +1066,11 @@ func (s *TimesheetService) groupExpensesByClient(expenses []*models.Expense) map
 	return clientExpenses
 }
 
+// calculateExpenseTotal returns the amount billed for expenses, including markup
 func (s *TimesheetService) calculateExpenseTotal(expenses []*models.Expense) decimal.Decimal {
 	total := decimal.Zero
 	for _, expense := range expenses {
-		total = total.Add(expense.Amount)
+		total = total.Add(ExpenseBilledAmount(expense))
 	}
 	return total
 }
@@ -649,7 +1097,7 @@ func (s *TimesheetService) calculateClientTotalWithRetainer(sessions []*models.W
 	var billableTotal decimal.Decimal
 
 	for _, session := range sessions {
-		sessionHours := decimal.NewFromFloat(s.CalculateDuration(session).Hours())
+		sessionHours := durationHours(s.CalculateDuration(session))
 		totalHours = sessionHours.Add(totalHours)
 
 		// Apply retainer hours at $0 rate first
@@ -662,7 +1110,7 @@ func (s *TimesheetService) calculateClientTotalWithRetainer(sessions []*models.W
 			billableHours := sessionHours.Sub(retainerCoveredHours)
 
 			if session.HourlyRate != nil && session.HourlyRate.GreaterThan(decimal.Zero) {
-				billableTotal = billableTotal.Add(billableHours.Mul(*session.HourlyRate))
+				billableTotal = billableTotal.Add(billableHours.Mul(*session.HourlyRate).Round(2))
 			}
 		} else {
 			// Session fully billable
@@ -688,7 +1136,7 @@ func (s *TimesheetService) calculateClientTotalWithGSTHandling(sessions []*model
 	var gstFromInclusiveSessions decimal.Decimal
 
 	for _, session := range sessions {
-		sessionHours := decimal.NewFromFloat(s.CalculateDuration(session).Hours())
+		sessionHours := durationHours(s.CalculateDuration(session))
 		totalHours = sessionHours.Add(totalHours)
 
 		// Apply retainer hours at $0 rate first
@@ -701,7 +1149,7 @@ func (s *TimesheetService) calculateClientTotalWithGSTHandling(sessions []*model
 			billableHours := sessionHours.Sub(retainerCoveredHours)
 
 			if session.HourlyRate != nil && session.HourlyRate.GreaterThan(decimal.Zero) {
-				sessionAmount := billableHours.Mul(*session.HourlyRate)
+				sessionAmount := billableHours.Mul(*session.HourlyRate).Round(2)
 				if session.IncludesGst && s.cfg.GSTRegistered {
 					// Extract GST-exclusive amount and GST amount
 					gstExclusiveAmount := sessionAmount.Div(decimal.NewFromFloat(1.1))
@@ -730,66 +1178,6 @@ func (s *TimesheetService) calculateClientTotalWithGSTHandling(sessions []*model
 	return billableTotal, gstFromInclusiveSessions, retainerAmount
 }
 
-// calculateClientTotalWithGSTSeparation separates GST-exclusive and GST-inclusive session amounts
-func (s *TimesheetService) calculateClientTotalWithGSTSeparation(sessions []*models.WorkSession, client *models.Client, period string) (decimal.Decimal, decimal.Decimal, decimal.Decimal, decimal.Decimal) {
-	// Check if client has retainer and if it applies to this period
-	var retainerAmount decimal.Decimal
-	if client.RetainerAmount != nil && client.RetainerHours != nil && client.RetainerBasis != nil &&
-		client.RetainerAmount.GreaterThan(decimal.Zero) && *client.RetainerHours > 0.0 && *client.RetainerBasis == period {
-		retainerAmount = *client.RetainerAmount
-	}
-
-	// Separate totals for GST-exclusive and GST-inclusive sessions
-	var totalHours decimal.Decimal
-	var gstExclusiveTotal decimal.Decimal        // Sessions that need GST added
-	var gstInclusiveTotal decimal.Decimal        // GST-exclusive amount from GST-inclusive sessions
-	var gstFromInclusiveSessions decimal.Decimal // GST that was extracted from GST-inclusive sessions
-
-	for _, session := range sessions {
-		sessionHours := decimal.NewFromFloat(s.CalculateDuration(session).Hours())
-		totalHours = sessionHours.Add(totalHours)
-
-		// Apply retainer hours at $0 rate first
-		if retainerAmount.GreaterThan(decimal.Zero) && client.RetainerHours != nil && totalHours.LessThanOrEqual(decimal.NewFromFloat(*client.RetainerHours)) {
-			// Session hours are covered by retainer, bill at $0
-			continue
-		} else if retainerAmount.GreaterThan(decimal.Zero) && client.RetainerHours != nil && (totalHours.Sub(sessionHours)).LessThan(decimal.NewFromFloat(*client.RetainerHours)) {
-			// Partial session covered by retainer
-			retainerCoveredHours := decimal.NewFromFloat(*client.RetainerHours).Sub((totalHours.Sub(sessionHours)))
-			billableHours := sessionHours.Sub(retainerCoveredHours)
-
-			if session.HourlyRate != nil && session.HourlyRate.GreaterThan(decimal.Zero) {
-				sessionAmount := billableHours.Mul(*session.HourlyRate)
-				if session.IncludesGst && s.cfg.GSTRegistered {
-					// Extract GST-exclusive amount and GST amount from GST-inclusive session
-					gstExclusiveAmount := sessionAmount.Div(decimal.NewFromFloat(1.1))
-					gstAmount := sessionAmount.Sub(gstExclusiveAmount)
-					gstInclusiveTotal = gstInclusiveTotal.Add(gstExclusiveAmount)
-					gstFromInclusiveSessions = gstFromInclusiveSessions.Add(gstAmount)
-				} else {
-					// GST-exclusive session
-					gstExclusiveTotal = gstExclusiveTotal.Add(sessionAmount)
-				}
-			}
-		} else {
-			// Session fully billable
-			sessionAmount := s.CalculateBillableAmount(session)
-			if session.IncludesGst && s.cfg.GSTRegistered {
-				// Extract GST-exclusive amount and GST amount from GST-inclusive session
-				gstExclusiveAmount := sessionAmount.Div(decimal.NewFromFloat(1.1))
-				gstAmount := sessionAmount.Sub(gstExclusiveAmount)
-				gstInclusiveTotal = gstInclusiveTotal.Add(gstExclusiveAmount)
-				gstFromInclusiveSessions = gstFromInclusiveSessions.Add(gstAmount)
-			} else {
-				// GST-exclusive session
-				gstExclusiveTotal = gstExclusiveTotal.Add(sessionAmount)
-			}
-		}
-	}
-
-	return gstExclusiveTotal, gstInclusiveTotal, gstFromInclusiveSessions, retainerAmount
-}
-
 func (s *TimesheetService) formatClientName(name string) string {
 	// Convert snake_case to Capitalized Case With Spaces
 	words := strings.Split(name, "_")
@@ -864,14 +1252,46 @@ func (s *TimesheetService) wrapDescriptionText(text string, maxChars int) []stri
 	return lines
 }
 
-// ListInvoices displays a list of invoices with client, period, amounts and payment status
-func (s *TimesheetService) ListInvoices(ctx context.Context, limit int32, clientName string, unpaidOnly bool) error {
+// InvoiceList is a listing of invoices, each with the credit notes issued against it
+type InvoiceList struct {
+	Invoices []*ListedInvoice
+	// UnpaidOnly is set when the listing leaves out drafts and invoices with nothing left to pay
+	UnpaidOnly bool
+}
+
+// ListedInvoice is an invoice in an InvoiceList
+type ListedInvoice struct {
+	Invoice     *models.Invoice
+	CreditNotes []*models.CreditNote
+	// Money formats amounts in the client's currency and locale
+	Money money.Formatter
+}
+
+// ListInvoices lists invoices, for one client when clientName is given, with the credit notes issued against
+// them and the currency each is in
+func (s *TimesheetService) ListInvoices(ctx context.Context, limit int32, clientName string, unpaidOnly bool) (*InvoiceList, error) {
 	invoices, err := s.GetInvoices(ctx, limit, clientName, unpaidOnly)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	s.PrintInvoices(invoices, unpaidOnly)
-	return nil
+	creditNotes, err := s.db.ListCreditNotes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	creditNotesByInvoice := make(map[string][]*models.CreditNote)
+	for _, creditNote := range creditNotes {
+		creditNotesByInvoice[creditNote.InvoiceID] = append(creditNotesByInvoice[creditNote.InvoiceID], creditNote)
+	}
+
+	list := &InvoiceList{UnpaidOnly: unpaidOnly}
+	for _, invoice := range invoices {
+		list.Invoices = append(list.Invoices, &ListedInvoice{
+			Invoice:     invoice,
+			CreditNotes: creditNotesByInvoice[invoice.ID],
+			Money:       s.clientMoneyByName(invoice.ClientName),
+		})
+	}
+	return list, nil
 }
 
 func (s *TimesheetService) GetInvoices(ctx context.Context, limit int32, clientName string, unpaidOnly bool) ([]*models.Invoice, error) {
@@ -890,11 +1310,11 @@ func (s *TimesheetService) GetInvoices(ctx context.Context, limit int32, clientN
 		}
 	}
 
-	// Filter for unpaid invoices if requested
+	// Filter for unpaid invoices if requested, leaving out drafts since nothing's owed until they're issued
 	if unpaidOnly {
 		var unpaidInvoices []*models.Invoice
 		for _, invoice := range invoices {
-			if invoice.AmountPaid.LessThan(invoice.TotalAmount) {
+			if invoice.Status != models.InvoiceStatusDraft && invoiceBalance(invoice).IsPositive() {
 				unpaidInvoices = append(unpaidInvoices, invoice)
 			}
 		}
@@ -904,52 +1324,158 @@ func (s *TimesheetService) GetInvoices(ctx context.Context, limit int32, clientN
 	return invoices, nil
 }
 
-func (s *TimesheetService) PrintInvoices(invoices []*models.Invoice, unpaidOnly bool) {
-	if len(invoices) == 0 {
-		if unpaidOnly {
-			fmt.Println("No unpaid invoices found.")
-		} else {
-			fmt.Println("No invoices found.")
+// invoiceStatus is an invoice's payment status as shown in invoice and payment listings
+func invoiceStatus(invoice *models.Invoice) string {
+	switch {
+	case invoice.Status == models.InvoiceStatusVoid:
+		return "VOID"
+	case invoice.Status == models.InvoiceStatusWrittenOff:
+		return "WRITTEN OFF"
+	case invoice.Status == models.InvoiceStatusDraft:
+		return "DRAFT"
+	case invoice.AmountCredited.GreaterThanOrEqual(invoice.TotalAmount):
+		return "CREDITED"
+	case !invoiceBalance(invoice).IsPositive():
+		return "PAID"
+	case invoice.AmountPaid.GreaterThan(decimal.Zero):
+		return "PARTIALLY PAID"
+	case invoice.AmountCredited.GreaterThan(decimal.Zero):
+		return "PART CREDITED"
+	default:
+		return "UNPAID"
+	}
+}
+
+// ShowInvoice prints an invoice's details and amounts, followed by its credit notes and payment history. The
+// invoice can be given by ID or number.
+func (s *TimesheetService) ShowInvoice(ctx context.Context, invoiceRef string) error {
+	invoice, err := s.getInvoiceByIDOrNumber(ctx, invoiceRef)
+	if err != nil {
+		return err
+	}
+	creditNotes, err := s.db.GetCreditNotesByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+	payments, err := s.db.ListPayments(ctx, invoice.ID, "")
+	if err != nil {
+		return err
+	}
+	repos, err := s.db.ListInvoiceSessionRepos(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+	sessions, err := s.db.GetSessionsByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+	users, err := s.db.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	m := s.clientMoneyByName(invoice.ClientName)
+	fmt.Fprintf(s.out, "Invoice: %s\n", invoice.InvoiceNumber)
+	fmt.Fprintf(s.out, "ID: %s\n", invoice.ID)
+	fmt.Fprintf(s.out, "Client: %s\n", invoice.ClientName)
+	fmt.Fprintf(s.out, "Period: %s %s to %s\n", invoice.PeriodType,
+		invoice.PeriodStartDate.Format("2006-01-02"), invoice.PeriodEndDate.Format("2006-01-02"))
+	fmt.Fprintf(s.out, "Issued: %s\n", invoice.GeneratedDate.Format("2006-01-02"))
+	fmt.Fprintf(s.out, "Status: %s\n", invoiceStatus(invoice))
+	if invoice.Status != models.InvoiceStatusDraft && invoiceBalance(invoice).IsPositive() {
+		due := fmt.Sprintf("Due: %s", s.invoiceDueDate(invoice).Format("2006-01-02"))
+		if days := s.invoiceDaysOverdue(invoice, time.Now()); days > 0 {
+			due += fmt.Sprintf(" (%d days overdue)", days)
 		}
+		fmt.Fprintln(s.out, due)
+	}
+	if closed := invoiceClosedSummary(m, invoice); closed != "" {
+		fmt.Fprintf(s.out, "%s\n", strings.ToUpper(closed[:1])+closed[1:])
+	}
+	if invoice.PaymentTerms != nil {
+		fmt.Fprintf(s.out, "Payment terms: %s\n", *invoice.PaymentTerms)
+	}
+	if invoice.Notes != nil {
+		fmt.Fprintf(s.out, "Notes: %s\n", *invoice.Notes)
+	}
+	if invoice.PoNumber != nil {
+		fmt.Fprintf(s.out, "PO number: %s\n", *invoice.PoNumber)
+	}
+	if invoice.ProjectCode != nil {
+		fmt.Fprintf(s.out, "Project code: %s\n", *invoice.ProjectCode)
+	}
+	if invoice.NeedsRegeneration {
+		fmt.Fprintf(s.out, "Sessions changed since it was issued, regenerate it with: %s\n", regenerateInvoiceCommand(invoice))
 	}
 
-	// Print header
-	if unpaidOnly {
-		fmt.Println("Unpaid Invoices:")
+	fmt.Fprintln(s.out)
+	fmt.Fprintln(s.out, "Amounts:")
+	fmt.Fprintf(s.out, "  Subtotal: %s\n", m.Format(invoice.SubtotalAmount))
+	fmt.Fprintf(s.out, "  GST: %s\n", m.Format(invoice.GstAmount))
+	if adjustment := invoiceTotalAdjustment(invoice); !adjustment.IsZero() {
+		fmt.Fprintf(s.out, "  Rounding adjustment: %s\n", m.Format(adjustment))
+	}
+	fmt.Fprintf(s.out, "  Total: %s\n", m.Format(invoice.TotalAmount))
+	if invoice.AmountCredited.IsPositive() {
+		fmt.Fprintf(s.out, "  Credited: -%s\n", m.Format(invoice.AmountCredited))
+	}
+	if invoice.AmountDiscounted.IsPositive() {
+		fmt.Fprintf(s.out, "  Early payment discount: -%s\n", m.Format(invoice.AmountDiscounted))
+	}
+	if invoice.AmountWrittenOff.IsPositive() {
+		fmt.Fprintf(s.out, "  Written off: -%s\n", m.Format(invoice.AmountWrittenOff))
+	}
+	fmt.Fprintf(s.out, "  Paid: %s\n", m.Format(invoice.AmountPaid))
+	balance := invoiceBalance(invoice)
+	if balance.IsNegative() {
+		fmt.Fprintf(s.out, "  Refund due: %s\n", m.Format(balance.Neg()))
+	} else {
+		fmt.Fprintf(s.out, "  Outstanding: %s\n", m.Format(balance))
 	}
-	fmt.Printf("%-38s %-15s %-10s %-12s %-12s %-12s %-12s %-16s %-18s %-12s\n",
-		"ID", "CLIENT", "PERIOD", "FROM", "TO", "SUBTOTAL", "TOTAL", "AMOUNT_PAID", "PAYMENT_DATE", "STATUS")
-	fmt.Println(strings.Repeat("-", 167))
 
-	// Print each invoice
-	for _, invoice := range invoices {
-		paidStatus := fmt.Sprintf("$%s", invoice.AmountPaid.StringFixed(2))
-		if invoice.AmountPaid.GreaterThanOrEqual(invoice.TotalAmount) {
-			paidStatus = "PAID"
-		} else if invoice.AmountPaid.GreaterThan(decimal.Zero) {
-			paidStatus = "PARTIALLY PAID"
-		} else {
-			paidStatus = "UNPAID"
+	if len(creditNotes) > 0 {
+		fmt.Fprintln(s.out)
+		fmt.Fprintln(s.out, "Credit notes:")
+		for _, creditNote := range creditNotes {
+			fmt.Fprintf(s.out, "  %s on %s: -%s (%s)\n",
+				creditNote.CreditNoteNumber, creditNote.IssuedDate.Format("2006-01-02"), m.Format(creditNote.Amount), creditNote.Reason)
 		}
+	}
 
-		paymentDate := ""
-		if invoice.PaymentDate != nil {
-			paymentDate = invoice.PaymentDate.Format("2006-01-02")
+	fmt.Fprintln(s.out)
+	fmt.Fprintln(s.out, "Payments:")
+	if len(payments) == 0 {
+		fmt.Fprintln(s.out, "  none")
+	}
+	for _, payment := range payments {
+		fmt.Fprintf(s.out, "  %s on %s: %s", payment.ID, payment.PaymentDate.Format("2006-01-02"), m.Format(payment.Amount))
+		if payment.DiscountAmount.IsPositive() {
+			fmt.Fprintf(s.out, " with a %s early payment discount", m.Format(payment.DiscountAmount))
 		}
+		fmt.Fprintln(s.out)
+	}
 
-		fmt.Printf("%-38s %-15s %-10s %-12s %-12s $%-11s $%-11s %-16s %-18s %-12s\n",
-			invoice.ID,
-			truncateString(invoice.ClientName, 14),
-			invoice.PeriodType,
-			invoice.PeriodStartDate.Format("2006-01-02"),
-			invoice.PeriodEndDate.Format("2006-01-02"),
-			invoice.SubtotalAmount.StringFixed(2),
-			invoice.TotalAmount.StringFixed(2),
-			invoice.AmountPaid.StringFixed(2),
-			paymentDate,
-			paidStatus,
-		)
+	// The repositories behind the invoiced work, in the order they were first worked in
+	if len(repos) > 0 {
+		fmt.Fprintln(s.out)
+		fmt.Fprintln(s.out, "Repositories:")
+		var paths []string
+		commits := make(map[string]int64)
+		sessionCounts := make(map[string]int)
+		for _, repo := range repos {
+			if sessionCounts[repo.RepoPath] == 0 {
+				paths = append(paths, repo.RepoPath)
+			}
+			commits[repo.RepoPath] += repo.CommitCount
+			sessionCounts[repo.RepoPath]++
+		}
+		for _, path := range paths {
+			fmt.Fprintf(s.out, "  %s: %s in %d session(s)\n", path, commitCountLabel(commits[path]), sessionCounts[path])
+		}
 	}
+
+	s.displayUserMargins(m, s.userMargins(users, sessions, invoice.GstAmount.IsPositive()))
+	return nil
 }
 
 func (s *TimesheetService) PayInvoice(ctx context.Context, id string, amount decimal.Decimal, date time.Time) error {
@@ -958,46 +1484,66 @@ func (s *TimesheetService) PayInvoice(ctx context.Context, id string, amount dec
 		return fmt.Errorf("failed to get invoice: %w", err)
 	}
 
-	remainingAmount := invoice.TotalAmount.Sub(invoice.AmountPaid)
+	if err := checkInvoiceIssued(invoice, "paid"); err != nil {
+		return err
+	}
+
+	m := s.clientMoneyByName(invoice.ClientName)
+	remainingAmount := invoiceBalance(invoice)
 	if remainingAmount.LessThanOrEqual(decimal.Zero) {
 		return fmt.Errorf("invoice already fully paid")
 	}
 
-	if amount.LessThanOrEqual(decimal.Zero) {
+	if amount.IsNegative() {
 		return fmt.Errorf("amount must be greater than 0")
 	}
 
-	if amount.Equal(decimal.Zero) {
-		amount = remainingAmount
+	if date.IsZero() {
+		now := time.Now()
+		date = time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location())
 	}
 
-	if amount.GreaterThan(remainingAmount) {
-		return fmt.Errorf("payment amount ($%s) exceeds remaining balance ($%s)", amount.StringFixed(2), remainingAmount.StringFixed(2))
+	client, _ := s.db.GetClientByID(ctx, invoice.ClientID)
+	available := earlyPaymentDiscount(client, invoice, date)
+
+	if amount.Equal(decimal.Zero) {
+		amount = remainingAmount.Sub(available)
 	}
 
-	if date.IsZero() {
-		now := time.Now()
-		date = time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location())
+	if amount.GreaterThan(remainingAmount) {
+		return fmt.Errorf("payment amount (%s) exceeds remaining balance (%s)", m.Format(amount), m.Format(remainingAmount))
 	}
+	discount := discountTaken(amount, remainingAmount, available)
 
 	err = s.db.PayInvoice(ctx, db.PayInvoiceParams{
-		ID:          models.NewUUID(),
-		InvoiceID:   invoice.ID,
-		Amount:      amount,
-		PaymentDate: date,
+		ID:             models.NewUUID(),
+		InvoiceID:      invoice.ID,
+		Amount:         amount,
+		PaymentDate:    date,
+		DiscountAmount: discount,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update invoice: %w", err)
 	}
+	if err := s.syncInvoicePaidStatus(ctx, invoice.ID); err != nil {
+		return err
+	}
 
 	newAmountPaid := invoice.AmountPaid.Add(amount)
+	owed := invoiceOwed(invoice).Sub(discount)
 	status := "partially paid"
-	if newAmountPaid.GreaterThanOrEqual(invoice.TotalAmount) {
+	if newAmountPaid.GreaterThanOrEqual(owed) {
 		status = "fully paid"
 	}
 
-	fmt.Printf("Invoice %s paid $%s (now %s: $%s/$%s)\n",
-		invoice.InvoiceNumber, amount.StringFixed(2), status, newAmountPaid.StringFixed(2), invoice.TotalAmount.StringFixed(2))
+	if discount.IsPositive() {
+		fmt.Fprintf(s.out, "Invoice %s paid %s with a %s early payment discount (now %s: %s/%s)\n",
+			invoice.InvoiceNumber, m.Format(amount), m.Format(discount), status, m.Format(newAmountPaid), m.Format(owed))
+	} else {
+		fmt.Fprintf(s.out, "Invoice %s paid %s (now %s: %s/%s)\n",
+			invoice.InvoiceNumber, m.Format(amount), status, m.Format(newAmountPaid), m.Format(owed))
+	}
+	s.publishInvoicePaid(ctx, invoice, m.Format(amount), m.Format(newAmountPaid), m.Format(owed), status)
 	return nil
 }
 
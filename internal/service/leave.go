@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// LeaveTypes are the kinds of leave that can be recorded
+var LeaveTypes = []string{models.LeaveTypeAnnual, models.LeaveTypeSick, models.LeaveTypePublic}
+
+// ValidateLeaveType returns an error if leaveType is not one of LeaveTypes
+func ValidateLeaveType(leaveType string) error {
+	for _, known := range LeaveTypes {
+		if leaveType == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported leave type %q, expected one of: %s", leaveType, strings.Join(LeaveTypes, ", "))
+}
+
+// leaveLabel describes a kind of leave in views, e.g. "annual leave" or "public holiday"
+func leaveLabel(leaveType string) string {
+	if leaveType == models.LeaveTypePublic {
+		return "public holiday"
+	}
+	return leaveType + " leave"
+}
+
+// AddLeave records days off from fromDate to toDate inclusive. Leave can't overlap leave that's already been
+// recorded, so each day off is counted once.
+func (s *TimesheetService) AddLeave(ctx context.Context, leaveType string, fromDate, toDate time.Time, note *string) (*models.Leave, error) {
+	if err := ValidateLeaveType(leaveType); err != nil {
+		return nil, err
+	}
+	fromDate, toDate = startOfDay(fromDate), startOfDay(toDate)
+	if toDate.Before(fromDate) {
+		return nil, fmt.Errorf("leave can't end (%s) before it starts (%s)", toDate.Format("2006-01-02"), fromDate.Format("2006-01-02"))
+	}
+
+	existing, err := s.db.ListLeaveInRange(ctx, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		other := existing[0]
+		return nil, fmt.Errorf("overlaps %s from %s to %s (%s), delete it first with 'work leave delete %s'", leaveLabel(other.Type),
+			other.StartDate.Format("2006-01-02"), other.EndDate.Format("2006-01-02"), other.ID, other.ID)
+	}
+
+	return s.db.CreateLeave(ctx, leaveType, fromDate, toDate, note)
+}
+
+// DeleteLeave removes recorded leave by ID
+func (s *TimesheetService) DeleteLeave(ctx context.Context, leaveID string) (*models.Leave, error) {
+	leave, err := s.db.GetLeaveByID(ctx, leaveID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("leave '%s' does not exist, see 'work leave list'", leaveID)
+		}
+		return nil, err
+	}
+	if err := s.db.DeleteLeave(ctx, leave.ID); err != nil {
+		return nil, err
+	}
+	return leave, nil
+}
+
+// DisplayLeave lists recorded leave with how many working days each takes, limited to a year when year isn't 0
+func (s *TimesheetService) DisplayLeave(ctx context.Context, year int) error {
+	var leave []*models.Leave
+	var err error
+	if year != 0 {
+		leave, err = s.db.ListLeaveInRange(ctx, time.Date(year, 1, 1, 0, 0, 0, 0, time.Local), time.Date(year, 12, 31, 0, 0, 0, 0, time.Local))
+	} else {
+		leave, err = s.db.ListLeave(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	if len(leave) == 0 {
+		fmt.Fprintln(s.out, "No leave found. Record days off with 'work leave add'.")
+		return nil
+	}
+
+	days := make(map[string]int)
+	for _, l := range leave {
+		note := ""
+		if l.Note != nil && *l.Note != "" {
+			note = " - " + *l.Note
+		}
+		workingDays := countWorkingDays(l.StartDate, l.EndDate)
+		days[l.Type] += workingDays
+		fmt.Fprintf(s.out, "%s: %s %s to %s, %s%s\n", l.ID, leaveLabel(l.Type), l.StartDate.Format("2006-01-02"),
+			l.EndDate.Format("2006-01-02"), pluralDays(workingDays), note)
+	}
+
+	fmt.Fprintln(s.out)
+	for _, leaveType := range LeaveTypes {
+		if days[leaveType] > 0 {
+			fmt.Fprintf(s.out, "Total %s: %s\n", leaveLabel(leaveType), pluralDays(days[leaveType]))
+		}
+	}
+	return nil
+}
+
+// leaveByDay returns the leave taken on each day from fromDate to toDate, keyed by date as YYYY-MM-DD
+func (s *TimesheetService) leaveByDay(ctx context.Context, fromDate, toDate time.Time) (map[string]*models.Leave, error) {
+	leave, err := s.db.ListLeaveInRange(ctx, startOfDay(fromDate), startOfDay(toDate))
+	if err != nil {
+		return nil, err
+	}
+	days := make(map[string]*models.Leave)
+	for _, l := range leave {
+		for day := l.StartDate; !day.After(l.EndDate); day = day.AddDate(0, 0, 1) {
+			days[day.Format("2006-01-02")] = l
+		}
+	}
+	return days, nil
+}
+
+// countWorkingDays counts the weekdays from fromDate to toDate inclusive
+func countWorkingDays(fromDate, toDate time.Time) int {
+	count := 0
+	for day := startOfDay(fromDate); !day.After(toDate); day = day.AddDate(0, 0, 1) {
+		if isWorkingDay(day) {
+			count++
+		}
+	}
+	return count
+}
+
+func isWorkingDay(day time.Time) bool {
+	return day.Weekday() != time.Saturday && day.Weekday() != time.Sunday
+}
+
+func pluralDays(days int) string {
+	if days == 1 {
+		return "1 working day"
+	}
+	return fmt.Sprintf("%d working days", days)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// AddMilestone adds a fixed-price milestone for a client, due on dueDate when it's given
+func (s *TimesheetService) AddMilestone(ctx context.Context, clientName, name string, amount decimal.Decimal, dueDate *time.Time) (*models.Milestone, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("a milestone needs a name")
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("a milestone's amount must be more than 0")
+	}
+	client, err := s.db.GetClientByName(ctx, clientName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("client '%s' does not exist", clientName)
+		}
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+	if dueDate != nil {
+		due := startOfDay(*dueDate)
+		dueDate = &due
+	}
+
+	return s.db.CreateMilestone(ctx, &models.Milestone{
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		Name:       strings.TrimSpace(name),
+		Amount:     amount.Round(2),
+		DueDate:    dueDate,
+	})
+}
+
+// CompleteMilestone marks a milestone complete on completedDate, so it's billed on the client's next invoice for a
+// period ending on or after that day
+func (s *TimesheetService) CompleteMilestone(ctx context.Context, milestoneID string, completedDate time.Time) (*models.Milestone, error) {
+	milestone, err := s.getMilestone(ctx, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if milestone.Status == models.MilestoneStatusComplete {
+		return nil, fmt.Errorf("milestone '%s' was already completed on %s", milestone.Name, milestone.CompletedDate.Format("2006-01-02"))
+	}
+	completedDate = startOfDay(completedDate)
+	if err := s.db.CompleteMilestone(ctx, milestone.ID, completedDate); err != nil {
+		return nil, err
+	}
+	milestone.Status = models.MilestoneStatusComplete
+	milestone.CompletedDate = &completedDate
+	return milestone, nil
+}
+
+// DeleteMilestone removes a milestone by ID. Invoiced milestones are kept until their invoice is voided or
+// regenerated without them, so the invoice still adds up.
+func (s *TimesheetService) DeleteMilestone(ctx context.Context, milestoneID string) (*models.Milestone, error) {
+	milestone, err := s.getMilestone(ctx, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if milestone.InvoiceID != nil {
+		return nil, fmt.Errorf("milestone '%s' has been invoiced, void its invoice before deleting it", milestone.Name)
+	}
+	if err := s.db.DeleteMilestone(ctx, milestone.ID); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+func (s *TimesheetService) getMilestone(ctx context.Context, milestoneID string) (*models.Milestone, error) {
+	milestone, err := s.db.GetMilestoneByID(ctx, milestoneID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("milestone '%s' does not exist, see 'work milestones list'", milestoneID)
+		}
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// DisplayMilestones lists milestones with their amounts, due dates and whether they're pending, complete or
+// invoiced, for one client when clientName is given
+func (s *TimesheetService) DisplayMilestones(ctx context.Context, clientName string) error {
+	var clientID *string
+	if clientName != "" {
+		client, err := s.GetClientByName(ctx, clientName)
+		if err != nil {
+			return err
+		}
+		clientID = &client.ID
+	}
+	milestones, err := s.db.ListMilestones(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if len(milestones) == 0 {
+		fmt.Fprintln(s.out, "No milestones found.")
+		return nil
+	}
+	invoiceNumbers, err := s.invoiceNumbers(ctx)
+	if err != nil {
+		return err
+	}
+
+	today := startOfDay(time.Now())
+	fmt.Fprintf(s.out, "%-36s %-15s %-28s %14s %-10s  %s\n", "ID", "CLIENT", "MILESTONE", "AMOUNT", "DUE", "STATUS")
+	fmt.Fprintln(s.out, strings.Repeat("-", 130))
+	for _, milestone := range milestones {
+		due := ""
+		if milestone.DueDate != nil {
+			due = milestone.DueDate.Format("2006-01-02")
+		}
+		fmt.Fprintf(s.out, "%-36s %-15s %-28s %14s %-10s  %s\n", milestone.ID, truncateString(milestone.ClientName, 15),
+			truncateString(milestone.Name, 28), s.clientMoneyByID(&milestone.ClientID).Format(milestone.Amount), due,
+			milestoneStatus(milestone, invoiceNumbers, today))
+	}
+	return nil
+}
+
+// invoiceNumbers maps invoice IDs to their numbers
+func (s *TimesheetService) invoiceNumbers(ctx context.Context) (map[string]string, error) {
+	invoices, err := s.db.ListInvoices(ctx, -1)
+	if err != nil {
+		return nil, err
+	}
+	numbers := make(map[string]string, len(invoices))
+	for _, invoice := range invoices {
+		numbers[invoice.ID] = invoice.InvoiceNumber
+	}
+	return numbers, nil
+}
+
+// milestoneStatus describes where a milestone is up to, e.g. "complete 2025-11-03" or "pending, overdue"
+func milestoneStatus(milestone *models.Milestone, invoiceNumbers map[string]string, today time.Time) string {
+	switch {
+	case milestone.InvoiceID != nil:
+		return strings.TrimSpace("invoiced " + invoiceNumbers[*milestone.InvoiceID])
+	case milestone.Status == models.MilestoneStatusComplete:
+		return "complete " + milestone.CompletedDate.Format("2006-01-02")
+	case milestone.DueDate != nil && milestone.DueDate.Before(today):
+		return "pending, overdue"
+	default:
+		return "pending"
+	}
+}
+
+// milestoneProgress totals a client's milestones, with invoiced ones counted as complete
+type milestoneProgress struct {
+	count     int
+	completed int
+	invoiced  int
+	overdue   int
+	value     decimal.Decimal
+	done      decimal.Decimal
+}
+
+// summariseMilestones totals milestones, counting pending ones due before today as overdue
+func summariseMilestones(milestones []*models.Milestone, today time.Time) milestoneProgress {
+	var progress milestoneProgress
+	for _, milestone := range milestones {
+		progress.count++
+		progress.value = progress.value.Add(milestone.Amount)
+		switch {
+		case milestone.Status == models.MilestoneStatusComplete:
+			progress.completed++
+			progress.done = progress.done.Add(milestone.Amount)
+			if milestone.InvoiceID != nil {
+				progress.invoiced++
+			}
+		case milestone.DueDate != nil && milestone.DueDate.Before(today):
+			progress.overdue++
+		}
+	}
+	return progress
+}
+
+// displayMilestoneProgress shows how far through its milestones a client is, followed by each milestone
+func (s *TimesheetService) displayMilestoneProgress(ctx context.Context, client *models.Client, m money.Formatter) error {
+	milestones, err := s.db.ListMilestones(ctx, &client.ID)
+	if err != nil {
+		return err
+	}
+	if len(milestones) == 0 {
+		return nil
+	}
+	invoiceNumbers, err := s.invoiceNumbers(ctx)
+	if err != nil {
+		return err
+	}
+
+	today := startOfDay(time.Now())
+	progress := summariseMilestones(milestones, today)
+	fmt.Fprintln(s.out)
+	fmt.Fprintf(s.out, "Milestones: %d of %d complete, %s of %s (%d invoiced, %d overdue)\n", progress.completed, progress.count,
+		m.Format(progress.done), m.Format(progress.value), progress.invoiced, progress.overdue)
+	for _, milestone := range milestones {
+		due := "no due date"
+		if milestone.DueDate != nil {
+			due = "due " + milestone.DueDate.Format("2006-01-02")
+		}
+		fmt.Fprintf(s.out, "  %s: %s, %s, %s\n", milestone.Name, m.Format(milestone.Amount), due, milestoneStatus(milestone, invoiceNumbers, today))
+	}
+	return nil
+}
+
+// groupMilestonesByClient groups milestones by their client's name
+func groupMilestonesByClient(milestones []*models.Milestone) map[string][]*models.Milestone {
+	clientMilestones := make(map[string][]*models.Milestone)
+	for _, milestone := range milestones {
+		clientMilestones[milestone.ClientName] = append(clientMilestones[milestone.ClientName], milestone)
+	}
+	return clientMilestones
+}
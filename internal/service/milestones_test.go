@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+func TestSummariseMilestones(t *testing.T) {
+	today := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+	invoiceID := "invoice"
+	milestones := []*models.Milestone{
+		{Name: "Design", Amount: decimal.NewFromInt(1000), Status: models.MilestoneStatusComplete, CompletedDate: &yesterday, InvoiceID: &invoiceID},
+		{Name: "Build", Amount: decimal.NewFromInt(3000), Status: models.MilestoneStatusComplete, CompletedDate: &today},
+		{Name: "Launch", Amount: decimal.NewFromInt(2000), Status: models.MilestoneStatusPending, DueDate: &yesterday},
+		{Name: "Support", Amount: decimal.NewFromInt(500), Status: models.MilestoneStatusPending, DueDate: &today},
+	}
+
+	progress := summariseMilestones(milestones, today)
+	if progress.count != 4 || progress.completed != 2 || progress.invoiced != 1 || progress.overdue != 1 {
+		t.Errorf("progress = %d of %d complete, %d invoiced, %d overdue, want 2 of 4, 1, 1",
+			progress.completed, progress.count, progress.invoiced, progress.overdue)
+	}
+	if !progress.done.Equal(decimal.NewFromInt(4000)) || !progress.value.Equal(decimal.NewFromInt(6500)) {
+		t.Errorf("progress = %s of %s, want 4000 of 6500", progress.done, progress.value)
+	}
+
+	numbers := map[string]string{invoiceID: "INV-ACME-2025-11"}
+	for i, want := range []string{"invoiced INV-ACME-2025-11", "complete 2025-11-10", "pending, overdue", "pending"} {
+		if got := milestoneStatus(milestones[i], numbers, today); got != want {
+			t.Errorf("milestoneStatus(%s) = %q, want %q", milestones[i].Name, got, want)
+		}
+	}
+}
@@ -0,0 +1,630 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/mail"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// monthlyReportTopExpenses is how many of the month's largest expenses are listed
+const monthlyReportTopExpenses = 5
+
+// monthlyReport is a digest of a month's work and money for your own records, formatted ready to render as
+// text, HTML or a PDF. Each table is a section so every format lays out the same rows.
+type monthlyReport struct {
+	Title    string
+	Subtitle string
+	Sections []monthlyReportSection
+}
+
+// monthlyReportSection is a table of the report, or Empty when there's nothing to list. Columns after the first
+// are right aligned unless listed in Left.
+type monthlyReportSection struct {
+	Heading string
+	Columns []string
+	Rows    [][]string
+	Left    int // number of leading columns that are left aligned
+	Empty   string
+}
+
+// currencyAmounts adds up amounts that may be in more than one currency, since they can't be summed together
+type currencyAmounts struct {
+	totals     map[string]decimal.Decimal
+	formatters map[string]money.Formatter
+}
+
+func newCurrencyAmounts() *currencyAmounts {
+	return &currencyAmounts{totals: make(map[string]decimal.Decimal), formatters: make(map[string]money.Formatter)}
+}
+
+func (c *currencyAmounts) add(m money.Formatter, amount decimal.Decimal) {
+	c.totals[m.Currency] = c.totals[m.Currency].Add(amount)
+	c.formatters[m.Currency] = m
+}
+
+// single returns the total when every amount was in the one currency
+func (c *currencyAmounts) single() (decimal.Decimal, string, bool) {
+	if len(c.totals) != 1 {
+		return decimal.Zero, "", false
+	}
+	for currency, total := range c.totals {
+		return total, currency, true
+	}
+	return decimal.Zero, "", false
+}
+
+// format joins the total in each currency, e.g. "$1,200.00 + NZ$300.00", formatting nothing with home
+func (c *currencyAmounts) format(home money.Formatter) string {
+	if len(c.totals) == 0 {
+		return home.Format(decimal.Zero)
+	}
+	currencies := make([]string, 0, len(c.totals))
+	for currency := range c.totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	amounts := make([]string, len(currencies))
+	for i, currency := range currencies {
+		amounts[i] = c.formatters[currency].Format(c.totals[currency].Round(2))
+	}
+	return strings.Join(amounts, " + ")
+}
+
+// monthFigures is what a month's comparison is made from
+type monthFigures struct {
+	worked    time.Duration
+	leaveDays int
+	billable  *currencyAmounts
+	issued    int
+	invoiced  *currencyAmounts
+	paid      *currencyAmounts
+	expenses  *currencyAmounts
+}
+
+// ParseReportMonth parses a month given as YYYY-MM, defaulting to the last complete month before now
+func ParseReportMonth(month string, now time.Time) (time.Time, error) {
+	if month == "" {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -1, 0), nil
+	}
+	parsed, err := time.ParseInLocation("2006-01", month, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q, expected YYYY-MM", month)
+	}
+	return parsed, nil
+}
+
+// MonthlyReport builds the digest for the month containing month: hours and billable amounts by client,
+// invoices issued and payments received in it, what's outstanding now, its largest expenses and how it compares
+// with the month before. It's printed unless output or email is given. Output is written as a PDF or HTML page
+// by its extension, and email sends it to REPORT_EMAIL through SMTP_HOST.
+func (s *TimesheetService) MonthlyReport(ctx context.Context, month time.Time, output string, email bool) error {
+	format := ""
+	if output != "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(output)), ".")
+		if format != InvoiceFormatPDF && format != InvoiceFormatHTML {
+			return fmt.Errorf("--output must end in .pdf or .html, got %q", output)
+		}
+	}
+	if email && (s.cfg.SMTPHost == "" || s.cfg.ReportEmail == "") {
+		return fmt.Errorf("emailing the report needs SMTP_HOST and SMTP_FROM (or REPORT_EMAIL) set")
+	}
+
+	report, err := s.buildMonthlyReport(ctx, month)
+	if err != nil {
+		return err
+	}
+
+	if output == "" && !email {
+		fmt.Fprint(s.out, report.text())
+		return nil
+	}
+	if output != "" {
+		write := report.html
+		if format == InvoiceFormatPDF {
+			write = report.pdf().Output
+		}
+		if err := writeFileAtomically(output, write); err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "Monthly report written to %s\n", output)
+	}
+	if email {
+		var html bytes.Buffer
+		if err := report.html(&html); err != nil {
+			return err
+		}
+		sender := mail.NewSender(s.cfg.SMTPHost, s.cfg.SMTPPort, s.cfg.SMTPUsername, s.cfg.SMTPPassword, s.cfg.SMTPFrom)
+		if err := sender.Send(s.cfg.ReportEmail, report.Title, report.text(), html.String()); err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "Monthly report emailed to %s\n", s.cfg.ReportEmail)
+	}
+	return nil
+}
+
+func (s *TimesheetService) buildMonthlyReport(ctx context.Context, month time.Time) (*monthlyReport, error) {
+	from, to := s.CalculatePeriodRange("month", month)
+	prevFrom, prevTo := s.CalculatePeriodRange("month", from.AddDate(0, -1, 0))
+
+	clients, err := s.db.ListClients(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+	clientMoney := make(map[string]money.Formatter, len(clients))
+	for _, client := range clients {
+		clientMoney[client.ID] = s.clientMoney(client)
+	}
+	moneyFor := func(clientID *string) money.Formatter {
+		if clientID != nil {
+			if m, ok := clientMoney[*clientID]; ok {
+				return m
+			}
+		}
+		return s.homeMoney()
+	}
+
+	sessions, err := s.ListSessionsWithDateRange(ctx, prevFrom.Format("2006-01-02"), to.Format("2006-01-02"), 100000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	invoices, err := s.db.ListInvoices(ctx, -1)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.db.ListPaymentsByDateRange(ctx, prevFrom, to)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.db.ListExpensesByDateRange(ctx, prevFrom, to)
+	if err != nil {
+		return nil, err
+	}
+
+	inMonth := func(t time.Time) bool { return !t.Before(from) && !t.After(to) }
+	inPrevious := func(t time.Time) bool { return !t.Before(prevFrom) && !t.After(prevTo) }
+	current := monthFigures{billable: newCurrencyAmounts(), invoiced: newCurrencyAmounts(), paid: newCurrencyAmounts(), expenses: newCurrencyAmounts()}
+	previous := monthFigures{billable: newCurrencyAmounts(), invoiced: newCurrencyAmounts(), paid: newCurrencyAmounts(), expenses: newCurrencyAmounts()}
+	figuresFor := func(t time.Time) *monthFigures {
+		switch {
+		case inMonth(t):
+			return &current
+		case inPrevious(t):
+			return &previous
+		}
+		return nil
+	}
+
+	// Hours by client
+	type clientHours struct {
+		name             string
+		worked, previous time.Duration
+		billable         decimal.Decimal
+		m                money.Formatter
+	}
+	byClient := make(map[string]*clientHours)
+	for _, session := range sessions {
+		figures := figuresFor(session.StartTime)
+		if session.EndTime == nil || figures == nil {
+			continue
+		}
+		m := moneyFor(&session.ClientID)
+		worked, billable := s.CalculateDuration(session), s.CalculateBillableAmount(session)
+		figures.worked += worked
+		figures.billable.add(m, billable)
+
+		hours, ok := byClient[session.ClientID]
+		if !ok {
+			hours = &clientHours{name: session.ClientName, m: m}
+			byClient[session.ClientID] = hours
+		}
+		if figures == &current {
+			hours.worked += worked
+			hours.billable = hours.billable.Add(billable)
+		} else {
+			hours.previous += worked
+		}
+	}
+	ordered := make([]*clientHours, 0, len(byClient))
+	for _, hours := range byClient {
+		ordered = append(ordered, hours)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].worked != ordered[j].worked {
+			return ordered[i].worked > ordered[j].worked
+		}
+		return ordered[i].name < ordered[j].name
+	})
+	hoursSection := monthlyReportSection{
+		Heading: "Hours by client",
+		Columns: []string{"Client", "Hours", "Billable", "Last month"},
+		Left:    1,
+		Empty:   "No sessions this month.",
+	}
+	for _, hours := range ordered {
+		hoursSection.Rows = append(hoursSection.Rows, []string{hours.name,
+			s.FormatDurationFor(config.DurationContextReport, hours.worked), hours.m.Format(hours.billable.Round(2)),
+			s.FormatDurationFor(config.DurationContextReport, hours.previous)})
+	}
+
+	// Invoices issued, payments received and what's still owed
+	invoicesByID := make(map[string]*models.Invoice, len(invoices))
+	issuedSection := monthlyReportSection{
+		Heading: "Invoices issued",
+		Columns: []string{"Invoice", "Client", "Period", "Total", "Status"},
+		Left:    3,
+		Empty:   "No invoices issued this month.",
+	}
+	type clientBalance struct {
+		name     string
+		invoices int
+		balance  decimal.Decimal
+		m        money.Formatter
+	}
+	outstanding := make(map[string]*clientBalance)
+	// ListInvoices is newest first, so issued invoices are listed oldest first
+	for i := len(invoices) - 1; i >= 0; i-- {
+		invoice := invoices[i]
+		invoicesByID[invoice.ID] = invoice
+		m := moneyFor(&invoice.ClientID)
+		if figures := figuresFor(invoice.GeneratedDate); figures != nil {
+			// Void invoices are listed but were never really issued
+			if invoice.Status != models.InvoiceStatusVoid {
+				figures.issued++
+				figures.invoiced.add(m, invoice.TotalAmount)
+			}
+			if figures == &current {
+				issuedSection.Rows = append(issuedSection.Rows, []string{invoice.InvoiceNumber, invoice.ClientName,
+					fmt.Sprintf("%s to %s", invoice.PeriodStartDate.Format("2006-01-02"), invoice.PeriodEndDate.Format("2006-01-02")),
+					m.Format(invoice.TotalAmount), invoiceStatus(invoice)})
+			}
+		}
+		if balance := invoiceBalance(invoice); balance.IsPositive() {
+			owed, ok := outstanding[invoice.ClientID]
+			if !ok {
+				owed = &clientBalance{name: invoice.ClientName, m: m}
+				outstanding[invoice.ClientID] = owed
+			}
+			owed.invoices++
+			owed.balance = owed.balance.Add(balance)
+		}
+	}
+
+	paidSection := monthlyReportSection{
+		Heading: "Payments received",
+		Columns: []string{"Date", "Invoice", "Client", "Amount"},
+		Left:    3,
+		Empty:   "No payments received this month.",
+	}
+	for _, payment := range payments {
+		figures := figuresFor(payment.PaymentDate)
+		invoice, ok := invoicesByID[payment.InvoiceID]
+		if figures == nil || !ok {
+			continue
+		}
+		m := moneyFor(&invoice.ClientID)
+		figures.paid.add(m, payment.Amount)
+		if figures == &current {
+			paidSection.Rows = append(paidSection.Rows, []string{payment.PaymentDate.Format("2006-01-02"),
+				invoice.InvoiceNumber, invoice.ClientName, m.Format(payment.Amount)})
+		}
+	}
+
+	balances := make([]*clientBalance, 0, len(outstanding))
+	for _, owed := range outstanding {
+		balances = append(balances, owed)
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if !balances[i].balance.Equal(balances[j].balance) {
+			return balances[i].balance.GreaterThan(balances[j].balance)
+		}
+		return balances[i].name < balances[j].name
+	})
+	outstandingSection := monthlyReportSection{
+		Heading: "Outstanding balances",
+		Columns: []string{"Client", "Unpaid invoices", "Balance"},
+		Left:    1,
+		Empty:   "Nothing outstanding, every invoice is paid.",
+	}
+	for _, owed := range balances {
+		outstandingSection.Rows = append(outstandingSection.Rows, []string{owed.name, fmt.Sprintf("%d", owed.invoices), owed.m.Format(owed.balance)})
+	}
+
+	// Largest expenses
+	var monthExpenses []*models.Expense
+	for _, expense := range expenses {
+		figures := figuresFor(expense.ExpenseDate)
+		if figures == nil {
+			continue
+		}
+		figures.expenses.add(moneyFor(expense.ClientID), expense.Amount)
+		if figures == &current {
+			monthExpenses = append(monthExpenses, expense)
+		}
+	}
+	sort.SliceStable(monthExpenses, func(i, j int) bool { return monthExpenses[i].Amount.GreaterThan(monthExpenses[j].Amount) })
+	if len(monthExpenses) > monthlyReportTopExpenses {
+		monthExpenses = monthExpenses[:monthlyReportTopExpenses]
+	}
+	leave, err := s.db.ListLeaveInRange(ctx, prevFrom, to)
+	if err != nil {
+		return nil, err
+	}
+	leaveSection := monthlyReportSection{
+		Heading: "Leave",
+		Columns: []string{"From", "To", "Type", "Working days"},
+		Left:    3,
+	}
+	for _, l := range leave {
+		current.leaveDays += countWorkingDays(maxTime(l.StartDate, from), minTime(l.EndDate, to))
+		previous.leaveDays += countWorkingDays(maxTime(l.StartDate, prevFrom), minTime(l.EndDate, prevTo))
+		if !l.StartDate.After(to) && !l.EndDate.Before(from) {
+			leaveSection.Rows = append(leaveSection.Rows, []string{l.StartDate.Format("2006-01-02"), l.EndDate.Format("2006-01-02"),
+				leaveLabel(l.Type), fmt.Sprintf("%d", countWorkingDays(maxTime(l.StartDate, from), minTime(l.EndDate, to)))})
+		}
+	}
+
+	expensesSection := monthlyReportSection{
+		Heading: "Top expenses",
+		Columns: []string{"Date", "Client", "Category", "Description", "Amount"},
+		Left:    4,
+		Empty:   "No expenses this month.",
+	}
+	for _, expense := range monthExpenses {
+		client, category, description := "", "", ""
+		if expense.ClientName != nil {
+			client = *expense.ClientName
+		}
+		if expense.Category != nil {
+			category = *expense.Category
+		}
+		if expense.Description != nil {
+			description = truncateString(*expense.Description, 40)
+		} else if expense.Reference != nil {
+			description = truncateString(*expense.Reference, 40)
+		}
+		expensesSection.Rows = append(expensesSection.Rows, []string{expense.ExpenseDate.Format("2006-01-02"),
+			client, category, description, moneyFor(expense.ClientID).Format(expense.Amount)})
+	}
+
+	home := s.homeMoney()
+	hoursChange := ""
+	if previous.worked > 0 {
+		hoursChange = percentChange(decimal.NewFromFloat(current.worked.Hours()), decimal.NewFromFloat(previous.worked.Hours()))
+	}
+	issuedChange := ""
+	if previous.issued > 0 {
+		issuedChange = percentChange(decimal.NewFromInt(int64(current.issued)), decimal.NewFromInt(int64(previous.issued)))
+	}
+	summary := monthlyReportSection{
+		Heading: "Compared with last month",
+		Columns: []string{"", from.Format("January"), prevFrom.Format("January"), "Change"},
+		Left:    1,
+		Rows: [][]string{
+			{"Hours worked", s.FormatDurationFor(config.DurationContextReport, current.worked),
+				s.FormatDurationFor(config.DurationContextReport, previous.worked), hoursChange},
+			{"Days of leave", fmt.Sprintf("%d", current.leaveDays), fmt.Sprintf("%d", previous.leaveDays), ""},
+			{"Billable", current.billable.format(home), previous.billable.format(home), amountChange(current.billable, previous.billable)},
+			{"Invoices issued", fmt.Sprintf("%d", current.issued), fmt.Sprintf("%d", previous.issued), issuedChange},
+			{"Invoiced", current.invoiced.format(home), previous.invoiced.format(home), amountChange(current.invoiced, previous.invoiced)},
+			{"Payments received", current.paid.format(home), previous.paid.format(home), amountChange(current.paid, previous.paid)},
+			{"Expenses", current.expenses.format(home), previous.expenses.format(home), amountChange(current.expenses, previous.expenses)},
+		},
+	}
+
+	title := fmt.Sprintf("Monthly report - %s", from.Format("January 2006"))
+	if s.cfg.BillingCompanyName != "" {
+		title = fmt.Sprintf("%s monthly report - %s", s.cfg.BillingCompanyName, from.Format("January 2006"))
+	}
+	sections := []monthlyReportSection{summary, hoursSection}
+	if len(leaveSection.Rows) > 0 {
+		sections = append(sections, leaveSection)
+	}
+	sections = append(sections, issuedSection, paidSection, outstandingSection, expensesSection)
+	return &monthlyReport{
+		Title:    title,
+		Subtitle: fmt.Sprintf("%s to %s, outstanding balances as at %s", from.Format("2006-01-02"), to.Format("2006-01-02"), time.Now().Format("2006-01-02")),
+		Sections: sections,
+	}, nil
+}
+
+// percentChange describes the change from previous to current, e.g. "+12.5%"
+func percentChange(current, previous decimal.Decimal) string {
+	if !previous.IsPositive() {
+		return ""
+	}
+	change := current.Sub(previous).Div(previous).Mul(decimal.NewFromInt(100))
+	if change.IsNegative() {
+		return change.StringFixed(1) + "%"
+	}
+	return "+" + change.StringFixed(1) + "%"
+}
+
+// amountChange compares two months' amounts when both are in the one currency
+func amountChange(current, previous *currencyAmounts) string {
+	previousTotal, previousCurrency, ok := previous.single()
+	if !ok {
+		return ""
+	}
+	currentTotal, currentCurrency, ok := current.single()
+	if !ok {
+		if len(current.totals) == 0 {
+			currentTotal, currentCurrency = decimal.Zero, previousCurrency
+		} else {
+			return ""
+		}
+	}
+	if currentCurrency != previousCurrency {
+		return ""
+	}
+	return percentChange(currentTotal, previousTotal)
+}
+
+// LeftAligned reports whether column i of the section is left aligned
+func (sec monthlyReportSection) LeftAligned(i int) bool {
+	return i < sec.Left
+}
+
+func (r *monthlyReport) text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n%s\n", r.Title, r.Subtitle)
+	for _, sec := range r.Sections {
+		fmt.Fprintf(&b, "\n%s\n", sec.Heading)
+		if len(sec.Rows) == 0 {
+			fmt.Fprintf(&b, "  %s\n", sec.Empty)
+			continue
+		}
+		widths := make([]int, len(sec.Columns))
+		for i, column := range sec.Columns {
+			widths[i] = len(column)
+		}
+		for _, row := range sec.Rows {
+			for i, cell := range row {
+				widths[i] = max(widths[i], len([]rune(cell)))
+			}
+		}
+		line := func(cells []string) {
+			parts := make([]string, len(cells))
+			for i, cell := range cells {
+				if sec.LeftAligned(i) {
+					parts[i] = fmt.Sprintf("%-*s", widths[i], cell)
+				} else {
+					parts[i] = fmt.Sprintf("%*s", widths[i], cell)
+				}
+			}
+			fmt.Fprintf(&b, "  %s\n", strings.TrimRight(strings.Join(parts, "  "), " "))
+		}
+		line(sec.Columns)
+		for _, row := range sec.Rows {
+			line(row)
+		}
+	}
+	return b.String()
+}
+
+// monthlyReportHTMLTemplate uses inline styles for the same reason as invoiceHTMLTemplate, as it's emailed
+var monthlyReportHTMLTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body style="margin:0;padding:0;background:#f4f4f4;font-family:Arial,Helvetica,sans-serif;color:#222;">
+<div style="max-width:720px;margin:0 auto;padding:16px;background:#fff;">
+<h1 style="font-size:20px;margin:0 0 4px;">{{.Title}}</h1>
+<p style="font-size:13px;margin:0 0 16px;color:#555;">{{.Subtitle}}</p>
+{{- range .Sections}}
+<h2 style="font-size:16px;margin:24px 0 4px;">{{.Heading}}</h2>
+{{- if .Rows}}
+{{- $sec := .}}
+<div style="overflow-x:auto;">
+<table style="width:100%;border-collapse:collapse;font-size:13px;">
+<tr style="background:#eee;">
+{{- range $i, $column := .Columns}}
+<th style="padding:6px;text-align:{{if $sec.LeftAligned $i}}left{{else}}right{{end}};">{{$column}}</th>
+{{- end}}
+</tr>
+{{- range .Rows}}
+<tr style="border-bottom:1px solid #ddd;">
+{{- range $i, $cell := .}}
+<td style="padding:6px;white-space:nowrap;text-align:{{if $sec.LeftAligned $i}}left{{else}}right{{end}};">{{$cell}}</td>
+{{- end}}
+</tr>
+{{- end}}
+</table>
+</div>
+{{- else}}
+<p style="font-size:13px;margin:0;">{{.Empty}}</p>
+{{- end}}
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+func (r *monthlyReport) html(w io.Writer) error {
+	if err := monthlyReportHTMLTemplate.Execute(w, r); err != nil {
+		return fmt.Errorf("failed to render monthly report HTML: %w", err)
+	}
+	return nil
+}
+
+func (r *monthlyReport) pdf() *gofpdf.Fpdf {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, tr(r.Title))
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "", 10)
+	pdf.Cell(40, 6, tr(r.Subtitle))
+	pdf.Ln(10)
+
+	for _, sec := range r.Sections {
+		pdf.SetFont("Arial", "B", 12)
+		pdf.Cell(40, 8, tr(sec.Heading))
+		pdf.Ln(8)
+		if len(sec.Rows) == 0 {
+			pdf.SetFont("Arial", "", 10)
+			pdf.Cell(40, 6, tr(sec.Empty))
+			pdf.Ln(10)
+			continue
+		}
+
+		// Columns share the page width in proportion to their widest cell
+		widths := make([]float64, len(sec.Columns))
+		total := 0.0
+		pdf.SetFont("Arial", "B", 10)
+		for i, column := range sec.Columns {
+			widths[i] = pdf.GetStringWidth(column) + 4
+		}
+		pdf.SetFont("Arial", "", 10)
+		for _, row := range sec.Rows {
+			for i, cell := range row {
+				widths[i] = max(widths[i], pdf.GetStringWidth(tr(cell))+4)
+			}
+		}
+		for _, width := range widths {
+			total += width
+		}
+		for i := range widths {
+			widths[i] = widths[i] * 190 / total
+		}
+
+		align := func(i int) string {
+			if sec.LeftAligned(i) {
+				return "L"
+			}
+			return "R"
+		}
+		pdf.SetFont("Arial", "B", 10)
+		for i, column := range sec.Columns {
+			pdf.CellFormat(widths[i], 7, tr(column), "B", 0, align(i), false, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Arial", "", 10)
+		for _, row := range sec.Rows {
+			for i, cell := range row {
+				pdf.CellFormat(widths[i], 6, tr(cell), "", 0, align(i), false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+		pdf.Ln(6)
+	}
+	return pdf
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// sessionNoteTimeLayout is how a note's time is kept in its bullet, e.g. "- [2025-03-14 10:30] Called the client"
+const sessionNoteTimeLayout = "2006-01-02 15:04"
+
+// sessionNote is one of the notes on a session, for work that won't show up in git. Notes are kept one bullet
+// per line in the session's outside_git text, and those added before notes were timestamped have no time.
+type sessionNote struct {
+	at   *time.Time
+	text string
+}
+
+// parseSessionNotes reads the notes kept in a session's outside_git text
+func parseSessionNotes(text string) []sessionNote {
+	var notes []sessionNote
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "-"))
+		if line == "" {
+			continue
+		}
+		note := sessionNote{text: line}
+		if stamp, rest, ok := strings.Cut(line, "] "); ok && strings.HasPrefix(stamp, "[") {
+			if at, err := time.ParseInLocation(sessionNoteTimeLayout, stamp[1:], time.Local); err == nil {
+				note.at, note.text = &at, strings.TrimSpace(rest)
+			}
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
+
+// formatSessionNotes writes notes back to outside_git text
+func formatSessionNotes(notes []sessionNote) string {
+	lines := make([]string, len(notes))
+	for i, note := range notes {
+		if note.at != nil {
+			lines[i] = fmt.Sprintf("- [%s] %s", note.at.Format(sessionNoteTimeLayout), note.text)
+		} else {
+			lines[i] = "- " + note.text
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sessionNotesText lists a session's notes as bullets for invoices, with the time of day each was added when
+// withTimes is set, e.g. "- 10:30 Called the client"
+func sessionNotesText(outsideGit *string, withTimes bool) string {
+	if outsideGit == nil {
+		return ""
+	}
+	notes := parseSessionNotes(*outsideGit)
+	lines := make([]string, len(notes))
+	for i, note := range notes {
+		if withTimes && note.at != nil {
+			lines[i] = fmt.Sprintf("- %s %s", note.at.Format("15:04"), note.text)
+		} else {
+			lines[i] = "- " + note.text
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// AddSessionNote adds a note about work outside git to a session, timestamped now
+func (s *TimesheetService) AddSessionNote(ctx context.Context, sessionID string, note string, force bool) (*models.WorkSession, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, fmt.Errorf("note text is required")
+	}
+	session, err := s.db.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	var notes []sessionNote
+	if session.OutsideGit != nil {
+		notes = parseSessionNotes(*session.OutsideGit)
+	}
+	now := time.Now()
+	notes = append(notes, sessionNote{at: &now, text: note})
+	return s.updateSessionNotes(ctx, session.ID, notes, force)
+}
+
+// EditSessionNote replaces the text of a session's note, numbered from 1, keeping the time it was added
+func (s *TimesheetService) EditSessionNote(ctx context.Context, sessionID string, number int, text string, force bool) (*models.WorkSession, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("note text is required, use --delete to remove a note")
+	}
+	session, notes, err := s.sessionNotes(ctx, sessionID, number)
+	if err != nil {
+		return nil, err
+	}
+	notes[number-1].text = text
+	return s.updateSessionNotes(ctx, session.ID, notes, force)
+}
+
+// DeleteSessionNote removes a session's note, numbered from 1
+func (s *TimesheetService) DeleteSessionNote(ctx context.Context, sessionID string, number int, force bool) (*models.WorkSession, error) {
+	session, notes, err := s.sessionNotes(ctx, sessionID, number)
+	if err != nil {
+		return nil, err
+	}
+	notes = append(notes[:number-1], notes[number:]...)
+	return s.updateSessionNotes(ctx, session.ID, notes, force)
+}
+
+// sessionNotes returns a session and its notes, checking it has the note numbered number
+func (s *TimesheetService) sessionNotes(ctx context.Context, sessionID string, number int) (*models.WorkSession, []sessionNote, error) {
+	session, err := s.db.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	var notes []sessionNote
+	if session.OutsideGit != nil {
+		notes = parseSessionNotes(*session.OutsideGit)
+	}
+	if number < 1 || number > len(notes) {
+		if len(notes) == 0 {
+			return nil, nil, fmt.Errorf("session %s has no notes", session.ID)
+		}
+		return nil, nil, fmt.Errorf("session %s has notes 1 to %d, not %d", session.ID, len(notes), number)
+	}
+	return session, notes, nil
+}
+
+func (s *TimesheetService) updateSessionNotes(ctx context.Context, sessionID string, notes []sessionNote, force bool) (*models.WorkSession, error) {
+	session, err := s.db.UpdateSessionOutsideGit(ctx, sessionID, formatSessionNotes(notes), force)
+	if err != nil {
+		return nil, invoicedSessionsError(err)
+	}
+	return session, nil
+}
+
+// DisplaySessionNotes lists a session's notes numbered from 1, with when each was added, for editing or
+// deleting by number
+func (s *TimesheetService) DisplaySessionNotes(session *models.WorkSession) {
+	var notes []sessionNote
+	if session.OutsideGit != nil {
+		notes = parseSessionNotes(*session.OutsideGit)
+	}
+	if len(notes) == 0 {
+		fmt.Fprintln(s.out, "No notes on this session.")
+		return
+	}
+	for i, note := range notes {
+		at := ""
+		if note.at != nil {
+			at = note.at.Format(sessionNoteTimeLayout) + " "
+		}
+		fmt.Fprintf(s.out, "%d. %s%s\n", i+1, at, note.text)
+	}
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/db"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// batchPayment is a validated row from a payments file
+type batchPayment struct {
+	line      int
+	invoice   *models.Invoice
+	amount    decimal.Decimal
+	date      time.Time
+	discount  decimal.Decimal // early payment discount the payment settles the invoice with
+	paidAfter decimal.Decimal
+}
+
+// PayInvoicesFromFile records payments listed in a CSV file of invoice number, amount and date (YYYY-MM-DD)
+// per row. An empty amount pays the remaining balance, less any early payment discount the client is due, and an
+// empty date means today. Every row is validated before anything is recorded, and the payments are recorded in
+// a single transaction.
+func (s *TimesheetService) PayInvoicesFromFile(ctx context.Context, fileName string, dryRun bool) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to open payments file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var payments []*batchPayment
+	var problems []string
+	// Track balances across rows so several part payments of one invoice are checked together
+	paidSoFar := make(map[string]decimal.Decimal)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read payments file: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(payments) == 0 && len(problems) == 0 && isPaymentsHeader(record) {
+			continue
+		}
+
+		payment, err := s.parseBatchPayment(ctx, record, paidSoFar)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		payment.line = line
+		payments = append(payments, payment)
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintln(s.out, "No payments recorded, fix these rows and try again:")
+		for _, problem := range problems {
+			fmt.Fprintf(s.out, "  %s\n", problem)
+		}
+		return fmt.Errorf("%d row(s) in %s have errors", len(problems), fileName)
+	}
+
+	if len(payments) == 0 {
+		fmt.Fprintln(s.out, "No payments found in file")
+		return nil
+	}
+
+	if !dryRun {
+		params := make([]db.PayInvoiceParams, len(payments))
+		for i, payment := range payments {
+			params[i] = db.PayInvoiceParams{
+				ID:             models.NewUUID(),
+				InvoiceID:      payment.invoice.ID,
+				Amount:         payment.amount,
+				PaymentDate:    payment.date,
+				DiscountAmount: payment.discount,
+			}
+		}
+		if err := s.db.PayInvoices(ctx, params); err != nil {
+			return err
+		}
+		for _, payment := range payments {
+			if err := s.syncInvoicePaidStatus(ctx, payment.invoice.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.printBatchPaymentSummary(payments, dryRun)
+	if !dryRun {
+		for _, payment := range payments {
+			m := s.clientMoneyByName(payment.invoice.ClientName)
+			owed := invoiceOwed(payment.invoice).Sub(payment.discount)
+			s.publishInvoicePaid(ctx, payment.invoice, m.Format(payment.amount), m.Format(payment.paidAfter), m.Format(owed), payment.status())
+		}
+	}
+	return nil
+}
+
+func (s *TimesheetService) parseBatchPayment(ctx context.Context, record []string, paidSoFar map[string]decimal.Decimal) (*batchPayment, error) {
+	if len(record) < 1 || len(record) > 3 {
+		return nil, fmt.Errorf("expected invoice number, amount and date, got %d column(s)", len(record))
+	}
+	field := func(i int) string {
+		if i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	invoiceNumber := field(0)
+	if invoiceNumber == "" {
+		return nil, fmt.Errorf("missing invoice number")
+	}
+	invoice, err := s.db.GetInvoiceByNumber(ctx, invoiceNumber)
+	if err != nil || invoice == nil {
+		return nil, fmt.Errorf("invoice %s not found", invoiceNumber)
+	}
+	if err := checkInvoiceIssued(invoice, "paid"); err != nil {
+		return nil, err
+	}
+
+	paid, ok := paidSoFar[invoice.ID]
+	if !ok {
+		paid = invoice.AmountPaid
+	}
+	remaining := invoiceOwed(invoice).Sub(paid)
+	m := s.clientMoneyByName(invoice.ClientName)
+	if remaining.LessThanOrEqual(decimal.Zero) {
+		return nil, fmt.Errorf("invoice %s is already fully paid", invoiceNumber)
+	}
+
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location())
+	if dateStr := field(2); dateStr != "" {
+		date, err = time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", dateStr)
+		}
+	}
+
+	client, _ := s.db.GetClientByID(ctx, invoice.ClientID)
+	available := earlyPaymentDiscount(client, invoice, date)
+
+	amount := remaining.Sub(available)
+	if amountStr := field(1); amountStr != "" {
+		amount, err = decimal.NewFromString(strings.NewReplacer("$", "", ",", "", " ", "").Replace(amountStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount %q", amountStr)
+		}
+	}
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, fmt.Errorf("amount must be greater than 0")
+	}
+	if amount.GreaterThan(remaining) {
+		return nil, fmt.Errorf("payment amount (%s) exceeds remaining balance of %s (%s)", m.Format(amount), invoiceNumber, m.Format(remaining))
+	}
+	discount := discountTaken(amount, remaining, available)
+
+	// The discount counts towards the balance so later rows for the invoice see it settled
+	paidSoFar[invoice.ID] = paid.Add(amount).Add(discount)
+	return &batchPayment{invoice: invoice, amount: amount, date: date, discount: discount, paidAfter: paid.Add(amount)}, nil
+}
+
+// status describes the invoice once the payment is recorded
+func (p *batchPayment) status() string {
+	if p.paidAfter.GreaterThanOrEqual(invoiceOwed(p.invoice).Sub(p.discount)) {
+		return "fully paid"
+	}
+	return "partially paid"
+}
+
+// isPaymentsHeader reports whether a row is a column header rather than a payment
+func isPaymentsHeader(record []string) bool {
+	if len(record) < 2 {
+		return false
+	}
+	_, err := decimal.NewFromString(strings.NewReplacer("$", "", ",", "", " ", "").Replace(strings.TrimSpace(record[1])))
+	return err != nil && strings.TrimSpace(record[1]) != ""
+}
+
+func (s *TimesheetService) printBatchPaymentSummary(payments []*batchPayment, dryRun bool) {
+	if dryRun {
+		fmt.Fprintf(s.out, "Would record %d payment(s):\n", len(payments))
+	} else {
+		fmt.Fprintf(s.out, "Recorded %d payment(s):\n", len(payments))
+	}
+
+	fmt.Fprintf(s.out, "%-30s %-20s %-12s %-12s %s\n", "Invoice", "Client", "Date", "Amount", "Status")
+	fmt.Fprintln(s.out, strings.Repeat("-", 95))
+
+	totals := make(map[string]decimal.Decimal)
+	formatters := make(map[string]money.Formatter)
+	for _, payment := range payments {
+		m := s.clientMoneyByName(payment.invoice.ClientName)
+		status := payment.status()
+		owed := invoiceOwed(payment.invoice).Sub(payment.discount)
+		fmt.Fprintf(s.out, "%-30s %-20s %-12s %-12s %s (%s/%s)\n",
+			truncateString(payment.invoice.InvoiceNumber, 30),
+			truncateString(payment.invoice.ClientName, 20),
+			payment.date.Format("2006-01-02"),
+			m.Format(payment.amount),
+			status,
+			m.Format(payment.paidAfter),
+			m.Format(owed),
+		)
+		totals[m.Currency] = totals[m.Currency].Add(payment.amount)
+		formatters[m.Currency] = m
+	}
+
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		fmt.Fprintf(s.out, "Total %s: %s\n", currency, formatters[currency].Format(totals[currency]))
+	}
+}
+
+// ListPayments prints payments recorded against invoices, oldest first, filtered to an invoice (by ID or
+// number) and/or a client when given, with the total received in each currency
+func (s *TimesheetService) ListPayments(ctx context.Context, invoiceRef, clientName string) error {
+	var invoiceID string
+	if invoiceRef != "" {
+		invoice, err := s.getInvoiceByIDOrNumber(ctx, invoiceRef)
+		if err != nil {
+			return err
+		}
+		invoiceID = invoice.ID
+	}
+	if clientName != "" {
+		if _, err := s.db.GetClientByName(ctx, clientName); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("client '%s' does not exist", clientName)
+			}
+			return fmt.Errorf("failed to get client: %w", err)
+		}
+	}
+
+	payments, err := s.db.ListPayments(ctx, invoiceID, clientName)
+	if err != nil {
+		return err
+	}
+	if len(payments) == 0 {
+		fmt.Fprintln(s.out, "No payments found.")
+		return nil
+	}
+
+	fmt.Fprintf(s.out, "%-38s %-12s %-30s %-20s %-12s %s\n", "ID", "Date", "Invoice", "Client", "Amount", "Discount")
+	fmt.Fprintln(s.out, strings.Repeat("-", 125))
+
+	totals := make(map[string]decimal.Decimal)
+	formatters := make(map[string]money.Formatter)
+	for _, payment := range payments {
+		m := s.clientMoneyByName(payment.ClientName)
+		discount := ""
+		if payment.DiscountAmount.IsPositive() {
+			discount = m.Format(payment.DiscountAmount)
+		}
+		fmt.Fprintf(s.out, "%-38s %-12s %-30s %-20s %-12s %s\n",
+			payment.ID,
+			payment.PaymentDate.Format("2006-01-02"),
+			truncateString(payment.InvoiceNumber, 30),
+			truncateString(payment.ClientName, 20),
+			m.Format(payment.Amount),
+			discount,
+		)
+		totals[m.Currency] = totals[m.Currency].Add(payment.Amount)
+		formatters[m.Currency] = m
+	}
+
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		fmt.Fprintf(s.out, "Total %s: %s\n", currency, formatters[currency].Format(totals[currency]))
+	}
+	return nil
+}
+
+// DeletePayment removes a payment recorded in error, along with any early payment discount it took, and prints
+// what's now owed on its invoice
+func (s *TimesheetService) DeletePayment(ctx context.Context, paymentID string) error {
+	payment, err := s.db.GetPaymentByID(ctx, paymentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("payment '%s' does not exist", paymentID)
+		}
+		return err
+	}
+	if err := s.db.DeletePayment(ctx, payment.ID); err != nil {
+		return err
+	}
+	if err := s.syncInvoicePaidStatus(ctx, payment.InvoiceID); err != nil {
+		return err
+	}
+
+	invoice, err := s.db.GetInvoiceByID(ctx, payment.InvoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to get invoice: %w", err)
+	}
+	m := s.clientMoneyByName(invoice.ClientName)
+	fmt.Fprintf(s.out, "Deleted payment of %s on %s from invoice %s (now %s: %s/%s)\n",
+		m.Format(payment.Amount), payment.PaymentDate.Format("2006-01-02"), invoice.InvoiceNumber,
+		strings.ToLower(invoiceStatus(invoice)), m.Format(invoice.AmountPaid), m.Format(invoiceOwed(invoice)))
+	return nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// PeriodTypes are the periods hours, sessions and invoices can be grouped by. Quarters and years follow
+// the financial year set by FY_START_MONTH.
+var PeriodTypes = []string{"day", "week", "fortnight", "month", "quarter", "year"}
+
+// CustomPeriod is the period type of an invoice covering an explicit --from/--to range
+const CustomPeriod = "custom"
+
+// ValidatePeriod returns an error if period is not one of PeriodTypes
+func ValidatePeriod(period string) error {
+	for _, known := range PeriodTypes {
+		if period == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported period %q, expected one of: %s", period, strings.Join(PeriodTypes, ", "))
+}
+
+// ResolvePeriodRange works out the dates (YYYY-MM-DD) covered by either the period containing date, which
+// defaults to today, or an explicit from/to range where either end may be left open. Giving both a period
+// and a range is an error since it's unclear which was meant.
+func (s *TimesheetService) ResolvePeriodRange(period, date, fromDate, toDate string) (string, string, error) {
+	if period == "" {
+		if date != "" {
+			return "", "", fmt.Errorf("--date needs a --period")
+		}
+		for _, d := range []string{fromDate, toDate} {
+			if d == "" {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", d); err != nil {
+				return "", "", fmt.Errorf("invalid date %q, expected YYYY-MM-DD", d)
+			}
+		}
+		return fromDate, toDate, nil
+	}
+
+	if fromDate != "" || toDate != "" {
+		return "", "", fmt.Errorf("use either --period or --from/--to, not both")
+	}
+	if err := ValidatePeriod(period); err != nil {
+		return "", "", err
+	}
+
+	targetDate := time.Now()
+	if date != "" {
+		var err error
+		targetDate, err = time.Parse("2006-01-02", date)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+		}
+	}
+
+	from, to := s.CalculatePeriodRange(period, targetDate)
+	return from.Format("2006-01-02"), to.Format("2006-01-02"), nil
+}
+
+// financialYearStartMonth is the configured FY_START_MONTH, or July when there's no config
+func (s *TimesheetService) financialYearStartMonth() time.Month {
+	if s.cfg == nil || s.cfg.FinancialYearStart == 0 {
+		return time.July
+	}
+	return s.cfg.FinancialYearStart
+}
+
+// financialYearStart returns the first day of the financial year containing t
+func (s *TimesheetService) financialYearStart(t time.Time) time.Time {
+	month := s.financialYearStartMonth()
+	year := t.Year()
+	if t.Month() < month {
+		year--
+	}
+	return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+}
+
+// financialYear names the financial year containing t by the calendar year it ends in, so with a July
+// start 2024-08-01 is in FY2025
+func (s *TimesheetService) financialYear(t time.Time) int {
+	start := s.financialYearStart(t)
+	if start.Month() == time.January {
+		return start.Year()
+	}
+	return start.Year() + 1
+}
+
+// financialYearLabel is how a financial year is shown in reports: "FY2025", or just "2025" when the
+// financial year is the calendar year
+func (s *TimesheetService) financialYearLabel(year int) string {
+	if s.financialYearStartMonth() == time.January {
+		return fmt.Sprintf("%d", year)
+	}
+	return fmt.Sprintf("FY%d", year)
+}
+
+// reportYear is the year t is reported under, its financial year when byFinancialYear is set or its
+// calendar year otherwise
+func (s *TimesheetService) reportYear(t time.Time, byFinancialYear bool) int {
+	if byFinancialYear {
+		return s.financialYear(t)
+	}
+	return t.Year()
+}
+
+// reportYearLabel is the heading for a year returned by reportYear
+func (s *TimesheetService) reportYearLabel(year int, byFinancialYear bool) string {
+	if byFinancialYear {
+		return s.financialYearLabel(year)
+	}
+	return fmt.Sprintf("%d", year)
+}
+
+// regenerateInvoiceCommand is the command that regenerates an invoice, for hints after its contents change
+func regenerateInvoiceCommand(invoice *models.Invoice) string {
+	if invoice.PeriodType == CustomPeriod {
+		return fmt.Sprintf("work invoices regenerate --from %s --to %s -c %s",
+			invoice.PeriodStartDate.Format("2006-01-02"), invoice.PeriodEndDate.Format("2006-01-02"), invoice.ClientName)
+	}
+	return fmt.Sprintf("work invoices regenerate -p %s -d %s -c %s",
+		invoice.PeriodType, invoice.PeriodStartDate.Format("2006-01-02"), invoice.ClientName)
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BillingPrecision is the unit sessions are billed and shown in. Start and end times are stored to the second,
+// durations are rounded to the nearest whole unit wherever they're used, and each session's amount is rounded to
+// the cent, so session lists, CSV exports and invoice PDFs all agree.
+const BillingPrecision = time.Minute
+
+// unitsPerHour is how many BillingPrecision units make an hour
+var unitsPerHour = decimal.NewFromInt(int64(time.Hour / BillingPrecision))
+
+// billedDuration rounds a duration to BillingPrecision
+func billedDuration(d time.Duration) time.Duration {
+	return d.Round(BillingPrecision)
+}
+
+// durationHours is a duration in hours as a decimal, counted in whole BillingPrecision units rather than the
+// float seconds time.Duration.Hours gives
+func durationHours(d time.Duration) decimal.Decimal {
+	return decimal.NewFromInt(int64(billedDuration(d) / BillingPrecision)).Div(unitsPerHour)
+}
+
+// billedAmount is what a duration is billed at an hourly rate, to the cent. Multiplying before dividing keeps 20
+// minutes at $90 at exactly $30.
+func billedAmount(d time.Duration, rate decimal.Decimal) decimal.Decimal {
+	return decimal.NewFromInt(int64(billedDuration(d) / BillingPrecision)).Mul(rate).Div(unitsPerHour).Round(2)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+func newPrecisionSession(start time.Time, worked time.Duration, breakSeconds int64, rate string) *models.WorkSession {
+	end := start.Add(worked)
+	hourlyRate := decimal.RequireFromString(rate)
+	return &models.WorkSession{
+		ID:           models.NewUUID(),
+		StartTime:    start,
+		EndTime:      &end,
+		HourlyRate:   &hourlyRate,
+		BreakSeconds: breakSeconds,
+	}
+}
+
+func TestCalculateDurationRoundsToBillingPrecision(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{})
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		worked       time.Duration
+		breakSeconds int64
+		want         time.Duration
+	}{
+		{"seconds short of an hour", 59*time.Minute + 40*time.Second, 0, time.Hour},
+		{"seconds over an hour", time.Hour + 29*time.Second, 0, time.Hour},
+		{"half a minute rounds up", 90*time.Minute + 30*time.Second, 0, 91 * time.Minute},
+		{"breaks come out before rounding", 2*time.Hour + 10*time.Second, 15*60 + 20, 105 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.CalculateDuration(newPrecisionSession(start, tt.worked, tt.breakSeconds, "100"))
+			if got != tt.want {
+				t.Errorf("CalculateDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateBillableAmountBillsByTheMinute(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{})
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		worked time.Duration
+		rate   string
+		want   string
+	}{
+		{"twenty minutes is a third of the rate exactly", 20 * time.Minute, "90", "30"},
+		{"seconds short of an hour bill the hour", 59*time.Minute + 40*time.Second, "100", "100"},
+		{"stray seconds aren't billed", 45*time.Minute + 12*time.Second, "120", "90"},
+		{"amounts are to the cent", 20 * time.Minute, "137.50", "45.83"},
+		{"no rate bills nothing", time.Hour, "0", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.CalculateBillableAmount(newPrecisionSession(start, tt.worked, 0, tt.rate))
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("CalculateBillableAmount() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Session lists and CSV exports show CalculateBillableAmount, and the invoice PDF shows its lines and totals, so
+// they must agree to the cent
+func TestBilledAmountsAgreeAcrossOutputs(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{})
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	sessions := []*models.WorkSession{
+		newPrecisionSession(start, 59*time.Minute+40*time.Second, 0, "137.50"),
+		newPrecisionSession(start.Add(2*time.Hour), 20*time.Minute+29*time.Second, 0, "137.50"),
+		newPrecisionSession(start.Add(4*time.Hour), 3*time.Hour+7*time.Minute+31*time.Second, 47, "95"),
+	}
+
+	var listed decimal.Decimal
+	for _, session := range sessions {
+		listed = listed.Add(s.CalculateBillableAmount(session))
+	}
+
+	var lined decimal.Decimal
+	for _, line := range s.invoiceLines(sessions, retainerTerms{}, models.InvoiceGroupBySession, func(d decimal.Decimal) string { return d.String() }) {
+		lined = lined.Add(line.amount)
+	}
+
+	totals := s.calculateInvoiceTotals(nil, sessions, nil, nil, retainerTerms{})
+
+	if !listed.Equal(lined) || !listed.Equal(totals.sessions) {
+		t.Errorf("session amounts = %s, invoice lines = %s, invoice total = %s, want all equal", listed, lined, totals.sessions)
+	}
+}
+
+func TestRoundTotalKeepsTheAdjustment(t *testing.T) {
+	tests := []struct {
+		name, total, rounding string
+		want, adjustment      string
+	}{
+		{"nearest dollar down", "1237.43", "1", "1237", "-0.43"},
+		{"nearest dollar up", "1237.50", "1", "1238", "0.5"},
+		{"nearest five", "1237.43", "5", "1235", "-2.43"},
+		{"already round", "1240", "5", "1240", "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rounding := decimal.RequireFromString(tt.rounding)
+			totals := invoiceTotals{total: decimal.RequireFromString(tt.total)}
+			totals.roundTotal(&rounding)
+			if !totals.total.Equal(decimal.RequireFromString(tt.want)) || !totals.totalAdjustment.Equal(decimal.RequireFromString(tt.adjustment)) {
+				t.Errorf("total = %s with adjustment %s, want %s with %s", totals.total, totals.totalAdjustment, tt.want, tt.adjustment)
+			}
+		})
+	}
+}
+
+func TestInvoiceTotalsFollowTheClientsTaxTreatment(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{GSTRegistered: true})
+	start := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	exclusive := newPrecisionSession(start, 2*time.Hour, 0, "100")
+	inclusive := newPrecisionSession(start.Add(3*time.Hour), time.Hour, 0, "110")
+	inclusive.IncludesGst = true
+
+	tests := []struct {
+		treatment     string
+		subtotal, gst string
+	}{
+		{models.TaxTreatmentTaxable, "300", "30"},
+		{models.TaxTreatmentGSTFree, "310", "0"},
+		{models.TaxTreatmentExport, "310", "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.treatment, func(t *testing.T) {
+			totals := s.calculateInvoiceTotals(&models.Client{TaxTreatment: tt.treatment}, []*models.WorkSession{exclusive, inclusive}, nil, nil, retainerTerms{})
+			if !totals.subtotal.Equal(decimal.RequireFromString(tt.subtotal)) || !totals.gst.Equal(decimal.RequireFromString(tt.gst)) {
+				t.Errorf("subtotal = %s with GST %s, want %s with %s", totals.subtotal, totals.gst, tt.subtotal, tt.gst)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// Presenter formats the results returned by service methods for the terminal, so the methods themselves can
+// be used by other front ends. It formats durations and money with the service's settings, without touching the
+// database.
+type Presenter struct {
+	s *TimesheetService
+	w io.Writer
+}
+
+// Presenter returns a presenter writing to w
+func (s *TimesheetService) Presenter(w io.Writer) *Presenter {
+	return &Presenter{s: s, w: w}
+}
+
+// Invoices lists invoices with any credit notes issued against them beneath each one. Unpaid lists finish with
+// the total still owed in each currency.
+func (p *Presenter) Invoices(list *InvoiceList) {
+	if len(list.Invoices) == 0 {
+		if list.UnpaidOnly {
+			fmt.Fprintln(p.w, "No unpaid invoices found.")
+		} else {
+			fmt.Fprintln(p.w, "No invoices found.")
+		}
+	}
+
+	// Print header
+	if list.UnpaidOnly {
+		fmt.Fprintln(p.w, "Unpaid Invoices:")
+	}
+	fmt.Fprintf(p.w, "%-38s %-15s %-10s %-12s %-12s %-12s %-12s %-16s %-18s %-12s\n",
+		"ID", "CLIENT", "PERIOD", "FROM", "TO", "SUBTOTAL", "TOTAL", "AMOUNT_PAID", "PAYMENT_DATE", "STATUS")
+	fmt.Fprintln(p.w, strings.Repeat("-", 167))
+
+	outstanding := make(map[string]decimal.Decimal)
+	formatters := make(map[string]money.Formatter)
+
+	// Print each invoice
+	for _, listed := range list.Invoices {
+		invoice, m := listed.Invoice, listed.Money
+		balance := invoiceBalance(invoice)
+		paidStatus := invoiceStatus(invoice)
+		if balance.IsPositive() {
+			outstanding[m.Currency] = outstanding[m.Currency].Add(balance)
+			formatters[m.Currency] = m
+		}
+
+		paymentDate := ""
+		if invoice.PaymentDate != nil {
+			paymentDate = invoice.PaymentDate.Format("2006-01-02")
+		}
+
+		fmt.Fprintf(p.w, "%-38s %-15s %-10s %-12s %-12s %-12s %-12s %-16s %-18s %-12s\n",
+			invoice.ID,
+			truncateString(invoice.ClientName, 14),
+			invoice.PeriodType,
+			invoice.PeriodStartDate.Format("2006-01-02"),
+			invoice.PeriodEndDate.Format("2006-01-02"),
+			m.Format(invoice.SubtotalAmount),
+			m.Format(invoice.TotalAmount),
+			m.Format(invoice.AmountPaid),
+			paymentDate,
+			paidStatus,
+		)
+		if invoice.NeedsRegeneration {
+			fmt.Fprintf(p.w, "  sessions changed since it was issued, regenerate it with: %s\n", regenerateInvoiceCommand(invoice))
+		}
+		if invoice.AmountDiscounted.IsPositive() {
+			fmt.Fprintf(p.w, "  early payment discount: -%s\n", m.Format(invoice.AmountDiscounted))
+		}
+		for _, creditNote := range listed.CreditNotes {
+			fmt.Fprintf(p.w, "  credit note %s on %s: -%s (%s)\n",
+				creditNote.CreditNoteNumber, creditNote.IssuedDate.Format("2006-01-02"), m.Format(creditNote.Amount), creditNote.Reason)
+		}
+		if closed := invoiceClosedSummary(m, invoice); closed != "" {
+			fmt.Fprintf(p.w, "  %s\n", closed)
+		}
+	}
+
+	if list.UnpaidOnly && len(outstanding) > 0 {
+		currencies := make([]string, 0, len(outstanding))
+		for currency := range outstanding {
+			currencies = append(currencies, currency)
+		}
+		sort.Strings(currencies)
+		for _, currency := range currencies {
+			fmt.Fprintf(p.w, "Outstanding %s: %s\n", currency, formatters[currency].Format(outstanding[currency]))
+		}
+	}
+}
+
+// HoursTotal shows the time worked, followed by what it's billed when there's anything to bill
+func (p *Presenter) HoursTotal(total *HoursTotal) {
+	fmt.Fprint(p.w, p.s.FormatDurationFor(config.DurationContextReport, total.Worked))
+	if total.Billable.GreaterThan(decimal.Zero) {
+		fmt.Fprintf(p.w, " | %s", p.s.FormatBillableAmountWithGST(total.Billable))
+	}
+	fmt.Fprintln(p.w)
+}
+
+// InvoiceRun shows the invoices deleted and the files written when generating invoices, the clients skipped
+// during review, and the invoices for other ranges whose sessions were left on them
+func (p *Presenter) InvoiceRun(run *InvoiceRun) {
+	for _, invoice := range run.Deleted {
+		fmt.Fprintf(p.w, "Deleted existing invoice: %s\n", invoice.InvoiceNumber)
+	}
+
+	for _, generated := range run.Invoices {
+		invoice := generated.Invoice
+		// Amounts are the invoice's, from the database for existing invoices and as calculated for new ones
+		m := p.s.clientMoney(generated.Client)
+		var totalDisplay string
+		if p.s.chargesGST(generated.Client) {
+			totalDisplay = fmt.Sprintf("%s (%s inc. GST)", m.Format(invoice.SubtotalAmount), m.Format(invoice.TotalAmount))
+		} else {
+			totalDisplay = m.Format(invoice.TotalAmount)
+		}
+
+		if generated.Existing {
+			fmt.Fprintf(p.w, "Found existing invoice for %s: %s\n", generated.Client.Name, invoice.InvoiceNumber)
+			fmt.Fprintf(p.w, "Regenerated files for existing invoice: %s (Total: %s)\n", strings.Join(generated.Files, ", "), totalDisplay)
+		} else {
+			fmt.Fprintf(p.w, "Generated invoice: %s (Total: %s)\n", strings.Join(generated.Files, ", "), totalDisplay)
+		}
+	}
+	for _, client := range run.Skipped {
+		fmt.Fprintf(p.w, "Skipped invoice for %s, their work is still uninvoiced\n", client.Name)
+	}
+	if len(run.Invoices) == 0 && len(run.Skipped) == 0 {
+		fmt.Fprintln(p.w, "No invoices generated - no clients with billable hours > 0 for the specified period")
+	}
+
+	for _, invoice := range run.Overlapping {
+		fmt.Fprintf(p.w, "Sessions on %s (%s to %s) weren't invoiced again\n", invoice.InvoiceNumber,
+			invoice.PeriodStartDate.Format("2006-01-02"), invoice.PeriodEndDate.Format("2006-01-02"))
+	}
+}
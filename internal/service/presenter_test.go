@@ -0,0 +1,146 @@
+package service
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the presenter golden files with the current output")
+
+// checkGolden compares output with testdata/presenter/name.golden, which pins the CLI's output. The files for
+// invoice listings, hour totals and invoice runs hold what was printed before the presenter took over.
+func checkGolden(t *testing.T, name string, output []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "presenter", name+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, output, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file, run the tests with -update to write it: %v", err)
+	}
+	if !bytes.Equal(output, want) {
+		t.Errorf("output doesn't match %s:\n got:\n%s\nwant:\n%s", path, output, want)
+	}
+}
+
+func presenterFixtures() (*TimesheetService, []*models.Invoice) {
+	s := NewTimesheetService(nil, &config.Config{BillingCurrency: "AUD", BillingLocale: "en-AU", GSTRegistered: true})
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	paidOn := date(11, 14)
+	voidedOn := date(12, 2)
+	reason := "Issued to the wrong client"
+	invoices := []*models.Invoice{
+		{
+			ID: "inv-1", ClientName: "acme", InvoiceNumber: "INV-acme-month-2025-10-01", PeriodType: "month",
+			PeriodStartDate: date(10, 1), PeriodEndDate: date(10, 31), Status: models.InvoiceStatusIssued,
+			SubtotalAmount: decimal.NewFromInt(1200), TotalAmount: decimal.NewFromInt(1320), AmountPaid: decimal.NewFromInt(500),
+			AmountCredited: decimal.NewFromInt(100), NeedsRegeneration: true,
+		},
+		{
+			ID: "inv-2", ClientName: "globex corporation", InvoiceNumber: "INV-globex-custom-2025-11-03", PeriodType: CustomPeriod,
+			PeriodStartDate: date(11, 3), PeriodEndDate: date(11, 7), Status: models.InvoiceStatusPaid,
+			SubtotalAmount: decimal.NewFromInt(800), TotalAmount: decimal.NewFromInt(880), AmountPaid: decimal.NewFromInt(862),
+			AmountDiscounted: decimal.NewFromInt(18), PaymentDate: &paidOn,
+		},
+		{
+			ID: "inv-3", ClientName: "initech", InvoiceNumber: "INV-initech-week-2025-12-01", PeriodType: "week",
+			PeriodStartDate: date(12, 1), PeriodEndDate: date(12, 7), Status: models.InvoiceStatusVoid,
+			SubtotalAmount: decimal.NewFromInt(300), TotalAmount: decimal.NewFromInt(330),
+			StatusChangedAt: &voidedOn, StatusReason: &reason,
+		},
+	}
+	return s, invoices
+}
+
+func TestPresenterInvoices(t *testing.T) {
+	s, invoices := presenterFixtures()
+	aud, usd := money.New("AUD", "en-AU"), money.New("USD", "en-US")
+	creditNotes := []*models.CreditNote{{
+		InvoiceID: "inv-1", CreditNoteNumber: "CN-acme-month-2025-10-01-1", Amount: decimal.NewFromInt(100),
+		Reason: "Duplicate session", IssuedDate: time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC),
+	}}
+
+	tests := []struct {
+		name string
+		list *InvoiceList
+	}{
+		{name: "invoices", list: &InvoiceList{Invoices: []*ListedInvoice{
+			{Invoice: invoices[0], CreditNotes: creditNotes, Money: aud},
+			{Invoice: invoices[1], Money: usd},
+			{Invoice: invoices[2], Money: aud},
+		}}},
+		{name: "invoices_unpaid", list: &InvoiceList{UnpaidOnly: true, Invoices: []*ListedInvoice{
+			{Invoice: invoices[0], CreditNotes: creditNotes, Money: aud},
+		}}},
+		{name: "invoices_none", list: &InvoiceList{}},
+		{name: "invoices_none_unpaid", list: &InvoiceList{UnpaidOnly: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			s.Presenter(&out).Invoices(tt.list)
+			checkGolden(t, tt.name, out.Bytes())
+		})
+	}
+}
+
+func TestPresenterHoursTotal(t *testing.T) {
+	s, _ := presenterFixtures()
+	var out bytes.Buffer
+	s.Presenter(&out).HoursTotal(&HoursTotal{Worked: 8*time.Hour + 52*time.Minute, Billable: decimal.RequireFromString("1773.33")})
+	s.Presenter(&out).HoursTotal(&HoursTotal{})
+	checkGolden(t, "hours_total", out.Bytes())
+}
+
+func TestPresenterInvoiceRun(t *testing.T) {
+	s, invoices := presenterFixtures()
+	acme := &models.Client{Name: "acme"}
+	usd := "USD"
+	globex := &models.Client{Name: "globex corporation", Currency: &usd}
+
+	tests := []struct {
+		name string
+		run  *InvoiceRun
+	}{
+		{name: "invoice_run", run: &InvoiceRun{
+			Deleted: []*models.Invoice{invoices[2]},
+			Invoices: []*GeneratedInvoice{
+				{Invoice: invoices[0], Client: acme, Files: []string{"invoice_acme_month_2025-10-01.pdf"}},
+				{Invoice: invoices[1], Client: globex, Existing: true,
+					Files: []string{"invoice_globex_corporation_custom_2025-11-03.pdf", "invoice_globex_corporation_custom_2025-11-03.html"}},
+			},
+		}},
+		{name: "invoice_run_none", run: &InvoiceRun{Overlapping: []*models.Invoice{invoices[0]}}},
+		{name: "invoice_run_skipped", run: &InvoiceRun{
+			Invoices: []*GeneratedInvoice{{Invoice: invoices[0], Client: acme, Files: []string{"invoice_acme_month_2025-10-01.pdf"}}},
+			Skipped:  []*models.Client{globex},
+		}},
+		{name: "invoice_run_all_skipped", run: &InvoiceRun{Skipped: []*models.Client{acme}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			s.Presenter(&out).InvoiceRun(tt.run)
+			checkGolden(t, tt.name, out.Bytes())
+		})
+	}
+}
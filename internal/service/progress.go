@@ -0,0 +1,138 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/logging"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// descriptionProgress tracks how many sessions have been described for each client and renders
+// a spinner with per-client counts on the terminal's status line
+type descriptionProgress struct {
+	term    *logging.Terminal
+	enabled bool
+	started time.Time
+
+	mu      sync.Mutex
+	clients []string
+	counts  map[string]*clientProgress
+	frame   int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+type clientProgress struct {
+	total  int
+	done   int
+	failed int
+}
+
+func newDescriptionProgress(term *logging.Terminal, enabled bool) *descriptionProgress {
+	return &descriptionProgress{
+		term:    term,
+		enabled: enabled && term.IsTTY(),
+		started: time.Now(),
+		counts:  make(map[string]*clientProgress),
+		stop:    make(chan struct{}),
+	}
+}
+
+// add registers sessions to be processed for a client
+func (p *descriptionProgress) add(client string, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.counts[client]; !ok {
+		p.clients = append(p.clients, client)
+		p.counts[client] = &clientProgress{}
+	}
+	p.counts[client].total += total
+}
+
+// complete records a processed session and returns the overall done and total counts
+func (p *descriptionProgress) complete(client string, err error) (int, int) {
+	p.mu.Lock()
+	c := p.counts[client]
+	c.done++
+	if err != nil {
+		c.failed++
+	}
+	done, total, _ := p.totalsLocked()
+	p.mu.Unlock()
+
+	p.render()
+	return done, total
+}
+
+// start begins animating the status line until finish is called
+func (p *descriptionProgress) start() {
+	if !p.enabled {
+		return
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.render()
+			}
+		}
+	}()
+}
+
+// finish stops the spinner, clears the status line and returns the done and failed counts
+func (p *descriptionProgress) finish() (int, int) {
+	if p.enabled {
+		close(p.stop)
+		p.wg.Wait()
+		p.term.ClearStatus()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	done, _, failed := p.totalsLocked()
+	return done, failed
+}
+
+func (p *descriptionProgress) render() {
+	if !p.enabled {
+		return
+	}
+
+	p.mu.Lock()
+	done, total, _ := p.totalsLocked()
+	parts := []string{fmt.Sprintf("%s Generating descriptions %d/%d", spinnerFrames[p.frame%len(spinnerFrames)], done, total)}
+	p.frame++
+	for _, client := range p.clients {
+		c := p.counts[client]
+		part := fmt.Sprintf("%s %d/%d", client, c.done, c.total)
+		if c.failed > 0 {
+			part += fmt.Sprintf(" (%d failed)", c.failed)
+		}
+		parts = append(parts, part)
+	}
+	p.mu.Unlock()
+
+	parts = append(parts, time.Since(p.started).Round(time.Second).String())
+	p.term.SetStatus(strings.Join(parts, " · "))
+}
+
+func (p *descriptionProgress) totalsLocked() (done, total, failed int) {
+	for _, c := range p.counts {
+		done += c.done
+		total += c.total
+		failed += c.failed
+	}
+	return done, total, failed
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/quickadd"
+)
+
+// QuickAdd is how a quick add was understood, ready to be confirmed and logged
+type QuickAdd struct {
+	ClientName  string
+	StartTime   time.Time
+	EndTime     time.Time
+	Description *string
+}
+
+// InterpretQuickAdd reads a session written in plain English, such as "2h yesterday for acme fixing the deploy
+// pipeline", matching the client loosely against existing clients
+func (s *TimesheetService) InterpretQuickAdd(ctx context.Context, input string, now time.Time) (*QuickAdd, error) {
+	entry, err := quickadd.Parse(input, now)
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := s.db.ListClients(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+	names := make([]string, len(clients))
+	for i, client := range clients {
+		names[i] = client.Name
+	}
+	clientName, err := quickadd.MatchClient(entry.ClientQuery, names)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := entry.Times(now.Truncate(time.Minute))
+	add := &QuickAdd{ClientName: clientName, StartTime: start, EndTime: end}
+	if entry.Description != "" {
+		add.Description = &entry.Description
+	}
+	s.logger.Debug("interpreted quick add", "input", input, "client", clientName, "start", start, "end", end)
+	return add, nil
+}
+
+// DisplayQuickAdd prints how a quick add was understood
+func (s *TimesheetService) DisplayQuickAdd(add *QuickAdd) {
+	fmt.Fprintf(s.out, "Client: %s\n", add.ClientName)
+	fmt.Fprintf(s.out, "Date: %s\n", add.StartTime.Format("Monday 2006-01-02"))
+	fmt.Fprintf(s.out, "Time: %s to %s (%s)\n", add.StartTime.Format("15:04"), add.EndTime.Format("15:04"),
+		s.FormatDuration(add.EndTime.Sub(add.StartTime)))
+	if add.Description != nil {
+		fmt.Fprintf(s.out, "Description: %s\n", *add.Description)
+	}
+}
+
+// LogQuickAdd creates the finished session a quick add describes
+func (s *TimesheetService) LogQuickAdd(ctx context.Context, add *QuickAdd) (*models.WorkSession, error) {
+	return s.CreateSessionWithTimes(ctx, add.ClientName, add.StartTime, add.EndTime, add.Description, false)
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// Rate card document formats
+const (
+	RateCardFormatPDF      = "pdf"
+	RateCardFormatMarkdown = "md"
+)
+
+// rateCard is what's shared with a client in a proposal, formatted ready to render
+type rateCard struct {
+	title       string
+	company     string
+	abn         string
+	date        string
+	preparedFor []string
+	rates       [][2]string // role and hourly rate
+	retainer    string
+	gstNote     string
+	terms       []string
+}
+
+// ExportRateCard writes a rate card for a client, or a general one when clientName is empty, listing their hourly
+// rate, any retainer, the roles in RATE_CARD_ROLES and the terms in RATE_CARD_TERMS. It's built from the same
+// client and billing settings invoices use, so proposals quote what will be billed. The path written to is printed.
+func (s *TimesheetService) ExportRateCard(ctx context.Context, clientName, format, output string) error {
+	if format != RateCardFormatPDF && format != RateCardFormatMarkdown {
+		return fmt.Errorf("invalid format %q, expected %s or %s", format, RateCardFormatPDF, RateCardFormatMarkdown)
+	}
+
+	var client *models.Client
+	if clientName != "" {
+		var err error
+		client, err = s.db.GetClientByName(ctx, clientName)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("client '%s' does not exist", clientName)
+			}
+			return fmt.Errorf("failed to get client: %w", err)
+		}
+	}
+
+	card := s.buildRateCard(client, time.Now())
+	if len(card.rates) == 0 && card.retainer == "" {
+		return fmt.Errorf("nothing to put on the rate card, set the client's rate with 'work clients update <client> -r <rate>' or roles with RATE_CARD_ROLES")
+	}
+
+	if output == "" {
+		name := "ratecard"
+		if client != nil {
+			name += "_" + client.Name
+		}
+		output = s.sanitizeFileName(name + "." + format)
+	}
+
+	if format == RateCardFormatMarkdown {
+		if err := os.WriteFile(output, []byte(card.markdown()), 0644); err != nil {
+			return fmt.Errorf("failed to write rate card: %w", err)
+		}
+	} else if err := s.writePDFAtomically(card.pdf(), output); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.out, "Rate card written to %s\n", output)
+	return nil
+}
+
+func (s *TimesheetService) buildRateCard(client *models.Client, now time.Time) rateCard {
+	card := rateCard{
+		title:   "Rate Card",
+		company: s.cfg.BillingCompanyName,
+		abn:     s.cfg.BillingABN,
+		date:    now.Format("2006-01-02"),
+		terms:   s.cfg.RateCardTerms,
+	}
+	home := s.homeMoney()
+
+	if client != nil {
+		card.title = fmt.Sprintf("Rate Card - %s", s.formatClientName(client.Name))
+		for _, line := range []*string{client.ContactName, client.CompanyName} {
+			if line != nil && *line != "" {
+				card.preparedFor = append(card.preparedFor, *line)
+			}
+		}
+		if client.HourlyRate.IsPositive() {
+			card.rates = append(card.rates, [2]string{"Standard rate", s.FormatClientRate(client)})
+		}
+		if client.RetainerAmount != nil && client.RetainerAmount.IsPositive() && client.RetainerBasis != nil {
+			m := s.clientMoney(client)
+			card.retainer = fmt.Sprintf("%s per %s", m.Format(*client.RetainerAmount), *client.RetainerBasis)
+			if client.RetainerHours != nil && *client.RetainerHours > 0 {
+				hours := decimal.NewFromFloat(*client.RetainerHours)
+				card.retainer += fmt.Sprintf(", covering %s hours (%s/hr)", hours.String(),
+					m.Format(client.RetainerAmount.Div(hours).Round(2)))
+				if client.HourlyRate.IsPositive() {
+					card.retainer += ", with further hours at the standard rate"
+				}
+			}
+		}
+		// Roles are configured in the billing currency, so they're only quoted to clients billed in it
+		if s.clientMoney(client).Currency != home.Currency {
+			return s.withGSTNote(card, client)
+		}
+	}
+
+	for _, role := range s.cfg.RateCardRoles {
+		card.rates = append(card.rates, [2]string{role.Name, home.Format(role.Rate) + "/hr"})
+	}
+	return s.withGSTNote(card, client)
+}
+
+func (s *TimesheetService) withGSTNote(card rateCard, client *models.Client) rateCard {
+	if s.chargesGST(client) {
+		card.gstNote = "Rates exclude GST, which is added at 10%."
+	} else if label := s.gstFreeLabel(client); label != "" {
+		card.gstNote = fmt.Sprintf("No GST is charged (%s).", label)
+	}
+	return card
+}
+
+func (c rateCard) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", c.title)
+	if c.company != "" {
+		fmt.Fprintf(&b, "%s  \n", c.company)
+	}
+	if c.abn != "" {
+		fmt.Fprintf(&b, "ABN %s  \n", c.abn)
+	}
+	fmt.Fprintf(&b, "Date: %s\n\n", c.date)
+	if len(c.preparedFor) > 0 {
+		fmt.Fprintf(&b, "Prepared for: %s\n\n", strings.Join(c.preparedFor, ", "))
+	}
+
+	if len(c.rates) > 0 {
+		b.WriteString("## Rates\n\n| Role | Hourly rate |\n| --- | ---: |\n")
+		for _, rate := range c.rates {
+			fmt.Fprintf(&b, "| %s | %s |\n", rate[0], rate[1])
+		}
+		b.WriteString("\n")
+	}
+	if c.retainer != "" {
+		fmt.Fprintf(&b, "## Retainer\n\n%s\n\n", c.retainer)
+	}
+	if c.gstNote != "" {
+		fmt.Fprintf(&b, "%s\n\n", c.gstNote)
+	}
+	if len(c.terms) > 0 {
+		b.WriteString("## Terms\n\n")
+		for _, term := range c.terms {
+			fmt.Fprintf(&b, "- %s\n", term)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func (c rateCard) pdf() *gofpdf.Fpdf {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Arial", "B", 16)
+	pdf.Cell(40, 10, tr(c.title))
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "", 11)
+	if c.company != "" {
+		pdf.Cell(40, 6, tr(c.company))
+		pdf.Ln(6)
+	}
+	if c.abn != "" {
+		pdf.Cell(40, 6, fmt.Sprintf("ABN %s", c.abn))
+		pdf.Ln(6)
+	}
+	pdf.Cell(40, 6, fmt.Sprintf("Date: %s", c.date))
+	pdf.Ln(6)
+	if len(c.preparedFor) > 0 {
+		pdf.Cell(40, 6, tr("Prepared for: "+strings.Join(c.preparedFor, ", ")))
+		pdf.Ln(6)
+	}
+	pdf.Ln(6)
+
+	heading := func(text string) {
+		pdf.SetFont("Arial", "B", 12)
+		pdf.Cell(40, 8, text)
+		pdf.Ln(8)
+		pdf.SetFont("Arial", "", 11)
+	}
+
+	if len(c.rates) > 0 {
+		heading("Rates")
+		pdf.SetFont("Arial", "B", 11)
+		pdf.CellFormat(130, 8, "Role", "B", 0, "L", false, 0, "")
+		pdf.CellFormat(60, 8, "Hourly rate", "B", 1, "R", false, 0, "")
+		pdf.SetFont("Arial", "", 11)
+		for _, rate := range c.rates {
+			pdf.CellFormat(130, 7, tr(rate[0]), "", 0, "L", false, 0, "")
+			pdf.CellFormat(60, 7, tr(rate[1]), "", 1, "R", false, 0, "")
+		}
+		pdf.Ln(6)
+	}
+	if c.retainer != "" {
+		heading("Retainer")
+		pdf.MultiCell(190, 6, tr(c.retainer), "", "L", false)
+		pdf.Ln(6)
+	}
+	if c.gstNote != "" {
+		pdf.MultiCell(190, 6, c.gstNote, "", "L", false)
+		pdf.Ln(6)
+	}
+	if len(c.terms) > 0 {
+		heading("Terms")
+		for _, term := range c.terms {
+			pdf.MultiCell(190, 6, tr("- "+term), "", "L", false)
+		}
+	}
+	return pdf
+}
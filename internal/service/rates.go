@@ -0,0 +1,72 @@
+package service
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// belowMinimumRate reports whether rate is under the configured MINIMUM_RATE. The floor is in the
+// billing currency, so clients billed in another currency aren't compared against it.
+func (s *TimesheetService) belowMinimumRate(client *models.Client, rate decimal.Decimal) bool {
+	if s.cfg == nil || !s.cfg.MinimumRate.IsPositive() {
+		return false
+	}
+	if s.clientMoney(client).Currency != s.homeMoney().Currency {
+		return false
+	}
+	return rate.LessThan(s.cfg.MinimumRate)
+}
+
+// warnIfClientRateBelowMinimum warns when a client is set up with an hourly rate under the floor.
+// Clients without a rate (such as retainer only clients) are checked when invoicing instead.
+func (s *TimesheetService) warnIfClientRateBelowMinimum(client *models.Client) {
+	if !client.HourlyRate.IsPositive() || !s.belowMinimumRate(client, client.HourlyRate) {
+		return
+	}
+	s.logger.Warn("client rate is below your minimum rate",
+		"client", client.Name,
+		"rate", s.FormatClientRate(client),
+		"minimum", s.formatMinimumRate())
+}
+
+// warnIfSessionRateBelowMinimum warns when a session is recorded at an hourly rate under the floor
+func (s *TimesheetService) warnIfSessionRateBelowMinimum(client *models.Client, session *models.WorkSession) {
+	if session.HourlyRate == nil || !session.HourlyRate.IsPositive() || !s.belowMinimumRate(client, *session.HourlyRate) {
+		return
+	}
+	s.logger.Warn("session rate is below your minimum rate",
+		"client", client.Name,
+		"rate", s.FormatClientMoney(client, *session.HourlyRate)+"/hr",
+		"minimum", s.formatMinimumRate())
+}
+
+// warnIfInvoiceRateBelowMinimum warns when the realised hourly rate of an invoice, its subtotal excluding
+// expenses divided by the hours worked, is under the floor. This catches retainers stretched over too many hours.
+func (s *TimesheetService) warnIfInvoiceRateBelowMinimum(client *models.Client, invoiceNumber string, sessions []*models.WorkSession, expenses []*models.Expense, subtotal decimal.Decimal) {
+	var worked time.Duration
+	for _, session := range sessions {
+		worked += s.CalculateDuration(session)
+	}
+	if worked <= 0 {
+		return
+	}
+
+	hours := durationHours(worked)
+	effectiveRate := subtotal.Sub(s.calculateExpenseTotal(expenses)).Div(hours)
+	if !s.belowMinimumRate(client, effectiveRate) {
+		return
+	}
+	s.logger.Warn("invoice effective rate is below your minimum rate",
+		"invoice", invoiceNumber,
+		"client", client.Name,
+		"hours", hours.StringFixed(1),
+		"effective_rate", s.FormatClientMoney(client, effectiveRate)+"/hr",
+		"minimum", s.formatMinimumRate())
+}
+
+func (s *TimesheetService) formatMinimumRate() string {
+	return s.homeMoney().Format(s.cfg.MinimumRate) + "/hr"
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// receiptsFolder is the folder inside a client's directory that receipts are dropped into
+const receiptsFolder = "receipts"
+
+// ReceiptDetails is what could be read from a receipt file. Anything that couldn't be read is left zero.
+type ReceiptDetails struct {
+	Amount    decimal.Decimal
+	Date      time.Time
+	Reference string
+}
+
+// ReceiptReader reads expense details from a receipt file, such as by parsing its name or running OCR
+// over its contents
+type ReceiptReader interface {
+	ReadReceipt(path string) (*ReceiptDetails, error)
+}
+
+// AddReceiptReader registers a reader, such as an OCR reader, to try before the built in ones.
+// The first reader to find an amount wins.
+func (s *TimesheetService) AddReceiptReader(reader ReceiptReader) {
+	s.receipts = append([]ReceiptReader{reader}, s.receipts...)
+}
+
+var (
+	receiptDatePattern   = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	receiptAmountPattern = regexp.MustCompile(`^\$?\d+(\.\d{1,2})?$`)
+)
+
+// filenameReceiptReader reads details from file names like 2025-03-14_officeworks_45.90.pdf, where the
+// date and amount are optional and the remaining words become the reference
+type filenameReceiptReader struct{}
+
+func (filenameReceiptReader) ReadReceipt(path string) (*ReceiptDetails, error) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	fields := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == ' ' })
+
+	details := &ReceiptDetails{}
+	var words []string
+	for i, field := range fields {
+		switch {
+		case i == 0 && receiptDatePattern.MatchString(field):
+			if date, err := time.ParseInLocation("2006-01-02", field, time.Local); err == nil {
+				details.Date = date
+				continue
+			}
+		case i == len(fields)-1 && receiptAmountPattern.MatchString(field):
+			if amount, err := decimal.NewFromString(strings.TrimPrefix(field, "$")); err == nil {
+				details.Amount = amount
+				continue
+			}
+		}
+		words = append(words, field)
+	}
+	details.Reference = strings.Join(words, " ")
+	return details, nil
+}
+
+// readReceipt combines what the receipt readers find, falling back to the file's modification time for the date
+func (s *TimesheetService) readReceipt(path string, info os.FileInfo) *ReceiptDetails {
+	combined := &ReceiptDetails{}
+	for _, reader := range s.receipts {
+		details, err := reader.ReadReceipt(path)
+		if err != nil {
+			s.logger.Debug("receipt reader failed", "file", path, "error", err)
+			continue
+		}
+		if combined.Amount.IsZero() {
+			combined.Amount = details.Amount
+		}
+		if combined.Date.IsZero() {
+			combined.Date = details.Date
+		}
+		if combined.Reference == "" {
+			combined.Reference = details.Reference
+		}
+	}
+	if combined.Date.IsZero() {
+		combined.Date = info.ModTime()
+	}
+	return combined
+}
+
+// ImportReceipts turns receipt files dropped into each client's receipts folder into draft expenses,
+// skipping files that have already been imported. An empty client name checks every client with a directory.
+func (s *TimesheetService) ImportReceipts(ctx context.Context, clientName string) (int, error) {
+	clients, err := s.getTargetClients(ctx, clientName)
+	if err != nil {
+		return 0, err
+	}
+
+	imported := 0
+	for _, client := range clients {
+		if utils.FromPtr(client.Dir) == "" {
+			continue
+		}
+		dir, err := expandClientDir(*client.Dir)
+		if err != nil {
+			s.logger.Warn("skipping receipts for client", "client", client.Name, "error", err)
+			continue
+		}
+		dir = filepath.Join(dir, receiptsFolder)
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				s.logger.Debug("no receipts folder", "client", client.Name, "dir", dir)
+				continue
+			}
+			return imported, fmt.Errorf("failed to read receipts folder for %s: %w", client.Name, err)
+		}
+
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			expense, err := s.importReceipt(ctx, client, filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return imported, err
+			}
+			if expense != nil {
+				imported++
+			}
+		}
+	}
+	return imported, nil
+}
+
+// importReceipt creates a draft expense for a receipt file, returning nil if it was imported before
+func (s *TimesheetService) importReceipt(ctx context.Context, client *models.Client, path string) (*models.Expense, error) {
+	if _, err := s.db.GetExpenseByReceiptPath(ctx, path); err == nil {
+		return nil, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipt %s: %w", path, err)
+	}
+	details := s.readReceipt(path, info)
+
+	var reference *string
+	if details.Reference != "" {
+		reference = &details.Reference
+	}
+	expense, err := s.db.CreateDraftExpense(ctx, client.ID, details.Amount, details.Date, reference, path)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := "no amount found"
+	if details.Amount.IsPositive() {
+		amount = s.FormatClientMoney(client, details.Amount)
+	}
+	fmt.Fprintf(s.out, "Imported %s for %s as draft expense %s (%s)\n", filepath.Base(path), client.Name, expense.ID, amount)
+	return expense, nil
+}
+
+// WatchReceipts imports receipts every interval until the context is cancelled
+func (s *TimesheetService) WatchReceipts(ctx context.Context, clientName string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		imported, err := s.ImportReceipts(ctx, clientName)
+		if err != nil {
+			return err
+		}
+		if imported > 0 {
+			fmt.Fprintf(s.out, "Review %d new draft expense(s) with 'work expenses review'\n", imported)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListDraftExpenses returns expenses imported from receipts that haven't been confirmed yet
+func (s *TimesheetService) ListDraftExpenses(ctx context.Context) ([]*models.Expense, error) {
+	return s.db.ListDraftExpenses(ctx)
+}
+
+// ConfirmExpense accepts a draft expense at the given amount so that it can be invoiced
+func (s *TimesheetService) ConfirmExpense(ctx context.Context, expenseID string, amount decimal.Decimal) (*models.Expense, error) {
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be greater than 0")
+	}
+	return s.db.ConfirmExpense(ctx, expenseID, amount)
+}
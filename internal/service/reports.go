@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// ClientSources are the acquisition channels a client can be tagged with
+var ClientSources = []string{"referral", "linkedin", "agency", "repeat", "other"}
+
+// unknownSource groups revenue from clients that haven't been tagged with a source
+const unknownSource = "unknown"
+
+// ValidateClientSource returns an error if source is not one of ClientSources
+func ValidateClientSource(source string) error {
+	if source == "" {
+		return nil
+	}
+	for _, known := range ClientSources {
+		if strings.EqualFold(source, known) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported source %q, expected one of: %s", source, strings.Join(ClientSources, ", "))
+}
+
+type sourceRevenue struct {
+	source   string
+	clients  map[string]bool
+	invoices int
+	revenue  decimal.Decimal
+}
+
+// sourceRevenueYear holds revenue by source for one year in one currency, since amounts in
+// different currencies can't be summed
+type sourceRevenueYear struct {
+	year    int
+	m       money.Formatter
+	sources map[string]*sourceRevenue
+	total   decimal.Decimal
+}
+
+// ShowRevenueBySource displays invoiced revenue (excluding GST) grouped by client source for each year,
+// based on the start of each invoice's period. Years are financial years when byFinancialYear is set. A year
+// of 0 shows every year.
+func (s *TimesheetService) ShowRevenueBySource(ctx context.Context, year int, byFinancialYear bool) error {
+	invoices, err := s.db.ListInvoices(ctx, -1)
+	if err != nil {
+		return err
+	}
+	clients, err := s.db.ListClients(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list clients: %w", err)
+	}
+
+	clientSources := make(map[string]string, len(clients))
+	clientMoney := make(map[string]money.Formatter, len(clients))
+	for _, client := range clients {
+		source := unknownSource
+		if client.Source != nil && *client.Source != "" {
+			source = *client.Source
+		}
+		clientSources[client.ID] = source
+		clientMoney[client.ID] = s.clientMoney(client)
+	}
+
+	years := make(map[string]*sourceRevenueYear)
+	for _, invoice := range invoices {
+		invoiceYear := s.reportYear(invoice.PeriodStartDate, byFinancialYear)
+		if year != 0 && invoiceYear != year {
+			continue
+		}
+
+		m := clientMoney[invoice.ClientID]
+		key := fmt.Sprintf("%d-%s", invoiceYear, m.Currency)
+		y, ok := years[key]
+		if !ok {
+			y = &sourceRevenueYear{year: invoiceYear, m: m, sources: make(map[string]*sourceRevenue)}
+			years[key] = y
+		}
+
+		source := clientSources[invoice.ClientID]
+		if source == "" {
+			source = unknownSource
+		}
+		r, ok := y.sources[source]
+		if !ok {
+			r = &sourceRevenue{source: source, clients: make(map[string]bool)}
+			y.sources[source] = r
+		}
+		r.clients[invoice.ClientID] = true
+		r.invoices++
+		r.revenue = r.revenue.Add(invoice.SubtotalAmount)
+		y.total = y.total.Add(invoice.SubtotalAmount)
+	}
+
+	if len(years) == 0 {
+		if year != 0 {
+			fmt.Fprintf(s.out, "No invoices found for %s\n", s.reportYearLabel(year, byFinancialYear))
+		} else {
+			fmt.Fprintln(s.out, "No invoices found")
+		}
+		return nil
+	}
+
+	ordered := make([]*sourceRevenueYear, 0, len(years))
+	for _, y := range years {
+		ordered = append(ordered, y)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].year != ordered[j].year {
+			return ordered[i].year > ordered[j].year
+		}
+		return ordered[i].m.Currency < ordered[j].m.Currency
+	})
+
+	home := s.homeMoney()
+	fmt.Fprintln(s.out, "Revenue by source (invoiced, excluding GST)")
+	for _, y := range ordered {
+		heading := s.reportYearLabel(y.year, byFinancialYear)
+		if y.m.Currency != home.Currency {
+			heading = fmt.Sprintf("%s (%s)", heading, y.m.Currency)
+		}
+		fmt.Fprintf(s.out, "\n%s\n", heading)
+		fmt.Fprintf(s.out, "  %-10s %8s %9s %15s %7s\n", "Source", "Clients", "Invoices", "Revenue", "Share")
+
+		rows := make([]*sourceRevenue, 0, len(y.sources))
+		for _, r := range y.sources {
+			rows = append(rows, r)
+		}
+		sort.Slice(rows, func(i, j int) bool {
+			if !rows[i].revenue.Equal(rows[j].revenue) {
+				return rows[i].revenue.GreaterThan(rows[j].revenue)
+			}
+			return rows[i].source < rows[j].source
+		})
+
+		clientCount, invoiceCount := 0, 0
+		for _, r := range rows {
+			share := decimal.Zero
+			if y.total.IsPositive() {
+				share = r.revenue.Div(y.total).Mul(decimal.NewFromInt(100))
+			}
+			fmt.Fprintf(s.out, "  %-10s %8d %9d %15s %6s%%\n", r.source, len(r.clients), r.invoices, y.m.Format(r.revenue), share.StringFixed(1))
+			clientCount += len(r.clients)
+			invoiceCount += r.invoices
+		}
+		fmt.Fprintf(s.out, "  %-10s %8d %9d %15s\n", "Total", clientCount, invoiceCount, y.m.Format(y.total))
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// ValidateRetainerBasis returns an error if basis isn't one of PeriodTypes, which invoices are generated for too
+func ValidateRetainerBasis(basis string) error {
+	if err := ValidatePeriod(basis); err != nil {
+		return fmt.Errorf("invalid retainer basis: %w", err)
+	}
+	return nil
+}
+
+// retainerTerms is the part of a client's retainer that applies to an invoice period
+type retainerTerms struct {
+	amount decimal.Decimal
+	hours  decimal.Decimal
+	// days the retainer was in place for out of periodDays, when it started partway through the period
+	days       int
+	periodDays int
+	// how many of the retainer's basis periods the invoice period covers, e.g. 3 for a quarter's invoice against
+	// a monthly retainer, or zero when the invoice period is exactly one
+	basisShare decimal.Decimal
+	basis      string
+}
+
+// applies reports whether any retainer is billed for the period
+func (r retainerTerms) applies() bool {
+	return r.amount.GreaterThan(decimal.Zero)
+}
+
+// prorated reports whether the retainer was cut down because it started partway through the period
+func (r retainerTerms) prorated() bool {
+	return r.periodDays > 0 && r.days < r.periodDays
+}
+
+// label describes the retainer on an invoice, e.g. "month", "month, 18 of 31 days" or "week, 0.23 of a month"
+func (r retainerTerms) label(period, proration string) string {
+	label := period
+	if !r.basisShare.IsZero() {
+		if r.basisShare.GreaterThan(decimal.NewFromInt(1)) {
+			label += fmt.Sprintf(", %s %ss", r.basisShare.Round(2), r.basis)
+		} else {
+			label += fmt.Sprintf(", %s of a %s", r.basisShare.Round(2), r.basis)
+		}
+	}
+	if !r.prorated() {
+		return label
+	}
+	unit := "days"
+	if proration == config.RetainerProrateWeekdays {
+		unit = "weekdays"
+	}
+	return fmt.Sprintf("%s, %d of %d %s", label, r.days, r.periodDays, unit)
+}
+
+// retainerForPeriod returns the client's retainer for an invoice period from fromDate to toDate. When the invoice
+// period isn't exactly one of the retainer's basis periods, such as a monthly invoice against a quarterly
+// retainer, the retainer is applied in proportion to how much of each basis period the invoice covers. When the
+// retainer started partway through the period, its amount and hours are prorated by the share of days it was in
+// place for. Both count days or weekdays as set by RETAINER_PRORATION.
+func (s *TimesheetService) retainerForPeriod(client *models.Client, period string, fromDate, toDate time.Time) retainerTerms {
+	if client.RetainerAmount == nil || client.RetainerHours == nil || client.RetainerBasis == nil ||
+		!client.RetainerAmount.GreaterThan(decimal.Zero) || *client.RetainerHours <= 0.0 || ValidatePeriod(*client.RetainerBasis) != nil {
+		return retainerTerms{}
+	}
+	terms := retainerTerms{amount: *client.RetainerAmount, hours: decimal.NewFromFloat(*client.RetainerHours), basis: *client.RetainerBasis}
+
+	proration := s.retainerProration()
+	count := countDays
+	if proration == config.RetainerProrateWeekdays {
+		count = countWeekdays
+	}
+	from, to := dateOnly(fromDate), dateOnly(toDate)
+	start := from
+	if client.RetainerStart != nil && proration != config.RetainerProrateNone {
+		if retainerStart := dateOnly(*client.RetainerStart); retainerStart.After(to) {
+			return retainerTerms{}
+		} else if retainerStart.After(from) {
+			start = retainerStart
+			terms.days, terms.periodDays = count(start, to), count(from, to)
+		}
+	}
+
+	one := decimal.NewFromInt(1)
+	if basisShare := s.retainerBasisShare(terms.basis, from, to, count); !basisShare.Equal(one) {
+		terms.basisShare = basisShare
+	}
+	share := s.retainerBasisShare(terms.basis, start, to, count)
+	if share.Equal(one) {
+		return terms
+	}
+	terms.amount = terms.amount.Mul(share).Round(2)
+	terms.hours = terms.hours.Mul(share)
+	return terms
+}
+
+// retainerBasisShare is how many of the retainer's basis periods the dates from one to another cover, adding the
+// share of each basis period they overlap, e.g. 7/31 for a week within a 31 day month
+func (s *TimesheetService) retainerBasisShare(basis string, from, to time.Time, count func(from, to time.Time) int) decimal.Decimal {
+	share := decimal.Zero
+	for day := from; !day.After(to); {
+		periodStart, periodEnd := s.CalculatePeriodRange(basis, day)
+		periodStart, periodEnd = dateOnly(periodStart), dateOnly(periodEnd)
+		overlapEnd := periodEnd
+		if to.Before(overlapEnd) {
+			overlapEnd = to
+		}
+		if periodDays := count(periodStart, periodEnd); periodDays > 0 {
+			share = share.Add(decimal.NewFromInt(int64(count(day, overlapEnd))).Div(decimal.NewFromInt(int64(periodDays))))
+		}
+		day = periodEnd.AddDate(0, 0, 1)
+	}
+	return share
+}
+
+func (s *TimesheetService) retainerProration() string {
+	if s.cfg == nil || s.cfg.RetainerProration == "" {
+		return config.RetainerProrateDays
+	}
+	return s.cfg.RetainerProration
+}
+
+// dateOnly is the calendar date of t at midnight UTC, so days can be counted without daylight saving shifts
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// countDays counts the days from one date to another, including both
+func countDays(from, to time.Time) int {
+	return int(to.Sub(from).Hours()/24) + 1
+}
+
+// countWeekdays counts the weekdays from one date to another, including both
+func countWeekdays(from, to time.Time) int {
+	n := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+func TestRetainerForPeriodAlignsBasisWithInvoicePeriod(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{FinancialYearStart: time.July, RetainerProration: config.RetainerProrateDays})
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		basis      string
+		start      *time.Time
+		period     string
+		from, to   time.Time
+		wantAmount string
+		wantHours  string
+		wantLabel  string
+	}{
+		{
+			name: "matching basis", basis: "month", period: "month",
+			from: date(2025, 10, 1), to: date(2025, 10, 31),
+			wantAmount: "3100", wantHours: "31", wantLabel: "month",
+		},
+		{
+			name: "monthly invoice against a quarterly retainer", basis: "quarter", period: "month",
+			// October to December is 92 days
+			from: date(2025, 10, 1), to: date(2025, 10, 31),
+			wantAmount: "3133.7", wantHours: "31.337", wantLabel: "month, 0.34 of a quarter",
+		},
+		{
+			name: "quarterly invoice against a monthly retainer", basis: "month", period: "quarter",
+			from: date(2025, 10, 1), to: date(2025, 12, 31),
+			wantAmount: "9300", wantHours: "93", wantLabel: "quarter, 3 months",
+		},
+		{
+			name: "weekly invoice against a monthly retainer", basis: "month", period: "week",
+			from: date(2025, 10, 6), to: date(2025, 10, 12),
+			wantAmount: "700", wantHours: "7", wantLabel: "week, 0.23 of a month",
+		},
+		{
+			name: "custom range across two months", basis: "month", period: CustomPeriod,
+			// 16 of October's 31 days and 15 of November's 30
+			from: date(2025, 10, 16), to: date(2025, 11, 15),
+			wantAmount: "3150", wantHours: "31.5", wantLabel: "custom, 1.02 months",
+		},
+		{
+			name: "retainer started partway through a matching period", basis: "month", period: "month",
+			start: utils.ToPtr(date(2025, 10, 22)),
+			from:  date(2025, 10, 1), to: date(2025, 10, 31),
+			wantAmount: "1000", wantHours: "10", wantLabel: "month, 10 of 31 days",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 100 an hour for 31 hours a month makes amounts easy to check against days
+			amount := decimal.NewFromInt(3100)
+			if tt.basis == "quarter" {
+				amount = decimal.RequireFromString("9300")
+			}
+			hours := amount.Div(decimal.NewFromInt(100)).InexactFloat64()
+			client := &models.Client{RetainerAmount: &amount, RetainerHours: &hours, RetainerBasis: &tt.basis, RetainerStart: tt.start}
+
+			terms := s.retainerForPeriod(client, tt.period, tt.from, tt.to)
+			if want := decimal.RequireFromString(tt.wantAmount); !terms.amount.Equal(want) {
+				t.Errorf("amount = %s, want %s", terms.amount, want)
+			}
+			if want := decimal.RequireFromString(tt.wantHours); !terms.hours.Round(4).Equal(want) {
+				t.Errorf("hours = %s, want %s", terms.hours.Round(4), want)
+			}
+			if label := terms.label(tt.period, config.RetainerProrateDays); label != tt.wantLabel {
+				t.Errorf("label = %q, want %q", label, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestValidateRetainerBasis(t *testing.T) {
+	for _, basis := range PeriodTypes {
+		if err := ValidateRetainerBasis(basis); err != nil {
+			t.Errorf("ValidateRetainerBasis(%q) = %v, want nil", basis, err)
+		}
+	}
+	if err := ValidateRetainerBasis("monthly"); err == nil {
+		t.Error("ValidateRetainerBasis(\"monthly\") = nil, want an error")
+	}
+}
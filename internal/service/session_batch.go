@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// sessionBatchRecord is a session as written in a batch, before it's validated
+type sessionBatchRecord struct {
+	Client      string `json:"client"`
+	Start       string `json:"start"`
+	End         string `json:"end"`
+	Description string `json:"description"`
+	// where locates the record in the batch for reporting problems, e.g. "line 3" or "record 2"
+	where string
+}
+
+// batchSession is a validated record from a session batch
+type batchSession struct {
+	where   string
+	client  *models.Client
+	session *models.WorkSession
+}
+
+// CreateSessionsFromBatch creates the sessions in a batch of records with a client, start, end and optional
+// description, read as JSON when it starts with [ or { and as CSV otherwise. JSON is an array of objects or one
+// object after another, and CSV has a row per session in that column order, or any order under a header row.
+// Every record is validated before anything is created, and the sessions are created in a single transaction.
+func (s *TimesheetService) CreateSessionsFromBatch(ctx context.Context, r io.Reader, includesGst, dryRun bool) error {
+	records, err := readSessionBatch(r)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		fmt.Fprintln(s.out, "No sessions found in batch")
+		return nil
+	}
+
+	var sessions []*batchSession
+	var problems []string
+	clients := make(map[string]*models.Client)
+	for _, record := range records {
+		session, err := s.parseBatchSession(ctx, record, clients, includesGst)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", record.where, err))
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintln(s.out, "No sessions created, fix these records and try again:")
+		for _, problem := range problems {
+			fmt.Fprintf(s.out, "  %s\n", problem)
+		}
+		return fmt.Errorf("%d of %d record(s) in the batch have errors", len(problems), len(records))
+	}
+
+	if !dryRun {
+		toCreate := make([]*models.WorkSession, len(sessions))
+		for i, session := range sessions {
+			toCreate[i] = session.session
+		}
+		created, err := s.db.CreateWorkSessions(ctx, toCreate)
+		if err != nil {
+			return err
+		}
+		for i, session := range created {
+			sessions[i].session = session
+			s.warnIfSessionRateBelowMinimum(sessions[i].client, session)
+		}
+	}
+
+	s.printBatchSessionSummary(sessions, dryRun)
+	return nil
+}
+
+func (s *TimesheetService) parseBatchSession(ctx context.Context, record sessionBatchRecord, clients map[string]*models.Client, includesGst bool) (*batchSession, error) {
+	clientName := strings.TrimSpace(record.Client)
+	if clientName == "" {
+		return nil, fmt.Errorf("missing client")
+	}
+	client, ok := clients[strings.ToLower(clientName)]
+	if !ok {
+		var err error
+		client, err = s.db.GetClientByName(ctx, clientName)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("client '%s' does not exist", clientName)
+			}
+			return nil, fmt.Errorf("failed to get client: %w", err)
+		}
+		clients[strings.ToLower(clientName)] = client
+	}
+
+	if strings.TrimSpace(record.Start) == "" || strings.TrimSpace(record.End) == "" {
+		return nil, fmt.Errorf("start and end times are both required")
+	}
+	start, err := s.ParseTimeString(strings.TrimSpace(record.Start))
+	if err != nil {
+		return nil, fmt.Errorf("invalid start time: %w", err)
+	}
+	end, err := s.ParseTimeString(strings.TrimSpace(record.End))
+	if err != nil {
+		return nil, fmt.Errorf("invalid end time: %w", err)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("end time must be after start time")
+	}
+
+	rate := s.sessionRate(ctx, client, nil, start)
+	session := &models.WorkSession{
+		ClientID:    client.ID,
+		ClientName:  client.Name,
+		StartTime:   start,
+		EndTime:     &end,
+		HourlyRate:  &rate,
+		IncludesGst: includesGst,
+	}
+	if description := strings.TrimSpace(record.Description); description != "" {
+		session.Description = &description
+	}
+	return &batchSession{where: record.where, client: client, session: session}, nil
+}
+
+// readSessionBatch reads the records in a batch as JSON or CSV, going by its first character
+func readSessionBatch(r io.Reader) ([]sessionBatchRecord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if data[0] == '[' || data[0] == '{' {
+		return readSessionBatchJSON(data)
+	}
+	return readSessionBatchCSV(data)
+}
+
+func readSessionBatchJSON(data []byte) ([]sessionBatchRecord, error) {
+	var records []sessionBatchRecord
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse batch JSON: %w", err)
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var record sessionBatchRecord
+			err := decoder.Decode(&record)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse record %d of batch JSON: %w", len(records)+1, err)
+			}
+			records = append(records, record)
+		}
+	}
+	for i := range records {
+		records[i].where = fmt.Sprintf("record %d", i+1)
+	}
+	return records, nil
+}
+
+func readSessionBatchCSV(data []byte) ([]sessionBatchRecord, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	columns := map[string]int{"client": 0, "start": 1, "end": 2, "description": 3}
+	var records []sessionBatchRecord
+	first := true
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read batch CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		if first {
+			first = false
+			if header := sessionBatchHeader(row); header != nil {
+				for _, name := range []string{"client", "start", "end"} {
+					if _, ok := header[name]; !ok {
+						return nil, fmt.Errorf("batch CSV header has no %s column", name)
+					}
+				}
+				columns = header
+				continue
+			}
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+		records = append(records, sessionBatchRecord{
+			Client:      field("client"),
+			Start:       field("start"),
+			End:         field("end"),
+			Description: field("description"),
+			where:       fmt.Sprintf("line %d", line),
+		})
+	}
+	return records, nil
+}
+
+// sessionBatchHeader maps the columns of a CSV header row to their positions, or returns nil when the row is a
+// session rather than a header
+func sessionBatchHeader(row []string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range row {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["client"]; !ok {
+		return nil
+	}
+	return columns
+}
+
+func (s *TimesheetService) printBatchSessionSummary(sessions []*batchSession, dryRun bool) {
+	if dryRun {
+		fmt.Fprintf(s.out, "Would create %d session(s):\n", len(sessions))
+	} else {
+		fmt.Fprintf(s.out, "Created %d session(s):\n", len(sessions))
+	}
+
+	fmt.Fprintf(s.out, "%-20s %-12s %-13s %-10s %-12s %s\n", "Client", "Date", "Time", "Duration", "Amount", "Description")
+	fmt.Fprintln(s.out, strings.Repeat("-", 95))
+
+	var total time.Duration
+	for _, batch := range sessions {
+		session := batch.session
+		duration := s.CalculateDuration(session)
+		total += duration
+		fmt.Fprintf(s.out, "%-20s %-12s %-13s %-10s %-12s %s\n",
+			truncateString(session.ClientName, 20),
+			session.StartTime.Format("2006-01-02"),
+			session.StartTime.Format("15:04")+"-"+session.EndTime.Format("15:04"),
+			s.FormatDurationFor(config.DurationContextList, duration),
+			s.FormatSessionBillableAmount(session),
+			truncateString(utils.FromPtr(session.Description), 30),
+		)
+	}
+	fmt.Fprintf(s.out, "Total: %s\n", s.FormatDurationFor(config.DurationContextList, total))
+}
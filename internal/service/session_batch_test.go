@@ -0,0 +1,58 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadSessionBatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []sessionBatchRecord
+	}{
+		{
+			name:  "JSON array",
+			input: `[{"client": "acme", "start": "2025-07-01 09:00", "end": "2025-07-01 10:00", "description": "Planning"}]`,
+			want:  []sessionBatchRecord{{Client: "acme", Start: "2025-07-01 09:00", End: "2025-07-01 10:00", Description: "Planning", where: "record 1"}},
+		},
+		{
+			name: "JSON objects one after another",
+			input: `{"client": "acme", "start": "09:00", "end": "10:00"}
+{"client": "globex", "start": "11:00", "end": "12:00"}`,
+			want: []sessionBatchRecord{
+				{Client: "acme", Start: "09:00", End: "10:00", where: "record 1"},
+				{Client: "globex", Start: "11:00", End: "12:00", where: "record 2"},
+			},
+		},
+		{
+			name:  "CSV without a header",
+			input: "acme,09:00,10:00,\"Planning, scoping\"\n\nglobex,11:00,12:00\n",
+			want: []sessionBatchRecord{
+				{Client: "acme", Start: "09:00", End: "10:00", Description: "Planning, scoping", where: "line 1"},
+				{Client: "globex", Start: "11:00", End: "12:00", where: "line 3"},
+			},
+		},
+		{
+			name:  "CSV with its columns named in a header",
+			input: "Description,Start,End,Client\nPlanning,09:00,10:00,acme\n",
+			want:  []sessionBatchRecord{{Client: "acme", Start: "09:00", End: "10:00", Description: "Planning", where: "line 2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readSessionBatch(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("readSessionBatch() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("readSessionBatch() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("record %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// sessionDay is the share of a session's worked time that fell on one calendar day
+type sessionDay struct {
+	date   time.Time // midnight at the start of the day, in the session's time zone
+	worked time.Duration
+}
+
+// sessionDays shares a session's worked time out across the calendar days it ran on, in proportion to how long
+// it ran on each, so a session from 22:00 to 02:00 counts two hours on each day. Breaks are shared out the same
+// way, and the days always add up to the session's duration. Sessions stay whole everywhere they're billed.
+func (s *TimesheetService) sessionDays(session *models.WorkSession) []sessionDay {
+	worked := s.CalculateDuration(session)
+	start, end := session.StartTime, time.Now()
+	if session.EndTime != nil {
+		end = *session.EndTime
+	}
+	first := startOfDay(start)
+	if !end.After(first.AddDate(0, 0, 1)) {
+		return []sessionDay{{date: first, worked: worked}}
+	}
+
+	span := end.Sub(start)
+	var days []sessionDay
+	remaining := worked
+	for day := first; ; day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		if !next.Before(end) {
+			// The last day takes what's left, so rounding each day's share doesn't change the total
+			return append(days, sessionDay{date: day, worked: remaining})
+		}
+		share := billedDuration(time.Duration(float64(worked) * float64(next.Sub(maxTime(start, day))) / float64(span)))
+		days = append(days, sessionDay{date: day, worked: share})
+		remaining -= share
+	}
+}
+
+// sessionWorkedBetween is the part of a session's worked time on the days from one date to another (YYYY-MM-DD),
+// including both. Either end can be empty to leave it open.
+func (s *TimesheetService) sessionWorkedBetween(session *models.WorkSession, fromDate, toDate string) time.Duration {
+	var worked time.Duration
+	for _, day := range s.sessionDays(session) {
+		date := day.date.Format("2006-01-02")
+		if (fromDate == "" || date >= fromDate) && (toDate == "" || date <= toDate) {
+			worked += day.worked
+		}
+	}
+	return worked
+}
+
+// sessionBillableShare is the part of a session's billable amount earned in worked of its time
+func (s *TimesheetService) sessionBillableShare(session *models.WorkSession, worked time.Duration) decimal.Decimal {
+	amount := s.CalculateBillableAmount(session)
+	total := s.CalculateDuration(session)
+	if worked == total || total <= 0 {
+		return amount
+	}
+	return amount.Mul(decimal.NewFromInt(int64(worked))).Div(decimal.NewFromInt(int64(total))).Round(2)
+}
+
+// dayBefore is the date before a YYYY-MM-DD date, for fetching sessions by start time that might run past
+// midnight into a range. An empty or invalid date is returned as it is.
+func dayBefore(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.AddDate(0, 0, -1).Format("2006-01-02")
+}
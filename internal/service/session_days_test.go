@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+)
+
+func TestSessionDaysSplitsAtMidnight(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{})
+
+	tests := []struct {
+		name         string
+		start        time.Time
+		worked       time.Duration
+		breakSeconds int64
+		want         map[string]time.Duration
+	}{
+		{"within a day", time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC), 3 * time.Hour, 0,
+			map[string]time.Duration{"2025-03-03": 3 * time.Hour}},
+		{"ending at midnight", time.Date(2025, 3, 3, 22, 0, 0, 0, time.UTC), 2 * time.Hour, 0,
+			map[string]time.Duration{"2025-03-03": 2 * time.Hour}},
+		{"22:00 to 02:00", time.Date(2025, 3, 3, 22, 0, 0, 0, time.UTC), 4 * time.Hour, 0,
+			map[string]time.Duration{"2025-03-03": 2 * time.Hour, "2025-03-04": 2 * time.Hour}},
+		{"breaks shared out", time.Date(2025, 3, 3, 23, 0, 0, 0, time.UTC), 4 * time.Hour, 40 * 60,
+			map[string]time.Duration{"2025-03-03": 50 * time.Minute, "2025-03-04": 150 * time.Minute}},
+		{"across three days", time.Date(2025, 3, 3, 18, 0, 0, 0, time.UTC), 36 * time.Hour, 0,
+			map[string]time.Duration{"2025-03-03": 6 * time.Hour, "2025-03-04": 24 * time.Hour, "2025-03-05": 6 * time.Hour}},
+	}
+	for _, tt := range tests {
+		session := newPrecisionSession(tt.start, tt.worked, tt.breakSeconds, "100")
+		days := s.sessionDays(session)
+		if len(days) != len(tt.want) {
+			t.Errorf("%s: split into %d days, want %d", tt.name, len(days), len(tt.want))
+			continue
+		}
+		var total time.Duration
+		for _, day := range days {
+			date := day.date.Format("2006-01-02")
+			if day.worked != tt.want[date] {
+				t.Errorf("%s: %s worked %s, want %s", tt.name, date, day.worked, tt.want[date])
+			}
+			total += day.worked
+		}
+		if total != s.CalculateDuration(session) {
+			t.Errorf("%s: days add up to %s, want the session's %s", tt.name, total, s.CalculateDuration(session))
+		}
+	}
+}
+
+func TestSessionWorkedBetweenCountsOnlyTheRange(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{})
+	session := newPrecisionSession(time.Date(2025, 3, 3, 22, 0, 0, 0, time.UTC), 4*time.Hour, 0, "90")
+
+	if got := s.sessionWorkedBetween(session, "2025-03-04", "2025-03-04"); got != 2*time.Hour {
+		t.Errorf("worked on the second day = %s, want 2h", got)
+	}
+	if got := s.sessionWorkedBetween(session, "", "2025-03-03"); got != 2*time.Hour {
+		t.Errorf("worked up to the first day = %s, want 2h", got)
+	}
+	if got := s.sessionBillableShare(session, 2*time.Hour); !got.Equal(decimal.NewFromInt(180)) {
+		t.Errorf("billable for 2h of a 4h session at $90 = %s, want 180", got)
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// shortSessionIDLength is the fewest characters of a session ID shown where it's abbreviated. Session IDs start
+// with their creation time, so sessions created within about a minute of each other need more.
+const shortSessionIDLength = 8
+
+// sessionIDPrefixPattern matches what could be the start of a session ID
+var sessionIDPrefixPattern = regexp.MustCompile(`^[0-9a-fA-F-]+$`)
+
+// ResolveSessionID returns the full ID of the session ref names, which can be its ID or any prefix of it that no
+// other session shares
+func (s *TimesheetService) ResolveSessionID(ctx context.Context, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", fmt.Errorf("a session ID is required")
+	}
+	if _, err := s.db.GetSessionByID(ctx, ref); err == nil {
+		return ref, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("failed to get session: %w", err)
+	}
+	if !sessionIDPrefixPattern.MatchString(ref) {
+		return "", fmt.Errorf("session '%s' does not exist", ref)
+	}
+
+	ids, err := s.db.ListSessionIDsByPrefix(ctx, strings.ToLower(ref), 4)
+	if err != nil {
+		return "", err
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("session '%s' does not exist", ref)
+	case 1:
+		return ids[0], nil
+	}
+	more := ""
+	if len(ids) > 3 {
+		ids, more = ids[:3], ", ..."
+	}
+	return "", fmt.Errorf("'%s' is the start of more than one session's ID (%s%s), use more of it", ref, strings.Join(ids, ", "), more)
+}
+
+// ShortSessionIDs abbreviates the sessions' IDs to the same length, the shortest of at least
+// shortSessionIDLength characters that tells them all apart
+func ShortSessionIDs(sessions []*models.WorkSession) map[string]string {
+	ids := make([]string, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.ID
+	}
+	// Sorted, each ID shares its longest prefix with a neighbour
+	sort.Strings(ids)
+	length := shortSessionIDLength
+	for i := 1; i < len(ids); i++ {
+		if ids[i] != ids[i-1] {
+			length = max(length, commonPrefixLength(ids[i], ids[i-1])+1)
+		}
+	}
+
+	short := make(map[string]string, len(ids))
+	for _, id := range ids {
+		short[id] = id[:min(length, len(id))]
+	}
+	return short
+}
+
+func commonPrefixLength(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// FilterSessionsByQuery returns the sessions whose client, date, ID or description match every word of query,
+// either as written or with other characters between its letters, e.g. "acm 10-02 login" or "acme lgn". Sessions
+// containing every word as written come first, otherwise they keep their order.
+func (s *TimesheetService) FilterSessionsByQuery(sessions []*models.WorkSession, query string) []*models.WorkSession {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return sessions
+	}
+
+	var exact, fuzzy []*models.WorkSession
+	for _, session := range sessions {
+		text := strings.ToLower(strings.Join([]string{session.ClientName, session.StartTime.Format("2006-01-02 Mon 15:04"),
+			session.ID, utils.FromPtr(session.Description), utils.FromPtr(session.OutsideGit)}, " "))
+		contained, matched := true, true
+		for _, term := range terms {
+			if strings.Contains(text, term) {
+				continue
+			}
+			contained = false
+			if !isSubsequence(term, text) {
+				matched = false
+				break
+			}
+		}
+		switch {
+		case contained:
+			exact = append(exact, session)
+		case matched:
+			fuzzy = append(fuzzy, session)
+		}
+	}
+	return append(exact, fuzzy...)
+}
+
+// isSubsequence reports whether the characters of term appear in text in order
+func isSubsequence(term, text string) bool {
+	remaining := []rune(term)
+	for _, c := range text {
+		if len(remaining) > 0 && remaining[0] == c {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
+
+// SessionPickerLine describes a session on one line for choosing it from a list
+func (s *TimesheetService) SessionPickerLine(session *models.WorkSession, shortID string) string {
+	end := "active"
+	if session.EndTime != nil {
+		end = session.EndTime.Format("15:04")
+	}
+	description := utils.FromPtr(session.Description)
+	if description == "" {
+		description = "(no description)"
+	}
+	return fmt.Sprintf("%s  %s-%-6s %8s  %-15s %s", shortID, session.StartTime.Format("2006-01-02 15:04"), end,
+		s.FormatDurationFor(config.DurationContextList, s.CalculateDuration(session)),
+		truncateString(session.ClientName, 15), truncateString(description, 60))
+}
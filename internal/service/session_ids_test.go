@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+func TestShortSessionIDsTellSessionsApart(t *testing.T) {
+	sessions := []*models.WorkSession{
+		{ID: "01a14549-e6b8-7cbb-9e13-55cd644168e1"},
+		{ID: "01a14549-e6c0-7d1d-aa16-e1c1c977b26d"},
+		{ID: "01a14558-189a-716c-8f54-ae69e7823d97"},
+	}
+	short := ShortSessionIDs(sessions)
+	want := map[string]string{
+		"01a14549-e6b8-7cbb-9e13-55cd644168e1": "01a14549-e6b",
+		"01a14549-e6c0-7d1d-aa16-e1c1c977b26d": "01a14549-e6c",
+		"01a14558-189a-716c-8f54-ae69e7823d97": "01a14558-189",
+	}
+	for id, prefix := range want {
+		if short[id] != prefix {
+			t.Errorf("short ID of %s = %q, want %q", id, short[id], prefix)
+		}
+	}
+
+	if got := ShortSessionIDs(sessions[2:])[sessions[2].ID]; got != "01a14558" {
+		t.Errorf("short ID of a lone session = %q, want the first %d characters", got, shortSessionIDLength)
+	}
+}
+
+func TestFilterSessionsByQuery(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{})
+	login, invoices, standup := "Add login endpoint", "Fix invoice totals", "Standup"
+	sessions := []*models.WorkSession{
+		{ID: "a", ClientName: "acme", StartTime: time.Date(2025, 10, 2, 9, 0, 0, 0, time.UTC), Description: &login},
+		{ID: "b", ClientName: "globex", StartTime: time.Date(2025, 10, 3, 9, 0, 0, 0, time.UTC), Description: &invoices},
+		{ID: "c", ClientName: "acme", StartTime: time.Date(2025, 10, 3, 13, 0, 0, 0, time.UTC), Description: &standup},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"a", "b", "c"}},
+		{"acme", []string{"a", "c"}},
+		{"acme 10-03", []string{"c"}},
+		{"LOGIN", []string{"a"}},
+		// "tot" is in "Fix invoice totals", and only spread out in "thu ... login endpoint"
+		{"tot", []string{"b", "a"}},
+		{"glbx", []string{"b"}},
+		{"nothing like it", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, session := range s.FilterSessionsByQuery(sessions, tt.query) {
+			got = append(got, session.ID)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("FilterSessionsByQuery(%q) = %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("FilterSessionsByQuery(%q) = %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// sparkBlocks are the bars of a sparkline, from least to most
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparklineMaxDays is the longest range shown a bar per day, longer ranges are shown a bar per week
+const sparklineMaxDays = 62
+
+// clientWorked is a client's completed sessions in a listing, valued at their session rates
+type clientWorked struct {
+	name     string
+	m        money.Formatter
+	sessions int
+	worked   time.Duration
+	value    decimal.Decimal
+}
+
+// sessionListSummary totals the completed sessions in a listing
+type sessionListSummary struct {
+	sessions int
+	worked   time.Duration
+	clients  []*clientWorked
+	// buckets is the time worked each day, or each week over long ranges, from the first session to the last
+	buckets []time.Duration
+	weekly  bool
+	first   time.Time
+	last    time.Time
+}
+
+// summariseSessionList totals completed sessions by client and by day. Active sessions are left out, as their
+// time isn't settled. moneyFor gives the currency of each session's client.
+func (s *TimesheetService) summariseSessionList(sessions []*models.WorkSession, moneyFor func(*models.WorkSession) money.Formatter) *sessionListSummary {
+	summary := &sessionListSummary{}
+	byClient := make(map[string]*clientWorked)
+	byDay := make(map[time.Time]time.Duration)
+	for _, session := range sessions {
+		if session.EndTime == nil {
+			continue
+		}
+		worked := s.CalculateDuration(session)
+		client, ok := byClient[session.ClientName]
+		if !ok {
+			client = &clientWorked{name: session.ClientName, m: moneyFor(session)}
+			byClient[session.ClientName] = client
+		}
+		client.sessions++
+		client.worked += worked
+		client.value = client.value.Add(s.CalculateBillableAmount(session))
+
+		day := dateOnly(session.StartTime)
+		byDay[day] += worked
+		if summary.sessions == 0 || day.Before(summary.first) {
+			summary.first = day
+		}
+		if summary.sessions == 0 || day.After(summary.last) {
+			summary.last = day
+		}
+		summary.sessions++
+		summary.worked += worked
+	}
+	if summary.sessions == 0 {
+		return summary
+	}
+
+	for _, client := range byClient {
+		summary.clients = append(summary.clients, client)
+	}
+	sort.Slice(summary.clients, func(i, j int) bool { return summary.clients[i].name < summary.clients[j].name })
+
+	buckets := countDays(summary.first, summary.last)
+	if buckets > sparklineMaxDays {
+		summary.weekly = true
+		buckets = (buckets + 6) / 7
+	}
+	summary.buckets = make([]time.Duration, buckets)
+	for day, worked := range byDay {
+		bucket := int(day.Sub(summary.first).Hours() / 24)
+		if summary.weekly {
+			bucket /= 7
+		}
+		summary.buckets[bucket] += worked
+	}
+	return summary
+}
+
+// sparkline draws each value as a bar scaled to the largest, with a space for nothing
+func sparkline(values []time.Duration) string {
+	var most time.Duration
+	for _, value := range values {
+		most = max(most, value)
+	}
+	var line strings.Builder
+	for _, value := range values {
+		if value <= 0 {
+			line.WriteRune(' ')
+			continue
+		}
+		level := int(int64(value) * int64(len(sparkBlocks)-1) / int64(most))
+		line.WriteRune(sparkBlocks[level])
+	}
+	return line.String()
+}
+
+// DisplaySessionSummary follows a session listing with the hours and billable amount of its completed sessions,
+// the average session length, each client's share and a sparkline of the hours worked across the listed range
+func (s *TimesheetService) DisplaySessionSummary(sessions []*models.WorkSession) {
+	summary := s.summariseSessionList(sessions, func(session *models.WorkSession) money.Formatter {
+		return s.clientMoneyByID(&session.ClientID)
+	})
+	fmt.Fprintln(s.out)
+	if summary.sessions == 0 {
+		fmt.Fprintln(s.out, "Summary: no completed sessions listed")
+		return
+	}
+
+	average := summary.worked / time.Duration(summary.sessions)
+	fmt.Fprintf(s.out, "Summary: %d completed session(s) from %s to %s, %s worked, averaging %s\n", summary.sessions,
+		summary.first.Format("2006-01-02"), summary.last.Format("2006-01-02"),
+		s.FormatDurationFor(config.DurationContextList, summary.worked), s.FormatDurationFor(config.DurationContextList, average))
+
+	totals := make(map[string]decimal.Decimal)
+	formatters := make(map[string]money.Formatter)
+	for _, client := range summary.clients {
+		fmt.Fprintf(s.out, "  %-16s %8d %10s %14s\n", truncateString(client.name, 16), client.sessions,
+			s.FormatDurationFor(config.DurationContextList, client.worked), client.m.Format(client.value))
+		totals[client.m.Currency] = totals[client.m.Currency].Add(client.value)
+		formatters[client.m.Currency] = client.m
+	}
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		fmt.Fprintf(s.out, "  %-36s %14s\n", "Billable "+currency, formatters[currency].Format(totals[currency]))
+	}
+
+	per := "day"
+	if summary.weekly {
+		per = "week"
+	}
+	fmt.Fprintf(s.out, "Hours per %s: |%s|\n", per, sparkline(summary.buckets))
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+func TestSummariseSessionListSkipsActiveSessions(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{})
+	day := time.Date(2025, 10, 6, 9, 0, 0, 0, time.UTC)
+	session := func(client string, start time.Time, worked time.Duration) *models.WorkSession {
+		session := newPrecisionSession(start, worked, 0, "100")
+		session.ClientName = client
+		return session
+	}
+	active := session("acme", day.AddDate(0, 0, 4), time.Hour)
+	active.EndTime = nil
+	sessions := []*models.WorkSession{
+		session("globex", day.AddDate(0, 0, 3), 4*time.Hour),
+		session("acme", day, time.Hour),
+		session("acme", day.AddDate(0, 0, 3), 3*time.Hour),
+		active,
+	}
+	aud := money.New("AUD", "en-AU")
+
+	summary := s.summariseSessionList(sessions, func(*models.WorkSession) money.Formatter { return aud })
+	if summary.sessions != 3 || summary.worked != 8*time.Hour {
+		t.Errorf("summary = %d sessions and %s worked, want 3 and 8h", summary.sessions, summary.worked)
+	}
+	if len(summary.clients) != 2 || summary.clients[0].name != "acme" || summary.clients[0].value.String() != "400" {
+		t.Errorf("clients = %+v, want acme at 400 then globex", summary.clients)
+	}
+	if got := sparkline(summary.buckets); got != "▂  █" {
+		t.Errorf("sparkline = %q, want %q", got, "▂  █")
+	}
+}
@@ -9,30 +9,135 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jesses-code-adventures/work/internal/config"
 	"github.com/jesses-code-adventures/work/internal/models"
 	"github.com/shopspring/decimal"
 )
 
-// ParseTimeString parses time strings in various formats
+// TimeFormatsHelp describes the formats ParseTimeString accepts, for flag help and errors
+const TimeFormatsHelp = "'YYYY-MM-DD HH:MM[:SS]', 'YYYY-MM-DDTHH:MM[:SS]', ISO8601 with an offset such as '2025-03-04T15:04:05+10:00', or 'HH:MM[:SS]' for today"
+
+// localTimeLayouts are timestamps without an offset, read as local time
+var localTimeLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// offsetTimeLayouts are ISO8601 timestamps carrying their own offset, as pasted from other tools
+var offsetTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05Z07:00",
+}
+
+// clockLayouts are times of day, applied to today
+var clockLayouts = []string{"15:04", "15:04:05"}
+
+// ParseTimeString parses a timestamp in any of the formats described by TimeFormatsHelp. Timestamps with
+// an offset are converted to local time so they display alongside other sessions.
 func (s *TimesheetService) ParseTimeString(timeStr string) (time.Time, error) {
-	now := time.Now()
+	return parseTimeString(timeStr, time.Now())
+}
+
+func parseTimeString(timeStr string, now time.Time) (time.Time, error) {
+	timeStr = strings.TrimSpace(timeStr)
+
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, timeStr, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	for _, layout := range offsetTimeLayouts {
+		if t, err := time.Parse(layout, timeStr); err == nil {
+			return t.In(now.Location()), nil
+		}
+	}
+
+	for _, layout := range clockLayouts {
+		if t, err := time.ParseInLocation(layout, timeStr, now.Location()); err == nil {
+			return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, now.Location()), nil
+		}
+	}
 
-	// Try full date-time format first
-	if t, err := time.ParseInLocation("2006-01-02 15:04", timeStr, now.Location()); err == nil {
+	return time.Time{}, fmt.Errorf("time must be in format %s, got %q", TimeFormatsHelp, timeStr)
+}
+
+// ParseDateString parses a date as YYYY-MM-DD, or takes the date from a timestamp in any format
+// ParseTimeString accepts. Dates are returned at midnight UTC, as dates are stored elsewhere.
+func (s *TimesheetService) ParseDateString(dateStr string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr)); err == nil {
 		return t, nil
 	}
+	t, err := parseTimeString(dateStr, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date must be in format YYYY-MM-DD or a timestamp, got %q", dateStr)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// DisplaySession formats and displays a single work session, with the repositories its description was generated
+// from in verbose mode
+func (s *TimesheetService) DisplaySession(session *models.WorkSession, repos []*models.SessionRepo, verbose bool) {
+	s.displaySession(session, "", repos, verbose)
+}
+
+// DisplaySessionList displays sessions as 'work sessions list' does, marking each as invoiced, with the invoice
+// number, or uninvoiced, and ending with the hours and amount not yet invoiced
+func (s *TimesheetService) DisplaySessionList(ctx context.Context, sessions []*models.WorkSession, verbose bool) error {
+	invoices, err := s.db.ListInvoices(ctx, -1)
+	if err != nil {
+		return err
+	}
+	invoiceNumbers := make(map[string]string)
+	for _, invoice := range invoices {
+		invoiceNumbers[invoice.ID] = invoice.InvoiceNumber
+	}
+
+	var uninvoicedDuration time.Duration
+	var uninvoicedAmount decimal.Decimal
+	var uninvoicedCount int
+	for _, session := range sessions {
+		var repos []*models.SessionRepo
+		if verbose {
+			if repos, err = s.db.ListSessionRepos(ctx, session.ID); err != nil {
+				return err
+			}
+		}
 
-	// Try time-only format (assume today)
-	if t, err := time.ParseInLocation("15:04", timeStr, now.Location()); err == nil {
-		// Combine with today's date
-		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location()), nil
+		invoiced := "UNINVOICED"
+		if session.InvoiceID != nil {
+			invoiced = strings.TrimSpace("INVOICED " + invoiceNumbers[*session.InvoiceID])
+		} else if session.EndTime != nil {
+			uninvoicedDuration += s.CalculateDuration(session)
+			uninvoicedAmount = uninvoicedAmount.Add(s.CalculateBillableAmount(session))
+			uninvoicedCount++
+		}
+		s.displaySession(session, invoiced, repos, verbose)
 	}
 
-	return time.Time{}, fmt.Errorf("time must be in format 'YYYY-MM-DD HH:MM' or 'HH:MM'")
+	fmt.Fprintf(s.out, "Uninvoiced: %s | %s across %d completed session(s)\n",
+		s.FormatDurationFor(config.DurationContextList, uninvoicedDuration), s.FormatBillableAmount(uninvoicedAmount), uninvoicedCount)
+	return nil
 }
 
-// DisplaySession formats and displays a single work session
-func (s *TimesheetService) DisplaySession(session *models.WorkSession, verbose bool) {
+// FilterUninvoicedSessions returns the sessions that aren't on an invoice
+func (s *TimesheetService) FilterUninvoicedSessions(sessions []*models.WorkSession) []*models.WorkSession {
+	var filtered []*models.WorkSession
+	for _, session := range sessions {
+		if session.InvoiceID == nil {
+			filtered = append(filtered, session)
+		}
+	}
+	return filtered
+}
+
+// displaySession shows a session, followed on its first line by whether it's been invoiced when invoiced is set
+func (s *TimesheetService) displaySession(session *models.WorkSession, invoiced string, repos []*models.SessionRepo, verbose bool) {
 	duration := s.CalculateDuration(session)
 	billable := s.CalculateBillableAmount(session)
 	status := "Active"
@@ -48,36 +153,93 @@ func (s *TimesheetService) DisplaySession(session *models.WorkSession, verbose b
 		billableStr = fmt.Sprintf(" | %s", s.FormatSessionBillableAmount(session))
 	}
 
+	if invoiced != "" {
+		status += " | " + invoiced
+	}
+
 	// Main session info
-	fmt.Printf("%s | %s | %s - %s (%s)%s | %s\n",
+	fmt.Fprintf(s.out, "%s | %s | %s - %s (%s)%s | %s\n",
 		session.ClientName,
 		session.StartTime.Format("2006-01-02"),
 		session.StartTime.Format("15:04:05"),
 		endTime,
-		s.FormatDuration(duration),
+		s.FormatDurationFor(config.DurationContextList, duration),
 		billableStr,
 		status)
 
 	// Description (always shown if present)
 	if session.Description != nil && *session.Description != "" {
-		fmt.Printf("  → %s\n", *session.Description)
+		fmt.Fprintf(s.out, "  → %s\n", *session.Description)
+	}
+
+	if verbose && session.Hostname != nil {
+		if session.OS != nil {
+			fmt.Fprintf(s.out, "  machine: %s (%s)\n", *session.Hostname, *session.OS)
+		} else {
+			fmt.Fprintf(s.out, "  machine: %s\n", *session.Hostname)
+		}
+	}
+	if verbose && session.GitBranch != nil {
+		fmt.Fprintf(s.out, "  branch: %s\n", *session.GitBranch)
+	}
+
+	if verbose && len(repos) > 0 {
+		fmt.Fprintln(s.out, "  repos:")
+		for _, repo := range repos {
+			fmt.Fprintf(s.out, "    %s (%s)\n", repo.RepoPath, commitCountLabel(repo.CommitCount))
+		}
 	}
 
 	// Full work summary (only in verbose mode)
 	if verbose && session.FullWorkSummary != nil && *session.FullWorkSummary != "" {
-		fmt.Printf("\n  ┌─ Full Work Summary ─────────────────────────────────────────────────\n")
+		fmt.Fprintf(s.out, "\n  ┌─ Full Work Summary ─────────────────────────────────────────────────\n")
 
 		// Format the summary with strategic linebreaks for better readability
 		summary := s.formatSummaryWithBreaks(*session.FullWorkSummary)
 		lines := s.wrapText(summary, 68) // Leave room for indentation
 
 		for _, line := range lines {
-			fmt.Printf("  │ %s\n", line)
+			fmt.Fprintf(s.out, "  │ %s\n", line)
 		}
-		fmt.Printf("  └─────────────────────────────────────────────────────────────────────\n")
+		fmt.Fprintf(s.out, "  └─────────────────────────────────────────────────────────────────────\n")
 	}
 
-	fmt.Println() // Add spacing between sessions
+	fmt.Fprintln(s.out) // Add spacing between sessions
+}
+
+// ListSessionRepos returns the repositories a session's description was generated from
+func (s *TimesheetService) ListSessionRepos(ctx context.Context, sessionID string) ([]*models.SessionRepo, error) {
+	return s.db.ListSessionRepos(ctx, sessionID)
+}
+
+func commitCountLabel(count int64) string {
+	if count == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", count)
+}
+
+// FilterSessionsByMachine returns the sessions started on the machine with the given hostname
+func (s *TimesheetService) FilterSessionsByMachine(sessions []*models.WorkSession, machine string) []*models.WorkSession {
+	var filtered []*models.WorkSession
+	for _, session := range sessions {
+		if session.Hostname != nil && strings.EqualFold(*session.Hostname, machine) {
+			filtered = append(filtered, session)
+		}
+	}
+	return filtered
+}
+
+// otherMachineSuffix names the machine a session was started on when it isn't this one, so timers left
+// running on another machine sharing the database are easy to spot
+func otherMachineSuffix(session *models.WorkSession) string {
+	if session.Hostname == nil {
+		return ""
+	}
+	if hostname, err := os.Hostname(); err == nil && strings.EqualFold(hostname, *session.Hostname) {
+		return ""
+	}
+	return " on " + *session.Hostname
 }
 
 // ExportSessionsCSV exports work sessions to CSV format
@@ -92,10 +254,10 @@ func (s *TimesheetService) ExportSessionsCSV(ctx context.Context, fromDate, toDa
 		if toDate == "" {
 			toDate = "2099-12-31"
 		}
-		fmt.Printf("Exporting with date range %s to %s\n with limit %d\n", fromDate, toDate, limit)
+		fmt.Fprintf(s.out, "Exporting with date range %s to %s\n with limit %d\n", fromDate, toDate, limit)
 		sessions, err = s.ListSessionsWithDateRange(ctx, fromDate, toDate, limit)
 	} else {
-		fmt.Printf("Exporting recent sessions with limit %d\n", limit)
+		fmt.Fprintf(s.out, "Exporting recent sessions with limit %d\n", limit)
 		sessions, err = s.ListRecentSessions(ctx, limit)
 	}
 	if err != nil {
@@ -103,19 +265,18 @@ func (s *TimesheetService) ExportSessionsCSV(ctx context.Context, fromDate, toDa
 	}
 
 	if len(sessions) == 0 {
-		fmt.Println("No sessions found to export.")
+		fmt.Fprintln(s.out, "No sessions found to export.")
 		return nil
 	}
 
-	var file *os.File
-	if output == "" || output == "-" {
-		file = os.Stdout
-	} else {
-		file, err = os.Create(output)
+	file := s.out
+	if output != "" && output != "-" {
+		created, err := os.Create(output)
 		if err != nil {
 			return fmt.Errorf("failed to create output file: %w", err)
 		}
-		defer file.Close()
+		defer created.Close()
+		file = created
 	}
 
 	writer := csv.NewWriter(file)
@@ -123,7 +284,7 @@ func (s *TimesheetService) ExportSessionsCSV(ctx context.Context, fromDate, toDa
 
 	// Write CSV header
 	if err := writer.Write([]string{
-		"ID", "Client", "Start Time", "End Time", "Duration (minutes)", "Hourly Rate", "Billable Amount", "Description", "Outside Git Notes", "Date",
+		"ID", "Client", "Start Time", "End Time", "Duration (minutes)", "Duration", "Currency", "Hourly Rate", "Billable Amount", "Description", "Outside Git Notes", "Date",
 	}); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
@@ -149,12 +310,13 @@ func (s *TimesheetService) ExportSessionsCSV(ctx context.Context, fromDate, toDa
 			outsideGitNotes = *session.OutsideGit
 		}
 
-		hourlyRate := "0.00"
+		m := s.clientMoneyByName(session.ClientName)
+		hourlyRate := m.Number(decimal.Zero)
 		if session.HourlyRate != nil && session.HourlyRate.GreaterThan(decimal.Zero) {
-			hourlyRate = session.HourlyRate.StringFixed(2)
+			hourlyRate = m.Number(*session.HourlyRate)
 		}
 
-		billableAmount := billable.StringFixed(2)
+		billableAmount := m.Number(billable)
 
 		record := []string{
 			session.ID,
@@ -162,6 +324,8 @@ func (s *TimesheetService) ExportSessionsCSV(ctx context.Context, fromDate, toDa
 			session.StartTime.Format("15:04:05"),
 			endTimeStr,
 			durationMinutes,
+			s.FormatDurationFor(config.DurationContextExport, duration),
+			m.Currency,
 			hourlyRate,
 			billableAmount,
 			description,
@@ -175,7 +339,7 @@ func (s *TimesheetService) ExportSessionsCSV(ctx context.Context, fromDate, toDa
 	}
 
 	if output != "" && output != "-" {
-		fmt.Printf("Exported %d sessions to %s\n", len(sessions), output)
+		fmt.Fprintf(s.out, "Exported %d sessions to %s\n", len(sessions), output)
 	}
 
 	return nil
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/db"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// SessionSplit is a proposed point at which to split a session that covered work for two clients
+type SessionSplit struct {
+	Session       *models.WorkSession
+	SessionClient *models.Client
+	OtherClient   *models.Client
+	At            time.Time
+	// OtherFirst is set when the other client's work came before the split point
+	OtherFirst     bool
+	SessionCommits int
+	OtherCommits   int
+	// Misplaced counts commits that fall on the wrong side of the split point
+	Misplaced int
+}
+
+// ProposeSessionSplit looks at commit times in both clients' repositories during a session and proposes
+// the point that best separates one client's commits from the other's
+func (s *TimesheetService) ProposeSessionSplit(ctx context.Context, sessionID, otherClientName string) (*SessionSplit, error) {
+	session, err := s.db.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("session '%s' does not exist", sessionID)
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.EndTime == nil {
+		return nil, ErrSessionNotFinished
+	}
+	if session.InvoiceID != nil {
+		return nil, fmt.Errorf("session '%s' has already been invoiced", sessionID)
+	}
+	if session.BreakSeconds > 0 {
+		return nil, fmt.Errorf("session '%s' has breaks taken out of it, which can't be divided between the split sessions", sessionID)
+	}
+
+	sessionClient, err := s.db.GetClientByID(ctx, session.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for session: %w", err)
+	}
+	otherClient, err := s.db.GetClientByName(ctx, otherClientName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("client '%s' does not exist", otherClientName)
+		}
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+	if otherClient.ID == sessionClient.ID {
+		return nil, fmt.Errorf("session '%s' is already for %s, choose the other client it covered", sessionID, otherClient.Name)
+	}
+
+	sessionCommits, err := s.clientCommitTimes(ctx, sessionClient, session.StartTime, *session.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	otherCommits, err := s.clientCommitTimes(ctx, otherClient, session.StartTime, *session.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessionCommits) == 0 || len(otherCommits) == 0 {
+		return nil, fmt.Errorf("need commits from both clients during the session to propose a split, found %d for %s and %d for %s",
+			len(sessionCommits), sessionClient.Name, len(otherCommits), otherClient.Name)
+	}
+
+	// Work is usually switched to the other client part way through, so that order wins a tie
+	at, misplaced, ok := bestSplitPoint(sessionCommits, otherCommits)
+	otherFirst := false
+	if reverseAt, reverseMisplaced, reverseOK := bestSplitPoint(otherCommits, sessionCommits); reverseOK && (!ok || reverseMisplaced < misplaced) {
+		at, misplaced, ok, otherFirst = reverseAt, reverseMisplaced, reverseOK, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("commits for %s and %s were made at the same times, can't find a split point", sessionClient.Name, otherClient.Name)
+	}
+
+	session.ClientName = sessionClient.Name
+	return &SessionSplit{
+		Session:        session,
+		SessionClient:  sessionClient,
+		OtherClient:    otherClient,
+		At:             at,
+		OtherFirst:     otherFirst,
+		SessionCommits: len(sessionCommits),
+		OtherCommits:   len(otherCommits),
+		Misplaced:      misplaced,
+	}, nil
+}
+
+// DisplaySessionSplit shows the sessions a split would produce
+func (s *TimesheetService) DisplaySessionSplit(split *SessionSplit) {
+	session := split.Session
+	fmt.Fprintf(s.out, "Session %s for %s, %s to %s\n", session.ID, split.SessionClient.Name,
+		session.StartTime.Format("2006-01-02 15:04"), session.EndTime.Format("15:04"))
+	fmt.Fprintf(s.out, "Found %d commit(s) for %s and %d for %s\n", split.SessionCommits, split.SessionClient.Name, split.OtherCommits, split.OtherClient.Name)
+	if split.Misplaced > 0 {
+		fmt.Fprintf(s.out, "%d commit(s) fall on the wrong side of the split, check the proposal before applying it\n", split.Misplaced)
+	}
+
+	first, second := split.SessionClient, split.OtherClient
+	if split.OtherFirst {
+		first, second = second, first
+	}
+	fmt.Fprintf(s.out, "\nProposed split at %s:\n", split.At.Format("15:04"))
+	fmt.Fprintf(s.out, "  %s  %s - %s (%s)\n", truncateString(first.Name, 20), session.StartTime.Format("15:04"), split.At.Format("15:04"),
+		s.FormatDuration(split.At.Sub(session.StartTime)))
+	fmt.Fprintf(s.out, "  %s  %s - %s (%s)\n", truncateString(second.Name, 20), split.At.Format("15:04"), session.EndTime.Format("15:04"),
+		s.FormatDuration(session.EndTime.Sub(split.At)))
+}
+
+// ApplySessionSplit narrows the session to its client's side of the split and creates a session for the
+// other client covering the rest, at the other client's rate. A session that's been invoiced is only split when
+// forced, and its invoice then needs regenerating.
+func (s *TimesheetService) ApplySessionSplit(ctx context.Context, split *SessionSplit, force bool) (*models.WorkSession, *models.WorkSession, error) {
+	session := split.Session
+	start, end := session.StartTime, split.At
+	otherStart, otherEnd := split.At, *session.EndTime
+	if split.OtherFirst {
+		start, end = split.At, *session.EndTime
+		otherStart, otherEnd = session.StartTime, split.At
+	}
+
+	var rate decimal.NullDecimal
+	if otherRate := s.sessionRate(ctx, split.OtherClient, nil, otherStart); otherRate.GreaterThan(decimal.Zero) {
+		rate = decimal.NullDecimal{Decimal: otherRate, Valid: true}
+	}
+
+	updated, created, err := s.db.SplitSession(ctx, session.ID, start, end, db.CreateSessionParams{
+		ID:          models.NewUUID(),
+		ClientID:    split.OtherClient.ID,
+		StartTime:   otherStart,
+		HourlyRate:  rate,
+		IncludesGst: session.IncludesGst,
+		// The other client's share was worked on the same machine, by the same person, as the session
+		Hostname:  sql.NullString{String: utils.FromPtr(session.Hostname), Valid: session.Hostname != nil},
+		Os:        sql.NullString{String: utils.FromPtr(session.OS), Valid: session.OS != nil},
+		GitBranch: sql.NullString{String: utils.FromPtr(session.GitBranch), Valid: session.GitBranch != nil},
+		UserID:    sql.NullString{String: utils.FromPtr(session.UserID), Valid: session.UserID != nil},
+	}, otherEnd, force)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to split session: %w", invoicedSessionsError(err))
+	}
+
+	updated.ClientName = split.SessionClient.Name
+	created.ClientName = split.OtherClient.Name
+	s.warnIfSessionRateBelowMinimum(split.OtherClient, created)
+	return updated, created, nil
+}
+
+// clientCommitTimes returns the times of commits made in a client's repositories between from and to
+func (s *TimesheetService) clientCommitTimes(ctx context.Context, client *models.Client, from, to time.Time) ([]time.Time, error) {
+	if utils.FromPtr(client.Dir) == "" {
+		return nil, fmt.Errorf("client '%s' has no directory configured, set one with 'work clients update %s --dir <dir>'", client.Name, client.Name)
+	}
+	dir, err := expandClientDir(*client.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var times []time.Time
+	for _, repo := range s.findGitRepositoriesWalk(dir) {
+		cmd := exec.CommandContext(ctx, "git", "-C", repo, "log", "--all",
+			"--since="+from.Format(time.RFC3339), "--until="+to.Format(time.RFC3339), "--format=%ct")
+		output, err := cmd.Output()
+		if err != nil {
+			s.logger.Warn("failed to read git log", "repo", repo, "error", err)
+			continue
+		}
+		for _, line := range strings.Fields(string(output)) {
+			seconds, err := strconv.ParseInt(line, 10, 64)
+			if err != nil {
+				continue
+			}
+			times = append(times, time.Unix(seconds, 0).In(from.Location()))
+		}
+	}
+	s.logger.Debug("read commit times", "client", client.Name, "dir", dir, "commits", len(times))
+	return times, nil
+}
+
+// bestSplitPoint finds the time that best separates earlier commits in first from later commits in
+// second, returning it with the number of commits on the wrong side. Ties go to the widest gap
+// between commits, and the split is placed halfway across the gap.
+func bestSplitPoint(first, second []time.Time) (time.Time, int, bool) {
+	type commit struct {
+		at    time.Time
+		first bool
+	}
+	commits := make([]commit, 0, len(first)+len(second))
+	for _, t := range first {
+		commits = append(commits, commit{t, true})
+	}
+	for _, t := range second {
+		commits = append(commits, commit{t, false})
+	}
+	sort.Slice(commits, func(i, j int) bool { return commits[i].at.Before(commits[j].at) })
+
+	// Splitting before commit k misplaces the second client's commits before it and the first's from it on
+	secondBefore := 0
+	firstFrom := len(first)
+	var best time.Time
+	bestMisplaced := -1
+	var bestGap time.Duration
+	for k := 1; k < len(commits); k++ {
+		if commits[k-1].first {
+			firstFrom--
+		} else {
+			secondBefore++
+		}
+		gap := commits[k].at.Sub(commits[k-1].at)
+		if gap <= 0 {
+			continue
+		}
+		misplaced := secondBefore + firstFrom
+		if bestMisplaced == -1 || misplaced < bestMisplaced || (misplaced == bestMisplaced && gap > bestGap) {
+			best = commits[k-1].at.Add(gap / 2)
+			if rounded := best.Truncate(time.Minute); rounded.After(commits[k-1].at) {
+				best = rounded
+			}
+			bestMisplaced = misplaced
+			bestGap = gap
+		}
+	}
+	return best, bestMisplaced, bestMisplaced != -1
+}
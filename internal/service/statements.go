@@ -0,0 +1,503 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// statementDateLayouts are the date formats banks export statements with, day first as Australian banks do
+var statementDateLayouts = []string{"02/01/2006", "2/1/2006", "2006-01-02", "02-01-2006", "2 Jan 2006", "02 Jan 2006", "2006/01/02"}
+
+// statementColumns are the header names each column of a statement is known by
+var statementColumns = map[string][]string{
+	"date":        {"date", "transaction date", "posted date", "posting date", "value date"},
+	"description": {"description", "narrative", "details", "transaction details", "memo", "payee", "merchant"},
+	"amount":      {"amount", "transaction amount", "value"},
+	"debit":       {"debit", "debit amount", "withdrawal", "withdrawals"},
+	"credit":      {"credit", "credit amount", "deposit", "deposits"},
+}
+
+// Statement is a card or bank statement read for importing as expenses
+type Statement struct {
+	Transactions []*StatementTransaction
+	Credits      int // incoming transactions, such as refunds and repayments, which aren't expenses
+}
+
+// StatementTransaction is an outgoing transaction on a statement. Expense is what it will be imported as, set
+// by the first rule matching its description or by AssignStatementTransaction, and nil until then.
+type StatementTransaction struct {
+	Line        int
+	Date        time.Time
+	Description string
+	Amount      decimal.Decimal
+	Rule        *models.ExpenseRule
+	Expense     *models.Expense
+	Skipped     bool // matched a rule that skips it
+	Duplicate   bool // an expense with the same date, amount and description already exists
+}
+
+// Unmatched returns the transactions that still need a client and category assigned before they're imported
+func (st *Statement) Unmatched() []*StatementTransaction {
+	var unmatched []*StatementTransaction
+	for _, transaction := range st.Transactions {
+		if transaction.Expense == nil && !transaction.Skipped && !transaction.Duplicate {
+			unmatched = append(unmatched, transaction)
+		}
+	}
+	return unmatched
+}
+
+// Expenses returns the expenses to create, one for each transaction that was matched or assigned
+func (st *Statement) Expenses() []*models.Expense {
+	var expenses []*models.Expense
+	for _, transaction := range st.Transactions {
+		if transaction.Expense != nil && !transaction.Duplicate {
+			expenses = append(expenses, transaction.Expense)
+		}
+	}
+	return expenses
+}
+
+// ApplyRule applies a rule added while importing to the unmatched transactions it matches, returning how many
+// it matched
+func (st *Statement) ApplyRule(rule *models.ExpenseRule) (int, error) {
+	pattern, err := compileExpenseRulePattern(rule.Pattern)
+	if err != nil {
+		return 0, err
+	}
+	matched := 0
+	for _, transaction := range st.Unmatched() {
+		if !pattern.MatchString(transaction.Description) {
+			continue
+		}
+		transaction.Rule = rule
+		if rule.Skip {
+			transaction.Skipped = true
+		} else {
+			transaction.Expense = statementExpense(transaction, rule.ClientID, rule.ClientName, rule.Category, rule.Billable)
+		}
+		matched++
+	}
+	return matched, nil
+}
+
+// ReadStatement reads a CSV card or bank statement and matches its outgoing transactions against the expense
+// rules. Transactions already imported are marked as duplicates. Purchases are negative amounts, as most banks
+// export them, unless debitsPositive is set; statements with separate debit and credit columns work either way.
+func (s *TimesheetService) ReadStatement(ctx context.Context, r io.Reader, debitsPositive bool) (*Statement, error) {
+	statement, err := parseStatement(r, debitsPositive)
+	if err != nil {
+		return nil, err
+	}
+	if len(statement.Transactions) == 0 {
+		return statement, nil
+	}
+
+	rules, err := s.db.ListExpenseRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matchers, err := compileExpenseRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	for _, transaction := range statement.Transactions {
+		rule := matchExpenseRule(matchers, transaction.Description)
+		if rule == nil {
+			continue
+		}
+		transaction.Rule = rule
+		if rule.Skip {
+			transaction.Skipped = true
+			continue
+		}
+		transaction.Expense = statementExpense(transaction, rule.ClientID, rule.ClientName, rule.Category, rule.Billable)
+	}
+
+	if err := s.markDuplicateTransactions(ctx, statement); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// AssignStatementTransaction sets the client, category and billable setting of a transaction no rule matched.
+// The client and category can be empty, for your own uncategorised costs.
+func (s *TimesheetService) AssignStatementTransaction(ctx context.Context, transaction *StatementTransaction, clientName, category string, billable bool) error {
+	if err := ValidateExpenseCategory(category); err != nil {
+		return err
+	}
+	var clientID, name *string
+	if clientName != "" {
+		client, err := s.db.GetClientByName(ctx, clientName)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("client '%s' does not exist", clientName)
+			}
+			return fmt.Errorf("failed to get client: %w", err)
+		}
+		clientID, name = &client.ID, &client.Name
+	}
+	var categoryPtr *string
+	if category != "" {
+		category = strings.ToLower(category)
+		categoryPtr = &category
+	}
+	transaction.Expense = statementExpense(transaction, clientID, name, categoryPtr, billable && clientID != nil)
+	return nil
+}
+
+// ImportStatement creates the statement's matched and assigned expenses in a single transaction
+func (s *TimesheetService) ImportStatement(ctx context.Context, statement *Statement) ([]*models.Expense, error) {
+	expenses := statement.Expenses()
+	if len(expenses) == 0 {
+		return nil, nil
+	}
+	return s.db.CreateExpenses(ctx, expenses)
+}
+
+// AddExpenseRule adds a rule for importing statements, tried after the rules added before it. A rule either
+// skips the transactions it matches or files them under a client and category, both of which are optional.
+func (s *TimesheetService) AddExpenseRule(ctx context.Context, pattern, clientName, category string, billable, skip bool) (*models.ExpenseRule, error) {
+	if _, err := compileExpenseRulePattern(pattern); err != nil {
+		return nil, err
+	}
+	if skip && (clientName != "" || category != "" || billable) {
+		return nil, fmt.Errorf("a rule that skips transactions can't also set a client, category or billable")
+	}
+	if billable && clientName == "" {
+		return nil, fmt.Errorf("a billable rule needs a client to bill")
+	}
+	if err := ValidateExpenseCategory(category); err != nil {
+		return nil, err
+	}
+
+	rule := &models.ExpenseRule{Pattern: pattern, Billable: billable, Skip: skip}
+	if clientName != "" {
+		client, err := s.db.GetClientByName(ctx, clientName)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("client '%s' does not exist", clientName)
+			}
+			return nil, fmt.Errorf("failed to get client: %w", err)
+		}
+		rule.ClientID, rule.ClientName = &client.ID, &client.Name
+	}
+	if category != "" {
+		category = strings.ToLower(category)
+		rule.Category = &category
+	}
+	return s.db.CreateExpenseRule(ctx, rule)
+}
+
+// DisplayExpenseRules lists the rules in the order they're tried
+func (s *TimesheetService) DisplayExpenseRules(ctx context.Context) error {
+	rules, err := s.db.ListExpenseRules(ctx)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		fmt.Fprintln(s.out, "No expense rules found. Add one with 'work expenses rules add'.")
+		return nil
+	}
+	for _, rule := range rules {
+		fmt.Fprintf(s.out, "%s: %s\n", rule.ID, ExpenseRuleSummary(rule))
+	}
+	return nil
+}
+
+// DeleteExpenseRule removes a rule by ID
+func (s *TimesheetService) DeleteExpenseRule(ctx context.Context, ruleID string) (*models.ExpenseRule, error) {
+	rule, err := s.db.GetExpenseRuleByID(ctx, ruleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("expense rule '%s' does not exist, see 'work expenses rules list'", ruleID)
+		}
+		return nil, err
+	}
+	if err := s.db.DeleteExpenseRule(ctx, rule.ID); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// ExpenseRuleSummary describes a rule on one line, e.g. "/github|figma/ -> acme, software, billable"
+func ExpenseRuleSummary(rule *models.ExpenseRule) string {
+	if rule.Skip {
+		return fmt.Sprintf("/%s/ -> skipped", rule.Pattern)
+	}
+	return fmt.Sprintf("/%s/ -> %s", rule.Pattern, expenseFiling(rule.ClientName, rule.Category, rule.Billable))
+}
+
+// expenseFiling describes who an imported expense is for and how it's filed, e.g. "acme, software, billable"
+func expenseFiling(clientName, category *string, billable bool) string {
+	var parts []string
+	if clientName != nil {
+		parts = append(parts, *clientName)
+	}
+	if category != nil {
+		parts = append(parts, *category)
+	}
+	if billable {
+		parts = append(parts, "billable")
+	}
+	if len(parts) == 0 {
+		return "your own, uncategorised"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// StatementTransactionSummary describes a transaction and what it will be imported as on one line, e.g.
+// "2025-07-01 GITHUB INC $21.00 -> acme, software, billable"
+func (s *TimesheetService) StatementTransactionSummary(transaction *StatementTransaction) string {
+	outcome := "unmatched"
+	switch {
+	case transaction.Duplicate:
+		outcome = "already imported"
+	case transaction.Skipped:
+		outcome = "skipped"
+	case transaction.Expense != nil:
+		outcome = expenseFiling(transaction.Expense.ClientName, transaction.Expense.Category, transaction.Expense.Billable)
+	}
+	return fmt.Sprintf("%s %s %s -> %s", transaction.Date.Format("2006-01-02"), transaction.Description,
+		s.homeMoney().Format(transaction.Amount), outcome)
+}
+
+// markDuplicateTransactions marks the transactions matching an existing expense's date, amount and description,
+// so a statement imported twice, or overlapping the last one, doesn't create expenses twice. Each existing
+// expense accounts for one transaction, so two identical purchases on the same day are both kept the first time.
+func (s *TimesheetService) markDuplicateTransactions(ctx context.Context, statement *Statement) error {
+	from, to := statement.Transactions[0].Date, statement.Transactions[0].Date
+	for _, transaction := range statement.Transactions {
+		if transaction.Date.Before(from) {
+			from = transaction.Date
+		}
+		if transaction.Date.After(to) {
+			to = transaction.Date
+		}
+	}
+	existing, err := s.db.ListExpensesByDateRange(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+
+	key := func(date time.Time, amount decimal.Decimal, description string) string {
+		return fmt.Sprintf("%s|%s|%s", date.Format("2006-01-02"), amount.StringFixed(2), strings.ToLower(strings.TrimSpace(description)))
+	}
+	imported := make(map[string]int)
+	for _, expense := range existing {
+		if expense.Description != nil {
+			imported[key(expense.ExpenseDate, expense.Amount, *expense.Description)]++
+		}
+	}
+	for _, transaction := range statement.Transactions {
+		k := key(transaction.Date, transaction.Amount, transaction.Description)
+		if imported[k] > 0 {
+			imported[k]--
+			transaction.Duplicate = true
+		}
+	}
+	return nil
+}
+
+// statementExpense is the expense a transaction is imported as
+func statementExpense(transaction *StatementTransaction, clientID, clientName, category *string, billable bool) *models.Expense {
+	description := transaction.Description
+	return &models.Expense{
+		Amount:      transaction.Amount,
+		ExpenseDate: transaction.Date,
+		ClientID:    clientID,
+		ClientName:  clientName,
+		Description: &description,
+		Category:    category,
+		Billable:    billable,
+	}
+}
+
+// expenseRuleMatcher is a rule with its pattern compiled
+type expenseRuleMatcher struct {
+	rule    *models.ExpenseRule
+	pattern *regexp.Regexp
+}
+
+func compileExpenseRules(rules []*models.ExpenseRule) ([]expenseRuleMatcher, error) {
+	matchers := make([]expenseRuleMatcher, 0, len(rules))
+	for _, rule := range rules {
+		pattern, err := compileExpenseRulePattern(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("expense rule %s: %w", rule.ID, err)
+		}
+		matchers = append(matchers, expenseRuleMatcher{rule: rule, pattern: pattern})
+	}
+	return matchers, nil
+}
+
+// compileExpenseRulePattern compiles a rule's pattern, which is matched ignoring case
+func compileExpenseRulePattern(pattern string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, fmt.Errorf("a rule needs a pattern to match descriptions with")
+	}
+	compiled, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return compiled, nil
+}
+
+// matchExpenseRule returns the first rule matching description, or nil when none do
+func matchExpenseRule(matchers []expenseRuleMatcher, description string) *models.ExpenseRule {
+	for _, matcher := range matchers {
+		if matcher.pattern.MatchString(description) {
+			return matcher.rule
+		}
+	}
+	return nil
+}
+
+// parseStatement reads the outgoing transactions from a CSV statement. Statements with a header row are read by
+// their column names. Those without one are read as date, amount and description, as the Commonwealth Bank and
+// others export them.
+func parseStatement(r io.Reader, debitsPositive bool) (*Statement, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{"date": 0, "amount": 1, "description": 2, "debit": -1, "credit": -1}
+	statement := &Statement{}
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read statement: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if first && isStatementHeader(record) {
+			if columns, err = statementHeaderColumns(record); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if blankRecord(record) {
+			continue
+		}
+		field := func(name string) string {
+			if index := columns[name]; index >= 0 && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+
+		date, err := parseStatementDate(field("date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		var amount decimal.Decimal
+		if columns["amount"] >= 0 {
+			amount, err = parseStatementAmount(field("amount"))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if !debitsPositive {
+				amount = amount.Neg()
+			}
+		} else {
+			amount, err = parseStatementAmount(field("debit"))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			amount = amount.Abs()
+		}
+		if !amount.IsPositive() {
+			statement.Credits++
+			continue
+		}
+
+		statement.Transactions = append(statement.Transactions, &StatementTransaction{
+			Line:        line,
+			Date:        date,
+			Description: strings.Join(strings.Fields(field("description")), " "),
+			Amount:      amount,
+		})
+	}
+	return statement, nil
+}
+
+// isStatementHeader reports whether a record names a statement's columns rather than being a transaction
+func isStatementHeader(record []string) bool {
+	for _, cell := range record {
+		for _, name := range statementColumns["date"] {
+			if strings.EqualFold(strings.TrimSpace(cell), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// statementHeaderColumns finds the index of each column from a header row, -1 for those it doesn't have
+func statementHeaderColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(statementColumns))
+	for column, names := range statementColumns {
+		columns[column] = -1
+		for i, cell := range header {
+			for _, name := range names {
+				if columns[column] < 0 && strings.EqualFold(strings.TrimSpace(cell), name) {
+					columns[column] = i
+				}
+			}
+		}
+	}
+	if columns["date"] < 0 || columns["description"] < 0 || (columns["amount"] < 0 && columns["debit"] < 0) {
+		return nil, fmt.Errorf("statement needs date, description and amount or debit columns, found: %s", strings.Join(header, ", "))
+	}
+	return columns, nil
+}
+
+func parseStatementDate(value string) (time.Time, error) {
+	for _, layout := range statementDateLayouts {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date %q, expected DD/MM/YYYY or YYYY-MM-DD", value)
+}
+
+// parseStatementAmount reads amounts like -45.90, $1,200.00 or (45.90), treating an empty amount as zero
+func parseStatementAmount(value string) (decimal.Decimal, error) {
+	cleaned := strings.NewReplacer("$", "", ",", "", " ", "").Replace(value)
+	if cleaned == "" {
+		return decimal.Zero, nil
+	}
+	negative := strings.HasPrefix(cleaned, "(") && strings.HasSuffix(cleaned, ")")
+	cleaned = strings.Trim(cleaned, "()")
+	amount, err := decimal.NewFromString(cleaned)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("unrecognised amount %q", value)
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, nil
+}
+
+func blankRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+func TestParseStatement(t *testing.T) {
+	type transaction struct {
+		line        int
+		date        string
+		description string
+		amount      string
+	}
+	tests := []struct {
+		name           string
+		input          string
+		debitsPositive bool
+		want           []transaction
+		credits        int
+	}{
+		{
+			name:    "CSV without a header, purchases negative",
+			input:   "01/07/2025,-21.00,GITHUB  INC   SAN FRANCISCO\n02/07/2025,\"+1,500.00\",PAYMENT RECEIVED\n\n03/07/2025,-$45.90,OFFICEWORKS\n",
+			want:    []transaction{{1, "2025-07-01", "GITHUB INC SAN FRANCISCO", "21"}, {4, "2025-07-03", "OFFICEWORKS", "45.9"}},
+			credits: 1,
+		},
+		{
+			name:           "CSV with a header, purchases positive",
+			input:          "Transaction Date,Amount,Narrative\n2025-07-01,21.00,GITHUB INC\n2025-07-02,(10.00),REFUND\n",
+			debitsPositive: true,
+			want:           []transaction{{2, "2025-07-01", "GITHUB INC", "21"}},
+			credits:        1,
+		},
+		{
+			name:    "CSV with debit and credit columns",
+			input:   "Date,Details,Debit,Credit\n1 Jul 2025,QANTAS,350.00,\n2 Jul 2025,SALARY,,4000.00\n",
+			want:    []transaction{{2, "2025-07-01", "QANTAS", "350"}},
+			credits: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement, err := parseStatement(strings.NewReader(tt.input), tt.debitsPositive)
+			if err != nil {
+				t.Fatalf("parseStatement() error = %v", err)
+			}
+			if statement.Credits != tt.credits {
+				t.Errorf("Credits = %d, want %d", statement.Credits, tt.credits)
+			}
+			if len(statement.Transactions) != len(tt.want) {
+				t.Fatalf("parseStatement() read %d transactions, want %d", len(statement.Transactions), len(tt.want))
+			}
+			for i, got := range statement.Transactions {
+				want := tt.want[i]
+				if got.Line != want.line || got.Date.Format("2006-01-02") != want.date || got.Description != want.description ||
+					got.Amount.String() != want.amount {
+					t.Errorf("transaction %d = line %d %s %q %s, want %+v", i, got.Line, got.Date.Format("2006-01-02"),
+						got.Description, got.Amount, want)
+				}
+			}
+		})
+	}
+
+	if _, err := parseStatement(strings.NewReader("Date,Description,Balance\n"), false); err == nil {
+		t.Error("parseStatement() with no amount column returned no error")
+	}
+}
+
+func TestMatchExpenseRuleUsesTheFirstMatch(t *testing.T) {
+	rules := []*models.ExpenseRule{
+		{ID: "skip", Pattern: "payment received", Skip: true},
+		{ID: "software", Pattern: `github|figma`},
+		{ID: "catch-all", Pattern: `.`},
+	}
+	matchers, err := compileExpenseRules(rules)
+	if err != nil {
+		t.Fatalf("compileExpenseRules() error = %v", err)
+	}
+	for description, want := range map[string]string{
+		"PAYMENT RECEIVED, THANK YOU": "skip",
+		"GitHub Inc":                  "software",
+		"OFFICEWORKS":                 "catch-all",
+	} {
+		if got := matchExpenseRule(matchers, description); got == nil || got.ID != want {
+			t.Errorf("matchExpenseRule(%q) = %+v, want rule %s", description, got, want)
+		}
+	}
+}
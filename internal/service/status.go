@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// WorkStatus is what's being worked on right now and the time tracked today, as served to widgets by
+// 'work serve --status-only'
+type WorkStatus struct {
+	Active         bool       `json:"active"`
+	Client         string     `json:"client,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	ElapsedSeconds int64      `json:"elapsed_seconds"`
+	Elapsed        string     `json:"elapsed"`
+	TodaySeconds   int64      `json:"today_seconds"`
+	Today          string     `json:"today"`
+}
+
+// CurrentStatus returns the active session, if any, with how long it has been worked for, and the time worked
+// today across every session including the active one. Durations are formatted in the status format.
+func (s *TimesheetService) CurrentStatus(ctx context.Context) (*WorkStatus, error) {
+	status := &WorkStatus{}
+	session, err := s.GetActiveSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if session != nil {
+		elapsed := s.CalculateDuration(session)
+		started := session.StartTime
+		status.Active = true
+		status.Client = session.ClientName
+		if session.Description != nil {
+			status.Description = *session.Description
+		}
+		status.StartedAt = &started
+		status.ElapsedSeconds = int64(elapsed.Seconds())
+	}
+	status.Elapsed = s.FormatDuration(time.Duration(status.ElapsedSeconds) * time.Second)
+
+	today := time.Now().Format("2006-01-02")
+	total, err := s.TotalHours(ctx, "", "", "", "", today, today)
+	if err != nil {
+		return nil, err
+	}
+	status.TodaySeconds = int64(total.Worked.Seconds())
+	status.Today = s.FormatDuration(total.Worked)
+	return status, nil
+}
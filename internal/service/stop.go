@@ -1,5 +1,94 @@
 package service
 
-// This file contains stop-related service methods
-// Currently, the stop functionality is handled by the StopWork method in timesheet.go
-// This file is created for consistency with the other command service files
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// ShowSessionEarnings prints what a session has earned so far, for when it stops or while it's running: the
+// amount billed for it with GST where it applies and, for clients on a retainer, how much of it the retainer
+// covers and the effective hourly rate over the retainer's period once the retainer is counted. Sessions are
+// covered by the retainer in the order they started, the same as on the period's invoice.
+func (s *TimesheetService) ShowSessionEarnings(ctx context.Context, session *models.WorkSession) error {
+	client, err := s.db.GetClientByID(ctx, session.ClientID)
+	if err != nil {
+		return fmt.Errorf("failed to get client: %w", err)
+	}
+	m := s.clientMoney(client)
+	worked := s.CalculateDuration(session)
+
+	var retainer retainerTerms
+	var period string
+	var periodFrom, periodTo time.Time
+	if client.RetainerBasis != nil {
+		period = *client.RetainerBasis
+		periodFrom, periodTo = s.CalculatePeriodRange(period, session.StartTime)
+		retainer = s.retainerForPeriod(client, period, periodFrom, periodTo)
+	}
+	if !retainer.applies() {
+		fmt.Fprintf(s.out, "Billable amount: %s\n", s.FormatSessionBillableAmount(session))
+		if worked > 0 && session.HourlyRate != nil && session.HourlyRate.IsPositive() {
+			fmt.Fprintf(s.out, "Effective rate: %s/hour\n", m.Format(s.CalculateBillableAmount(session).Div(decimal.NewFromFloat(worked.Hours())).Round(2)))
+		}
+		return nil
+	}
+
+	sessions, err := s.ListSessionsWithDateRange(ctx, periodFrom.Format("2006-01-02"), periodTo.Format("2006-01-02"), 100000)
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %w", err)
+	}
+	var periodSessions []*models.WorkSession
+	found := false
+	for _, other := range sessions {
+		if other.ClientID != session.ClientID {
+			continue
+		}
+		if other.ID == session.ID {
+			other, found = session, true
+		}
+		periodSessions = append(periodSessions, other)
+	}
+	if !found {
+		periodSessions = append(periodSessions, session)
+	}
+	sort.SliceStable(periodSessions, func(i, j int) bool { return periodSessions[i].StartTime.Before(periodSessions[j].StartTime) })
+
+	// How much of the retainer's hours were used before this session, and in the period so far
+	var before, periodWorked time.Duration
+	for _, other := range periodSessions {
+		if other.StartTime.Before(session.StartTime) {
+			before += s.CalculateDuration(other)
+		}
+		periodWorked += s.CalculateDuration(other)
+	}
+	retainerHours := time.Duration(retainer.hours.Mul(decimal.NewFromInt(int64(time.Hour))).IntPart())
+	covered := min(max(retainerHours-before, 0), worked)
+	used := min(periodWorked, retainerHours)
+
+	amount := decimal.Zero
+	if session.HourlyRate != nil && session.HourlyRate.IsPositive() {
+		amount = billedAmount(worked-covered, *session.HourlyRate)
+	}
+	if covered > 0 {
+		fmt.Fprintf(s.out, "Billable amount: %s beyond the retainer\n", s.formatSessionAmount(m, s.chargesGST(client), session, amount))
+	} else {
+		fmt.Fprintf(s.out, "Billable amount: %s\n", s.formatSessionAmount(m, s.chargesGST(client), session, amount))
+	}
+	fmt.Fprintf(s.out, "Retainer: %s of this session covered, %s of %s used this %s\n",
+		s.FormatDuration(covered), s.FormatDurationFor(config.DurationContextReport, used),
+		s.FormatDurationFor(config.DurationContextReport, retainerHours), period)
+
+	// What the period earns per hour worked: the retainer plus what's billed beyond it, excluding GST
+	if periodWorked > 0 {
+		earned := retainer.amount.Add(exclusiveTotal(s.sessionGSTLines(periodSessions, retainer), s.chargesGST(client)))
+		fmt.Fprintf(s.out, "Effective rate: %s/hour this %s after the retainer\n", m.Format(earned.Div(decimal.NewFromFloat(periodWorked.Hours())).Round(2)), period)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/database"
+)
+
+// SyncDatabase syncs an embedded replica with its Turso primary now, instead of waiting for the next
+// background sync
+func (s *TimesheetService) SyncDatabase(ctx context.Context) error {
+	status, err := s.db.Sync(ctx)
+	if errors.Is(err, database.ErrNoReplica) {
+		return s.noReplicaError()
+	}
+	if err != nil {
+		if status != nil && status.Failures > 0 {
+			return fmt.Errorf("%w (%d failed in a row, retrying in the background from %s)", err, status.Failures, status.NextAttempt.Format("15:04:05"))
+		}
+		return err
+	}
+	fmt.Fprintf(s.out, "Synced at %s (frame %d)\n", status.LastSync.Format("2006-01-02 15:04:05"), status.FrameNo)
+	if status.PendingFrames > 0 {
+		fmt.Fprintf(s.out, "%d frame(s) still to push\n", status.PendingFrames)
+	}
+	return nil
+}
+
+// ShowSyncStatus shows when an embedded replica last synced, what's waiting to be pushed and any conflicts
+func (s *TimesheetService) ShowSyncStatus(ctx context.Context) error {
+	status, err := s.db.SyncStatus(ctx)
+	if errors.Is(err, database.ErrNoReplica) {
+		return s.noReplicaError()
+	}
+	if err != nil {
+		return err
+	}
+
+	if status.LastSync.IsZero() {
+		fmt.Fprintln(s.out, "Last sync: never")
+	} else {
+		fmt.Fprintf(s.out, "Last sync: %s (%s ago)\n", status.LastSync.Format("2006-01-02 15:04:05"), s.FormatDuration(time.Since(status.LastSync)))
+	}
+	fmt.Fprintf(s.out, "Frame: %d\n", status.FrameNo)
+	fmt.Fprintf(s.out, "Pending frames: %d\n", status.PendingFrames)
+	if status.Failures > 0 {
+		fmt.Fprintf(s.out, "Failing: %d attempt(s) since %s, last error: %s\n", status.Failures, status.LastAttempt.Format("2006-01-02 15:04:05"), status.LastError)
+		fmt.Fprintf(s.out, "Next attempt: %s\n", status.NextAttempt.Format("2006-01-02 15:04:05"))
+	}
+	if len(status.Conflicts) == 0 {
+		fmt.Fprintln(s.out, "Conflicts: none")
+		return nil
+	}
+	fmt.Fprintf(s.out, "Conflicts: %d\n", len(status.Conflicts))
+	for _, conflict := range status.Conflicts {
+		fmt.Fprintf(s.out, "  %s  %s\n", conflict.Time.Format("2006-01-02 15:04:05"), conflict.Message)
+	}
+	return nil
+}
+
+func (s *TimesheetService) noReplicaError() error {
+	return fmt.Errorf("%s isn't an embedded replica, it's opened directly with %s so there's nothing to sync", s.cfg.DatabaseURL, s.cfg.DatabaseDriver)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// CreateSessionTemplate saves a named template for a recurring kind of session, such as a daily standup.
+// A duration of 0 means sessions from the template are started and stopped as usual rather than logged.
+func (s *TimesheetService) CreateSessionTemplate(ctx context.Context, name, clientName string, description *string, duration time.Duration) (*models.SessionTemplate, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("template name is required")
+	}
+	if duration < 0 {
+		return nil, fmt.Errorf("duration must not be negative")
+	}
+	if duration > 0 && duration < time.Minute {
+		return nil, fmt.Errorf("duration must be at least a minute")
+	}
+
+	if _, err := s.db.GetSessionTemplateByName(ctx, name); err == nil {
+		return nil, fmt.Errorf("template '%s' already exists", name)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check for existing template: %w", err)
+	}
+
+	client, err := s.db.GetClientByName(ctx, clientName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("client '%s' does not exist", clientName)
+		}
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+
+	var minutes *int64
+	if duration > 0 {
+		m := int64(duration.Round(time.Minute) / time.Minute)
+		minutes = &m
+	}
+
+	template, err := s.db.CreateSessionTemplate(ctx, name, client.ID, description, minutes)
+	if err != nil {
+		return nil, err
+	}
+	template.ClientName = client.Name
+	return template, nil
+}
+
+// GetSessionTemplate returns the template with the given name
+func (s *TimesheetService) GetSessionTemplate(ctx context.Context, name string) (*models.SessionTemplate, error) {
+	template, err := s.db.GetSessionTemplateByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("template '%s' does not exist, see 'work templates list'", name)
+		}
+		return nil, err
+	}
+
+	client, err := s.db.GetClientByID(ctx, template.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client for template: %w", err)
+	}
+	template.ClientName = client.Name
+	return template, nil
+}
+
+// ListSessionTemplates returns every template with its client's name
+func (s *TimesheetService) ListSessionTemplates(ctx context.Context) ([]*models.SessionTemplate, error) {
+	templates, err := s.db.ListSessionTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, template := range templates {
+		client, err := s.db.GetClientByID(ctx, template.ClientID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client for template '%s': %w", template.Name, err)
+		}
+		template.ClientName = client.Name
+	}
+	return templates, nil
+}
+
+// DeleteSessionTemplate deletes a template. Sessions created from it are kept.
+func (s *TimesheetService) DeleteSessionTemplate(ctx context.Context, name string) error {
+	template, err := s.GetSessionTemplate(ctx, name)
+	if err != nil {
+		return err
+	}
+	return s.db.DeleteSessionTemplate(ctx, template.ID)
+}
+
+// StartFromTemplate starts a session for the template's client and description, stopping any active session
+func (s *TimesheetService) StartFromTemplate(ctx context.Context, name string) (*models.WorkSession, error) {
+	template, err := s.GetSessionTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.StartWork(ctx, template.ClientName, template.Description, nil, false)
+}
+
+// QuickSession logs a finished session from a template, ending now and lasting the template's duration.
+// Templates without a duration start a session instead.
+func (s *TimesheetService) QuickSession(ctx context.Context, name string) (*models.WorkSession, error) {
+	template, err := s.GetSessionTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if template.DurationMinutes == nil {
+		return s.StartWork(ctx, template.ClientName, template.Description, nil, false)
+	}
+
+	end := time.Now().Truncate(time.Minute)
+	start := end.Add(-time.Duration(*template.DurationMinutes) * time.Minute)
+	return s.CreateSessionWithTimes(ctx, template.ClientName, start, end, template.Description, false)
+}
+
+// DisplaySessionTemplate prints a one line summary of a template
+func (s *TimesheetService) DisplaySessionTemplate(template *models.SessionTemplate) {
+	fmt.Fprintf(s.out, "%s - %s", template.Name, template.ClientName)
+	if template.DurationMinutes != nil {
+		fmt.Fprintf(s.out, " - %s", s.FormatDurationFor(config.DurationContextList, time.Duration(*template.DurationMinutes)*time.Minute))
+	}
+	if template.Description != nil && *template.Description != "" {
+		fmt.Fprintf(s.out, " - %s", *template.Description)
+	}
+	fmt.Fprintln(s.out)
+}
@@ -5,42 +5,97 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/jesses-code-adventures/work/internal/config"
 	"github.com/jesses-code-adventures/work/internal/database"
+	"github.com/jesses-code-adventures/work/internal/logging"
 	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
 	"github.com/shopspring/decimal"
 )
 
 type TimesheetService struct {
-	db  database.DB
-	cfg *config.Config
+	db       database.DB
+	cfg      *config.Config
+	term     *logging.Terminal
+	logLevel *slog.LevelVar
+	logger   *slog.Logger
+	analysis *analysisPool
+	receipts []ReceiptReader
+	events   *EventBus
+	out      io.Writer
 }
 
 func NewTimesheetService(db database.DB, cfg *config.Config) *TimesheetService {
-	return &TimesheetService{db: db, cfg: cfg}
+	term := logging.NewTerminal(os.Stderr)
+	logLevel := new(slog.LevelVar)
+	logger := logging.New(term, logLevel)
+	events := NewEventBus()
+	subscribeWebhooks(events, cfg.Webhooks, logger)
+	return &TimesheetService{
+		db:       db,
+		cfg:      cfg,
+		term:     term,
+		logLevel: logLevel,
+		logger:   logger,
+		analysis: newAnalysisPool(cfg.AnalysisConcurrency, cfg.AnalysisRateLimits, logger),
+		receipts: []ReceiptReader{filenameReceiptReader{}},
+		events:   events,
+		out:      stdout{},
+	}
 }
 
 func (s *TimesheetService) Config() *config.Config {
 	return s.cfg
 }
 
-func (s *TimesheetService) StartWork(ctx context.Context, clientName string, description *string) (*models.WorkSession, error) {
+// stdout writes to os.Stdout as it is when written to, rather than when the service was created
+type stdout struct{}
+
+func (stdout) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+// SetOutput sets where reports, tables and other output meant to be read are written, stdout by default.
+// Programs embedding the service can pass io.Discard to keep it quiet.
+func (s *TimesheetService) SetOutput(w io.Writer) {
+	s.out = w
+}
+
+// SetLogLevel sets the level for log output on stderr. Progress displays are hidden above info.
+func (s *TimesheetService) SetLogLevel(level slog.Level) {
+	s.logLevel.Set(level)
+}
+
+// Logger returns the structured logger for progress and diagnostic output
+func (s *TimesheetService) Logger() *slog.Logger {
+	return s.logger
+}
+
+func (s *TimesheetService) StartWork(ctx context.Context, clientName string, description *string, rate *decimal.Decimal, includesGst bool) (*models.WorkSession, error) {
 	activeSession, err := s.db.GetActiveSession(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for active session: %w", err)
 	}
 
 	if activeSession != nil {
-		fmt.Printf("Stopping current session for %s (started at %s)\n",
+		fmt.Fprintf(s.out, "Stopping current session for %s (started at %s%s)\n",
 			activeSession.ClientName,
-			activeSession.StartTime.Format("15:04:05"))
+			activeSession.StartTime.Format("15:04:05"),
+			otherMachineSuffix(activeSession))
 
-		_, err := s.db.StopWorkSession(ctx, activeSession.ID)
+		stoppedSession, err := s.db.StopWorkSession(ctx, activeSession.ID, time.Now())
 		if err != nil {
 			return nil, fmt.Errorf("failed to stop active session: %w", err)
 		}
+		stoppedSession.ClientName = activeSession.ClientName
+		s.publishSessionStopped(ctx, stoppedSession)
 	}
 
 	client, err := s.db.GetClientByName(ctx, clientName)
@@ -51,30 +106,35 @@ func (s *TimesheetService) StartWork(ctx context.Context, clientName string, des
 		return nil, fmt.Errorf("failed to get client: %w", err)
 	}
 
-	session, err := s.db.CreateWorkSession(ctx, client.ID, description, client.HourlyRate, false)
+	session, err := s.db.CreateWorkSession(ctx, client.ID, description, s.sessionRate(ctx, client, rate, time.Now()), includesGst)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create work session: %w", err)
 	}
 
 	session.ClientName = clientName
+	s.warnIfSessionRateBelowMinimum(client, session)
+	s.publishSessionStarted(ctx, session)
 	return session, nil
 }
 
-func (s *TimesheetService) StartWorkWithTime(ctx context.Context, clientName string, startTime time.Time, description *string) (*models.WorkSession, error) {
+func (s *TimesheetService) StartWorkWithTime(ctx context.Context, clientName string, startTime time.Time, description *string, rate *decimal.Decimal, includesGst bool) (*models.WorkSession, error) {
 	activeSession, err := s.db.GetActiveSession(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for active session: %w", err)
 	}
 
 	if activeSession != nil {
-		fmt.Printf("Stopping current session for %s (started at %s)\n",
+		fmt.Fprintf(s.out, "Stopping current session for %s (started at %s%s)\n",
 			activeSession.ClientName,
-			activeSession.StartTime.Format("15:04:05"))
+			activeSession.StartTime.Format("15:04:05"),
+			otherMachineSuffix(activeSession))
 
-		_, err := s.db.StopWorkSession(ctx, activeSession.ID)
+		stoppedSession, err := s.db.StopWorkSession(ctx, activeSession.ID, time.Now())
 		if err != nil {
 			return nil, fmt.Errorf("failed to stop active session: %w", err)
 		}
+		stoppedSession.ClientName = activeSession.ClientName
+		s.publishSessionStopped(ctx, stoppedSession)
 	}
 
 	client, err := s.db.GetClientByName(ctx, clientName)
@@ -85,15 +145,87 @@ func (s *TimesheetService) StartWorkWithTime(ctx context.Context, clientName str
 		return nil, fmt.Errorf("failed to get client: %w", err)
 	}
 
-	session, err := s.db.CreateWorkSessionWithStartTime(ctx, client.ID, startTime, description, client.HourlyRate, false)
+	session, err := s.db.CreateWorkSessionWithStartTime(ctx, client.ID, startTime, description, s.sessionRate(ctx, client, rate, startTime), includesGst)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create work session: %w", err)
 	}
 
 	session.ClientName = clientName
+	s.warnIfSessionRateBelowMinimum(client, session)
+	s.publishSessionStarted(ctx, session)
 	return session, nil
 }
 
+// LastStoppedSession returns the session a new one can start where it left off: the session sessionRef names, or
+// when it's empty, your session that ended latest today. It's an error while a session is active, as starting
+// another from an earlier stop would overlap it.
+func (s *TimesheetService) LastStoppedSession(ctx context.Context, sessionRef string) (*models.WorkSession, error) {
+	activeSession, err := s.db.GetActiveSession(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for active session: %w", err)
+	}
+	if activeSession != nil {
+		return nil, fmt.Errorf("the session for %s started at %s is still active, stop it before starting from the last stop",
+			activeSession.ClientName, activeSession.StartTime.Format("15:04"))
+	}
+
+	if sessionRef != "" {
+		sessionID, err := s.ResolveSessionID(ctx, sessionRef)
+		if err != nil {
+			return nil, err
+		}
+		session, err := s.db.GetSessionByID(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get session: %w", err)
+		}
+		if session.EndTime == nil {
+			return nil, fmt.Errorf("session '%s' hasn't stopped", sessionRef)
+		}
+		return session, nil
+	}
+
+	// Sessions are found by start time, so include yesterday's for any that ran past midnight
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	sessions, err := s.ListSessionsWithDateRange(ctx, today.AddDate(0, 0, -1).Format("2006-01-02"), today.Format("2006-01-02"), 1000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list today's sessions: %w", err)
+	}
+	var last *models.WorkSession
+	for _, session := range sessions {
+		if session.EndTime == nil || session.UserID != nil || session.EndTime.Before(today) || session.EndTime.After(now) {
+			continue
+		}
+		if last == nil || session.EndTime.After(*last.EndTime) {
+			last = session
+		}
+	}
+	if last == nil {
+		return nil, fmt.Errorf("no session has stopped today, give a start time with --from instead")
+	}
+	return last, nil
+}
+
+// SetSessionRate changes the rate a session is billed at, such as when it was started without one
+func (s *TimesheetService) SetSessionRate(ctx context.Context, sessionID string, rate decimal.Decimal, includesGst bool) (*models.WorkSession, error) {
+	if rate.IsNegative() {
+		return nil, fmt.Errorf("rate can't be negative")
+	}
+	session, err := s.db.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	updated, err := s.db.UpdateSessionRate(ctx, sessionID, rate, includesGst, false)
+	if err != nil {
+		return nil, err
+	}
+	updated.ClientName = session.ClientName
+	if client, err := s.db.GetClientByID(ctx, session.ClientID); err == nil {
+		s.warnIfSessionRateBelowMinimum(client, updated)
+	}
+	return updated, nil
+}
+
 func (s *TimesheetService) CreateSessionWithTimes(ctx context.Context, clientName string, startTime, endTime time.Time, description *string, includesGst bool) (*models.WorkSession, error) {
 	client, err := s.db.GetClientByName(ctx, clientName)
 	if err != nil {
@@ -103,21 +235,22 @@ func (s *TimesheetService) CreateSessionWithTimes(ctx context.Context, clientNam
 		return nil, fmt.Errorf("failed to get client: %w", err)
 	}
 
-	var hourlyRate decimal.Decimal
-	if client.HourlyRate.GreaterThan(decimal.Zero) {
-		hourlyRate = client.HourlyRate
-	}
-
-	session, err := s.db.CreateWorkSessionWithTimes(ctx, client.ID, startTime, endTime, description, hourlyRate, includesGst)
+	session, err := s.db.CreateWorkSessionWithTimes(ctx, client.ID, startTime, endTime, description, s.sessionRate(ctx, client, nil, startTime), includesGst)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create work session: %w", err)
 	}
 
 	session.ClientName = clientName
+	s.warnIfSessionRateBelowMinimum(client, session)
 	return session, nil
 }
 
 func (s *TimesheetService) StopWork(ctx context.Context) (*models.WorkSession, error) {
+	return s.StopWorkAt(ctx, time.Now())
+}
+
+// StopWorkAt stops the active session at endTime, for when it's stopped after the work finished
+func (s *TimesheetService) StopWorkAt(ctx context.Context, endTime time.Time) (*models.WorkSession, error) {
 	activeSession, err := s.db.GetActiveSession(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check for active session: %w", err)
@@ -127,12 +260,18 @@ func (s *TimesheetService) StopWork(ctx context.Context) (*models.WorkSession, e
 		return nil, fmt.Errorf("no active work session to stop")
 	}
 
-	stoppedSession, err := s.db.StopWorkSession(ctx, activeSession.ID)
+	if !endTime.After(activeSession.StartTime) {
+		return nil, fmt.Errorf("end time %s must be after the session's start time %s",
+			endTime.Format("2006-01-02 15:04:05"), activeSession.StartTime.Format("2006-01-02 15:04:05"))
+	}
+
+	stoppedSession, err := s.db.StopWorkSession(ctx, activeSession.ID, endTime)
 	if err != nil {
 		return nil, fmt.Errorf("failed to stop work session: %w", err)
 	}
 
 	stoppedSession.ClientName = activeSession.ClientName
+	s.publishSessionStopped(ctx, stoppedSession)
 	return stoppedSession, nil
 }
 
@@ -154,17 +293,49 @@ func (s *TimesheetService) ListSessionsByClient(ctx context.Context, clientName
 	return s.db.ListSessionsByClient(ctx, clientName, limit)
 }
 
-func (s *TimesheetService) DeleteAllSessions(ctx context.Context) error {
-	return s.db.DeleteAllSessions(ctx)
+// DeleteAllSessions moves every session to the trash, as DeleteSessionsByDateRange does for a date range
+func (s *TimesheetService) DeleteAllSessions(ctx context.Context, includeInvoiced bool) (int64, int64, error) {
+	return s.db.DeleteAllSessions(ctx, includeInvoiced)
 }
 
-func (s *TimesheetService) DeleteSessionsByDateRange(ctx context.Context, fromDate, toDate string) error {
+// DeleteSessionsByDateRange moves the sessions started between two dates to the trash, returning how many were
+// deleted and how many were skipped for being on an invoice. Sessions on an invoice are only deleted with
+// includeInvoiced, which flags their invoices as needing regeneration.
+func (s *TimesheetService) DeleteSessionsByDateRange(ctx context.Context, fromDate, toDate string, includeInvoiced bool) (int64, int64, error) {
 	from := s.formatDateForQuery(fromDate, true)
 	to := s.formatDateForQuery(toDate, false)
-	return s.db.DeleteSessionsByDateRange(ctx, from, to)
+	return s.db.DeleteSessionsByDateRange(ctx, from, to, includeInvoiced)
+}
+
+// invoicedSessionsError explains how to change sessions that are on an invoice anyway
+func invoicedSessionsError(err error) error {
+	if errors.Is(err, database.ErrSessionInvoiced) {
+		return fmt.Errorf("%w, use --force to change them anyway and then regenerate the invoice", err)
+	}
+	return err
+}
+
+// DisplayInvoicesNeedingRegeneration lists the invoices whose sessions have changed since they were issued, with
+// the command to regenerate each
+func (s *TimesheetService) DisplayInvoicesNeedingRegeneration(ctx context.Context) error {
+	invoices, err := s.db.ListInvoices(ctx, math.MaxInt32)
+	if err != nil {
+		return fmt.Errorf("failed to list invoices: %w", err)
+	}
+	for _, invoice := range invoices {
+		if invoice.NeedsRegeneration {
+			fmt.Fprintf(s.out, "Invoice %s no longer matches its sessions, regenerate it with: %s\n", invoice.InvoiceNumber, regenerateInvoiceCommand(invoice))
+		}
+	}
+	return nil
 }
 
 func (s *TimesheetService) CreateClient(ctx context.Context, name string, hourlyRate decimal.Decimal, retainerAmount *decimal.Decimal, retainerHours *float64, retainerBasis, dir *string) (*models.Client, error) {
+	if retainerBasis != nil {
+		if err := ValidateRetainerBasis(*retainerBasis); err != nil {
+			return nil, err
+		}
+	}
 	existing, err := s.db.GetClientByName(ctx, name)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to check for existing client: %w", err)
@@ -173,7 +344,12 @@ func (s *TimesheetService) CreateClient(ctx context.Context, name string, hourly
 	if existing != nil {
 		return nil, fmt.Errorf("client '%s' already exists", name)
 	}
-	return s.db.CreateClient(ctx, name, hourlyRate, retainerAmount, retainerHours, retainerBasis, dir)
+	client, err := s.db.CreateClient(ctx, name, hourlyRate, retainerAmount, retainerHours, retainerBasis, dir)
+	if err != nil {
+		return nil, err
+	}
+	s.warnIfClientRateBelowMinimum(client)
+	return client, nil
 }
 
 func (s *TimesheetService) ListClients(ctx context.Context) ([]*models.Client, error) {
@@ -193,6 +369,49 @@ func (s *TimesheetService) GetClientByID(ctx context.Context, ID string) (*model
 }
 
 func (s *TimesheetService) UpdateClient(ctx context.Context, clientName string, updates *database.ClientUpdateDetails) (*models.Client, error) {
+	if updates.RetainerBasis != nil {
+		if err := ValidateRetainerBasis(*updates.RetainerBasis); err != nil {
+			return nil, err
+		}
+	}
+	if updates.TaxTreatment != nil {
+		if err := ValidateTaxTreatment(*updates.TaxTreatment); err != nil {
+			return nil, err
+		}
+	}
+	// Logos are stored as absolute paths so invoices find them from any directory, and an empty one is removed
+	if updates.LogoPath != nil && *updates.LogoPath != "" {
+		logo, err := readLogo(*updates.LogoPath)
+		if err != nil {
+			return nil, err
+		}
+		updates.LogoPath = &logo.Path
+	}
+	if updates.BrandColour != nil && *updates.BrandColour != "" {
+		colour, err := config.ParseColour(*updates.BrandColour)
+		if err != nil {
+			return nil, fmt.Errorf("invalid brand colour: %w", err)
+		}
+		updates.BrandColour = &colour
+	}
+	if updates.AnalysisMaxCommits != nil && *updates.AnalysisMaxCommits < -1 {
+		return nil, fmt.Errorf("analysis max commits must be a whole number (0 for no limit)")
+	}
+	if updates.AnalysisIgnore != nil {
+		ignore := strings.Join(config.ParseIgnorePatterns(*updates.AnalysisIgnore), ",")
+		updates.AnalysisIgnore = &ignore
+	}
+	if updates.AnalysisDetail != nil && *updates.AnalysisDetail != "" {
+		detail, err := config.ParseGitAnalysisDetail(*updates.AnalysisDetail)
+		if err != nil {
+			return nil, fmt.Errorf("invalid analysis detail: %w", err)
+		}
+		updates.AnalysisDetail = &detail
+	}
+	if updates.AnalysisPrompt != nil {
+		prompt := strings.TrimSpace(*updates.AnalysisPrompt)
+		updates.AnalysisPrompt = &prompt
+	}
 	c, err := s.db.GetClientByName(ctx, clientName)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -200,116 +419,285 @@ func (s *TimesheetService) UpdateClient(ctx context.Context, clientName string,
 		}
 		return nil, fmt.Errorf("failed to get client: %w", err)
 	}
-	return s.db.UpdateClient(ctx, c.ID, updates)
+	client, err := s.db.UpdateClient(ctx, c.ID, updates)
+	if err != nil {
+		return nil, err
+	}
+	if updates.HourlyRate != nil && !client.HourlyRate.Equal(c.HourlyRate) {
+		if err := s.recordClientRateChange(ctx, client, c.HourlyRate); err != nil {
+			return nil, err
+		}
+	}
+	s.warnIfClientRateBelowMinimum(client)
+	return client, nil
 }
 
 func (s *TimesheetService) DisplayClient(ctx context.Context, client *models.Client) {
-	fmt.Printf("Client: %s\n", client.Name)
+	fmt.Fprintf(s.out, "Client: %s\n", client.Name)
 	if !client.HourlyRate.Equal(decimal.Zero) {
-		fmt.Printf("Rate: %s\n", s.FormatBillableAmount(client.HourlyRate))
+		fmt.Fprintf(s.out, "Rate: %s\n", s.FormatClientBillableAmount(client, client.HourlyRate))
 	}
 	if client.CompanyName != nil {
-		fmt.Printf("Company: %s\n", *client.CompanyName)
+		fmt.Fprintf(s.out, "Company: %s\n", *client.CompanyName)
 	}
 	if client.ContactName != nil {
-		fmt.Printf("Contact: %s\n", *client.ContactName)
+		fmt.Fprintf(s.out, "Contact: %s\n", *client.ContactName)
 	}
 	if client.Email != nil {
-		fmt.Printf("Email: %s\n", *client.Email)
+		fmt.Fprintf(s.out, "Email: %s\n", *client.Email)
 	}
 	if client.Phone != nil {
-		fmt.Printf("Phone: %s\n", *client.Phone)
+		fmt.Fprintf(s.out, "Phone: %s\n", *client.Phone)
 	}
 	if client.AddressLine1 != nil {
-		fmt.Printf("Address: %s", *client.AddressLine1)
+		fmt.Fprintf(s.out, "Address: %s", *client.AddressLine1)
 		if client.AddressLine2 != nil {
-			fmt.Printf(", %s", *client.AddressLine2)
+			fmt.Fprintf(s.out, ", %s", *client.AddressLine2)
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(s.out, "\n")
 	}
 	if client.City != nil || client.State != nil || client.PostalCode != nil {
-		fmt.Printf("Location: ")
+		fmt.Fprintf(s.out, "Location: ")
 		if client.City != nil {
-			fmt.Printf("%s", *client.City)
+			fmt.Fprintf(s.out, "%s", *client.City)
 		}
 		if client.State != nil {
-			fmt.Printf(", %s", *client.State)
+			fmt.Fprintf(s.out, ", %s", *client.State)
 		}
 		if client.PostalCode != nil {
-			fmt.Printf(" %s", *client.PostalCode)
+			fmt.Fprintf(s.out, " %s", *client.PostalCode)
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(s.out, "\n")
 	}
 	if client.Country != nil {
-		fmt.Printf("Country: %s\n", *client.Country)
+		fmt.Fprintf(s.out, "Country: %s\n", *client.Country)
 	}
 	if client.Abn != nil {
-		fmt.Printf("ABN: %s\n", *client.Abn)
+		fmt.Fprintf(s.out, "ABN: %s\n", *client.Abn)
 	}
 	if client.RetainerAmount != nil && client.RetainerHours != nil && client.RetainerBasis != nil {
-		fmt.Printf("Retainer: $%s for %.1f hours per %s\n", client.RetainerAmount.StringFixed(2), *client.RetainerHours, *client.RetainerBasis)
+		fmt.Fprintf(s.out, "Retainer: %s for %.1f hours per %s\n", s.FormatClientMoney(client, *client.RetainerAmount), *client.RetainerHours, *client.RetainerBasis)
+	}
+	if client.RetainerStart != nil {
+		fmt.Fprintf(s.out, "Retainer start: %s\n", client.RetainerStart.Format("2006-01-02"))
+	}
+	if hasEarlyDiscount(client) {
+		fmt.Fprintf(s.out, "Early payment discount: %s\n", earlyDiscountTerms(client))
+	}
+	if client.PaymentTerms != nil {
+		fmt.Fprintf(s.out, "Payment terms: %s\n", *client.PaymentTerms)
+	}
+	if client.InvoiceNotes != nil {
+		fmt.Fprintf(s.out, "Invoice notes: %s\n", *client.InvoiceNotes)
+	}
+	if client.PoNumber != nil {
+		fmt.Fprintf(s.out, "PO number: %s\n", *client.PoNumber)
+	}
+	if client.ProjectCode != nil {
+		fmt.Fprintf(s.out, "Project code: %s\n", *client.ProjectCode)
+	}
+	if client.InvoiceRounding != nil {
+		fmt.Fprintf(s.out, "Invoice rounding: to the nearest %s\n", s.FormatClientMoney(client, *client.InvoiceRounding))
+	}
+	if client.TaxTreatment != "" && client.TaxTreatment != models.TaxTreatmentTaxable {
+		fmt.Fprintf(s.out, "Tax treatment: %s\n", client.TaxTreatment)
+	}
+	if client.LogoPath != nil {
+		fmt.Fprintf(s.out, "Logo: %s\n", *client.LogoPath)
+	}
+	if client.BrandColour != nil {
+		fmt.Fprintf(s.out, "Brand colour: %s\n", *client.BrandColour)
+	}
+	if client.AnalysisMaxCommits != nil || client.AnalysisIgnore != nil || client.AnalysisDetail != nil {
+		fmt.Fprintf(s.out, "Git analysis: %s\n", s.gitAnalysisDepth(client))
+	}
+	if client.AnalysisPrompt != nil {
+		fmt.Fprintf(s.out, "Analysis prompt: %s\n", *client.AnalysisPrompt)
+	}
+	if client.Currency != nil || client.Locale != nil {
+		m := s.clientMoney(client)
+		fmt.Fprintf(s.out, "Currency: %s (%s)\n", m.Currency, m.Locale)
+	}
+	if client.Source != nil {
+		fmt.Fprintf(s.out, "Source: %s\n", *client.Source)
+	}
+	if client.WorkLog != nil {
+		fmt.Fprintf(s.out, "Work log: %s\n", *client.WorkLog)
 	}
 }
 
+// CalculateDuration is how long a session has been worked for, less any breaks taken out of it, rounded to
+// BillingPrecision
 func (s *TimesheetService) CalculateDuration(session *models.WorkSession) time.Duration {
+	breaks := time.Duration(session.BreakSeconds) * time.Second
 	if session.EndTime == nil {
-		return time.Since(session.StartTime)
+		return billedDuration(time.Since(session.StartTime) - breaks)
 	}
-	return session.EndTime.Sub(session.StartTime)
+	return billedDuration(session.EndTime.Sub(session.StartTime) - breaks)
 }
 
+// FormatDuration shows a duration in the status format, for messages about a single session
 func (s *TimesheetService) FormatDuration(d time.Duration) string {
+	return s.FormatDurationFor(config.DurationContextStatus, d)
+}
+
+// FormatDurationFor shows a duration in the format configured for one of config.DurationContexts, with decimal
+// hours rounded to DURATION_PRECISION
+func (s *TimesheetService) FormatDurationFor(durationContext string, d time.Duration) string {
+	format := s.cfg.DurationFormats[durationContext]
+	if format == "" {
+		format = config.DurationHoursMinutes
+	}
+	return formatDuration(format, s.cfg.DurationPrecision, d)
+}
+
+// formatDuration shows a duration in one of the config duration formats, to the minute or, for decimal hours,
+// to precision decimal places without trailing zeros
+func formatDuration(format string, precision int, d time.Duration) string {
 	hours := d / time.Hour
 	minutes := (d % time.Hour) / time.Minute
-	return fmt.Sprintf("%dh %dm", hours, minutes)
+	switch format {
+	case config.DurationDecimal:
+		return decimal.NewFromFloat(d.Hours()).Round(int32(precision)).String() + "h"
+	case config.DurationClock:
+		return fmt.Sprintf("%d:%02d", hours, minutes)
+	case config.DurationVerbose:
+		plural := func(n time.Duration, unit string) string {
+			if n == 1 {
+				return fmt.Sprintf("%d %s", n, unit)
+			}
+			return fmt.Sprintf("%d %ss", n, unit)
+		}
+		if hours == 0 {
+			return plural(minutes, "minute")
+		}
+		if minutes == 0 {
+			return plural(hours, "hour")
+		}
+		return plural(hours, "hour") + " " + plural(minutes, "minute")
+	default:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
 }
 
+// CalculateBillableAmount is what a session is billed at its rate, for its duration rounded to BillingPrecision
 func (s *TimesheetService) CalculateBillableAmount(session *models.WorkSession) decimal.Decimal {
 	if session.HourlyRate == nil || session.HourlyRate.LessThanOrEqual(decimal.Zero) {
 		return decimal.Zero
 	}
 
-	duration := s.CalculateDuration(session)
-	hours := duration.Hours()
-	return decimal.NewFromFloat(hours).Mul(*session.HourlyRate)
+	return billedAmount(s.CalculateDuration(session), *session.HourlyRate)
 }
 
 func (s *TimesheetService) FormatBillableAmount(amount decimal.Decimal) string {
+	return s.formatBillableAmount(s.homeMoney(), amount, s.cfg.GSTRegistered)
+}
+
+// FormatClientBillableAmount formats an amount in the client's currency and locale, with GST when it's charged
+// to them
+func (s *TimesheetService) FormatClientBillableAmount(client *models.Client, amount decimal.Decimal) string {
+	return s.formatBillableAmount(s.clientMoney(client), amount, s.chargesGST(client))
+}
+
+func (s *TimesheetService) formatBillableAmount(m money.Formatter, amount decimal.Decimal, chargeGST bool) string {
 	if amount.LessThanOrEqual(decimal.Zero) {
-		return "$0.00"
+		return m.Format(decimal.Zero)
 	}
-	return s.FormatBillableAmountWithGST(amount)
+	return formatBillableAmountWithGST(m, amount, chargeGST)
 }
 
 func (s *TimesheetService) FormatSessionBillableAmount(session *models.WorkSession) string {
-	amount := s.CalculateBillableAmount(session)
+	client, err := s.db.GetClientByName(context.Background(), session.ClientName)
+	if err != nil {
+		client = nil
+	}
+	return s.formatSessionAmount(s.clientMoney(client), s.chargesGST(client), session, s.CalculateBillableAmount(session))
+}
+
+// formatSessionAmount formats an amount billed for a session, with GST shown the way the session is billed
+func (s *TimesheetService) formatSessionAmount(m money.Formatter, chargeGST bool, session *models.WorkSession, amount decimal.Decimal) string {
 	if amount.LessThanOrEqual(decimal.Zero) {
-		return "$0.00"
+		return m.Format(decimal.Zero)
 	}
 
 	if session.IncludesGst {
 		// Session amount already includes GST
-		if s.cfg.GSTRegistered {
-			return fmt.Sprintf("$%s (inc. GST)", amount.StringFixed(2))
+		if chargeGST {
+			return fmt.Sprintf("%s (inc. GST)", m.Format(amount))
 		}
-		return fmt.Sprintf("$%s", amount.StringFixed(2))
+		return m.Format(amount)
 	} else {
 		// Session amount excludes GST, show both amounts
-		return s.FormatBillableAmountWithGST(amount)
+		return formatBillableAmountWithGST(m, amount, chargeGST)
 	}
 }
 
 func (s *TimesheetService) FormatBillableAmountWithGST(amount decimal.Decimal) string {
+	return formatBillableAmountWithGST(s.homeMoney(), amount, s.cfg.GSTRegistered)
+}
+
+func formatBillableAmountWithGST(m money.Formatter, amount decimal.Decimal, chargeGST bool) string {
 	if amount.LessThanOrEqual(decimal.Zero) {
-		return "$0.00"
+		return m.Format(decimal.Zero)
 	}
 
-	if s.cfg.GSTRegistered {
+	if chargeGST {
 		total := amount.Mul(decimal.NewFromFloat(1.1)) // Add 10% GST
-		return fmt.Sprintf("$%s ($%s inc. GST)", amount.StringFixed(2), total.StringFixed(2))
+		return fmt.Sprintf("%s (%s inc. GST)", m.Format(amount), m.Format(total))
+	}
+
+	return m.Format(amount)
+}
+
+// FormatClientRate formats the client's hourly rate in their currency, e.g. $150.00/hr
+func (s *TimesheetService) FormatClientRate(client *models.Client) string {
+	return fmt.Sprintf("%s/hr", s.clientMoney(client).Format(client.HourlyRate))
+}
+
+// FormatClientMoney formats an amount in the client's currency and locale, without GST
+func (s *TimesheetService) FormatClientMoney(client *models.Client, amount decimal.Decimal) string {
+	return s.clientMoney(client).Format(amount)
+}
+
+// homeMoney returns the formatter for the configured billing currency and locale
+func (s *TimesheetService) homeMoney() money.Formatter {
+	return money.New(s.cfg.BillingCurrency, s.cfg.BillingLocale)
+}
+
+// clientMoney returns the formatter for a client, honouring any currency or locale override
+func (s *TimesheetService) clientMoney(client *models.Client) money.Formatter {
+	m := s.homeMoney()
+	if client == nil {
+		return m
+	}
+	if client.Currency != nil && *client.Currency != "" {
+		m = money.New(*client.Currency, m.Locale)
+	}
+	if client.Locale != nil && *client.Locale != "" {
+		m = money.New(m.Currency, *client.Locale)
+	}
+	return m
+}
+
+// clientMoneyByName returns the formatter for the named client, falling back to the billing defaults
+func (s *TimesheetService) clientMoneyByName(clientName string) money.Formatter {
+	client, err := s.db.GetClientByName(context.Background(), clientName)
+	if err != nil {
+		return s.homeMoney()
 	}
+	return s.clientMoney(client)
+}
 
-	return fmt.Sprintf("$%s", amount.StringFixed(2))
+// clientMoneyByID returns the formatter for the client with the given ID, falling back to the billing defaults
+func (s *TimesheetService) clientMoneyByID(clientID *string) money.Formatter {
+	if clientID == nil {
+		return s.homeMoney()
+	}
+	client, err := s.db.GetClientByID(context.Background(), *clientID)
+	if err != nil {
+		return s.homeMoney()
+	}
+	return s.clientMoney(client)
 }
 
 func (s *TimesheetService) formatDateForQuery(dateStr string, isStart bool) string {
@@ -337,34 +725,12 @@ func (s *TimesheetService) GetSessionByID(ctx context.Context, sessionID string)
 }
 
 func (s *TimesheetService) UpdateSessionDescription(ctx context.Context, sessionID string, description string, fullWorkSummary *string) (*models.WorkSession, error) {
-	return s.db.UpdateSessionDescription(ctx, sessionID, description, fullWorkSummary)
-}
-
-func (s *TimesheetService) AddSessionNote(ctx context.Context, sessionID string, note string) (*models.WorkSession, error) {
-	session, err := s.db.GetSessionByID(ctx, sessionID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get session: %w", err)
-	}
-
-	var currentNotes string
-	if session.OutsideGit != nil {
-		currentNotes = *session.OutsideGit
-	}
-
-	newNote := fmt.Sprintf("- %s", note)
-	var updatedNotes string
-	if currentNotes == "" {
-		updatedNotes = newNote
-	} else {
-		updatedNotes = fmt.Sprintf("%s\n%s", currentNotes, newNote)
-	}
-
-	return s.db.UpdateSessionOutsideGit(ctx, sessionID, updatedNotes)
+	return s.db.UpdateSessionDescription(ctx, sessionID, description, fullWorkSummary, false)
 }
 
 // Expense operations
-func (s *TimesheetService) CreateExpense(ctx context.Context, amount decimal.Decimal, expenseDate time.Time, reference *string, clientID *string, invoiceID *string, description *string) (*models.Expense, error) {
-	return s.db.CreateExpense(ctx, amount, expenseDate, reference, clientID, invoiceID, description)
+func (s *TimesheetService) CreateExpense(ctx context.Context, amount decimal.Decimal, expenseDate time.Time, reference *string, clientID *string, invoiceID *string, description *string, category *string, markupPercent *decimal.Decimal, billable bool, gstAmount *decimal.Decimal) (*models.Expense, error) {
+	return s.db.CreateExpense(ctx, amount, expenseDate, reference, clientID, invoiceID, description, category, markupPercent, billable, gstAmount)
 }
 
 func (s *TimesheetService) GetExpenseByID(ctx context.Context, expenseID string) (*models.Expense, error) {
@@ -395,7 +761,7 @@ func (s *TimesheetService) ListExpensesByClientAndDateRange(ctx context.Context,
 	return s.db.ListExpensesByClientAndDateRange(ctx, client.ID, startDate, endDate)
 }
 
-func (s *TimesheetService) UpdateExpense(ctx context.Context, expenseID string, amount *decimal.Decimal, expenseDate *time.Time, reference *string, clientName *string, invoiceID *string, description *string) (*models.Expense, error) {
+func (s *TimesheetService) UpdateExpense(ctx context.Context, expenseID string, amount *decimal.Decimal, expenseDate *time.Time, reference *string, clientName *string, invoiceID *string, description *string, category *string, markupPercent *decimal.Decimal, billable *bool, gstAmount *decimal.Decimal) (*models.Expense, error) {
 	var clientID *string
 	if clientName != nil && *clientName != "" {
 		client, err := s.db.GetClientByName(ctx, *clientName)
@@ -404,11 +770,7 @@ func (s *TimesheetService) UpdateExpense(ctx context.Context, expenseID string,
 		}
 		clientID = &client.ID
 	}
-	return s.db.UpdateExpense(ctx, expenseID, amount, expenseDate, reference, clientID, invoiceID, description)
-}
-
-func (s *TimesheetService) DeleteExpense(ctx context.Context, expenseID string) error {
-	return s.db.DeleteExpense(ctx, expenseID)
+	return s.db.UpdateExpense(ctx, expenseID, amount, expenseDate, reference, clientID, invoiceID, description, category, markupPercent, billable, gstAmount)
 }
 
 func (s *TimesheetService) GetExpensesByInvoiceID(ctx context.Context, invoiceID string) ([]*models.Expense, error) {
@@ -439,29 +801,58 @@ func (s *TimesheetService) ClearExpenseInvoiceIDs(ctx context.Context, invoiceID
 	return s.db.ClearExpenseInvoiceIDs(ctx, invoiceID)
 }
 
+// FormatExpenseAmount formats an expense in its client's currency and locale
+func (s *TimesheetService) FormatExpenseAmount(expense *models.Expense) string {
+	return s.clientMoneyByID(expense.ClientID).Format(expense.Amount)
+}
+
 func (s *TimesheetService) DisplayExpense(ctx context.Context, expense *models.Expense) {
-	fmt.Printf("Expense: %s\n", expense.ID)
-	fmt.Printf("Amount: %s\n", fmt.Sprintf("$%s", expense.Amount.StringFixed(2)))
-	fmt.Printf("Date: %s\n", expense.ExpenseDate.Format("2006-01-02"))
+	fmt.Fprintf(s.out, "Expense: %s\n", expense.ID)
+	fmt.Fprintf(s.out, "Amount: %s\n", s.FormatExpenseAmount(expense))
+	if expense.GstAmount != nil && !expense.GstAmount.IsZero() {
+		fmt.Fprintf(s.out, "GST included: %s\n", s.clientMoneyByID(expense.ClientID).Format(*expense.GstAmount))
+	}
+	if expense.Draft {
+		fmt.Fprintln(s.out, "Status: draft, confirm it with 'work expenses review'")
+	}
+	if !expense.Billable {
+		fmt.Fprintln(s.out, "Billable: no")
+	} else if expense.MarkupPercent != nil && !expense.MarkupPercent.IsZero() {
+		fmt.Fprintf(s.out, "Markup: %s%%\n", expense.MarkupPercent.String())
+		fmt.Fprintf(s.out, "Billed: %s\n", s.clientMoneyByID(expense.ClientID).Format(ExpenseBilledAmount(expense)))
+	}
+	fmt.Fprintf(s.out, "Date: %s\n", expense.ExpenseDate.Format("2006-01-02"))
+
+	if mileage := s.FormatMileage(expense); mileage != "" {
+		fmt.Fprintf(s.out, "Mileage: %s\n", mileage)
+	}
 
 	if expense.Reference != nil && *expense.Reference != "" {
-		fmt.Printf("Reference: %s\n", *expense.Reference)
+		fmt.Fprintf(s.out, "Reference: %s\n", *expense.Reference)
 	}
 
 	if expense.Description != nil && *expense.Description != "" {
-		fmt.Printf("Description: %s\n", *expense.Description)
+		fmt.Fprintf(s.out, "Description: %s\n", *expense.Description)
+	}
+
+	if expense.Category != nil && *expense.Category != "" {
+		fmt.Fprintf(s.out, "Category: %s\n", *expense.Category)
+	}
+
+	if expense.ReceiptPath != nil {
+		fmt.Fprintf(s.out, "Receipt: %s\n", *expense.ReceiptPath)
 	}
 
 	if expense.ClientID != nil {
 		client, err := s.db.GetClientByID(ctx, *expense.ClientID)
 		if err == nil {
-			fmt.Printf("Client: %s\n", client.Name)
+			fmt.Fprintf(s.out, "Client: %s\n", client.Name)
 		}
 	}
 
 	if expense.InvoiceID != nil {
-		fmt.Printf("Invoice: %s\n", *expense.InvoiceID)
+		fmt.Fprintf(s.out, "Invoice: %s\n", *expense.InvoiceID)
 	}
 
-	fmt.Printf("Created: %s\n", expense.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(s.out, "Created: %s\n", expense.CreatedAt.Format("2006-01-02 15:04:05"))
 }
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/utils"
+)
+
+// DefaultTrashPurgeAge is how long deleted sessions and expenses stay in the trash before 'work trash purge'
+// deletes them for good
+const DefaultTrashPurgeAge = "30d"
+
+// ParseTrashAge reads how long something has been in the trash, as days such as 30d, weeks such as 2w, or a Go
+// duration such as 36h
+func ParseTrashAge(age string) (time.Duration, error) {
+	age = strings.TrimSpace(age)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if count, ok := strings.CutSuffix(age, suffix); ok {
+			n, err := strconv.Atoi(count)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("--older-than must be a number of days (30d), weeks (2w) or a duration (36h), got %q", age)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(age)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("--older-than must be a number of days (30d), weeks (2w) or a duration (36h), got %q", age)
+	}
+	return d, nil
+}
+
+// DisplayTrash lists the deleted sessions and expenses waiting in the trash, most recently deleted first
+func (s *TimesheetService) DisplayTrash(ctx context.Context) error {
+	sessions, err := s.db.ListTrashedSessions(ctx)
+	if err != nil {
+		return err
+	}
+	expenses, err := s.db.ListTrashedExpenses(ctx)
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 && len(expenses) == 0 {
+		fmt.Fprintln(s.out, "The trash is empty.")
+		return nil
+	}
+
+	if len(sessions) > 0 {
+		fmt.Fprintf(s.out, "Sessions (%d):\n", len(sessions))
+		short := ShortSessionIDs(sessions)
+		for _, session := range sessions {
+			fmt.Fprintf(s.out, "  deleted %s  %s\n", session.DeletedAt.Local().Format("2006-01-02"), s.SessionPickerLine(session, short[session.ID]))
+		}
+	}
+	if len(expenses) > 0 {
+		if len(sessions) > 0 {
+			fmt.Fprintln(s.out)
+		}
+		fmt.Fprintf(s.out, "Expenses (%d):\n", len(expenses))
+		for _, expense := range expenses {
+			fmt.Fprintf(s.out, "  deleted %s  %s  %s %12s  %s\n", expense.DeletedAt.Local().Format("2006-01-02"), expense.ID,
+				expense.ExpenseDate.Format("2006-01-02"), s.FormatExpenseAmount(expense), truncateString(utils.FromPtr(expense.Description), 50))
+		}
+	}
+	fmt.Fprintf(s.out, "\nRestore one with 'work trash restore <id>', or delete them for good with 'work trash purge'\n")
+	return nil
+}
+
+// RestoreFromTrash takes a deleted session or expense out of the trash by its ID, or the start of it, returning
+// a description of what was restored. An unfinished session can't be restored while another session is active.
+func (s *TimesheetService) RestoreFromTrash(ctx context.Context, ref string) (string, error) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if ref == "" {
+		return "", fmt.Errorf("an ID is required, see 'work trash list'")
+	}
+	sessions, err := s.db.ListTrashedSessions(ctx)
+	if err != nil {
+		return "", err
+	}
+	expenses, err := s.db.ListTrashedExpenses(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var session *models.WorkSession
+	var expense *models.Expense
+	var matches []string
+	for _, trashed := range sessions {
+		if strings.HasPrefix(trashed.ID, ref) {
+			session = trashed
+			matches = append(matches, trashed.ID)
+		}
+	}
+	for _, trashed := range expenses {
+		if strings.HasPrefix(trashed.ID, ref) {
+			expense = trashed
+			matches = append(matches, trashed.ID)
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return "", fmt.Errorf("nothing in the trash has the ID '%s', see 'work trash list'", ref)
+	case len(matches) > 1:
+		return "", fmt.Errorf("'%s' is the start of more than one ID in the trash (%s), use more of it", ref, strings.Join(matches, ", "))
+	}
+
+	if expense != nil {
+		restored, err := s.db.RestoreExpense(ctx, expense.ID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("expense %s of %s on %s", restored.ID, s.FormatExpenseAmount(restored), restored.ExpenseDate.Format("2006-01-02")), nil
+	}
+
+	if session.EndTime == nil {
+		active, err := s.db.GetActiveSession(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to check for active session: %w", err)
+		}
+		if active != nil {
+			return "", fmt.Errorf("session %s was never stopped and %s's session is active, stop it first", session.ID, active.ClientName)
+		}
+	}
+	if _, err := s.db.RestoreSession(ctx, session.ID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("session %s for %s on %s (%s)", session.ID, session.ClientName, session.StartTime.Format("2006-01-02 15:04"),
+		s.FormatDurationFor(config.DurationContextList, s.CalculateDuration(session))), nil
+}
+
+// PurgeTrash deletes the sessions and expenses that have been in the trash for longer than olderThan for good,
+// returning how many of each were deleted
+func (s *TimesheetService) PurgeTrash(ctx context.Context, olderThan time.Duration) (int64, int64, error) {
+	return s.db.PurgeTrash(ctx, time.Now().Add(-olderThan))
+}
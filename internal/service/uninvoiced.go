@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// clientUninvoiced is a client's completed sessions that haven't been invoiced, valued at their session rates
+type clientUninvoiced struct {
+	name     string
+	m        money.Formatter
+	sessions int
+	worked   time.Duration
+	value    decimal.Decimal
+	oldest   time.Time
+	// stale is the value of sessions started before the warning cutoff
+	stale decimal.Decimal
+}
+
+// summariseUninvoiced totals uninvoiced sessions by client name, with the value of those started before cutoff
+// counted as stale. moneyFor gives the currency of each session's client.
+func (s *TimesheetService) summariseUninvoiced(sessions []*models.WorkSession, cutoff time.Time, moneyFor func(*models.WorkSession) money.Formatter) []*clientUninvoiced {
+	byClient := make(map[string]*clientUninvoiced)
+	for _, session := range sessions {
+		summary, ok := byClient[session.ClientName]
+		if !ok {
+			summary = &clientUninvoiced{name: session.ClientName, m: moneyFor(session), oldest: session.StartTime}
+			byClient[session.ClientName] = summary
+		}
+		amount := s.CalculateBillableAmount(session)
+		summary.sessions++
+		summary.worked += s.CalculateDuration(session)
+		summary.value = summary.value.Add(amount)
+		if session.StartTime.Before(summary.oldest) {
+			summary.oldest = session.StartTime
+		}
+		if session.StartTime.Before(cutoff) {
+			summary.stale = summary.stale.Add(amount)
+		}
+	}
+
+	clients := make([]*clientUninvoiced, 0, len(byClient))
+	for _, summary := range byClient {
+		clients = append(clients, summary)
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].name < clients[j].name })
+	return clients
+}
+
+// ShowUninvoicedWork lists each client's completed sessions that haven't been invoiced as of today: how many,
+// the hours worked, their value at session rates before retainers and GST, and the date of the oldest. Clients
+// with at least threshold of work older than olderThanDays are warned about, so nothing is left unbilled.
+func (s *TimesheetService) ShowUninvoicedWork(ctx context.Context, clientName string, olderThanDays int, threshold decimal.Decimal) error {
+	if olderThanDays < 0 {
+		return fmt.Errorf("--older-than can't be negative")
+	}
+	asOf := time.Now()
+	var sessions []*models.WorkSession
+	var err error
+	if clientName != "" {
+		sessions, err = s.db.GetSessionsForPeriodWithoutInvoiceByClient(ctx, time.Time{}, asOf, clientName)
+	} else {
+		sessions, err = s.db.GetSessionsForPeriodWithoutInvoice(ctx, time.Time{}, asOf)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get uninvoiced sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		fmt.Fprintln(s.out, "No uninvoiced work, every completed session is invoiced.")
+		return nil
+	}
+
+	cutoff := dateOnly(asOf).AddDate(0, 0, -olderThanDays)
+	clients := s.summariseUninvoiced(sessions, cutoff, func(session *models.WorkSession) money.Formatter {
+		return s.clientMoneyByID(&session.ClientID)
+	})
+
+	fmt.Fprintf(s.out, "Uninvoiced work on %s (at session rates, before retainers and GST)\n\n", asOf.Format("2006-01-02"))
+	fmt.Fprintf(s.out, "%-16s %8s %10s %14s  %s\n", "CLIENT", "SESSIONS", "HOURS", "VALUE", "OLDEST")
+	fmt.Fprintln(s.out, strings.Repeat("-", 72))
+
+	totals := make(map[string]decimal.Decimal)
+	formatters := make(map[string]money.Formatter)
+	for _, summary := range clients {
+		days := int(dateOnly(asOf).Sub(dateOnly(summary.oldest)).Hours() / 24)
+		fmt.Fprintf(s.out, "%-16s %8d %10s %14s  %s (%d days)\n", truncateString(summary.name, 16), summary.sessions,
+			s.FormatDurationFor(config.DurationContextReport, summary.worked), summary.m.Format(summary.value),
+			summary.oldest.Format("2006-01-02"), days)
+		totals[summary.m.Currency] = totals[summary.m.Currency].Add(summary.value)
+		formatters[summary.m.Currency] = summary.m
+	}
+
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	fmt.Fprintln(s.out, strings.Repeat("-", 72))
+	for _, currency := range currencies {
+		fmt.Fprintf(s.out, "%-37s %14s\n", "Total "+currency, formatters[currency].Format(totals[currency]))
+	}
+
+	var warned bool
+	for _, summary := range clients {
+		if !summary.stale.IsPositive() || summary.stale.LessThan(threshold) {
+			continue
+		}
+		if !warned {
+			fmt.Fprintln(s.out)
+			warned = true
+		}
+		fmt.Fprintf(s.out, "Warning: %s has %s uninvoiced older than %d days\n", summary.name, summary.m.Format(summary.stale), olderThanDays)
+	}
+	return nil
+}
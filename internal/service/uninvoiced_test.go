@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+func TestSummariseUninvoicedCountsOlderWorkAsStale(t *testing.T) {
+	s := NewTimesheetService(nil, &config.Config{})
+	cutoff := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	session := func(client string, start time.Time, rate string) *models.WorkSession {
+		session := newPrecisionSession(start, 2*time.Hour, 0, rate)
+		session.ClientName = client
+		return session
+	}
+	sessions := []*models.WorkSession{
+		session("globex", cutoff.AddDate(0, 0, 3), "150"),
+		session("acme", cutoff.AddDate(0, 0, 2), "100"),
+		session("acme", cutoff.AddDate(0, 0, -10), "100"),
+		session("acme", cutoff.AddDate(0, 0, -40), "100"),
+	}
+	aud := money.New("AUD", "en-AU")
+
+	clients := s.summariseUninvoiced(sessions, cutoff, func(*models.WorkSession) money.Formatter { return aud })
+	if len(clients) != 2 || clients[0].name != "acme" || clients[1].name != "globex" {
+		t.Fatalf("clients = %+v, want acme then globex", clients)
+	}
+	acme := clients[0]
+	if acme.sessions != 3 || acme.worked != 6*time.Hour || acme.value.String() != "600" || acme.stale.String() != "400" {
+		t.Errorf("acme = %d sessions, %s worked, %s value, %s stale, want 3, 6h, 600 and 400", acme.sessions, acme.worked, acme.value, acme.stale)
+	}
+	if !acme.oldest.Equal(cutoff.AddDate(0, 0, -40)) {
+		t.Errorf("acme oldest = %s, want %s", acme.oldest, cutoff.AddDate(0, 0, -40))
+	}
+	if !clients[1].stale.IsZero() {
+		t.Errorf("globex stale = %s, want nothing", clients[1].stale)
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/money"
+)
+
+// OwnerUser stands for the owner of the database in --user flags, whose sessions aren't attributed to a user
+const OwnerUser = "me"
+
+// CreateUser adds someone who works on sessions alongside the owner, such as a subcontractor, with what an
+// hour of their time costs
+func (s *TimesheetService) CreateUser(ctx context.Context, name string, costRate *decimal.Decimal) (*models.User, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("user name is required")
+	}
+	if strings.EqualFold(name, OwnerUser) {
+		return nil, fmt.Errorf("'%s' stands for your own sessions, choose another name", OwnerUser)
+	}
+	if costRate != nil && costRate.IsNegative() {
+		return nil, fmt.Errorf("cost rate must not be negative")
+	}
+
+	if _, err := s.db.GetUserByName(ctx, name); err == nil {
+		return nil, fmt.Errorf("user '%s' already exists", name)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check for existing user: %w", err)
+	}
+
+	return s.db.CreateUser(ctx, name, costRate)
+}
+
+// UpdateUser renames a user or changes their cost rate, leaving what isn't given as it is
+func (s *TimesheetService) UpdateUser(ctx context.Context, name string, newName *string, costRate *decimal.Decimal) (*models.User, error) {
+	user, err := s.GetUser(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if newName != nil && strings.EqualFold(*newName, OwnerUser) {
+		return nil, fmt.Errorf("'%s' stands for your own sessions, choose another name", OwnerUser)
+	}
+	if costRate != nil && costRate.IsNegative() {
+		return nil, fmt.Errorf("cost rate must not be negative")
+	}
+	return s.db.UpdateUser(ctx, user.ID, newName, costRate)
+}
+
+// GetUser returns the user with the given name
+func (s *TimesheetService) GetUser(ctx context.Context, name string) (*models.User, error) {
+	user, err := s.db.GetUserByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user '%s' does not exist, see 'work users list'", name)
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// ListUsers returns every user by name
+func (s *TimesheetService) ListUsers(ctx context.Context) ([]*models.User, error) {
+	return s.db.ListUsers(ctx)
+}
+
+// SetSessionUser attributes a session to the named user, or back to the owner given OwnerUser
+func (s *TimesheetService) SetSessionUser(ctx context.Context, sessionID, userName string, force bool) (*models.WorkSession, error) {
+	var userID *string
+	if !strings.EqualFold(userName, OwnerUser) {
+		user, err := s.GetUser(ctx, userName)
+		if err != nil {
+			return nil, err
+		}
+		userID = &user.ID
+	}
+	session, err := s.db.UpdateSessionUser(ctx, sessionID, userID, force)
+	if err != nil {
+		return nil, invoicedSessionsError(err)
+	}
+	return session, nil
+}
+
+// FilterSessionsByUser returns the sessions attributed to the named user, or the owner's given OwnerUser
+func (s *TimesheetService) FilterSessionsByUser(ctx context.Context, sessions []*models.WorkSession, userName string) ([]*models.WorkSession, error) {
+	var userID *string
+	if !strings.EqualFold(userName, OwnerUser) {
+		user, err := s.GetUser(ctx, userName)
+		if err != nil {
+			return nil, err
+		}
+		userID = &user.ID
+	}
+
+	var filtered []*models.WorkSession
+	for _, session := range sessions {
+		if (userID == nil && session.UserID == nil) || (userID != nil && session.UserID != nil && *session.UserID == *userID) {
+			filtered = append(filtered, session)
+		}
+	}
+	return filtered, nil
+}
+
+// DisplayUsers lists the users sessions can be attributed to, with their cost rates
+func (s *TimesheetService) DisplayUsers(ctx context.Context) error {
+	users, err := s.db.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		fmt.Fprintln(s.out, "No users found. Sessions are all yours until you add one with 'work users create'.")
+		return nil
+	}
+
+	m := s.homeMoney()
+	for _, user := range users {
+		costRate := "no cost rate"
+		if user.CostRate != nil {
+			costRate = m.Format(*user.CostRate) + "/hour"
+		}
+		current := ""
+		if user.Name == s.cfg.WorkUser {
+			current = " (WORK_USER)"
+		}
+		fmt.Fprintf(s.out, "%s: %s%s\n", user.Name, costRate, current)
+	}
+	return nil
+}
+
+// userMargin is what a user's hours on an invoice were billed at against what they cost
+type userMargin struct {
+	name   string
+	worked time.Duration
+	billed decimal.Decimal
+	cost   decimal.Decimal
+}
+
+// userMargins adds up the billed amount, excluding GST, and the cost of each user's sessions for users with a
+// cost rate, by name. Hours covered by a retainer count at the sessions' rates. GST is only taken out of GST
+// inclusive sessions with chargeGST.
+func (s *TimesheetService) userMargins(users []*models.User, sessions []*models.WorkSession, chargeGST bool) []*userMargin {
+	costed := make(map[string]*models.User)
+	for _, user := range users {
+		if user.CostRate != nil {
+			costed[user.ID] = user
+		}
+	}
+
+	byUser := make(map[string]*userMargin)
+	for _, session := range sessions {
+		if session.UserID == nil || costed[*session.UserID] == nil {
+			continue
+		}
+		user := costed[*session.UserID]
+		margin, ok := byUser[user.ID]
+		if !ok {
+			margin = &userMargin{name: user.Name}
+			byUser[user.ID] = margin
+		}
+		worked := s.CalculateDuration(session)
+		margin.worked += worked
+		margin.billed = margin.billed.Add(exclusiveTotal([]gstLine{{amount: s.CalculateBillableAmount(session), includesGst: session.IncludesGst}}, chargeGST))
+		margin.cost = margin.cost.Add(billedAmount(worked, *user.CostRate))
+	}
+
+	margins := make([]*userMargin, 0, len(byUser))
+	for _, margin := range byUser {
+		margins = append(margins, margin)
+	}
+	sort.Slice(margins, func(i, j int) bool { return margins[i].name < margins[j].name })
+	return margins
+}
+
+// displayUserMargins prints the margin on each subcontracted user's hours, e.g.
+// "sam: 12.5h billed $2,250.00, cost $1,125.00, margin $1,125.00 (50.0%)"
+func (s *TimesheetService) displayUserMargins(m money.Formatter, margins []*userMargin) {
+	if len(margins) == 0 {
+		return
+	}
+	fmt.Fprintln(s.out)
+	fmt.Fprintln(s.out, "Subcontracted hours:")
+	for _, margin := range margins {
+		profit := margin.billed.Sub(margin.cost)
+		share := ""
+		if margin.billed.IsPositive() {
+			share = fmt.Sprintf(" (%s%%)", profit.Div(margin.billed).Mul(decimal.NewFromInt(100)).StringFixed(1))
+		}
+		fmt.Fprintf(s.out, "  %s: %s billed %s, cost %s, margin %s%s\n", margin.name,
+			s.FormatDurationFor(config.DurationContextReport, margin.worked),
+			m.Format(margin.billed.Round(2)), m.Format(margin.cost.Round(2)), m.Format(profit.Round(2)), share)
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+)
+
+// Utilisation percentages weeks and periods are flagged at
+const (
+	// utilisationOverbooked is the share of capacity above which a week is overbooked
+	utilisationOverbooked = 100
+	// utilisationUnder is the share of capacity below which a week is underutilised
+	utilisationUnder = 75
+)
+
+// utilisationWeek is the billable hours tracked in the part of a week that falls in a report period, against
+// the hours available on its working days
+type utilisationWeek struct {
+	from, to    time.Time
+	workingDays int
+	leaveDays   int
+	capacity    time.Duration
+	billable    time.Duration
+}
+
+// percent is billable hours as a percentage of capacity, or false when there was no capacity
+func (w utilisationWeek) percent() (decimal.Decimal, bool) {
+	return utilisationPercent(w.billable, w.capacity)
+}
+
+func utilisationPercent(billable, capacity time.Duration) (decimal.Decimal, bool) {
+	if capacity <= 0 {
+		return decimal.Zero, false
+	}
+	return decimal.NewFromInt(int64(billable)).Div(decimal.NewFromInt(int64(capacity))).Mul(decimal.NewFromInt(100)), true
+}
+
+// ShowUtilisation displays the billable hours tracked in each week of the period containing date against
+// capacity, TARGET_WEEKLY_HOURS shared across a week's working days, with the percentage used and how it changed
+// from the week before. Working days on leave are taken out of capacity unless ignoreLeave is set. Only your own
+// sessions at a rate count, not time that's unbillable or attributed to other users.
+func (s *TimesheetService) ShowUtilisation(ctx context.Context, period, date string, ignoreLeave bool) error {
+	if !s.cfg.TargetWeeklyHours.IsPositive() {
+		return fmt.Errorf("set TARGET_WEEKLY_HOURS to the billable hours you aim for in a week, e.g. TARGET_WEEKLY_HOURS=30")
+	}
+	if err := ValidatePeriod(period); err != nil {
+		return err
+	}
+	if period == "day" {
+		return fmt.Errorf("utilisation is reported by week, use a period of a week or longer")
+	}
+	target := time.Now()
+	if date != "" {
+		var err error
+		if target, err = time.ParseInLocation("2006-01-02", date, time.Local); err != nil {
+			return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+		}
+	}
+
+	from, to := s.CalculatePeriodRange(period, target)
+	prevFrom, prevTo := s.CalculatePeriodRange(period, from.AddDate(0, 0, -1))
+	weeks, err := s.utilisationWeeks(ctx, from, to, ignoreLeave)
+	if err != nil {
+		return err
+	}
+	previous, err := s.utilisationWeeks(ctx, prevFrom, prevTo, ignoreLeave)
+	if err != nil {
+		return err
+	}
+
+	capacityNote := "less leave"
+	if ignoreLeave {
+		capacityNote = "ignoring leave"
+	}
+	fmt.Fprintf(s.out, "Utilisation for %s to %s (target %sh a week, %s)\n\n", from.Format("2006-01-02"), to.Format("2006-01-02"),
+		s.cfg.TargetWeeklyHours.String(), capacityNote)
+	fmt.Fprintf(s.out, "%-12s %4s %5s %9s %9s %11s %8s\n", "Week of", "Days", "Leave", "Capacity", "Billable", "Utilisation", "Change")
+
+	var total utilisationWeek
+	var last *decimal.Decimal
+	for _, week := range weeks {
+		total.workingDays += week.workingDays
+		total.leaveDays += week.leaveDays
+		total.capacity += week.capacity
+		total.billable += week.billable
+
+		used, change, flag := "-", "", ""
+		if percent, ok := week.percent(); ok {
+			used = percent.StringFixed(1) + "%"
+			if last != nil {
+				change = pointsChange(percent, *last)
+			}
+			flag = utilisationFlag(percent)
+			last = &percent
+		}
+		fmt.Fprintf(s.out, "%-12s %4d %5d %9s %9s %11s %8s  %s\n", week.from.Format("2006-01-02"), week.workingDays, week.leaveDays,
+			s.FormatDurationFor(config.DurationContextReport, week.capacity),
+			s.FormatDurationFor(config.DurationContextReport, week.billable), used, change, flag)
+	}
+
+	used := "-"
+	percent, ok := total.percent()
+	if ok {
+		used = percent.StringFixed(1) + "%"
+	}
+	fmt.Fprintf(s.out, "%-12s %4d %5d %9s %9s %11s\n", "Total", total.workingDays, total.leaveDays,
+		s.FormatDurationFor(config.DurationContextReport, total.capacity),
+		s.FormatDurationFor(config.DurationContextReport, total.billable), used)
+
+	var prevCapacity, prevBillable time.Duration
+	for _, week := range previous {
+		prevCapacity += week.capacity
+		prevBillable += week.billable
+	}
+	fmt.Fprintln(s.out)
+	if prevPercent, prevOK := utilisationPercent(prevBillable, prevCapacity); prevOK && ok {
+		fmt.Fprintf(s.out, "Previous %s: %s%%, %s\n", period, prevPercent.StringFixed(1), pointsTrend(percent, prevPercent))
+	}
+	switch {
+	case !ok:
+		fmt.Fprintln(s.out, "No capacity in this period, every working day was leave")
+	case percent.GreaterThan(decimal.NewFromInt(utilisationOverbooked)):
+		fmt.Fprintf(s.out, "Overbooked: %s more billable than capacity\n", s.FormatDurationFor(config.DurationContextReport, total.billable-total.capacity))
+	case percent.LessThan(decimal.NewFromInt(utilisationUnder)):
+		fmt.Fprintf(s.out, "Underutilised: %s of capacity unbooked\n", s.FormatDurationFor(config.DurationContextReport, total.capacity-total.billable))
+	default:
+		fmt.Fprintln(s.out, "On target")
+	}
+	return nil
+}
+
+// utilisationWeeks splits from to to into Monday to Sunday weeks, cut short at either end, with the capacity of
+// each and the billable hours tracked in it
+func (s *TimesheetService) utilisationWeeks(ctx context.Context, from, to time.Time, ignoreLeave bool) ([]utilisationWeek, error) {
+	// Sessions are found by start time, so include the day before for any running past midnight into the range
+	sessions, err := s.ListSessionsWithDateRange(ctx, from.AddDate(0, 0, -1).Format("2006-01-02"), to.Format("2006-01-02"), 100000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	leave := map[string]bool{}
+	if !ignoreLeave {
+		days, err := s.leaveByDay(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for day := range days {
+			leave[day] = true
+		}
+	}
+
+	dayCapacity := s.cfg.TargetWeeklyHours.Div(decimal.NewFromInt(5)).Mul(decimal.NewFromInt(int64(time.Hour)))
+	var weeks []utilisationWeek
+	for weekFrom := from; !weekFrom.After(to); {
+		_, weekEnd := s.CalculatePeriodRange("week", weekFrom)
+		week := utilisationWeek{from: weekFrom, to: minTime(weekEnd, to)}
+		for day := startOfDay(week.from); !day.After(week.to); day = day.AddDate(0, 0, 1) {
+			if !isWorkingDay(day) {
+				continue
+			}
+			week.workingDays++
+			if leave[day.Format("2006-01-02")] {
+				week.leaveDays++
+			}
+		}
+		week.capacity = time.Duration(dayCapacity.Mul(decimal.NewFromInt(int64(week.workingDays - week.leaveDays))).IntPart())
+		weeks = append(weeks, week)
+		weekFrom = weekEnd.Add(time.Nanosecond)
+	}
+
+	for _, session := range sessions {
+		if session.UserID != nil || session.HourlyRate == nil || !session.HourlyRate.IsPositive() {
+			continue
+		}
+		for _, day := range s.sessionDays(session) {
+			for i := range weeks {
+				if !day.date.Before(startOfDay(weeks[i].from)) && !day.date.After(weeks[i].to) {
+					weeks[i].billable += day.worked
+					break
+				}
+			}
+		}
+	}
+	return weeks, nil
+}
+
+// utilisationFlag marks an overbooked or underutilised percentage
+func utilisationFlag(percent decimal.Decimal) string {
+	switch {
+	case percent.GreaterThan(decimal.NewFromInt(utilisationOverbooked)):
+		return "overbooked"
+	case percent.LessThan(decimal.NewFromInt(utilisationUnder)):
+		return "under"
+	}
+	return ""
+}
+
+// pointsChange is the difference between two percentages in points, e.g. "+5.0" or "-12.5"
+func pointsChange(current, previous decimal.Decimal) string {
+	change := current.Sub(previous)
+	if change.IsNegative() {
+		return change.StringFixed(1)
+	}
+	return "+" + change.StringFixed(1)
+}
+
+// pointsTrend describes the change from a previous percentage, e.g. "up 5.0 points this period"
+func pointsTrend(current, previous decimal.Decimal) string {
+	change := current.Sub(previous)
+	switch {
+	case change.Round(1).IsZero():
+		return "flat this period"
+	case change.IsPositive():
+		return "up " + change.StringFixed(1) + " points this period"
+	default:
+		return "down " + change.Neg().StringFixed(1) + " points this period"
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/models"
+)
+
+// invoiceAmounts are an invoice's subtotal, GST and total
+type invoiceAmounts struct {
+	subtotal decimal.Decimal
+	gst      decimal.Decimal
+	total    decimal.Decimal
+}
+
+func (a invoiceAmounts) equal(other invoiceAmounts) bool {
+	return a.subtotal.Round(2).Equal(other.subtotal.Round(2)) &&
+		a.gst.Round(2).Equal(other.gst.Round(2)) &&
+		a.total.Round(2).Equal(other.total.Round(2))
+}
+
+// expectedInvoiceAmounts recalculates what an invoice should total from the sessions, expenses and milestones
+// linked to it, rounded as it was when it was issued
+func (s *TimesheetService) expectedInvoiceAmounts(ctx context.Context, invoice *models.Invoice) (*models.Client, invoiceAmounts, error) {
+	client, err := s.db.GetClientByID(ctx, invoice.ClientID)
+	if err != nil {
+		return nil, invoiceAmounts{}, fmt.Errorf("failed to get client for invoice %s: %w", invoice.InvoiceNumber, err)
+	}
+	sessions, err := s.db.GetSessionsByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return nil, invoiceAmounts{}, err
+	}
+	expenses, err := s.db.GetExpensesByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return nil, invoiceAmounts{}, err
+	}
+	milestones, err := s.db.GetMilestonesByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return nil, invoiceAmounts{}, err
+	}
+	subtotal, gst, total, _ := s.calculateInvoiceAmounts(client, invoice.Rounding, sessions, expenses, milestones, s.retainerForPeriod(client, invoice.PeriodType, invoice.PeriodStartDate, invoice.PeriodEndDate))
+	return client, invoiceAmounts{subtotal: subtotal, gst: gst, total: total}, nil
+}
+
+// VerifyInvoices checks every invoice against the sessions and expenses linked to it. It reports sessions and
+// expenses linked to deleted invoices, and invoices whose stored totals no longer match what's linked to them,
+// such as after sessions were edited. With fix, orphans are relinked to the client's invoice covering their
+// date (or unlinked so they can be invoiced again) and stored totals are recalculated.
+func (s *TimesheetService) VerifyInvoices(ctx context.Context, fix bool) error {
+	invoices, err := s.db.ListInvoices(ctx, -1)
+	if err != nil {
+		return err
+	}
+
+	problems, err := s.verifyOrphans(ctx, invoices, fix)
+	if err != nil {
+		return err
+	}
+
+	// Totals are checked after orphans are relinked, so the invoices they join are recalculated with them
+	mismatches := 0
+	for _, invoice := range invoices {
+		// Void invoices had their sessions and expenses taken off them
+		if invoice.Status == models.InvoiceStatusVoid {
+			continue
+		}
+		client, expected, err := s.expectedInvoiceAmounts(ctx, invoice)
+		if err != nil {
+			return err
+		}
+		stored := invoiceAmounts{subtotal: invoice.SubtotalAmount, gst: invoice.GstAmount, total: invoice.TotalAmount}
+		if stored.equal(expected) {
+			continue
+		}
+
+		mismatches++
+		m := s.clientMoney(client)
+		fmt.Fprintf(s.out, "Invoice %s (%s) totals %s but its sessions and expenses add up to %s\n",
+			invoice.InvoiceNumber, invoice.ClientName, m.Format(invoice.TotalAmount), m.Format(expected.total))
+		if !stored.subtotal.Round(2).Equal(expected.subtotal.Round(2)) {
+			fmt.Fprintf(s.out, "  subtotal %s, expected %s\n", m.Format(stored.subtotal), m.Format(expected.subtotal))
+		}
+		if !stored.gst.Round(2).Equal(expected.gst.Round(2)) {
+			fmt.Fprintf(s.out, "  GST %s, expected %s\n", m.Format(stored.gst), m.Format(expected.gst))
+		}
+		if !fix {
+			continue
+		}
+
+		if err := s.db.UpdateInvoiceAmounts(ctx, invoice.ID, expected.subtotal, expected.gst, expected.total); err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "  recalculated, reissue the PDF with: %s\n", regenerateInvoiceCommand(invoice))
+		if over := invoice.AmountPaid.Add(invoice.AmountCredited).Add(invoice.AmountDiscounted).Sub(expected.total); over.IsPositive() {
+			fmt.Fprintf(s.out, "  now overpaid by %s, refund or credit the difference\n", m.Format(over))
+		}
+	}
+	problems += mismatches
+
+	fmt.Fprintln(s.out)
+	if problems == 0 {
+		fmt.Fprintf(s.out, "All %d invoice(s) match their sessions and expenses\n", len(invoices))
+		return nil
+	}
+	if fix {
+		fmt.Fprintf(s.out, "Fixed %d problem(s)\n", problems)
+		return nil
+	}
+	fmt.Fprintf(s.out, "%d problem(s) found, run 'work invoices verify --fix' to relink and recalculate\n", problems)
+	return fmt.Errorf("invoice verification found %d problem(s)", problems)
+}
+
+// verifyOrphans reports sessions and expenses linked to deleted invoices, returning how many there are. With
+// fix, each is moved to the client's invoice covering its date when there's exactly one, or unlinked.
+func (s *TimesheetService) verifyOrphans(ctx context.Context, invoices []*models.Invoice, fix bool) (int, error) {
+	sessions, err := s.db.GetSessionsWithMissingInvoice(ctx)
+	if err != nil {
+		return 0, err
+	}
+	expenses, err := s.db.GetExpensesWithMissingInvoice(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// covering finds the one invoice for a client whose period includes t
+	covering := func(clientID string, t time.Time) *models.Invoice {
+		var found *models.Invoice
+		for _, invoice := range invoices {
+			if invoice.ClientID != clientID || t.Before(invoice.PeriodStartDate) || t.After(invoice.PeriodEndDate) {
+				continue
+			}
+			if found != nil {
+				return nil
+			}
+			found = invoice
+		}
+		return found
+	}
+	action := func(target *models.Invoice) string {
+		if target == nil {
+			return "unlink so it can be invoiced again"
+		}
+		return "relink to " + target.InvoiceNumber
+	}
+
+	for _, session := range sessions {
+		target := covering(session.ClientID, session.StartTime)
+		fmt.Fprintf(s.out, "Session %s (%s, %s) is linked to deleted invoice %s: %s\n",
+			session.ID, session.ClientName, session.StartTime.Format("2006-01-02"), *session.InvoiceID, action(target))
+		if fix && target != nil {
+			if err := s.db.UpdateSessionInvoiceID(ctx, session.ID, target.ID); err != nil {
+				return 0, err
+			}
+		}
+	}
+	for _, expense := range expenses {
+		var target *models.Invoice
+		if expense.ClientID != nil {
+			target = covering(*expense.ClientID, expense.ExpenseDate)
+		}
+		fmt.Fprintf(s.out, "Expense %s (%s) is linked to deleted invoice %s: %s\n",
+			expense.ID, expense.ExpenseDate.Format("2006-01-02"), *expense.InvoiceID, action(target))
+		if fix && target != nil {
+			if err := s.db.UpdateExpenseInvoiceID(ctx, expense.ID, &target.ID); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	// Whatever couldn't be relinked is unlinked
+	if fix && len(sessions)+len(expenses) > 0 {
+		if err := s.db.ClearMissingInvoiceIDs(ctx); err != nil {
+			return 0, err
+		}
+	}
+	return len(sessions) + len(expenses), nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+)
+
+const (
+	// weekCellDuration is the time covered by each character of the week grid
+	weekCellDuration = 30 * time.Minute
+	// weekDefaultFirstHour and weekDefaultLastHour bound the grid unless sessions fall outside them
+	weekDefaultFirstHour = 8
+	weekDefaultLastHour  = 18
+)
+
+// weekClientColours are ANSI colours assigned to clients in the order they first appear in the week
+var weekClientColours = []string{"\033[32m", "\033[34m", "\033[35m", "\033[33m", "\033[36m", "\033[31m", "\033[92m", "\033[94m"}
+
+// weekBlock is the part of a session that falls on one day
+type weekBlock struct {
+	client     string
+	start, end time.Time
+}
+
+// ShowWeek renders a Monday to Sunday grid of the week containing date, with a row per day showing when
+// each client was worked on and the day's total hours. Weekdays before today with nothing tracked are flagged
+// as untracked unless they were leave, which is named instead. Clients are drawn in colour when colour is set,
+// and with letters otherwise. Given a user, only their sessions are shown.
+func (s *TimesheetService) ShowWeek(ctx context.Context, date time.Time, user string, colour bool) error {
+	weekStart, weekEnd := s.CalculatePeriodRange("week", date)
+
+	// Sessions are filtered by start time, so include the day before for sessions running past midnight
+	sessions, err := s.ListSessionsWithDateRange(ctx, weekStart.AddDate(0, 0, -1).Format("2006-01-02"), weekEnd.AddDate(0, 0, 1).Format("2006-01-02"), 10000)
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %w", err)
+	}
+	if user != "" {
+		if sessions, err = s.FilterSessionsByUser(ctx, sessions, user); err != nil {
+			return err
+		}
+	}
+
+	leave, err := s.leaveByDay(ctx, weekStart, weekEnd)
+	if err != nil {
+		return err
+	}
+
+	days := make([][]weekBlock, 7)
+	dayTotals := make([]time.Duration, 7)
+	firstHour, lastHour := weekDefaultFirstHour, weekDefaultLastHour
+	var clients []string
+	clientTotals := make(map[string]time.Duration)
+	now := time.Now()
+	for _, session := range sessions {
+		// Totals count the time worked each day, less breaks, while the grid shows when the session ran
+		for _, day := range s.sessionDays(session) {
+			if i := int(dateOnly(day.date).Sub(dateOnly(weekStart)).Hours() / 24); i >= 0 && i < 7 {
+				dayTotals[i] += day.worked
+				clientTotals[session.ClientName] += day.worked
+			}
+		}
+
+		end := now
+		if session.EndTime != nil {
+			end = *session.EndTime
+		}
+		for day := 0; day < 7; day++ {
+			dayStart := weekStart.AddDate(0, 0, day)
+			dayEnd := dayStart.AddDate(0, 0, 1)
+			start, finish := maxTime(session.StartTime, dayStart), minTime(end, dayEnd)
+			if !finish.After(start) {
+				continue
+			}
+
+			if !slices.Contains(clients, session.ClientName) {
+				clients = append(clients, session.ClientName)
+			}
+			days[day] = append(days[day], weekBlock{client: session.ClientName, start: start, end: finish})
+
+			firstHour = min(firstHour, start.Hour())
+			if finish.Equal(dayEnd) {
+				lastHour = 24
+			} else {
+				lastHour = max(lastHour, finish.Add(-time.Nanosecond).Hour()+1)
+			}
+		}
+	}
+
+	sort.Strings(clients)
+	symbols := make(map[string]string, len(clients))
+	for i, client := range clients {
+		symbols[client] = weekClientSymbol(i, colour)
+	}
+
+	cells := (lastHour - firstHour) * int(time.Hour/weekCellDuration)
+	fmt.Fprintf(s.out, "Week of %s\n\n", weekStart.Format("Mon 2 Jan 2006"))
+
+	// Hour labels every two hours, aligned with the first cell of the hour
+	var header strings.Builder
+	for hour := firstHour; hour < lastHour; hour += 2 {
+		label := fmt.Sprintf("%02d", hour)
+		header.WriteString(label + strings.Repeat(" ", 2*int(time.Hour/weekCellDuration)-len(label)))
+	}
+	fmt.Fprintf(s.out, "%-11s%s\n", "", strings.TrimRight(header.String(), " "))
+
+	var weekTotal time.Duration
+	today := startOfDay(now)
+	for day := 0; day < 7; day++ {
+		dayStart := weekStart.AddDate(0, 0, day)
+		gridStart := dayStart.Add(time.Duration(firstHour) * time.Hour)
+
+		var row strings.Builder
+		dayTotal := dayTotals[day]
+		for cell := 0; cell < cells; cell++ {
+			cellStart := gridStart.Add(time.Duration(cell) * weekCellDuration)
+			if client := weekCellClient(days[day], cellStart, cellStart.Add(weekCellDuration)); client != "" {
+				row.WriteString(symbols[client])
+			} else {
+				row.WriteString("·")
+			}
+		}
+		weekTotal += dayTotal
+
+		total := fmt.Sprintf("%6s", s.FormatDurationFor(config.DurationContextReport, dayTotal))
+		if dayTotal == 0 {
+			total = "     -"
+		}
+		if l, ok := leave[dayStart.Format("2006-01-02")]; ok {
+			total += " " + leaveLabel(l.Type)
+		} else if dayTotal == 0 && isWorkingDay(dayStart) && dayStart.Before(today) {
+			total += " untracked"
+		}
+		fmt.Fprintf(s.out, "%-11s%s %s\n", dayStart.Format("Mon 02/01"), row.String(), total)
+	}
+
+	fmt.Fprintln(s.out)
+	if len(clients) == 0 {
+		fmt.Fprintln(s.out, "No sessions this week")
+		return nil
+	}
+	for _, client := range clients {
+		fmt.Fprintf(s.out, "%s %s %s\n", symbols[client], client, s.FormatDurationFor(config.DurationContextReport, clientTotals[client]))
+	}
+	fmt.Fprintf(s.out, "Total: %s\n", s.FormatDurationFor(config.DurationContextReport, weekTotal))
+	return nil
+}
+
+// weekCellClient returns the client worked on for most of a grid cell, or "" if nothing was tracked
+func weekCellClient(blocks []weekBlock, cellStart, cellEnd time.Time) string {
+	covered := make(map[string]time.Duration)
+	best := ""
+	for _, block := range blocks {
+		overlap := minTime(block.end, cellEnd).Sub(maxTime(block.start, cellStart))
+		if overlap <= 0 {
+			continue
+		}
+		covered[block.client] += overlap
+		if best == "" || covered[block.client] > covered[best] {
+			best = block.client
+		}
+	}
+	return best
+}
+
+// weekClientSymbol draws the i-th client as a coloured block, or as a letter without colour
+func weekClientSymbol(i int, colour bool) string {
+	if colour {
+		return weekClientColours[i%len(weekClientColours)] + "█\033[0m"
+	}
+	return string(rune('A' + i%26))
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/worklog"
+)
+
+// noDescription stands in for sessions that were never described
+const noDescription = "(no description)"
+
+// ValidateWorkLog returns an error if value isn't a work log target, allowing empty to leave it unset
+func ValidateWorkLog(value string) error {
+	if value == "" {
+		return nil
+	}
+	_, err := worklog.ParseTarget(value)
+	return err
+}
+
+// PublishWorkLog appends an invoice's work summary to its client's work log. The invoice can be given by
+// ID or number.
+func (s *TimesheetService) PublishWorkLog(ctx context.Context, invoiceRef string) error {
+	invoice, err := s.getInvoiceByIDOrNumber(ctx, invoiceRef)
+	if err != nil {
+		return err
+	}
+	client, err := s.db.GetClientByID(ctx, invoice.ClientID)
+	if err != nil {
+		return fmt.Errorf("failed to get client for invoice: %w", err)
+	}
+	if client.WorkLog == nil || *client.WorkLog == "" {
+		return fmt.Errorf("client '%s' has no work log, set one with 'work clients update %s --work-log notion:<database-id>'", client.Name, client.Name)
+	}
+	sessions, err := s.db.GetSessionsByInvoiceID(ctx, invoice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get sessions for invoice %s: %w", invoice.InvoiceNumber, err)
+	}
+	return s.publishWorkLog(ctx, client, invoice, sessions)
+}
+
+// publishInvoiceWorkLog publishes a newly generated invoice's work summary when its client keeps a work
+// log. The invoice has already been issued, so a failure is reported with how to retry rather than
+// returned.
+func (s *TimesheetService) publishInvoiceWorkLog(ctx context.Context, client *models.Client, invoice *models.Invoice, sessions []*models.WorkSession) {
+	if client.WorkLog == nil || *client.WorkLog == "" {
+		return
+	}
+	if err := s.publishWorkLog(ctx, client, invoice, sessions); err != nil {
+		s.logger.Warn("failed to publish work log", "client", client.Name, "invoice", invoice.InvoiceNumber, "error", err)
+		fmt.Fprintf(s.out, "Couldn't publish the work log for %s, retry with: work invoices publish %s\n", invoice.InvoiceNumber, invoice.InvoiceNumber)
+	}
+}
+
+func (s *TimesheetService) publishWorkLog(ctx context.Context, client *models.Client, invoice *models.Invoice, sessions []*models.WorkSession) error {
+	target, err := worklog.ParseTarget(*client.WorkLog)
+	if err != nil {
+		return err
+	}
+
+	entry := s.workLogEntry(client, invoice, sessions)
+	link, err := worklog.NewPublisher(s.workLogCredentials()).Publish(ctx, target, entry)
+	if err != nil {
+		return fmt.Errorf("failed to publish work log to %s: %w", target.Kind, err)
+	}
+
+	if link != "" {
+		fmt.Fprintf(s.out, "Published work log for %s to %s: %s\n", invoice.InvoiceNumber, target.Kind, link)
+	} else {
+		fmt.Fprintf(s.out, "Published work log for %s to %s\n", invoice.InvoiceNumber, target.Kind)
+	}
+	return nil
+}
+
+// workLogEntry summarises the sessions on an invoice, oldest first
+func (s *TimesheetService) workLogEntry(client *models.Client, invoice *models.Invoice, sessions []*models.WorkSession) worklog.Entry {
+	sorted := make([]*models.WorkSession, len(sessions))
+	copy(sorted, sessions)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartTime.Before(sorted[j].StartTime) })
+
+	entry := worklog.Entry{
+		Title: fmt.Sprintf("%s: %s to %s", s.formatClientName(client.Name),
+			invoice.PeriodStartDate.Format("2006-01-02"), invoice.PeriodEndDate.Format("2006-01-02")),
+		Client:      client.Name,
+		Invoice:     invoice.InvoiceNumber,
+		PeriodStart: invoice.PeriodStartDate,
+		PeriodEnd:   invoice.PeriodEndDate,
+		FormatDuration: func(d time.Duration) string {
+			return s.FormatDurationFor(config.DurationContextList, d)
+		},
+	}
+	for _, session := range sorted {
+		duration := s.CalculateDuration(session)
+		description := noDescription
+		if session.Description != nil && strings.TrimSpace(*session.Description) != "" {
+			description = strings.TrimSpace(*session.Description)
+		}
+		entry.Total += duration
+		entry.Items = append(entry.Items, worklog.Item{
+			Date:        session.StartTime,
+			Duration:    duration,
+			Description: description,
+		})
+	}
+	return entry
+}
+
+func (s *TimesheetService) workLogCredentials() worklog.Credentials {
+	if s.cfg == nil {
+		return worklog.Credentials{}
+	}
+	return worklog.Credentials{
+		NotionToken:        s.cfg.NotionToken,
+		ConfluenceURL:      s.cfg.ConfluenceURL,
+		ConfluenceEmail:    s.cfg.ConfluenceEmail,
+		ConfluenceAPIToken: s.cfg.ConfluenceAPIToken,
+	}
+}
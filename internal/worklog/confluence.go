@@ -0,0 +1,124 @@
+package worklog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// confluencePage is the part of a Confluence page needed to append to it
+type confluencePage struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+	Body struct {
+		Storage struct {
+			Value          string `json:"value"`
+			Representation string `json:"representation"`
+		} `json:"storage"`
+	} `json:"body"`
+	Links struct {
+		Base  string `json:"base"`
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+// publishConfluence appends the entry to the end of a Confluence page, so the page reads as a running log
+// with the newest period last
+func (p *Publisher) publishConfluence(ctx context.Context, pageID string, entry Entry) (string, error) {
+	var page confluencePage
+	if err := p.confluenceRequest(ctx, http.MethodGet, "/rest/api/content/"+pageID+"?expand=body.storage,version", nil, &page); err != nil {
+		return "", err
+	}
+
+	var section strings.Builder
+	fmt.Fprintf(&section, "<h2>%s</h2>", html.EscapeString(entry.Title))
+	fmt.Fprintf(&section, "<p>%s, %s (invoice %s)</p>", html.EscapeString(periodText(entry)),
+		html.EscapeString(entry.formatDuration(entry.Total)), html.EscapeString(entry.Invoice))
+	section.WriteString("<ul>")
+	for _, item := range entry.Items {
+		fmt.Fprintf(&section, "<li><strong>%s</strong> (%s): %s</li>", item.Date.Format("2006-01-02"),
+			entry.formatDuration(item.Duration), html.EscapeString(item.Description))
+	}
+	section.WriteString("</ul>")
+
+	update := map[string]any{
+		"id":      page.ID,
+		"type":    page.Type,
+		"title":   page.Title,
+		"version": map[string]int{"number": page.Version.Number + 1},
+		"body": map[string]any{
+			"storage": map[string]string{
+				"value":          page.Body.Storage.Value + section.String(),
+				"representation": "storage",
+			},
+		},
+	}
+	var updated confluencePage
+	if err := p.confluenceRequest(ctx, http.MethodPut, "/rest/api/content/"+pageID, update, &updated); err != nil {
+		return "", err
+	}
+	if updated.Links.WebUI == "" {
+		return "", nil
+	}
+	return updated.Links.Base + updated.Links.WebUI, nil
+}
+
+// confluenceBaseURL is the site's REST root, accepting the site URL with or without /wiki
+func (p *Publisher) confluenceBaseURL() string {
+	base := strings.TrimSuffix(p.creds.ConfluenceURL, "/")
+	if !strings.HasSuffix(base, "/wiki") {
+		base += "/wiki"
+	}
+	return base
+}
+
+func (p *Publisher) confluenceRequest(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode confluence request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.confluenceBaseURL()+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create confluence request: %w", err)
+	}
+	req.SetBasicAuth(p.creds.ConfluenceEmail, p.creds.ConfluenceAPIToken)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("confluence request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		return fmt.Errorf("confluence returned %d: %s", resp.StatusCode, apiErr.Message)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to read confluence response: %w", err)
+		}
+	}
+	return nil
+}
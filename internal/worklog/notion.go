@@ -0,0 +1,121 @@
+package worklog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	notionAPIURL  = "https://api.notion.com/v1"
+	notionVersion = "2022-06-28"
+)
+
+// notionTextLimit is the most characters Notion accepts in one rich text object
+const notionTextLimit = 2000
+
+// publishNotion adds a page for the entry to a Notion database. Databases can name their title property
+// anything, so the database is looked up first to find it.
+func (p *Publisher) publishNotion(ctx context.Context, databaseID string, entry Entry) (string, error) {
+	var database struct {
+		Properties map[string]struct {
+			Type string `json:"type"`
+		} `json:"properties"`
+	}
+	if err := p.notionRequest(ctx, http.MethodGet, "/databases/"+databaseID, nil, &database); err != nil {
+		return "", err
+	}
+	titleProperty := ""
+	for name, property := range database.Properties {
+		if property.Type == "title" {
+			titleProperty = name
+			break
+		}
+	}
+	if titleProperty == "" {
+		return "", fmt.Errorf("notion database %s has no title property", databaseID)
+	}
+
+	children := []map[string]any{
+		notionBlock("paragraph", fmt.Sprintf("%s, %s for %s (invoice %s)", periodText(entry), entry.formatDuration(entry.Total), entry.Client, entry.Invoice)),
+	}
+	for _, item := range entry.Items {
+		text := fmt.Sprintf("%s (%s): %s", item.Date.Format("2006-01-02"), entry.formatDuration(item.Duration), item.Description)
+		children = append(children, notionBlock("bulleted_list_item", text))
+	}
+
+	page := map[string]any{
+		"parent": map[string]string{"database_id": databaseID},
+		"properties": map[string]any{
+			titleProperty: map[string]any{"title": notionText(entry.Title)},
+		},
+		"children": children,
+	}
+	var created struct {
+		URL string `json:"url"`
+	}
+	if err := p.notionRequest(ctx, http.MethodPost, "/pages", page, &created); err != nil {
+		return "", err
+	}
+	return created.URL, nil
+}
+
+func notionBlock(blockType, text string) map[string]any {
+	return map[string]any{
+		"object":  "block",
+		"type":    blockType,
+		blockType: map[string]any{"rich_text": notionText(text)},
+	}
+}
+
+func notionText(text string) []map[string]any {
+	if runes := []rune(text); len(runes) > notionTextLimit {
+		text = string(runes[:notionTextLimit-3]) + "..."
+	}
+	return []map[string]any{{"type": "text", "text": map[string]string{"content": text}}}
+}
+
+func (p *Publisher) notionRequest(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode notion request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, notionAPIURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create notion request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.creds.NotionToken)
+	req.Header.Set("Notion-Version", notionVersion)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notion request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		return fmt.Errorf("notion returned %d: %s", resp.StatusCode, apiErr.Message)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to read notion response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Package worklog publishes summaries of invoiced work to pages clients can browse, such as a Notion
+// database or a Confluence page, keeping a running log of what was done each invoice period.
+package worklog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Destinations a work log can be published to
+const (
+	Notion     = "notion"
+	Confluence = "confluence"
+)
+
+// Target is where a client's work log is kept, written as "notion:<database-id>" or
+// "confluence:<page-id>"
+type Target struct {
+	Kind string
+	ID   string
+}
+
+func (t Target) String() string {
+	return t.Kind + ":" + t.ID
+}
+
+// ParseTarget reads a work log target such as "notion:0f3c..." or "confluence:123456"
+func ParseTarget(value string) (Target, error) {
+	kind, id, ok := strings.Cut(strings.TrimSpace(value), ":")
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	id = strings.TrimSpace(id)
+	if !ok || id == "" || (kind != Notion && kind != Confluence) {
+		return Target{}, fmt.Errorf("work log must be notion:<database-id> or confluence:<page-id>, got %q", value)
+	}
+	return Target{Kind: kind, ID: id}, nil
+}
+
+// Entry is the work done for a client over one invoice period
+type Entry struct {
+	Title       string
+	Client      string
+	Invoice     string
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Total       time.Duration
+	Items       []Item
+	// FormatDuration writes the entry's durations, as hours and minutes (2h 15m) when nil
+	FormatDuration func(time.Duration) string
+}
+
+// Item is one session in an entry
+type Item struct {
+	Date        time.Time
+	Duration    time.Duration
+	Description string
+}
+
+// Credentials are the API details for each destination. A destination without credentials can't be
+// published to.
+type Credentials struct {
+	NotionToken        string
+	ConfluenceURL      string
+	ConfluenceEmail    string
+	ConfluenceAPIToken string
+}
+
+// Publisher sends entries to Notion and Confluence
+type Publisher struct {
+	creds  Credentials
+	client *http.Client
+}
+
+// NewPublisher returns a publisher using creds
+func NewPublisher(creds Credentials) *Publisher {
+	return &Publisher{
+		creds:  creds,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Publish appends entry to the work log at target, returning a link to what was written when the
+// destination provides one
+func (p *Publisher) Publish(ctx context.Context, target Target, entry Entry) (string, error) {
+	switch target.Kind {
+	case Notion:
+		if p.creds.NotionToken == "" {
+			return "", fmt.Errorf("NOTION_TOKEN must be set to publish to Notion")
+		}
+		return p.publishNotion(ctx, target.ID, entry)
+	case Confluence:
+		if p.creds.ConfluenceURL == "" || p.creds.ConfluenceEmail == "" || p.creds.ConfluenceAPIToken == "" {
+			return "", fmt.Errorf("CONFLUENCE_URL, CONFLUENCE_EMAIL and CONFLUENCE_API_TOKEN must be set to publish to Confluence")
+		}
+		return p.publishConfluence(ctx, target.ID, entry)
+	default:
+		return "", fmt.Errorf("unsupported work log %q", target.Kind)
+	}
+}
+
+// formatDuration shows a duration with the entry's FormatDuration, or as hours and minutes, e.g. 2h 15m
+func (e Entry) formatDuration(d time.Duration) string {
+	if e.FormatDuration != nil {
+		return e.FormatDuration(d)
+	}
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// periodText describes the dates an entry covers
+func periodText(entry Entry) string {
+	return fmt.Sprintf("%s to %s", entry.PeriodStart.Format("2006-01-02"), entry.PeriodEnd.Format("2006-01-02"))
+}
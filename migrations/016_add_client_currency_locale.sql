@@ -0,0 +1,2 @@
+ALTER TABLE clients ADD COLUMN currency VARCHAR(3);
+ALTER TABLE clients ADD COLUMN locale VARCHAR(20);
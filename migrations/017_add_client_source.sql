@@ -0,0 +1 @@
+ALTER TABLE clients ADD COLUMN source VARCHAR(20);
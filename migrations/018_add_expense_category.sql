@@ -0,0 +1 @@
+ALTER TABLE expenses ADD COLUMN category VARCHAR(20);
@@ -0,0 +1,2 @@
+ALTER TABLE expenses ADD COLUMN markup_percent DECIMAL(5,2);
+ALTER TABLE expenses ADD COLUMN billable BOOLEAN DEFAULT 1 NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE expenses ADD COLUMN draft BOOLEAN DEFAULT 0 NOT NULL;
+ALTER TABLE expenses ADD COLUMN receipt_path VARCHAR(500);
@@ -0,0 +1,2 @@
+ALTER TABLE expenses ADD COLUMN distance_km DECIMAL(10,2);
+ALTER TABLE expenses ADD COLUMN rate_per_km DECIMAL(10,2);
@@ -0,0 +1 @@
+ALTER TABLE sessions ADD COLUMN hostname VARCHAR(255);
@@ -0,0 +1,16 @@
+CREATE TABLE session_templates (
+    id TEXT PRIMARY KEY NOT NULL, -- UUID v7
+    name TEXT NOT NULL UNIQUE,
+    client_id TEXT NOT NULL,
+    description TEXT,
+    duration_minutes INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id)
+);
+
+CREATE TRIGGER session_templates_updated_at
+    AFTER UPDATE ON session_templates
+    BEGIN
+        UPDATE session_templates SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+    END;
@@ -0,0 +1,32 @@
+CREATE TABLE credit_notes (
+    id TEXT PRIMARY KEY NOT NULL, -- UUID v7
+    invoice_id TEXT NOT NULL,
+    credit_note_number VARCHAR(60) UNIQUE NOT NULL,
+    amount DECIMAL(10,2) NOT NULL, -- credited against the invoice total, including GST
+    gst_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00,
+    reason TEXT NOT NULL,
+    issued_date DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (invoice_id) REFERENCES invoices(id)
+);
+
+CREATE INDEX idx_credit_notes_invoice_id ON credit_notes(invoice_id);
+
+CREATE TRIGGER credit_notes_updated_at
+    AFTER UPDATE ON credit_notes
+    BEGIN
+        UPDATE credit_notes SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+    END;
+
+-- Credits reduce what's owed on an invoice, so the view totals them alongside payments. Subqueries keep
+-- an invoice with several payments and credits from being counted more than once.
+DROP VIEW IF EXISTS v_invoices;
+
+CREATE VIEW v_invoices AS
+SELECT
+	i.*,
+	CAST(COALESCE((SELECT SUM(p.amount) FROM payments p WHERE p.invoice_id = i.id), 0.0) AS REAL) as amount_paid,
+	(SELECT MAX(p.payment_date) FROM payments p WHERE p.invoice_id = i.id) as payment_date,
+	CAST(COALESCE((SELECT SUM(cn.amount) FROM credit_notes cn WHERE cn.invoice_id = i.id), 0.0) AS REAL) as amount_credited
+FROM invoices i;
@@ -0,0 +1,2 @@
+-- GST included in what an expense cost, claimable as a GST credit on the BAS
+ALTER TABLE expenses ADD COLUMN gst_amount DECIMAL(10,2);
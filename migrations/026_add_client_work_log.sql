@@ -0,0 +1,2 @@
+-- Where each invoice period's work summary is published for the client, e.g. notion:<database-id>
+ALTER TABLE clients ADD COLUMN work_log TEXT;
@@ -0,0 +1,2 @@
+-- When a client's retainer started, so the first period's retainer can be prorated
+ALTER TABLE clients ADD COLUMN retainer_start_date DATETIME;
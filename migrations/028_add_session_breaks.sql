@@ -0,0 +1,35 @@
+-- Breaks are idle periods taken out of a session, such as those detected by ActivityWatch when stopping
+CREATE TABLE session_breaks (
+    id TEXT PRIMARY KEY NOT NULL, -- UUID v7
+    session_id TEXT NOT NULL,
+    start_time DATETIME NOT NULL,
+    end_time DATETIME NOT NULL,
+    source VARCHAR(20) NOT NULL DEFAULT 'manual', -- manual or activitywatch
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_session_breaks_session_id ON session_breaks(session_id);
+
+-- The session keeps the total of its breaks, so durations can be worked out without joining its breaks
+ALTER TABLE sessions ADD COLUMN break_seconds INTEGER NOT NULL DEFAULT 0;
+
+CREATE TRIGGER session_breaks_total_insert
+    AFTER INSERT ON session_breaks
+    BEGIN
+        UPDATE sessions SET break_seconds = break_seconds + CAST(ROUND((julianday(NEW.end_time) - julianday(NEW.start_time)) * 86400) AS INTEGER)
+        WHERE id = NEW.session_id;
+    END;
+
+CREATE TRIGGER session_breaks_total_delete
+    AFTER DELETE ON session_breaks
+    BEGIN
+        UPDATE sessions SET break_seconds = MAX(break_seconds - CAST(ROUND((julianday(OLD.end_time) - julianday(OLD.start_time)) * 86400) AS INTEGER), 0)
+        WHERE id = OLD.session_id;
+    END;
+
+CREATE TRIGGER sessions_delete_breaks
+    AFTER DELETE ON sessions
+    BEGIN
+        DELETE FROM session_breaks WHERE session_id = OLD.id;
+    END;
@@ -0,0 +1,3 @@
+-- How an invoice's sessions are listed: one line per session, per day or per description. Kept so regenerating
+-- the invoice lays it out the same way.
+ALTER TABLE invoices ADD COLUMN group_by VARCHAR(20) NOT NULL DEFAULT 'session';
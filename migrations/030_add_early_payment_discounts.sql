@@ -0,0 +1,18 @@
+-- Early payment discount terms, e.g. 2% off when the invoice is paid within 7 days of being issued
+ALTER TABLE clients ADD COLUMN early_discount_percent REAL;
+ALTER TABLE clients ADD COLUMN early_discount_days INTEGER;
+
+-- The early payment discount taken when a payment settled an invoice for less than was owed
+ALTER TABLE payments ADD COLUMN discount_amount decimal(10,2) NOT NULL DEFAULT 0;
+
+-- Discounts taken reduce what's owed alongside payments and credits
+DROP VIEW IF EXISTS v_invoices;
+
+CREATE VIEW v_invoices AS
+SELECT
+	i.*,
+	CAST(COALESCE((SELECT SUM(p.amount) FROM payments p WHERE p.invoice_id = i.id), 0.0) AS REAL) as amount_paid,
+	(SELECT MAX(p.payment_date) FROM payments p WHERE p.invoice_id = i.id) as payment_date,
+	CAST(COALESCE((SELECT SUM(cn.amount) FROM credit_notes cn WHERE cn.invoice_id = i.id), 0.0) AS REAL) as amount_credited,
+	CAST(COALESCE((SELECT SUM(p.discount_amount) FROM payments p WHERE p.invoice_id = i.id), 0.0) AS REAL) as amount_discounted
+FROM invoices i;
@@ -0,0 +1,3 @@
+-- Set when sessions on an issued invoice are changed or deleted anyway, so the invoice no longer matches them
+-- until it's regenerated
+ALTER TABLE invoices ADD COLUMN needs_regeneration BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+-- Text printed in the Notes section of a client's invoices, e.g. a PO number, and their payment terms
+ALTER TABLE clients ADD COLUMN invoice_notes TEXT;
+ALTER TABLE clients ADD COLUMN payment_terms TEXT;
+
+-- The notes and terms an invoice was issued with, so regenerating it prints the same ones
+ALTER TABLE invoices ADD COLUMN notes TEXT;
+ALTER TABLE invoices ADD COLUMN payment_terms TEXT;
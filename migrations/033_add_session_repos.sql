@@ -0,0 +1,16 @@
+-- The repositories whose commits a session's description was generated from, so invoiced work can be traced
+-- back to code. Replaced each time the session's description is generated.
+CREATE TABLE session_repos (
+    session_id TEXT NOT NULL,
+    repo_path TEXT NOT NULL,
+    commit_count INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (session_id, repo_path),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER sessions_delete_repos
+    AFTER DELETE ON sessions
+    BEGIN
+        DELETE FROM session_repos WHERE session_id = OLD.id;
+    END;
@@ -0,0 +1,11 @@
+-- People other than the owner who work on client sessions, such as subcontractors, with what their hours cost
+-- so invoices can show the margin on them. Sessions without a user are the owner's.
+CREATE TABLE users (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    cost_rate DECIMAL(10,2),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+ALTER TABLE sessions ADD COLUMN user_id TEXT REFERENCES users(id);
@@ -0,0 +1,7 @@
+-- Purchase order numbers and project codes some clients require on every invoice, as defaults on the client
+-- and as issued on each invoice so regenerating it prints the same ones
+ALTER TABLE clients ADD COLUMN po_number TEXT;
+ALTER TABLE clients ADD COLUMN project_code TEXT;
+
+ALTER TABLE invoices ADD COLUMN po_number TEXT;
+ALTER TABLE invoices ADD COLUMN project_code TEXT;
@@ -0,0 +1,4 @@
+-- The operating system and git branch a session was started on, alongside its hostname, for telling which
+-- machine and branch old entries were worked on. Left empty when SESSION_METADATA is off.
+ALTER TABLE sessions ADD COLUMN os TEXT;
+ALTER TABLE sessions ADD COLUMN git_branch TEXT;
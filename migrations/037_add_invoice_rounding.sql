@@ -0,0 +1,3 @@
+-- The amount a client's invoice totals are rounded to, e.g. 1 for the nearest dollar or 5 for the nearest five,
+-- with the difference shown on the invoice as a rounding adjustment. Unset leaves totals to the cent.
+ALTER TABLE clients ADD COLUMN invoice_rounding decimal(10,2);
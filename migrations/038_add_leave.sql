@@ -0,0 +1,13 @@
+-- Days off, so days without sessions that were annual leave, sick leave or public holidays aren't counted as
+-- untracked and can be taken out of capacity. Dates are inclusive.
+CREATE TABLE leave (
+    id TEXT PRIMARY KEY,
+    leave_type TEXT NOT NULL CHECK (leave_type IN ('annual', 'sick', 'public')),
+    start_date DATETIME NOT NULL,
+    end_date DATETIME NOT NULL,
+    note TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_leave_dates ON leave(start_date, end_date);
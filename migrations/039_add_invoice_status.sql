@@ -0,0 +1,13 @@
+-- Where an invoice is in its life: draft, issued, paid, written-off or void. Written-off invoices keep the
+-- balance given up on them and void ones are treated as never issued, with the reason and date of either kept.
+ALTER TABLE invoices ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'issued';
+ALTER TABLE invoices ADD COLUMN status_reason TEXT;
+ALTER TABLE invoices ADD COLUMN status_changed_at DATETIME;
+ALTER TABLE invoices ADD COLUMN amount_written_off decimal(10,2) NOT NULL DEFAULT 0;
+
+-- Invoices already settled by payments, credit notes and discounts are paid
+UPDATE invoices SET status = 'paid'
+WHERE id IN (
+	SELECT id FROM v_invoices
+	WHERE amount_paid > 0 AND amount_paid + amount_credited + amount_discounted >= total_amount
+);
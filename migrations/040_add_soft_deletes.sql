@@ -0,0 +1,7 @@
+-- Deleted sessions and expenses go to the trash, marked with when they were deleted, until they're restored or
+-- purged. Everything else leaves trashed rows out.
+ALTER TABLE sessions ADD COLUMN deleted_at DATETIME;
+ALTER TABLE expenses ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX idx_sessions_deleted_at ON sessions(deleted_at);
+CREATE INDEX idx_expenses_deleted_at ON expenses(deleted_at);
@@ -0,0 +1,13 @@
+-- Changes to clients' hourly rates, each from the day it takes effect, so a rise can be scheduled ahead and
+-- sessions are billed at the rate in effect on the day they start. Sessions keep their rate once created.
+CREATE TABLE client_rates (
+    id TEXT PRIMARY KEY,
+    client_id TEXT NOT NULL,
+    previous_rate DECIMAL(10,2) NOT NULL,
+    hourly_rate DECIMAL(10,2) NOT NULL,
+    effective_date DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_client_rates_client_id ON client_rates(client_id, effective_date);
@@ -0,0 +1,17 @@
+-- Calls, emails and meetings with clients, kept for context when invoicing or chasing payment. A contact that
+-- was billed points at the session it was billed as.
+CREATE TABLE client_contacts (
+    id TEXT PRIMARY KEY,
+    client_id TEXT NOT NULL,
+    contact_type TEXT NOT NULL CHECK (contact_type IN ('call', 'email', 'meeting')),
+    note TEXT NOT NULL,
+    contacted_at DATETIME NOT NULL,
+    duration_minutes INTEGER,
+    session_id TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_client_contacts_client_id ON client_contacts(client_id, contacted_at);
@@ -0,0 +1,3 @@
+-- How GST applies to a client's invoices: taxable clients are charged GST when GST_REGISTERED is on, and
+-- GST-free and export clients never are. GST-free and export sales are reported separately on the BAS.
+ALTER TABLE clients ADD COLUMN tax_treatment TEXT NOT NULL DEFAULT 'taxable' CHECK (tax_treatment IN ('taxable', 'gst_free', 'export'));
@@ -0,0 +1,13 @@
+-- Rules applied when importing card and bank statements. The first rule, in the order they were added, whose
+-- pattern matches a transaction's description sets the expense's client, category and whether it's billable,
+-- or skips the transaction altogether.
+CREATE TABLE expense_rules (
+    id TEXT PRIMARY KEY,
+    pattern TEXT NOT NULL,
+    client_id TEXT,
+    category VARCHAR(20),
+    billable BOOLEAN DEFAULT 0 NOT NULL,
+    skip BOOLEAN DEFAULT 0 NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
+);
@@ -0,0 +1,18 @@
+-- Fixed-price milestones agreed with a client, billed as a line of their own when they're completed rather than
+-- by the hour. A completed milestone goes on the client's next invoice, which it's linked to like sessions.
+CREATE TABLE milestones (
+    id TEXT PRIMARY KEY,
+    client_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    due_date DATETIME,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'complete')),
+    completed_date DATETIME,
+    invoice_id TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_milestones_client_id ON milestones(client_id, due_date);
+CREATE INDEX idx_milestones_invoice_id ON milestones(invoice_id);
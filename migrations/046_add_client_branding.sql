@@ -0,0 +1,4 @@
+-- Branding some clients want on the invoices they're sent: a logo image (PNG, JPEG or GIF) and a colour for
+-- headings, as #rrggbb. Invoices fall back to BILLING_LOGO and BILLING_BRAND_COLOUR when a client has none.
+ALTER TABLE clients ADD COLUMN logo_path TEXT;
+ALTER TABLE clients ADD COLUMN brand_colour TEXT;
@@ -0,0 +1,6 @@
+-- How much git history is given to the AI when describing a client's sessions, instead of GIT_ANALYSIS_MAX_COMMITS,
+-- GIT_ANALYSIS_IGNORE and GIT_ANALYSIS_DETAIL: the most recent commits per repository, comma separated paths left
+-- out such as vendor/ and lockfiles, and 'diffs' or 'messages' for whether diff content is included.
+ALTER TABLE clients ADD COLUMN analysis_max_commits INTEGER;
+ALTER TABLE clients ADD COLUMN analysis_ignore TEXT;
+ALTER TABLE clients ADD COLUMN analysis_detail TEXT;
@@ -0,0 +1,3 @@
+-- A client's own prompt for describing its sessions from git, e.g. formal business language for one and technical
+-- detail for another, used instead of GIT_ANALYSIS_PROMPT when it's set.
+ALTER TABLE clients ADD COLUMN analysis_prompt TEXT;
@@ -0,0 +1,6 @@
+-- The rounding each invoice's total was rounded to when it was issued, so verifying or regenerating it prints the
+-- same total after the client's invoice rounding changes. Invoices already issued take the client's current
+-- rounding, the closest there is to what they were issued with.
+ALTER TABLE invoices ADD COLUMN rounding decimal(10,2);
+
+UPDATE invoices SET rounding = (SELECT invoice_rounding FROM clients WHERE clients.id = invoices.client_id);
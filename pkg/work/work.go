@@ -0,0 +1,171 @@
+// Package work exposes part of the time tracker behind the work CLI for use in other Go programs: clients,
+// sessions, and listing invoices and expenses, in the same database as the CLI. It returns data rather than
+// printing it. The CLI itself is built on the internal service rather than this package, so commands beyond these
+// aren't available here.
+//
+// The database must already have the migrations in the repository's migrations directory applied, as it does
+// once the CLI has been set up against it.
+package work
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/internal/config"
+	"github.com/jesses-code-adventures/work/internal/database"
+	"github.com/jesses-code-adventures/work/internal/models"
+	"github.com/jesses-code-adventures/work/internal/service"
+)
+
+// Config is where the database is and how sessions are billed and invoiced
+type Config = config.Config
+
+// DB is the storage the service reads and writes through
+type DB = database.DB
+
+type (
+	Client     = models.Client
+	ClientRate = models.ClientRate
+	Session    = models.WorkSession
+	Invoice    = models.Invoice
+	Payment    = models.Payment
+	Expense    = models.Expense
+)
+
+// LoadConfig reads the configuration from the environment and any .env file in the working directory, as the
+// CLI does when it's built without settings baked in
+func LoadConfig() (*Config, error) {
+	return config.LoadFromEnv()
+}
+
+// OpenDB connects to the database cfg points at
+func OpenDB(cfg *Config) (DB, error) {
+	return database.NewDB(cfg)
+}
+
+// Service tracks time against a database. Its methods are safe to call from one goroutine at a time.
+type Service struct {
+	db DB
+	ts *service.TimesheetService
+}
+
+// New returns a Service using db. The confirmations and warnings the CLI prints are discarded, since each method
+// returns what it did, and nothing is logged until SetLogLevel is called.
+func New(db DB, cfg *Config) *Service {
+	ts := service.NewTimesheetService(db, cfg)
+	ts.SetOutput(io.Discard)
+	ts.SetLogLevel(slog.LevelError + 1)
+	return &Service{db: db, ts: ts}
+}
+
+// Open loads the configuration from the environment and connects to its database
+func Open() (*Service, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	db, err := OpenDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return New(db, cfg), nil
+}
+
+// SetLogLevel logs warnings, such as a session billed below your minimum rate, and progress at level and above
+// to stderr
+func (s *Service) SetLogLevel(level slog.Level) {
+	s.ts.SetLogLevel(level)
+}
+
+// Close closes the database
+func (s *Service) Close() error {
+	return s.db.Close()
+}
+
+// Config returns the configuration the service was created with
+func (s *Service) Config() *Config {
+	return s.ts.Config()
+}
+
+// Clients lists every client
+func (s *Service) Clients(ctx context.Context) ([]*Client, error) {
+	return s.ts.ListClients(ctx)
+}
+
+// Client gets a client by name
+func (s *Service) Client(ctx context.Context, name string) (*Client, error) {
+	return s.ts.GetClientByName(ctx, name)
+}
+
+// CreateClient adds a client billed at hourlyRate, which can be zero for unbilled work
+func (s *Service) CreateClient(ctx context.Context, name string, hourlyRate decimal.Decimal) (*Client, error) {
+	return s.ts.CreateClient(ctx, name, hourlyRate, nil, nil, nil, nil)
+}
+
+// Start starts a session for a client now, stopping any session that's already active. The description can be
+// empty.
+func (s *Service) Start(ctx context.Context, clientName, description string) (*Session, error) {
+	return s.ts.StartWork(ctx, clientName, optional(description), nil, false)
+}
+
+// StartAt starts a session for a client that began at start
+func (s *Service) StartAt(ctx context.Context, clientName string, start time.Time, description string) (*Session, error) {
+	return s.ts.StartWorkWithTime(ctx, clientName, start, optional(description), nil, false)
+}
+
+// Stop stops the active session now
+func (s *Service) Stop(ctx context.Context) (*Session, error) {
+	return s.ts.StopWork(ctx)
+}
+
+// StopAt stops the active session at end
+func (s *Service) StopAt(ctx context.Context, end time.Time) (*Session, error) {
+	return s.ts.StopWorkAt(ctx, end)
+}
+
+// ActiveSession returns the session being tracked, or nil when there isn't one
+func (s *Service) ActiveSession(ctx context.Context) (*Session, error) {
+	return s.ts.GetActiveSession(ctx)
+}
+
+// CreateSession records a finished session for a client, billed at the client's rate on the day it started
+func (s *Service) CreateSession(ctx context.Context, clientName string, start, end time.Time, description string) (*Session, error) {
+	return s.ts.CreateSessionWithTimes(ctx, clientName, start, end, optional(description), false)
+}
+
+// Sessions lists the sessions started on the days from from to to, inclusive, most recent first
+func (s *Service) Sessions(ctx context.Context, from, to time.Time) ([]*Session, error) {
+	return s.ts.ListSessionsWithDateRange(ctx, from.Format("2006-01-02"), to.Format("2006-01-02"), math.MaxInt32)
+}
+
+// Duration is how long a session has run, less its breaks
+func (s *Service) Duration(session *Session) time.Duration {
+	return s.ts.CalculateDuration(session)
+}
+
+// BillableAmount is what a session is billed, before GST
+func (s *Service) BillableAmount(session *Session) decimal.Decimal {
+	return s.ts.CalculateBillableAmount(session)
+}
+
+// Invoices lists invoices, newest first, for one client when clientName isn't empty
+func (s *Service) Invoices(ctx context.Context, clientName string, unpaidOnly bool) ([]*Invoice, error) {
+	return s.ts.GetInvoices(ctx, math.MaxInt32, clientName, unpaidOnly)
+}
+
+// Expenses lists the expenses dated from from to to
+func (s *Service) Expenses(ctx context.Context, from, to time.Time) ([]*Expense, error) {
+	return s.ts.ListExpensesByDateRange(ctx, from, to)
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,91 @@
+package work_test
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jesses-code-adventures/work/pkg/work"
+)
+
+func TestServiceTracksTimeWithoutPrinting(t *testing.T) {
+	cfg := &work.Config{
+		DatabaseURL:    filepath.Join(t.TempDir(), "work.db"),
+		DatabaseDriver: "sqlite3",
+		DatabaseName:   "work",
+	}
+	migrate(t, cfg)
+	db, err := work.OpenDB(cfg)
+	if err != nil {
+		t.Fatalf("OpenDB() error = %v", err)
+	}
+	svc := work.New(db, cfg)
+	defer svc.Close()
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	ctx := context.Background()
+	if _, err := svc.CreateClient(ctx, "acme", decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("CreateClient() error = %v", err)
+	}
+	start := time.Date(2025, 7, 1, 9, 0, 0, 0, time.Local)
+	if _, err := svc.StartAt(ctx, "acme", start, "Planning"); err != nil {
+		t.Fatalf("StartAt() error = %v", err)
+	}
+	session, err := svc.StopAt(ctx, start.Add(90*time.Minute))
+	if err != nil {
+		t.Fatalf("StopAt() error = %v", err)
+	}
+	if got := svc.Duration(session); got != 90*time.Minute {
+		t.Errorf("Duration() = %s, want 1h30m", got)
+	}
+	if got := svc.BillableAmount(session); !got.Equal(decimal.NewFromInt(150)) {
+		t.Errorf("BillableAmount() = %s, want 150", got)
+	}
+	sessions, err := svc.Sessions(ctx, start, start)
+	if err != nil {
+		t.Fatalf("Sessions() error = %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != session.ID {
+		t.Errorf("Sessions() = %v, want the stopped session", sessions)
+	}
+
+	w.Close()
+	os.Stdout = old
+	if printed, _ := io.ReadAll(r); len(printed) > 0 {
+		t.Errorf("printed to stdout: %q", printed)
+	}
+}
+
+func migrate(t *testing.T, cfg *work.Config) {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join("../../migrations", "*.sql"))
+	if err != nil {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+	sort.Strings(files)
+	conn, err := sql.Open(cfg.DatabaseDriver, cfg.DatabaseURL)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer conn.Close()
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		if _, err := conn.Exec(string(content)); err != nil {
+			t.Fatalf("failed to apply %s: %v", file, err)
+		}
+	}
+}
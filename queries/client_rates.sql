@@ -0,0 +1,14 @@
+-- name: CreateClientRate :one
+INSERT INTO client_rates (id, client_id, previous_rate, hourly_rate, effective_date)
+VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(previous_rate), sqlc.arg(hourly_rate), sqlc.arg(effective_date))
+RETURNING *;
+
+-- name: ListClientRates :many
+SELECT * FROM client_rates
+WHERE client_id = sqlc.arg(client_id)
+ORDER BY effective_date, created_at;
+
+-- name: UpdateClientHourlyRate :exec
+UPDATE clients
+SET hourly_rate = sqlc.arg(hourly_rate)
+WHERE id = sqlc.arg(id);
@@ -18,7 +18,7 @@ ORDER BY name;
 -- name: UpdateClient :one
 UPDATE clients 
 SET 
-	hourly_rate = sqlc.narg(hourly_rate),
+	hourly_rate = CASE WHEN sqlc.narg(hourly_rate) IS NULL THEN hourly_rate ELSE sqlc.narg(hourly_rate) END,
     company_name = sqlc.narg(company_name),
     contact_name = sqlc.narg(contact_name),
     email = sqlc.narg(email),
@@ -33,7 +33,26 @@ SET
     dir = sqlc.narg(dir),
     retainer_amount = sqlc.narg(retainer_amount),
     retainer_hours = sqlc.narg(retainer_hours),
-    retainer_basis = sqlc.narg(retainer_basis)
+    retainer_basis = sqlc.narg(retainer_basis),
+    currency = CASE WHEN sqlc.narg(currency) IS NULL THEN currency ELSE NULLIF(sqlc.narg(currency), '') END,
+    locale = CASE WHEN sqlc.narg(locale) IS NULL THEN locale ELSE NULLIF(sqlc.narg(locale), '') END,
+    source = CASE WHEN sqlc.narg(source) IS NULL THEN source ELSE NULLIF(sqlc.narg(source), '') END,
+    work_log = CASE WHEN sqlc.narg(work_log) IS NULL THEN work_log ELSE NULLIF(sqlc.narg(work_log), '') END,
+    retainer_start_date = CASE WHEN sqlc.narg(retainer_start_date) IS NULL THEN retainer_start_date ELSE sqlc.narg(retainer_start_date) END,
+    early_discount_percent = CASE WHEN sqlc.narg(early_discount_percent) IS NULL THEN early_discount_percent ELSE NULLIF(CAST(sqlc.narg(early_discount_percent) AS REAL), 0) END,
+    early_discount_days = CASE WHEN sqlc.narg(early_discount_days) IS NULL THEN early_discount_days ELSE NULLIF(sqlc.narg(early_discount_days), 0) END,
+    invoice_notes = CASE WHEN sqlc.narg(invoice_notes) IS NULL THEN invoice_notes ELSE NULLIF(sqlc.narg(invoice_notes), '') END,
+    payment_terms = CASE WHEN sqlc.narg(payment_terms) IS NULL THEN payment_terms ELSE NULLIF(sqlc.narg(payment_terms), '') END,
+    po_number = CASE WHEN sqlc.narg(po_number) IS NULL THEN po_number ELSE NULLIF(sqlc.narg(po_number), '') END,
+    project_code = CASE WHEN sqlc.narg(project_code) IS NULL THEN project_code ELSE NULLIF(sqlc.narg(project_code), '') END,
+    invoice_rounding = CASE WHEN sqlc.narg(invoice_rounding) IS NULL THEN invoice_rounding ELSE NULLIF(CAST(sqlc.narg(invoice_rounding) AS REAL), 0) END,
+    tax_treatment = COALESCE(sqlc.narg(tax_treatment), tax_treatment),
+    logo_path = CASE WHEN sqlc.narg(logo_path) IS NULL THEN logo_path ELSE NULLIF(sqlc.narg(logo_path), '') END,
+    brand_colour = CASE WHEN sqlc.narg(brand_colour) IS NULL THEN brand_colour ELSE NULLIF(sqlc.narg(brand_colour), '') END,
+    analysis_max_commits = CASE WHEN sqlc.narg(analysis_max_commits) IS NULL THEN analysis_max_commits ELSE NULLIF(sqlc.narg(analysis_max_commits), -1) END,
+    analysis_ignore = CASE WHEN sqlc.narg(analysis_ignore) IS NULL THEN analysis_ignore ELSE NULLIF(sqlc.narg(analysis_ignore), '') END,
+    analysis_detail = CASE WHEN sqlc.narg(analysis_detail) IS NULL THEN analysis_detail ELSE NULLIF(sqlc.narg(analysis_detail), '') END,
+    analysis_prompt = CASE WHEN sqlc.narg(analysis_prompt) IS NULL THEN analysis_prompt ELSE NULLIF(sqlc.narg(analysis_prompt), '') END
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
@@ -41,3 +60,41 @@ RETURNING *;
 SELECT * FROM clients
 WHERE dir IS NOT NULL AND dir != ''
 ORDER BY name;
+
+-- name: UpdateClientContactFields :exec
+UPDATE clients
+SET
+    contact_name = sqlc.narg(contact_name),
+    email = sqlc.narg(email),
+    phone = sqlc.narg(phone),
+    address_line1 = sqlc.narg(address_line1),
+    address_line2 = sqlc.narg(address_line2),
+    abn = sqlc.narg(abn)
+WHERE id = sqlc.arg(id);
+
+-- name: GetClientSessionStats :one
+SELECT
+    COUNT(*) AS session_count,
+    CAST(COALESCE(SUM(ROUND(((julianday(end_time) - julianday(start_time)) * 86400 - break_seconds) / 60.0) / 60.0), 0) AS REAL) AS total_hours
+FROM sessions
+WHERE client_id = sqlc.arg(client_id) AND end_time IS NOT NULL AND deleted_at IS NULL;
+
+-- name: GetClientHoursSince :one
+SELECT CAST(COALESCE(SUM(ROUND(((julianday(end_time) - julianday(start_time)) * 86400 - break_seconds) / 60.0) / 60.0), 0) AS REAL) AS hours
+FROM sessions
+WHERE client_id = sqlc.arg(client_id) AND end_time IS NOT NULL AND deleted_at IS NULL AND start_time >= sqlc.arg(since);
+
+-- name: GetClientLastSessionStart :one
+SELECT start_time
+FROM sessions
+WHERE client_id = sqlc.arg(client_id) AND deleted_at IS NULL
+ORDER BY start_time DESC
+LIMIT 1;
+
+-- name: GetClientInvoiceStats :one
+SELECT
+    COUNT(*) AS invoice_count,
+    CAST(COALESCE(SUM(total_amount), 0) AS REAL) AS total_invoiced,
+    CAST(COALESCE(SUM(MAX(total_amount - amount_paid - amount_credited - amount_discounted, 0)), 0) AS REAL) AS outstanding
+FROM v_invoices
+WHERE client_id = sqlc.arg(client_id);
@@ -0,0 +1,27 @@
+-- name: CreateClientContact :one
+INSERT INTO client_contacts (id, client_id, contact_type, note, contacted_at, duration_minutes)
+VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(contact_type), sqlc.arg(note), sqlc.arg(contacted_at), sqlc.narg(duration_minutes))
+RETURNING *;
+
+-- name: GetClientContactByID :one
+SELECT cc.*, c.name AS client_name
+FROM client_contacts cc
+JOIN clients c ON cc.client_id = c.id
+WHERE cc.id = sqlc.arg(id);
+
+-- name: ListClientContacts :many
+SELECT cc.*, c.name AS client_name
+FROM client_contacts cc
+JOIN clients c ON cc.client_id = c.id
+WHERE (sqlc.narg(client_id) IS NULL OR cc.client_id = sqlc.narg(client_id))
+ORDER BY cc.contacted_at DESC
+LIMIT sqlc.arg(limit_count);
+
+-- name: SetClientContactSession :exec
+UPDATE client_contacts
+SET session_id = sqlc.arg(session_id), updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id);
+
+-- name: DeleteClientContact :exec
+DELETE FROM client_contacts
+WHERE id = sqlc.arg(id);
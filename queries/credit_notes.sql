@@ -0,0 +1,14 @@
+-- name: CreateCreditNote :one
+INSERT INTO credit_notes (id, invoice_id, credit_note_number, amount, gst_amount, reason, issued_date)
+VALUES (sqlc.arg(id), sqlc.arg(invoice_id), sqlc.arg(credit_note_number), sqlc.arg(amount), sqlc.arg(gst_amount), sqlc.arg(reason), sqlc.arg(issued_date))
+RETURNING *;
+
+-- name: GetCreditNotesByInvoiceID :many
+SELECT * FROM credit_notes
+WHERE invoice_id = sqlc.arg(invoice_id)
+ORDER BY issued_date, created_at;
+
+-- name: ListCreditNotes :many
+SELECT * FROM credit_notes
+ORDER BY issued_date, created_at;
+
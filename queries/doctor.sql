@@ -0,0 +1,29 @@
+-- name: GetSessionsWithMissingInvoice :many
+SELECT s.*, c.name as client_name
+FROM sessions s
+JOIN clients c ON s.client_id = c.id
+WHERE s.invoice_id IS NOT NULL AND s.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM invoices i WHERE i.id = s.invoice_id)
+ORDER BY s.start_time;
+
+-- name: GetExpensesWithMissingInvoice :many
+SELECT * FROM expenses
+WHERE invoice_id IS NOT NULL AND deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM invoices i WHERE i.id = expenses.invoice_id)
+ORDER BY expense_date;
+
+-- name: ClearMissingSessionInvoiceIDs :exec
+UPDATE sessions
+SET invoice_id = NULL
+WHERE invoice_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM invoices i WHERE i.id = sessions.invoice_id);
+
+-- name: ClearMissingExpenseInvoiceIDs :exec
+UPDATE expenses
+SET invoice_id = NULL
+WHERE invoice_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM invoices i WHERE i.id = expenses.invoice_id);
+
+-- name: CountActiveSessions :one
+SELECT COUNT(*) FROM sessions
+WHERE end_time IS NULL AND deleted_at IS NULL;
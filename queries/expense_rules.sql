@@ -0,0 +1,20 @@
+-- name: CreateExpenseRule :one
+INSERT INTO expense_rules (id, pattern, client_id, category, billable, skip)
+VALUES (sqlc.arg(id), sqlc.arg(pattern), sqlc.narg(client_id), sqlc.narg(category), sqlc.arg(billable), sqlc.arg(skip))
+RETURNING *;
+
+-- name: GetExpenseRuleByID :one
+SELECT er.*, c.name AS client_name
+FROM expense_rules er
+LEFT JOIN clients c ON er.client_id = c.id
+WHERE er.id = sqlc.arg(id);
+
+-- name: ListExpenseRules :many
+SELECT er.*, c.name AS client_name
+FROM expense_rules er
+LEFT JOIN clients c ON er.client_id = c.id
+ORDER BY er.created_at, er.rowid;
+
+-- name: DeleteExpenseRule :exec
+DELETE FROM expense_rules
+WHERE id = sqlc.arg(id);
@@ -1,24 +1,27 @@
 -- name: CreateExpense :one
-INSERT INTO expenses (id, amount, expense_date, reference, client_id, invoice_id, description)
-VALUES (sqlc.arg(id), sqlc.arg(amount), sqlc.arg(expense_date), sqlc.narg(reference), sqlc.narg(client_id), sqlc.narg(invoice_id), sqlc.narg(description))
+INSERT INTO expenses (id, amount, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, gst_amount)
+VALUES (sqlc.arg(id), sqlc.arg(amount), sqlc.arg(expense_date), sqlc.narg(reference), sqlc.narg(client_id), sqlc.narg(invoice_id), sqlc.narg(description), sqlc.narg(category), sqlc.narg(markup_percent), sqlc.arg(billable), sqlc.narg(gst_amount))
 RETURNING *;
 
 -- name: GetExpenseByID :one
 SELECT * FROM expenses
-WHERE id = sqlc.arg(id);
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL;
 
 -- name: ListExpenses :many
 SELECT * FROM expenses
+WHERE deleted_at IS NULL
 ORDER BY expense_date DESC;
 
 -- name: ListExpensesByClient :many
 SELECT * FROM expenses
 WHERE client_id = sqlc.arg(client_id)
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC;
 
 -- name: ListExpensesByDateRange :many
 SELECT * FROM expenses
 WHERE expense_date >= sqlc.arg(start_date) AND expense_date <= sqlc.arg(end_date)
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC;
 
 -- name: ListExpensesByClientAndDateRange :many
@@ -26,6 +29,7 @@ SELECT * FROM expenses
 WHERE client_id = sqlc.arg(client_id) 
   AND expense_date >= sqlc.arg(start_date) 
   AND expense_date <= sqlc.arg(end_date)
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC;
 
 -- name: UpdateExpense :one
@@ -36,27 +40,35 @@ SET
     reference = sqlc.narg(reference),
     client_id = sqlc.narg(client_id),
     invoice_id = sqlc.narg(invoice_id),
-    description = sqlc.narg(description)
-WHERE id = sqlc.arg(id)
+    description = sqlc.narg(description),
+    category = sqlc.narg(category),
+    markup_percent = sqlc.narg(markup_percent),
+    billable = sqlc.arg(billable),
+    gst_amount = sqlc.narg(gst_amount)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
 RETURNING *;
 
--- name: DeleteExpense :exec
-DELETE FROM expenses
-WHERE id = sqlc.arg(id);
+-- name: TrashExpense :exec
+UPDATE expenses
+SET deleted_at = sqlc.arg(deleted_at)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL;
 
 -- name: GetExpensesByReference :many
 SELECT * FROM expenses
 WHERE reference = sqlc.arg(reference)
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC;
 
 -- name: GetExpensesByInvoiceID :many
 SELECT * FROM expenses
 WHERE invoice_id = sqlc.arg(invoice_id)
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC;
 
 -- name: GetExpensesWithoutInvoiceByClient :many
 SELECT * FROM expenses
 WHERE client_id = sqlc.arg(client_id) AND invoice_id IS NULL
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC;
 
 -- name: GetExpensesWithoutInvoiceByClientAndDateRange :many
@@ -65,14 +77,43 @@ WHERE client_id = sqlc.arg(client_id)
   AND invoice_id IS NULL
   AND expense_date >= sqlc.arg(start_date) 
   AND expense_date <= sqlc.arg(end_date)
+  AND deleted_at IS NULL
 ORDER BY expense_date DESC;
 
 -- name: UpdateExpenseInvoiceID :exec
 UPDATE expenses 
 SET invoice_id = sqlc.narg(invoice_id)
-WHERE id = sqlc.arg(id);
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL;
 
 -- name: ClearExpenseInvoiceIDs :exec
 UPDATE expenses 
 SET invoice_id = NULL
-WHERE invoice_id = sqlc.arg(invoice_id);
\ No newline at end of file
+WHERE invoice_id = sqlc.arg(invoice_id);
+
+-- name: CreateDraftExpense :one
+INSERT INTO expenses (id, amount, expense_date, reference, client_id, receipt_path, draft)
+VALUES (sqlc.arg(id), sqlc.arg(amount), sqlc.arg(expense_date), sqlc.narg(reference), sqlc.narg(client_id), sqlc.narg(receipt_path), 1)
+RETURNING *;
+
+-- name: CreateMileageExpense :one
+INSERT INTO expenses (id, amount, expense_date, reference, client_id, description, category, billable, distance_km, rate_per_km)
+VALUES (sqlc.arg(id), sqlc.arg(amount), sqlc.arg(expense_date), sqlc.narg(reference), sqlc.narg(client_id), sqlc.narg(description), 'travel', sqlc.arg(billable), sqlc.narg(distance_km), sqlc.narg(rate_per_km))
+RETURNING *;
+
+-- name: GetExpenseByReceiptPath :one
+SELECT * FROM expenses
+WHERE receipt_path = sqlc.narg(receipt_path)
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListDraftExpenses :many
+SELECT * FROM expenses
+WHERE draft = 1
+  AND deleted_at IS NULL
+ORDER BY expense_date ASC;
+
+-- name: ConfirmExpense :one
+UPDATE expenses
+SET amount = sqlc.arg(amount), draft = 0
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
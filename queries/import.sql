@@ -0,0 +1,98 @@
+-- name: ImportClient :exec
+INSERT INTO clients (id, name, created_at, updated_at, hourly_rate, company_name, contact_name, email, phone, address_line1, address_line2, city, state, postal_code, country, dir, abn, retainer_amount, retainer_hours, retainer_basis, currency, locale, source, work_log, retainer_start_date, early_discount_percent, early_discount_days, invoice_notes, payment_terms, po_number, project_code, invoice_rounding, tax_treatment, logo_path, brand_colour, analysis_max_commits, analysis_ignore, analysis_detail, analysis_prompt)
+VALUES (sqlc.arg(id), sqlc.arg(name), sqlc.arg(created_at), sqlc.arg(updated_at), sqlc.arg(hourly_rate), sqlc.arg(company_name), sqlc.arg(contact_name), sqlc.arg(email), sqlc.arg(phone), sqlc.arg(address_line1), sqlc.arg(address_line2), sqlc.arg(city), sqlc.arg(state), sqlc.arg(postal_code), sqlc.arg(country), sqlc.arg(dir), sqlc.arg(abn), sqlc.arg(retainer_amount), sqlc.arg(retainer_hours), sqlc.arg(retainer_basis), sqlc.arg(currency), sqlc.arg(locale), sqlc.arg(source), sqlc.arg(work_log), sqlc.arg(retainer_start_date), sqlc.arg(early_discount_percent), sqlc.arg(early_discount_days), sqlc.arg(invoice_notes), sqlc.arg(payment_terms), sqlc.arg(po_number), sqlc.arg(project_code), sqlc.arg(invoice_rounding), sqlc.arg(tax_treatment), sqlc.arg(logo_path), sqlc.arg(brand_colour), sqlc.arg(analysis_max_commits), sqlc.arg(analysis_ignore), sqlc.arg(analysis_detail), sqlc.arg(analysis_prompt));
+
+-- name: ImportClientContact :exec
+INSERT INTO client_contacts (id, client_id, contact_type, note, contacted_at, duration_minutes, session_id, created_at, updated_at)
+VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(contact_type), sqlc.arg(note), sqlc.arg(contacted_at), sqlc.arg(duration_minutes), sqlc.arg(session_id), sqlc.arg(created_at), sqlc.arg(updated_at));
+
+-- name: ImportClientRate :exec
+INSERT INTO client_rates (id, client_id, previous_rate, hourly_rate, effective_date, created_at)
+VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(previous_rate), sqlc.arg(hourly_rate), sqlc.arg(effective_date), sqlc.arg(created_at));
+
+-- name: ImportSession :exec
+INSERT INTO sessions (id, client_id, start_time, end_time, description, created_at, updated_at, hourly_rate, full_work_summary, outside_git, invoice_id, includes_gst, hostname, break_seconds, os, git_branch, user_id)
+VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(start_time), sqlc.arg(end_time), sqlc.arg(description), sqlc.arg(created_at), sqlc.arg(updated_at), sqlc.arg(hourly_rate), sqlc.arg(full_work_summary), sqlc.arg(outside_git), sqlc.arg(invoice_id), sqlc.arg(includes_gst), sqlc.arg(hostname), sqlc.arg(break_seconds), sqlc.arg(os), sqlc.arg(git_branch), sqlc.arg(user_id));
+
+-- name: ImportSessionBreak :exec
+INSERT INTO session_breaks (id, session_id, start_time, end_time, source, created_at)
+VALUES (sqlc.arg(id), sqlc.arg(session_id), sqlc.arg(start_time), sqlc.arg(end_time), sqlc.arg(source), sqlc.arg(created_at));
+
+-- name: ImportInvoice :exec
+INSERT INTO invoices (id, client_id, invoice_number, period_type, period_start_date, period_end_date, subtotal_amount, gst_amount, total_amount, generated_date, created_at, updated_at, group_by, needs_regeneration, notes, payment_terms, po_number, project_code, status, status_reason, status_changed_at, amount_written_off, rounding)
+VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(invoice_number), sqlc.arg(period_type), sqlc.arg(period_start_date), sqlc.arg(period_end_date), sqlc.arg(subtotal_amount), sqlc.arg(gst_amount), sqlc.arg(total_amount), sqlc.arg(generated_date), sqlc.arg(created_at), sqlc.arg(updated_at), sqlc.arg(group_by), sqlc.arg(needs_regeneration), sqlc.arg(notes), sqlc.arg(payment_terms), sqlc.arg(po_number), sqlc.arg(project_code), sqlc.arg(status), sqlc.arg(status_reason), sqlc.arg(status_changed_at), sqlc.arg(amount_written_off), sqlc.arg(rounding));
+
+-- name: ImportLeave :exec
+INSERT INTO leave (id, leave_type, start_date, end_date, note, created_at, updated_at)
+VALUES (sqlc.arg(id), sqlc.arg(leave_type), sqlc.arg(start_date), sqlc.arg(end_date), sqlc.arg(note), sqlc.arg(created_at), sqlc.arg(updated_at));
+
+-- name: ImportMilestone :exec
+INSERT INTO milestones (id, client_id, name, amount, due_date, status, completed_date, invoice_id, created_at, updated_at)
+VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(name), sqlc.arg(amount), sqlc.arg(due_date), sqlc.arg(status), sqlc.arg(completed_date), sqlc.arg(invoice_id), sqlc.arg(created_at), sqlc.arg(updated_at));
+
+-- name: ImportPayment :exec
+INSERT INTO payments (id, invoice_id, amount, payment_date, created_at, updated_at, discount_amount)
+VALUES (sqlc.arg(id), sqlc.arg(invoice_id), sqlc.arg(amount), sqlc.arg(payment_date), sqlc.arg(created_at), sqlc.arg(updated_at), sqlc.arg(discount_amount));
+
+-- name: ImportCreditNote :exec
+INSERT INTO credit_notes (id, invoice_id, credit_note_number, amount, gst_amount, reason, issued_date, created_at, updated_at)
+VALUES (sqlc.arg(id), sqlc.arg(invoice_id), sqlc.arg(credit_note_number), sqlc.arg(amount), sqlc.arg(gst_amount), sqlc.arg(reason), sqlc.arg(issued_date), sqlc.arg(created_at), sqlc.arg(updated_at));
+
+-- name: ImportExpense :exec
+INSERT INTO expenses (id, amount, created_at, updated_at, expense_date, reference, client_id, invoice_id, description, category, markup_percent, billable, draft, receipt_path, distance_km, rate_per_km, gst_amount)
+VALUES (sqlc.arg(id), sqlc.arg(amount), sqlc.arg(created_at), sqlc.arg(updated_at), sqlc.arg(expense_date), sqlc.arg(reference), sqlc.arg(client_id), sqlc.arg(invoice_id), sqlc.arg(description), sqlc.arg(category), sqlc.arg(markup_percent), sqlc.arg(billable), sqlc.arg(draft), sqlc.arg(receipt_path), sqlc.arg(distance_km), sqlc.arg(rate_per_km), sqlc.arg(gst_amount));
+
+-- name: ImportExpenseRule :exec
+INSERT INTO expense_rules (id, pattern, client_id, category, billable, skip, created_at)
+VALUES (sqlc.arg(id), sqlc.arg(pattern), sqlc.arg(client_id), sqlc.arg(category), sqlc.arg(billable), sqlc.arg(skip), sqlc.arg(created_at));
+
+-- name: ImportSessionRepo :exec
+INSERT INTO session_repos (session_id, repo_path, commit_count, created_at)
+VALUES (sqlc.arg(session_id), sqlc.arg(repo_path), sqlc.arg(commit_count), sqlc.arg(created_at));
+
+-- name: ImportSessionTemplate :exec
+INSERT INTO session_templates (id, name, client_id, description, duration_minutes, created_at, updated_at)
+VALUES (sqlc.arg(id), sqlc.arg(name), sqlc.arg(client_id), sqlc.arg(description), sqlc.arg(duration_minutes), sqlc.arg(created_at), sqlc.arg(updated_at));
+
+-- name: ImportUser :exec
+INSERT INTO users (id, name, cost_rate, created_at, updated_at)
+VALUES (sqlc.arg(id), sqlc.arg(name), sqlc.arg(cost_rate), sqlc.arg(created_at), sqlc.arg(updated_at));
+
+-- name: DeleteAllClients :exec
+DELETE FROM clients;
+
+-- name: DeleteAllClientContacts :exec
+DELETE FROM client_contacts;
+
+-- name: DeleteAllClientRates :exec
+DELETE FROM client_rates;
+
+-- name: DeleteAllCreditNotes :exec
+DELETE FROM credit_notes;
+
+-- name: DeleteAllExpenseRules :exec
+DELETE FROM expense_rules;
+
+-- name: DeleteAllExpenses :exec
+DELETE FROM expenses;
+
+-- name: DeleteAllInvoices :exec
+DELETE FROM invoices;
+
+-- name: DeleteAllLeave :exec
+DELETE FROM leave;
+
+-- name: DeleteAllMilestones :exec
+DELETE FROM milestones;
+
+-- name: DeleteAllPayments :exec
+DELETE FROM payments;
+
+-- name: DeleteAllSessionBreaks :exec
+DELETE FROM session_breaks;
+
+-- name: DeleteAllSessionTemplates :exec
+DELETE FROM session_templates;
+
+-- name: DeleteAllUsers :exec
+DELETE FROM users;
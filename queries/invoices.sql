@@ -1,6 +1,6 @@
 -- name: CreateInvoice :one
-INSERT INTO invoices (id, client_id, invoice_number, period_type, period_start_date, period_end_date, subtotal_amount, gst_amount, total_amount)
-VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(invoice_number), sqlc.arg(period_type), sqlc.arg(period_start_date), sqlc.arg(period_end_date), sqlc.arg(subtotal_amount), sqlc.arg(gst_amount), sqlc.arg(total_amount))
+INSERT INTO invoices (id, client_id, invoice_number, period_type, period_start_date, period_end_date, subtotal_amount, gst_amount, total_amount, group_by, notes, payment_terms, po_number, project_code, rounding)
+VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(invoice_number), sqlc.arg(period_type), sqlc.arg(period_start_date), sqlc.arg(period_end_date), sqlc.arg(subtotal_amount), sqlc.arg(gst_amount), sqlc.arg(total_amount), sqlc.arg(group_by), sqlc.narg(notes), sqlc.narg(payment_terms), sqlc.narg(po_number), sqlc.narg(project_code), sqlc.narg(rounding))
 RETURNING *;
 
 -- name: GetInvoiceByID :one
@@ -42,10 +42,41 @@ ORDER BY c.name;
 DELETE FROM invoices
 WHERE id = sqlc.arg(id);
 
+-- name: UpdateInvoiceAmounts :exec
+UPDATE invoices
+SET subtotal_amount = sqlc.arg(subtotal_amount),
+    gst_amount = sqlc.arg(gst_amount),
+    total_amount = sqlc.arg(total_amount)
+WHERE id = sqlc.arg(id);
+
+-- name: UpdateInvoiceGroupBy :exec
+UPDATE invoices
+SET group_by = sqlc.arg(group_by)
+WHERE id = sqlc.arg(id);
+
+-- name: UpdateInvoiceNotes :exec
+UPDATE invoices
+SET notes = sqlc.narg(notes)
+WHERE id = sqlc.arg(id);
+
+-- name: UpdateInvoiceReferences :exec
+UPDATE invoices
+SET po_number = sqlc.narg(po_number),
+    project_code = sqlc.narg(project_code)
+WHERE id = sqlc.arg(id);
+
+-- name: UpdateInvoiceStatus :exec
+UPDATE invoices
+SET status = sqlc.arg(status),
+    status_reason = sqlc.narg(status_reason),
+    status_changed_at = sqlc.narg(status_changed_at),
+    amount_written_off = sqlc.arg(amount_written_off)
+WHERE id = sqlc.arg(id);
+
 -- name: UpdateSessionInvoiceID :exec
 UPDATE sessions
 SET invoice_id = sqlc.arg(invoice_id)
-WHERE id = sqlc.arg(session_id);
+WHERE id = sqlc.arg(session_id) AND deleted_at IS NULL;
 
 -- name: GetSessionsForPeriodWithoutInvoice :many
 SELECT s.*, c.name as client_name
@@ -55,13 +86,14 @@ WHERE s.start_time >= sqlc.arg(start_date)
   AND s.start_time <= sqlc.arg(end_date)
   AND s.end_time IS NOT NULL
   AND s.invoice_id IS NULL
+  AND s.deleted_at IS NULL
 ORDER BY c.name, s.start_time;
 
 -- name: GetSessionsByInvoiceID :many
 SELECT s.*, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
-WHERE s.invoice_id = sqlc.arg(invoice_id)
+WHERE s.invoice_id = sqlc.arg(invoice_id) AND s.deleted_at IS NULL
 ORDER BY s.start_time;
 
 -- name: ClearSessionInvoiceIDs :exec
@@ -77,6 +109,7 @@ WHERE s.start_time >= sqlc.arg(start_date)
   AND s.start_time <= sqlc.arg(end_date)
   AND s.end_time IS NOT NULL
   AND s.invoice_id IS NULL
+  AND s.deleted_at IS NULL
   AND c.name = sqlc.arg(client_name)
 ORDER BY s.start_time;
 
@@ -91,5 +124,46 @@ WHERE i.period_start_date = sqlc.arg(period_start_date)
 ORDER BY i.generated_date;
 
 -- name: PayInvoice :exec
-INSERT INTO payments (id, invoice_id, amount, payment_date)
-VALUES (sqlc.arg(id), sqlc.arg(invoice_id), sqlc.arg(amount), sqlc.arg(payment_date));
+INSERT INTO payments (id, invoice_id, amount, payment_date, discount_amount)
+VALUES (sqlc.arg(id), sqlc.arg(invoice_id), sqlc.arg(amount), sqlc.arg(payment_date), sqlc.arg(discount_amount));
+
+-- name: ListPaymentsByDateRange :many
+SELECT * FROM payments
+WHERE payment_date >= sqlc.arg(start_date) AND payment_date <= sqlc.arg(end_date)
+ORDER BY payment_date, created_at;
+
+-- name: ListPayments :many
+SELECT p.*, i.invoice_number, c.name AS client_name
+FROM payments p
+JOIN invoices i ON p.invoice_id = i.id
+JOIN clients c ON i.client_id = c.id
+WHERE (sqlc.narg(invoice_id) IS NULL OR p.invoice_id = sqlc.narg(invoice_id))
+  AND (sqlc.narg(client_name) IS NULL OR c.name = sqlc.narg(client_name))
+ORDER BY p.payment_date, p.created_at;
+
+-- name: GetPaymentByID :one
+SELECT * FROM payments
+WHERE id = sqlc.arg(id);
+
+-- name: DeletePayment :exec
+DELETE FROM payments
+WHERE id = sqlc.arg(id);
+
+-- name: FlagInvoiceNeedsRegeneration :exec
+UPDATE invoices
+SET needs_regeneration = 1
+WHERE id = sqlc.arg(id);
+
+-- name: GetInvoiceIDsForSessionsByDateRange :many
+SELECT DISTINCT invoice_id
+FROM sessions
+WHERE invoice_id IS NOT NULL AND deleted_at IS NULL
+  AND (sqlc.narg(start_date) IS NULL OR start_time >= sqlc.narg(start_date))
+  AND (sqlc.narg(end_date) IS NULL OR start_time <= sqlc.narg(end_date));
+
+-- name: CountInvoicedSessionsByDateRange :one
+SELECT COUNT(*)
+FROM sessions
+WHERE invoice_id IN (SELECT id FROM invoices) AND deleted_at IS NULL
+  AND (sqlc.narg(start_date) IS NULL OR start_time >= sqlc.narg(start_date))
+  AND (sqlc.narg(end_date) IS NULL OR start_time <= sqlc.narg(end_date));
@@ -0,0 +1,21 @@
+-- name: CreateLeave :one
+INSERT INTO leave (id, leave_type, start_date, end_date, note)
+VALUES (sqlc.arg(id), sqlc.arg(leave_type), sqlc.arg(start_date), sqlc.arg(end_date), sqlc.narg(note))
+RETURNING *;
+
+-- name: GetLeaveByID :one
+SELECT * FROM leave
+WHERE id = sqlc.arg(id);
+
+-- name: ListLeave :many
+SELECT * FROM leave
+ORDER BY start_date;
+
+-- name: ListLeaveInRange :many
+SELECT * FROM leave
+WHERE start_date <= sqlc.arg(to_date) AND end_date >= sqlc.arg(from_date)
+ORDER BY start_date;
+
+-- name: DeleteLeave :exec
+DELETE FROM leave
+WHERE id = sqlc.arg(id);
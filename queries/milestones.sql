@@ -0,0 +1,53 @@
+-- name: CreateMilestone :one
+INSERT INTO milestones (id, client_id, name, amount, due_date)
+VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(name), sqlc.arg(amount), sqlc.narg(due_date))
+RETURNING *;
+
+-- name: GetMilestoneByID :one
+SELECT m.*, c.name AS client_name
+FROM milestones m
+JOIN clients c ON m.client_id = c.id
+WHERE m.id = sqlc.arg(id);
+
+-- name: ListMilestones :many
+SELECT m.*, c.name AS client_name
+FROM milestones m
+JOIN clients c ON m.client_id = c.id
+WHERE (sqlc.narg(client_id) IS NULL OR m.client_id = sqlc.narg(client_id))
+ORDER BY c.name, m.due_date IS NULL, m.due_date, m.created_at;
+
+-- name: GetCompletedMilestonesWithoutInvoice :many
+SELECT m.*, c.name AS client_name
+FROM milestones m
+JOIN clients c ON m.client_id = c.id
+WHERE m.status = 'complete'
+  AND m.invoice_id IS NULL
+  AND m.completed_date <= sqlc.arg(completed_before)
+  AND (sqlc.narg(client_id) IS NULL OR m.client_id = sqlc.narg(client_id))
+ORDER BY c.name, m.completed_date;
+
+-- name: GetMilestonesByInvoiceID :many
+SELECT m.*, c.name AS client_name
+FROM milestones m
+JOIN clients c ON m.client_id = c.id
+WHERE m.invoice_id = sqlc.arg(invoice_id)
+ORDER BY m.completed_date;
+
+-- name: CompleteMilestone :exec
+UPDATE milestones
+SET status = 'complete', completed_date = sqlc.arg(completed_date), updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id);
+
+-- name: UpdateMilestoneInvoiceID :exec
+UPDATE milestones
+SET invoice_id = sqlc.narg(invoice_id), updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id);
+
+-- name: ClearMilestoneInvoiceIDs :exec
+UPDATE milestones
+SET invoice_id = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE invoice_id = sqlc.arg(invoice_id);
+
+-- name: DeleteMilestone :exec
+DELETE FROM milestones
+WHERE id = sqlc.arg(id);
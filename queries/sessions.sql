@@ -1,41 +1,49 @@
 -- name: CreateSession :one
-INSERT INTO sessions (id, client_id, start_time, description, hourly_rate, includes_gst)
-VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(start_time), sqlc.narg(description), sqlc.narg(hourly_rate), sqlc.arg(includes_gst))
+INSERT INTO sessions (id, client_id, start_time, description, hourly_rate, includes_gst, hostname, user_id, os, git_branch)
+VALUES (sqlc.arg(id), sqlc.arg(client_id), sqlc.arg(start_time), sqlc.narg(description), sqlc.narg(hourly_rate), sqlc.arg(includes_gst), sqlc.narg(hostname), sqlc.narg(user_id), sqlc.narg(os), sqlc.narg(git_branch))
 RETURNING *;
 
 -- name: GetActiveSession :one
 SELECT s.*, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
-WHERE s.end_time IS NULL
+WHERE s.end_time IS NULL AND s.deleted_at IS NULL
 ORDER BY s.start_time DESC
 LIMIT 1;
 
 -- name: StopSession :one
 UPDATE sessions
 SET end_time = sqlc.arg(end_time)
-WHERE id = sqlc.arg(id)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
 RETURNING *;
 
 -- name: ListRecentSessions :many
 SELECT s.*, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
+WHERE s.deleted_at IS NULL
 ORDER BY s.start_time DESC
 LIMIT sqlc.arg(limit_count);
 
+-- name: ListSessionIDsByPrefix :many
+SELECT id
+FROM sessions
+WHERE id LIKE sqlc.arg(prefix) || '%' AND deleted_at IS NULL
+ORDER BY start_time DESC
+LIMIT sqlc.arg(limit_count);
+
 -- name: GetSessionsByClient :many
 SELECT s.*, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
-WHERE c.name = sqlc.arg(client_name)
+WHERE c.name = sqlc.arg(client_name) AND s.deleted_at IS NULL
 ORDER BY s.start_time DESC;
 
 -- name: GetSessionsByDateRange :many
 SELECT s.*, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
-WHERE s.start_time >= sqlc.arg(start_date) AND s.start_time <= sqlc.arg(end_date)
+WHERE s.start_time >= sqlc.arg(start_date) AND s.start_time <= sqlc.arg(end_date) AND s.deleted_at IS NULL
 ORDER BY s.start_time DESC;
 
 -- name: ListSessionsWithDateRange :many
@@ -45,22 +53,26 @@ JOIN clients c ON s.client_id = c.id
 WHERE (sqlc.narg(start_date) IS NULL OR s.start_time >= sqlc.narg(start_date)) 
   AND (sqlc.narg(end_date) IS NULL OR s.start_time <= sqlc.narg(end_date))
   AND (sqlc.narg(client_name) IS NULL OR c.name = sqlc.narg(client_name))
+  AND s.deleted_at IS NULL
 ORDER BY s.start_time DESC
 LIMIT sqlc.arg(limit_count);
 
 -- name: DeleteAllSessions :exec
 DELETE FROM sessions;
 
--- name: DeleteSessionsByDateRange :exec
-DELETE FROM sessions
+-- name: TrashSessionsByDateRange :execrows
+UPDATE sessions
+SET deleted_at = sqlc.arg(deleted_at)
 WHERE (sqlc.narg(start_date) IS NULL OR start_time >= sqlc.narg(start_date)) 
-  AND (sqlc.narg(end_date) IS NULL OR start_time <= sqlc.narg(end_date));
+  AND (sqlc.narg(end_date) IS NULL OR start_time <= sqlc.narg(end_date))
+  AND (sqlc.arg(include_invoiced) OR invoice_id IS NULL OR invoice_id NOT IN (SELECT id FROM invoices))
+  AND deleted_at IS NULL;
 
 -- name: GetSessionsWithoutDescription :many
 select s.*, c.name as client_name
 from sessions s
 join clients c on s.client_id = c.id
-where s.end_time is not null 
+where s.end_time is not null and s.deleted_at is null 
   and (s.description is null or s.description = '')
   and (sqlc.narg(client_name) is null or c.name = sqlc.narg(client_name))
   and (sqlc.narg(session_id) is null or s.id = sqlc.narg(session_id))
@@ -69,17 +81,64 @@ order by s.start_time desc;
 -- name: UpdateSessionDescription :one
 UPDATE sessions
 SET description = sqlc.arg(description), full_work_summary = sqlc.narg(full_work_summary)
-WHERE id = sqlc.arg(id)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
 RETURNING *;
 
 -- name: UpdateSessionOutsideGit :one
 UPDATE sessions
 SET outside_git = sqlc.arg(outside_git)
-WHERE id = sqlc.arg(id)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateSessionRate :one
+UPDATE sessions
+SET hourly_rate = sqlc.arg(hourly_rate), includes_gst = sqlc.arg(includes_gst)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateSessionUser :one
+UPDATE sessions
+SET user_id = sqlc.narg(user_id)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateSessionTimes :one
+UPDATE sessions
+SET start_time = sqlc.arg(start_time), end_time = sqlc.narg(end_time)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
 RETURNING *;
 
 -- name: GetSessionByID :one
 SELECT s.*, c.name as client_name
 FROM sessions s
 JOIN clients c ON s.client_id = c.id
-WHERE s.id = sqlc.arg(id);
+WHERE s.id = sqlc.arg(id) AND s.deleted_at IS NULL;
+
+-- name: CreateSessionBreak :one
+INSERT INTO session_breaks (id, session_id, start_time, end_time, source)
+VALUES (sqlc.arg(id), sqlc.arg(session_id), sqlc.arg(start_time), sqlc.arg(end_time), sqlc.arg(source))
+RETURNING *;
+
+-- name: ListSessionBreaks :many
+SELECT * FROM session_breaks
+WHERE session_id = sqlc.arg(session_id)
+ORDER BY start_time;
+
+-- name: DeleteSessionRepos :exec
+DELETE FROM session_repos
+WHERE session_id = sqlc.arg(session_id);
+
+-- name: CreateSessionRepo :exec
+INSERT INTO session_repos (session_id, repo_path, commit_count)
+VALUES (sqlc.arg(session_id), sqlc.arg(repo_path), sqlc.arg(commit_count));
+
+-- name: ListSessionRepos :many
+SELECT * FROM session_repos
+WHERE session_id = sqlc.arg(session_id)
+ORDER BY repo_path;
+
+-- name: ListInvoiceSessionRepos :many
+SELECT sr.* FROM session_repos sr
+JOIN sessions s ON sr.session_id = s.id
+WHERE s.invoice_id = sqlc.arg(invoice_id) AND s.deleted_at IS NULL
+ORDER BY s.start_time, sr.repo_path;
@@ -0,0 +1,16 @@
+-- name: CreateSessionTemplate :one
+INSERT INTO session_templates (id, name, client_id, description, duration_minutes)
+VALUES (sqlc.arg(id), sqlc.arg(name), sqlc.arg(client_id), sqlc.narg(description), sqlc.narg(duration_minutes))
+RETURNING *;
+
+-- name: GetSessionTemplateByName :one
+SELECT * FROM session_templates
+WHERE name = sqlc.arg(name);
+
+-- name: ListSessionTemplates :many
+SELECT * FROM session_templates
+ORDER BY name;
+
+-- name: DeleteSessionTemplate :exec
+DELETE FROM session_templates
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,31 @@
+-- name: ListTrashedSessions :many
+SELECT s.*, c.name as client_name
+FROM sessions s
+JOIN clients c ON s.client_id = c.id
+WHERE s.deleted_at IS NOT NULL
+ORDER BY s.deleted_at DESC, s.start_time DESC;
+
+-- name: ListTrashedExpenses :many
+SELECT * FROM expenses
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, expense_date DESC;
+
+-- name: RestoreSession :one
+UPDATE sessions
+SET deleted_at = NULL
+WHERE id = sqlc.arg(id) AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: RestoreExpense :one
+UPDATE expenses
+SET deleted_at = NULL
+WHERE id = sqlc.arg(id) AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeSessions :execrows
+DELETE FROM sessions
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg(before);
+
+-- name: PurgeExpenses :execrows
+DELETE FROM expenses
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg(before);
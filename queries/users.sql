@@ -0,0 +1,20 @@
+-- name: CreateUser :one
+INSERT INTO users (id, name, cost_rate)
+VALUES (sqlc.arg(id), sqlc.arg(name), sqlc.narg(cost_rate))
+RETURNING *;
+
+-- name: GetUserByName :one
+SELECT * FROM users
+WHERE name = sqlc.arg(name);
+
+-- name: ListUsers :many
+SELECT * FROM users
+ORDER BY name;
+
+-- name: UpdateUser :one
+UPDATE users
+SET name = COALESCE(sqlc.narg(name), name),
+    cost_rate = COALESCE(sqlc.narg(cost_rate), cost_rate),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -6,7 +6,7 @@ CREATE TABLE clients (
 , hourly_rate DECIMAL(10,2) DEFAULT 0.00, company_name VARCHAR(255), contact_name VARCHAR(255), email VARCHAR(255), phone VARCHAR(50), address_line1 VARCHAR(255), address_line2 VARCHAR(255), city VARCHAR(100), state VARCHAR(100), postal_code VARCHAR(20), country VARCHAR(100), dir VARCHAR(255), abn VARCHAR(20), retainer_amount DECIMAL(10,2), retainer_hours DECIMAL(10,2), retainer_basis TEXT CHECK (
     retainer_basis IS NULL OR 
     retainer_basis IN ('day', 'week', 'month', 'quarter', 'year')
-));
+), currency VARCHAR(3), locale VARCHAR(20), source VARCHAR(20), work_log TEXT, retainer_start_date DATETIME, early_discount_percent REAL, early_discount_days INTEGER, invoice_notes TEXT, payment_terms TEXT, po_number TEXT, project_code TEXT, invoice_rounding decimal(10,2), tax_treatment TEXT NOT NULL DEFAULT 'taxable' CHECK (tax_treatment IN ('taxable', 'gst_free', 'export')), logo_path TEXT, brand_colour TEXT, analysis_max_commits INTEGER, analysis_ignore TEXT, analysis_detail TEXT, analysis_prompt TEXT);
 CREATE TABLE sessions (
     id TEXT PRIMARY KEY NOT NULL, -- UUID v7
     client_id TEXT NOT NULL,
@@ -14,7 +14,7 @@ CREATE TABLE sessions (
     end_time DATETIME,
     description TEXT,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL, hourly_rate DECIMAL(10,2), full_work_summary TEXT, outside_git TEXT, invoice_id text, includes_gst BOOLEAN DEFAULT 0 NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL, hourly_rate DECIMAL(10,2), full_work_summary TEXT, outside_git TEXT, invoice_id text, includes_gst BOOLEAN DEFAULT 0 NOT NULL, hostname VARCHAR(255), break_seconds INTEGER NOT NULL DEFAULT 0, user_id TEXT REFERENCES users(id), os TEXT, git_branch TEXT, deleted_at DATETIME,
     FOREIGN KEY (client_id) REFERENCES clients(id)
 );
 CREATE INDEX idx_sessions_client_id ON sessions(client_id);
@@ -76,7 +76,7 @@ CREATE TABLE IF NOT EXISTS "invoices" (
     total_amount decimal(10,2) not null default 0.00,
     generated_date datetime default current_timestamp not null,
     created_at datetime default current_timestamp not null,
-    updated_at datetime default current_timestamp not null,
+    updated_at datetime default current_timestamp not null, group_by VARCHAR(20) NOT NULL DEFAULT 'session', needs_regeneration BOOLEAN NOT NULL DEFAULT 0, notes TEXT, payment_terms TEXT, po_number TEXT, project_code TEXT, status VARCHAR(20) NOT NULL DEFAULT 'issued', status_reason TEXT, status_changed_at DATETIME, amount_written_off decimal(10,2) NOT NULL DEFAULT 0, rounding decimal(10,2),
     foreign key (client_id) references clients(id)
 );
 CREATE TABLE IF NOT EXISTS "payments" (
@@ -85,18 +85,9 @@ CREATE TABLE IF NOT EXISTS "payments" (
 	amount decimal(10,2) not null,
 	payment_date datetime not null,
 	created_at datetime default current_timestamp not null,
-	updated_at datetime default current_timestamp not null,
+	updated_at datetime default current_timestamp not null, discount_amount decimal(10,2) NOT NULL DEFAULT 0,
 	foreign key (invoice_id) references invoices(id)
 );
-CREATE VIEW v_invoices AS
-SELECT 
-	i.*,
-	CAST(COALESCE(SUM(p.amount), 0.0) AS REAL) as amount_paid,
-	MAX(p.payment_date) as payment_date
-FROM invoices i
-LEFT JOIN payments p ON p.invoice_id = i.id
-GROUP BY i.id
-/* v_invoices(id,client_id,invoice_number,period_type,period_start_date,period_end_date,subtotal_amount,gst_amount,total_amount,generated_date,created_at,updated_at,amount_paid,payment_date) */;
 CREATE TABLE expenses (
     id TEXT PRIMARY KEY NOT NULL, -- UUID v7
     amount DECIMAL(10,2) NOT NULL,
@@ -104,7 +95,7 @@ CREATE TABLE expenses (
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
     expense_date DATETIME NOT NULL,
     reference TEXT,
-    client_id TEXT, invoice_id TEXT,
+    client_id TEXT, invoice_id TEXT, description TEXT, category VARCHAR(20), markup_percent DECIMAL(5,2), billable BOOLEAN DEFAULT 1 NOT NULL, draft BOOLEAN DEFAULT 0 NOT NULL, receipt_path VARCHAR(500), distance_km DECIMAL(10,2), rate_per_km DECIMAL(10,2), gst_amount DECIMAL(10,2), deleted_at DATETIME,
     FOREIGN KEY (client_id) REFERENCES clients(id)
 );
 CREATE INDEX idx_expenses_client_id ON expenses(client_id);
@@ -116,3 +107,153 @@ CREATE TRIGGER expenses_updated_at
         UPDATE expenses SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
     END;
 CREATE INDEX idx_expenses_invoice_id ON expenses(invoice_id);
+CREATE TABLE session_templates (
+    id TEXT PRIMARY KEY NOT NULL, -- UUID v7
+    name TEXT NOT NULL UNIQUE,
+    client_id TEXT NOT NULL,
+    description TEXT,
+    duration_minutes INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id)
+);
+CREATE TRIGGER session_templates_updated_at
+    AFTER UPDATE ON session_templates
+    BEGIN
+        UPDATE session_templates SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+    END;
+CREATE TABLE credit_notes (
+    id TEXT PRIMARY KEY NOT NULL, -- UUID v7
+    invoice_id TEXT NOT NULL,
+    credit_note_number VARCHAR(60) UNIQUE NOT NULL,
+    amount DECIMAL(10,2) NOT NULL, -- credited against the invoice total, including GST
+    gst_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00,
+    reason TEXT NOT NULL,
+    issued_date DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (invoice_id) REFERENCES invoices(id)
+);
+CREATE INDEX idx_credit_notes_invoice_id ON credit_notes(invoice_id);
+CREATE TRIGGER credit_notes_updated_at
+    AFTER UPDATE ON credit_notes
+    BEGIN
+        UPDATE credit_notes SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+    END;
+CREATE TABLE session_breaks (
+    id TEXT PRIMARY KEY NOT NULL, -- UUID v7
+    session_id TEXT NOT NULL,
+    start_time DATETIME NOT NULL,
+    end_time DATETIME NOT NULL,
+    source VARCHAR(20) NOT NULL DEFAULT 'manual', -- manual or activitywatch
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_session_breaks_session_id ON session_breaks(session_id);
+CREATE TRIGGER session_breaks_total_insert
+    AFTER INSERT ON session_breaks
+    BEGIN
+        UPDATE sessions SET break_seconds = break_seconds + CAST(ROUND((julianday(NEW.end_time) - julianday(NEW.start_time)) * 86400) AS INTEGER)
+        WHERE id = NEW.session_id;
+    END;
+CREATE TRIGGER session_breaks_total_delete
+    AFTER DELETE ON session_breaks
+    BEGIN
+        UPDATE sessions SET break_seconds = MAX(break_seconds - CAST(ROUND((julianday(OLD.end_time) - julianday(OLD.start_time)) * 86400) AS INTEGER), 0)
+        WHERE id = OLD.session_id;
+    END;
+CREATE TRIGGER sessions_delete_breaks
+    AFTER DELETE ON sessions
+    BEGIN
+        DELETE FROM session_breaks WHERE session_id = OLD.id;
+    END;
+CREATE VIEW v_invoices AS
+SELECT
+	i.*,
+	CAST(COALESCE((SELECT SUM(p.amount) FROM payments p WHERE p.invoice_id = i.id), 0.0) AS REAL) as amount_paid,
+	(SELECT MAX(p.payment_date) FROM payments p WHERE p.invoice_id = i.id) as payment_date,
+	CAST(COALESCE((SELECT SUM(cn.amount) FROM credit_notes cn WHERE cn.invoice_id = i.id), 0.0) AS REAL) as amount_credited,
+	CAST(COALESCE((SELECT SUM(p.discount_amount) FROM payments p WHERE p.invoice_id = i.id), 0.0) AS REAL) as amount_discounted
+FROM invoices i
+/* v_invoices(id,client_id,invoice_number,period_type,period_start_date,period_end_date,subtotal_amount,gst_amount,total_amount,generated_date,created_at,updated_at,group_by,needs_regeneration,notes,payment_terms,po_number,project_code,status,status_reason,status_changed_at,amount_written_off,rounding,amount_paid,payment_date,amount_credited,amount_discounted) */;
+CREATE TABLE session_repos (
+    session_id TEXT NOT NULL,
+    repo_path TEXT NOT NULL,
+    commit_count INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (session_id, repo_path),
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+CREATE TRIGGER sessions_delete_repos
+    AFTER DELETE ON sessions
+    BEGIN
+        DELETE FROM session_repos WHERE session_id = OLD.id;
+    END;
+CREATE TABLE users (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    cost_rate DECIMAL(10,2),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+CREATE TABLE leave (
+    id TEXT PRIMARY KEY,
+    leave_type TEXT NOT NULL CHECK (leave_type IN ('annual', 'sick', 'public')),
+    start_date DATETIME NOT NULL,
+    end_date DATETIME NOT NULL,
+    note TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+CREATE INDEX idx_leave_dates ON leave(start_date, end_date);
+CREATE INDEX idx_sessions_deleted_at ON sessions(deleted_at);
+CREATE INDEX idx_expenses_deleted_at ON expenses(deleted_at);
+CREATE TABLE client_rates (
+    id TEXT PRIMARY KEY,
+    client_id TEXT NOT NULL,
+    previous_rate DECIMAL(10,2) NOT NULL,
+    hourly_rate DECIMAL(10,2) NOT NULL,
+    effective_date DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_client_rates_client_id ON client_rates(client_id, effective_date);
+CREATE TABLE client_contacts (
+    id TEXT PRIMARY KEY,
+    client_id TEXT NOT NULL,
+    contact_type TEXT NOT NULL CHECK (contact_type IN ('call', 'email', 'meeting')),
+    note TEXT NOT NULL,
+    contacted_at DATETIME NOT NULL,
+    duration_minutes INTEGER,
+    session_id TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE,
+    FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_client_contacts_client_id ON client_contacts(client_id, contacted_at);
+CREATE TABLE expense_rules (
+    id TEXT PRIMARY KEY,
+    pattern TEXT NOT NULL,
+    client_id TEXT,
+    category VARCHAR(20),
+    billable BOOLEAN DEFAULT 0 NOT NULL,
+    skip BOOLEAN DEFAULT 0 NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
+);
+CREATE TABLE milestones (
+    id TEXT PRIMARY KEY,
+    client_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    due_date DATETIME,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'complete')),
+    completed_date DATETIME,
+    invoice_id TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_milestones_client_id ON milestones(client_id, due_date);
+CREATE INDEX idx_milestones_invoice_id ON milestones(invoice_id);